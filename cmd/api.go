@@ -6,7 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
 	"github.com/desertthunder/ytx/internal/shared"
 	"github.com/desertthunder/ytx/internal/tasks"
 	"github.com/urfave/cli/v3"
@@ -84,34 +88,49 @@ func (r *Runner) APIPost(ctx context.Context, cmd *cli.Command) error {
 func (r *Runner) APIDump(ctx context.Context, cmd *cli.Command) error {
 	pretty := cmd.Bool("pretty")
 	save := cmd.Bool("save")
+	persist := cmd.Bool("persist")
+	r.engine.SetDumpFull(cmd.Bool("full"))
+	if timeout := cmd.Int("timeout"); timeout > 0 {
+		r.engine.SetDumpTimeout(time.Duration(timeout) * time.Second)
+	}
 
 	r.logger.Info("dumping API state")
 	r.writePlain("Fetching proxy state...\n\n")
 
-	progressCh := make(chan tasks.ProgressUpdate, 20)
-	go func() {
-		// TODO: use unicode symbols where possible
-		symbols := map[tasks.Phase]string{
-			tasks.FetchHealth:    "📊",
-			tasks.FetchPlaylists: "📝",
-			tasks.FetchSongs:     "🎵",
-			tasks.FetchAlbums:    "💿",
-			tasks.FetchArtists:   "👨‍🎤",
-			tasks.FetchLiked:     "❤️ ",
-			tasks.FetchHistory:   "📜",
-			tasks.FetchUploads:   "☁️ ",
+	// TODO: use unicode symbols where possible
+	symbols := map[tasks.Phase]string{
+		tasks.FetchHealth:    "📊",
+		tasks.FetchPlaylists: "📝",
+		tasks.FetchSongs:     "🎵",
+		tasks.FetchAlbums:    "💿",
+		tasks.FetchArtists:   "👨‍🎤",
+		tasks.FetchLiked:     "❤️ ",
+		tasks.FetchHistory:   "📜",
+		tasks.FetchUploads:   "☁️ ",
+	}
+	progressCh, finishProgress, err := r.reportProgress(cmd, 20, func(update tasks.ProgressUpdate) {
+		emoji := symbols[update.Phase]
+		if emoji == "" {
+			emoji = "📥"
 		}
-		for update := range progressCh {
-			emoji := symbols[update.Phase]
-			if emoji == "" {
-				emoji = "📥"
-			}
-			r.writePlain("%s %s\n", emoji, update.Message)
+		r.writePlain("%s %s\n", emoji, update.Message)
+	})
+	if err != nil {
+		return err
+	}
+
+	if dir := cmd.String("sections"); dir != "" {
+		sectionCh, finishSections, err := r.writeSections(dir, pretty, progressCh)
+		if err != nil {
+			finishProgress()
+			return err
 		}
-	}()
+		finishOutput := finishProgress
+		progressCh, finishProgress = sectionCh, func() { finishSections(); finishOutput() }
+	}
 
 	result, err := r.engine.Dump(ctx, progressCh)
-	close(progressCh)
+	finishProgress()
 
 	if err != nil {
 		return err
@@ -121,7 +140,7 @@ func (r *Runner) APIDump(ctx context.Context, cmd *cli.Command) error {
 		r.logger.Warn("failed to fetch endpoint", "endpoint", endpointErr.Endpoint, "error", endpointErr.Error)
 	}
 
-	r.writePlainln("✓ Dump complete\n")
+	r.writePlainln("✓ Dump complete in %s\n", result.Timing.Duration().Round(time.Millisecond))
 
 	dump := tasks.DumpData{
 		Health:         result.Health,
@@ -134,6 +153,7 @@ func (r *Runner) APIDump(ctx context.Context, cmd *cli.Command) error {
 		UploadedSongs:  result.UploadedSongs,
 		UploadedAlbums: result.UploadedAlbums,
 		Errors:         []any{},
+		Timing:         &result.Timing,
 	}
 
 	for _, endpointErr := range result.Errors {
@@ -157,5 +177,112 @@ func (r *Runner) APIDump(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
+	if persist {
+		if err := r.persistDump(ctx, result); err != nil {
+			return err
+		}
+	}
+
 	return r.writeJSON(dump, pretty)
 }
+
+// writeSections returns a progress channel that writes each finished dump section to dir/<name>.json before
+// forwarding every update to next. The returned function closes the channel and waits for pending writes.
+func (r *Runner) writeSections(dir string, pretty bool, next chan<- tasks.ProgressUpdate) (chan tasks.ProgressUpdate, func(), error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create sections directory: %w", err)
+	}
+
+	progress := make(chan tasks.ProgressUpdate, cap(next))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for update := range progress {
+			if section, ok := update.Data.(tasks.EndpointResult); ok && section.Error == nil {
+				path := filepath.Join(dir, section.Name+".json")
+				if data, err := shared.MarshalJSON(section.Data, pretty); err != nil {
+					r.logger.Warn("failed to marshal section", "section", section.Name, "error", err)
+				} else if err := os.WriteFile(path, data, 0644); err != nil {
+					r.logger.Warn("failed to write section", "file", path, "error", err)
+				} else {
+					r.logger.Debug("section written", "file", path)
+				}
+			}
+			next <- update
+		}
+	}()
+
+	return progress, func() {
+		close(progress)
+		<-done
+	}, nil
+}
+
+// persistDump upserts the dumped library into the local database as the local user. Each upsert has the database
+// timeout to itself, so a large library takes as long as it needs.
+func (r *Runner) persistDump(ctx context.Context, result *tasks.DumpResult) error {
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	queryCtx, cancel := r.queryContext(ctx)
+	user, err := repositories.EnsureLocalUser(queryCtx, repositories.NewUserRepository(db))
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to resolve local user: %w", err)
+	}
+
+	adapter := repositories.NewLibraryCacheAdapter(
+		user.ID(),
+		repositories.NewPlaylistRepository(db),
+		repositories.NewTrackRepository(db),
+		repositories.NewAlbumRepository(db),
+		repositories.NewArtistRepository(db),
+	)
+
+	summary, err := tasks.PersistDump(ctx, result, timedPersister{adapter, r.queryContext})
+	if err != nil {
+		return fmt.Errorf("failed to persist dump: %w", err)
+	}
+
+	for _, persistErr := range summary.Errors {
+		r.logger.Warn("failed to persist entry", "error", persistErr)
+	}
+
+	r.logger.Info("dump persisted", "path", r.config.Database.Path)
+	r.writePlain("✓ Persisted %d playlists, %d songs, %d albums, %d artists (%d skipped)\n\n",
+		summary.Playlists, summary.Songs, summary.Albums, summary.Artists, summary.Skipped)
+	return nil
+}
+
+// timedPersister runs each upsert of a [tasks.LibraryPersister] under its own context from timeout
+type timedPersister struct {
+	tasks.LibraryPersister
+	timeout func(context.Context) (context.Context, context.CancelFunc)
+}
+
+func (p timedPersister) UpsertPlaylist(ctx context.Context, service string, playlist models.Playlist) error {
+	ctx, cancel := p.timeout(ctx)
+	defer cancel()
+	return p.LibraryPersister.UpsertPlaylist(ctx, service, playlist)
+}
+
+func (p timedPersister) UpsertTrack(ctx context.Context, service string, track models.Track) error {
+	ctx, cancel := p.timeout(ctx)
+	defer cancel()
+	return p.LibraryPersister.UpsertTrack(ctx, service, track)
+}
+
+func (p timedPersister) UpsertAlbum(ctx context.Context, service string, album models.Album) error {
+	ctx, cancel := p.timeout(ctx)
+	defer cancel()
+	return p.LibraryPersister.UpsertAlbum(ctx, service, album)
+}
+
+func (p timedPersister) UpsertArtist(ctx context.Context, service string, artist models.Artist) error {
+	ctx, cancel := p.timeout(ctx)
+	defer cancel()
+	return p.LibraryPersister.UpsertArtist(ctx, service, artist)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+	"github.com/urfave/cli/v3"
+)
+
+// Apply brings the playlist pairs declared in a sync file in line, once or, with --watch, on their schedules until
+// interrupted.
+func (r *Runner) Apply(ctx context.Context, cmd *cli.Command) error {
+	path := cmd.String("file")
+	pairs, err := tasks.LoadSyncFile(path)
+	if err != nil {
+		return err
+	}
+
+	if only := cmd.StringSlice("only"); len(only) > 0 {
+		for _, name := range only {
+			if !slices.ContainsFunc(pairs, func(pair tasks.SyncPair) bool { return pair.Name == name }) {
+				return fmt.Errorf("%w: %s has no playlist named %q", shared.ErrInvalidArgument, path, name)
+			}
+		}
+		pairs = slices.DeleteFunc(pairs, func(pair tasks.SyncPair) bool { return !slices.Contains(only, pair.Name) })
+	}
+
+	dryRun, watch := cmd.Bool("dry-run"), cmd.Bool("watch")
+	if dryRun && watch {
+		return fmt.Errorf("%w: --dry-run and --watch cannot be combined", shared.ErrInvalidArgument)
+	}
+	if watch && !slices.ContainsFunc(pairs, func(pair tasks.SyncPair) bool { return pair.Interval() > 0 }) {
+		return fmt.Errorf("%w: no playlist in %s has a schedule to watch", shared.ErrInvalidArgument, path)
+	}
+
+	r.logger.Info("apply requested", "file", path, "playlists", len(pairs), "dry_run", dryRun, "watch", watch)
+
+	state, err := tasks.LoadSyncState(path)
+	if err != nil {
+		return err
+	}
+
+	closeIgnoreList, err := r.enableIgnoreList(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeIgnoreList()
+
+	store, closeCache, err := r.enableCaching(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	if dryRun {
+		r.writePlain("Dry run: nothing will be created or changed\n\n")
+	}
+	err = r.applyPairs(ctx, store, state, pairs, dryRun)
+	if !watch {
+		return err
+	}
+	if err != nil {
+		r.logger.Warn("apply failed", "error", err)
+	}
+	return r.watchPairs(ctx, store, state, pairs)
+}
+
+// applyPairs applies each pair in turn, carrying on past failures, and returns the failures joined.
+func (r *Runner) applyPairs(ctx context.Context, store tasks.PlaylistStore, state *tasks.SyncState, pairs []tasks.SyncPair, dryRun bool) error {
+	var errs []error
+	for _, pair := range pairs {
+		result, err := r.engine.Apply(ctx, store, state, pair, dryRun, nil)
+		if result != nil {
+			r.writeApplyResult(result, dryRun)
+		}
+		if err != nil {
+			r.writePlain("✗ %s: %v\n\n", pair.Name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", pair.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d playlists failed to apply: %w", len(errs), len(pairs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// watchPairs applies each scheduled pair again whenever its interval has passed since it was last applied, until
+// ctx is done. Failures are reported and the pair is retried on its schedule.
+func (r *Runner) watchPairs(ctx context.Context, store tasks.PlaylistStore, state *tasks.SyncState, pairs []tasks.SyncPair) error {
+	scheduled := slices.DeleteFunc(slices.Clone(pairs), func(pair tasks.SyncPair) bool { return pair.Interval() == 0 })
+	due := make([]time.Time, len(scheduled))
+	for i, pair := range scheduled {
+		due[i] = time.Now().Add(pair.Interval())
+	}
+
+	for {
+		next := 0
+		for i := range due {
+			if due[i].Before(due[next]) {
+				next = i
+			}
+		}
+		r.writePlain("Watching %d playlists; next is %s at %s\n\n", len(scheduled), scheduled[next].Name, due[next].Format(time.Kitchen))
+
+		timer := time.NewTimer(time.Until(due[next]))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		if err := r.applyPairs(ctx, store, state, scheduled[next:next+1], false); err != nil {
+			r.logger.Warn("scheduled apply failed", "playlist", scheduled[next].Name, "error", err)
+		}
+		due[next] = time.Now().Add(scheduled[next].Interval())
+	}
+}
+
+// writeApplyResult prints what applying a pair did, or would do in a dry run
+func (r *Runner) writeApplyResult(result *tasks.ApplyResult, dryRun bool) {
+	pair := result.Pair
+	r.writePlain("▶ %s: %s %q → %s\n", pair.Name, serviceLabel(pair.From), result.Source.Playlist.Name, serviceLabel(pair.To))
+
+	verb := "Added"
+	if dryRun {
+		verb = "Would add"
+	}
+	switch {
+	case result.Action == tasks.ApplySkipped:
+		r.writePlain("  ↷ Skipped: %q already exists (%s)\n", result.DestName, result.DestID)
+	case result.Action == tasks.ApplyCreated && dryRun:
+		r.writePlain("  + Would create %q with %d tracks\n", result.DestName, len(result.Missing))
+	case result.Action == tasks.ApplyCreated && result.Transfer != nil:
+		r.writePlain("  + Created %q (%s): %d of %d tracks matched\n", result.DestName, result.DestID, result.Transfer.SuccessCount, result.Transfer.TotalTracks)
+	case result.Merge != nil:
+		threeWay := result.Merge.ThreeWay
+		if threeWay == nil || threeWay.InSync() {
+			r.writePlain("  ✓ In sync\n")
+			break
+		}
+		r.writePlain("  ⇄ Source: +%d -%d, destination: +%d -%d since the last sync\n",
+			len(threeWay.AddedOnSource), len(threeWay.RemovedOnSource), len(threeWay.AddedOnDest), len(threeWay.RemovedOnDest))
+	case len(result.Missing) == 0:
+		r.writePlain("  ✓ In sync\n")
+	case result.Push != nil:
+		r.writePlain("  %s %d tracks to %s (%d not found, %d skipped)\n", verb, result.Push.AddedCount, result.DestID, result.Push.FailedCount, result.Push.SkippedCount)
+	default:
+		r.writePlain("  %s %d tracks to %s\n", verb, len(result.Missing), result.DestID)
+	}
+
+	if result.Duplicates > 0 {
+		r.writePlain("  %d repeated tracks left out\n", result.Duplicates)
+	}
+	r.writePlain("\n")
+}
+
+// serviceLabel names a sync file service key for output
+func serviceLabel(key string) string {
+	if key == "spotify" {
+		return "Spotify"
+	}
+	return "YouTube Music"
+}
+
+// applyCommand syncs the playlists declared in a sync file
+func applyCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:  "apply",
+		Usage: "Sync the playlist pairs declared in a ytx.yaml file, creating destinations as needed",
+		Description: "Each pair names a source playlist and, optionally, the destination to sync it into; without one, a playlist\n" +
+			"named after the source is found or created, and a created playlist is recorded in ytx.state.yaml next to the\n" +
+			"sync file so later applies sync into it. One-way pairs add the source's new tracks to the destination;\n" +
+			"two-way pairs merge changes made on either side since the last apply. With --watch, pairs are applied\n" +
+			"again on their schedules until interrupted. Pairs sync between Spotify and YouTube Music.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Sync file declaring the playlist pairs",
+				Value:   tasks.DefaultSyncFile,
+			},
+			&cli.StringSliceFlag{
+				Name:  "only",
+				Usage: "Apply only the named pairs (repeatable)",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be created and added without changing anything",
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "Keep running, applying each pair again on its schedule",
+			},
+		},
+		Action: r.Apply,
+	}
+}
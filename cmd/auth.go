@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
 	"github.com/urfave/cli/v3"
 )
@@ -51,46 +54,239 @@ func (r *Runner) AuthLogin(ctx context.Context, cmd *cli.Command) error {
 	return r.writePlain("✓ Authentication successful\n")
 }
 
-// AuthStatus checks current authentication state by calling the /health endpoint.
-func (r *Runner) AuthStatus(ctx context.Context, cmd *cli.Command) error {
-	r.logger.Info("checking auth status")
+// AuthMigrateSecure moves the stored tokens to the secrets store chosen with --store and records the
+// choice in config.toml.
+//
+// Tokens are read from the current store, so this also moves them between stores or back into config.toml.
+func (r *Runner) AuthMigrateSecure(ctx context.Context, cmd *cli.Command) error {
+	configPath := cmd.String("config")
+	secrets := shared.SecretsConfig{Store: cmd.String("store")}
+	if secrets.Store == shared.SecretStoreFile {
+		secrets.Path = cmd.String("path")
+	}
 
-	resp, err := r.api.Get(ctx, "/health")
+	config, err := shared.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrMissingConfig, err)
+	}
+
+	from, err := config.Secrets.NewSecretStore()
+	if err != nil {
+		return err
+	}
+	to, err := secrets.NewSecretStore()
 	if err != nil {
-		return fmt.Errorf("%w: service unavailable: %v", shared.ErrServiceUnavailable, err)
+		return err
+	}
+
+	config.Secrets = secrets
+	if err := shared.SaveConfig(configPath, config); err != nil {
+		return err
+	}
+
+	source, dest := configPath, configPath
+	if from != nil {
+		source = from.String()
+	}
+	if to != nil {
+		dest = to.String()
+	}
+
+	r.writePlain("✓ Tokens moved from %s to %s\n", source, dest)
+	if secrets.Store == shared.SecretStoreFile {
+		r.writePlain("Set %s to unlock them on every run\n", shared.SecretsPassphraseEnv)
 	}
+	return nil
+}
+
+// authCheck is one row of the 'auth status' report.
+type authCheck struct {
+	Service string `json:"service"`
+	Check   string `json:"check"`
+	Status  string `json:"status"` // ok, warn, or fail
+	Detail  string `json:"detail"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
 
-	if !resp.IsJSON {
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return r.writePlain("✓ Service is healthy\nStatus: %s\n", string(resp.Body))
+// authStatusTimeout bounds each network check of 'auth status'.
+const authStatusTimeout = 10 * time.Second
+
+// spotifyAccount is the part of [services.SpotifyService] used to check token health.
+type spotifyAccount interface {
+	UserProfile(ctx context.Context) (*services.SpotifyUser, error)
+	RefreshToken(ctx context.Context) error
+}
+
+// AuthStatus checks Spotify token health and YouTube Music proxy reachability and auth, printing one row per
+// check with a remediation hint for each problem.
+//
+// Returns an error wrapping [shared.ErrNotAuthenticated] when any check fails.
+func (r *Runner) AuthStatus(ctx context.Context, cmd *cli.Command) error {
+	r.logger.Info("checking auth status")
+
+	checks := append(r.spotifyChecks(ctx), r.youtubeChecks(ctx)...)
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == checkFail {
+			failed++
 		}
-		return fmt.Errorf("%w: status %d", shared.ErrServiceUnavailable, resp.StatusCode)
 	}
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		healthData, ok := resp.JSONData.(map[string]any)
-		if !ok {
-			return r.writePlain("✓ Service is healthy\n")
+	if cmd.Bool("json") {
+		report := struct {
+			Healthy bool        `json:"healthy"`
+			Checks  []authCheck `json:"checks"`
+		}{failed == 0, checks}
+		if err := r.writeJSON(report, true); err != nil {
+			return err
 		}
+	} else {
+		r.writeAuthChecks(checks)
+	}
 
-		status, ok := healthData["status"].(string)
-		if !ok {
-			status = "unknown"
+	if failed > 0 {
+		return fmt.Errorf("%w: %d auth checks failed", shared.ErrNotAuthenticated, failed)
+	}
+	return nil
+}
+
+func (r *Runner) writeAuthChecks(checks []authCheck) {
+	symbols := map[string]string{checkOK: "✓", checkWarn: "⚠", checkFail: "✗"}
+
+	r.writePlainHeader("Authentication status")
+	r.writePlain("  %-14s %-12s %-6s %s\n", "SERVICE", "CHECK", "STATUS", "DETAIL")
+	for _, check := range checks {
+		r.writePlain("  %-14s %-12s %-6s %s\n", check.Service, check.Check, symbols[check.Status], check.Detail)
+	}
+
+	hints := false
+	for _, check := range checks {
+		if check.Hint == "" {
+			continue
 		}
-		authenticated := false
-		if auth, ok := healthData["authenticated"].(bool); ok {
-			authenticated = auth
+		if !hints {
+			r.writePlain("\nHints:\n")
+			hints = true
 		}
+		r.writePlain("  → %s %s: %s\n", check.Service, check.Check, check.Hint)
+	}
+}
 
-		r.writePlain("✓ Service is healthy\n")
-		r.writePlain("Status: %s\n", status)
-		if authenticated {
-			r.writePlain("Authentication: ✓ Authenticated\n")
-		} else {
-			r.writePlain("Authentication: ✗ Not authenticated\n")
+// spotifyChecks verifies the configured credentials and that the stored token works, refreshing it if expired.
+func (r *Runner) spotifyChecks(ctx context.Context) []authCheck {
+	const service = "Spotify"
+	creds := r.config.Credentials.Spotify
+	authHint := "Run 'ytx spotify auth'"
+
+	if creds.ClientID == "" {
+		return []authCheck{{service, "credentials", checkFail, "client_id not set", "Set credentials.spotify.client_id in config.toml or YTX_SPOTIFY_CLIENT_ID"}}
+	}
+
+	checks := []authCheck{{service, "credentials", checkOK, "client ID and secret", ""}}
+	if creds.ClientSecret == "" {
+		checks[0].Detail = "client ID (PKCE)"
+	}
+
+	if creds.RefreshToken == "" {
+		checks = append(checks, authCheck{service, "refresh", checkWarn, "no refresh token stored", "Expired tokens cannot be renewed; " + authHint})
+	} else {
+		checks = append(checks, authCheck{service, "refresh", checkOK, "refresh token stored", ""})
+	}
+
+	if creds.AccessToken == "" {
+		return append(checks, authCheck{service, "token", checkFail, "not authorized", authHint})
+	}
+
+	account, ok := r.spotify.(spotifyAccount)
+	if !ok {
+		return append(checks, authCheck{service, "token", checkFail, "Spotify service not initialized", "Check credentials.spotify in config.toml"})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, authStatusTimeout)
+	defer cancel()
+
+	user, err := account.UserProfile(ctx)
+	refreshed := false
+	if errors.Is(err, shared.ErrTokenExpired) && creds.RefreshToken != "" {
+		if err = account.RefreshToken(ctx); err == nil {
+			refreshed = true
+			user, err = account.UserProfile(ctx)
 		}
-		return nil
 	}
 
-	return fmt.Errorf("%w: status %d", shared.ErrServiceUnavailable, resp.StatusCode)
+	switch {
+	case err == nil:
+		detail := fmt.Sprintf("signed in as %s", user.DisplayName)
+		if refreshed {
+			detail = "expired token refreshed; " + detail
+		}
+		if expiry := r.config.Credentials.Spotify.TokenExpiry; !expiry.IsZero() {
+			detail += fmt.Sprintf(" (expires %s)", expiry.Local().Format(time.DateTime))
+		}
+		return append(checks, authCheck{service, "token", checkOK, detail, ""})
+	case errors.Is(err, shared.ErrTokenExpired), errors.Is(err, shared.ErrRefreshFailed), errors.Is(err, shared.ErrNoRefreshToken):
+		return append(checks, authCheck{service, "token", checkFail, err.Error(), authHint})
+	default:
+		return append(checks, authCheck{service, "token", checkFail, err.Error(), "Check network access to api.spotify.com"})
+	}
+}
+
+// youtubeChecks verifies the proxy is reachable, the browser headers file is valid JSON, and the proxy
+// reports an authenticated session.
+func (r *Runner) youtubeChecks(ctx context.Context) []authCheck {
+	const service = "YouTube Music"
+	creds := r.config.Credentials.YouTube
+	loginHint := "Run 'ytx setup youtube --curl-file <file>', then 'ytx auth login <headers_path>'"
+
+	var checks []authCheck
+	switch data, err := os.ReadFile(creds.HeadersPath); {
+	case creds.HeadersPath == "":
+		checks = append(checks, authCheck{service, "auth file", checkFail, "headers_path not set", loginHint})
+	case err != nil:
+		checks = append(checks, authCheck{service, "auth file", checkFail, fmt.Sprintf("cannot read %s", creds.HeadersPath), loginHint})
+	case shared.ValidateJSON(data) != nil:
+		checks = append(checks, authCheck{service, "auth file", checkFail, fmt.Sprintf("%s is not valid JSON", creds.HeadersPath), loginHint})
+	default:
+		checks = append(checks, authCheck{service, "auth file", checkOK, creds.HeadersPath, ""})
+	}
+
+	if r.api == nil {
+		return append(checks, authCheck{service, "proxy", checkFail, "API service not initialized", "Set credentials.youtube.proxy_url in config.toml"})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, authStatusTimeout)
+	defer cancel()
+
+	proxyHint := "Start the proxy, or point credentials.youtube.proxy_url at it"
+	resp, err := r.api.Get(ctx, "/health")
+	if err != nil {
+		return append(checks, authCheck{service, "proxy", checkFail, fmt.Sprintf("unreachable: %v", err), proxyHint})
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return append(checks, authCheck{service, "proxy", checkFail, fmt.Sprintf("/health returned status %d", resp.StatusCode), proxyHint})
+	}
+
+	health, _ := resp.JSONData.(map[string]any)
+	status, ok := health["status"].(string)
+	if !ok {
+		status = "healthy"
+	}
+	checks = append(checks, authCheck{service, "proxy", checkOK, status, ""})
+
+	switch authenticated, ok := health["authenticated"].(bool); {
+	case !ok:
+		checks = append(checks, authCheck{service, "session", checkWarn, "proxy did not report its auth state", ""})
+	case authenticated:
+		checks = append(checks, authCheck{service, "session", checkOK, "authenticated", ""})
+	default:
+		checks = append(checks, authCheck{service, "session", checkFail, "not authenticated", loginHint})
+	}
+	return checks
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// BackupCreate writes the whole local database to a portable SQLite archive.
+func (r *Runner) BackupCreate(ctx context.Context, cmd *cli.Command) error {
+	path := cmd.StringArg("file")
+	if path == "" {
+		return fmt.Errorf("%w: backup file is required", shared.ErrMissingArgument)
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r.logger.Info("backing up database", "path", r.config.Database.Path, "file", path)
+	if err := shared.CreateBackup(ctx, db, path); err != nil {
+		return err
+	}
+
+	stats, err := shared.CollectStats(ctx, db)
+	if err != nil {
+		return err
+	}
+	r.writePlainln("✓ Backed up %s (%s, %d tables) to %s",
+		r.config.Database.Path, shared.FormatBytes(stats.SizeBytes), len(stats.Tables), path)
+	return nil
+}
+
+// BackupRestore replaces the contents of the local database with a backup archive.
+func (r *Runner) BackupRestore(ctx context.Context, cmd *cli.Command) error {
+	path := cmd.StringArg("file")
+	if path == "" {
+		return fmt.Errorf("%w: backup file is required", shared.ErrMissingArgument)
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r.logger.Info("restoring database", "path", r.config.Database.Path, "file", path)
+	result, err := shared.RestoreBackup(ctx, db, path)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("json") {
+		return r.writeJSON(result, true)
+	}
+
+	r.writePlainHeader(fmt.Sprintf("Restored %s", path))
+	if result.BackupVersion < result.SchemaVersion {
+		r.writePlain("Migrated backup from schema version %d to %d\n", result.BackupVersion, result.SchemaVersion)
+	}
+	var total int64
+	for _, table := range result.Tables {
+		r.writePlain("  %-24s %d\n", table.Name, table.Rows)
+		total += table.Rows
+	}
+	r.writePlainln("✓ Restored %d rows into %d tables", total, len(result.Tables))
+	return nil
+}
+
+// backupCommand handles backing up and restoring the local database
+func backupCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:  "backup",
+		Usage: "Back up or restore the whole local database (cache, history, and ignore list)",
+		Commands: []*cli.Command{
+			{
+				Name:      "create",
+				Usage:     "Write the database to a portable SQLite archive",
+				ArgsUsage: "<file>",
+				Arguments: []cli.Argument{
+					&cli.StringArg{Name: "file"},
+				},
+				Action: r.BackupCreate,
+			},
+			{
+				Name:      "restore",
+				Usage:     "Replace the database's contents with a backup, migrating older backups first",
+				ArgsUsage: "<file>",
+				Arguments: []cli.Argument{
+					&cli.StringArg{Name: "file"},
+				},
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output raw JSON",
+					},
+				},
+				Action: r.BackupRestore,
+			},
+		},
+	}
+}
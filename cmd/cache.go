@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/desertthunder/ytx/internal/repositories"
 	"github.com/urfave/cli/v3"
 )
 
@@ -65,6 +66,44 @@ func (r *Runner) CachePlaylistYouTube(ctx context.Context, cmd *cli.Command) err
 	return nil
 }
 
+// CachePurge removes search cache entries older than the configured TTL, or all entries with --all.
+func (r *Runner) CachePurge(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ttl := r.config.Cache.SearchCacheTTL()
+	if cmd.Bool("all") {
+		ttl = 0
+	}
+
+	queryCtx, cancel := r.queryContext(ctx)
+	removed, err := repositories.NewSearchCacheRepository(db, ttl).Purge(queryCtx, ttl)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infof("purged %d search cache entries", removed)
+
+	if ttl == 0 {
+		r.writePlainln("✓ Removed %d cached searches", removed)
+	} else {
+		r.writePlainln("✓ Removed %d cached searches older than %s", removed, ttl)
+	}
+
+	if ttl == 0 && r.config.Cache.HTTPDir != "" {
+		if err := r.responseCache(r.profile).Clear(); err != nil {
+			return err
+		}
+		r.writePlainln("✓ Removed cached Spotify responses")
+	}
+
+	return nil
+}
+
 // cacheCommand handles opt-in playlist and track caching
 func cacheCommand(r *Runner) *cli.Command {
 	return &cli.Command{
@@ -114,6 +153,23 @@ func cacheCommand(r *Runner) *cli.Command {
 					},
 				},
 			},
+			{
+				Name:  "purge",
+				Usage: "Remove expired search results from the local cache",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Remove every cached search result, not just expired ones, and cached Spotify responses",
+					},
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
+					},
+				},
+				Action: r.CachePurge,
+			},
 		},
 	}
 }
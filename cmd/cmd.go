@@ -58,6 +58,66 @@ func spotifyCommand(r *Runner) *cli.Command {
 				},
 				Action: r.SpotifyPlaylists,
 			},
+			{
+				Name:  "albums",
+				Usage: "List saved Spotify albums, or look up albums and their tracks by ID",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
+					},
+					&cli.StringFlag{
+						Name:  "ids",
+						Usage: "Comma-separated album IDs to look up (default: saved albums)",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "Maximum number of albums to return (0 for all)",
+						Value: 50,
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output raw JSON",
+					},
+					&cli.BoolFlag{
+						Name:  "pretty",
+						Usage: "Pretty-print output",
+					},
+				},
+				Action: r.SpotifyAlbums,
+			},
+			{
+				Name:  "artists",
+				Usage: "List followed Spotify artists, or look up artists by ID",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
+					},
+					&cli.StringFlag{
+						Name:  "ids",
+						Usage: "Comma-separated artist IDs to look up (default: followed artists)",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "Maximum number of artists to return (0 for all)",
+						Value: 50,
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output raw JSON",
+					},
+					&cli.BoolFlag{
+						Name:  "pretty",
+						Usage: "Pretty-print output",
+					},
+				},
+				Action: r.SpotifyArtists,
+			},
 			{
 				Name:  "export",
 				Usage: "Export playlist JSON for debugging",
@@ -96,13 +156,18 @@ func spotifyCommand(r *Runner) *cli.Command {
 						Name:  "save",
 						Usage: "Save API response locally",
 					},
+					enrichFlag(),
 				},
 				Action: r.SpotifyExport,
 			},
 			{
-				Name:    "export-all",
-				Aliases: []string{"bulk-export"},
-				Usage:   "Export multiple playlists concurrently",
+				Name:  "delete",
+				Usage: "Delete (unfollow) a playlist",
+				Arguments: []cli.Argument{
+					&cli.StringArg{
+						Name: "playlist-id",
+					},
+				},
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:    "config",
@@ -110,35 +175,38 @@ func spotifyCommand(r *Runner) *cli.Command {
 						Usage:   "Path to configuration file",
 						Value:   "config.toml",
 					},
-					&cli.StringFlag{
-						Name:  "ids",
-						Usage: "Comma-separated playlist IDs (default: all user playlists)",
-					},
-					&cli.StringFlag{
-						Name:  "format",
-						Usage: "Export format: json, csv, markdown, txt",
-						Value: "json",
+				},
+				Action: r.SpotifyDelete,
+			},
+			{
+				Name:  "edit",
+				Usage: "Change a playlist's name, description, or visibility",
+				Arguments: []cli.Argument{
+					&cli.StringArg{
+						Name: "playlist-id",
 					},
+				},
+				Flags: append(playlistEditFlags(),
 					&cli.StringFlag{
-						Name:    "output",
-						Aliases: []string{"o"},
-						Usage:   "Output directory name (default: spotify_export_{epoch})",
-					},
-					&cli.IntFlag{
-						Name:  "workers",
-						Usage: "Number of concurrent workers (max 10)",
-						Value: 5,
-					},
-					&cli.Float64Flag{
-						Name:  "rate-limit",
-						Usage: "API requests per second",
-						Value: 5.0,
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
 					},
+				),
+				Action: r.SpotifyEdit,
+			},
+			{
+				Name:    "export-all",
+				Aliases: []string{"bulk-export"},
+				Usage:   "Export multiple playlists concurrently",
+				Flags: append(bulkExportFlags("spotify"),
 					&cli.StringFlag{
 						Name:  "user",
 						Usage: "Filter playlists by user ID (default: all, use 'me' for current user)",
 					},
-				},
+					enrichFlag(),
+				),
 				Action: r.SpotifyExportAll,
 			},
 		},
@@ -200,6 +268,24 @@ func apiCommand(r *Runner) *cli.Command {
 						Usage: "Save dump to api_dump.json",
 						Value: false,
 					},
+					&cli.BoolFlag{
+						Name:  "persist",
+						Usage: "Upsert dumped playlists, songs, albums, and artists into the local database",
+						Value: false,
+					},
+					&cli.BoolFlag{
+						Name:  "full",
+						Usage: "Fetch library endpoints page by page until exhausted instead of the proxy's truncated default",
+					},
+					&cli.StringFlag{
+						Name:  "sections",
+						Usage: "Write each endpoint's data to `DIR`/<name>.json as soon as it is fetched",
+					},
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "Seconds each endpoint may take (default 60, or 600 for history)",
+					},
+					progressFlag(),
 				},
 				Action: r.APIDump,
 			},
@@ -250,6 +336,55 @@ func setupCommand(r *Runner) *cli.Command {
 	}
 }
 
+// diffFlags returns the flags shared by 'ytx diff' and 'ytx transfer diff'
+func diffFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "source-id",
+			Usage: "Source playlist ID",
+		},
+		&cli.StringFlag{
+			Name:  "dest-id",
+			Usage: "Destination playlist ID",
+		},
+		&cli.StringFlag{
+			Name:  "source-file",
+			Usage: "Compare a JSON or CSV export instead of fetching the source playlist",
+		},
+		&cli.StringFlag{
+			Name:  "dest-file",
+			Usage: "Compare a JSON or CSV export instead of fetching the destination playlist",
+		},
+		&cli.StringFlag{
+			Name:     "source-service",
+			Usage:    "Source service (spotify or youtube)",
+			Value:    "spotify",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "dest-service",
+			Usage:    "Destination service (spotify or youtube)",
+			Value:    "youtube",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:  "offline",
+			Usage: "Compare using the local cache, fetching live only for playlists that aren't cached",
+		},
+		progressFlag(),
+	}
+}
+
+// diffCommand exposes playlist comparison at the top level
+func diffCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:   "diff",
+		Usage:  "Compare and show missing tracks between two playlists",
+		Flags:  diffFlags(),
+		Action: r.TransferDiff,
+	}
+}
+
 // transferCommand handles playlist transfer operations (v0.6 stubs)
 func transferCommand(r *Runner) *cli.Command {
 	return &cli.Command{
@@ -258,13 +393,68 @@ func transferCommand(r *Runner) *cli.Command {
 		Commands: []*cli.Command{
 			{
 				Name:  "run",
-				Usage: "Run full Spotify → YouTube Music sync",
+				Usage: "Run full playlist sync (default: Spotify → YouTube Music)",
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:     "source",
 						Usage:    "Source playlist name or ID",
 						Required: true,
 					},
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "Service to transfer from: spotify or youtube",
+						Value: "spotify",
+					},
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "Service to transfer to: spotify or youtube",
+						Value: "youtube",
+					},
+					&cli.StringFlag{
+						Name:  "to-profile",
+						Usage: "Profile whose account receives the playlist, e.g. to transfer Spotify → Spotify between accounts (default: --profile)",
+					},
+					&cli.BoolFlag{
+						Name:  "cache",
+						Usage: "Cache tracks and source/destination playlists in the local database",
+					},
+					&cli.BoolFlag{
+						Name:  "cover",
+						Usage: "Copy the source playlist's cover image to the new playlist",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "Destination playlist name template, e.g. '{{.SourceName}} ({{.Date}})' (default: [transfer] name_template, or the source name)",
+					},
+					&cli.StringFlag{
+						Name:  "description",
+						Usage: "Destination playlist description template (default: [transfer] description_template)",
+					},
+					&cli.BoolFlag{
+						Name:  "split",
+						Usage: "Split a transfer beyond the destination's playlist size limit into 'Name (1/N)', ... playlists instead of failing",
+					},
+					&cli.StringFlag{
+						Name:  "overrides",
+						Usage: "YAML or JSON file pinning source tracks to destination IDs or search queries",
+					},
+					&cli.DurationFlag{
+						Name:  "max-duration-delta",
+						Usage: "Refuse matches whose duration differs from the source track's by more, e.g. 5s (default: [transfer] max_duration_delta, or unchecked)",
+					},
+					&cli.StringFlag{
+						Name:  "prefer",
+						Usage: "Version to take when a search finds both: explicit, clean, or none (default: [transfer] explicit, or none)",
+					},
+					&cli.BoolFlag{
+						Name:  "flag-unavailable",
+						Usage: "Refuse matches that cannot be played in the destination account's region and report them as unavailable (default: [transfer] flag_unavailable)",
+					},
+					&cli.StringFlag{
+						Name:  "report",
+						Usage: "Write a transfer report of matched, failed, and skipped tracks (.html for HTML, Markdown otherwise)",
+					},
+					progressFlag(),
 				},
 				Action: r.TransferRun,
 			},
@@ -274,8 +464,14 @@ func transferCommand(r *Runner) *cli.Command {
 				Action: r.TransferUI,
 			},
 			{
-				Name:  "diff",
-				Usage: "Compare and show missing tracks between two playlists",
+				Name:   "diff",
+				Usage:  "Compare and show missing tracks between two playlists",
+				Flags:  diffFlags(),
+				Action: r.TransferDiff,
+			},
+			{
+				Name:  "merge",
+				Usage: "Reconcile a playlist edited on both services since its last transfer or merge",
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:     "source-id",
@@ -288,19 +484,56 @@ func transferCommand(r *Runner) *cli.Command {
 						Required: true,
 					},
 					&cli.StringFlag{
-						Name:     "source-service",
-						Usage:    "Source service (spotify or youtube)",
-						Value:    "spotify",
-						Required: false,
+						Name:  "source-service",
+						Usage: "Source service (spotify or youtube)",
+						Value: "spotify",
 					},
 					&cli.StringFlag{
-						Name:     "dest-service",
-						Usage:    "Destination service (spotify or youtube)",
-						Value:    "youtube",
-						Required: false,
+						Name:  "dest-service",
+						Usage: "Destination service (spotify or youtube)",
+						Value: "youtube",
 					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show the three-way comparison without changing either playlist",
+					},
+					progressFlag(),
 				},
-				Action: r.TransferDiff,
+				Action: r.TransferMerge,
+			},
+			{
+				Name:  "split",
+				Usage: "Split a playlist into several new playlists by artist, release decade, or size",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "Playlist ID to split",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "service",
+						Usage: "Service of the playlist, where the new playlists are created (spotify or youtube)",
+						Value: "spotify",
+					},
+					&cli.StringFlag{
+						Name:  "by",
+						Usage: "Group tracks by artist or decade (album release date); omit to split by --max-tracks alone",
+					},
+					&cli.IntFlag{
+						Name:  "max-tracks",
+						Usage: "Most tracks per playlist; larger groups are split into numbered parts (0: no limit)",
+					},
+					&cli.IntFlag{
+						Name:  "min-tracks",
+						Usage: "Gather groups with fewer tracks into one \"Other\" playlist (0: keep every group)",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "List the playlists the split would create without creating them",
+					},
+					progressFlag(),
+				},
+				Action: r.TransferSplit,
 			},
 		},
 	}
@@ -321,10 +554,38 @@ func authCommand(r *Runner) *cli.Command {
 				Action: r.AuthLogin,
 			},
 			{
-				Name:   "status",
-				Usage:  "Check current authentication state (calls /health)",
+				Name:  "status",
+				Usage: "Check Spotify token health and YouTube Music proxy reachability and auth",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output checks as JSON",
+					},
+				},
 				Action: r.AuthStatus,
 			},
+			{
+				Name:  "migrate-secure",
+				Usage: "Move access and refresh tokens out of config.toml into the OS keychain or an encrypted file",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "store",
+						Usage: "Where to keep tokens: keychain, file (encrypted with $YTX_SECRETS_PASSPHRASE), or config",
+						Value: "keychain",
+					},
+					&cli.StringFlag{
+						Name:  "path",
+						Usage: "Encrypted credentials file for --store file",
+						Value: "./credentials.enc",
+					},
+					&cli.StringFlag{
+						Name:  "config",
+						Usage: "Path to config file",
+						Value: "config.toml",
+					},
+				},
+				Action: r.AuthMigrateSecure,
+			},
 		},
 	}
 }
@@ -333,7 +594,7 @@ func authCommand(r *Runner) *cli.Command {
 func ytmusicCommand(r *Runner) *cli.Command {
 	return &cli.Command{
 		Name:    "ytmusic",
-		Aliases: []string{"ytm", "yt"},
+		Aliases: []string{"ytm", "yt", "youtube"},
 		Usage:   "YouTube Music operations",
 		Commands: []*cli.Command{
 			{
@@ -395,6 +656,174 @@ func ytmusicCommand(r *Runner) *cli.Command {
 				},
 				Action: r.YTMusicAdd,
 			},
+			{
+				Name:  "delete",
+				Usage: "Delete a playlist from YouTube Music",
+				Arguments: []cli.Argument{
+					&cli.StringArg{
+						Name: "playlist-id",
+					},
+				},
+				Action: r.YTMusicDelete,
+			},
+			{
+				Name:  "edit",
+				Usage: "Change a playlist's title, description, or privacy",
+				Arguments: []cli.Argument{
+					&cli.StringArg{
+						Name: "playlist-id",
+					},
+				},
+				Flags:  playlistEditFlags(),
+				Action: r.YTMusicEdit,
+			},
+			{
+				Name:  "history",
+				Usage: "Export listening history",
+				Flags: append(libraryExportFlags(),
+					&cli.StringFlag{
+						Name:  "since",
+						Usage: "Only tracks played on or after this date (YYYY-MM-DD)",
+					},
+					&cli.StringFlag{
+						Name:  "until",
+						Usage: "Only tracks played on or before this date (YYYY-MM-DD)",
+					},
+				),
+				Action: r.YTMusicHistory,
+			},
+			{
+				Name:   "liked",
+				Usage:  "Export liked songs",
+				Flags:  libraryExportFlags(),
+				Action: r.YTMusicLiked,
+			},
+			{
+				Name:  "export",
+				Usage: "Export playlists concurrently, with their cover thumbnails",
+				Flags: append(bulkExportFlags("youtube_music"),
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Export every library playlist that passes the filters",
+					},
+				),
+				Action: r.YTMusicExport,
+			},
+		},
+	}
+}
+
+// playlistEditFlags returns the metadata flags shared by the playlist edit commands
+func playlistEditFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "title",
+			Usage: "New playlist title",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "New playlist description",
+		},
+		&cli.BoolFlag{
+			Name:  "private",
+			Usage: "Make the playlist private (--private=false makes it public)",
+		},
+	}
+}
+
+// bulkExportFlags returns the flags shared by the bulk exports; service names the default output directory
+func bulkExportFlags(service string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "Path to configuration file",
+			Value:   "config.toml",
+		},
+		&cli.StringFlag{
+			Name:  "ids",
+			Usage: "Comma-separated playlist IDs (default: all user playlists)",
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Export format: json, csv, markdown, txt",
+			Value: "json",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Output directory name (default: " + service + "_export_{epoch})",
+		},
+		&cli.IntFlag{
+			Name:  "workers",
+			Usage: "Number of concurrent workers (max 10)",
+			Value: 5,
+		},
+		&cli.Float64Flag{
+			Name:  "rate-limit",
+			Usage: "API requests per second",
+			Value: 5.0,
+		},
+		&cli.StringFlag{
+			Name:  "match",
+			Usage: "Only playlists whose name matches a glob, ignoring case, e.g. \"Workout*\"",
+		},
+		&cli.IntFlag{
+			Name:  "min-tracks",
+			Usage: "Only playlists with at least this many tracks",
+		},
+		&cli.IntFlag{
+			Name:  "max-tracks",
+			Usage: "Only playlists with at most this many tracks",
+		},
+		&cli.BoolFlag{
+			Name:  "changed",
+			Usage: "Only playlists changed since their last export, transfer, or diff, as cached locally",
+		},
+		&cli.BoolFlag{
+			Name:  "covers",
+			Usage: "Save each playlist's cover image with json, csv, and txt exports (markdown always includes it)",
+		},
+		&cli.StringFlag{
+			Name:  "archive",
+			Usage: "Also package the output directory into a single zip or tar.gz",
+		},
+	}
+}
+
+// enrichFlag adds artist genres and audio features to Spotify exports
+func enrichFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "enrich",
+		Usage: "Add artist genres and audio features to each track and its cached copy (a few extra requests per 50 tracks)",
+	}
+}
+
+// libraryExportFlags returns the output flags shared by the history and liked songs exports
+func libraryExportFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Export format: json or csv",
+			Value: "json",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Output file (csv writes <output>_tracks.csv and <output>_metadata.json)",
+		},
+		&cli.BoolFlag{
+			Name:  "save",
+			Usage: "Save to history.json / liked.json (or the csv equivalents)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Output raw JSON instead of a track listing",
+		},
+		&cli.BoolFlag{
+			Name:  "pretty",
+			Usage: "Pretty-print output",
+			Value: true,
 		},
 	}
 }
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// playlistCompletion names a flag, or the first argument when flag is empty, of the command at path that takes a
+// playlist ID, completed from the playlists cached for service. An empty service offers both services' playlists,
+// unless serviceFlag names a flag that picks one.
+type playlistCompletion struct {
+	path        []string
+	flag        string
+	service     string
+	serviceFlag string
+}
+
+// playlistCompletions lists every flag and argument completed with cached playlist IDs
+var playlistCompletions = []playlistCompletion{
+	{path: []string{"diff"}, flag: "source-id", serviceFlag: "source-service"},
+	{path: []string{"diff"}, flag: "dest-id", serviceFlag: "dest-service"},
+	{path: []string{"transfer", "run"}, flag: "source", service: "spotify", serviceFlag: "from"},
+	{path: []string{"transfer", "diff"}, flag: "source-id", serviceFlag: "source-service"},
+	{path: []string{"transfer", "diff"}, flag: "dest-id", serviceFlag: "dest-service"},
+	{path: []string{"transfer", "merge"}, flag: "source-id", serviceFlag: "source-service"},
+	{path: []string{"transfer", "merge"}, flag: "dest-id", serviceFlag: "dest-service"},
+	{path: []string{"transfer", "split"}, flag: "id", serviceFlag: "service"},
+	{path: []string{"spotify", "export"}, flag: "id", service: "spotify"},
+	{path: []string{"spotify", "delete"}, service: "spotify"},
+	{path: []string{"spotify", "edit"}, service: "spotify"},
+	{path: []string{"ytmusic", "add"}, flag: "playlist-id", service: "youtube"},
+	{path: []string{"ytmusic", "delete"}, service: "youtube"},
+	{path: []string{"ytmusic", "edit"}, service: "youtube"},
+}
+
+// configureCompletion shows the built-in completion command and attaches playlist completers to the commands of
+// root listed in [playlistCompletions].
+//
+// Fish completes from a static script rather than by asking ytx, so its script gains a line per playlist flag that
+// asks for the cached playlists when the flag is completed.
+func (r *Runner) configureCompletion(root *cli.Command) {
+	root.EnableShellCompletion = true
+	root.ConfigureShellCompletionCommand = func(cmd *cli.Command) {
+		cmd.Hidden = false
+		cmd.Usage = "Output a shell completion script for bash, zsh, fish, or pwsh"
+		cmd.ArgsUsage = "<shell>"
+		printScript := cmd.Action
+		cmd.Action = func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().First() != "fish" {
+				return printScript(ctx, cmd)
+			}
+			script, err := cmd.Root().ToFishCompletion()
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(cmd.Root().Writer, script+fishPlaylistCompletions(cmd.Root()))
+			return err
+		}
+	}
+
+	for _, c := range playlistCompletions {
+		cmd := root
+		for _, name := range c.path {
+			if cmd = cmd.Command(name); cmd == nil {
+				panic("completion for unknown command " + strings.Join(c.path, " "))
+			}
+		}
+		cmd.ShellComplete = r.completePlaylists
+	}
+}
+
+// completePlaylists completes the playlist flag or argument being typed with the IDs of cached playlists, and
+// everything else as urfave/cli does.
+//
+// Completion runs before [Runner.configure], with nothing but the cache to go on: when the database doesn't exist
+// yet or can't be read, no playlists are offered and nothing is reported.
+func (r *Runner) completePlaylists(ctx context.Context, cmd *cli.Command) {
+	completion, ok := completionFor(cmd, os.Args)
+	if !ok {
+		cli.DefaultCompleteWithFlags(ctx, cmd)
+		return
+	}
+
+	service := completion.service
+	if completion.serviceFlag != "" && cmd.IsSet(completion.serviceFlag) {
+		service = cmd.String(completion.serviceFlag)
+	}
+
+	playlists, err := r.cachedPlaylists(ctx, cmd, service)
+	if err != nil {
+		return
+	}
+	writePlaylistCompletions(cmd.Root().Writer, playlists, completionShell())
+}
+
+// completionFor finds the entry of [playlistCompletions] for cmd that the word being completed belongs to, given
+// the process arguments ending with --generate-shell-completion
+func completionFor(cmd *cli.Command, args []string) (playlistCompletion, bool) {
+	path := []string{}
+	for c := cmd; c.Root() != c; c = c.Lineage()[1] {
+		path = append([]string{c.Name}, path...)
+	}
+
+	previous := ""
+	if len(args) >= 2 {
+		previous = args[len(args)-2]
+	}
+	for _, c := range playlistCompletions {
+		if !slices.Equal(c.path, path) {
+			continue
+		}
+		if c.flag != "" && previous == "--"+c.flag {
+			return c, true
+		}
+		if c.flag == "" && !strings.HasPrefix(previous, "-") && cmd.Args().Len() == 0 {
+			return c, true
+		}
+	}
+	return playlistCompletion{}, false
+}
+
+// cachedPlaylists lists the playlists of service in the configured cache, or of every service when it is empty
+func (r *Runner) cachedPlaylists(ctx context.Context, cmd *cli.Command, service string) ([]*models.PersistedPlaylist, error) {
+	base := r.fileConfig
+	if loaded, err := shared.LoadConfig(r.configPath); err == nil {
+		base = loaded
+	}
+	config, err := r.resolveConfig(cmd, base)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completing should never create a database
+	if !shared.IsPostgresDSN(config.Database.Path) {
+		if _, err := os.Stat(config.Database.Path); errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	db, err := shared.OpenDatabase(config.Database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	criteria := map[string]any{}
+	if service != "" {
+		criteria["service"] = service
+	}
+	return repositories.NewPlaylistRepository(db).List(ctx, criteria, models.ListOpts{})
+}
+
+// completionShell returns the shell asking for completions, as urfave/cli detects it
+func completionShell() string {
+	if strings.HasSuffix(os.Getenv("SHELL"), "zsh") {
+		return "zsh"
+	}
+	if strings.HasSuffix(os.Getenv("SHELL"), "fish") {
+		return "fish"
+	}
+	return "bash"
+}
+
+// writePlaylistCompletions writes one completion per playlist: its ID, described by its name for shells that show
+// descriptions
+func writePlaylistCompletions(w io.Writer, playlists []*models.PersistedPlaylist, shell string) {
+	for _, p := range playlists {
+		name := strings.Join(strings.Fields(p.Name()), " ")
+		switch {
+		case name == "" || shell == "bash":
+			fmt.Fprintln(w, p.ServiceID())
+		case shell == "zsh":
+			fmt.Fprintf(w, "%s:%s\n", strings.ReplaceAll(p.ServiceID(), ":", `\:`), name)
+		default:
+			fmt.Fprintf(w, "%s\t%s\n", p.ServiceID(), name)
+		}
+	}
+}
+
+// fishPlaylistCompletions returns fish completions asking root for cached playlists when a playlist flag or
+// argument of [playlistCompletions] is completed
+func fishPlaylistCompletions(root *cli.Command) string {
+	var b strings.Builder
+	b.WriteString("\n# Playlist IDs from the local cache\n")
+	for _, c := range playlistCompletions {
+		conditions := []string{}
+		cmd := root
+		for _, name := range c.path {
+			cmd = cmd.Command(name)
+			conditions = append(conditions, "__fish_seen_subcommand_from "+strings.Join(cmd.Names(), " "))
+		}
+		condition := strings.Join(conditions, "; and ")
+		words := strings.Join(append([]string{root.Name}, c.path...), " ")
+
+		if c.flag == "" {
+			fmt.Fprintf(&b, "complete -c %s -n '%s' -f -a '(env SHELL=fish %s --generate-shell-completion)'\n",
+				root.Name, condition, words)
+			continue
+		}
+		fmt.Fprintf(&b, "complete -c %s -n '%s' -l %s -x -a '(env SHELL=fish %s --%s --generate-shell-completion)'\n",
+			root.Name, condition, c.flag, words, c.flag)
+	}
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// configCommandName is the name of [configCommand], whose subcommands run without services
+const configCommandName = "config"
+
+// configCommand inspects and edits config.toml
+func configCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:  configCommandName,
+		Usage: "Inspect and edit the configuration file",
+		Commands: []*cli.Command{
+			{
+				Name:  "validate",
+				Usage: "Report every problem with the configuration: missing credentials, bad URLs, unreadable files",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output problems as JSON",
+					},
+				},
+				Action: r.ConfigValidate,
+			},
+			{
+				Name:      "get",
+				Usage:     "Print a config value by its dotted key, e.g. credentials.youtube.proxy_url",
+				ArgsUsage: "<key>",
+				Arguments: []cli.Argument{
+					&cli.StringArg{Name: "key"},
+				},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
+					},
+				},
+				Action: r.ConfigGet,
+			},
+			{
+				Name:      "set",
+				Usage:     "Change a config value by its dotted key, keeping the file's comments and layout",
+				ArgsUsage: "<key> <value>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
+					},
+				},
+				Action: r.ConfigSet,
+			},
+		},
+	}
+}
+
+// ConfigValidate checks the configuration file, with the selected profile and any overrides applied, and lists
+// every problem found.
+//
+// Unlike other commands, which warn about problems and carry on, it fails when there are any.
+func (r *Runner) ConfigValidate(ctx context.Context, cmd *cli.Command) error {
+	configPath := cmd.String("config")
+	if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s does not exist; run 'ytx setup database' to create it", shared.ErrMissingConfig, configPath)
+	}
+
+	config, err := shared.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrInvalidConfig, err)
+	}
+	if config, err = r.resolveConfig(cmd, config); err != nil {
+		return err
+	}
+
+	var problems []string
+	var configErr *shared.ConfigError
+	if err := config.Validate(); errors.As(err, &configErr) {
+		for _, problem := range configErr.Problems {
+			problems = append(problems, problem.Error())
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if cmd.Bool("json") {
+		report := struct {
+			Path     string   `json:"path"`
+			Valid    bool     `json:"valid"`
+			Problems []string `json:"problems"`
+		}{configPath, len(problems) == 0, problems}
+		if report.Problems == nil {
+			report.Problems = []string{}
+		}
+		if err := r.writeJSON(report, true); err != nil {
+			return err
+		}
+	} else if len(problems) == 0 {
+		r.writePlain("✓ %s is valid\n", configPath)
+	} else {
+		r.writePlain("%s:\n", configPath)
+		for _, problem := range problems {
+			r.writePlain("  ✗ %s\n", problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d problems in %s", shared.ErrInvalidConfig, len(problems), configPath)
+	}
+	return nil
+}
+
+// ConfigGet prints the value of a config key, with the selected profile and any overrides applied, so scripts see
+// what commands use.
+func (r *Runner) ConfigGet(ctx context.Context, cmd *cli.Command) error {
+	key := cmd.StringArg("key")
+	if key == "" {
+		return fmt.Errorf("%w: config key, e.g. credentials.youtube.proxy_url", shared.ErrMissingArgument)
+	}
+
+	configPath := cmd.String("config")
+	config, err := shared.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrMissingConfig, err)
+	}
+	if config, err = r.resolveConfig(cmd, config); err != nil {
+		return err
+	}
+
+	value, err := shared.ConfigValue(config, key)
+	if err != nil {
+		return err
+	}
+	return r.writePlain("%s\n", value)
+}
+
+// ConfigSet changes the value of a config key in the configuration file, keeping its comments, and warns about
+// any problem the new value causes.
+func (r *Runner) ConfigSet(ctx context.Context, cmd *cli.Command) error {
+	// An empty value clears a key, so it must be given explicitly as ""
+	if cmd.Args().Len() != 2 {
+		return fmt.Errorf("%w: usage: ytx config set <key> <value>", shared.ErrMissingArgument)
+	}
+	key, value := cmd.Args().Get(0), cmd.Args().Get(1)
+
+	configPath := cmd.String("config")
+	if err := shared.SetConfigValue(configPath, key, value); err != nil {
+		return err
+	}
+
+	if config, err := shared.LoadConfig(configPath); err == nil {
+		var configErr *shared.ConfigError
+		if errors.As(config.Validate(), &configErr) {
+			for _, problem := range configErr.Problems {
+				if strings.Contains(problem.Error(), key) {
+					r.logger.Warnf("config: %v", problem)
+				}
+			}
+		}
+	}
+	return r.writePlain("✓ Set %s in %s\n", key, configPath)
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// DBMigrate applies all pending schema migrations.
+func (r *Runner) DBMigrate(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	before, err := shared.SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	if err := shared.RunMigrations(db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	after, err := shared.SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infof("migrated database %v from version %d to %d", r.config.Database.Path, before, after)
+
+	if before == after {
+		r.writePlainln("✓ Database is up to date (version %d)", after)
+	} else {
+		r.writePlainln("✓ Migrated database from version %d to %d", before, after)
+	}
+
+	return nil
+}
+
+// DBStatus lists every known migration and whether it has been applied.
+func (r *Runner) DBStatus(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	statuses, err := shared.MigrationStatuses(db)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("json") {
+		return r.writeJSON(statuses, true)
+	}
+
+	pending := 0
+	r.writePlainHeader(fmt.Sprintf("Database: %s", r.config.Database.Path))
+	for _, status := range statuses {
+		if status.Applied {
+			r.writePlain("  ✓ %04d %-28s applied %s\n", status.Version, status.Name, status.AppliedAt.Local().Format("2006-01-02 15:04:05"))
+		} else {
+			pending++
+			r.writePlain("  · %04d %-28s pending\n", status.Version, status.Name)
+		}
+	}
+	r.writePlainln("%d applied, %d pending", len(statuses)-pending, pending)
+
+	return nil
+}
+
+// DBRollback reverts the most recently applied migrations.
+func (r *Runner) DBRollback(ctx context.Context, cmd *cli.Command) error {
+	steps := cmd.Int("steps")
+	if steps < 1 {
+		return fmt.Errorf("%w: --steps must be at least 1", shared.ErrInvalidFlag)
+	}
+
+	db, err := r.connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for i := 0; i < steps; i++ {
+		version, err := shared.SchemaVersion(db)
+		if err != nil {
+			return err
+		}
+		if version < 0 {
+			r.writePlainln("No migrations left to roll back")
+			return nil
+		}
+
+		if err := shared.RollbackMigration(db); err != nil {
+			return err
+		}
+
+		r.logger.Infof("rolled back migration %d", version)
+		r.writePlain("✓ Rolled back migration %04d\n", version)
+	}
+
+	return nil
+}
+
+// DBStats reports the database size along with per-table row counts and sequence values.
+func (r *Runner) DBStats(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := shared.CollectStats(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("json") {
+		return r.writeJSON(stats, true)
+	}
+
+	r.writePlainHeader(fmt.Sprintf("Database: %s", r.config.Database.Path))
+	r.writePlain("Size: %s (%d pages, %d free)\n\n", shared.FormatBytes(stats.SizeBytes), stats.PageCount, stats.FreePages)
+	r.writePlain("  %-24s %10s %10s %10s\n", "TABLE", "ROWS", "DELETED", "SEQUENCE")
+	for _, table := range stats.Tables {
+		sequence := "-"
+		if table.Sequence != nil {
+			sequence = fmt.Sprintf("%d", *table.Sequence)
+		}
+		r.writePlain("  %-24s %10d %10d %10s\n", table.Name, table.Rows, table.Deleted, sequence)
+	}
+
+	return nil
+}
+
+// DBCheck runs SQLite's integrity check and reports any problems found.
+func (r *Runner) DBCheck(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	problems, err := shared.IntegrityCheck(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		r.writePlainln("✓ Integrity check passed")
+		return nil
+	}
+
+	r.writePlainln("✗ Integrity check found %d problems:", len(problems))
+	for _, problem := range problems {
+		r.writePlain("  - %s\n", problem)
+	}
+	return fmt.Errorf("database integrity check failed with %d problems", len(problems))
+}
+
+// DBVacuum reclaims unused space and refreshes query planner statistics.
+func (r *Runner) DBVacuum(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.connectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	before, err := shared.CollectStats(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("vacuuming database", "path", r.config.Database.Path)
+	if err := shared.Vacuum(ctx, db); err != nil {
+		return err
+	}
+
+	after, err := shared.CollectStats(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	r.writePlainln("✓ Vacuumed and analyzed database: %s → %s",
+		shared.FormatBytes(before.SizeBytes), shared.FormatBytes(after.SizeBytes))
+
+	return nil
+}
+
+// DBPurge permanently removes rows that were soft-deleted longer ago than the retention window.
+func (r *Runner) DBPurge(ctx context.Context, cmd *cli.Command) error {
+	olderThan := cmd.Duration("older-than")
+	if olderThan < 0 {
+		return fmt.Errorf("%w: --older-than cannot be negative", shared.ErrInvalidFlag)
+	}
+	only := cmd.String("table")
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Dependent tables are purged before the tables they reference.
+	purgers := []struct {
+		table string
+		purge func(context.Context, time.Duration) (int64, error)
+	}{
+		{"playlist_tracks", repositories.NewPlaylistTrackRepository(db).PurgeDeleted},
+		{"migrations", repositories.NewMigrationRepository(db).PurgeDeleted},
+		{"playlists", repositories.NewPlaylistRepository(db).PurgeDeleted},
+		{"tracks", repositories.NewTrackRepository(db).PurgeDeleted},
+		{"albums", repositories.NewAlbumRepository(db).PurgeDeleted},
+		{"artists", repositories.NewArtistRepository(db).PurgeDeleted},
+		{"users", repositories.NewUserRepository(db).PurgeDeleted},
+	}
+
+	var total int64
+	matched := false
+	for _, p := range purgers {
+		if only != "" && only != p.table {
+			continue
+		}
+		matched = true
+
+		removed, err := p.purge(ctx, olderThan)
+		if err != nil {
+			return err
+		}
+		total += removed
+		r.logger.Debugf("purged %d rows from %s", removed, p.table)
+		r.writePlain("  %-16s %d removed\n", p.table, removed)
+	}
+
+	if !matched {
+		return fmt.Errorf("%w: unknown table %q", shared.ErrInvalidArgument, only)
+	}
+
+	r.writePlainln("✓ Purged %d soft-deleted rows older than %s", total, olderThan)
+	return nil
+}
+
+// deletedRecord is the common view of soft-deleted models listed by 'ytx db restore'.
+type deletedRecord interface {
+	ID() string
+	DeletedAt() *time.Time
+}
+
+// restorableTable pairs a table's restore and deleted-listing operations.
+type restorableTable struct {
+	restore     func(context.Context, string) error
+	listDeleted func(context.Context) ([]deletedRecord, error)
+}
+
+// listDeletedAs adapts a repository's typed ListDeleted to return [deletedRecord] values.
+func listDeletedAs[T deletedRecord](list func(context.Context) ([]T, error)) func(context.Context) ([]deletedRecord, error) {
+	return func(ctx context.Context) ([]deletedRecord, error) {
+		items, err := list(ctx)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]deletedRecord, len(items))
+		for i, item := range items {
+			records[i] = item
+		}
+		return records, nil
+	}
+}
+
+// restorableTables returns the soft-deletable tables keyed by table name.
+func restorableTables(db *sql.DB) map[string]restorableTable {
+	users := repositories.NewUserRepository(db)
+	playlists := repositories.NewPlaylistRepository(db)
+	tracks := repositories.NewTrackRepository(db)
+	albums := repositories.NewAlbumRepository(db)
+	artists := repositories.NewArtistRepository(db)
+	playlistTracks := repositories.NewPlaylistTrackRepository(db)
+	migrations := repositories.NewMigrationRepository(db)
+
+	return map[string]restorableTable{
+		"users":           {users.Restore, listDeletedAs(users.ListDeleted)},
+		"playlists":       {playlists.Restore, listDeletedAs(playlists.ListDeleted)},
+		"tracks":          {tracks.Restore, listDeletedAs(tracks.ListDeleted)},
+		"albums":          {albums.Restore, listDeletedAs(albums.ListDeleted)},
+		"artists":         {artists.Restore, listDeletedAs(artists.ListDeleted)},
+		"playlist_tracks": {playlistTracks.Restore, listDeletedAs(playlistTracks.ListDeleted)},
+		"migrations":      {migrations.Restore, listDeletedAs(migrations.ListDeleted)},
+	}
+}
+
+// describeDeleted returns a short human-readable label for a soft-deleted record.
+func describeDeleted(record deletedRecord) string {
+	switch v := record.(type) {
+	case *models.User:
+		return v.Email()
+	case *models.PersistedPlaylist:
+		return fmt.Sprintf("%s (%s)", v.Name(), v.Service())
+	case *models.PersistedTrack:
+		return fmt.Sprintf("%s - %s (%s)", v.Artist(), v.Title(), v.Service())
+	case *models.PersistedAlbum:
+		return fmt.Sprintf("%s - %s (%s)", v.Artist(), v.Title(), v.Service())
+	case *models.PersistedArtist:
+		return fmt.Sprintf("%s (%s)", v.Name(), v.Service())
+	case *models.PlaylistTrack:
+		return fmt.Sprintf("playlist %s #%d", v.PlaylistID(), v.Position())
+	case *models.MigrationJob:
+		return fmt.Sprintf("%s → %s", v.SourceService(), v.TargetService())
+	default:
+		return ""
+	}
+}
+
+// DBRestore restores a soft-deleted row, or lists a table's soft-deleted rows when no ID is given.
+func (r *Runner) DBRestore(ctx context.Context, cmd *cli.Command) error {
+	tableName := cmd.StringArg("table")
+	id := cmd.StringArg("id")
+
+	if tableName == "" {
+		return fmt.Errorf("%w: table is required", shared.ErrMissingArgument)
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	table, ok := restorableTables(db)[tableName]
+	if !ok {
+		return fmt.Errorf("%w: unknown table %q", shared.ErrInvalidArgument, tableName)
+	}
+
+	if id == "" {
+		deleted, err := table.listDeleted(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(deleted) == 0 {
+			r.writePlainln("No deleted %s", tableName)
+			return nil
+		}
+
+		r.writePlainHeader(fmt.Sprintf("Deleted %s (%d)", tableName, len(deleted)))
+		for _, record := range deleted {
+			r.writePlain("  %s  %s  %s\n", record.ID(), record.DeletedAt().Local().Format("2006-01-02 15:04:05"), describeDeleted(record))
+		}
+		return nil
+	}
+
+	if err := table.restore(ctx, id); err != nil {
+		return err
+	}
+
+	r.logger.Infof("restored %s %s", tableName, id)
+	r.writePlainln("✓ Restored %s %s", tableName, id)
+	return nil
+}
+
+// dbCommand handles local database maintenance
+func dbCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:    "db",
+		Aliases: []string{"database"},
+		Usage:   "Manage the local database",
+		Commands: []*cli.Command{
+			{
+				Name:   "migrate",
+				Usage:  "Apply pending schema migrations",
+				Action: r.DBMigrate,
+			},
+			{
+				Name:  "status",
+				Usage: "Show applied and pending schema migrations",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output raw JSON",
+					},
+				},
+				Action: r.DBStatus,
+			},
+			{
+				Name:  "rollback",
+				Usage: "Revert the most recently applied schema migrations",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "steps",
+						Usage: "Number of migrations to revert",
+						Value: 1,
+					},
+				},
+				Action: r.DBRollback,
+			},
+			{
+				Name:  "stats",
+				Usage: "Show database size, row counts, and sequence values",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output raw JSON",
+					},
+				},
+				Action: r.DBStats,
+			},
+			{
+				Name:   "check",
+				Usage:  "Run an integrity check on the database",
+				Action: r.DBCheck,
+			},
+			{
+				Name:   "vacuum",
+				Usage:  "Reclaim unused space and refresh query statistics (VACUUM + ANALYZE)",
+				Action: r.DBVacuum,
+			},
+			{
+				Name:  "purge",
+				Usage: "Permanently remove soft-deleted rows past the retention window",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "older-than",
+						Usage: "Only purge rows deleted longer ago than this (0 purges all soft-deleted rows)",
+						Value: 30 * 24 * time.Hour,
+					},
+					&cli.StringFlag{
+						Name:  "table",
+						Usage: "Only purge this table (e.g. playlists, tracks)",
+					},
+				},
+				Action: r.DBPurge,
+			},
+			{
+				Name:      "restore",
+				Usage:     "Restore a soft-deleted row, or list a table's deleted rows when no ID is given",
+				ArgsUsage: "<table> [id]",
+				Arguments: []cli.Argument{
+					&cli.StringArg{Name: "table"},
+					&cli.StringArg{Name: "id"},
+				},
+				Action: r.DBRestore,
+			},
+		},
+	}
+}
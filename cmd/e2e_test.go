@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	tu "github.com/desertthunder/ytx/internal/testing"
+)
+
+// e2e runs ytx commands through the full command tree, from flag parsing to output, against fake services sharing
+// one temporary database
+type e2e struct {
+	t       *testing.T
+	dir     string
+	config  *shared.Config
+	spotify *tu.FakeService
+	youtube *tu.FakeService
+}
+
+// run is the output of one command
+type run struct {
+	stdout string
+	stderr string
+	err    error
+}
+
+func newE2E(t *testing.T) *e2e {
+	t.Helper()
+	dir := t.TempDir()
+	config := shared.DefaultConfig()
+	config.Database.Path = filepath.Join(dir, "ytx.db")
+	config.Logging.File = filepath.Join(dir, "ytx.log")
+
+	spotify := tu.NewFakeService("Spotify")
+	spotify.AddPlaylist(models.PlaylistExport{
+		Playlist: models.Playlist{ID: "sp1", Name: "Road Trip", Description: "Songs for the drive", Owner: "Ada", Followers: 4},
+		Tracks: []models.Track{
+			{ID: "s1", Title: "Nude", Artists: []string{"Radiohead"}, Album: "In Rainbows", Duration: 255},
+			{ID: "s2", Title: "Hoppípolla", Artists: []string{"Sigur Rós"}, Album: "Takk...", Duration: 268},
+			{ID: "s3", Title: "Unreleased Demo", Artists: []string{"Nobody"}},
+			{ID: "e1", Title: "Episode 12", Artists: []string{"Song Exploder"}, URI: "spotify:episode:e1"},
+		},
+	})
+
+	youtube := tu.NewFakeService("YouTube Music")
+	youtube.AddCatalog(
+		models.Track{ID: "v1", Title: "Nude", Artists: []string{"Radiohead"}, Duration: 255},
+		models.Track{ID: "v2", Title: "Hoppipolla", Artists: []string{"Sigur Ros"}, Duration: 268},
+	)
+	youtube.AddPlaylist(models.PlaylistExport{
+		Playlist: models.Playlist{ID: "yt1", Name: "Road Trip (YouTube)"},
+		Tracks: []models.Track{
+			{ID: "v1", Title: "Nude", Artists: []string{"Radiohead"}},
+			{ID: "v9", Title: "Idioteque", Artists: []string{"Radiohead"}},
+		},
+	})
+
+	return &e2e{t: t, dir: dir, config: config, spotify: spotify, youtube: youtube}
+}
+
+// run runs ytx with args on a new runner, as a new process would
+func (e *e2e) run(args ...string) run {
+	e.t.Helper()
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	runner := NewRunner(RunnerOpts{
+		Config:     e.config,
+		ConfigPath: filepath.Join(e.dir, "config.toml"),
+		Spotify:    e.spotify,
+		YouTube:    e.youtube,
+		Output:     stdout,
+		ErrOutput:  stderr,
+	})
+	err := newApp(runner).Run(e.t.Context(), append([]string{"ytx"}, args...))
+	return run{stdout: stdout.String(), stderr: stderr.String(), err: err}
+}
+
+// path returns the path of name in the test's temporary directory
+func (e *e2e) path(name string) string {
+	return filepath.Join(e.dir, name)
+}
+
+func assertContains(t *testing.T, output string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("expected output to contain %q, got:\n%s", w, output)
+		}
+	}
+}
+
+func TestE2E(t *testing.T) {
+	t.Run("transfer run", func(t *testing.T) {
+		e := newE2E(t)
+		got := e.run("transfer", "run", "--source", "sp1", "--report", e.path("report.md"))
+		if got.err != nil {
+			t.Fatalf("transfer failed: %v\n%s", got.err, got.stdout)
+		}
+
+		assertContains(t, got.stdout,
+			"Transfer Complete!",
+			"Source: Road Trip (4 tracks)",
+			"Destination: Road Trip (2 tracks)",
+			"Success rate: 2/3 (66.7%)",
+			"Skipped (unmatchable): 1",
+			"Time: ",
+			"Failed to match 1 tracks:",
+			"  - Nobody - Unreleased Demo",
+			"  - Song Exploder - Episode 12 (skipped: ",
+			"Report: "+e.path("report.md"),
+		)
+
+		created, ok := e.youtube.Playlist("fake-1")
+		if !ok {
+			t.Fatal("expected the playlist to be created on YouTube Music")
+		}
+		if len(created.Tracks) != 2 || created.Tracks[0].ID != "v1" || created.Tracks[1].ID != "v2" {
+			t.Errorf("expected the matched tracks in source order, got %+v", created.Tracks)
+		}
+
+		report := tu.MustReadFile(t, e.path("report.md"))
+		assertContains(t, report, "Road Trip", "Nude", "Unreleased Demo", "Episode 12")
+	})
+
+	t.Run("transfer run reports JSON progress on stderr", func(t *testing.T) {
+		e := newE2E(t)
+		got := e.run("transfer", "run", "--source", "sp1", "--progress", "json")
+		if got.err != nil {
+			t.Fatalf("transfer failed: %v", got.err)
+		}
+
+		phases := map[string]bool{}
+		scanner := bufio.NewScanner(strings.NewReader(got.stderr))
+		for scanner.Scan() {
+			var event struct {
+				Phase string `json:"phase"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				t.Fatalf("expected one JSON object per line, got %q: %v", scanner.Text(), err)
+			}
+			phases[event.Phase] = true
+		}
+		for _, phase := range []string{"fetch_source", "search_tracks", "create_playlist"} {
+			if !phases[phase] {
+				t.Errorf("expected a %s event, got %v", phase, phases)
+			}
+		}
+	})
+
+	t.Run("transfer run flag errors", func(t *testing.T) {
+		e := newE2E(t)
+		if got := e.run("transfer", "run"); got.err == nil || !strings.Contains(got.err.Error(), "source") {
+			t.Errorf("expected the required --source flag to be reported, got %v", got.err)
+		}
+		if got := e.run("transfer", "run", "--source", "sp1", "--prefer", "loud"); !errors.Is(got.err, shared.ErrInvalidArgument) {
+			t.Errorf("expected an invalid --prefer to be refused, got %v", got.err)
+		}
+		if got := e.run("transfer", "run", "--source", "missing"); !errors.Is(got.err, shared.ErrPlaylistNotFound) || exitCodeFor(got.err) != exitNotFound {
+			t.Errorf("expected a missing playlist to exit with %d, got %v", exitNotFound, got.err)
+		}
+		if got := e.run("transfer", "run", "--source", "sp1", "--to", "spotify"); !errors.Is(got.err, shared.ErrInvalidArgument) {
+			t.Errorf("expected a transfer within one account to be refused, got %v", got.err)
+		}
+		if got := e.run("transfer", "run", "--source", "sp1", "--to", "spotify", "--to-profile", "work"); !errors.Is(got.err, shared.ErrMissingConfig) {
+			t.Errorf("expected an undefined --to-profile to be reported, got %v", got.err)
+		}
+		if got := e.run("transfer", "run", "--source", "sp1", "--from", "deezer"); !errors.Is(got.err, shared.ErrInvalidArgument) {
+			t.Errorf("expected an unknown --from service to be refused, got %v", got.err)
+		}
+		if _, ok := e.youtube.Playlist("fake-1"); ok {
+			t.Error("expected no playlist created by failed transfers")
+		}
+	})
+
+	t.Run("transfer run from YouTube Music", func(t *testing.T) {
+		e := newE2E(t)
+		e.spotify.AddCatalog(models.Track{ID: "s1", Title: "Nude", Artists: []string{"Radiohead"}, Duration: 255})
+
+		got := e.run("transfer", "run", "--source", "yt1", "--from", "ytmusic", "--to", "spotify")
+		if got.err != nil {
+			t.Fatalf("transfer failed: %v\n%s", got.err, got.stdout)
+		}
+		assertContains(t, got.stdout, "Source: Road Trip (YouTube) (2 tracks)", "Success rate: 1/2 (50.0%)", "  - Radiohead - Idioteque")
+
+		created, ok := e.spotify.Playlist("fake-1")
+		if !ok || len(created.Tracks) != 1 || created.Tracks[0].ID != "s1" {
+			t.Errorf("expected the matched track imported into Spotify, got %+v", created)
+		}
+		if _, ok := e.youtube.Playlist("fake-1"); ok {
+			t.Error("expected nothing created on YouTube Music")
+		}
+	})
+
+	t.Run("spotify export", func(t *testing.T) {
+		e := newE2E(t)
+
+		got := e.run("spotify", "export", "--id", "sp1", "--format", "csv", "--output", e.path("road_trip"))
+		if got.err != nil {
+			t.Fatalf("export failed: %v", got.err)
+		}
+		assertContains(t, got.stdout, "✓ Playlist exported to:", "road_trip_tracks.csv (4 tracks)", "road_trip_metadata.json")
+		tu.AssertFileExists(t, e.path("road_trip_metadata.json"))
+		csv := tu.MustReadFile(t, e.path("road_trip_tracks.csv"))
+		if lines := strings.Split(strings.TrimSpace(csv), "\n"); len(lines) != 5 || !strings.HasPrefix(lines[0], "ID,Title,Artist") {
+			t.Errorf("expected a header and 4 tracks, got:\n%s", csv)
+		}
+		assertContains(t, csv, "Hoppípolla,Sigur Rós")
+		assertContains(t, tu.MustReadFile(t, e.path("road_trip_metadata.json")), `"Owner": "Ada"`, `"Followers": 4`)
+
+		got = e.run("spotify", "export", "--id", "sp1", "--json")
+		if got.err != nil {
+			t.Fatalf("export failed: %v", got.err)
+		}
+		var export models.PlaylistExport
+		if err := json.Unmarshal([]byte(got.stdout), &export); err != nil {
+			t.Fatalf("expected the export as JSON, got %q: %v", got.stdout, err)
+		}
+		if export.Playlist.Name != "Road Trip" || len(export.Tracks) != 4 {
+			t.Errorf("unexpected export %+v", export)
+		}
+
+		got = e.run("spotify", "export", "--id", "sp1")
+		assertContains(t, got.stdout, "Playlist: Road Trip", "Description: Songs for the drive", "Owner: Ada", "Followers: 4", "1. Radiohead - Nude")
+
+		if got := e.run("spotify", "export"); got.err == nil || !strings.Contains(got.err.Error(), `"id"`) {
+			t.Errorf("expected the required --id flag to be reported, got %v", got.err)
+		}
+		if got := e.run("spotify", "export", "--id", "sp1", "--format", "xml"); got.err == nil {
+			t.Error("expected an unsupported format to fail")
+		}
+	})
+
+	t.Run("transfer diff", func(t *testing.T) {
+		e := newE2E(t)
+		got := e.run("transfer", "diff", "--source-id", "sp1", "--dest-id", "yt1")
+		if got.err != nil {
+			t.Fatalf("diff failed: %v", got.err)
+		}
+		assertContains(t, got.stdout,
+			"✓ Source: Road Trip (4 tracks)",
+			"✓ Destination: Road Trip (YouTube) (2 tracks)",
+			"Matched: 1 tracks",
+			"Missing from destination: 3 tracks",
+			"Extra in destination: 1 tracks",
+			"  1. Sigur Rós - Hoppípolla (Takk...)",
+			"  1. Radiohead - Idioteque",
+		)
+
+		// An export of the source compares offline against the cached destination
+		if got := e.run("spotify", "export", "--id", "sp1", "--output", e.path("sp1.json")); got.err != nil {
+			t.Fatalf("export failed: %v", got.err)
+		}
+		got = e.run("transfer", "diff", "--source-file", e.path("sp1.json"), "--dest-id", "yt1")
+		if got.err != nil {
+			t.Fatalf("diff from a file failed: %v", got.err)
+		}
+		assertContains(t, got.stdout, "Matched: 1 tracks", "Missing from destination: 3 tracks")
+
+		if _, err := os.Stat(e.path("ytx.log")); err != nil {
+			t.Errorf("expected logs written to the configured file: %v", err)
+		}
+	})
+
+	t.Run("transfer diff exit codes", func(t *testing.T) {
+		e := newE2E(t)
+		// The cause of a failed export decides the exit code, and only a missing playlist exits as not found
+		for _, tt := range []struct {
+			name string
+			err  error
+			want int
+		}{
+			{"missing playlist", &services.SpotifyAPIError{StatusCode: 404, Message: "Resource not found"}, exitNotFound},
+			{"expired token", fmt.Errorf("%w: refresh the token", shared.ErrTokenExpired), exitTokenExpired},
+			{"no login", shared.ErrNotAuthenticated, exitNotAuthenticated},
+			{"timeout", context.DeadlineExceeded, exitTimeout},
+			{"API error", &services.SpotifyAPIError{StatusCode: 500, Message: "Server error"}, exitAPIRequest},
+			{"interrupted", context.Canceled, exitCancelled},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				e.spotify.FailExports(tt.err)
+				defer e.spotify.FailExports(nil)
+
+				got := e.run("transfer", "diff", "--source-id", "sp1", "--dest-id", "yt1")
+				if code := exitCodeFor(got.err); code != tt.want {
+					t.Errorf("exit code %d for %v, want %d", code, got.err, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		e := newE2E(t)
+		e.youtube.AddCatalog(models.Track{ID: "v3", Title: "Idioteque", Artists: []string{"Radiohead"}})
+		syncFile := e.path("ytx.yaml")
+		if err := os.WriteFile(syncFile, []byte("playlists:\n  - name: drive\n    source: Road Trip\n"), 0o644); err != nil {
+			t.Fatalf("failed to write sync file: %v", err)
+		}
+
+		got := e.run("apply", "--file", syncFile, "--dry-run")
+		if got.err != nil {
+			t.Fatalf("dry run failed: %v\n%s", got.err, got.stdout)
+		}
+		assertContains(t, got.stdout, `▶ drive: Spotify "Road Trip" → YouTube Music`, `+ Would create "Road Trip" with 4 tracks`)
+		if _, ok := e.youtube.Playlist("fake-1"); ok {
+			t.Fatal("expected nothing created in a dry run")
+		}
+
+		got = e.run("apply", "--file", syncFile)
+		if got.err != nil {
+			t.Fatalf("apply failed: %v\n%s", got.err, got.stdout)
+		}
+		assertContains(t, got.stdout, `+ Created "Road Trip" (fake-1): 2 of 4 tracks matched`)
+
+		// Applying again syncs new source tracks into the playlist it created
+		if err := e.spotify.AddTracks(t.Context(), "sp1", []models.Track{{ID: "s4", Title: "Idioteque", Artists: []string{"Radiohead"}}}); err != nil {
+			t.Fatalf("failed to add a source track: %v", err)
+		}
+		got = e.run("apply", "--file", syncFile, "--only", "drive")
+		if got.err != nil {
+			t.Fatalf("apply failed: %v\n%s", got.err, got.stdout)
+		}
+		assertContains(t, got.stdout, "Added 1 tracks to fake-1 (1 not found, 1 skipped)")
+		if _, ok := e.youtube.Playlist("fake-2"); ok {
+			t.Error("expected the existing playlist updated, not another created")
+		}
+		if synced, _ := e.youtube.Playlist("fake-1"); len(synced.Tracks) != 3 || synced.Tracks[2].ID != "v3" {
+			t.Errorf("expected the new track added to the destination, got %+v", synced.Tracks)
+		}
+
+		if got := e.run("apply", "--file", syncFile, "--only", "focus"); !errors.Is(got.err, shared.ErrInvalidArgument) {
+			t.Errorf("expected an unknown pair to be rejected, got %v", got.err)
+		}
+		if got := e.run("apply", "--file", e.path("missing.yaml")); got.err == nil {
+			t.Error("expected a missing sync file to fail")
+		}
+	})
+}
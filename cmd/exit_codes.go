@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// Process exit codes reported by ytx. Scripts can branch on these to tell failure causes apart.
+const (
+	exitOK               = 0
+	exitFailure          = 1 // Any error without a more specific code
+	exitUsage            = 2
+	exitNotAuthenticated = 3
+	exitTokenExpired     = 4
+	exitNotFound         = 5
+	exitTimeout          = 6
+	exitAPIRequest       = 7
+	exitConfig           = 8
+	exitCancelled        = 130
+)
+
+// exitCode maps a family of errors to the process exit code reported for them.
+type exitCode struct {
+	code        int
+	errs        []error
+	description string
+}
+
+// exitCodes are checked in order, so an error wrapping several causes reports the most specific one.
+var exitCodes = []exitCode{
+	{exitCancelled, []error{context.Canceled}, "Interrupted or cancelled"},
+	{exitTokenExpired, []error{shared.ErrTokenExpired, shared.ErrRefreshFailed, shared.ErrNoRefreshToken}, "Access token expired and could not be refreshed; re-run 'ytx spotify auth'"},
+	{exitNotAuthenticated, []error{shared.ErrNotAuthenticated, shared.ErrAuthFailed, shared.ErrMissingCredentials, shared.ErrInvalidCredentials}, "Not authenticated, or credentials are missing or invalid"},
+	{exitTimeout, []error{shared.ErrTimeout, context.DeadlineExceeded}, "Operation timed out"},
+	{exitNotFound, []error{shared.ErrPlaylistNotFound, shared.ErrTrackNotFound, shared.ErrRecordNotFound}, "Playlist, track, or record not found"},
+	{exitAPIRequest, []error{shared.ErrAPIRequest, shared.ErrServiceUnavailable}, "API request failed or service unavailable"},
+	{exitConfig, []error{shared.ErrMissingConfig, shared.ErrInvalidConfig, shared.ErrSecretStore}, "Configuration missing or invalid"},
+	{exitUsage, []error{shared.ErrInvalidInput, shared.ErrMissingArgument, shared.ErrInvalidArgument, shared.ErrInvalidFlag}, "Invalid arguments, flags, or input"},
+}
+
+// exitCodeFor returns the process exit code for an error returned by a command.
+func exitCodeFor(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	for _, ec := range exitCodes {
+		for _, target := range ec.errs {
+			if errors.Is(err, target) {
+				return ec.code
+			}
+		}
+	}
+
+	var exitErr cli.ExitCoder
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return exitFailure
+}
+
+// errorHint suggests how to resolve an error returned by a command, or returns an empty string when there is
+// nothing more to say than the error itself.
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, shared.ErrRateLimited):
+		var apiErr *services.SpotifyAPIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			return fmt.Sprintf("Spotify is rate limiting requests; try again in %s", apiErr.RetryAfter)
+		}
+		return "Requests are being rate limited; wait a moment and try again"
+	case errors.Is(err, shared.ErrForbidden):
+		return "Spotify refused the request: re-run 'ytx spotify auth' if a scope is missing, " +
+			"or check the playlist or track is available in your account's market"
+	default:
+		return ""
+	}
+}
+
+// exitCodesHelp renders the exit code table in ascending code order.
+func exitCodesHelp() string {
+	var b strings.Builder
+	b.WriteString("Exit codes:\n\n")
+	b.WriteString(fmt.Sprintf("  %3d  %s\n", exitOK, "Success"))
+	b.WriteString(fmt.Sprintf("  %3d  %s\n", exitFailure, "Any other error"))
+	for code := exitUsage; code <= exitCancelled; code++ {
+		for _, ec := range exitCodes {
+			if ec.code == code {
+				b.WriteString(fmt.Sprintf("  %3d  %s\n", ec.code, ec.description))
+			}
+		}
+	}
+	return b.String()
+}
+
+// exitCodesCommand is a help topic listing exit codes, shown by 'ytx help exit-codes'
+func exitCodesCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:               "exit-codes",
+		Usage:              "List process exit codes",
+		Hidden:             true,
+		CustomHelpTemplate: exitCodesHelp(),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return r.writePlain("%s", exitCodesHelp())
+		},
+	}
+}
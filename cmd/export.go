@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/formatter"
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+	"github.com/urfave/cli/v3"
+)
+
+// bulkExport is a bulk export requested with the flags of [bulkExportFlags]
+type bulkExport struct {
+	ids    []string // Playlists given with --ids; empty to select from the library
+	filter tasks.ExportFilter
+	opts   tasks.BulkExportOpts
+}
+
+// parseBulkExport reads a bulk export from the flags of [bulkExportFlags]. Cover images are left for the caller to
+// fetch, as only it knows the service.
+func parseBulkExport(cmd *cli.Command) (*bulkExport, error) {
+	req := &bulkExport{
+		filter: tasks.ExportFilter{
+			Match:     cmd.String("match"),
+			MinTracks: cmd.Int("min-tracks"),
+			MaxTracks: cmd.Int("max-tracks"),
+			Changed:   cmd.Bool("changed"),
+		},
+		opts: tasks.BulkExportOpts{
+			Format:     cmd.String("format"),
+			OutputDir:  cmd.String("output"),
+			NumWorkers: cmd.Int("workers"),
+			RateLimit:  cmd.Float64("rate-limit"),
+			Covers:     cmd.Bool("covers"),
+		},
+	}
+
+	if archive := cmd.String("archive"); archive != "" {
+		var err error
+		if req.opts.Archive, err = formatter.ParseArchiveFormat(archive); err != nil {
+			return nil, err
+		}
+	}
+	if err := req.filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	for id := range strings.SplitSeq(cmd.String("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			req.ids = append(req.ids, id)
+		}
+	}
+	if len(req.ids) > 0 && req.filter != (tasks.ExportFilter{}) {
+		return nil, fmt.Errorf("%w: --ids cannot be combined with --match, --min-tracks, --max-tracks, or --changed", shared.ErrInvalidArgument)
+	}
+	return req, nil
+}
+
+// runBulkExport exports the playlists of srv that req selects: those it lists by ID, or else those of the library,
+// as returned by list, that pass its filters. Progress is written as playlists finish, followed by a summary.
+func (r *Runner) runBulkExport(
+	ctx context.Context,
+	srv services.Service,
+	serviceKey string,
+	req *bulkExport,
+	list func(context.Context) ([]models.Playlist, error),
+) error {
+	if r.engine == nil {
+		return fmt.Errorf("%w: Playlist engine not initialized", shared.ErrServiceUnavailable)
+	}
+
+	// Exports are cached as the snapshots --changed compares with
+	store, closeCache, err := r.enableCaching(ctx)
+	if err != nil {
+		if req.filter.Changed {
+			return err
+		}
+		r.logger.Warnf("exports won't be cached for --changed: %v", err)
+	} else {
+		defer closeCache()
+	}
+
+	playlistIDs := req.ids
+	if len(playlistIDs) == 0 {
+		r.writePlain("→ Fetching playlist list...\n")
+		playlists, err := list(ctx)
+		if err != nil {
+			return err
+		}
+
+		if req.filter != (tasks.ExportFilter{}) {
+			selected, err := tasks.FilterPlaylists(ctx, store, serviceKey, playlists, req.filter)
+			if err != nil {
+				return err
+			}
+			r.writePlain("→ %d of %d playlists selected by filters\n", len(selected), len(playlists))
+			if len(selected) == 0 {
+				// Nothing having changed is the usual outcome of an incremental export, not a failure
+				r.writePlain("✓ Nothing to export\n")
+				return nil
+			}
+			playlists = selected
+		}
+
+		for _, pl := range playlists {
+			playlistIDs = append(playlistIDs, pl.ID)
+		}
+	}
+
+	if len(playlistIDs) == 0 {
+		return fmt.Errorf("no playlists to export")
+	}
+
+	r.writePlain("→ Preparing to export %d playlists in %s format\n\n", len(playlistIDs), req.opts.Format)
+
+	progress := make(chan tasks.ProgressUpdate, 100)
+	done := make(chan *tasks.BulkExportResult)
+	errs := make(chan error, 1)
+
+	go func() {
+		result, err := r.engine.BulkExport(ctx, progress, srv, playlistIDs, req.opts)
+		if err != nil {
+			errs <- err
+			return
+		}
+		done <- result
+	}()
+
+	for {
+		select {
+		case update := <-progress:
+			r.writePlain("%s\n", update.Message)
+		case result := <-done:
+			r.writePlain("\n")
+			r.writePlain("✓ Bulk export complete!\n")
+			r.writePlain("  Total playlists: %d\n", result.TotalPlaylists)
+			r.writePlain("  Successful: %d\n", result.SuccessfulExports)
+			r.writePlain("  Failed: %d\n", result.FailedExports)
+			r.writePlain("  Output directory: %s\n", result.OutputDirectory)
+			r.writePlain("  Manifest: %s\n", result.ManifestPath)
+			if result.ArchivePath != "" {
+				r.writePlain("  Archive: %s\n", result.ArchivePath)
+			}
+			r.writePlain("\n")
+
+			if result.FailedExports > 0 {
+				r.writePlain("Failed exports:\n")
+				for _, res := range result.Results {
+					if !res.Success {
+						r.writePlain("  ✗ %s: %v\n", res.PlaylistName, res.Error)
+					}
+				}
+			}
+
+			if result.SuccessfulExports == 0 {
+				return fmt.Errorf("all exports failed")
+			}
+
+			if result.FailedExports > 0 {
+				return fmt.Errorf("partial success: %d/%d exports failed", result.FailedExports, result.TotalPlaylists)
+			}
+
+			return nil
+		case err := <-errs:
+			return fmt.Errorf("bulk export failed: %w", err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
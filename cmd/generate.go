@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+	"github.com/urfave/cli/v3"
+)
+
+// Generate builds a playlist from listening history or liked tracks by a recipe and creates it, or with --dry-run
+// lists the tracks it would have.
+func (r *Runner) Generate(ctx context.Context, cmd *cli.Command) error {
+	if r.engine == nil {
+		return fmt.Errorf("%w: Playlist engine not initialized", shared.ErrServiceUnavailable)
+	}
+
+	recipe, err := tasks.ParseGenerateRecipe(cmd.Args().First())
+	if err != nil {
+		return err
+	}
+	months, limit := int(cmd.Int("months")), int(cmd.Int("limit"))
+	if months < 0 || limit < 0 {
+		return fmt.Errorf("%w: --months and --limit cannot be negative", shared.ErrInvalidArgument)
+	}
+	if limit == 0 {
+		limit = tasks.DefaultGenerateLimit
+	}
+
+	// History comes from YouTube Music, liked tracks from Spotify unless --from says otherwise
+	from := cmd.String("from")
+	if from == "" {
+		from = "spotify"
+		if recipe == tasks.GenerateTopPlayed {
+			from = "youtube"
+		}
+	}
+	to := cmd.String("to")
+	if to == "" {
+		to = from
+	}
+	source, err := r.resolveService(from)
+	if err != nil {
+		return err
+	}
+	dest, err := r.resolveService(to)
+	if err != nil {
+		return err
+	}
+
+	opts := tasks.GenerateOpts{
+		Recipe: recipe,
+		Source: source,
+		Dest:   dest,
+		Limit:  limit,
+		Name:   cmd.String("name"),
+		Public: cmd.Bool("public"),
+		DryRun: cmd.Bool("dry-run"),
+	}
+	if months > 0 {
+		opts.Since = time.Now().AddDate(0, -months, 0)
+	}
+	if opts.Name == "" {
+		opts.Name = generatedName(recipe, limit, months)
+	}
+
+	r.logger.Info("generate requested", "recipe", recipe, "from", from, "to", to, "months", months, "limit", limit, "dry_run", opts.DryRun)
+
+	if !opts.DryRun {
+		if _, closeCache, err := r.enableCaching(ctx); err != nil {
+			r.logger.Warnf("generated playlist won't be cached: %v", err)
+		} else {
+			defer closeCache()
+		}
+	}
+
+	progressCh, finishProgress, err := r.reportProgress(cmd, 10, func(update tasks.ProgressUpdate) {
+		r.writePlain("📥 %s\n", update.Message)
+	})
+	if err != nil {
+		return err
+	}
+	result, err := r.engine.Generate(ctx, opts, progressCh)
+	finishProgress()
+
+	if result != nil {
+		r.writePlain("\n")
+		header := fmt.Sprintf("%s (%d tracks)", opts.Name, len(result.Tracks))
+		if opts.DryRun {
+			header += " (dry run)"
+		}
+		r.writePlainHeader(header)
+		for i, track := range result.Tracks {
+			if result.Plays != nil {
+				r.writePlain("  %d. %s - %s (%d plays)\n", i+1, track.Artist(), track.Title, result.Plays[i])
+			} else {
+				r.writePlain("  %d. %s - %s\n", i+1, track.Artist(), track.Title)
+			}
+		}
+		if len(result.Tracks) == 0 {
+			r.writePlain("✓ No tracks to add; no playlist created\n")
+		}
+		if result.Playlist != nil {
+			r.writePlain("\n✓ Created %s on %s (ID: %s)\n", result.Playlist.Name, dest.Name(), result.Playlist.ID)
+		}
+		if result.Push != nil {
+			r.writePlain("Added: %d tracks (%d not found, %d skipped)\n", result.Push.AddedCount, result.Push.FailedCount, result.Push.SkippedCount)
+		}
+	}
+	return err
+}
+
+// generatedName names a generated playlist after its recipe, e.g. "Top 100 last 6 months"
+func generatedName(recipe string, limit, months int) string {
+	period := "all time"
+	if months == 1 {
+		period = "last month"
+	} else if months > 1 {
+		period = fmt.Sprintf("last %d months", months)
+	}
+
+	switch recipe {
+	case tasks.GenerateTopPlayed:
+		return fmt.Sprintf("Top %d %s", limit, period)
+	default:
+		return fmt.Sprintf("Unsorted likes (%s)", period)
+	}
+}
+
+// generateCommand builds playlists from listening data
+func generateCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:      "generate",
+		Usage:     "Create a playlist from listening history (top) or liked tracks in no playlist yet (unsorted-likes)",
+		ArgsUsage: "<top|unsorted-likes>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "Service the history or liked tracks come from (default: youtube for top, spotify for unsorted-likes)",
+			},
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "Service to create the playlist on, searching for each track when it differs from --from (default: --from)",
+			},
+			&cli.IntFlag{
+				Name:  "months",
+				Usage: "Only plays or likes from the last N months (0: all time)",
+				Value: 6,
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Most tracks in the playlist",
+				Value: tasks.DefaultGenerateLimit,
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Playlist name (default: from the recipe, e.g. \"Top 100 last 6 months\")",
+			},
+			&cli.BoolFlag{
+				Name:  "public",
+				Usage: "Make the playlist public",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "List the tracks without creating the playlist",
+			},
+			progressFlag(),
+		},
+		Action: r.Generate,
+	}
+}
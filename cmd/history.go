@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// historyStaleAfter is how long a running migration may go without a progress update before it is
+// considered abandoned (e.g. the transfer process was killed) and no longer watched.
+const historyStaleAfter = time.Minute
+
+// historyEntry is a migration job as reported by [Runner.History]
+type historyEntry struct {
+	ID              string     `json:"id"`
+	Sequence        int        `json:"sequence"`
+	Status          string     `json:"status"`
+	Active          bool       `json:"active"` // Running with a recent progress update
+	SourceService   string     `json:"source_service"`
+	SourcePlaylist  string     `json:"source_playlist"`
+	TargetService   string     `json:"target_service"`
+	ProgressCurrent int        `json:"progress_current"`
+	ProgressTotal   int        `json:"progress_total"`
+	ProgressPercent float64    `json:"progress_percent"`
+	TracksTotal     int        `json:"tracks_total"`
+	TracksMigrated  int        `json:"tracks_migrated"`
+	TracksFailed    int        `json:"tracks_failed"`
+	Error           string     `json:"error,omitempty"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	LastProgressAt  *time.Time `json:"last_progress_at,omitempty"`
+}
+
+// History lists recorded migrations with their progress.
+//
+// With --watch the list is refreshed every --interval until no migration is actively running.
+func (r *Runner) History(ctx context.Context, cmd *cli.Command) error {
+	limit := cmd.Int("limit")
+	if limit < 0 {
+		return fmt.Errorf("%w: --limit cannot be negative", shared.ErrInvalidFlag)
+	}
+
+	interval := cmd.Duration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("%w: --interval must be positive", shared.ErrInvalidFlag)
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrations := repositories.NewMigrationRepository(db)
+	playlists := repositories.NewPlaylistRepository(db)
+	criteria := map[string]any{"status": cmd.String("status")}
+
+	for {
+		entries, err := r.loadHistory(ctx, migrations, playlists, criteria, limit)
+		if err != nil {
+			return err
+		}
+
+		if cmd.Bool("json") {
+			if err := r.writeJSON(entries, true); err != nil {
+				return err
+			}
+		} else {
+			r.printHistory(entries)
+		}
+
+		if !cmd.Bool("watch") || !anyActive(entries) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// loadHistory lists migrations matching criteria, newest first, and resolves their source playlist names
+func (r *Runner) loadHistory(ctx context.Context, migrations *repositories.MigrationRepository, playlists *repositories.PlaylistRepository, criteria map[string]any, limit int) ([]historyEntry, error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+
+	jobs, err := migrations.List(ctx, criteria, models.ListOpts{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries := make([]historyEntry, 0, len(jobs))
+	for _, job := range jobs {
+		entry := historyEntry{
+			ID:              job.ID(),
+			Sequence:        job.Sequence(),
+			Status:          job.Status(),
+			SourceService:   job.SourceService(),
+			SourcePlaylist:  job.SourcePlaylistID(),
+			TargetService:   job.TargetService(),
+			ProgressCurrent: job.ProgressCurrent(),
+			ProgressTotal:   job.ProgressTotal(),
+			ProgressPercent: job.ProgressPercent(),
+			TracksTotal:     job.TracksTotal(),
+			TracksMigrated:  job.TracksMigrated(),
+			TracksFailed:    job.TracksFailed(),
+			Error:           job.ErrorMessage(),
+			StartedAt:       job.StartedAt(),
+			CompletedAt:     job.CompletedAt(),
+			LastProgressAt:  job.LastProgressAt(),
+		}
+		entry.Active = entry.Status == "in_progress" && entry.LastProgressAt != nil && now.Sub(*entry.LastProgressAt) < historyStaleAfter
+
+		if playlist, err := playlists.Get(ctx, job.SourcePlaylistID()); err == nil {
+			entry.SourcePlaylist = playlist.Name()
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// printHistory writes history entries as a table
+func (r *Runner) printHistory(entries []historyEntry) {
+	r.writePlainHeader(fmt.Sprintf("Migrations (%d)", len(entries)))
+	if len(entries) == 0 {
+		r.writePlainln("No migrations recorded yet. Run 'ytx transfer run --cache' to record transfers.")
+		return
+	}
+
+	r.writePlain("  %-6s %-12s %-16s %-10s %-20s %s\n", "#", "STATUS", "PROGRESS", "MATCHED", "STARTED", "SOURCE")
+	for _, entry := range entries {
+		status := entry.Status
+		if status == "in_progress" && !entry.Active {
+			status = "stalled"
+		}
+
+		started := "-"
+		if entry.StartedAt != nil {
+			started = entry.StartedAt.Local().Format("2006-01-02 15:04:05")
+		}
+
+		progress := fmt.Sprintf("%d/%d %5.1f%%", entry.ProgressCurrent, entry.ProgressTotal, entry.ProgressPercent)
+		matched := fmt.Sprintf("%d/%d", entry.TracksMigrated, entry.TracksTotal)
+		r.writePlain("  %-6d %-12s %-16s %-10s %-20s [%s] %s\n", entry.Sequence, status, progress, matched, started, entry.SourceService, entry.SourcePlaylist)
+		if entry.Error != "" {
+			r.writePlain("         error: %s\n", entry.Error)
+		}
+	}
+}
+
+// anyActive reports whether any history entry is still running
+func anyActive(entries []historyEntry) bool {
+	for _, entry := range entries {
+		if entry.Active {
+			return true
+		}
+	}
+	return false
+}
+
+// historyCommand lists recorded migrations with live progress
+func historyCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:  "history",
+		Usage: "List recorded migrations and their progress",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Usage:   "Maximum number of migrations to show (0 for all)",
+				Value:   20,
+			},
+			&cli.StringFlag{
+				Name:  "status",
+				Usage: "Only show migrations with this status (pending, in_progress, completed, failed)",
+			},
+			&cli.BoolFlag{
+				Name:    "watch",
+				Aliases: []string{"w"},
+				Usage:   "Refresh until no migration is running",
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "Refresh interval for --watch",
+				Value: 2 * time.Second,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output migrations as JSON",
+			},
+		},
+		Action: r.History,
+	}
+}
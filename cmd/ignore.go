@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// ignoreEntry is an ignore list entry as reported by [Runner.IgnoreList]
+type ignoreEntry struct {
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IgnoreAdd adds an ISRC or artist to the ignore list.
+func (r *Runner) IgnoreAdd(ctx context.Context, cmd *cli.Command) error {
+	kind, value, err := ignoreTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	entry := &models.IgnoreEntry{Kind: kind, Value: value, Reason: cmd.String("reason")}
+	queryCtx, cancel := r.queryContext(ctx)
+	defer cancel()
+	if err := repositories.NewIgnoreListRepository(db).Add(queryCtx, entry); err != nil {
+		return err
+	}
+
+	r.logger.Infof("ignoring %s %s", kind, entry.Value)
+	r.writePlainln("✓ Ignoring %s %q in transfers and diffs", kind, entry.Value)
+	return nil
+}
+
+// IgnoreRemove removes an ISRC or artist from the ignore list.
+func (r *Runner) IgnoreRemove(ctx context.Context, cmd *cli.Command) error {
+	kind, value, err := ignoreTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	queryCtx, cancel := r.queryContext(ctx)
+	defer cancel()
+	if err := repositories.NewIgnoreListRepository(db).Remove(queryCtx, kind, value); err != nil {
+		return err
+	}
+
+	r.logger.Infof("no longer ignoring %s %s", kind, value)
+	r.writePlainln("✓ No longer ignoring %s %q", kind, value)
+	return nil
+}
+
+// IgnoreList prints the ignore list.
+func (r *Runner) IgnoreList(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	queryCtx, cancel := r.queryContext(ctx)
+	list, err := repositories.NewIgnoreListRepository(db).List(queryCtx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]ignoreEntry, 0, len(list))
+	for _, entry := range list {
+		entries = append(entries, ignoreEntry{Kind: entry.Kind, Value: entry.Value, Reason: entry.Reason, CreatedAt: entry.CreatedAt})
+	}
+
+	if cmd.Bool("json") {
+		return r.writeJSON(entries, true)
+	}
+
+	if len(entries) == 0 {
+		r.writePlain("The ignore list is empty\n")
+		return nil
+	}
+
+	r.writePlainHeader(fmt.Sprintf("Ignore List (%d)", len(entries)))
+	for _, entry := range entries {
+		r.writePlain("  %-7s %s", entry.Kind, entry.Value)
+		if entry.Reason != "" {
+			r.writePlain(" (%s)", entry.Reason)
+		}
+		r.writePlain("\n")
+	}
+	return nil
+}
+
+// enableIgnoreList attaches the ignore list from the local database to the engine.
+//
+// Nothing is attached when the database has not been created yet, since it cannot list anything.
+// The returned function detaches the ignore list and closes the database.
+func (r *Runner) enableIgnoreList(ctx context.Context) (func(), error) {
+	if _, err := os.Stat(r.config.Database.Path); os.IsNotExist(err) {
+		return func() {}, nil
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.engine.SetIgnoreList(repositories.NewIgnoreListRepository(db))
+	return func() {
+		r.engine.SetIgnoreList(nil)
+		db.Close()
+	}, nil
+}
+
+// ignoreTarget returns the kind and value selected by exactly one of --isrc and --artist
+func ignoreTarget(cmd *cli.Command) (string, string, error) {
+	isrc, artist := cmd.String("isrc"), cmd.String("artist")
+	switch {
+	case isrc != "" && artist != "":
+		return "", "", fmt.Errorf("%w: --isrc and --artist cannot be combined", shared.ErrInvalidFlag)
+	case isrc != "":
+		return models.IgnoreKindISRC, isrc, nil
+	case artist != "":
+		return models.IgnoreKindArtist, artist, nil
+	default:
+		return "", "", fmt.Errorf("%w: --isrc or --artist", shared.ErrMissingArgument)
+	}
+}
+
+// ignoreCommand manages the ISRCs and artists skipped by transfers and diffs
+func ignoreCommand(r *Runner) *cli.Command {
+	targetFlags := func() []cli.Flag {
+		return []cli.Flag{
+			&cli.StringFlag{
+				Name:  "isrc",
+				Usage: "ISRC of the track",
+			},
+			&cli.StringFlag{
+				Name:  "artist",
+				Usage: "Artist name (case-insensitive)",
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to configuration file",
+				Value:   "config.toml",
+			},
+		}
+	}
+
+	return &cli.Command{
+		Name:  "ignore",
+		Usage: "Manage tracks and artists skipped by transfers and diffs",
+		Commands: []*cli.Command{
+			{
+				Name:  "add",
+				Usage: "Skip an ISRC or artist in transfers and diffs",
+				Flags: append(targetFlags(), &cli.StringFlag{
+					Name:  "reason",
+					Usage: "Why the track or artist is ignored (e.g. podcast, local file)",
+				}),
+				Action: r.IgnoreAdd,
+			},
+			{
+				Name:    "remove",
+				Aliases: []string{"rm"},
+				Usage:   "Stop skipping an ISRC or artist",
+				Flags:   targetFlags(),
+				Action:  r.IgnoreRemove,
+			},
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Usage:   "List ignored ISRCs and artists",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output as JSON",
+					},
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to configuration file",
+						Value:   "config.toml",
+					},
+				},
+				Action: r.IgnoreList,
+			},
+		},
+	}
+}
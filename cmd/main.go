@@ -4,109 +4,65 @@ import (
 	"context"
 	"errors"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
-	"github.com/desertthunder/ytx/internal/tasks"
 	"github.com/urfave/cli/v3"
-	"golang.org/x/oauth2"
 )
 
 func main() {
-	var spot services.Service
-	var yt services.Service
-
 	logger := shared.NewLogger(nil)
-	config := shared.DefaultConfig()
-	configPath := "config.toml"
-
-	if _, err := os.Stat(configPath); err == nil {
-		if loadedConfig, err := shared.LoadConfig(configPath); err == nil {
-			config = loadedConfig
-		}
-	}
-
-	if config.Credentials.Spotify.ClientID != "" && config.Credentials.Spotify.ClientSecret != "" {
-		creds := config.Credentials.Spotify.Map()
-		if svc, err := services.NewSpotifyService(creds); err == nil {
-			spot = svc
-
-			if config.Credentials.Spotify.AccessToken != "" {
-				ctx := context.Background()
-				creds["access_token"] = config.Credentials.Spotify.AccessToken
-				creds["refresh_token"] = config.Credentials.Spotify.RefreshToken
-				if err := svc.Authenticate(ctx, creds); err != nil {
-					logger.Warnf("failed to authenticate with stored token %v", err)
-				} else {
-					logger.Debug("authenticated with stored access token")
-				}
-			}
-		}
-	}
 
 	rconf := RunnerOpts{
-		Config:     config,
-		ConfigPath: configPath,
-		Spotify:    spot,
-		YouTube:    nil,
-		API:        nil,
+		Config:     shared.DefaultConfig(),
+		ConfigPath: "config.toml",
 		Logger:     logger,
 	}
 	runner := NewRunner(rconf)
 
-	if spotifyService, ok := spot.(*services.SpotifyService); ok && spot != nil {
-		spotifyService.SetTokenRefreshCallback(func(token *oauth2.Token) {
-			logger.Info("token refreshed, saving to config")
-			if err := runner.saveTokens(token); err != nil {
-				logger.Warnf("failed to save refreshed tokens: %v", err)
-			}
-		})
-	}
+	app := newApp(runner)
 
-	yt = services.NewYouTubeService(config.Credentials.YouTube.ProxyURL)
+	// The first interrupt cancels the running command, which stops at the next track or request; a second one
+	// kills the process as usual
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
-	if config.Credentials.YouTube.HeadersPath != "" {
-		ctx := context.Background()
-		headersPath := config.Credentials.YouTube.HeadersPath
-
-		if absPath, err := shared.AbsolutePath(headersPath); err == nil {
-			headersPath = absPath
-		}
-
-		logger.Debugf("authenticating YouTube service with header path %v", headersPath)
-		if err := yt.Authenticate(ctx, map[string]string{"auth_file": headersPath}); err != nil {
-			logger.Errorf("failed to authenticate YouTube service %v", err)
-		} else {
-			logger.Debug("authenticated YouTube service successfully")
+	if err := app.Run(ctx, os.Args); err != nil {
+		if errors.Is(err, shared.ErrNotImplemented) {
+			logger.Warn("not implemented")
+			os.Exit(exitOK)
 		}
-	}
-
-	api := services.NewAPIService(config.Credentials.YouTube.ProxyURL, nil)
-	if config.Credentials.YouTube.HeadersPath != "" {
-		if absPath, err := shared.AbsolutePath(config.Credentials.YouTube.HeadersPath); err == nil {
-			api.SetAuthFile(absPath)
-			logger.Debugf("configured API service with auth file header path %v", absPath)
+		logger.Errorf("application error: %v", err)
+		if hint := errorHint(err); hint != "" {
+			logger.Info(hint)
 		}
+		os.Exit(exitCodeFor(err))
 	}
+}
 
-	runner.youtube = yt
-	runner.api = api
-	runner.engine = tasks.NewPlaylistEngine(spot, yt, api)
-
+// newApp returns the ytx command tree, running its commands with runner
+func newApp(runner *Runner) *cli.Command {
 	app := &cli.Command{
-		Name:     "ytx",
-		Usage:    "Transfer playlists between Spotify & YouTube Music",
-		Version:  "0.2.0",
+		Name:        "ytx",
+		Usage:       "Transfer playlists between Spotify & YouTube Music",
+		Description: "Failures exit with a code describing their cause; run 'ytx help exit-codes' to list them.",
+		Version:     "0.2.0",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "profile",
+				Usage:   "Named profile from [profiles] in config.toml to use for credentials, tokens, and database",
+				Sources: cli.EnvVars("YTX_PROFILE"),
+			},
+		}, configFlags()...),
+		Writer:   runner.output,
+		Before:   runner.configure,
+		After:    runner.shutdown,
 		Commands: runner.register(),
 	}
-
-	if err := app.Run(context.Background(), os.Args); err != nil {
-		err_ := errors.Unwrap(err)
-		if errors.Is(err_, shared.ErrNotImplemented) {
-			logger.Warn("not implemented")
-			os.Exit(0)
-		} else {
-			logger.Fatalf("application error: %v", err)
-		}
-	}
+	runner.configureCompletion(app)
+	return app
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+	"github.com/urfave/cli/v3"
+)
+
+// progressEvent is the line written to the error output for each progress update with --progress json.
+type progressEvent struct {
+	Phase       tasks.Phase `json:"phase"` // Stable code, e.g. "search_tracks"
+	Description string      `json:"description"`
+	Step        int         `json:"step"`
+	Total       int         `json:"total"`
+	Message     string      `json:"message"`
+	Time        time.Time   `json:"time"`
+}
+
+// progressFlag returns the --progress flag shared by transfer, diff, and dump commands
+func progressFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "progress",
+		Usage: "Progress output: text, or json for one JSON object per update on stderr",
+		Value: "text",
+	}
+}
+
+// reportProgress returns a buffered progress channel whose updates are printed in the background,
+// with text by default or as JSON lines on the error output with --progress json.
+//
+// The returned function closes the channel and waits until every update has been written.
+func (r *Runner) reportProgress(cmd *cli.Command, size int, text func(tasks.ProgressUpdate)) (chan tasks.ProgressUpdate, func(), error) {
+	write := text
+	switch mode := cmd.String("progress"); mode {
+	case "", "text":
+	case "json":
+		encoder := json.NewEncoder(r.errOutput)
+		write = func(update tasks.ProgressUpdate) {
+			if err := encoder.Encode(progressEvent{
+				Phase:       update.Phase,
+				Description: update.Phase.Description(),
+				Step:        update.Step,
+				Total:       update.Total,
+				Message:     update.Message,
+				Time:        update.Time,
+			}); err != nil {
+				r.logger.Warn("failed to write progress event", "error", err)
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: invalid progress output '%s' (must be 'text' or 'json')", shared.ErrInvalidArgument, mode)
+	}
+
+	progress := make(chan tasks.ProgressUpdate, size)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for update := range progress {
+			write(update)
+		}
+	}()
+
+	return progress, func() {
+		close(progress)
+		<-done
+	}, nil
+}
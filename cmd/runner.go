@@ -1,31 +1,50 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/server"
 	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
 	"github.com/desertthunder/ytx/internal/tasks"
+	"github.com/desertthunder/ytx/internal/tracing"
 	"github.com/urfave/cli/v3"
 	"golang.org/x/oauth2"
 )
 
+// tokenRefreshWindow is how close to expiry a stored Spotify token is refreshed at startup.
+const tokenRefreshWindow = 5 * time.Minute
+
 // Runner holds all dependencies for CLI commands and provides methods for each command action.
 type Runner struct {
 	config     *shared.Config
+	fileConfig *shared.Config // config.toml as loaded, before a profile is applied
 	configPath string
+	profile    string
 	spotify    services.Service
 	youtube    services.Service
 	api        *services.APIService
 	httpClient *http.Client
 	logger     *log.Logger
+	logging    *shared.Logging // Module loggers built from the logging config; nil until configured
 	output     io.Writer
+	errOutput  io.Writer // Machine-readable progress events
 	engine     *tasks.PlaylistEngine
+	given      bool            // Services were given to NewRunner, so configure keeps them
+	metrics    *server.Metrics // Nil unless server.metrics_addr is set
+	tracer     *tracing.Tracer // Nil unless tracing.endpoint is set
 }
 
 // RunnerOpts contains configuration options for creating a Runner.
@@ -38,6 +57,7 @@ type RunnerOpts struct {
 	HTTPClient *http.Client
 	Logger     *log.Logger
 	Output     io.Writer
+	ErrOutput  io.Writer
 }
 
 // NewRunner creates a new Runner with the provided configuration
@@ -51,6 +71,9 @@ func NewRunner(opts RunnerOpts) *Runner {
 	if opts.Output == nil {
 		opts.Output = os.Stdout
 	}
+	if opts.ErrOutput == nil {
+		opts.ErrOutput = os.Stderr
+	}
 	if opts.HTTPClient == nil {
 		opts.HTTPClient = http.DefaultClient
 	}
@@ -59,6 +82,7 @@ func NewRunner(opts RunnerOpts) *Runner {
 
 	return &Runner{
 		config:     opts.Config,
+		fileConfig: opts.Config,
 		configPath: opts.ConfigPath,
 		spotify:    opts.Spotify,
 		youtube:    opts.YouTube,
@@ -66,14 +90,292 @@ func NewRunner(opts RunnerOpts) *Runner {
 		httpClient: opts.HTTPClient,
 		logger:     opts.Logger,
 		output:     opts.Output,
+		errOutput:  opts.ErrOutput,
 		engine:     engine,
+		given:      opts.Spotify != nil || opts.YouTube != nil,
+	}
+}
+
+// configure loads config.toml, applies the --profile selected on the root command, and initializes the
+// services with the resulting credentials.
+//
+// Missing or unreadable config files fall back to the runner's current configuration, except when the
+// tokens cannot be read from the configured secrets store.
+func (r *Runner) configure(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	if _, err := os.Stat(r.configPath); err == nil {
+		loadedConfig, err := shared.LoadConfig(r.configPath)
+		switch {
+		case err == nil:
+			r.fileConfig = loadedConfig
+		case errors.Is(err, shared.ErrSecretStore), errors.Is(err, shared.ErrInvalidCredentials):
+			// Falling back to defaults here would let a token refresh overwrite config.toml
+			return ctx, err
+		default:
+			r.logger.Warnf("failed to load config, using defaults %v", err)
+		}
+	}
+
+	config, err := r.resolveConfig(cmd, r.fileConfig)
+	if err != nil {
+		return ctx, err
+	}
+	r.config = config
+	r.profile = cmd.String("profile")
+
+	logging, err := shared.NewLogging(config.Logging, nil)
+	if err != nil {
+		return ctx, err
+	}
+	r.logging = logging
+	r.logger = logging.Logger("cmd")
+	if r.profile != "" {
+		r.logger.Debugf("using profile %s", r.profile)
+	}
+
+	// The config commands report problems themselves and need none of the services set up below
+	if cmd.Args().First() == configCommandName {
+		return ctx, nil
+	}
+	var configErr *shared.ConfigError
+	if errors.As(config.Validate(), &configErr) {
+		for _, problem := range configErr.Problems {
+			r.logger.Warnf("config: %v", problem)
+		}
+	}
+
+	if config.Server.MetricsAddr != "" && r.metrics == nil {
+		r.serveMetrics(config.Server.MetricsAddr)
+	}
+	if config.Tracing.Endpoint != "" && r.tracer == nil {
+		r.startTracing(config.Tracing)
+	}
+
+	// Services given to NewRunner, such as fakes in end-to-end tests, are used as they are
+	if !r.given {
+		r.spotify, r.youtube, r.api = r.newServices(ctx, config, r.profile)
+	}
+	r.engine = tasks.NewPlaylistEngine(r.spotify, r.youtube, r.api)
+	r.engine.SetLogger(logging.Logger("tasks"))
+	r.engine.SetPlaylistTemplates(config.Transfer.NameTemplate, config.Transfer.DescriptionTemplate)
+	r.engine.SetMaxDurationDelta(config.Transfer.DurationDelta())
+	explicit, err := tasks.ParseExplicitPreference(config.Transfer.Explicit)
+	if err != nil {
+		return ctx, fmt.Errorf("[transfer] explicit: %w", err)
+	}
+	r.engine.SetExplicitPreference(explicit)
+	r.engine.SetFlagUnavailable(config.Transfer.FlagUnavailable)
+	if config.Notifications.Enabled() {
+		r.engine.SetNotifier(loggingNotifier{tasks.NewWebhookNotifier(config.Notifications, nil), r})
+	}
+	if r.metrics != nil {
+		r.engine.SetMetricsRecorder(r.metrics)
+	}
+	return ctx, nil
+}
+
+// newServices creates the Spotify, YouTube Music, and proxy API services from config, authenticated with its
+// credentials. Spotify is nil without a client ID.
+//
+// config is resolved for profile, whose response cache the services use and to which refreshed tokens are saved;
+// it need not be the active profile, e.g. for the destination account of a transfer.
+func (r *Runner) newServices(ctx context.Context, config *shared.Config, profile string) (services.Service, services.Service, *services.APIService) {
+	var spotify services.Service
+	if config.Credentials.Spotify.ClientID != "" {
+		creds := config.Credentials.Spotify.Map()
+		if svc, err := services.NewSpotifyService(creds); err == nil {
+			spotify = svc
+			svc.SetHTTPClient(r.serviceClient("spotify"))
+			svc.SetResponseCache(r.responseCache(profile))
+
+			// Set before authenticating so the client reports refreshes made during requests
+			svc.SetTokenRefreshCallback(func(token *oauth2.Token) {
+				r.logger.Info("token refreshed, saving to config")
+				if err := r.saveProfileTokens(profile, token); err != nil {
+					r.logger.Warnf("failed to save refreshed tokens: %v", err)
+				}
+			})
+
+			if config.Credentials.Spotify.AccessToken != "" {
+				if err := svc.OAuthenticate(ctx, config.Credentials.Spotify.Token()); err != nil {
+					r.logger.Warnf("failed to authenticate with stored token %v", err)
+				} else {
+					r.logger.Debug("authenticated with stored access token")
+					r.refreshExpiringToken(ctx, svc, config.Credentials.Spotify)
+				}
+			}
+		} else {
+			r.logger.Warnf("failed to create Spotify service %v", err)
+		}
+	}
+
+	yt := services.NewYouTubeService(config.Credentials.YouTube.ProxyURL)
+	ytClient := r.serviceClient("youtube")
+	yt.SetHTTPClient(ytClient)
+	api := services.NewAPIService(config.Credentials.YouTube.ProxyURL, ytClient)
+	if config.Credentials.YouTube.HeadersPath != "" {
+		headersPath := config.Credentials.YouTube.HeadersPath
+		if absPath, err := shared.AbsolutePath(headersPath); err == nil {
+			headersPath = absPath
+		}
+
+		r.logger.Debugf("authenticating YouTube service with header path %v", headersPath)
+		if err := yt.Authenticate(ctx, map[string]string{"auth_file": headersPath}); err != nil {
+			r.logger.Errorf("failed to authenticate YouTube service %v", err)
+		} else {
+			r.logger.Debug("authenticated YouTube service successfully")
+		}
+
+		api.SetAuthFile(headersPath)
+		r.logger.Debugf("configured API service with auth file header path %v", headersPath)
+	}
+
+	return spotify, yt, api
+}
+
+// serveMetrics starts serving Prometheus metrics on addr in the background for the rest of the process.
+//
+// A server that fails to start is logged and leaves metrics disabled.
+func (r *Runner) serveMetrics(addr string) {
+	logger := r.logging.Logger("server")
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Warnf("failed to start metrics server: %v", err)
+		return
+	}
+
+	r.metrics = server.NewMetrics()
+	router := server.NewBasicRouter()
+	router.Handler(r.metrics)
+	srv := server.NewServer(router)
+	srv.Start(listener)
+	go func() {
+		logger.Warnf("metrics server stopped: %v", <-srv.Errors())
+	}()
+	logger.Debugf("serving metrics on http://%s/metrics", listener.Addr())
+}
+
+// serviceClient returns the HTTP client for service's requests, logged by the "services" module logger,
+// counted by the metrics registry when enabled, and traced when a tracer is installed.
+func (r *Runner) serviceClient(service string) *http.Client {
+	transport := services.LoggingTransport(r.logging.Logger("services"), service, nil)
+	if r.metrics != nil {
+		transport = r.metrics.InstrumentTransport(service, transport)
+	}
+	return &http.Client{Transport: tracing.Transport(transport)}
+}
+
+// responseCache returns the cache for Spotify GET responses: on disk under cache.http_dir, with a subdirectory
+// per profile so accounts never share responses, or in memory for this run.
+func (r *Runner) responseCache(profile string) services.ResponseCache {
+	dir := r.config.Cache.HTTPDir
+	if dir == "" {
+		return services.NewMemoryResponseCache()
+	}
+	dir = shared.ExpandPath(dir)
+	if profile != "" {
+		dir = filepath.Join(dir, "profiles", profile)
+	}
+	return services.NewDiskResponseCache(dir)
+}
+
+// startTracing installs a tracer exporting spans to the configured OTLP collector.
+//
+// The exporter uses its own untraced client so exports don't produce spans of their own.
+func (r *Runner) startTracing(config shared.TracingConfig) {
+	exporter := tracing.NewOTLPExporter(config.Endpoint, config.Service(), config.Headers, &http.Client{Timeout: 10 * time.Second})
+	r.tracer = tracing.NewTracer(exporter)
+	r.tracer.OnError(func(err error) {
+		r.logger.Warnf("failed to export trace spans: %v", err)
+	})
+	tracing.SetTracer(r.tracer)
+	r.logger.Debugf("exporting trace spans to %s", exporter.URL())
+}
+
+// shutdown flushes buffered trace spans once a command finishes. Export failures are logged, never returned.
+func (r *Runner) shutdown(ctx context.Context, cmd *cli.Command) error {
+	if r.tracer == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := r.tracer.Shutdown(ctx); err != nil {
+		r.logger.Warnf("failed to export trace spans: %v", err)
+	}
+	return nil
+}
+
+// loggingNotifier logs failed notifications, which the engine otherwise ignores.
+type loggingNotifier struct {
+	tasks.Notifier
+	r *Runner
+}
+
+func (n loggingNotifier) NotifyTransfer(ctx context.Context, summary tasks.TransferSummary) error {
+	err := n.Notifier.NotifyTransfer(ctx, summary)
+	if err != nil {
+		n.r.logger.Warnf("failed to send transfer notification: %v", err)
+	}
+	return err
+}
+
+// configFlags returns the global flags that override config values, each also read from its environment variable.
+func configFlags() []cli.Flag {
+	flags := make([]cli.Flag, len(shared.ConfigOverrides))
+	for i, o := range shared.ConfigOverrides {
+		flags[i] = &cli.StringFlag{
+			Name:     o.Flag,
+			Usage:    fmt.Sprintf("%s (overrides %s)", o.Usage, o.Key),
+			Category: "config overrides",
+			Sources:  cli.EnvVars(o.Env),
+		}
+	}
+	return flags
+}
+
+// resolveConfig applies the selected profile and any config override flags or environment variables to base.
+//
+// Precedence, highest first: flags, environment variables, the profile, config.toml, built-in defaults.
+// base is never modified, so overrides are not written back when the config is saved.
+func (r *Runner) resolveConfig(cmd *cli.Command, base *shared.Config) (*shared.Config, error) {
+	config, err := base.WithProfile(cmd.String("profile"))
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *config
+	for _, o := range shared.ConfigOverrides {
+		if cmd.IsSet(o.Flag) {
+			if err := o.Apply(&resolved, cmd.String(o.Flag)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &resolved, nil
+}
+
+// refreshExpiringToken refreshes the stored Spotify token when it expires within [tokenRefreshWindow], so
+// commands don't discover an expired token mid-transfer.
+//
+// A failed refresh is only logged: the first request then falls back to the reauthorization flow.
+func (r *Runner) refreshExpiringToken(ctx context.Context, svc *services.SpotifyService, creds shared.SpotifyConfig) {
+	if creds.RefreshToken == "" || !creds.ExpiresWithin(tokenRefreshWindow) {
+		return
+	}
+
+	r.logger.Debug("spotify token expires soon, refreshing", "expiry", creds.TokenExpiry)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := svc.RefreshToken(ctx); err != nil {
+		r.logger.Warnf("failed to refresh spotify token, reauthorization will be required: %v", err)
 	}
 }
 
 func (r *Runner) register() []*cli.Command {
 	commands := []*cli.Command{}
 	for _, fn := range [](func(*Runner) *cli.Command){
-		setupCommand, authCommand, spotifyCommand, apiCommand, ytmusicCommand, transferCommand, cacheCommand, tuiCommand,
+		setupCommand, authCommand, spotifyCommand, apiCommand, ytmusicCommand, transferCommand, diffCommand, cacheCommand, ignoreCommand, configCommand, dbCommand, backupCommand, searchCommand, generateCommand, verifyCommand, applyCommand, statsCommand, historyCommand, tuiCommand, serveCommand, exitCodesCommand,
 	} {
 		commands = append(commands, fn(r))
 	}
@@ -128,9 +430,84 @@ func (r *Runner) writePlainHeader(title string) {
 	r.writePlain("═══════════════════════════════════════\n")
 }
 
+// connectDatabase opens the configured SQLite database without touching its schema.
+func (r *Runner) connectDatabase(ctx context.Context) (*sql.DB, error) {
+	db, err := shared.OpenDatabase(r.config.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to reach database: %w", err)
+	}
+
+	return db, nil
+}
+
+// openDatabase opens the configured SQLite database and applies pending migrations.
+func (r *Runner) openDatabase(ctx context.Context) (*sql.DB, error) {
+	db, err := r.connectDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := shared.RunMigrations(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return db, nil
+}
+
+// queryContext bounds ctx by the configured database timeout for one step's repository calls. Commands take it
+// around their queries only, so fetching from a service or writing output is never cut short by it.
+func (r *Runner) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, r.config.Database.Timeout())
+}
+
+// enableCaching attaches track, playlist, and search caches and the migration history backed by the local database to the engine.
+//
+// Returns the playlist cache so callers can also read cached playlists. The returned function
+// detaches the cachers and closes the database.
+func (r *Runner) enableCaching(ctx context.Context) (*repositories.PlaylistCacheAdapter, func(), error) {
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := repositories.EnsureLocalUser(ctx, repositories.NewUserRepository(db))
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to resolve local user: %w", err)
+	}
+
+	tracks := repositories.NewTrackRepository(db)
+	playlists := repositories.NewPlaylistRepository(db)
+	playlistCache := repositories.NewPlaylistCacheAdapter(
+		user.ID(),
+		playlists,
+		tracks,
+		repositories.NewPlaylistTrackRepository(db),
+	)
+	r.engine.SetTrackCacher(repositories.NewTrackCacheAdapter(tracks))
+	r.engine.SetPlaylistCacher(playlistCache)
+	r.engine.SetSearchCache(repositories.NewSearchCacheRepository(db, r.config.Cache.SearchCacheTTL()))
+	r.engine.SetMigrationRecorder(repositories.NewMigrationHistoryAdapter(user.ID(), playlists, repositories.NewMigrationRepository(db)))
+	r.logger.Debug("caching enabled", "path", r.config.Database.Path)
+
+	return playlistCache, func() {
+		r.engine.SetTrackCacher(nil)
+		r.engine.SetPlaylistCacher(nil)
+		r.engine.SetSearchCache(nil)
+		r.engine.SetMigrationRecorder(nil)
+		db.Close()
+	}, nil
+}
+
 // SetLogger replaces the runner's logger with a new instance.
 //
-// This is useful for redirecting logs to a file when running the TUI.
+// Module loggers from the logging config are unaffected; the TUI redirects them with [shared.Logging.SetFile].
 func (r *Runner) SetLogger(logger *log.Logger) {
 	r.logger = logger
 }
@@ -141,16 +518,60 @@ func (r *Runner) saveTokens(token *oauth2.Token) error {
 		return fmt.Errorf("config is nil")
 	}
 
-	if err := r.config.Credentials.Spotify.Update(token); err != nil {
-		return fmt.Errorf("failed to update spotify configuration: %w", err)
+	if err := r.storeSpotifyToken(r.configPath, r.config, token); err != nil {
+		return err
 	}
 
 	if r.configPath != "" {
-		if err := shared.SaveConfig(r.configPath, r.config); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
-		}
 		r.logger.Debugf("saved refreshed tokens to %s", r.configPath)
 	}
 
 	return nil
 }
+
+// saveProfileTokens persists refreshed tokens for the Spotify account of profile, which need not be the active one
+func (r *Runner) saveProfileTokens(profile string, token *oauth2.Token) error {
+	if profile == r.profile {
+		return r.saveTokens(token)
+	}
+	if r.fileConfig == nil {
+		return fmt.Errorf("config is nil")
+	}
+
+	if err := r.fileConfig.SetSpotifyToken(profile, token); err != nil {
+		return fmt.Errorf("failed to update spotify configuration: %w", err)
+	}
+	if r.configPath == "" {
+		return nil
+	}
+	if err := shared.SaveConfig(r.configPath, r.fileConfig); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	r.logger.Debugf("saved refreshed tokens for profile %s to %s", profile, r.configPath)
+	return nil
+}
+
+// storeSpotifyToken updates config with new tokens and saves it to path.
+//
+// When config is the runner's profile-resolved config, the tokens are written to the active profile in
+// the file config instead, so other profiles and the top-level credentials are left untouched.
+func (r *Runner) storeSpotifyToken(path string, config *shared.Config, token *oauth2.Token) error {
+	if err := config.Credentials.Spotify.Update(token); err != nil {
+		return fmt.Errorf("failed to update spotify configuration: %w", err)
+	}
+
+	if config == r.config && r.fileConfig != nil && r.fileConfig != config {
+		if err := r.fileConfig.SetSpotifyToken(r.profile, token); err != nil {
+			return fmt.Errorf("failed to update spotify configuration: %w", err)
+		}
+		config = r.fileConfig
+	}
+
+	if path == "" {
+		return nil
+	}
+	if err := shared.SaveConfig(path, config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
@@ -2,15 +2,25 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
 	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
 	tu "github.com/desertthunder/ytx/internal/testing"
+	"github.com/urfave/cli/v3"
 	"golang.org/x/oauth2"
 )
 
@@ -253,6 +263,67 @@ func TestRunner(t *testing.T) {
 		})
 	})
 
+	t.Run("reportProgress", func(t *testing.T) {
+		update := tasks.ProgressUpdate{Phase: tasks.SearchTracks, Step: 2, Total: 5, Message: "[2/5] Artist - Song", Time: time.Now()}
+		run := func(t *testing.T, args ...string) (string, string, error) {
+			output, errOutput := &bytes.Buffer{}, &bytes.Buffer{}
+			runner := NewRunner(RunnerOpts{Output: output, ErrOutput: errOutput})
+			cmd := &cli.Command{
+				Name:  "test",
+				Flags: []cli.Flag{progressFlag()},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					progress, finish, err := runner.reportProgress(cmd, 1, func(update tasks.ProgressUpdate) {
+						runner.writePlain("%s\n", update.Message)
+					})
+					if err != nil {
+						return err
+					}
+					progress <- update
+					finish()
+					return nil
+				},
+			}
+			err := cmd.Run(t.Context(), append([]string{"test"}, args...))
+			return output.String(), errOutput.String(), err
+		}
+
+		t.Run("writes text to output by default", func(t *testing.T) {
+			output, errOutput, err := run(t)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if output != update.Message+"\n" || errOutput != "" {
+				t.Errorf("expected text progress on output only, got %q and %q", output, errOutput)
+			}
+		})
+
+		t.Run("writes JSON lines to error output", func(t *testing.T) {
+			output, errOutput, err := run(t, "--progress", "json")
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if output != "" {
+				t.Errorf("expected no text progress, got %q", output)
+			}
+
+			var event map[string]any
+			if err := json.Unmarshal([]byte(errOutput), &event); err != nil {
+				t.Fatalf("expected a JSON line, got %q: %v", errOutput, err)
+			}
+			if event["phase"] != "search_tracks" || event["step"] != float64(2) || event["total"] != float64(5) || event["message"] != update.Message ||
+				event["description"] != "Search tracks" {
+				t.Errorf("unexpected progress event %v", event)
+			}
+		})
+
+		t.Run("rejects unknown modes", func(t *testing.T) {
+			_, _, err := run(t, "--progress", "xml")
+			if !errors.Is(err, shared.ErrInvalidArgument) {
+				t.Errorf("expected ErrInvalidArgument, got %v", err)
+			}
+		})
+	})
+
 	t.Run("register", func(t *testing.T) {
 		runner := NewRunner(RunnerOpts{})
 		commands := runner.register()
@@ -268,6 +339,363 @@ func TestRunner(t *testing.T) {
 		}
 	})
 
+	t.Run("db commands", func(t *testing.T) {
+		config := shared.DefaultConfig()
+		config.Database.Path = filepath.Join(t.TempDir(), "ytx.db")
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Config: config, Output: output})
+
+		if err := dbCommand(runner).Run(t.Context(), []string{"db", "migrate"}); err != nil {
+			t.Fatalf("db migrate failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "Migrated database from version -1") {
+			t.Errorf("expected migrate summary, got %q", output.String())
+		}
+
+		output.Reset()
+		if err := dbCommand(runner).Run(t.Context(), []string{"db", "rollback", "--steps", "2"}); err != nil {
+			t.Fatalf("db rollback failed: %v", err)
+		}
+		if got := strings.Count(output.String(), "Rolled back migration"); got != 2 {
+			t.Errorf("expected 2 rollbacks, got %d", got)
+		}
+
+		output.Reset()
+		if err := dbCommand(runner).Run(t.Context(), []string{"db", "status"}); err != nil {
+			t.Fatalf("db status failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "2 pending") {
+			t.Errorf("expected 2 pending migrations, got %q", output.String())
+		}
+
+		for _, sub := range []string{"stats", "check", "vacuum"} {
+			output.Reset()
+			if err := dbCommand(runner).Run(t.Context(), []string{"db", sub}); err != nil {
+				t.Fatalf("db %s failed: %v", sub, err)
+			}
+		}
+		if !strings.Contains(output.String(), "Vacuumed") {
+			t.Errorf("expected vacuum summary, got %q", output.String())
+		}
+
+		output.Reset()
+		if err := dbCommand(runner).Run(t.Context(), []string{"db", "purge", "--older-than", "0s"}); err != nil {
+			t.Fatalf("db purge failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "Purged 0 soft-deleted rows") {
+			t.Errorf("expected purge summary, got %q", output.String())
+		}
+		if err := dbCommand(runner).Run(t.Context(), []string{"db", "purge", "--table", "nope"}); err == nil {
+			t.Error("expected error for unknown table")
+		}
+
+		output.Reset()
+		if err := dbCommand(runner).Run(t.Context(), []string{"db", "restore", "playlists"}); err != nil {
+			t.Fatalf("db restore list failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "No deleted playlists") {
+			t.Errorf("expected empty deleted listing, got %q", output.String())
+		}
+		if err := dbCommand(runner).Run(t.Context(), []string{"db", "restore", "playlists", "missing"}); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound restoring unknown ID, got %v", err)
+		}
+	})
+
+	t.Run("config validate", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		if err := shared.CreateConfigFile(configPath); err != nil {
+			t.Fatal(err)
+		}
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Output: output})
+
+		err := configCommand(runner).Run(t.Context(), []string{"config", "validate", "--config", configPath})
+		if !errors.Is(err, shared.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for the example config, got %v", err)
+		}
+		if !strings.Contains(output.String(), "✗ missing credentials: credentials.spotify.client_id is still the example value") {
+			t.Errorf("expected the example client ID reported, got %q", output.String())
+		}
+
+		config, _ := shared.LoadConfig(configPath)
+		config.Credentials.Spotify.ClientID = "client"
+		config.Credentials.Spotify.ClientSecret = ""
+		config.Credentials.YouTube.HeadersPath = configPath
+		if err := shared.SaveConfig(configPath, config); err != nil {
+			t.Fatal(err)
+		}
+		output.Reset()
+		if err := configCommand(runner).Run(t.Context(), []string{"config", "validate", "--config", configPath, "--json"}); err != nil {
+			t.Fatalf("expected a valid config, got %v", err)
+		}
+		if !strings.Contains(output.String(), `"valid": true`) {
+			t.Errorf("expected a valid report, got %s", output.String())
+		}
+
+		missing := filepath.Join(t.TempDir(), "config.toml")
+		if err := configCommand(runner).Run(t.Context(), []string{"config", "validate", "--config", missing}); !errors.Is(err, shared.ErrMissingConfig) {
+			t.Errorf("expected ErrMissingConfig for a missing file, got %v", err)
+		}
+
+		output.Reset()
+		if err := configCommand(runner).Run(t.Context(), []string{"config", "set", "--config", configPath, "server.port", "4000"}); err != nil {
+			t.Fatalf("config set failed: %v", err)
+		}
+		if err := configCommand(runner).Run(t.Context(), []string{"config", "get", "--config", configPath, "server.port"}); err != nil {
+			t.Fatalf("config get failed: %v", err)
+		}
+		if !strings.HasSuffix(output.String(), "\n4000\n") {
+			t.Errorf("expected the new port, got %q", output.String())
+		}
+		if err := configCommand(runner).Run(t.Context(), []string{"config", "set", "--config", configPath, "server.port"}); !errors.Is(err, shared.ErrMissingArgument) {
+			t.Errorf("expected ErrMissingArgument without a value, got %v", err)
+		}
+	})
+
+	t.Run("backup commands", func(t *testing.T) {
+		config := shared.DefaultConfig()
+		config.Database.Path = filepath.Join(t.TempDir(), "ytx.db")
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Config: config, Output: output})
+
+		path := filepath.Join(t.TempDir(), "ytx-backup.db")
+		if err := backupCommand(runner).Run(t.Context(), []string{"backup", "create", path}); err != nil {
+			t.Fatalf("backup create failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "Backed up") {
+			t.Errorf("expected backup summary, got %q", output.String())
+		}
+
+		output.Reset()
+		if err := backupCommand(runner).Run(t.Context(), []string{"backup", "restore", path}); err != nil {
+			t.Fatalf("backup restore failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "Restored 0 rows") {
+			t.Errorf("expected restore summary, got %q", output.String())
+		}
+
+		if err := backupCommand(runner).Run(t.Context(), []string{"backup", "create"}); !errors.Is(err, shared.ErrMissingArgument) {
+			t.Errorf("expected ErrMissingArgument without a file, got %v", err)
+		}
+	})
+
+	t.Run("search command", func(t *testing.T) {
+		config := shared.DefaultConfig()
+		config.Database.Path = filepath.Join(t.TempDir(), "ytx.db")
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Config: config, Output: output})
+
+		if err := searchCommand(runner).Run(t.Context(), []string{"search", "nothing", "here"}); err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if !strings.Contains(output.String(), `No cached playlists or tracks match "nothing here"`) {
+			t.Errorf("expected empty search summary, got %q", output.String())
+		}
+		if err := searchCommand(runner).Run(t.Context(), []string{"search"}); !errors.Is(err, shared.ErrMissingArgument) {
+			t.Errorf("expected ErrMissingArgument without a query, got %v", err)
+		}
+	})
+
+	t.Run("ytmusic export", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/library/playlists":
+				json.NewEncoder(w).Encode([]map[string]any{
+					{"playlistId": "PLfocus", "title": "Focus Mix", "count": 1},
+					{"playlistId": "PLparty", "title": "Party", "count": 1},
+				})
+			case "/api/playlists/PLfocus":
+				json.NewEncoder(w).Encode(map[string]any{
+					"id": "PLfocus", "title": "Focus Mix", "trackCount": 1,
+					"tracks":     []map[string]any{{"videoId": "vid1", "title": "Song", "artists": []map[string]any{{"name": "Artist"}}}},
+					"thumbnails": []map[string]any{{"url": server.URL + "/cover.jpg", "width": 544, "height": 544}},
+				})
+			case "/cover.jpg":
+				w.Write([]byte("jpeg"))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		config := shared.DefaultConfig()
+		config.Database.Path = filepath.Join(t.TempDir(), "ytx.db")
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Config: config, Output: output, YouTube: services.NewYouTubeService(server.URL)})
+
+		dir := filepath.Join(t.TempDir(), "backup")
+		args := []string{"ytmusic", "export", "--all", "--match", "focus*", "--covers", "--output", dir}
+		if err := ytmusicCommand(runner).Run(t.Context(), args); err != nil {
+			t.Fatalf("export failed: %v\n%s", err, output.String())
+		}
+		for _, file := range []string{"PLfocus.json", "PLfocus_cover.jpg", "export_manifest.json"} {
+			if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+				t.Errorf("expected %s in the export: %v", file, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "PLparty.json")); err == nil {
+			t.Error("expected PLparty left out by --match")
+		}
+
+		// Only the playlist without a snapshot is selected; the proxy then fails to export it
+		output.Reset()
+		args = []string{"ytmusic", "export", "--all", "--changed", "--output", dir}
+		if err := ytmusicCommand(runner).Run(t.Context(), args); err == nil || !strings.Contains(output.String(), "1 of 2 playlists selected") {
+			t.Errorf("expected only the unexported playlist selected as changed, got %v: %q", err, output.String())
+		}
+
+		if err := ytmusicCommand(runner).Run(t.Context(), []string{"ytmusic", "export"}); !errors.Is(err, shared.ErrMissingArgument) {
+			t.Errorf("expected ErrMissingArgument without --all or --ids, got %v", err)
+		}
+	})
+
+	t.Run("stats command", func(t *testing.T) {
+		config := shared.DefaultConfig()
+		config.Database.Path = filepath.Join(t.TempDir(), "ytx.db")
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Config: config, Output: output})
+
+		if err := statsCommand(runner).Run(t.Context(), []string{"stats"}); err != nil {
+			t.Fatalf("stats failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "No migrations recorded yet") {
+			t.Errorf("expected empty history hint, got %q", output.String())
+		}
+
+		output.Reset()
+		if err := statsCommand(runner).Run(t.Context(), []string{"stats", "--json"}); err != nil {
+			t.Fatalf("stats --json failed: %v", err)
+		}
+		if !strings.Contains(output.String(), `"match_rate_by_day": []`) {
+			t.Errorf("expected JSON analytics, got %q", output.String())
+		}
+		if err := statsCommand(runner).Run(t.Context(), []string{"stats", "--top", "-1"}); !errors.Is(err, shared.ErrInvalidFlag) {
+			t.Errorf("expected ErrInvalidFlag for negative --top, got %v", err)
+		}
+	})
+
+	t.Run("auth status command", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status": "ok", "authenticated": false}`))
+		}))
+		defer proxy.Close()
+
+		headersPath := filepath.Join(t.TempDir(), "headers_auth.json")
+		if err := os.WriteFile(headersPath, []byte(`{"cookie": "x"}`), 0600); err != nil {
+			t.Fatalf("failed to write headers file: %v", err)
+		}
+
+		config := shared.DefaultConfig()
+		config.Credentials.Spotify.ClientSecret = ""
+		config.Credentials.Spotify.AccessToken = "expired"
+		config.Credentials.Spotify.RefreshToken = "refresh"
+		config.Credentials.YouTube.HeadersPath = headersPath
+
+		account := &fakeSpotifyAccount{expired: true}
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Config: config, Output: output, API: services.NewAPIService(proxy.URL, nil)})
+		runner.spotify = account
+
+		err := authCommand(runner).Run(t.Context(), []string{"auth", "status", "--json"})
+		if !errors.Is(err, shared.ErrNotAuthenticated) {
+			t.Errorf("expected ErrNotAuthenticated for an unauthenticated proxy, got %v", err)
+		}
+		if !account.refreshed {
+			t.Error("expected the expired token to be refreshed")
+		}
+
+		var report struct {
+			Healthy bool        `json:"healthy"`
+			Checks  []authCheck `json:"checks"`
+		}
+		if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+			t.Fatalf("failed to parse JSON output: %v\n%s", err, output.String())
+		}
+		if report.Healthy {
+			t.Error("expected unhealthy report")
+		}
+
+		statuses := map[string]string{}
+		for _, check := range report.Checks {
+			statuses[check.Service+" "+check.Check] = check.Status
+		}
+		want := map[string]string{
+			"Spotify credentials":     checkOK,
+			"Spotify refresh":         checkOK,
+			"Spotify token":           checkOK,
+			"YouTube Music auth file": checkOK,
+			"YouTube Music proxy":     checkOK,
+			"YouTube Music session":   checkFail,
+		}
+		for name, status := range want {
+			if statuses[name] != status {
+				t.Errorf("expected %s to be %s, got %q", name, status, statuses[name])
+			}
+		}
+
+		output.Reset()
+		config.Credentials.Spotify.AccessToken = ""
+		authCommand(runner).Run(t.Context(), []string{"auth", "status"})
+		for _, text := range []string{"client ID (PKCE)", "not authorized", "Run 'ytx spotify auth'", "ytx auth login"} {
+			if !strings.Contains(output.String(), text) {
+				t.Errorf("expected %q in table output, got:\n%s", text, output.String())
+			}
+		}
+	})
+
+	t.Run("history command", func(t *testing.T) {
+		config := shared.DefaultConfig()
+		config.Database.Path = filepath.Join(t.TempDir(), "ytx.db")
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{Config: config, Output: output})
+
+		db, err := runner.openDatabase(t.Context())
+		if err != nil {
+			t.Fatalf("failed to open database: %v", err)
+		}
+		user, err := repositories.EnsureLocalUser(t.Context(), repositories.NewUserRepository(db))
+		if err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		history := repositories.NewMigrationHistoryAdapter(user.ID(), repositories.NewPlaylistRepository(db), repositories.NewMigrationRepository(db))
+		record := models.TransferRecord{
+			SourceService: "spotify", SourcePlaylist: models.Playlist{ID: "sp1", Name: "Road Trip"},
+			TargetService: "youtube",
+			TracksTotal:   4,
+			StartedAt:     time.Now().Add(-2 * historyStaleAfter),
+		}
+		id, err := history.StartTransfer(t.Context(), record)
+		if err != nil {
+			t.Fatalf("failed to start transfer: %v", err)
+		}
+		if _, err := db.ExecContext(t.Context(), "UPDATE migrations SET progress_current = 2, last_progress_at = ? WHERE id = ?", record.StartedAt, id); err != nil {
+			t.Fatalf("failed to backdate progress: %v", err)
+		}
+		db.Close()
+
+		if err := historyCommand(runner).Run(t.Context(), []string{"history", "--watch", "--interval", "1ms"}); err != nil {
+			t.Fatalf("history failed: %v", err)
+		}
+		for _, want := range []string{"stalled", "2/4  50.0%", "Road Trip"} {
+			if !strings.Contains(output.String(), want) {
+				t.Errorf("expected %q in history output, got %q", want, output.String())
+			}
+		}
+		if got := strings.Count(output.String(), "Migrations (1)"); got != 1 {
+			t.Errorf("expected --watch to stop after one refresh without active migrations, got %d", got)
+		}
+
+		output.Reset()
+		if err := historyCommand(runner).Run(t.Context(), []string{"history", "--status", "completed"}); err != nil {
+			t.Fatalf("history --status failed: %v", err)
+		}
+		if !strings.Contains(output.String(), "No migrations recorded yet") {
+			t.Errorf("expected no completed migrations, got %q", output.String())
+		}
+	})
+
 	t.Run("saveTokens", func(t *testing.T) {
 		t.Run("saves tokens successfully", func(t *testing.T) {
 			tmpDir := t.TempDir()
@@ -417,5 +845,365 @@ func TestRunner(t *testing.T) {
 				t.Errorf("expected updated access token in runner config")
 			}
 		})
+
+		t.Run("saves tokens to the active profile", func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+
+			config := shared.DefaultConfig()
+			config.Credentials.Spotify.ClientID = "base_id"
+			config.Credentials.Spotify.AccessToken = "base_token"
+			config.Profiles = map[string]shared.ProfileConfig{
+				"work": {Credentials: shared.CredentialsConfig{Spotify: shared.SpotifyConfig{ClientID: "work_id", ClientSecret: "work_secret"}}},
+			}
+			if err := shared.SaveConfig(configPath, config); err != nil {
+				t.Fatalf("failed to create test config: %v", err)
+			}
+
+			runner := NewRunner(RunnerOpts{Config: config, ConfigPath: configPath})
+			root := &cli.Command{
+				Name:   "ytx",
+				Flags:  []cli.Flag{&cli.StringFlag{Name: "profile"}},
+				Before: runner.configure,
+				Action: func(context.Context, *cli.Command) error { return nil },
+			}
+			if err := root.Run(context.Background(), []string{"ytx", "--profile", "work"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if runner.config.Credentials.Spotify.ClientID != "work_id" {
+				t.Errorf("expected work credentials, got %s", runner.config.Credentials.Spotify.ClientID)
+			}
+
+			if err := runner.saveTokens(&oauth2.Token{AccessToken: "work_token", RefreshToken: "work_refresh"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			loadedConfig, err := shared.LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("failed to reload config: %v", err)
+			}
+			if got := loadedConfig.Profiles["work"].Credentials.Spotify.AccessToken; got != "work_token" {
+				t.Errorf("expected profile access token work_token, got %q", got)
+			}
+			if loadedConfig.Credentials.Spotify.AccessToken != "base_token" {
+				t.Errorf("expected top-level token to be unchanged, got %s", loadedConfig.Credentials.Spotify.AccessToken)
+			}
+		})
+
+		t.Run("saves tokens to another profile", func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+
+			config := shared.DefaultConfig()
+			config.Credentials.Spotify.ClientID = "base_id"
+			config.Credentials.Spotify.AccessToken = "base_token"
+			config.Profiles = map[string]shared.ProfileConfig{
+				"work": {Credentials: shared.CredentialsConfig{Spotify: shared.SpotifyConfig{ClientID: "work_id", AccessToken: "old_token"}}},
+			}
+			if err := shared.SaveConfig(configPath, config); err != nil {
+				t.Fatalf("failed to create test config: %v", err)
+			}
+
+			runner := NewRunner(RunnerOpts{Config: config, ConfigPath: configPath})
+			if err := runner.saveProfileTokens("work", &oauth2.Token{AccessToken: "work_token", RefreshToken: "work_refresh"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			loadedConfig, err := shared.LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("failed to reload config: %v", err)
+			}
+			if got := loadedConfig.Profiles["work"].Credentials.Spotify.AccessToken; got != "work_token" {
+				t.Errorf("expected profile access token work_token, got %q", got)
+			}
+			if loadedConfig.Credentials.Spotify.AccessToken != "base_token" {
+				t.Errorf("expected the active account's token to be unchanged, got %s", loadedConfig.Credentials.Spotify.AccessToken)
+			}
+		})
+
+		t.Run("rejects unknown profile", func(t *testing.T) {
+			runner := NewRunner(RunnerOpts{ConfigPath: filepath.Join(t.TempDir(), "config.toml")})
+			root := &cli.Command{
+				Name:   "ytx",
+				Flags:  []cli.Flag{&cli.StringFlag{Name: "profile"}},
+				Before: runner.configure,
+				Action: func(context.Context, *cli.Command) error { return nil },
+			}
+
+			err := root.Run(context.Background(), []string{"ytx", "--profile", "missing"})
+			if !errors.Is(err, shared.ErrMissingConfig) {
+				t.Errorf("expected ErrMissingConfig, got %v", err)
+			}
+		})
+	})
+}
+
+// fakeSpotifyAccount reports an expired token until it is refreshed.
+type fakeSpotifyAccount struct {
+	services.Service
+	expired   bool
+	refreshed bool
+}
+
+func (f *fakeSpotifyAccount) UserProfile(ctx context.Context) (*services.SpotifyUser, error) {
+	if f.expired {
+		return nil, fmt.Errorf("%w: Spotify returned 401", shared.ErrTokenExpired)
+	}
+	return &services.SpotifyUser{DisplayName: "Test User"}, nil
+}
+
+func (f *fakeSpotifyAccount) RefreshToken(ctx context.Context) error {
+	f.expired = false
+	f.refreshed = true
+	return nil
+}
+
+func TestRunnerConfigure(t *testing.T) {
+	run := func(t *testing.T, runner *Runner, args ...string) error {
+		t.Helper()
+		root := &cli.Command{
+			Name:   "ytx",
+			Flags:  append([]cli.Flag{&cli.StringFlag{Name: "profile", Sources: cli.EnvVars("YTX_PROFILE")}}, configFlags()...),
+			Before: runner.configure,
+			Action: func(context.Context, *cli.Command) error { return nil },
+		}
+		return root.Run(context.Background(), append([]string{"ytx"}, args...))
+	}
+
+	newConfigFile := func(t *testing.T) string {
+		t.Helper()
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		config := shared.DefaultConfig()
+		config.Credentials.Spotify.ClientID = "file_id"
+		config.Credentials.Spotify.ClientSecret = "file_secret"
+		config.Profiles = map[string]shared.ProfileConfig{
+			"work": {Credentials: shared.CredentialsConfig{YouTube: shared.YouTubeConfig{ProxyURL: "http://work:8080"}}},
+		}
+		if err := shared.SaveConfig(configPath, config); err != nil {
+			t.Fatalf("failed to create test config: %v", err)
+		}
+		return configPath
+	}
+
+	t.Run("flags take precedence over environment, profile, and file", func(t *testing.T) {
+		t.Setenv("YTX_PROFILE", "work")
+		t.Setenv("YTX_SPOTIFY_CLIENT_ID", "env_id")
+		t.Setenv("YTX_YT_PROXY_URL", "http://env:8080")
+
+		runner := NewRunner(RunnerOpts{ConfigPath: newConfigFile(t)})
+		if err := run(t, runner, "--yt-proxy-url", "http://flag:8080"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if runner.config.Credentials.YouTube.ProxyURL != "http://flag:8080" {
+			t.Errorf("expected flag proxy_url, got %s", runner.config.Credentials.YouTube.ProxyURL)
+		}
+		if runner.config.Credentials.Spotify.ClientID != "env_id" {
+			t.Errorf("expected env client_id, got %s", runner.config.Credentials.Spotify.ClientID)
+		}
+		if runner.config.Credentials.Spotify.ClientSecret != "file_secret" {
+			t.Errorf("expected file client_secret, got %s", runner.config.Credentials.Spotify.ClientSecret)
+		}
+	})
+
+	t.Run("overrides are not saved with tokens", func(t *testing.T) {
+		t.Setenv("YTX_SPOTIFY_CLIENT_SECRET", "env_secret")
+
+		configPath := newConfigFile(t)
+		runner := NewRunner(RunnerOpts{ConfigPath: configPath})
+		if err := run(t, runner); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if err := runner.saveTokens(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		loadedConfig, err := shared.LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("failed to reload config: %v", err)
+		}
+		if loadedConfig.Credentials.Spotify.ClientSecret != "file_secret" {
+			t.Errorf("expected client_secret from file to be kept, got %s", loadedConfig.Credentials.Spotify.ClientSecret)
+		}
+		if loadedConfig.Credentials.Spotify.AccessToken != "access" {
+			t.Errorf("expected access token to be saved, got %s", loadedConfig.Credentials.Spotify.AccessToken)
+		}
+	})
+
+	t.Run("invalid integer override", func(t *testing.T) {
+		runner := NewRunner(RunnerOpts{ConfigPath: newConfigFile(t)})
+		if err := run(t, runner, "--server-port", "http"); !errors.Is(err, shared.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	config := shared.DefaultConfig()
+	config.Database.Path = filepath.Join(t.TempDir(), "ytx.db")
+	if err := shared.SaveConfig(configPath, config); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := func(t *testing.T, shell string, args ...string) string {
+		t.Helper()
+		t.Setenv("SHELL", shell)
+		args = append(append([]string{"ytx"}, args...), "--generate-shell-completion")
+		oldArgs := os.Args
+		os.Args = args
+		t.Cleanup(func() { os.Args = oldArgs })
+
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{ConfigPath: configPath})
+		root := &cli.Command{Name: "ytx", Writer: output, Flags: configFlags(), Commands: runner.register()}
+		root.Flags = append(root.Flags, &cli.StringFlag{Name: "profile"})
+		runner.configureCompletion(root)
+		if err := root.Run(t.Context(), args); err != nil {
+			t.Fatalf("completion failed: %v", err)
+		}
+		return output.String()
+	}
+
+	t.Run("without a cache", func(t *testing.T) {
+		if got := complete(t, "/bin/bash", "transfer", "run", "--source"); got != "" {
+			t.Errorf("expected no completions, got %q", got)
+		}
+		if _, err := os.Stat(config.Database.Path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected completion not to create the database, got %v", err)
+		}
+	})
+
+	db, err := shared.NewDatabase(config.Database.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := shared.RunMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+	playlists := repositories.NewPlaylistRepository(db)
+	for _, p := range []*models.PersistedPlaylist{
+		models.NewPersistedPlaylist(0, "spotify", "sp1", "user", models.Playlist{ID: "sp1", Name: "Road Trip"}),
+		models.NewPersistedPlaylist(0, "youtube", "PLyt1", "user", models.Playlist{ID: "PLyt1", Name: "Focus: Deep Work"}),
+	} {
+		if err := playlists.Create(t.Context(), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		shell string
+		args  []string
+		want  string
+	}{
+		{"flag value in bash", "/bin/bash", []string{"transfer", "run", "--source"}, "sp1\n"},
+		{"flag value in zsh", "/bin/zsh", []string{"spotify", "export", "--id"}, "sp1:Road Trip\n"},
+		{"argument in fish", "/usr/bin/fish", []string{"ytmusic", "delete"}, "PLyt1\tFocus: Deep Work\n"},
+		{"either service", "/bin/bash", []string{"diff", "--dest-id"}, "sp1\nPLyt1\n"},
+		{"service picked by flag", "/bin/bash", []string{"diff", "--dest-service", "youtube", "--dest-id"}, "PLyt1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := complete(t, tt.shell, tt.args...); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("other flags complete as before", func(t *testing.T) {
+		got := complete(t, "/bin/bash", "transfer", "run", "-")
+		if !strings.Contains(got, "--source") || strings.Contains(got, "sp1") {
+			t.Errorf("expected flag names, got %q", got)
+		}
+	})
+
+	t.Run("fish script", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		runner := NewRunner(RunnerOpts{ConfigPath: configPath})
+		root := &cli.Command{Name: "ytx", Writer: output, Commands: runner.register()}
+		runner.configureCompletion(root)
+		if err := root.Run(t.Context(), []string{"ytx", "completion", "fish"}); err != nil {
+			t.Fatalf("completion fish failed: %v", err)
+		}
+		want := "complete -c ytx -n '__fish_seen_subcommand_from transfer; and __fish_seen_subcommand_from run' -l source -x " +
+			"-a '(env SHELL=fish ytx transfer run --source --generate-shell-completion)'"
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("expected playlist completion for transfer run --source, got %q", output.String())
+		}
+	})
+}
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitOK},
+		{"unknown error", errors.New("boom"), exitFailure},
+		{"not authenticated", fmt.Errorf("%w: call Authenticate first", shared.ErrNotAuthenticated), exitNotAuthenticated},
+		{"token expired", fmt.Errorf("%w: Spotify returned 401", shared.ErrTokenExpired), exitTokenExpired},
+		{"playlist not found", fmt.Errorf("%w: no playlist found with name 'x'", shared.ErrPlaylistNotFound), exitNotFound},
+		{"timeout", fmt.Errorf("%w: authorization timed out", shared.ErrTimeout), exitTimeout},
+		{"deadline exceeded", fmt.Errorf("request failed: %w", context.DeadlineExceeded), exitTimeout},
+		{"api request", fmt.Errorf("%w: failed to create playlist", shared.ErrAPIRequest), exitAPIRequest},
+		{"invalid argument", fmt.Errorf("%w: invalid service", shared.ErrInvalidArgument), exitUsage},
+		{"cancelled", fmt.Errorf("transfer cancelled: %w", context.Canceled), exitCancelled},
+		{"most specific cause wins", fmt.Errorf("%w: %w", shared.ErrAPIRequest, shared.ErrTokenExpired), exitTokenExpired},
+		{"spotify API error", fmt.Errorf("%w: %w", shared.ErrAPIRequest, &services.SpotifyAPIError{StatusCode: 403}), exitAPIRequest},
+		{"cli exit coder", cli.Exit("usage", 9), 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.want {
+				t.Errorf("exitCodeFor(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("help topic lists every code", func(t *testing.T) {
+		help := exitCodesHelp()
+		for _, ec := range exitCodes {
+			if !strings.Contains(help, fmt.Sprintf("%3d  %s", ec.code, ec.description)) {
+				t.Errorf("expected exit code %d in help, got %q", ec.code, help)
+			}
+		}
+	})
+}
+
+// slowPersister takes delay per upsert and fails if the context it was handed has already expired
+type slowPersister struct {
+	tasks.LibraryPersister
+	delay time.Duration
+}
+
+func (p slowPersister) UpsertTrack(ctx context.Context, _ string, _ models.Track) error {
+	time.Sleep(p.delay)
+	return ctx.Err()
+}
+
+func TestTimedPersister(t *testing.T) {
+	timeout := func(ctx context.Context) (context.Context, context.CancelFunc) {
+		return context.WithTimeout(ctx, 50*time.Millisecond)
+	}
+	persister := timedPersister{slowPersister{delay: 20 * time.Millisecond}, timeout}
+
+	t.Run("each upsert gets the full timeout", func(t *testing.T) {
+		for i := range 5 {
+			if err := persister.UpsertTrack(context.Background(), "spotify", models.Track{ID: fmt.Sprint(i)}); err != nil {
+				t.Fatalf("upsert %d: unexpected error: %v", i, err)
+			}
+		}
+	})
+
+	t.Run("slow upsert times out", func(t *testing.T) {
+		slow := timedPersister{slowPersister{delay: 80 * time.Millisecond}, timeout}
+		err := slow.UpsertTrack(context.Background(), "spotify", models.Track{ID: "1"})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
 	})
 }
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// searchPlaylistResult is a cached playlist matched by [Runner.Search]
+type searchPlaylistResult struct {
+	Service  string          `json:"service"`
+	Playlist models.Playlist `json:"playlist"`
+}
+
+// searchTrackResult is a cached track matched by [Runner.Search], with the cached playlists that contain it
+type searchTrackResult struct {
+	Service   string                 `json:"service"`
+	Track     models.Track           `json:"track"`
+	Playlists []searchPlaylistResult `json:"playlists"`
+}
+
+// searchResults is the output of [Runner.Search]
+type searchResults struct {
+	Query     string                 `json:"query"`
+	Playlists []searchPlaylistResult `json:"playlists"`
+	Tracks    []searchTrackResult    `json:"tracks"`
+}
+
+// Search looks up cached playlists and tracks by keyword without contacting any external API.
+//
+// Each matching track is listed with the cached playlists that contain it.
+func (r *Runner) Search(ctx context.Context, cmd *cli.Command) error {
+	query := strings.TrimSpace(strings.Join(cmd.Args().Slice(), " "))
+	if query == "" {
+		return fmt.Errorf("%w: search query is required", shared.ErrMissingArgument)
+	}
+
+	limit := cmd.Int("limit")
+	if limit < 0 {
+		return fmt.Errorf("%w: --limit cannot be negative", shared.ErrInvalidFlag)
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	results, err := r.searchCache(ctx, db, query, limit)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infof("search %q matched %d playlists and %d tracks", query, len(results.Playlists), len(results.Tracks))
+
+	if cmd.Bool("json") {
+		return r.writeJSON(results, true)
+	}
+
+	if len(results.Playlists) == 0 && len(results.Tracks) == 0 {
+		r.writePlainln("No cached playlists or tracks match %q", query)
+		return nil
+	}
+
+	if len(results.Playlists) > 0 {
+		r.writePlainHeader(fmt.Sprintf("Playlists (%d)", len(results.Playlists)))
+		for _, result := range results.Playlists {
+			r.writePlain("  [%s] %s (%d tracks)\n", result.Service, result.Playlist.Name, result.Playlist.TrackCount)
+		}
+		r.writePlainln("")
+	}
+
+	if len(results.Tracks) > 0 {
+		r.writePlainHeader(fmt.Sprintf("Tracks (%d)", len(results.Tracks)))
+		for _, result := range results.Tracks {
+			r.writePlain("  [%s] %s - %s\n", result.Service, result.Track.Title, result.Track.Artist())
+			if len(result.Playlists) == 0 {
+				r.writePlain("      not in any cached playlist\n")
+			}
+			for _, playlist := range result.Playlists {
+				r.writePlain("      in %s\n", playlist.Playlist.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// searchCache looks query up in the cached playlists and tracks, with the playlists containing each matched track
+func (r *Runner) searchCache(ctx context.Context, db *sql.DB, query string, limit int) (searchResults, error) {
+	ctx, cancel := r.queryContext(ctx)
+	defer cancel()
+
+	playlistRepo := repositories.NewPlaylistRepository(db)
+	trackRepo := repositories.NewTrackRepository(db)
+
+	playlists, err := playlistRepo.Search(ctx, query, limit)
+	if err != nil {
+		return searchResults{}, err
+	}
+
+	tracks, err := trackRepo.Search(ctx, query, limit)
+	if err != nil {
+		return searchResults{}, err
+	}
+
+	results := searchResults{
+		Query:     query,
+		Playlists: make([]searchPlaylistResult, 0, len(playlists)),
+		Tracks:    make([]searchTrackResult, 0, len(tracks)),
+	}
+	for _, playlist := range playlists {
+		results.Playlists = append(results.Playlists, searchPlaylistResult{Service: playlist.Service(), Playlist: playlist.ToPlaylist()})
+	}
+	for _, track := range tracks {
+		containing, err := playlistRepo.ListByTrack(ctx, track.ID())
+		if err != nil {
+			return searchResults{}, err
+		}
+
+		result := searchTrackResult{
+			Service:   track.Service(),
+			Track:     track.ToTrack(),
+			Playlists: make([]searchPlaylistResult, 0, len(containing)),
+		}
+		for _, playlist := range containing {
+			result.Playlists = append(result.Playlists, searchPlaylistResult{Service: playlist.Service(), Playlist: playlist.ToPlaylist()})
+		}
+		results.Tracks = append(results.Tracks, result)
+	}
+
+	return results, nil
+}
+
+// searchCommand searches the local cache of playlists and tracks
+func searchCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Search cached playlists and tracks",
+		ArgsUsage: "<query>",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Usage:   "Maximum number of playlists and tracks to show (0 for no limit)",
+				Value:   20,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output results as JSON",
+			},
+		},
+		Action: r.Search,
+	}
+}
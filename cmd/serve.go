@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/server"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+	"github.com/desertthunder/ytx/internal/web"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
+)
+
+// defaultServeAddr is where serve listens without --addr or server.listen_addr, clear of the OAuth callback port
+const defaultServeAddr = "localhost:8080"
+
+// Serve runs the multi-user web server and its transfer workers until interrupted.
+func (r *Runner) Serve(ctx context.Context, cmd *cli.Command) error {
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	secret := []byte(cmd.String("session-secret"))
+	if len(secret) == 0 {
+		secret = make([]byte, web.MinSessionSecret)
+		rand.Read(secret)
+		r.logger.Warn("no session secret set, sessions and stored credentials will not survive a restart (set YTX_SESSION_SECRET)")
+	}
+
+	addr := cmd.String("addr")
+	if !cmd.IsSet("addr") && r.config.Server.ListenAddr != "" {
+		addr = r.config.Server.ListenAddr
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrServiceUnavailable, err)
+	}
+	defer listener.Close()
+
+	if r.config.Server.APIKey == "" && !isLoopback(listener.Addr()) {
+		r.logger.Warn("serving beyond localhost without an API key; set server.api_key (or YTX_API_KEY)")
+	}
+
+	baseURL := cmd.String("base-url")
+	if baseURL == "" {
+		baseURL = "http://" + listener.Addr().String()
+	}
+
+	var spotify services.OAuthService
+	if r.config.Credentials.Spotify.ClientID != "" {
+		svc, err := services.NewSpotifyService(r.config.Credentials.Spotify.Map())
+		if err != nil {
+			return fmt.Errorf("%w: %v", shared.ErrInvalidConfig, err)
+		}
+		spotify = svc
+	} else {
+		r.logger.Warn("spotify credentials not configured, users cannot connect Spotify accounts")
+	}
+
+	transfer, err := r.queuedTransfer(db, secret)
+	if err != nil {
+		return err
+	}
+	queue := web.NewQueue(transfer, web.QueueOpts{Workers: cmd.Int("workers"), RequestsPerSecond: cmd.Float("requests-per-second")})
+	defer queue.Stop()
+
+	app, err := web.NewApp(web.AppOpts{
+		DB:            db,
+		SessionSecret: secret,
+		Spotify:       spotify,
+		BaseURL:       baseURL,
+		SecureCookies: strings.HasPrefix(baseURL, "https://"),
+		Jobs:          queue,
+		Origins:       r.config.Server.CORSOrigins,
+	})
+	if err != nil {
+		return err
+	}
+
+	router := server.NewBasicRouter()
+	// Added first so they wrap the API key check: every request is logged, and preflights and rejected requests get
+	// their headers too
+	logger := r.logging.Logger("server")
+	router.Use(server.AccessLog(logger), server.Recover(logger))
+	router.Use(server.SecurityHeaders(server.DefaultContentSecurityPolicy), server.CORS(r.config.Server.CORSOrigins))
+	router.Use(server.RequireAPIKeyOr(r.config.Server.APIKey, app.HasSession, server.DefaultAPIKeyExemptions...))
+	router.Handler(app)
+	srv := server.NewServer(router)
+	srv.AddReadinessCheck("database", db.PingContext)
+	if r.api != nil {
+		srv.AddReadinessCheck("proxy", r.proxyReady)
+	}
+
+	r.writePlainln("Serving ytx on %s", baseURL)
+	if spotify != nil {
+		r.writePlain("  Add %s/auth/spotify/callback to your Spotify app's Redirect URIs.\n", baseURL)
+	}
+	if err := srv.Run(ctx, listener); err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrServiceUnavailable, err)
+	}
+	r.writePlainln("✓ Server stopped")
+	return nil
+}
+
+// queuedTransfer runs the web server's queued transfers, each with the credentials of the user who submitted it
+// and with its own engine, so jobs of different users never share a token or an auth file. Credentials are decrypted
+// with the key derived from secret, as the web app stored them.
+func (r *Runner) queuedTransfer(db *sql.DB, secret []byte) (web.TransferFunc, error) {
+	credentials, err := repositories.NewCredentialRepository(db, secret)
+	if err != nil {
+		return nil, err
+	}
+	tracks := repositories.NewTrackRepository(db)
+	playlists := repositories.NewPlaylistRepository(db)
+	playlistTracks := repositories.NewPlaylistTrackRepository(db)
+	migrations := repositories.NewMigrationRepository(db)
+	logger := r.logging.Logger("tasks")
+
+	return func(ctx context.Context, userID string, req web.TransferRequest, limiter *rate.Limiter, progress chan<- tasks.ProgressUpdate) (*tasks.TransferRunResult, error) {
+		spotifyClient, ytClient := r.serviceClient("spotify"), r.serviceClient("youtube")
+		spotifyClient.Transport = services.RateLimitedTransport(limiter, spotifyClient.Transport)
+		ytClient.Transport = services.RateLimitedTransport(limiter, ytClient.Transport)
+
+		token, err := credentials.Token(ctx, userID, "spotify")
+		if errors.Is(err, shared.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: connect a Spotify account first", shared.ErrMissingCredentials)
+		} else if err != nil {
+			return nil, err
+		}
+		spotify, err := services.NewSpotifyService(r.config.Credentials.Spotify.Map())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", shared.ErrInvalidConfig, err)
+		}
+		spotify.SetHTTPClient(spotifyClient)
+		spotify.SetTokenRefreshCallback(func(token *oauth2.Token) {
+			if err := credentials.SaveToken(context.WithoutCancel(ctx), userID, "spotify", token); err != nil {
+				logger.Warnf("failed to save refreshed token for user %s: %v", userID, err)
+			}
+		})
+		if err := spotify.OAuthenticate(ctx, token); err != nil {
+			return nil, fmt.Errorf("%w: %v", shared.ErrNotAuthenticated, err)
+		}
+
+		headers, err := credentials.AuthHeaders(ctx, userID, "youtube")
+		if errors.Is(err, shared.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: upload a YouTube Music auth file first", shared.ErrMissingCredentials)
+		} else if err != nil {
+			return nil, err
+		}
+		authFile, err := writeJobAuthFile(headers)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(authFile)
+		proxyURL := r.config.Credentials.YouTube.ProxyURL
+		yt := services.NewYouTubeService(proxyURL)
+		yt.SetHTTPClient(ytClient)
+		if err := yt.Authenticate(ctx, map[string]string{"auth_file": authFile}); err != nil {
+			return nil, fmt.Errorf("%w: %v", shared.ErrNotAuthenticated, err)
+		}
+		api := services.NewAPIService(proxyURL, ytClient)
+		if err := api.SetAuthFile(authFile); err != nil {
+			return nil, fmt.Errorf("%w: %v", shared.ErrNotAuthenticated, err)
+		}
+
+		engine := tasks.NewPlaylistEngine(spotify, yt, api)
+		engine.SetLogger(logger)
+		engine.SetPlaylistTemplates(r.config.Transfer.NameTemplate, r.config.Transfer.DescriptionTemplate)
+		engine.SetMaxDurationDelta(r.config.Transfer.DurationDelta())
+		explicit, err := tasks.ParseExplicitPreference(r.config.Transfer.Explicit)
+		if err != nil {
+			return nil, err
+		}
+		engine.SetExplicitPreference(explicit)
+		engine.SetFlagUnavailable(r.config.Transfer.FlagUnavailable)
+		engine.SetTrackCacher(repositories.NewTrackCacheAdapter(tracks))
+		engine.SetPlaylistCacher(repositories.NewPlaylistCacheAdapter(userID, playlists, tracks, playlistTracks))
+		engine.SetMigrationRecorder(repositories.NewMigrationHistoryAdapter(userID, playlists, migrations))
+		if r.metrics != nil {
+			engine.SetMetricsRecorder(r.metrics)
+		}
+
+		return engine.RunWithOpts(ctx, req.PlaylistID, tasks.TransferOpts{Name: req.Name, Public: req.Public, Reverse: req.Reverse, Explicit: req.Explicit}, progress)
+	}, nil
+}
+
+// writeJobAuthFile writes a user's YouTube Music auth headers to a file readable only by the server, for the proxy
+// to read during one job. The caller removes it when the job ends.
+func writeJobAuthFile(headers []byte) (string, error) {
+	file, err := os.CreateTemp("", "ytx-auth-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create auth file: %w", err)
+	}
+	if _, err := file.Write(headers); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write auth file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write auth file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// proxyReady checks that the YouTube Music proxy answers its health endpoint
+func (r *Runner) proxyReady(ctx context.Context) error {
+	resp, err := r.api.Get(ctx, "/health")
+	if err != nil {
+		return fmt.Errorf("unreachable: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("/health returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isLoopback reports whether addr only accepts connections from this machine
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
+
+// serveCommand runs ytx as a self-hosted migration service
+func serveCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:    "serve",
+		Aliases: []string{"web"},
+		Usage:   "Run the web server and transfer workers, where each account keeps its own service credentials",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Value: defaultServeAddr,
+				Usage: "Address to listen on (default: server.listen_addr, then " + defaultServeAddr + ")",
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Value: web.DefaultQueueWorkers,
+				Usage: "Transfers run at once",
+			},
+			&cli.FloatFlag{
+				Name:  "requests-per-second",
+				Value: web.DefaultRequestsPerSecond,
+				Usage: "Rate limit of each transfer's service requests, and the most a job's rate_limit control may set",
+			},
+			&cli.StringFlag{
+				Name:  "base-url",
+				Usage: "Public URL of the server, for the Spotify redirect URI (default: http://<addr>)",
+			},
+			&cli.StringFlag{
+				Name:    "session-secret",
+				Usage:   fmt.Sprintf("Key signing session cookies, at least %d bytes (default: random per run)", web.MinSessionSecret),
+				Sources: cli.EnvVars("YTX_SESSION_SECRET"),
+			},
+		},
+		Action: r.Serve,
+	}
+}
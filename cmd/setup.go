@@ -35,16 +35,19 @@ func (r *Runner) SetupDatabase(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
+	config, err := r.resolveConfig(cmd, config)
+	if err != nil {
+		return err
+	}
+
 	r.logger.Info("initializing database", "path", config.Database.Path)
 
-	db, err := shared.NewDatabase(config.Database.Path)
+	db, err := shared.OpenDatabase(config.Database)
 	if err != nil {
 		return fmt.Errorf("failed to create database: %w", err)
 	}
 	defer db.Close()
 
-	shared.ConfigureDatabase(db, config.Database.MaxOpenConns, config.Database.MaxIdleConns)
-
 	r.logger.Info("running database migrations")
 	if err := shared.RunMigrations(db); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -14,7 +14,6 @@ import (
 	"github.com/desertthunder/ytx/internal/server"
 	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
-	"github.com/desertthunder/ytx/internal/tasks"
 	"github.com/urfave/cli/v3"
 	"golang.org/x/oauth2"
 )
@@ -26,12 +25,8 @@ func (r *Runner) SpotifyReauth(ctx context.Context, configPath string, config *s
 		return nil, err
 	}
 
-	if err := config.Credentials.Spotify.Update(token); err != nil {
-		return nil, fmt.Errorf("failed to update spotify configuration: %w", err)
-	}
-
-	if err := shared.SaveConfig(configPath, config); err != nil {
-		return nil, fmt.Errorf("failed to save config: %w", err)
+	if err := r.storeSpotifyToken(configPath, config, token); err != nil {
+		return nil, err
 	}
 
 	r.writePlainln("✓ Reauthorization successful")
@@ -60,8 +55,8 @@ func (r *Runner) SpotifyAuth(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
-	if config.Credentials.Spotify.ClientID == "" || config.Credentials.Spotify.ClientSecret == "" {
-		return fmt.Errorf("%w: Spotify client_id and client_secret must be set in config.toml", shared.ErrInvalidArgument)
+	if config.Credentials.Spotify.ClientID == "" {
+		return fmt.Errorf("%w: Spotify client_id must be set in config.toml", shared.ErrInvalidArgument)
 	}
 
 	spotifyService, err := services.NewSpotifyService(config.Credentials.Spotify.Map())
@@ -74,12 +69,8 @@ func (r *Runner) SpotifyAuth(ctx context.Context, cmd *cli.Command) error {
 		return err
 	}
 
-	if err := config.Credentials.Spotify.Update(token); err != nil {
-		return fmt.Errorf("failed to update spotify configuration: %w", err)
-	}
-
-	if err := shared.SaveConfig(configPath, config); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := r.storeSpotifyToken(configPath, config, token); err != nil {
+		return err
 	}
 
 	r.writePlainln("✓ Authorization successful")
@@ -110,10 +101,10 @@ func (r *Runner) SpotifyPlaylists(ctx context.Context, cmd *cli.Command) error {
 				return authErr
 			}
 			if playlists, err = r.spotify.GetPlaylists(ctx); err != nil {
-				return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+				return fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
 			}
 		} else {
-			return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+			return fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
 		}
 	}
 
@@ -223,10 +214,16 @@ func (r *Runner) SpotifyExport(ctx context.Context, cmd *cli.Command) error {
 			}
 			export, err = r.spotify.ExportPlaylist(ctx, playlistID)
 			if err != nil {
-				return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+				return fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
 			}
 		} else {
-			return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+			return fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
+		}
+	}
+
+	if cmd.Bool("enrich") {
+		if err := r.enrichExport(ctx, export); err != nil {
+			return err
 		}
 	}
 
@@ -245,6 +242,44 @@ func (r *Runner) SpotifyExport(ctx context.Context, cmd *cli.Command) error {
 	}
 }
 
+// trackEnricher is implemented by services that can add genres and audio features to their tracks
+type trackEnricher interface {
+	EnrichTracks(ctx context.Context, tracks []models.Track) error
+}
+
+// spotifyEnricher returns the Spotify service as a [trackEnricher]
+func (r *Runner) spotifyEnricher() (trackEnricher, error) {
+	enricher, ok := r.spotify.(trackEnricher)
+	if !ok {
+		return nil, fmt.Errorf("%w: Spotify service does not support enrichment", shared.ErrServiceUnavailable)
+	}
+	return enricher, nil
+}
+
+// enrichExport adds genres and audio features to the tracks of export and caches the enriched tracks.
+//
+// Enrichment that fails part way is reported and the export goes ahead with what was found.
+func (r *Runner) enrichExport(ctx context.Context, export *models.PlaylistExport) error {
+	enricher, err := r.spotifyEnricher()
+	if err != nil {
+		return err
+	}
+	if err := enricher.EnrichTracks(ctx, export.Tracks); err != nil {
+		r.logger.Warnf("tracks not fully enriched: %v", err)
+	}
+
+	store, closeCache, err := r.enableCaching(ctx)
+	if err != nil {
+		r.logger.Warnf("enriched tracks won't be cached: %v", err)
+		return nil
+	}
+	defer closeCache()
+	if err := store.CachePlaylist(ctx, "spotify", export.Playlist, export.Tracks); err != nil {
+		r.logger.Warnf("enriched tracks won't be cached: %v", err)
+	}
+	return nil
+}
+
 // exportCSV exports a playlist to CSV format with accompanying metadata JSON
 func (r *Runner) exportCSV(export *models.PlaylistExport, filepath string, save bool) error {
 	if filepath == "" && !save {
@@ -337,11 +372,20 @@ func (r *Runner) exportJSON(export *models.PlaylistExport, outputFile string, sa
 	if export.Playlist.Description != "" {
 		r.writePlain("Description: %s\n", export.Playlist.Description)
 	}
+	if export.Playlist.Owner != "" {
+		r.writePlain("Owner: %s\n", export.Playlist.Owner)
+	}
+	if export.Playlist.Collaborative {
+		r.writePlain("Collaborative: yes\n")
+	}
+	if export.Playlist.Followers > 0 {
+		r.writePlain("Followers: %d\n", export.Playlist.Followers)
+	}
 
 	r.writePlain("Tracks: %d\n\n", len(export.Tracks))
 
 	for i, track := range export.Tracks {
-		r.writePlain("%d. %s - %s\n", i+1, track.Artist, track.Title)
+		r.writePlain("%d. %s - %s\n", i+1, track.Artist(), track.Title)
 		if track.Album != "" {
 			r.writePlain("   Album: %s\n", track.Album)
 		}
@@ -360,26 +404,44 @@ func (r *Runner) doOAuth(config *shared.Config, oauthSrv services.OAuthService,
 		return nil, fmt.Errorf("failed to generate state token: %w", err)
 	}
 
-	authURL := oauthSrv.GetAuthURL(state)
-	oauthHandler := server.NewOAuthHandler(oauthSrv.GetOAuthConfig(), state)
-	router := server.NewBasicRouter()
-	router.Handler(oauthHandler)
+	var authOpts, exchangeOpts []oauth2.AuthCodeOption
+	if oauthSrv.UsesPKCE() {
+		pkce := services.NewPKCE()
+		authOpts = append(authOpts, pkce.AuthURLOption())
+		exchangeOpts = append(exchangeOpts, pkce.ExchangeOption())
+		r.logger.Debug("using authorization code flow with PKCE")
+	}
 
-	serverAddr := fmt.Sprintf("%s:%d", config.Server.Host, config.Server.Port)
-	httpServer := &http.Server{
-		Addr:    serverAddr,
-		Handler: router,
+	ports, err := config.Server.CallbackPorts()
+	if err != nil {
+		return nil, err
 	}
+	listener, err := server.ListenCallback(config.Server.Host, ports)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrServiceUnavailable, err)
+	}
+	defer listener.Close()
 
-	serverErrors := make(chan error, 1)
-	go func() {
-		r.logger.Infof("starting OAuth server for %s at %v", prefix, serverAddr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			serverErrors <- err
-		}
-	}()
+	configuredURI := config.Credentials.Spotify.RedirectURI
+	redirectURI, err := server.CallbackURL(configuredURI, listener.Addr().(*net.TCPAddr).Port)
+	if err != nil {
+		return nil, fmt.Errorf("%w: credentials.spotify.redirect_uri: %v", shared.ErrInvalidConfig, err)
+	}
+	if redirectURI != configuredURI {
+		r.writePlain("⚠ Using redirect URI %s (configured: %q).\n", redirectURI, configuredURI)
+		r.writePlain("  It must be added to your Spotify app's Redirect URIs, or Spotify will reject the login.\n")
+	}
+	authOpts = append(authOpts, oauth2.SetAuthURLParam("redirect_uri", redirectURI))
+	exchangeOpts = append(exchangeOpts, oauth2.SetAuthURLParam("redirect_uri", redirectURI))
+
+	authURL := oauthSrv.GetAuthURL(state, authOpts...)
+	oauthHandler := server.NewOAuthHandler(oauthSrv.GetOAuthConfig(), state, exchangeOpts...)
+	router := server.NewBasicRouter()
+	router.Handler(oauthHandler)
 
-	time.Sleep(100 * time.Millisecond)
+	httpServer := server.NewServer(router)
+	r.logger.Infof("starting OAuth server for %s at %v", prefix, listener.Addr())
+	httpServer.Start(listener)
 
 	r.writePlain("→ Opening browser for Spotify %s...\n", prefix)
 	if err := shared.OpenBrowser(authURL); err != nil {
@@ -398,15 +460,13 @@ func (r *Runner) doOAuth(config *shared.Config, oauthSrv services.OAuthService,
 	select {
 	case result = <-oauthHandler.Result():
 		// Got result from callback
-	case err := <-serverErrors:
+	case err := <-httpServer.Errors():
 		return nil, fmt.Errorf("server error: %w", err)
 	case <-timeout.C:
-		return nil, fmt.Errorf("%w: authorization timed out after 2 minutes", shared.ErrTimeout)
+		return nil, fmt.Errorf("%w: authorization timed out after 2 minutes; if Spotify reported an invalid redirect URI, register %s for your app", shared.ErrTimeout, redirectURI)
 	}
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+	if err := httpServer.Shutdown(context.Background()); err != nil {
 		r.logger.Warn("error shutting down server", "error", err)
 	}
 
@@ -481,52 +541,51 @@ func (r *Runner) SpotifyExportAll(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("%w: Spotify service not initialized", shared.ErrServiceUnavailable)
 	}
 
-	if r.engine == nil {
-		return fmt.Errorf("%w: Playlist engine not initialized", shared.ErrServiceUnavailable)
+	req, err := parseBulkExport(cmd)
+	if err != nil {
+		return err
+	}
+	if req.opts.Format == "markdown" || req.opts.Covers {
+		if spotifySvc, ok := r.spotify.(*services.SpotifyService); ok {
+			req.opts.GetCoverImage = spotifySvc.CoverImageURL
+		}
 	}
 
-	format := cmd.String("format")
-	outputDir := cmd.String("output")
-	idsStr := cmd.String("ids")
-	workers := cmd.Int("workers")
-	rateLimit := cmd.Float64("rate-limit")
-	userFilter := cmd.String("user")
-
-	playlistIDs := []string{}
-	if idsStr != "" {
-		for id := range strings.SplitSeq(idsStr, ",") {
-			id = strings.TrimSpace(id)
-			if id != "" {
-				playlistIDs = append(playlistIDs, id)
-			}
+	if cmd.Bool("enrich") {
+		enricher, err := r.spotifyEnricher()
+		if err != nil {
+			return err
 		}
-	} else {
-		r.writePlain("→ Fetching playlist list...\n")
+		req.opts.EnrichTracks = enricher.EnrichTracks
+	}
+
+	userFilter := cmd.String("user")
+	return r.runBulkExport(ctx, r.spotify, "spotify", req, func(ctx context.Context) ([]models.Playlist, error) {
 		playlists, err := r.spotify.GetPlaylists(ctx)
 		if err != nil {
 			if reauthed, authErr := r.handleSpotifyAuthError(ctx, err, cmd); reauthed {
 				if authErr != nil {
-					return authErr
+					return nil, authErr
 				}
 				if playlists, err = r.spotify.GetPlaylists(ctx); err != nil {
-					return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+					return nil, fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
 				}
 			} else {
-				return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+				return nil, fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
 			}
 		}
 
 		if userFilter != "" {
 			spotifySvc, ok := r.spotify.(*services.SpotifyService)
 			if !ok {
-				return fmt.Errorf("spotify service type assertion failed")
+				return nil, fmt.Errorf("spotify service type assertion failed")
 			}
 
 			var targetUserID string
 			if userFilter == "me" {
 				user, err := spotifySvc.UserProfile(ctx)
 				if err != nil {
-					return fmt.Errorf("failed to get user profile: %w", err)
+					return nil, fmt.Errorf("failed to get user profile: %w", err)
 				}
 				targetUserID = user.ID
 			} else {
@@ -546,86 +605,249 @@ func (r *Runner) SpotifyExportAll(ctx context.Context, cmd *cli.Command) error {
 			}
 			playlists = filtered
 		}
+		return playlists, nil
+	})
+}
 
-		for _, pl := range playlists {
-			playlistIDs = append(playlistIDs, pl.ID)
+// spotifyLibrary returns the Spotify service for album and artist lookups and playlist management.
+func (r *Runner) spotifyLibrary() (*services.SpotifyService, error) {
+	if r.spotify == nil {
+		return nil, fmt.Errorf("%w: Spotify service not initialized", shared.ErrServiceUnavailable)
+	}
+	spotifySvc, ok := r.spotify.(*services.SpotifyService)
+	if !ok {
+		return nil, fmt.Errorf("%w: Spotify service does not support library and playlist management", shared.ErrServiceUnavailable)
+	}
+	return spotifySvc, nil
+}
+
+// withSpotifyReauth runs fetch, reauthorizing and retrying once when the token has expired.
+func (r *Runner) withSpotifyReauth(ctx context.Context, cmd *cli.Command, fetch func() error) error {
+	err := fetch()
+	if err == nil {
+		return nil
+	}
+	if reauthed, authErr := r.handleSpotifyAuthError(ctx, err, cmd); reauthed {
+		if authErr != nil {
+			return authErr
 		}
+		err = fetch()
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
 	}
+	return nil
+}
 
-	if len(playlistIDs) == 0 {
-		return fmt.Errorf("no playlists to export")
+// splitIDs splits a comma-separated list of IDs, dropping blanks.
+func splitIDs(idsStr string) []string {
+	var ids []string
+	for id := range strings.SplitSeq(idsStr, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
 	}
+	return ids
+}
 
-	r.writePlain("→ Preparing to export %d playlists in %s format\n\n", len(playlistIDs), format)
+// SpotifyAlbums lists albums saved in the user's library, or looks up albums by ID with their tracks.
+// SpotifyDelete deletes a Spotify playlist by unfollowing it.
+func (r *Runner) SpotifyDelete(ctx context.Context, cmd *cli.Command) error {
+	spotifySvc, err := r.spotifyLibrary()
+	if err != nil {
+		return err
+	}
 
-	progress := make(chan tasks.ProgressUpdate, 100)
-	done := make(chan *tasks.BulkExportResult)
-	errs := make(chan error, 1)
+	playlistID := cmd.StringArg("playlist-id")
+	if playlistID == "" {
+		return fmt.Errorf("%w: playlist ID is required", shared.ErrMissingArgument)
+	}
 
-	var getCoverImage func(context.Context, string) (string, error)
-	if format == "markdown" {
-		spotifySvc, ok := r.spotify.(*services.SpotifyService)
-		if ok {
-			getCoverImage = func(ctx context.Context, playlistID string) (string, error) {
-				pl, err := spotifySvc.Playlist(ctx, playlistID)
-				if err != nil || len(pl.Images) == 0 {
-					return "", fmt.Errorf("no image available")
+	var playlist *models.Playlist
+	if err := r.withSpotifyReauth(ctx, cmd, func() (err error) {
+		playlist, err = spotifySvc.GetPlaylist(ctx, playlistID)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	r.logger.Info("unfollowing spotify playlist", "id", playlistID, "name", playlist.Name)
+	if err := r.withSpotifyReauth(ctx, cmd, func() error {
+		return spotifySvc.UnfollowPlaylist(ctx, playlistID)
+	}); err != nil {
+		return err
+	}
+
+	r.writePlain("✓ Playlist deleted\n")
+	r.writePlain("Name: %s\n", playlist.Name)
+	r.writePlain("ID: %s\n", playlistID)
+	return nil
+}
+
+// SpotifyEdit changes the details of a Spotify playlist, leaving the fields without a flag as they are.
+func (r *Runner) SpotifyEdit(ctx context.Context, cmd *cli.Command) error {
+	spotifySvc, err := r.spotifyLibrary()
+	if err != nil {
+		return err
+	}
+
+	playlistID := cmd.StringArg("playlist-id")
+	if playlistID == "" {
+		return fmt.Errorf("%w: playlist ID is required", shared.ErrMissingArgument)
+	}
+	update, err := playlistUpdate(cmd)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("editing spotify playlist", "id", playlistID)
+	if err := r.withSpotifyReauth(ctx, cmd, func() error {
+		return spotifySvc.ChangePlaylistDetails(ctx, playlistID, update)
+	}); err != nil {
+		return err
+	}
+
+	r.writePlaylistUpdate(playlistID, update)
+	return nil
+}
+
+func (r *Runner) SpotifyAlbums(ctx context.Context, cmd *cli.Command) error {
+	limit := int(cmd.Int("limit"))
+	ids := splitIDs(cmd.String("ids"))
+
+	spotifySvc, err := r.spotifyLibrary()
+	if err != nil {
+		return err
+	}
+
+	var albums []services.SpotifyAlbum
+	if len(ids) > 0 {
+		r.logger.Infof("looking up %d spotify albums", len(ids))
+		err = r.withSpotifyReauth(ctx, cmd, func() (err error) {
+			albums, err = spotifySvc.SeveralAlbums(ctx, ids)
+			return err
+		})
+	} else {
+		r.logger.Infof("listing saved spotify albums with limit %v", limit)
+		err = r.withSpotifyReauth(ctx, cmd, func() error {
+			albums = nil
+			for limit <= 0 || len(albums) < limit {
+				page, err := spotifySvc.SavedAlbums(ctx, 50, len(albums))
+				if err != nil {
+					return err
+				}
+				for _, item := range page.Items {
+					albums = append(albums, item.Album)
+				}
+				if page.Next == nil || len(page.Items) == 0 {
+					break
 				}
-				return pl.Images[0].URL, nil
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if limit > 0 && limit < len(albums) {
+		albums = albums[:limit]
+	}
+
+	if cmd.Bool("json") {
+		return r.writeJSON(albums, cmd.Bool("pretty"))
+	}
+
+	r.writePlain("Found %d albums:\n\n", len(albums))
+	for i, album := range albums {
+		r.writePlain("%d. %s\n", i+1, album.Name)
+		if names := spotifyArtistNames(album.Artists); names != "" {
+			r.writePlain("   Artists: %s\n", names)
+		}
+		r.writePlain("   ID: %s\n", album.ID)
+		if album.ReleaseDate != "" {
+			r.writePlain("   Released: %s\n", album.ReleaseDate)
+		}
+		r.writePlain("   Tracks: %d\n", album.TotalTracks)
+		if album.Tracks != nil {
+			for j, track := range album.Tracks.Items {
+				r.writePlain("     %d. %s\n", j+1, track.Name)
 			}
 		}
+		r.writePlain("\n")
 	}
 
-	go func() {
-		result, err := r.engine.BulkExport(ctx, progress, r.spotify, playlistIDs, tasks.BulkExportOpts{
-			Format:        format,
-			OutputDir:     outputDir,
-			NumWorkers:    workers,
-			RateLimit:     rateLimit,
-			GetCoverImage: getCoverImage,
+	return nil
+}
+
+// SpotifyArtists lists artists the user follows, or looks up artists by ID.
+func (r *Runner) SpotifyArtists(ctx context.Context, cmd *cli.Command) error {
+	limit := int(cmd.Int("limit"))
+	ids := splitIDs(cmd.String("ids"))
+
+	spotifySvc, err := r.spotifyLibrary()
+	if err != nil {
+		return err
+	}
+
+	var artists []services.SpotifyArtist
+	if len(ids) > 0 {
+		r.logger.Infof("looking up %d spotify artists", len(ids))
+		err = r.withSpotifyReauth(ctx, cmd, func() (err error) {
+			artists, err = spotifySvc.SeveralArtists(ctx, ids)
+			return err
 		})
-		if err != nil {
-			errs <- err
-			return
-		}
-		done <- result
-	}()
-
-	for {
-		select {
-		case update := <-progress:
-			r.writePlain("%s\n", update.Message)
-		case result := <-done:
-			r.writePlain("\n")
-			r.writePlain("✓ Bulk export complete!\n")
-			r.writePlain("  Total playlists: %d\n", result.TotalPlaylists)
-			r.writePlain("  Successful: %d\n", result.SuccessfulExports)
-			r.writePlain("  Failed: %d\n", result.FailedExports)
-			r.writePlain("  Output directory: %s\n", result.OutputDirectory)
-			r.writePlain("  Manifest: %s\n\n", result.ManifestPath)
-
-			if result.FailedExports > 0 {
-				r.writePlain("Failed exports:\n")
-				for _, res := range result.Results {
-					if !res.Success {
-						r.writePlain("  ✗ %s: %v\n", res.PlaylistName, res.Error)
-					}
+	} else {
+		r.logger.Infof("listing followed spotify artists with limit %v", limit)
+		err = r.withSpotifyReauth(ctx, cmd, func() error {
+			artists = nil
+			after := ""
+			for limit <= 0 || len(artists) < limit {
+				page, err := spotifySvc.FollowedArtists(ctx, 50, after)
+				if err != nil {
+					return err
+				}
+				artists = append(artists, page.Items...)
+				if after = page.Cursors.After; after == "" || len(page.Items) == 0 {
+					break
 				}
 			}
+			return nil
+		})
+	}
+	if err != nil {
+		return err
+	}
 
-			if result.SuccessfulExports == 0 {
-				return fmt.Errorf("all exports failed")
-			}
+	if limit > 0 && limit < len(artists) {
+		artists = artists[:limit]
+	}
 
-			if result.FailedExports > 0 {
-				return fmt.Errorf("partial success: %d/%d exports failed", result.FailedExports, result.TotalPlaylists)
-			}
+	if cmd.Bool("json") {
+		return r.writeJSON(artists, cmd.Bool("pretty"))
+	}
 
-			return nil
-		case err := <-errs:
-			return fmt.Errorf("bulk export failed: %w", err)
-		case <-ctx.Done():
-			return ctx.Err()
+	r.writePlain("Found %d artists:\n\n", len(artists))
+	for i, artist := range artists {
+		r.writePlain("%d. %s\n", i+1, artist.Name)
+		r.writePlain("   ID: %s\n", artist.ID)
+		if len(artist.Genres) > 0 {
+			r.writePlain("   Genres: %s\n", strings.Join(artist.Genres, ", "))
 		}
+		if artist.Followers != nil {
+			r.writePlain("   Followers: %d\n", artist.Followers.Total)
+		}
+		r.writePlain("\n")
+	}
+
+	return nil
+}
+
+// spotifyArtistNames joins artist names for display.
+func spotifyArtistNames(artists []services.SpotifyArtist) string {
+	names := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		names = append(names, artist.Name)
 	}
+	return strings.Join(names, ", ")
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// Stats reports analytics aggregated from the migration history and local cache.
+//
+// Only transfers run with --cache are recorded in the migration history.
+func (r *Runner) Stats(ctx context.Context, cmd *cli.Command) error {
+	opts := repositories.AnalyticsOpts{Days: cmd.Int("days"), TopArtists: cmd.Int("top")}
+	if opts.Days < 0 || opts.TopArtists < 0 {
+		return fmt.Errorf("%w: --days and --top cannot be negative", shared.ErrInvalidFlag)
+	}
+
+	db, err := r.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	queryCtx, cancel := r.queryContext(ctx)
+	analytics, err := repositories.CollectAnalytics(queryCtx, db, opts)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("json") {
+		return r.writeJSON(analytics, true)
+	}
+
+	r.writePlainHeader("Migrations")
+	r.writePlain("Total: %d (%d completed, %d failed)\n", analytics.Migrations, analytics.Completed, analytics.Failed)
+	r.writePlain("Tracks migrated: %d/%d (%.1f%%)\n", analytics.TracksMigrated, analytics.TracksTotal, analytics.MatchRate)
+	r.writePlain("Tracks unmatched: %d\n", analytics.TracksFailed)
+	r.writePlain("Average duration: %s\n", time.Duration(analytics.AverageDuration*float64(time.Second)).Round(time.Second))
+	r.writePlain("Cached searches: %d\n\n", analytics.CachedSearches)
+
+	if analytics.Migrations == 0 {
+		r.writePlain("No migrations recorded yet. Run 'ytx transfer run --cache' to record transfers.\n\n")
+	}
+
+	if len(analytics.MatchRateByDay) > 0 {
+		r.writePlainHeader("Match Rate by Day")
+		r.writePlain("  %-12s %10s %10s %10s %8s\n", "DATE", "MIGRATIONS", "TRACKS", "MATCHED", "RATE")
+		for _, day := range analytics.MatchRateByDay {
+			r.writePlain("  %-12s %10d %10d %10d %7.1f%%\n", day.Date, day.Migrations, day.TracksTotal, day.TracksMigrated, day.MatchRate)
+		}
+		r.writePlain("\n")
+	}
+
+	if len(analytics.MostFailedArtists) > 0 {
+		r.writePlainHeader("Most Failed Artists")
+		r.writePlain("  %-32s %8s\n", "ARTIST", "FAILURES")
+		for _, artist := range analytics.MostFailedArtists {
+			r.writePlain("  %-32s %8d\n", artist.Artist, artist.Failures)
+		}
+		r.writePlain("\n")
+	}
+
+	if len(analytics.Services) > 0 {
+		r.writePlainHeader("Services")
+		r.writePlain("  %-10s %10s %10s %10s %10s %10s %10s\n", "SERVICE", "PLAYLISTS", "TRACKS", "ALBUMS", "ARTISTS", "FROM", "TO")
+		for _, service := range analytics.Services {
+			r.writePlain("  %-10s %10d %10d %10d %10d %10d %10d\n",
+				service.Service, service.Playlists, service.Tracks, service.Albums, service.Artists, service.MigrationsFrom, service.MigrationsTo)
+		}
+	}
+
+	return nil
+}
+
+// statsCommand reports analytics over the migration history and local cache
+func statsCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Show migration and cache analytics",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "days",
+				Usage: "Number of recent days in the match rate trend (0 for all)",
+				Value: 30,
+			},
+			&cli.IntFlag{
+				Name:  "top",
+				Usage: "Number of most failed artists to show (0 for all)",
+				Value: 10,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output analytics as JSON",
+			},
+		},
+		Action: r.Stats,
+	}
+}
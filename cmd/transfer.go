@@ -1,10 +1,15 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desertthunder/ytx/internal/formatter"
+	"github.com/desertthunder/ytx/internal/models"
 	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
 	"github.com/desertthunder/ytx/internal/tasks"
@@ -12,102 +17,240 @@ import (
 	"github.com/urfave/cli/v3"
 )
 
-// TransferRun runs a full Spotify → YouTube Music sync.
+// TransferRun runs a full playlist sync, Spotify → YouTube Music unless --from, --to, or --to-profile choose other
+// accounts.
 func (r *Runner) TransferRun(ctx context.Context, cmd *cli.Command) error {
 	sourceID := cmd.String("source")
 
 	r.logger.Infof("starting transfer from source: %v", sourceID)
 
+	opts := tasks.TransferOpts{
+		Name:             cmd.String("name"),
+		Description:      cmd.String("description"),
+		CopyCover:        cmd.Bool("cover"),
+		Split:            cmd.Bool("split"),
+		MaxDurationDelta: cmd.Duration("max-duration-delta"),
+	}
+	explicit, err := tasks.ParseExplicitPreference(cmd.String("prefer"))
+	if err != nil {
+		return err
+	}
+	opts.Explicit = explicit
+	opts.FlagUnavailable = cmd.Bool("flag-unavailable")
+	if path := cmd.String("overrides"); path != "" {
+		overrides, err := tasks.LoadTrackOverrides(path)
+		if err != nil {
+			return err
+		}
+		opts.Overrides = overrides
+	}
+	if opts.Source, opts.Dest, err = r.transferAccounts(ctx, cmd); err != nil {
+		return err
+	}
+
+	if cmd.Bool("cache") {
+		_, closeCache, err := r.enableCaching(ctx)
+		if err != nil {
+			return err
+		}
+		defer closeCache()
+	}
+
+	closeIgnoreList, err := r.enableIgnoreList(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeIgnoreList()
+
 	r.writePlain("Starting playlist transfer...\n")
-	r.writePlain("Source: %s\n\n", sourceID)
-
-	progressCh := make(chan tasks.ProgressUpdate, 50)
-	go func() {
-		for update := range progressCh {
-			switch update.Phase {
-			case tasks.FetchSource:
-				r.writePlain("📥 %s\n", update.Message)
-			case tasks.SearchTracks:
-				if update.Step == 0 {
-					r.writePlainln("🔍 %s", update.Message)
-				} else {
-					r.writePlain("   %s\n", update.Message)
-				}
-			case tasks.CreatePlaylist:
-				r.writePlainln("📝 %s", update.Message)
+	r.writePlain("Source: %s\n", sourceID)
+	if profile := cmd.String("to-profile"); profile != "" {
+		r.writePlain("Destination account: profile %s\n", profile)
+	}
+	r.writePlain("\n")
+
+	progressCh, finishProgress, err := r.reportProgress(cmd, 50, func(update tasks.ProgressUpdate) {
+		switch update.Phase {
+		case tasks.FetchSource:
+			r.writePlain("📥 %s\n", update.Message)
+		case tasks.SearchTracks:
+			if update.Step == 0 {
+				r.writePlainln("🔍 %s", update.Message)
+			} else {
+				r.writePlain("   %s\n", update.Message)
 			}
+		case tasks.CreatePlaylist:
+			r.writePlainln("📝 %s", update.Message)
+		case tasks.RateLimited:
+			r.writePlain("⏸  %s\n", update.Message)
 		}
-	}()
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := r.engine.RunWithOpts(ctx, sourceID, opts, progressCh)
+	finishProgress()
 
-	result, err := r.engine.Run(ctx, sourceID, progressCh)
-	close(progressCh)
+	// The report is also written for failed transfers, which still list the tracks searched so far
+	var reportPath string
+	if path := cmd.String("report"); path != "" && result != nil {
+		written, reportErr := formatter.WriteTransferReport(result.Report(time.Now()), path)
+		if reportErr != nil {
+			r.logger.Warnf("failed to write transfer report: %v", reportErr)
+		}
+		reportPath = written
+	}
 
 	if err != nil {
+		if result != nil && result.Cancelled {
+			r.writePlainHeader("Transfer Cancelled")
+			r.writePlain("Searched: %d of %d tracks\n", len(result.TrackMatches), result.TotalTracks)
+			r.writePlain("Matched: %d, failed: %d\n", result.SuccessCount, result.FailedCount)
+			r.writePlain("No playlist was created.\n")
+		}
+		var batchErr *services.BatchAddError
+		if result != nil && result.DestPlaylist != nil && errors.As(err, &batchErr) {
+			r.writePlainHeader("Transfer Incomplete")
+			r.writePlain("Destination: %s (ID: %s)\n", result.DestPlaylist.Name, result.DestPlaylist.ID)
+			r.writePlain("Added: %d of %d tracks\n", batchErr.Added, batchErr.Total)
+			for _, batch := range batchErr.Failed {
+				r.writePlain("  ✗ tracks %d-%d: %v\n", batch.Start+1, batch.End, batch.Err)
+			}
+		}
+		if reportPath != "" {
+			r.writePlain("Report: %s\n", reportPath)
+		}
 		return err
 	}
 
 	r.writePlainHeader("Transfer Complete!")
 	r.writePlain("Source: %s (%d tracks)\n", result.SourcePlaylist.Playlist.Name, result.TotalTracks)
-	r.writePlain("Destination: %s (%d tracks)\n", result.DestPlaylist.Name, result.DestPlaylist.TrackCount)
-	r.writePlain("Success rate: %d/%d (%.1f%%)\n", result.SuccessCount, result.TotalTracks, result.MatchPercentage)
+	if len(result.DestParts) > 0 {
+		r.writePlain("Destination: split into %d playlists\n", len(result.DestParts))
+		for _, part := range result.DestParts {
+			r.writePlain("  - %s (%d tracks)\n", part.Name, part.TrackCount)
+		}
+	} else {
+		r.writePlain("Destination: %s (%d tracks)\n", result.DestPlaylist.Name, result.DestPlaylist.TrackCount)
+	}
+	r.writePlain("Success rate: %d/%d (%.1f%%)\n", result.SuccessCount, result.MatchableTracks(), result.MatchPercentage)
+	if result.SkippedCount > 0 {
+		r.writePlain("Skipped (unmatchable): %d\n", result.SkippedCount)
+	}
+	if result.CachedSearches > 0 {
+		r.writePlain("Cached searches: %d\n", result.CachedSearches)
+	}
+	if result.Overridden > 0 {
+		r.writePlain("Overridden matches: %d\n", result.Overridden)
+	}
+	if len(result.Ignored) > 0 {
+		r.writePlain("Ignored tracks: %d (see 'ytx ignore list')\n", len(result.Ignored))
+	}
+	if result.MigrationID != "" {
+		r.writePlain("Migration: %s (see 'ytx history')\n", result.MigrationID)
+	}
+	r.writePlain("Time: %s\n", result.Timing.Summary())
+	if result.CoverCopied {
+		r.writePlain("Cover image: copied\n")
+	} else if result.CoverError != nil {
+		r.writePlain("⚠ Cover image not copied: %v\n", result.CoverError)
+	}
+	for _, warning := range result.Warnings {
+		r.writePlain("⚠ %s\n", warning)
+	}
 
 	if result.FailedCount > 0 {
 		r.writePlainln("Failed to match %d tracks:", result.FailedCount)
 		for _, match := range result.TrackMatches {
-			if match.Error != nil {
-				r.writePlain("  - %s - %s\n", match.Original.Artist, match.Original.Title)
+			if errors.Is(match.Error, shared.ErrTrackUnavailable) {
+				r.writePlain("  - %s - %s (unavailable in this region)\n", match.Original.Artist(), match.Original.Title)
+			} else if match.Error != nil {
+				r.writePlain("  - %s - %s\n", match.Original.Artist(), match.Original.Title)
+			}
+		}
+	}
+
+	if result.SkippedCount > 0 {
+		r.writePlainln("Skipped %d unmatchable tracks:", result.SkippedCount)
+		for _, match := range result.TrackMatches {
+			if match.Skipped != "" {
+				r.writePlain("  - %s - %s (skipped: %s)\n", match.Original.Artist(), match.Original.Title, match.Skipped)
 			}
 		}
 	}
 
+	if reportPath != "" {
+		r.writePlainln("Report: %s", reportPath)
+	}
+
 	return nil
 }
 
 // TransferDiff compares and shows missing tracks between two playlists.
+//
+// Either side can be an export file instead of a live playlist, in which case its service is not needed.
 func (r *Runner) TransferDiff(ctx context.Context, cmd *cli.Command) error {
-	sourceID := cmd.String("source-id")
-	destID := cmd.String("dest-id")
-	sourceService := cmd.String("source-service")
-	destService := cmd.String("dest-service")
+	offline := cmd.Bool("offline")
+
+	source, err := r.diffTarget(cmd, "source", offline)
+	if err != nil {
+		return err
+	}
+	dest, err := r.diffTarget(cmd, "dest", offline)
+	if err != nil {
+		return err
+	}
 
-	r.logger.Infof("transfer diff requested source: %v dest %v", sourceID, destID)
+	r.logger.Infof("transfer diff requested source: %v dest %v", cmp.Or(source.PlaylistID, cmd.String("source-file")), cmp.Or(dest.PlaylistID, cmd.String("dest-file")))
 	r.writePlain("Comparing playlists...\n\n")
 
-	srcService, err := r.resolveService(sourceService)
+	closeIgnoreList, err := r.enableIgnoreList(ctx)
 	if err != nil {
 		return err
 	}
-	dstService, err := r.resolveService(destService)
+	defer closeIgnoreList()
+
+	progressCh, finishProgress, err := r.reportProgress(cmd, 10, func(update tasks.ProgressUpdate) {
+		r.writePlain("📥 %s\n", update.Message)
+	})
 	if err != nil {
 		return err
 	}
 
-	progressCh := make(chan tasks.ProgressUpdate, 10)
-	go func() {
-		for update := range progressCh {
-			r.writePlain("📥 %s\n", update.Message)
-		}
-	}()
-
-	result, err := r.engine.Diff(ctx, srcService, dstService, sourceID, destID, progressCh)
-	close(progressCh)
+	var result *tasks.TransferDiffResult
+	if offline {
+		result, err = r.offlineDiff(ctx, progressCh, source, dest)
+	} else {
+		result, err = r.engine.DiffTargets(ctx, source, dest, progressCh)
+	}
+	finishProgress()
 
 	if err != nil {
 		return err
 	}
 
+	if offline {
+		r.writePlain("\nSource loaded from %s, destination loaded from %s\n", diffOrigin(source, result.SourceCached), diffOrigin(dest, result.DestCached))
+	}
+
 	r.writePlainln("✓ Source: %s (%d tracks)", result.Comparison.SourcePlaylist.Playlist.Name, len(result.Comparison.SourcePlaylist.Tracks))
 	r.writePlain("✓ Destination: %s (%d tracks)\n\n", result.Comparison.DestPlaylist.Playlist.Name, len(result.Comparison.DestPlaylist.Tracks))
 
 	r.writePlainHeader("Comparison Results")
 	r.writePlain("Matched: %d tracks\n", result.Comparison.MatchedCount)
 	r.writePlain("Missing from destination: %d tracks\n", len(result.Comparison.MissingInDest))
-	r.writePlain("Extra in destination: %d tracks\n\n", len(result.Comparison.ExtraInDest))
+	r.writePlain("Extra in destination: %d tracks\n", len(result.Comparison.ExtraInDest))
+	if len(result.Comparison.Ignored) > 0 {
+		r.writePlain("Ignored: %d tracks\n", len(result.Comparison.Ignored))
+	}
+	r.writePlain("Time: %s\n", result.Timing.Summary())
+	r.writePlain("\n")
 
 	if len(result.Comparison.MissingInDest) > 0 {
 		r.writePlain("Missing from destination:\n")
 		for i, track := range result.Comparison.MissingInDest {
-			r.writePlain("  %d. %s - %s", i+1, track.Artist, track.Title)
+			r.writePlain("  %d. %s - %s", i+1, track.Artist(), track.Title)
 			if track.Album != "" {
 				r.writePlain(" (%s)", track.Album)
 			}
@@ -119,7 +262,7 @@ func (r *Runner) TransferDiff(ctx context.Context, cmd *cli.Command) error {
 	if len(result.Comparison.ExtraInDest) > 0 {
 		r.writePlain("Extra in destination (not in source):\n")
 		for i, track := range result.Comparison.ExtraInDest {
-			r.writePlain("  %d. %s - %s", i+1, track.Artist, track.Title)
+			r.writePlain("  %d. %s - %s", i+1, track.Artist(), track.Title)
 			if track.Album != "" {
 				r.writePlain(" (%s)", track.Album)
 			}
@@ -130,6 +273,159 @@ func (r *Runner) TransferDiff(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
+// offlineDiff compares playlists from the local cache, fetching live only for playlists that aren't cached.
+func (r *Runner) offlineDiff(ctx context.Context, progress chan<- tasks.ProgressUpdate, source, dest tasks.DiffTarget) (*tasks.TransferDiffResult, error) {
+	store, closeCache, err := r.enableCaching(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCache()
+
+	return r.engine.DiffOffline(ctx, store, source, dest, progress)
+}
+
+// TransferMerge compares two playlists with their last stored snapshots and, unless --dry-run is set, copies each
+// side's additions and removals to the other.
+func (r *Runner) TransferMerge(ctx context.Context, cmd *cli.Command) error {
+	dryRun := cmd.Bool("dry-run")
+
+	var targets [2]tasks.DiffTarget
+	for i, side := range []string{"source", "dest"} {
+		name := cmd.String(side + "-service")
+		service, err := r.resolveService(name)
+		if err != nil {
+			return err
+		}
+		targets[i] = tasks.DiffTarget{ServiceKey: serviceKey(name), PlaylistID: cmd.String(side + "-id"), Service: service}
+	}
+	source, dest := targets[0], targets[1]
+
+	r.logger.Info("merge requested", "source", source.PlaylistID, "dest", dest.PlaylistID, "dry_run", dryRun)
+
+	closeIgnoreList, err := r.enableIgnoreList(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeIgnoreList()
+
+	store, closeCache, err := r.enableCaching(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	progressCh, finishProgress, err := r.reportProgress(cmd, 10, func(update tasks.ProgressUpdate) {
+		r.writePlain("📥 %s\n", update.Message)
+	})
+	if err != nil {
+		return err
+	}
+
+	var threeWay *tasks.ThreeWayResult
+	var merge *tasks.MergeResult
+	if dryRun {
+		threeWay, err = r.engine.ThreeWayDiff(ctx, store, source, dest, progressCh)
+	} else if merge, err = r.engine.Merge(ctx, store, source, dest, progressCh); merge != nil {
+		threeWay = merge.ThreeWay
+	}
+	finishProgress()
+
+	if threeWay != nil {
+		r.writePlain("\n")
+		r.writePlainHeader("Changes since the last snapshot")
+		r.writeTrackList("Added on source", threeWay.AddedOnSource)
+		r.writeTrackList("Removed on source", threeWay.RemovedOnSource)
+		r.writeTrackList("Added on destination", threeWay.AddedOnDest)
+		r.writeTrackList("Removed on destination", threeWay.RemovedOnDest)
+		r.writeTrackList("Added on both (in sync)", threeWay.AddedOnBoth)
+		r.writeTrackList("Removed on both (in sync)", threeWay.RemovedOnBoth)
+		if threeWay.InSync() {
+			r.writePlain("✓ Playlists are in sync\n")
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if merge != nil && !threeWay.InSync() {
+		r.writePlainHeader("Merge")
+		for _, push := range []struct {
+			label  string
+			result *tasks.PushResult
+		}{{"destination", merge.ToDest}, {"source", merge.ToSource}} {
+			if push.result != nil {
+				r.writePlain("Added to %s: %d tracks (%d not found, %d skipped)\n", push.label, push.result.AddedCount, push.result.FailedCount, push.result.SkippedCount)
+			}
+		}
+		r.writePlain("Removed from destination: %d tracks\n", len(merge.RemovedFromDest))
+		r.writePlain("Removed from source: %d tracks\n", len(merge.RemovedFromSource))
+	}
+	return nil
+}
+
+// writeTrackList prints a labelled, numbered list of tracks, or nothing when there are none
+func (r *Runner) writeTrackList(label string, tracks []models.Track) {
+	if len(tracks) == 0 {
+		return
+	}
+	r.writePlain("%s (%d):\n", label, len(tracks))
+	for i, track := range tracks {
+		r.writePlain("  %d. %s - %s\n", i+1, track.Artist(), track.Title)
+	}
+	r.writePlain("\n")
+}
+
+// diffTarget builds one side of a diff from its --<side>-id or --<side>-file flag, exactly one of which must be set.
+//
+// An export file is read up front; otherwise the side's service must be available unless the diff is offline.
+func (r *Runner) diffTarget(cmd *cli.Command, side string, offline bool) (tasks.DiffTarget, error) {
+	id, file := cmd.String(side+"-id"), cmd.String(side+"-file")
+	switch {
+	case id == "" && file == "":
+		return tasks.DiffTarget{}, fmt.Errorf("%w: --%s-id or --%s-file is required", shared.ErrMissingArgument, side, side)
+	case id != "" && file != "":
+		return tasks.DiffTarget{}, fmt.Errorf("%w: --%s-id and --%s-file cannot be used together", shared.ErrInvalidArgument, side, side)
+	}
+
+	name := cmd.String(side + "-service")
+	target := tasks.DiffTarget{ServiceKey: serviceKey(name), PlaylistID: id}
+	if file != "" {
+		export, err := formatter.ReadExport(file)
+		if err != nil {
+			return tasks.DiffTarget{}, err
+		}
+		target.Export = export
+		return target, nil
+	}
+
+	service, err := r.resolveService(name)
+	if err != nil && !offline {
+		return tasks.DiffTarget{}, err
+	}
+	target.Service = service
+	return target, nil
+}
+
+// diffOrigin describes where an offline diff loaded a playlist from.
+func diffOrigin(target tasks.DiffTarget, cached bool) string {
+	switch {
+	case target.Export != nil:
+		return "export file"
+	case cached:
+		return "cache"
+	default:
+		return "live API"
+	}
+}
+
+// serviceKey maps a service flag value to the service name used by the cache.
+func serviceKey(name string) string {
+	if name == "ytmusic" {
+		return "youtube"
+	}
+	return name
+}
+
 // TransferUI launches the interactive TUI for playlist transfer.
 func (r *Runner) TransferUI(ctx context.Context, cmd *cli.Command) error {
 	if r.spotify == nil {
@@ -139,7 +435,7 @@ func (r *Runner) TransferUI(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("%w: transfer engine not initialized", shared.ErrServiceUnavailable)
 	}
 
-	model := ui.NewModel(ctx, r.spotify, r.engine)
+	model := ui.NewModel(ctx, r.spotify, r.youtube, r.engine)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -149,6 +445,49 @@ func (r *Runner) TransferUI(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
+// transferAccounts resolves the accounts of a transfer from --from and --to, with the destination signed in to
+// the --to-profile account when one is given. Both are nil for the default Spotify → YouTube Music route.
+//
+// Transfers within one provider, e.g. Spotify → Spotify, need --to-profile naming a different account.
+func (r *Runner) transferAccounts(ctx context.Context, cmd *cli.Command) (source, dest *tasks.TransferAccount, err error) {
+	from, to, profile := cmd.String("from"), cmd.String("to"), cmd.String("to-profile")
+	if !cmd.IsSet("from") && !cmd.IsSet("to") && profile == "" {
+		return nil, nil, nil
+	}
+
+	fromKey, toKey := serviceKey(from), serviceKey(to)
+	if fromKey == toKey && (profile == "" || profile == r.profile) {
+		return nil, nil, fmt.Errorf("%w: transferring %s → %s needs --to-profile naming another account", shared.ErrInvalidArgument, from, to)
+	}
+
+	sourceService, err := r.resolveService(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	destService, err := r.resolveService(to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if profile != "" && profile != r.profile {
+		config, err := r.fileConfig.WithProfile(profile)
+		if err != nil {
+			return nil, nil, err
+		}
+		spotify, youtube, _ := r.newServices(ctx, config, profile)
+		destService = youtube
+		if toKey == "spotify" {
+			if spotify == nil {
+				return nil, nil, fmt.Errorf("%w: profile '%s' has no Spotify credentials", shared.ErrServiceUnavailable, profile)
+			}
+			destService = spotify
+		}
+	}
+
+	return &tasks.TransferAccount{ServiceKey: fromKey, Service: sourceService},
+		&tasks.TransferAccount{ServiceKey: toKey, Service: destService}, nil
+}
+
 // resolveService resolves a service name to its corresponding Service instance.
 func (r *Runner) resolveService(name string) (services.Service, error) {
 	switch name {
@@ -166,3 +505,74 @@ func (r *Runner) resolveService(name string) (services.Service, error) {
 		return nil, fmt.Errorf("%w: invalid service '%s' (must be 'spotify' or 'youtube')", shared.ErrInvalidArgument, name)
 	}
 }
+
+// TransferSplit divides a playlist into several new playlists on the same service by artist, release decade, or
+// size, or with --dry-run lists the playlists it would create.
+func (r *Runner) TransferSplit(ctx context.Context, cmd *cli.Command) error {
+	if r.engine == nil {
+		return fmt.Errorf("%w: Playlist engine not initialized", shared.ErrServiceUnavailable)
+	}
+
+	by, err := tasks.ParseSplitRule(cmd.String("by"))
+	if err != nil {
+		return err
+	}
+	opts := tasks.SplitOpts{
+		By:        by,
+		MaxTracks: int(cmd.Int("max-tracks")),
+		MinTracks: int(cmd.Int("min-tracks")),
+		DryRun:    cmd.Bool("dry-run"),
+	}
+	switch {
+	case opts.MaxTracks < 0 || opts.MinTracks < 0:
+		return fmt.Errorf("%w: --max-tracks and --min-tracks cannot be negative", shared.ErrInvalidArgument)
+	case by == "" && opts.MaxTracks == 0:
+		return fmt.Errorf("%w: --by or --max-tracks is required", shared.ErrMissingArgument)
+	}
+
+	service, err := r.resolveService(cmd.String("service"))
+	if err != nil {
+		return err
+	}
+	playlistID := cmd.String("id")
+
+	r.logger.Info("split requested", "playlist", playlistID, "by", by, "max_tracks", opts.MaxTracks, "dry_run", opts.DryRun)
+
+	if !opts.DryRun {
+		// Created playlists are cached like exported ones
+		if _, closeCache, err := r.enableCaching(ctx); err != nil {
+			r.logger.Warnf("split playlists won't be cached: %v", err)
+		} else {
+			defer closeCache()
+		}
+	}
+
+	progressCh, finishProgress, err := r.reportProgress(cmd, 10, func(update tasks.ProgressUpdate) {
+		r.writePlain("📥 %s\n", update.Message)
+	})
+	if err != nil {
+		return err
+	}
+	result, err := r.engine.Split(ctx, service, playlistID, opts, progressCh)
+	finishProgress()
+
+	if result != nil {
+		r.writePlain("\n")
+		header := fmt.Sprintf("Splitting %s (%d tracks) into %d playlists", result.Source.Playlist.Name, len(result.Source.Tracks), len(result.Partitions))
+		if opts.DryRun {
+			header += " (dry run)"
+		}
+		r.writePlainHeader(header)
+		for _, partition := range result.Partitions {
+			switch {
+			case opts.DryRun:
+				r.writePlain("  • %s: %d tracks\n", partition.Name, len(partition.Tracks))
+			case partition.Playlist != nil:
+				r.writePlain("  ✓ %s: %d tracks (ID: %s)\n", partition.Name, len(partition.Tracks), partition.Playlist.ID)
+			default:
+				r.writePlain("  ✗ %s: %d tracks not created\n", partition.Name, len(partition.Tracks))
+			}
+		}
+	}
+	return err
+}
@@ -20,13 +20,13 @@ func (r *Runner) TUI(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	// Redirect logs to file to avoid interfering with TUI rendering
-	fileLogger, err := shared.NewFileLogger("./tmp/ytx-tui.log")
-	if err != nil {
-		return fmt.Errorf("failed to create file logger: %w", err)
+	if r.logging != nil && r.config.Logging.File == "" {
+		if err := r.logging.SetFile("./tmp/ytx-tui.log"); err != nil {
+			return fmt.Errorf("failed to create file logger: %w", err)
+		}
 	}
-	r.SetLogger(fileLogger)
 
-	model := ui.NewModel(ctx, r.spotify, r.engine)
+	model := ui.NewModel(ctx, r.spotify, r.youtube, r.engine)
 	p := tea.NewProgram(model)
 
 	if _, err := p.Run(); err != nil {
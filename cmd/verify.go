@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/desertthunder/ytx/internal/formatter"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/urfave/cli/v3"
+)
+
+// Verify checks an export directory against the sizes and checksums in its manifest.
+func (r *Runner) Verify(ctx context.Context, cmd *cli.Command) error {
+	dir := cmd.Args().First()
+	if dir == "" {
+		return fmt.Errorf("%w: export directory", shared.ErrMissingArgument)
+	}
+
+	result, err := formatter.VerifyExport(dir)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("json") {
+		if err := r.writeJSON(result, true); err != nil {
+			return err
+		}
+	} else {
+		symbols := map[string]string{
+			formatter.FileOK:       "✓",
+			formatter.FileMissing:  "✗",
+			formatter.FileModified: "✗",
+			formatter.FileUnlisted: "?",
+		}
+		r.writePlainHeader(fmt.Sprintf("Verifying %s (manifest schema v%d)", dir, result.SchemaVersion))
+		matched := 0
+		for _, file := range result.Files {
+			if file.Status == formatter.FileOK {
+				matched++
+			}
+			line := fmt.Sprintf("  %s %-9s %s", symbols[file.Status], file.Status, file.Path)
+			if file.Detail != "" {
+				line += " (" + file.Detail + ")"
+			}
+			r.writePlain("%s\n", line)
+		}
+		if result.Failed == 0 {
+			r.writePlain("\n✓ All %d files match the manifest\n", matched)
+		}
+	}
+
+	if result.Failed > 0 {
+		listed := 0
+		for _, file := range result.Files {
+			if file.Status != formatter.FileUnlisted {
+				listed++
+			}
+		}
+		return fmt.Errorf("%w: %d of %d files in %s are missing or differ from the manifest", shared.ErrInvalidInput, result.Failed, listed, dir)
+	}
+	return nil
+}
+
+// verifyCommand checks export directories against their manifests
+func verifyCommand(r *Runner) *cli.Command {
+	return &cli.Command{
+		Name:      "verify",
+		Usage:     "Check an export directory's files against the sizes and SHA-256 checksums in its manifest",
+		ArgsUsage: "<dir>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output results as JSON",
+			},
+		},
+		Action: r.Verify,
+	}
+}
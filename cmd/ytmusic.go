@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
 	"github.com/urfave/cli/v3"
 )
@@ -32,8 +34,8 @@ func (r *Runner) YTMusicSearch(ctx context.Context, cmd *cli.Command) error {
 
 	r.writePlain("Found track:\n\n")
 	r.writePlain("Title: %s\n", track.Title)
-	if track.Artist != "" {
-		r.writePlain("Artist: %s\n", track.Artist)
+	if track.Artist() != "" {
+		r.writePlain("Artist: %s\n", track.Artist())
 	}
 	if track.Album != "" {
 		r.writePlain("Album: %s\n", track.Album)
@@ -113,7 +115,7 @@ func (r *Runner) YTMusicAdd(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("%w: failed to find track: %v", shared.ErrTrackNotFound, err)
 	}
 
-	r.logger.Info("found track", "id", track.ID, "title", track.Title, "artist", track.Artist)
+	r.logger.Info("found track", "id", track.ID, "title", track.Title, "artist", track.Artist())
 
 	playlist, err := r.youtube.GetPlaylist(ctx, playlistID)
 	if err != nil {
@@ -134,7 +136,261 @@ func (r *Runner) YTMusicAdd(ctx context.Context, cmd *cli.Command) error {
 	r.logger.Info("track added successfully")
 	r.writePlain("✓ Track added to playlist\n")
 	r.writePlain("Playlist: %s (ID: %s)\n", playlist.Name, playlistID)
-	r.writePlain("Added: %s - %s\n", track.Artist, track.Title)
+	r.writePlain("Added: %s - %s\n", track.Artist(), track.Title)
 
 	return nil
 }
+
+// youtubeLibrary is the part of [services.YouTubeService] that reads listening data
+type youtubeLibrary interface {
+	History(ctx context.Context) ([]models.Track, error)
+	LikedSongs(ctx context.Context) ([]models.Track, error)
+}
+
+// YTMusicHistory exports the listening history, optionally limited to a date range.
+func (r *Runner) YTMusicHistory(ctx context.Context, cmd *cli.Command) error {
+	library, err := r.youtubeLibrary()
+	if err != nil {
+		return err
+	}
+
+	since, err := parseDateFlag(cmd, "since")
+	if err != nil {
+		return err
+	}
+	until, err := parseDateFlag(cmd, "until")
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		until = until.AddDate(0, 0, 1) // Inclusive of the whole day
+	}
+
+	r.logger.Info("fetching youtube music history", "since", cmd.String("since"), "until", cmd.String("until"))
+
+	tracks, err := library.History(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+	}
+	if !since.IsZero() || !until.IsZero() {
+		tracks = playedBetween(tracks, since, until)
+	}
+
+	export := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "history", Name: "Listening History", TrackCount: len(tracks)},
+		Tracks:   tracks,
+	}
+	return r.exportLibrary(export, cmd)
+}
+
+// YTMusicLiked exports the liked songs.
+func (r *Runner) YTMusicLiked(ctx context.Context, cmd *cli.Command) error {
+	library, err := r.youtubeLibrary()
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("fetching youtube music liked songs")
+
+	tracks, err := library.LikedSongs(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+	}
+
+	export := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "liked", Name: "Liked Songs", TrackCount: len(tracks)},
+		Tracks:   tracks,
+	}
+	return r.exportLibrary(export, cmd)
+}
+
+// coverImager is implemented by services that can find a playlist's cover image
+type coverImager interface {
+	CoverImageURL(ctx context.Context, playlistID string) (string, error)
+}
+
+// YTMusicExport exports the playlists given with --ids, or with --all every library playlist that passes the
+// filters, using the same worker pool as the Spotify bulk export.
+func (r *Runner) YTMusicExport(ctx context.Context, cmd *cli.Command) error {
+	if r.youtube == nil {
+		return fmt.Errorf("%w: YouTube Music service not initialized", shared.ErrServiceUnavailable)
+	}
+
+	req, err := parseBulkExport(cmd)
+	if err != nil {
+		return err
+	}
+	if len(req.ids) == 0 && !cmd.Bool("all") {
+		return fmt.Errorf("%w: --all or --ids", shared.ErrMissingArgument)
+	}
+	if len(req.ids) > 0 && cmd.Bool("all") {
+		return fmt.Errorf("%w: --all cannot be combined with --ids", shared.ErrInvalidArgument)
+	}
+	if req.opts.Format == "markdown" || req.opts.Covers {
+		if covers, ok := r.youtube.(coverImager); ok {
+			req.opts.GetCoverImage = covers.CoverImageURL
+		}
+	}
+
+	return r.runBulkExport(ctx, r.youtube, "youtube", req, func(ctx context.Context) ([]models.Playlist, error) {
+		playlists, err := r.youtube.GetPlaylists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
+		}
+		return playlists, nil
+	})
+}
+
+// youtubeLibrary returns the YouTube Music service as a [youtubeLibrary]
+func (r *Runner) youtubeLibrary() (youtubeLibrary, error) {
+	if r.youtube == nil {
+		return nil, fmt.Errorf("%w: YouTube Music service not initialized", shared.ErrServiceUnavailable)
+	}
+	library, ok := r.youtube.(youtubeLibrary)
+	if !ok {
+		return nil, fmt.Errorf("%w: YouTube Music service does not support library exports", shared.ErrServiceUnavailable)
+	}
+	return library, nil
+}
+
+// exportLibrary writes a history or liked songs export in the --format given, as json or csv
+func (r *Runner) exportLibrary(export *models.PlaylistExport, cmd *cli.Command) error {
+	outputFile := cmd.String("output")
+	save := cmd.Bool("save")
+
+	switch format := cmd.String("format"); format {
+	case "csv":
+		return r.exportCSV(export, outputFile, save)
+	case "json":
+		return r.exportJSON(export, outputFile, save, cmd.Bool("json"), cmd.Bool("pretty"))
+	default:
+		return fmt.Errorf("%w: unsupported format: %s (supported: json, csv)", shared.ErrInvalidArgument, format)
+	}
+}
+
+// parseDateFlag parses a YYYY-MM-DD flag in local time, returning the zero time when it is unset
+func parseDateFlag(cmd *cli.Command, name string) (time.Time, error) {
+	value := cmd.String(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: --%s must be a date like 2024-01-31", shared.ErrInvalidArgument, name)
+	}
+	return date, nil
+}
+
+// playedBetween keeps the tracks played in [since, until), either bound being open when zero.
+// Tracks whose play time is unknown are dropped.
+func playedBetween(tracks []models.Track, since, until time.Time) []models.Track {
+	var kept []models.Track
+	for _, track := range tracks {
+		if track.AddedAt.IsZero() || track.AddedAt.Before(since) || (!until.IsZero() && !track.AddedAt.Before(until)) {
+			continue
+		}
+		kept = append(kept, track)
+	}
+	return kept
+}
+
+// YTMusicDelete deletes a YouTube Music playlist.
+func (r *Runner) YTMusicDelete(ctx context.Context, cmd *cli.Command) error {
+	if r.youtube == nil {
+		return fmt.Errorf("%w: YouTube Music service not initialized", shared.ErrServiceUnavailable)
+	}
+	deleter, ok := r.youtube.(services.PlaylistDeleter)
+	if !ok {
+		return fmt.Errorf("%w: %s cannot delete playlists", shared.ErrServiceUnavailable, r.youtube.Name())
+	}
+
+	playlistID := cmd.StringArg("playlist-id")
+	if playlistID == "" {
+		return fmt.Errorf("%w: playlist ID is required", shared.ErrMissingArgument)
+	}
+
+	playlist, err := r.youtube.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", shared.ErrPlaylistNotFound, err)
+	}
+
+	r.logger.Info("deleting youtube music playlist", "id", playlistID, "name", playlist.Name)
+	if err := deleter.DeletePlaylist(ctx, playlistID); err != nil {
+		return fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
+	}
+
+	r.writePlain("✓ Playlist deleted\n")
+	r.writePlain("Name: %s\n", playlist.Name)
+	r.writePlain("ID: %s\n", playlistID)
+	return nil
+}
+
+// YTMusicEdit changes the metadata of a YouTube Music playlist, leaving the fields without a flag as they are.
+func (r *Runner) YTMusicEdit(ctx context.Context, cmd *cli.Command) error {
+	if r.youtube == nil {
+		return fmt.Errorf("%w: YouTube Music service not initialized", shared.ErrServiceUnavailable)
+	}
+	editor, ok := r.youtube.(services.PlaylistEditor)
+	if !ok {
+		return fmt.Errorf("%w: %s cannot edit playlists", shared.ErrServiceUnavailable, r.youtube.Name())
+	}
+
+	playlistID := cmd.StringArg("playlist-id")
+	if playlistID == "" {
+		return fmt.Errorf("%w: playlist ID is required", shared.ErrMissingArgument)
+	}
+
+	update, err := playlistUpdate(cmd)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("editing youtube music playlist", "id", playlistID)
+	if err := editor.UpdatePlaylistMetadata(ctx, playlistID, update); err != nil {
+		return fmt.Errorf("%w: %w", shared.ErrAPIRequest, err)
+	}
+
+	r.writePlaylistUpdate(playlistID, update)
+	return nil
+}
+
+// playlistUpdate reads the metadata change from the flags of [playlistEditFlags], requiring at least one
+func playlistUpdate(cmd *cli.Command) (services.PlaylistUpdate, error) {
+	var update services.PlaylistUpdate
+	if cmd.IsSet("title") {
+		title := cmd.String("title")
+		if title == "" {
+			return update, fmt.Errorf("%w: --title cannot be empty", shared.ErrInvalidInput)
+		}
+		update.Name = &title
+	}
+	if cmd.IsSet("description") {
+		description := cmd.String("description")
+		update.Description = &description
+	}
+	if cmd.IsSet("private") {
+		public := !cmd.Bool("private")
+		update.Public = &public
+	}
+	if update.Name == nil && update.Description == nil && update.Public == nil {
+		return update, fmt.Errorf("%w: set at least one of --title, --description, or --private", shared.ErrMissingArgument)
+	}
+	return update, nil
+}
+
+// writePlaylistUpdate prints the fields a playlist edit changed
+func (r *Runner) writePlaylistUpdate(playlistID string, update services.PlaylistUpdate) {
+	r.writePlain("✓ Playlist updated\n")
+	r.writePlain("ID: %s\n", playlistID)
+	if update.Name != nil {
+		r.writePlain("Title: %s\n", *update.Name)
+	}
+	if update.Description != nil {
+		r.writePlain("Description: %s\n", *update.Description)
+	}
+	if update.Public != nil && *update.Public {
+		r.writePlain("Visibility: Public\n")
+	} else if update.Public != nil {
+		r.writePlain("Visibility: Private\n")
+	}
+}
@@ -0,0 +1,152 @@
+package formatter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// Archive formats [ArchiveDirectory] writes
+const (
+	ArchiveZip   = "zip"
+	ArchiveTarGz = "tar.gz"
+)
+
+// ParseArchiveFormat returns the archive format named by name: zip, or tar.gz (also tgz).
+func ParseArchiveFormat(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case ArchiveZip:
+		return ArchiveZip, nil
+	case ArchiveTarGz, "tgz":
+		return ArchiveTarGz, nil
+	default:
+		return "", fmt.Errorf("%w: archive format must be zip or tar.gz, got '%s'", shared.ErrInvalidArgument, name)
+	}
+}
+
+// ArchiveDirectory packages the files of dir into a single archive next to it, dir.zip or dir.tar.gz, and returns
+// its path. Entries sit under the directory's name, so extracting the archive gives back dir.
+//
+// Files are added in lexical path order with normalized permissions and no owner, so the same export always
+// archives the same way.
+func ArchiveDirectory(dir, format string) (string, error) {
+	format, err := ParseArchiveFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	slices.Sort(files)
+
+	// Resolved so that a dir of "." is archived as the directory it names
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	archivePath := abs + "." + format
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	root := filepath.Base(abs)
+	if format == ArchiveZip {
+		err = writeZip(out, dir, root, files)
+	} else {
+		err = writeTarGz(out, dir, root, files)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(archivePath)
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	return archivePath, nil
+}
+
+// writeZip writes files of dir to w as a zip archive, under root
+func writeZip(w io.Writer, dir, root string, files []string) error {
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return err
+		}
+		header := &zip.FileHeader{Name: root + "/" + file, Method: zip.Deflate, Modified: info.ModTime().UTC()}
+		header.SetMode(0o644)
+		entry, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(entry, filepath.Join(dir, filepath.FromSlash(file))); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeTarGz writes files of dir to w as a gzipped tar archive, under root
+func writeTarGz(w io.Writer, dir, root string, files []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     root + "/" + file,
+			Size:     info.Size(),
+			Mode:     0o644,
+			ModTime:  info.ModTime().UTC().Truncate(time.Second),
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if err := copyFile(tw, filepath.Join(dir, filepath.FromSlash(file))); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// copyFile copies the contents of the file at path to w
+func copyFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
@@ -0,0 +1,121 @@
+package formatter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestArchiveDirectory(t *testing.T) {
+	// writeExport writes an export directory with files created out of order
+	writeExport := func(t *testing.T) string {
+		t.Helper()
+		dir := filepath.Join(t.TempDir(), "backup")
+		for _, file := range []string{"zeta.json", "csv/alpha.csv", ManifestFileName, "beta.json"} {
+			path := filepath.Join(dir, filepath.FromSlash(file))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+	want := []string{"backup/beta.json", "backup/csv/alpha.csv", "backup/" + ManifestFileName, "backup/zeta.json"}
+
+	t.Run("Zip", func(t *testing.T) {
+		dir := writeExport(t)
+		path, err := ArchiveDirectory(dir, "zip")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != dir+".zip" {
+			t.Errorf("expected %s.zip, got %s", dir, path)
+		}
+
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatalf("failed to open archive: %v", err)
+		}
+		defer zr.Close()
+		names := []string{}
+		for _, file := range zr.File {
+			names = append(names, file.Name)
+		}
+		if !slices.Equal(names, want) {
+			t.Errorf("expected entries %v, got %v", want, names)
+		}
+
+		rc, err := zr.File[1].Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rc.Close()
+		if data, _ := io.ReadAll(rc); string(data) != "csv/alpha.csv" {
+			t.Errorf("unexpected contents %q", data)
+		}
+	})
+
+	t.Run("Tar.gz", func(t *testing.T) {
+		dir := writeExport(t)
+		path, err := ArchiveDirectory(dir, "tgz")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != dir+".tar.gz" {
+			t.Errorf("expected %s.tar.gz, got %s", dir, path)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to open archive: %v", err)
+		}
+		tr := tar.NewReader(gz)
+		names := []string{}
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header.Mode != 0o644 || header.Uid != 0 || header.Uname != "" {
+				t.Errorf("expected normalized permissions and owner for %s, got %+v", header.Name, header)
+			}
+			names = append(names, header.Name)
+		}
+		if !slices.Equal(names, want) {
+			t.Errorf("expected entries %v, got %v", want, names)
+		}
+
+		// Archiving the same export again gives the same bytes
+		again, err := ArchiveDirectory(dir, ArchiveTarGz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repeat, _ := os.ReadFile(again); !bytes.Equal(repeat, data) {
+			t.Error("expected archiving the same directory twice to give identical archives")
+		}
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		if _, err := ArchiveDirectory(writeExport(t), "rar"); !errors.Is(err, shared.ErrInvalidArgument) {
+			t.Errorf("expected ErrInvalidArgument, got %v", err)
+		}
+	})
+}
@@ -9,7 +9,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
@@ -30,7 +32,11 @@ type MarkdownExportResult struct {
 }
 
 // ExportManifest represents a summary of a bulk export operation.
+//
+// From [ManifestSchemaVersion] 1, entries record the size and SHA-256 checksum of every file, which
+// [VerifyExport] checks the export directory against.
 type ExportManifest struct {
+	SchemaVersion     int                   `json:"schema_version"` // 0 for manifests written before versioning
 	Timestamp         string                `json:"timestamp"`
 	Format            string                `json:"format"`
 	TotalPlaylists    int                   `json:"total_playlists"`
@@ -41,11 +47,13 @@ type ExportManifest struct {
 
 // ExportManifestEntry represents a single playlist export in the manifest.
 type ExportManifestEntry struct {
-	PlaylistID   string   `json:"playlist_id"`
-	PlaylistName string   `json:"playlist_name"`
-	Status       string   `json:"status"`
-	Files        []string `json:"files,omitempty"`
-	Error        string   `json:"error,omitempty"`
+	PlaylistID   string               `json:"playlist_id"`
+	PlaylistName string               `json:"playlist_name"`
+	Status       string               `json:"status"`
+	Files        []string             `json:"files,omitempty"`
+	Checksums    []ExportManifestFile `json:"checksums,omitempty"` // Files, with their sizes and checksums
+	CoverImage   string               `json:"cover_image,omitempty"`
+	Error        string               `json:"error,omitempty"`
 }
 
 // Type assertion helper struct matching tasks.BulkExportResult for JSON unmarshaling
@@ -53,35 +61,58 @@ type BulkExportResult struct {
 	TotalPlaylists    int
 	SuccessfulExports int
 	FailedExports     int
-	Results           []struct {
-		PlaylistID   string
-		PlaylistName string
-		Success      bool
-		Files        []string
-		Error        interface{} // Use interface{} to handle both error objects and strings
-	}
-	OutputDirectory string
-	ManifestPath    string
+	Results           []BulkExportItem
+	OutputDirectory   string
+	ManifestPath      string
+}
+
+// BulkExportItem is one playlist of a [BulkExportResult], matching tasks.PlaylistExportResult
+type BulkExportItem struct {
+	PlaylistID   string
+	PlaylistName string
+	Success      bool
+	Files        []string
+	CoverImage   string
+	Error        interface{} // Use interface{} to handle both error objects and strings
 }
 
-// ExportToCSV converts a PlaylistExport to CSV format with columns: ID, Title, Artist, Album, Duration, ISRC
+// ExportToCSV converts a PlaylistExport to CSV format with columns: ID, Title, Artist, Album, Duration, ISRC, AddedAt,
+// URI, URL
+//
+// Artist lists every credited artist, primary first, separated by "; ". AddedAt is an RFC 3339 timestamp, empty
+// when unknown. URI is the service URI and URL the track's web link, each empty when unknown.
 func ExportToCSV(export *models.PlaylistExport) ([]byte, error) {
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
 
-	headers := []string{"ID", "Title", "Artist", "Album", "Duration", "ISRC"}
+	headers := []string{"ID", "Title", "Artist", "Album", "Duration", "ISRC", "AddedAt", "URI", "URL"}
+	enriched := slices.ContainsFunc(export.Tracks, func(t models.Track) bool { return len(t.Genres) > 0 || t.Features != nil })
+	if enriched {
+		headers = append(headers, enrichmentColumns...)
+	}
 	if err := writer.Write(headers); err != nil {
 		return nil, fmt.Errorf("failed to write CSV headers: %w", err)
 	}
 
 	for _, track := range export.Tracks {
+		var addedAt string
+		if !track.AddedAt.IsZero() {
+			addedAt = track.AddedAt.UTC().Format(time.RFC3339)
+		}
+
 		record := []string{
 			track.ID,
 			track.Title,
-			track.Artist,
+			strings.Join(track.Artists, "; "),
 			track.Album,
 			strconv.Itoa(track.Duration),
 			track.ISRC,
+			addedAt,
+			track.URI,
+			track.ExternalURL,
+		}
+		if enriched {
+			record = append(record, enrichmentRecord(track)...)
 		}
 		if err := writer.Write(record); err != nil {
 			return nil, fmt.Errorf("failed to write CSV record: %w", err)
@@ -96,11 +127,34 @@ func ExportToCSV(export *models.PlaylistExport) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// ExportToMarkdown converts a PlaylistExport to Markdown format with optional cover image
+// enrichmentColumns are the CSV columns [ExportToCSV] adds for tracks enriched with genres and audio features
+var enrichmentColumns = []string{
+	"Genres", "Danceability", "Energy", "Valence", "Acousticness", "Instrumentalness", "Speechiness", "Liveness", "Loudness", "Tempo",
+}
+
+// enrichmentRecord returns the values of track for [enrichmentColumns]: genres separated by "; ", and empty
+// audio features when it has none
+func enrichmentRecord(track models.Track) []string {
+	record := make([]string, len(enrichmentColumns))
+	record[0] = strings.Join(track.Genres, "; ")
+	if f := track.Features; f != nil {
+		for i, value := range []float64{f.Danceability, f.Energy, f.Valence, f.Acousticness, f.Instrumentalness, f.Speechiness, f.Liveness, f.Loudness, f.Tempo} {
+			record[i+1] = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return record
+}
+
+// ExportToMarkdown converts a PlaylistExport to Markdown format with optional cover image.
+//
+// The playlist and track titles link to their services when their URLs are known.
 func ExportToMarkdown(export *models.PlaylistExport, imageFilename string) ([]byte, error) {
 	var buf bytes.Buffer
 
 	buf.WriteString(fmt.Sprintf("# %s\n\n", export.Playlist.Name))
+	if export.Playlist.ExternalURL != "" {
+		buf.WriteString(fmt.Sprintf("**Link**: %s\n\n", export.Playlist.ExternalURL))
+	}
 
 	if imageFilename != "" {
 		buf.WriteString(fmt.Sprintf("![Cover](%s)\n\n", imageFilename))
@@ -110,8 +164,14 @@ func ExportToMarkdown(export *models.PlaylistExport, imageFilename string) ([]by
 		buf.WriteString(fmt.Sprintf("**Description**: %s\n\n", export.Playlist.Description))
 	}
 
+	if export.Playlist.Owner != "" {
+		buf.WriteString(fmt.Sprintf("**Owner**: %s\n", export.Playlist.Owner))
+	}
 	buf.WriteString(fmt.Sprintf("**Tracks**: %d\n", len(export.Tracks)))
-	buf.WriteString(fmt.Sprintf("**Visibility**: %s\n\n", shared.VisibilityString(export.Playlist.Public)))
+	if export.Playlist.Followers > 0 {
+		buf.WriteString(fmt.Sprintf("**Followers**: %d\n", export.Playlist.Followers))
+	}
+	buf.WriteString(fmt.Sprintf("**Visibility**: %s\n\n", visibility(export.Playlist)))
 
 	buf.WriteString("## Tracks\n\n")
 	for i, track := range export.Tracks {
@@ -120,24 +180,53 @@ func ExportToMarkdown(export *models.PlaylistExport, imageFilename string) ([]by
 		if track.Album != "" {
 			albumPart = fmt.Sprintf(" (%s)", track.Album)
 		}
-		buf.WriteString(fmt.Sprintf("%d. %s - %s%s [%s]\n", i+1, track.Artist, track.Title, albumPart, duration))
+		title := track.Title
+		if track.ExternalURL != "" {
+			title = mdLink(track.Title, track.ExternalURL)
+		}
+		buf.WriteString(fmt.Sprintf("%d. %s - %s%s [%s]\n", i+1, track.Artist(), title, albumPart, duration))
 	}
 
 	return buf.Bytes(), nil
 }
 
-// ExportToText converts a PlaylistExport to plain text format
+// visibility describes who can see and edit a playlist, e.g. "private, collaborative"
+func visibility(playlist models.Playlist) string {
+	if playlist.Collaborative {
+		return shared.VisibilityString(playlist.Public) + ", collaborative"
+	}
+	return shared.VisibilityString(playlist.Public)
+}
+
+// ExportToText converts a PlaylistExport to plain text format, with the links of the playlist and its tracks when
+// they are known
 func ExportToText(export *models.PlaylistExport) ([]byte, error) {
 	var buf bytes.Buffer
 
 	buf.WriteString(fmt.Sprintf("Playlist: %s\n", export.Playlist.Name))
+	if export.Playlist.ExternalURL != "" {
+		buf.WriteString(fmt.Sprintf("Link: %s\n", export.Playlist.ExternalURL))
+	}
 	if export.Playlist.Description != "" {
 		buf.WriteString(fmt.Sprintf("Description: %s\n", export.Playlist.Description))
 	}
+	if export.Playlist.Owner != "" {
+		buf.WriteString(fmt.Sprintf("Owner: %s\n", export.Playlist.Owner))
+	}
+	if export.Playlist.Collaborative {
+		buf.WriteString("Collaborative: yes\n")
+	}
+	if export.Playlist.Followers > 0 {
+		buf.WriteString(fmt.Sprintf("Followers: %d\n", export.Playlist.Followers))
+	}
 	buf.WriteString(fmt.Sprintf("Tracks: %d\n\n", len(export.Tracks)))
 
 	for i, track := range export.Tracks {
-		buf.WriteString(fmt.Sprintf("%d. %s - %s\n", i+1, track.Artist, track.Title))
+		if track.ExternalURL != "" {
+			buf.WriteString(fmt.Sprintf("%d. %s - %s <%s>\n", i+1, track.Artist(), track.Title, track.ExternalURL))
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("%d. %s - %s\n", i+1, track.Artist(), track.Title))
 	}
 
 	return buf.Bytes(), nil
@@ -184,6 +273,7 @@ func ToMetadataJSON(playlist models.Playlist) ([]byte, error) {
 // WriteCSVExport exports a playlist to CSV format with accompanying metadata JSON file.
 //
 // Defaults to playlist ID as the base filename & creates {base}_tracks.csv and {base}_metadata.json
+// The metadata records the export's CoverImage, when it has one.
 func WriteCSVExport(export *models.PlaylistExport, baseFilepath string) (*CSVExportResult, error) {
 	if baseFilepath == "" {
 		baseFilepath = export.Playlist.ID
@@ -200,6 +290,12 @@ func WriteCSVExport(export *models.PlaylistExport, baseFilepath string) (*CSVExp
 	}
 
 	metadataJSON, err := ToMetadataJSON(export.Playlist)
+	if export.CoverImage != "" {
+		metadataJSON, err = shared.MarshalJSON(struct {
+			models.Playlist
+			CoverImage string
+		}{export.Playlist, export.CoverImage}, true)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate metadata JSON: %w", err)
 	}
@@ -215,6 +311,18 @@ func WriteCSVExport(export *models.PlaylistExport, baseFilepath string) (*CSVExp
 	}, nil
 }
 
+// WriteCoverImage downloads the cover image at imageURL and saves it to path.
+func WriteCoverImage(imageURL, path string) error {
+	imageData, err := DownloadImage(imageURL)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, imageData, 0644); err != nil {
+		return fmt.Errorf("failed to save cover image: %w", err)
+	}
+	return nil
+}
+
 // WriteMarkdownExport exports a playlist to Markdown format in a dedicated directory.
 //
 // Directory name defaults to the playlist ID.
@@ -323,6 +431,7 @@ func WriteBulkExportManifest(result any, format string, filepath string) error {
 	}
 
 	manifest := ExportManifest{
+		SchemaVersion:     ManifestSchemaVersion,
 		Timestamp:         time.Now().UTC().Format(time.RFC3339),
 		Format:            format,
 		TotalPlaylists:    bulkResult.TotalPlaylists,
@@ -336,6 +445,8 @@ func WriteBulkExportManifest(result any, format string, filepath string) error {
 			PlaylistID:   res.PlaylistID,
 			PlaylistName: res.PlaylistName,
 			Files:        res.Files,
+			Checksums:    checksumFiles(filepath, res.Files),
+			CoverImage:   res.CoverImage,
 		}
 
 		if res.Success {
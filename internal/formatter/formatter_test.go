@@ -3,6 +3,7 @@ package formatter
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
 	th "github.com/desertthunder/ytx/internal/testing"
@@ -22,7 +23,7 @@ func TestExporters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Album:    "Album One",
 					Duration: 180,
 					ISRC:     "USRC12345678",
@@ -30,7 +31,7 @@ func TestExporters(t *testing.T) {
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Album:    "Album Two",
 					Duration: 240,
 					ISRC:     "USRC87654321",
@@ -76,7 +77,7 @@ func TestExporters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Album:    "Album One",
 					Duration: 180,
 					ISRC:     "USRC12345678",
@@ -84,7 +85,7 @@ func TestExporters(t *testing.T) {
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Album:    "",
 					Duration: 240,
 					ISRC:     "USRC87654321",
@@ -152,14 +153,14 @@ func TestExporters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Album:    "Album One",
 					Duration: 180,
 				},
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Album:    "Album Two",
 					Duration: 240,
 				},
@@ -191,6 +192,40 @@ func TestExporters(t *testing.T) {
 		}
 	})
 
+	t.Run("owner and collaborators", func(t *testing.T) {
+		export := &models.PlaylistExport{
+			Playlist: models.Playlist{ID: "shared", Name: "Shared", Owner: "Ada", Collaborative: true, Followers: 12},
+			Tracks:   []models.Track{{ID: "track1", Title: "Song One", Artists: []string{"Artist One"}}},
+		}
+
+		markdown, _ := ExportToMarkdown(export, "")
+		for _, want := range []string{"**Owner**: Ada", "**Followers**: 12", "**Visibility**: Private, collaborative"} {
+			if !strings.Contains(string(markdown), want) {
+				t.Errorf("Markdown missing %q, got: %s", want, markdown)
+			}
+		}
+
+		text, _ := ExportToText(export)
+		for _, want := range []string{"Owner: Ada", "Collaborative: yes", "Followers: 12"} {
+			if !strings.Contains(string(text), want) {
+				t.Errorf("Text missing %q, got: %s", want, text)
+			}
+		}
+
+		metadata, _ := ToMetadataJSON(export.Playlist)
+		for _, want := range []string{`"Owner": "Ada"`, `"Collaborative": true`, `"Followers": 12`} {
+			if !strings.Contains(string(metadata), want) {
+				t.Errorf("JSON missing %q, got: %s", want, metadata)
+			}
+		}
+
+		// Unknown metadata is left out
+		plain, _ := ExportToText(&models.PlaylistExport{Playlist: models.Playlist{Name: "Mine"}})
+		if strings.Contains(string(plain), "Owner") || strings.Contains(string(plain), "Followers") {
+			t.Errorf("expected no owner or followers, got: %s", plain)
+		}
+	})
+
 	t.Run("ToMetadataJSON", func(t *testing.T) {
 		playlist := models.Playlist{
 			ID:          "test123",
@@ -228,7 +263,7 @@ func TestExporters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Album:    "Album One",
 					Duration: 180,
 					ISRC:     "USRC12345678",
@@ -236,7 +271,7 @@ func TestExporters(t *testing.T) {
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Album:    "Album Two",
 					Duration: 240,
 					ISRC:     "USRC87654321",
@@ -269,6 +304,97 @@ func TestExporters(t *testing.T) {
 		if !strings.Contains(output, `"USRC12345678"`) {
 			t.Errorf("JSON missing track1 ISRC")
 		}
+		if strings.Contains(output, `"AddedAt"`) {
+			t.Errorf("JSON should omit unknown AddedAt")
+		}
+	})
+
+	t.Run("AddedAt", func(t *testing.T) {
+		export := &models.PlaylistExport{
+			Playlist: models.Playlist{ID: "test123", Name: "Test Playlist"},
+			Tracks: []models.Track{
+				{ID: "track1", Title: "Song One", AddedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)},
+				{ID: "track2", Title: "Song Two"},
+			},
+		}
+
+		data, err := ExportToCSV(export)
+		if err != nil {
+			t.Fatalf("ExportToCSV failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if lines[0] != "ID,Title,Artist,Album,Duration,ISRC,AddedAt,URI,URL" {
+			t.Errorf("unexpected CSV headers: %s", lines[0])
+		}
+		if !strings.HasSuffix(lines[1], ",2024-03-01T12:30:00Z,,") {
+			t.Errorf("CSV missing track1 added_at, got: %s", lines[1])
+		}
+		if !strings.HasSuffix(lines[2], ",,,") {
+			t.Errorf("expected empty added_at for track2, got: %s", lines[2])
+		}
+
+		data, err = ExportToJSON(export)
+		if err != nil {
+			t.Fatalf("ExportToJSON failed: %v", err)
+		}
+		if !strings.Contains(string(data), `"AddedAt": "2024-03-01T12:30:00Z"`) {
+			t.Errorf("JSON missing track1 added_at, got: %s", data)
+		}
+	})
+
+	t.Run("Links", func(t *testing.T) {
+		export := &models.PlaylistExport{
+			Playlist: models.Playlist{ID: "pl1", Name: "Test Playlist", ExternalURL: "https://open.spotify.com/playlist/pl1"},
+			Tracks: []models.Track{
+				{ID: "track1", Title: "Song [Live]", Artists: []string{"Artist"}, URI: "spotify:track:track1", ExternalURL: "https://open.spotify.com/track/track1"},
+				{ID: "local", Title: "Local File", Artists: []string{"Artist"}},
+			},
+		}
+
+		data, err := ExportToCSV(export)
+		if err != nil {
+			t.Fatalf("ExportToCSV failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if !strings.HasSuffix(lines[1], ",spotify:track:track1,https://open.spotify.com/track/track1") {
+			t.Errorf("CSV missing track1 URI and URL, got: %s", lines[1])
+		}
+
+		data, err = ExportToMarkdown(export, "")
+		if err != nil {
+			t.Fatalf("ExportToMarkdown failed: %v", err)
+		}
+		for _, want := range []string{
+			"**Link**: https://open.spotify.com/playlist/pl1",
+			"1. Artist - [Song \\[Live\\]](https://open.spotify.com/track/track1) [0:00]",
+			"2. Artist - Local File [0:00]",
+		} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("Markdown missing %q, got:\n%s", want, data)
+			}
+		}
+
+		data, err = ExportToText(export)
+		if err != nil {
+			t.Fatalf("ExportToText failed: %v", err)
+		}
+		for _, want := range []string{
+			"Link: https://open.spotify.com/playlist/pl1\n",
+			"1. Artist - Song [Live] <https://open.spotify.com/track/track1>\n",
+			"2. Artist - Local File\n",
+		} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("text export missing %q, got:\n%s", want, data)
+			}
+		}
+
+		data, err = ExportToJSON(export)
+		if err != nil {
+			t.Fatalf("ExportToJSON failed: %v", err)
+		}
+		if !strings.Contains(string(data), `"ExternalURL": "https://open.spotify.com/track/track1"`) {
+			t.Errorf("JSON missing track1 URL, got: %s", data)
+		}
 	})
 }
 
@@ -295,7 +421,7 @@ func TestWriters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Album:    "Album One",
 					Duration: 180,
 					ISRC:     "USRC12345678",
@@ -303,7 +429,7 @@ func TestWriters(t *testing.T) {
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Album:    "Album Two",
 					Duration: 240,
 					ISRC:     "USRC87654321",
@@ -382,14 +508,14 @@ func TestWriters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Album:    "Album One",
 					Duration: 180,
 				},
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Duration: 240,
 				},
 			},
@@ -459,13 +585,13 @@ func TestWriters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Duration: 180,
 				},
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Duration: 240,
 				},
 			},
@@ -529,7 +655,7 @@ func TestWriters(t *testing.T) {
 				{
 					ID:       "track1",
 					Title:    "Song One",
-					Artist:   "Artist One",
+					Artists:  []string{"Artist One"},
 					Album:    "Album One",
 					Duration: 180,
 					ISRC:     "USRC12345678",
@@ -537,7 +663,7 @@ func TestWriters(t *testing.T) {
 				{
 					ID:       "track2",
 					Title:    "Song Two",
-					Artist:   "Artist Two",
+					Artists:  []string{"Artist Two"},
 					Album:    "Album Two",
 					Duration: 240,
 					ISRC:     "USRC87654321",
@@ -605,13 +731,7 @@ func TestWriters(t *testing.T) {
 				TotalPlaylists:    2,
 				SuccessfulExports: 2,
 				FailedExports:     0,
-				Results: []struct {
-					PlaylistID   string
-					PlaylistName string
-					Success      bool
-					Files        []string
-					Error        interface{}
-				}{
+				Results: []BulkExportItem{
 					{
 						PlaylistID:   "playlist1",
 						PlaylistName: "My Playlist 1",
@@ -671,13 +791,7 @@ func TestWriters(t *testing.T) {
 				TotalPlaylists:    3,
 				SuccessfulExports: 1,
 				FailedExports:     2,
-				Results: []struct {
-					PlaylistID   string
-					PlaylistName string
-					Success      bool
-					Files        []string
-					Error        interface{}
-				}{
+				Results: []BulkExportItem{
 					{
 						PlaylistID:   "playlist1",
 						PlaylistName: "Success Playlist",
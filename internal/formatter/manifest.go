@@ -0,0 +1,163 @@
+package formatter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// ManifestFileName is the name of the manifest a bulk export writes to its output directory.
+const ManifestFileName = "export_manifest.json"
+
+// ManifestSchemaVersion is the [ExportManifest] schema written by this version of ytx.
+//
+//	0: written before schema_version, listing files without checksums
+//	1: the size and SHA-256 checksum of every file
+const ManifestSchemaVersion = 1
+
+// ExportManifestFile records the size and checksum of an exported file.
+type ExportManifestFile struct {
+	Path   string `json:"path"` // Relative to the manifest's directory, with forward slashes
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Statuses of a file checked by [VerifyExport]
+const (
+	FileOK       = "ok"
+	FileMissing  = "missing"
+	FileModified = "modified" // Size or checksum differ from the manifest's
+	FileUnlisted = "unlisted" // In the directory but not the manifest; reported without failing verification
+)
+
+// VerifiedFile is the result of checking one file of an export directory against its manifest.
+type VerifiedFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// VerifyResult is the result of [VerifyExport].
+type VerifyResult struct {
+	SchemaVersion int            `json:"schema_version"`
+	Files         []VerifiedFile `json:"files"`
+	Failed        int            `json:"failed"` // Files missing or modified
+}
+
+// checksumFiles returns the size and SHA-256 checksum of each of files, with paths relative to the directory of
+// the manifest at manifestPath. Files that cannot be read are left out.
+func checksumFiles(manifestPath string, files []string) []ExportManifestFile {
+	dir := filepath.Dir(manifestPath)
+	var checksums []ExportManifestFile
+	for _, file := range files {
+		size, sum, err := checksumFile(file)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			rel = file
+		}
+		checksums = append(checksums, ExportManifestFile{Path: filepath.ToSlash(rel), Size: size, SHA256: sum})
+	}
+	return checksums
+}
+
+// checksumFile returns the size and hex SHA-256 checksum of the file at path
+func checksumFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// VerifyExport checks the files of the export directory dir against the checksums in its [ManifestFileName]:
+// each listed file must exist with the recorded size and SHA-256 checksum. Files in dir that the manifest
+// doesn't list are reported as [FileUnlisted] without counting as failures.
+//
+// Unversioned manifests, which have no checksums, and manifests from a newer ytx fail with [shared.ErrInvalidInput].
+func VerifyExport(dir string) (*VerifyResult, error) {
+	manifestPath := filepath.Join(dir, ManifestFileName)
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s has no export manifest: %v", shared.ErrInvalidInput, dir, err)
+	}
+
+	var manifest ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s is not a valid export manifest: %v", shared.ErrInvalidInput, manifestPath, err)
+	}
+	switch {
+	case manifest.SchemaVersion > ManifestSchemaVersion:
+		return nil, fmt.Errorf("%w: manifest schema version %d is newer than this ytx supports (%d); upgrade ytx first",
+			shared.ErrInvalidInput, manifest.SchemaVersion, ManifestSchemaVersion)
+	case manifest.SchemaVersion < 1:
+		return nil, fmt.Errorf("%w: manifest schema version %d has no checksums to verify; export again to add them",
+			shared.ErrInvalidInput, manifest.SchemaVersion)
+	}
+
+	result := &VerifyResult{SchemaVersion: manifest.SchemaVersion, Files: []VerifiedFile{}}
+	listed := map[string]bool{ManifestFileName: true}
+	for _, entry := range manifest.Exports {
+		for _, want := range entry.Checksums {
+			listed[want.Path] = true
+			file := VerifiedFile{Path: want.Path, Status: FileOK}
+
+			size, sum, err := checksumFile(filepath.Join(dir, filepath.FromSlash(want.Path)))
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				file.Status = FileMissing
+			case err != nil:
+				file.Status, file.Detail = FileMissing, err.Error()
+			case size != want.Size:
+				file.Status, file.Detail = FileModified, fmt.Sprintf("size %d, manifest has %d", size, want.Size)
+			case sum != want.SHA256:
+				file.Status, file.Detail = FileModified, "checksum differs from the manifest's"
+			}
+			if file.Status != FileOK {
+				result.Failed++
+			}
+			result.Files = append(result.Files, file)
+		}
+	}
+
+	var unlisted []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); !listed[rel] {
+			unlisted = append(unlisted, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	slices.Sort(unlisted)
+	for _, path := range unlisted {
+		result.Files = append(result.Files, VerifiedFile{Path: path, Status: FileUnlisted})
+	}
+	return result, nil
+}
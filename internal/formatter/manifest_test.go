@@ -0,0 +1,132 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestVerifyExport(t *testing.T) {
+	// writeExport writes two exported files and their manifest to a new directory
+	writeExport := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		files := []string{filepath.Join(dir, "road_trip.json"), filepath.Join(dir, "csv", "road_trip.csv")}
+		if err := os.MkdirAll(filepath.Join(dir, "csv"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for i, file := range files {
+			if err := os.WriteFile(file, []byte{'a' + byte(i), '\n'}, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result := map[string]any{
+			"TotalPlaylists":    1,
+			"SuccessfulExports": 1,
+			"Results":           []map[string]any{{"PlaylistID": "pl1", "PlaylistName": "Road Trip", "Success": true, "Files": files}},
+		}
+		if err := WriteBulkExportManifest(result, "json", filepath.Join(dir, ManifestFileName)); err != nil {
+			t.Fatalf("failed to write manifest: %v", err)
+		}
+		return dir
+	}
+
+	t.Run("Manifest records sizes and checksums", func(t *testing.T) {
+		dir := writeExport(t)
+		data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var manifest ExportManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			t.Fatalf("failed to parse manifest: %v", err)
+		}
+
+		if manifest.SchemaVersion != ManifestSchemaVersion {
+			t.Errorf("expected schema version %d, got %d", ManifestSchemaVersion, manifest.SchemaVersion)
+		}
+		checksums := manifest.Exports[0].Checksums
+		if len(checksums) != 2 {
+			t.Fatalf("expected 2 checksums, got %d", len(checksums))
+		}
+		// SHA-256 of "a\n"
+		want := ExportManifestFile{Path: "road_trip.json", Size: 2, SHA256: "87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7"}
+		if checksums[0] != want {
+			t.Errorf("expected %+v, got %+v", want, checksums[0])
+		}
+		if checksums[1].Path != "csv/road_trip.csv" {
+			t.Errorf("expected a relative slash path, got %s", checksums[1].Path)
+		}
+	})
+
+	t.Run("Unchanged export", func(t *testing.T) {
+		result, err := VerifyExport(writeExport(t))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Failed != 0 || len(result.Files) != 2 {
+			t.Fatalf("expected 2 files and no failures, got %+v", result)
+		}
+		for _, file := range result.Files {
+			if file.Status != FileOK {
+				t.Errorf("expected %s to be ok, got %s", file.Path, file.Status)
+			}
+		}
+	})
+
+	t.Run("Missing, modified, and unlisted files", func(t *testing.T) {
+		dir := writeExport(t)
+		if err := os.Remove(filepath.Join(dir, "road_trip.json")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "csv", "road_trip.csv"), []byte("x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := VerifyExport(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Failed != 2 {
+			t.Errorf("expected 2 failures, got %d", result.Failed)
+		}
+		statuses := map[string]string{}
+		for _, file := range result.Files {
+			statuses[file.Path] = file.Status
+		}
+		want := map[string]string{"road_trip.json": FileMissing, "csv/road_trip.csv": FileModified, "notes.txt": FileUnlisted}
+		for path, status := range want {
+			if statuses[path] != status {
+				t.Errorf("expected %s to be %s, got %s", path, status, statuses[path])
+			}
+		}
+	})
+
+	t.Run("Unsupported manifests", func(t *testing.T) {
+		for name, manifest := range map[string]string{
+			"unversioned": `{"format": "json", "exports": []}`,
+			"newer":       `{"schema_version": 99, "exports": []}`,
+			"invalid":     `{`,
+		} {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ManifestFileName), []byte(manifest), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := VerifyExport(dir); !errors.Is(err, shared.ErrInvalidInput) {
+				t.Errorf("%s: expected ErrInvalidInput, got %v", name, err)
+			}
+		}
+
+		if _, err := VerifyExport(t.TempDir()); !errors.Is(err, shared.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput without a manifest, got %v", err)
+		}
+	})
+}
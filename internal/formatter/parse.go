@@ -0,0 +1,132 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// ReadExport reads a playlist export written by [WriteJSONExport] or [WriteCSVExport], by its file extension.
+//
+// For a CSV tracks file ({base}_tracks.csv), the playlist is read from {base}_metadata.json when it exists and
+// named after the file otherwise.
+func ReadExport(path string) (*models.PlaylistExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return ParseJSONExport(data)
+	case ".csv":
+		tracks, err := ParseCSVExport(data)
+		if err != nil {
+			return nil, err
+		}
+
+		base := strings.TrimSuffix(strings.TrimSuffix(path, filepath.Ext(path)), "_tracks")
+		playlist := models.Playlist{Name: filepath.Base(base)}
+		metadata, err := os.ReadFile(base + "_metadata.json")
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(metadata, &playlist); err != nil {
+				return nil, fmt.Errorf("%w: invalid metadata file: %v", shared.ErrInvalidInput, err)
+			}
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("failed to read metadata: %w", err)
+		}
+		playlist.TrackCount = len(tracks)
+		return &models.PlaylistExport{Playlist: playlist, Tracks: tracks}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported export file type '%s' (must be .json or .csv)", shared.ErrInvalidInput, ext)
+	}
+}
+
+// ParseJSONExport parses a playlist export in the format of [ExportToJSON].
+func ParseJSONExport(data []byte) (*models.PlaylistExport, error) {
+	var export models.PlaylistExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON export: %v", shared.ErrInvalidInput, err)
+	}
+	if export.Playlist.Name == "" && len(export.Tracks) == 0 {
+		return nil, fmt.Errorf("%w: JSON export has no playlist or tracks", shared.ErrInvalidInput)
+	}
+	return &export, nil
+}
+
+// ParseCSVExport parses the tracks of a CSV export in the format of [ExportToCSV].
+//
+// Columns are found by their header, so exports from before a column was added still parse.
+func ParseCSVExport(data []byte) ([]models.Track, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid CSV export: %v", shared.ErrInvalidInput, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: CSV export is empty", shared.ErrInvalidInput)
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, header := range records[0] {
+		columns[header] = i
+	}
+	if _, ok := columns["Title"]; !ok {
+		return nil, fmt.Errorf("%w: CSV export has no Title column", shared.ErrInvalidInput)
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	tracks := make([]models.Track, 0, len(records)-1)
+	for line, record := range records[1:] {
+		track := models.Track{
+			ID:          field(record, "ID"),
+			Title:       field(record, "Title"),
+			Album:       field(record, "Album"),
+			ISRC:        field(record, "ISRC"),
+			URI:         field(record, "URI"),
+			ExternalURL: field(record, "URL"),
+		}
+		track.SetArtists(strings.Split(field(record, "Artist"), "; ")...)
+		if duration := field(record, "Duration"); duration != "" {
+			if track.Duration, err = strconv.Atoi(duration); err != nil {
+				return nil, fmt.Errorf("%w: invalid duration on line %d: %q", shared.ErrInvalidInput, line+2, duration)
+			}
+		}
+		if addedAt := field(record, "AddedAt"); addedAt != "" {
+			if track.AddedAt, err = time.Parse(time.RFC3339, addedAt); err != nil {
+				return nil, fmt.Errorf("%w: invalid AddedAt on line %d: %q", shared.ErrInvalidInput, line+2, addedAt)
+			}
+		}
+		if genres := field(record, "Genres"); genres != "" {
+			track.Genres = strings.Split(genres, "; ")
+		}
+		if field(record, "Danceability") != "" {
+			f := &models.AudioFeatures{}
+			for i, value := range []*float64{&f.Danceability, &f.Energy, &f.Valence, &f.Acousticness, &f.Instrumentalness, &f.Speechiness, &f.Liveness, &f.Loudness, &f.Tempo} {
+				name := enrichmentColumns[i+1]
+				if *value, err = strconv.ParseFloat(field(record, name), 64); err != nil {
+					return nil, fmt.Errorf("%w: invalid %s on line %d: %q", shared.ErrInvalidInput, name, line+2, field(record, name))
+				}
+			}
+			track.Features = f
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
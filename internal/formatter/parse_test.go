@@ -0,0 +1,129 @@
+package formatter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestReadExport(t *testing.T) {
+	export := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "pl1", Name: "Road Trip", Description: "Long drives", TrackCount: 2, Public: true, ExternalURL: "https://open.spotify.com/playlist/pl1"},
+		Tracks: []models.Track{
+			{ID: "t1", Title: "Song, One", Artists: []string{"Artist"}, Album: "Album", Duration: 180, ISRC: "USRC1", AddedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+				URI: "spotify:track:t1", ExternalURL: "https://open.spotify.com/track/t1"},
+			{ID: "t2", Title: "Song Two", Artists: []string{"Other", "Guest"}},
+		},
+	}
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		path, err := WriteJSONExport(export, filepath.Join(t.TempDir(), "export.json"))
+		if err != nil {
+			t.Fatalf("WriteJSONExport failed: %v", err)
+		}
+
+		got, err := ReadExport(path)
+		if err != nil {
+			t.Fatalf("ReadExport failed: %v", err)
+		}
+		if got.Playlist != export.Playlist || len(got.Tracks) != 2 || !got.Tracks[0].AddedAt.Equal(export.Tracks[0].AddedAt) {
+			t.Errorf("unexpected export %+v", got)
+		}
+		if !slices.Equal(got.Tracks[1].Artists, export.Tracks[1].Artists) {
+			t.Errorf("expected every artist kept, got %q", got.Tracks[1].Artists)
+		}
+	})
+
+	t.Run("JSON export with only the primary artist", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "old.json")
+		if err := os.WriteFile(path, []byte(`{"Playlist":{"Name":"old"},"Tracks":[{"ID":"t1","Title":"Song","Artist":"Artist"}]}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ReadExport(path)
+		if err != nil {
+			t.Fatalf("ReadExport failed: %v", err)
+		}
+		if len(got.Tracks) != 1 || !slices.Equal(got.Tracks[0].Artists, []string{"Artist"}) {
+			t.Errorf("expected Artist credited, got %+v", got.Tracks)
+		}
+	})
+
+	t.Run("CSV round trip with metadata", func(t *testing.T) {
+		result, err := WriteCSVExport(export, filepath.Join(t.TempDir(), "road"))
+		if err != nil {
+			t.Fatalf("WriteCSVExport failed: %v", err)
+		}
+
+		got, err := ReadExport(result.TracksFile)
+		if err != nil {
+			t.Fatalf("ReadExport failed: %v", err)
+		}
+		if got.Playlist.Name != "Road Trip" || got.Playlist.ID != "pl1" {
+			t.Errorf("expected the playlist from the metadata file, got %+v", got.Playlist)
+		}
+		if !reflect.DeepEqual(got.Tracks, export.Tracks) {
+			t.Errorf("unexpected tracks %+v", got.Tracks)
+		}
+	})
+
+	t.Run("CSV round trip with enrichment", func(t *testing.T) {
+		enriched := *export
+		enriched.Tracks = []models.Track{
+			{ID: "t1", Title: "Song", Artists: []string{"Artist"}, Genres: []string{"indie pop", "dream pop"}, Features: &models.AudioFeatures{Danceability: 0.5, Loudness: -7.25, Tempo: 98}},
+			{ID: "t2", Title: "Song Two", Artists: []string{"Other"}},
+		}
+		result, err := WriteCSVExport(&enriched, filepath.Join(t.TempDir(), "road"))
+		if err != nil {
+			t.Fatalf("WriteCSVExport failed: %v", err)
+		}
+
+		got, err := ReadExport(result.TracksFile)
+		if err != nil {
+			t.Fatalf("ReadExport failed: %v", err)
+		}
+		if !reflect.DeepEqual(got.Tracks, enriched.Tracks) {
+			t.Errorf("unexpected tracks %+v", got.Tracks)
+		}
+	})
+
+	t.Run("CSV without metadata or newer columns", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "old_tracks.csv")
+		if err := os.WriteFile(path, []byte("ID,Title,Artist,Album,Duration,ISRC\nt1,Song,Artist,,200,\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ReadExport(path)
+		if err != nil {
+			t.Fatalf("ReadExport failed: %v", err)
+		}
+		if got.Playlist.Name != "old" || len(got.Tracks) != 1 || got.Tracks[0].Duration != 200 {
+			t.Errorf("unexpected export %+v", got)
+		}
+	})
+
+	t.Run("Invalid files", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"notes.txt":         "hello",
+			"empty.json":        "{}",
+			"broken_tracks.csv": "ID,Title,Duration\nt1,Song,long\n",
+		}
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadExport(path); !errors.Is(err, shared.ErrInvalidInput) {
+				t.Errorf("ReadExport(%s) error = %v, want ErrInvalidInput", name, err)
+			}
+		}
+	})
+}
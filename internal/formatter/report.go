@@ -0,0 +1,283 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+)
+
+// Transfer report track statuses
+const (
+	ReportMatched = "matched"
+	ReportFailed  = "failed"
+	ReportSkipped = "skipped"
+
+	ReportUnavailable = "unavailable" // Only found in versions that cannot be played in the destination account's region
+)
+
+// TransferReport summarizes a finished transfer for [ExportTransferReportMarkdown] and [ExportTransferReportHTML].
+type TransferReport struct {
+	GeneratedAt     time.Time
+	SourceService   string // Service key of the source playlist ("spotify" or "youtube")
+	DestService     string // Service key of the destination playlist
+	SourcePlaylist  models.Playlist
+	DestPlaylist    *models.Playlist // Nil when no destination playlist was created
+	MatchPercentage float64
+	Tracks          []TransferReportTrack // Source tracks in playlist order
+
+	StartedAt   time.Time     // When the transfer started; zero when unknown
+	CompletedAt time.Time     // When it finished; zero when unknown
+	Phases      []ReportPhase // Time spent in each phase, in order
+}
+
+// ReportPhase is the time a transfer spent in one phase, such as searching for tracks.
+type ReportPhase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Duration summarizes how long the transfer took, as [FormatTiming] does.
+func (r TransferReport) Duration() string {
+	return FormatTiming(r.StartedAt, r.CompletedAt, r.Phases)
+}
+
+// FormatTiming summarizes how long an operation took and each phase of it, e.g. "1m2.5s (Search tracks 58.1s,
+// Create playlist 2.2s)", or returns an empty string when its times are unknown.
+func FormatTiming(startedAt, completedAt time.Time, phases []ReportPhase) string {
+	if startedAt.IsZero() || completedAt.IsZero() {
+		return ""
+	}
+	summary := roundDuration(completedAt.Sub(startedAt)).String()
+	if len(phases) == 0 {
+		return summary
+	}
+	parts := make([]string, len(phases))
+	for i, phase := range phases {
+		parts[i] = fmt.Sprintf("%s %s", phase.Name, roundDuration(phase.Duration))
+	}
+	return fmt.Sprintf("%s (%s)", summary, strings.Join(parts, ", "))
+}
+
+// roundDuration rounds d for display: to the millisecond under a second, and to a tenth of a second above
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
+
+// TransferReportTrack is the outcome for one source track.
+type TransferReportTrack struct {
+	Source     models.Track
+	Match      *models.Track // Destination track; nil unless Status is [ReportMatched]
+	Status     string        // [ReportMatched], [ReportFailed], [ReportUnavailable], or [ReportSkipped]
+	Method     string        // How the match was found (e.g. "search", "search (title)", "cache", "override")
+	Confidence float64       // Similarity between the source track and its match, from 0 to 1
+	Note       string        // Why the track failed, was unavailable, or was skipped
+}
+
+// Count returns the number of report tracks with status
+func (r TransferReport) Count(status string) int {
+	count := 0
+	for _, track := range r.Tracks {
+		if track.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// ExportTransferReportMarkdown renders a transfer report as Markdown with a summary and one table per status.
+func ExportTransferReportMarkdown(report TransferReport) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(fmt.Sprintf("# Transfer Report: %s\n\n", mdEscape(report.SourcePlaylist.Name)))
+	buf.WriteString(fmt.Sprintf("**Generated**: %s\n", report.GeneratedAt.UTC().Format(time.RFC3339)))
+	buf.WriteString(fmt.Sprintf("**Source**: %s\n", mdLink(fmt.Sprintf("%s (%s)", report.SourcePlaylist.Name, report.SourceService),
+		models.PlaylistURL(report.SourceService, report.SourcePlaylist.ID))))
+	if report.DestPlaylist != nil {
+		buf.WriteString(fmt.Sprintf("**Destination**: %s\n", mdLink(fmt.Sprintf("%s (%s)", report.DestPlaylist.Name, report.DestService),
+			models.PlaylistURL(report.DestService, report.DestPlaylist.ID))))
+	} else {
+		buf.WriteString(fmt.Sprintf("**Destination**: not created (%s)\n", report.DestService))
+	}
+	buf.WriteString(fmt.Sprintf("**Matched**: %d (%.1f%%)\n", report.Count(ReportMatched), report.MatchPercentage))
+	buf.WriteString(fmt.Sprintf("**Failed**: %d\n", report.Count(ReportFailed)))
+	if n := report.Count(ReportUnavailable); n > 0 {
+		buf.WriteString(fmt.Sprintf("**Unavailable**: %d\n", n))
+	}
+	buf.WriteString(fmt.Sprintf("**Skipped**: %d\n", report.Count(ReportSkipped)))
+	if duration := report.Duration(); duration != "" {
+		buf.WriteString(fmt.Sprintf("**Duration**: %s\n", duration))
+	}
+
+	if report.Count(ReportMatched) > 0 {
+		buf.WriteString("\n## Matched\n\n")
+		buf.WriteString("| # | Source | Match | Method | Confidence |\n")
+		buf.WriteString("| - | ------ | ----- | ------ | ---------- |\n")
+		for i, track := range report.Tracks {
+			if track.Status != ReportMatched {
+				continue
+			}
+			buf.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %.0f%% |\n",
+				i+1,
+				mdLink(trackLabel(track.Source), models.TrackURL(report.SourceService, track.Source.ID)),
+				mdLink(trackLabel(*track.Match), models.TrackURL(report.DestService, track.Match.ID)),
+				track.Method,
+				track.Confidence*100,
+			))
+		}
+	}
+
+	for _, section := range []struct{ status, title string }{{ReportFailed, "Failed"}, {ReportUnavailable, "Unavailable"}, {ReportSkipped, "Skipped"}} {
+		if report.Count(section.status) == 0 {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("\n## %s\n\n", section.title))
+		buf.WriteString("| # | Source | Reason |\n")
+		buf.WriteString("| - | ------ | ------ |\n")
+		for i, track := range report.Tracks {
+			if track.Status != section.status {
+				continue
+			}
+			buf.WriteString(fmt.Sprintf("| %d | %s | %s |\n",
+				i+1,
+				mdLink(trackLabel(track.Source), models.TrackURL(report.SourceService, track.Source.ID)),
+				mdEscape(track.Note),
+			))
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// reportTemplate renders [TransferReport] as a standalone HTML page
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"trackURL":    models.TrackURL,
+	"playlistURL": models.PlaylistURL,
+	"label": func(track any) string {
+		switch t := track.(type) {
+		case models.Track:
+			return trackLabel(t)
+		case *models.Track:
+			return trackLabel(*t)
+		}
+		return ""
+	},
+	"link":      func(text, url string) map[string]string { return map[string]string{"Text": text, "URL": url} },
+	"inc":       func(i int) int { return i + 1 },
+	"percent":   func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"timestamp": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`{{define "link"}}{{if .URL}}<a href="{{.URL}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Transfer Report: {{.SourcePlaylist.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; }
+table { border-collapse: collapse; margin-bottom: 2rem; }
+th, td { border: 1px solid #ccc; padding: 0.25rem 0.5rem; text-align: left; }
+.matched { color: #2e7d32; } .failed { color: #c62828; } .unavailable { color: #ef6c00; } .skipped { color: #757575; }
+</style>
+</head>
+<body>
+<h1>Transfer Report: {{.SourcePlaylist.Name}}</h1>
+<ul>
+<li>Generated: {{timestamp .GeneratedAt}}</li>
+<li>Source: {{template "link" link (printf "%s (%s)" .SourcePlaylist.Name .SourceService) (playlistURL .SourceService .SourcePlaylist.ID)}}</li>
+{{- if .DestPlaylist}}
+<li>Destination: {{template "link" link (printf "%s (%s)" .DestPlaylist.Name .DestService) (playlistURL .DestService .DestPlaylist.ID)}}</li>
+{{- else}}
+<li>Destination: not created ({{.DestService}})</li>
+{{- end}}
+<li class="matched">Matched: {{.Count "matched"}} ({{printf "%.1f" .MatchPercentage}}%)</li>
+<li class="failed">Failed: {{.Count "failed"}}</li>
+{{- with .Count "unavailable"}}
+<li class="unavailable">Unavailable: {{.}}</li>
+{{- end}}
+<li class="skipped">Skipped: {{.Count "skipped"}}</li>
+{{- with .Duration}}
+<li>Duration: {{.}}</li>
+{{- end}}
+</ul>
+<table>
+<tr><th>#</th><th>Status</th><th>Source</th><th>Match</th><th>Method</th><th>Confidence</th><th>Notes</th></tr>
+{{- $report := .}}
+{{- range $i, $track := .Tracks}}
+<tr class="{{$track.Status}}">
+<td>{{inc $i}}</td>
+<td>{{$track.Status}}</td>
+<td>{{template "link" link (label $track.Source) (trackURL $report.SourceService $track.Source.ID)}}</td>
+<td>{{with $track.Match}}{{template "link" link (label .) (trackURL $report.DestService .ID)}}{{end}}</td>
+<td>{{$track.Method}}</td>
+<td>{{if $track.Match}}{{percent $track.Confidence}}{{end}}</td>
+<td>{{$track.Note}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// ExportTransferReportHTML renders a transfer report as a standalone HTML page with one row per source track.
+func ExportTransferReportHTML(report TransferReport) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTransferReport writes a transfer report, as HTML for .html/.htm files and as Markdown otherwise.
+//
+// Defaults to {source playlist ID}_report.md as the filename.
+func WriteTransferReport(report TransferReport, path string) (string, error) {
+	if path == "" {
+		path = fmt.Sprintf("%s_report.md", report.SourcePlaylist.ID)
+	}
+
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		data, err = ExportTransferReportHTML(report)
+	default:
+		data, err = ExportTransferReportMarkdown(report)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	return path, nil
+}
+
+// trackLabel formats a track as "Artist - Title", or just the title when the artist is unknown
+func trackLabel(track models.Track) string {
+	if track.Artist() == "" {
+		return track.Title
+	}
+	return track.Artist() + " - " + track.Title
+}
+
+// mdLink formats a Markdown link, or escaped plain text when there is no URL
+func mdLink(text, link string) string {
+	if link == "" {
+		return mdEscape(text)
+	}
+	return fmt.Sprintf("[%s](%s)", mdEscape(text), link)
+}
+
+// mdEscape escapes characters that would break Markdown tables and links
+func mdEscape(text string) string {
+	return strings.NewReplacer("|", `\|`, "[", `\[`, "]", `\]`, "\n", " ").Replace(text)
+}
@@ -0,0 +1,125 @@
+package formatter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+)
+
+func TestTransferReport(t *testing.T) {
+	report := TransferReport{
+		GeneratedAt:     time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		SourceService:   "spotify",
+		DestService:     "youtube",
+		SourcePlaylist:  models.Playlist{ID: "sp_pl", Name: "Road Trip"},
+		DestPlaylist:    &models.Playlist{ID: "yt_pl", Name: "Road Trip"},
+		MatchPercentage: 50,
+		Tracks: []TransferReportTrack{
+			{
+				Source:     models.Track{ID: "sp1", Title: "Song | One", Artists: []string{"Artist"}},
+				Match:      &models.Track{ID: "yt1", Title: "Song One", Artists: []string{"Artist"}},
+				Status:     ReportMatched,
+				Method:     "search",
+				Confidence: 0.875,
+			},
+			{Source: models.Track{ID: "sp2", Title: "Rare <B-side>", Artists: []string{"Artist"}}, Status: ReportFailed, Note: "track not found"},
+			{Source: models.Track{Title: "Episode 12"}, Status: ReportSkipped, Note: models.UnmatchableEpisode},
+			{Source: models.Track{Title: "Blocked", Artists: []string{"Artist"}}, Status: ReportUnavailable, Note: "track unavailable in this region"},
+		},
+		StartedAt:   time.Date(2024, 3, 1, 11, 58, 0, 0, time.UTC),
+		CompletedAt: time.Date(2024, 3, 1, 11, 59, 2, 500_000_000, time.UTC),
+		Phases: []ReportPhase{
+			{Name: "Fetch source", Duration: 430 * time.Millisecond},
+			{Name: "Search tracks", Duration: 58123 * time.Millisecond},
+		},
+	}
+
+	if report.Count(ReportMatched) != 1 || report.Count(ReportFailed) != 1 || report.Count(ReportSkipped) != 1 {
+		t.Errorf("unexpected counts: %d matched, %d failed, %d skipped",
+			report.Count(ReportMatched), report.Count(ReportFailed), report.Count(ReportSkipped))
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		data, err := ExportTransferReportMarkdown(report)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		md := string(data)
+		for _, want := range []string{
+			"# Transfer Report: Road Trip",
+			"[Road Trip (spotify)](https://open.spotify.com/playlist/sp_pl)",
+			"[Road Trip (youtube)](https://music.youtube.com/playlist?list=yt_pl)",
+			"| 1 | [Artist - Song \\| One](https://open.spotify.com/track/sp1) | [Artist - Song One](https://music.youtube.com/watch?v=yt1) | search | 88% |",
+			"## Failed",
+			"| 2 | [Artist - Rare <B-side>](https://open.spotify.com/track/sp2) | track not found |",
+			"| 3 | Episode 12 | podcast episode |",
+			"**Unavailable**: 1",
+			"## Unavailable",
+			"| 4 | Artist - Blocked | track unavailable in this region |",
+			"**Duration**: 1m2.5s (Fetch source 430ms, Search tracks 58.1s)",
+		} {
+			if !strings.Contains(md, want) {
+				t.Errorf("expected markdown to contain %q, got:\n%s", want, md)
+			}
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		data, err := ExportTransferReportHTML(report)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		page := string(data)
+		for _, want := range []string{
+			`<a href="https://open.spotify.com/track/sp1">Artist - Song | One</a>`,
+			`<a href="https://music.youtube.com/watch?v=yt1">Artist - Song One</a>`,
+			"Artist - Rare &lt;B-side&gt;",
+			"<td>88%</td>",
+			`<tr class="skipped">`,
+			`<li class="unavailable">Unavailable: 1</li>`,
+			"<li>Duration: 1m2.5s (Fetch source 430ms, Search tracks 58.1s)</li>",
+		} {
+			if !strings.Contains(page, want) {
+				t.Errorf("expected HTML to contain %q, got:\n%s", want, page)
+			}
+		}
+	})
+
+	t.Run("duration is left out when unknown", func(t *testing.T) {
+		untimed := report
+		untimed.StartedAt = time.Time{}
+		data, err := ExportTransferReportMarkdown(untimed)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if strings.Contains(string(data), "Duration") {
+			t.Errorf("expected no duration, got:\n%s", data)
+		}
+	})
+
+	t.Run("WriteTransferReport picks the format from the extension", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, prefix := range map[string]string{"report.md": "# Transfer Report", "report.html": "<!DOCTYPE html>"} {
+			path, err := WriteTransferReport(report, filepath.Join(dir, name))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read report: %v", err)
+			}
+			if !strings.HasPrefix(string(data), prefix) {
+				t.Errorf("%s: expected content starting with %q, got %.40q", name, prefix, data)
+			}
+		}
+
+		if _, err := WriteTransferReport(report, filepath.Join(dir, "missing", "report.md")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected a write error for a missing directory, got %v", err)
+		}
+	})
+}
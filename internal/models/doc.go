@@ -6,11 +6,15 @@
 //   - [Playlist] : Basic playlist metadata from music services
 //   - [PlaylistExport] : Playlist with complete track listing
 //   - [Track] : Song metadata with ISRC for cross-service matching
+//   - [Album] : Album metadata from music services
+//   - [Artist] : Artist metadata from music services
 //
 // 2. Persistent Entities: Database-backed models with full lifecycle management
 //   - [User] : User accounts with authentication and preferences
 //   - [PersistedPlaylist] : Cached playlists with service metadata
 //   - [PersistedTrack] : Cached tracks with ISRC for matching optimization
+//   - [PersistedAlbum] : Cached albums from library dumps
+//   - [PersistedArtist] : Cached artists from library dumps
 //   - [PlaylistTrack] : Junction table linking playlists to tracks with ordering
 //   - [MigrationJob] : Migration operations tracking progress and results
 //
@@ -2,7 +2,12 @@
 package models
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,11 +23,30 @@ type Model interface {
 // Repository defines the interface for data access operations.
 // Implementations handle database interactions for specific model types.
 type Repository[T Model] interface {
-	Create(model T) error                      // Create inserts a new model into the database
-	Get(id string) (T, error)                  // Get retrieves a model by its ID
-	Update(model T) error                      // Update modifies an existing model in the database
-	Delete(id string) error                    // Delete removes a model from the database by its ID
-	List(criteria map[string]any) ([]T, error) // List retrieves all models matching the given criteria
+	Create(ctx context.Context, model T) error                                     // Create inserts a new model into the database
+	Get(ctx context.Context, id string) (T, error)                                 // Get retrieves a model by its ID
+	Update(ctx context.Context, model T) error                                     // Update modifies an existing model in the database
+	Delete(ctx context.Context, id string) error                                   // Delete removes a model from the database by its ID
+	List(ctx context.Context, criteria map[string]any, opts ListOpts) ([]T, error) // List retrieves models matching the given criteria, one page at a time
+	Count(ctx context.Context, criteria map[string]any) (int, error)               // Count returns how many models match the given criteria
+}
+
+// OrderField names a column that List results can be sorted by.
+type OrderField string
+
+const (
+	OrderBySequence  OrderField = "sequence"
+	OrderByCreatedAt OrderField = "created_at"
+)
+
+// ListOpts controls ordering and pagination for [Repository.List].
+//
+// The zero value returns every row in the repository's default order.
+type ListOpts struct {
+	Limit   int        // Maximum number of rows to return; 0 means no limit
+	Offset  int        // Number of rows to skip
+	OrderBy OrderField // Sort column; empty uses the repository's default order
+	Desc    bool       // Sort descending instead of ascending (ignored when OrderBy is empty)
 }
 
 // Playlist represents a music playlist from any service
@@ -32,22 +56,175 @@ type Playlist struct {
 	Description string
 	TrackCount  int
 	Public      bool
+	ExternalURL string `json:",omitempty"` // Web URL of the playlist on its service; empty when unknown
+
+	Owner         string `json:",omitempty"` // Display name of the playlist's owner; empty when unknown
+	Collaborative bool   `json:",omitempty"` // Editable by users the owner invited (Spotify only)
+	Followers     int    `json:",omitempty"` // Number of followers; zero when unknown, and in playlist listings
 }
 
 // PlaylistExport represents a playlist with all its [Track] objects for migration
 type PlaylistExport struct {
-	Playlist Playlist
-	Tracks   []Track
+	Playlist   Playlist
+	Tracks     []Track
+	CoverImage string `json:",omitempty"` // Cover image file saved with the export, relative to it
 }
 
 // Track represents a music track from any service
 type Track struct {
-	ID       string
-	Title    string
-	Artist   string
-	Album    string
-	Duration int    // Duration in seconds
-	ISRC     string // International Standard Recording Code for matching
+	ID          string
+	Title       string
+	Artists     []string // Every credited artist, primary first; see [Track.Artist]
+	Album       string
+	Duration    int       // Duration in seconds
+	ISRC        string    // International Standard Recording Code for matching
+	Explicit    bool      `json:",omitempty"` // Marked explicit by its service; false for clean versions and when unknown
+	Unavailable bool      `json:",omitempty"` // The service reports it cannot be played in the account's region
+	AddedAt     time.Time `json:",omitzero"`  // When the track was added to its source playlist, or played for history; zero when unknown
+	URI         string    `json:",omitempty"` // Service URI (e.g. spotify:track:..., spotify:episode:...); empty when unknown
+	ReleaseDate string    `json:",omitempty"` // Album release date as YYYY, YYYY-MM, or YYYY-MM-DD; empty when unknown
+	ExternalURL string    `json:",omitempty"` // Web URL that opens the track on its service; empty when unknown
+
+	// Filled in by Spotify enrichment, which costs extra requests; empty when not enriched
+	Genres   []string       `json:",omitempty"` // Genres of the track's artists, primary artist's first
+	Features *AudioFeatures `json:",omitempty"`
+}
+
+// AudioFeatures are Spotify's audio analysis of a track. Scores run from 0 to 1.
+type AudioFeatures struct {
+	Danceability     float64
+	Energy           float64
+	Valence          float64 // Musical positiveness
+	Acousticness     float64
+	Instrumentalness float64
+	Speechiness      float64
+	Liveness         float64
+	Loudness         float64 // Average loudness in dB, typically -60 to 0
+	Tempo            float64 // Estimated beats per minute
+}
+
+// TrackURL returns the web URL of a track on service ("spotify" or "youtube"), or an empty string when unknown.
+func TrackURL(service, id string) string {
+	if id == "" {
+		return ""
+	}
+	switch service {
+	case "spotify":
+		return "https://open.spotify.com/track/" + url.PathEscape(id)
+	case "youtube":
+		return "https://music.youtube.com/watch?v=" + url.QueryEscape(id)
+	}
+	return ""
+}
+
+// PlaylistURL returns the web URL of a playlist on service ("spotify" or "youtube"), or an empty string when unknown.
+func PlaylistURL(service, id string) string {
+	if id == "" {
+		return ""
+	}
+	switch service {
+	case "spotify":
+		return "https://open.spotify.com/playlist/" + url.PathEscape(id)
+	case "youtube":
+		return "https://music.youtube.com/playlist?list=" + url.QueryEscape(id)
+	}
+	return ""
+}
+
+// ReleaseYear returns the year of the track's [Track.ReleaseDate], or 0 when it is unknown.
+func (t Track) ReleaseYear() int {
+	if len(t.ReleaseDate) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(t.ReleaseDate[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+// Artist returns the track's primary artist, the one searched for and shown, or an empty string when unknown.
+func (t Track) Artist() string {
+	if len(t.Artists) == 0 {
+		return ""
+	}
+	return t.Artists[0]
+}
+
+// SetArtists credits names to the track in order, skipping blank names, the first as its primary [Track.Artist].
+func (t *Track) SetArtists(names ...string) {
+	t.Artists = nil
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			t.Artists = append(t.Artists, name)
+		}
+	}
+}
+
+// trackFields has the fields of [Track] without its JSON methods
+type trackFields Track
+
+// trackJSON is the JSON form of a [Track]. Artist repeats the primary artist for readers of older exports, which
+// carry only Artist.
+type trackJSON struct {
+	trackFields
+	Artist string `json:",omitempty"`
+}
+
+// MarshalJSON encodes the track with its primary artist as Artist alongside Artists.
+func (t Track) MarshalJSON() ([]byte, error) {
+	return json.Marshal(trackJSON{trackFields(t), t.Artist()})
+}
+
+// UnmarshalJSON decodes a track, crediting Artist alone when an older export has no Artists.
+func (t *Track) UnmarshalJSON(data []byte) error {
+	var decoded trackJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*t = Track(decoded.trackFields)
+	if len(t.Artists) == 0 {
+		t.SetArtists(decoded.Artist)
+	}
+	return nil
+}
+
+// Reasons a [Track] can never be matched on another service
+const (
+	UnmatchableEpisode   = "podcast episode"
+	UnmatchableLocalFile = "local file"
+)
+
+// Unmatchable returns why the track can never be matched on another service, or an empty string when it can.
+//
+// Podcast episodes and local files are detected from their URI. Tracks with an ISRC are always matchable.
+func (t Track) Unmatchable() string {
+	if t.ISRC != "" {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(t.URI, "spotify:episode:"):
+		return UnmatchableEpisode
+	case strings.HasPrefix(t.URI, "spotify:local:"):
+		return UnmatchableLocalFile
+	}
+	return ""
+}
+
+// Album represents an album from any service
+type Album struct {
+	ID         string
+	Title      string
+	Artist     string
+	Year       string
+	TrackCount int
+	UPC        string // Universal Product Code for matching
+}
+
+// Artist represents an artist from any service
+type Artist struct {
+	ID   string
+	Name string
 }
 
 // User represents a user account in the persistence layer with authentication tokens, preferences, and migration history.
@@ -184,6 +361,7 @@ func (p *PersistedPlaylist) ToPlaylist() Playlist {
 		Description: p.description,
 		TrackCount:  p.trackCount,
 		Public:      p.public,
+		ExternalURL: PlaylistURL(p.service, p.serviceID),
 	}
 }
 
@@ -194,10 +372,12 @@ type PersistedTrack struct {
 	service   string
 	serviceID string
 	title     string
-	artist    string
+	artists   []string
 	album     string
 	duration  int
 	isrc      string
+	genres    []string
+	features  *AudioFeatures
 	createdAt time.Time
 	updatedAt time.Time
 	deletedAt *time.Time
@@ -215,7 +395,7 @@ func (t *PersistedTrack) Validate() error {
 	if t.service == "" || t.serviceID == "" {
 		return ErrInvalidModel
 	}
-	if t.title == "" || t.artist == "" {
+	if t.title == "" || t.Artist() == "" {
 		return ErrInvalidModel
 	}
 	return nil
@@ -229,10 +409,12 @@ func NewPersistedTrack(sequence int, service, serviceID string, track Track) *Pe
 		service:   service,
 		serviceID: serviceID,
 		title:     track.Title,
-		artist:    track.Artist,
+		artists:   track.Artists,
 		album:     track.Album,
 		duration:  track.Duration,
 		isrc:      track.ISRC,
+		genres:    track.Genres,
+		features:  track.Features,
 		createdAt: now,
 		updatedAt: now,
 	}
@@ -244,15 +426,31 @@ func (t *PersistedTrack) Service() string { return t.service }
 // ServiceID returns the service-specific track ID
 func (t *PersistedTrack) ServiceID() string { return t.serviceID }
 
-func (t *PersistedTrack) Title() string  { return t.title }
-func (t *PersistedTrack) Artist() string { return t.artist }
-func (t *PersistedTrack) Album() string  { return t.album }
-func (t *PersistedTrack) Duration() int  { return t.duration }
-func (t *PersistedTrack) Sequence() int  { return t.sequence }
+func (t *PersistedTrack) Title() string { return t.title }
+func (t *PersistedTrack) Album() string { return t.album }
+func (t *PersistedTrack) Duration() int { return t.duration }
+func (t *PersistedTrack) Sequence() int { return t.sequence }
+
+// Artist returns the primary artist, the first of [PersistedTrack.Artists]
+func (t *PersistedTrack) Artist() string {
+	if len(t.artists) == 0 {
+		return ""
+	}
+	return t.artists[0]
+}
+
+// Artists returns every credited artist, primary first
+func (t *PersistedTrack) Artists() []string { return t.artists }
 
 // ISRC returns the International Standard Recording Code
 func (t *PersistedTrack) ISRC() string { return t.isrc }
 
+// Genres returns the genres of the track's artists; empty when not enriched
+func (t *PersistedTrack) Genres() []string { return t.genres }
+
+// AudioFeatures returns the track's audio features; nil when not enriched
+func (t *PersistedTrack) AudioFeatures() *AudioFeatures { return t.features }
+
 // DeletedAt returns when this track was soft deleted (nil if not deleted)
 func (t *PersistedTrack) DeletedAt() *time.Time { return t.deletedAt }
 
@@ -263,15 +461,167 @@ func (t *PersistedTrack) SetDeletedAt(t2 *time.Time) { t.deletedAt = t2 }
 // ToTrack converts a PersistedTrack to a Track DTO
 func (t *PersistedTrack) ToTrack() Track {
 	return Track{
-		ID:       t.serviceID,
-		Title:    t.title,
-		Artist:   t.artist,
-		Album:    t.album,
-		Duration: t.duration,
-		ISRC:     t.isrc,
+		ID:          t.serviceID,
+		Title:       t.title,
+		Artists:     t.artists,
+		Album:       t.album,
+		Duration:    t.duration,
+		ISRC:        t.isrc,
+		Genres:      t.genres,
+		Features:    t.features,
+		ExternalURL: TrackURL(t.service, t.serviceID),
 	}
 }
 
+// PersistedAlbum represents an album cached in the persistence layer with service metadata.
+type PersistedAlbum struct {
+	id         string
+	sequence   int
+	service    string
+	serviceID  string
+	title      string
+	artist     string
+	year       string
+	trackCount int
+	upc        string
+	createdAt  time.Time
+	updatedAt  time.Time
+	deletedAt  *time.Time
+}
+
+func (a *PersistedAlbum) ID() string           { return a.id }
+func (a *PersistedAlbum) CreatedAt() time.Time { return a.createdAt }
+func (a *PersistedAlbum) UpdatedAt() time.Time { return a.updatedAt }
+
+// Validate checks if the album's data is valid
+func (a *PersistedAlbum) Validate() error {
+	if a.id == "" {
+		return ErrInvalidModel
+	}
+	if a.service == "" || a.serviceID == "" {
+		return ErrInvalidModel
+	}
+	if a.title == "" {
+		return ErrInvalidModel
+	}
+	return nil
+}
+
+// NewPersistedAlbum creates a new PersistedAlbum from an Album DTO
+func NewPersistedAlbum(sequence int, service, serviceID string, album Album) *PersistedAlbum {
+	now := time.Now()
+	return &PersistedAlbum{
+		sequence:   sequence,
+		service:    service,
+		serviceID:  serviceID,
+		title:      album.Title,
+		artist:     album.Artist,
+		year:       album.Year,
+		trackCount: album.TrackCount,
+		upc:        album.UPC,
+		createdAt:  now,
+		updatedAt:  now,
+	}
+}
+
+// Service returns the music service name (spotify, youtube)
+func (a *PersistedAlbum) Service() string { return a.service }
+
+// ServiceID returns the service-specific album ID
+func (a *PersistedAlbum) ServiceID() string { return a.serviceID }
+
+func (a *PersistedAlbum) Title() string   { return a.title }
+func (a *PersistedAlbum) Artist() string  { return a.artist }
+func (a *PersistedAlbum) Year() string    { return a.year }
+func (a *PersistedAlbum) TrackCount() int { return a.trackCount }
+func (a *PersistedAlbum) Sequence() int   { return a.sequence }
+
+// UPC returns the Universal Product Code
+func (a *PersistedAlbum) UPC() string { return a.upc }
+
+// DeletedAt returns when this album was soft deleted (nil if not deleted)
+func (a *PersistedAlbum) DeletedAt() *time.Time { return a.deletedAt }
+
+func (a *PersistedAlbum) SetID(id string)           { a.id = id }
+func (a *PersistedAlbum) SetUpdatedAt(t time.Time)  { a.updatedAt = t }
+func (a *PersistedAlbum) SetDeletedAt(t *time.Time) { a.deletedAt = t }
+
+// ToAlbum converts a PersistedAlbum to an Album DTO
+func (a *PersistedAlbum) ToAlbum() Album {
+	return Album{
+		ID:         a.serviceID,
+		Title:      a.title,
+		Artist:     a.artist,
+		Year:       a.year,
+		TrackCount: a.trackCount,
+		UPC:        a.upc,
+	}
+}
+
+// PersistedArtist represents an artist cached in the persistence layer with service metadata.
+type PersistedArtist struct {
+	id        string
+	sequence  int
+	service   string
+	serviceID string
+	name      string
+	createdAt time.Time
+	updatedAt time.Time
+	deletedAt *time.Time
+}
+
+func (a *PersistedArtist) ID() string           { return a.id }
+func (a *PersistedArtist) CreatedAt() time.Time { return a.createdAt }
+func (a *PersistedArtist) UpdatedAt() time.Time { return a.updatedAt }
+
+// Validate checks if the artist's data is valid
+func (a *PersistedArtist) Validate() error {
+	if a.id == "" {
+		return ErrInvalidModel
+	}
+	if a.service == "" || a.serviceID == "" {
+		return ErrInvalidModel
+	}
+	if a.name == "" {
+		return ErrInvalidModel
+	}
+	return nil
+}
+
+// NewPersistedArtist creates a new PersistedArtist from an Artist DTO
+func NewPersistedArtist(sequence int, service, serviceID string, artist Artist) *PersistedArtist {
+	now := time.Now()
+	return &PersistedArtist{
+		sequence:  sequence,
+		service:   service,
+		serviceID: serviceID,
+		name:      artist.Name,
+		createdAt: now,
+		updatedAt: now,
+	}
+}
+
+// Service returns the music service name (spotify, youtube)
+func (a *PersistedArtist) Service() string { return a.service }
+
+// ServiceID returns the service-specific artist ID
+func (a *PersistedArtist) ServiceID() string { return a.serviceID }
+
+func (a *PersistedArtist) Name() string  { return a.name }
+func (a *PersistedArtist) Sequence() int { return a.sequence }
+
+// DeletedAt returns when this artist was soft deleted (nil if not deleted)
+func (a *PersistedArtist) DeletedAt() *time.Time { return a.deletedAt }
+
+func (a *PersistedArtist) SetID(id string)           { a.id = id }
+func (a *PersistedArtist) SetUpdatedAt(t time.Time)  { a.updatedAt = t }
+func (a *PersistedArtist) SetDeletedAt(t *time.Time) { a.deletedAt = t }
+
+// ToArtist converts a PersistedArtist to an Artist DTO
+func (a *PersistedArtist) ToArtist() Artist {
+	return Artist{ID: a.serviceID, Name: a.name}
+}
+
 // PlaylistTrack represents a track within a playlist with ordering via position field.
 type PlaylistTrack struct {
 	id         string
@@ -303,7 +653,8 @@ func (pt *PlaylistTrack) TrackID() string    { return pt.trackID }
 func (pt *PlaylistTrack) Position() int      { return pt.position }
 func (pt *PlaylistTrack) Sequence() int      { return pt.sequence }
 
-func (pt *PlaylistTrack) SetID(id string) { pt.id = id }
+func (pt *PlaylistTrack) SetID(id string)          { pt.id = id }
+func (pt *PlaylistTrack) SetPosition(position int) { pt.position = position }
 
 // DeletedAt returns when this playlist-track was soft deleted (nil if not deleted)
 func (pt *PlaylistTrack) DeletedAt() *time.Time { return pt.deletedAt }
@@ -338,6 +689,9 @@ type MigrationJob struct {
 	errorMessage     string
 	startedAt        *time.Time
 	completedAt      *time.Time
+	progressCurrent  int
+	progressTotal    int
+	lastProgressAt   *time.Time
 	createdAt        time.Time
 	updatedAt        time.Time
 	deletedAt        *time.Time
@@ -401,6 +755,23 @@ func (m *MigrationJob) StartedAt() *time.Time    { return m.startedAt }
 func (m *MigrationJob) CompletedAt() *time.Time  { return m.completedAt }
 func (m *MigrationJob) Sequence() int            { return m.sequence }
 
+// ProgressCurrent returns the number of tracks processed so far
+func (m *MigrationJob) ProgressCurrent() int { return m.progressCurrent }
+
+// ProgressTotal returns the number of tracks to process (0 if unknown)
+func (m *MigrationJob) ProgressTotal() int { return m.progressTotal }
+
+// LastProgressAt returns when progress was last persisted (nil if never)
+func (m *MigrationJob) LastProgressAt() *time.Time { return m.lastProgressAt }
+
+// ProgressPercent returns progress as a percentage, or 0 when the total is unknown
+func (m *MigrationJob) ProgressPercent() float64 {
+	if m.progressTotal <= 0 {
+		return 0
+	}
+	return float64(m.progressCurrent) / float64(m.progressTotal) * 100
+}
+
 // DeletedAt returns when this migration was soft deleted (nil if not deleted)
 func (m *MigrationJob) DeletedAt() *time.Time { return m.deletedAt }
 
@@ -418,5 +789,41 @@ func (m *MigrationJob) SetErrorMessage(msg string)     { m.errorMessage = msg }
 func (m *MigrationJob) SetStartedAt(t *time.Time)      { m.startedAt = t }
 func (m *MigrationJob) SetCompletedAt(t *time.Time)    { m.completedAt = t }
 
+// SetProgress records how many of total tracks have been processed as of at
+func (m *MigrationJob) SetProgress(current, total int, at *time.Time) {
+	m.progressCurrent = current
+	m.progressTotal = total
+	m.lastProgressAt = at
+}
+
+// TransferRecord summarizes a finished playlist transfer for the migration history.
+type TransferRecord struct {
+	SourceService  string
+	SourcePlaylist Playlist
+	TargetService  string
+	TargetPlaylist *Playlist // Nil when no destination playlist was created
+	TracksTotal    int
+	TracksMigrated int
+	Failures       []Track // Source tracks that could not be matched
+	StartedAt      time.Time
+	CompletedAt    time.Time
+	Error          string // Empty when the transfer succeeded
+}
+
+// Ignore list entry kinds
+const (
+	IgnoreKindISRC   = "isrc"
+	IgnoreKindArtist = "artist"
+)
+
+// IgnoreEntry is an ISRC or artist name that transfers and diffs skip.
+type IgnoreEntry struct {
+	ID        string
+	Kind      string // IgnoreKindISRC or IgnoreKindArtist
+	Value     string // Value as entered by the user
+	Reason    string
+	CreatedAt time.Time
+}
+
 // ErrInvalidModel is returned when a model fails validation
 var ErrInvalidModel = fmt.Errorf("invalid model")
@@ -0,0 +1,370 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// AlbumRepository implements models.Repository[*models.PersistedAlbum] for album caching.
+//
+// Handles album CRUD operations with soft delete support and service-specific lookups.
+// Albums carrying a UPC can be matched across services via [AlbumRepository.GetByUPC].
+type AlbumRepository struct {
+	db *sql.DB
+}
+
+// NewAlbumRepository creates a new AlbumRepository with the given database connection
+func NewAlbumRepository(db *sql.DB) *AlbumRepository {
+	return &AlbumRepository{db: db}
+}
+
+// Create inserts a new [models.PersistedAlbum] into the database with generated ID and sequence
+func (r *AlbumRepository) Create(ctx context.Context, album *models.PersistedAlbum) error {
+	sequence, err := NextSequence(ctx, r.db, "albums")
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	id := shared.GenerateID()
+	album.SetID(id)
+
+	if err := album.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	query := `
+		INSERT INTO albums (id, sequence, service, service_id, title, artist, year, track_count, upc, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		id,
+		sequence,
+		album.Service(),
+		album.ServiceID(),
+		album.Title(),
+		album.Artist(),
+		album.Year(),
+		album.TrackCount(),
+		album.UPC(),
+		album.CreatedAt(),
+		album.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert album: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an album by ID, excluding soft-deleted albums
+func (r *AlbumRepository) Get(ctx context.Context, id string) (*models.PersistedAlbum, error) {
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, year, track_count, upc, created_at, updated_at, deleted_at
+		FROM albums
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByServiceID retrieves an album by service and service_id
+func (r *AlbumRepository) GetByServiceID(ctx context.Context, service, serviceID string) (*models.PersistedAlbum, error) {
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, year, track_count, upc, created_at, updated_at, deleted_at
+		FROM albums
+		WHERE service = ? AND service_id = ? AND deleted_at IS NULL
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, service, serviceID))
+}
+
+// GetByUPC retrieves an album by UPC code across any service.
+// Returns [shared.ErrRecordNotFound] for an empty UPC rather than matching albums cached without one.
+func (r *AlbumRepository) GetByUPC(ctx context.Context, upc string) (*models.PersistedAlbum, error) {
+	if upc == "" {
+		return nil, fmt.Errorf("%w: no UPC to look up", shared.ErrRecordNotFound)
+	}
+
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, year, track_count, upc, created_at, updated_at, deleted_at
+		FROM albums
+		WHERE upc = ? AND deleted_at IS NULL
+		LIMIT 1
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, upc))
+}
+
+// Update modifies an existing album in the database
+func (r *AlbumRepository) Update(ctx context.Context, album *models.PersistedAlbum) error {
+	if err := album.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	album.SetUpdatedAt(now)
+
+	query := `
+		UPDATE albums
+		SET title = ?, artist = ?, year = ?, track_count = ?, upc = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		album.Title(),
+		album.Artist(),
+		album.Year(),
+		album.TrackCount(),
+		album.UPC(),
+		now,
+		album.ID(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update album: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("album not found or already deleted: %s", album.ID())
+	}
+
+	return nil
+}
+
+// Delete soft-deletes an album by ID
+func (r *AlbumRepository) Delete(ctx context.Context, id string) error {
+	now := time.Now()
+
+	query := `
+		UPDATE albums
+		SET deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete album: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("album not found or already deleted: %s", id)
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently removes albums soft-deleted more than olderThan ago.
+// A zero olderThan purges every soft-deleted row. Returns the number of albums removed.
+func (r *AlbumRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return purgeDeleted(ctx, r.db, "albums", olderThan)
+}
+
+// Restore undoes the soft delete of an album by ID, rejecting conflicts on service+service_id
+func (r *AlbumRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, r.db, "albums", id, "service", "service_id")
+}
+
+// ListDeleted retrieves all soft-deleted albums, most recently deleted first
+func (r *AlbumRepository) ListDeleted(ctx context.Context) ([]*models.PersistedAlbum, error) {
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, year, track_count, upc, created_at, updated_at, deleted_at
+		FROM albums
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted albums: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []*models.PersistedAlbum
+	for rows.Next() {
+		item, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// List retrieves all albums matching the given criteria, excluding soft-deleted albums
+func (r *AlbumRepository) List(ctx context.Context, criteria map[string]any, opts models.ListOpts) ([]*models.PersistedAlbum, error) {
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, year, track_count, upc, created_at, updated_at, deleted_at
+		FROM albums
+		WHERE deleted_at IS NULL
+	`
+
+	where, args := r.filter(criteria)
+	query += where
+
+	clause, pageArgs, err := listClause(opts, "sequence ASC")
+	if err != nil {
+		return nil, err
+	}
+	query += clause
+	args = append(args, pageArgs...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query albums: %w", err)
+	}
+	defer rows.Close()
+
+	var albums []*models.PersistedAlbum
+	for rows.Next() {
+		album, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return albums, nil
+}
+
+// Count returns the number of albums matching the given criteria, excluding soft-deleted albums
+func (r *AlbumRepository) Count(ctx context.Context, criteria map[string]any) (int, error) {
+	where, args := r.filter(criteria)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM albums WHERE deleted_at IS NULL"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count albums: %w", err)
+	}
+
+	return count, nil
+}
+
+// filter narrows albums by "service", "artist", and "upc"
+func (r *AlbumRepository) filter(criteria map[string]any) (string, []any) {
+	query := ""
+	args := []any{}
+
+	if service, ok := criteria["service"].(string); ok && service != "" {
+		query += " AND service = ?"
+		args = append(args, service)
+	}
+
+	if artist, ok := criteria["artist"].(string); ok && artist != "" {
+		query += " AND artist = ?"
+		args = append(args, artist)
+	}
+
+	if upc, ok := criteria["upc"].(string); ok && upc != "" {
+		query += " AND upc = ?"
+		args = append(args, upc)
+	}
+
+	return query, args
+}
+
+// scanOne scans a single [sql.Row] into a [models.PersistedAlbum]
+func (r *AlbumRepository) scanOne(row *sql.Row) (*models.PersistedAlbum, error) {
+	var (
+		id         string
+		sequence   int
+		service    string
+		serviceID  string
+		title      string
+		artist     sql.NullString
+		year       sql.NullString
+		trackCount int
+		upc        sql.NullString
+		createdAt  time.Time
+		updatedAt  time.Time
+		deletedAt  sql.NullTime
+	)
+
+	err := row.Scan(&id, &sequence, &service, &serviceID, &title, &artist, &year, &trackCount, &upc, &createdAt, &updatedAt, &deletedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("album not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan album: %w", err)
+	}
+
+	dto := models.Album{
+		ID:         serviceID,
+		Title:      title,
+		Artist:     artist.String,
+		Year:       year.String,
+		TrackCount: trackCount,
+		UPC:        upc.String,
+	}
+
+	album := models.NewPersistedAlbum(sequence, service, serviceID, dto)
+	album.SetID(id)
+	album.SetUpdatedAt(updatedAt)
+	if deletedAt.Valid {
+		album.SetDeletedAt(&deletedAt.Time)
+	}
+
+	return album, nil
+}
+
+// scanRow scans a row from [sql.Rows] into a [models.PersistedAlbum]
+func (r *AlbumRepository) scanRow(rows *sql.Rows) (*models.PersistedAlbum, error) {
+	var (
+		id         string
+		sequence   int
+		service    string
+		serviceID  string
+		title      string
+		artist     sql.NullString
+		year       sql.NullString
+		trackCount int
+		upc        sql.NullString
+		createdAt  time.Time
+		updatedAt  time.Time
+		deletedAt  sql.NullTime
+	)
+
+	err := rows.Scan(&id, &sequence, &service, &serviceID, &title, &artist, &year, &trackCount, &upc, &createdAt, &updatedAt, &deletedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan album: %w", err)
+	}
+
+	dto := models.Album{
+		ID:         serviceID,
+		Title:      title,
+		Artist:     artist.String,
+		Year:       year.String,
+		TrackCount: trackCount,
+		UPC:        upc.String,
+	}
+
+	album := models.NewPersistedAlbum(sequence, service, serviceID, dto)
+	album.SetID(id)
+	album.SetUpdatedAt(updatedAt)
+	if deletedAt.Valid {
+		album.SetDeletedAt(&deletedAt.Time)
+	}
+
+	return album, nil
+}
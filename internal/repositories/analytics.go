@@ -0,0 +1,303 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// AnalyticsOpts controls the size of the breakdowns returned by [CollectAnalytics].
+type AnalyticsOpts struct {
+	Days       int // Number of most recent days in the match rate trend; 0 includes every day
+	TopArtists int // Maximum number of artists in the failure ranking; 0 includes every artist
+}
+
+// DailyMatchRate summarizes the migrations started on a single day.
+type DailyMatchRate struct {
+	Date           string  `json:"date"` // Local date in YYYY-MM-DD form
+	Migrations     int     `json:"migrations"`
+	TracksTotal    int     `json:"tracks_total"`
+	TracksMigrated int     `json:"tracks_migrated"`
+	MatchRate      float64 `json:"match_rate"` // Percentage of tracks matched
+}
+
+// ArtistFailures counts the unmatched tracks recorded for an artist.
+type ArtistFailures struct {
+	Artist   string `json:"artist"`
+	Failures int    `json:"failures"`
+}
+
+// ServiceCounts counts the live cached rows and migrations for a single service.
+type ServiceCounts struct {
+	Service        string `json:"service"`
+	Playlists      int    `json:"playlists"`
+	Tracks         int    `json:"tracks"`
+	Albums         int    `json:"albums"`
+	Artists        int    `json:"artists"`
+	MigrationsFrom int    `json:"migrations_from"` // Migrations using this service as the source
+	MigrationsTo   int    `json:"migrations_to"`   // Migrations using this service as the target
+}
+
+// MigrationAnalytics aggregates the migration history and local cache.
+//
+// Soft-deleted migrations and cached rows are excluded throughout.
+type MigrationAnalytics struct {
+	Migrations        int              `json:"migrations"`
+	Completed         int              `json:"completed"`
+	Failed            int              `json:"failed"`
+	TracksTotal       int              `json:"tracks_total"`
+	TracksMigrated    int              `json:"tracks_migrated"`
+	TracksFailed      int              `json:"tracks_failed"`
+	MatchRate         float64          `json:"match_rate"`               // Percentage of tracks matched across all migrations
+	AverageDuration   float64          `json:"average_duration_seconds"` // Mean wall time of migrations with start and end times
+	CachedSearches    int              `json:"cached_searches"`
+	MatchRateByDay    []DailyMatchRate `json:"match_rate_by_day"`
+	MostFailedArtists []ArtistFailures `json:"most_failed_artists"`
+	Services          []ServiceCounts  `json:"services"`
+}
+
+// CollectAnalytics aggregates migration totals, match rates, failures, and per-service cache counts.
+func CollectAnalytics(ctx context.Context, db *sql.DB, opts AnalyticsOpts) (*MigrationAnalytics, error) {
+	if opts.Days < 0 || opts.TopArtists < 0 {
+		return nil, fmt.Errorf("%w: analytics options cannot be negative", shared.ErrInvalidInput)
+	}
+
+	analytics := &MigrationAnalytics{
+		MatchRateByDay:    []DailyMatchRate{},
+		MostFailedArtists: []ArtistFailures{},
+		Services:          []ServiceCounts{},
+	}
+
+	err := db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(status = 'completed'), 0),
+			COALESCE(SUM(status = 'failed'), 0),
+			COALESCE(SUM(tracks_total), 0),
+			COALESCE(SUM(tracks_migrated), 0),
+			COALESCE(SUM(tracks_failed), 0)
+		FROM migrations
+		WHERE deleted_at IS NULL
+	`).Scan(
+		&analytics.Migrations,
+		&analytics.Completed,
+		&analytics.Failed,
+		&analytics.TracksTotal,
+		&analytics.TracksMigrated,
+		&analytics.TracksFailed,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total migrations: %w", err)
+	}
+	analytics.MatchRate = matchRate(analytics.TracksMigrated, analytics.TracksTotal)
+
+	if analytics.AverageDuration, err = averageMigrationDuration(ctx, db); err != nil {
+		return nil, err
+	}
+
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM search_cache").Scan(&analytics.CachedSearches); err != nil {
+		return nil, fmt.Errorf("failed to count cached searches: %w", err)
+	}
+
+	if analytics.MatchRateByDay, err = dailyMatchRates(ctx, db, opts.Days); err != nil {
+		return nil, err
+	}
+
+	if analytics.MostFailedArtists, err = mostFailedArtists(ctx, db, opts.TopArtists); err != nil {
+		return nil, err
+	}
+
+	if analytics.Services, err = serviceCounts(ctx, db); err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+// matchRate returns migrated as a percentage of total, or zero when there were no tracks
+func matchRate(migrated, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(migrated) / float64(total) * 100
+}
+
+// averageMigrationDuration returns the mean wall time in seconds of migrations that recorded start and end times.
+//
+// Durations are computed in Go because timestamps are stored as text with zone offsets.
+func averageMigrationDuration(ctx context.Context, db *sql.DB) (float64, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT started_at, completed_at
+		FROM migrations
+		WHERE deleted_at IS NULL AND started_at IS NOT NULL AND completed_at IS NOT NULL
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query migration durations: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		total time.Duration
+		count int
+	)
+	for rows.Next() {
+		var startedAt, completedAt time.Time
+		if err := rows.Scan(&startedAt, &completedAt); err != nil {
+			return 0, fmt.Errorf("failed to scan migration duration: %w", err)
+		}
+		total += completedAt.Sub(startedAt)
+		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+	return (total / time.Duration(count)).Seconds(), nil
+}
+
+// dailyMatchRates groups migrations by the local day they started, oldest first, limited to the last days days
+func dailyMatchRates(ctx context.Context, db *sql.DB, days int) ([]DailyMatchRate, error) {
+	day := shared.DialectOf(db).Day("COALESCE(started_at, created_at)")
+	query := `
+		SELECT ` + day + ` AS day, COUNT(*), SUM(tracks_total), SUM(tracks_migrated)
+		FROM migrations
+		WHERE deleted_at IS NULL
+	`
+	args := []any{}
+
+	if days > 0 {
+		query += " AND " + day + " >= ?"
+		args = append(args, time.Now().AddDate(0, 0, 1-days).Format("2006-01-02"))
+	}
+	query += " GROUP BY day ORDER BY day ASC"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily match rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []DailyMatchRate{}
+	for rows.Next() {
+		var rate DailyMatchRate
+		if err := rows.Scan(&rate.Date, &rate.Migrations, &rate.TracksTotal, &rate.TracksMigrated); err != nil {
+			return nil, fmt.Errorf("failed to scan daily match rate: %w", err)
+		}
+		rate.MatchRate = matchRate(rate.TracksMigrated, rate.TracksTotal)
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return rates, nil
+}
+
+// mostFailedArtists ranks artists by recorded unmatched tracks, limited to the top entries when top is positive
+func mostFailedArtists(ctx context.Context, db *sql.DB, top int) ([]ArtistFailures, error) {
+	query := `
+		SELECT COALESCE(NULLIF(f.artist, ''), 'Unknown Artist') AS name, COUNT(*) AS failures
+		FROM migration_failures f
+		JOIN migrations m ON m.id = f.migration_id
+		WHERE m.deleted_at IS NULL
+		GROUP BY name
+		ORDER BY failures DESC, name ASC
+	`
+	args := []any{}
+
+	if top > 0 {
+		query += " LIMIT ?"
+		args = append(args, top)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query failed artists: %w", err)
+	}
+	defer rows.Close()
+
+	artists := []ArtistFailures{}
+	for rows.Next() {
+		var artist ArtistFailures
+		if err := rows.Scan(&artist.Artist, &artist.Failures); err != nil {
+			return nil, fmt.Errorf("failed to scan failed artist: %w", err)
+		}
+		artists = append(artists, artist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return artists, nil
+}
+
+// serviceCounts counts live cached rows and migrations per service, sorted by service name
+func serviceCounts(ctx context.Context, db *sql.DB) ([]ServiceCounts, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT service, 'playlists', COUNT(*) FROM playlists WHERE deleted_at IS NULL GROUP BY service
+		UNION ALL SELECT service, 'tracks', COUNT(*) FROM tracks WHERE deleted_at IS NULL GROUP BY service
+		UNION ALL SELECT service, 'albums', COUNT(*) FROM albums WHERE deleted_at IS NULL GROUP BY service
+		UNION ALL SELECT service, 'artists', COUNT(*) FROM artists WHERE deleted_at IS NULL GROUP BY service
+		UNION ALL SELECT source_service, 'from', COUNT(*) FROM migrations WHERE deleted_at IS NULL GROUP BY source_service
+		UNION ALL SELECT target_service, 'to', COUNT(*) FROM migrations WHERE deleted_at IS NULL GROUP BY target_service
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service counts: %w", err)
+	}
+	defer rows.Close()
+
+	byService := map[string]*ServiceCounts{}
+	for rows.Next() {
+		var (
+			service string
+			kind    string
+			count   int
+		)
+		if err := rows.Scan(&service, &kind, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan service count: %w", err)
+		}
+
+		counts, ok := byService[service]
+		if !ok {
+			counts = &ServiceCounts{Service: service}
+			byService[service] = counts
+		}
+
+		switch kind {
+		case "playlists":
+			counts.Playlists = count
+		case "tracks":
+			counts.Tracks = count
+		case "albums":
+			counts.Albums = count
+		case "artists":
+			counts.Artists = count
+		case "from":
+			counts.MigrationsFrom = count
+		case "to":
+			counts.MigrationsTo = count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	services := make([]ServiceCounts, 0, len(byService))
+	for _, counts := range byService {
+		services = append(services, *counts)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Service < services[j].Service })
+
+	return services, nil
+}
@@ -0,0 +1,323 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// ArtistRepository implements models.Repository[*models.PersistedArtist] for artist caching.
+//
+// Handles artist CRUD operations with soft delete support and service-specific lookups.
+// Artists have no shared identifier across services, so [ArtistRepository.GetByName] matches case-insensitively by name.
+type ArtistRepository struct {
+	db *sql.DB
+}
+
+// NewArtistRepository creates a new ArtistRepository with the given database connection
+func NewArtistRepository(db *sql.DB) *ArtistRepository {
+	return &ArtistRepository{db: db}
+}
+
+// Create inserts a new [models.PersistedArtist] into the database with generated ID and sequence
+func (r *ArtistRepository) Create(ctx context.Context, artist *models.PersistedArtist) error {
+	sequence, err := NextSequence(ctx, r.db, "artists")
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	id := shared.GenerateID()
+	artist.SetID(id)
+
+	if err := artist.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	query := `
+		INSERT INTO artists (id, sequence, service, service_id, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		id,
+		sequence,
+		artist.Service(),
+		artist.ServiceID(),
+		artist.Name(),
+		artist.CreatedAt(),
+		artist.UpdatedAt(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert artist: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an artist by ID, excluding soft-deleted artists
+func (r *ArtistRepository) Get(ctx context.Context, id string) (*models.PersistedArtist, error) {
+	query := `
+		SELECT id, sequence, service, service_id, name, created_at, updated_at, deleted_at
+		FROM artists
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByServiceID retrieves an artist by service and service_id
+func (r *ArtistRepository) GetByServiceID(ctx context.Context, service, serviceID string) (*models.PersistedArtist, error) {
+	query := `
+		SELECT id, sequence, service, service_id, name, created_at, updated_at, deleted_at
+		FROM artists
+		WHERE service = ? AND service_id = ? AND deleted_at IS NULL
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, service, serviceID))
+}
+
+// GetByName retrieves the first artist with the given name across any service
+func (r *ArtistRepository) GetByName(ctx context.Context, name string) (*models.PersistedArtist, error) {
+	query := `
+		SELECT id, sequence, service, service_id, name, created_at, updated_at, deleted_at
+		FROM artists
+		WHERE ` + shared.DialectOf(r.db).FoldCase("name") + ` AND deleted_at IS NULL
+		ORDER BY sequence ASC
+		LIMIT 1
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, name))
+}
+
+// Update modifies an existing artist in the database
+func (r *ArtistRepository) Update(ctx context.Context, artist *models.PersistedArtist) error {
+	if err := artist.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	artist.SetUpdatedAt(now)
+
+	query := `
+		UPDATE artists
+		SET name = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, artist.Name(), now, artist.ID())
+	if err != nil {
+		return fmt.Errorf("failed to update artist: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("artist not found or already deleted: %s", artist.ID())
+	}
+
+	return nil
+}
+
+// Delete soft-deletes an artist by ID
+func (r *ArtistRepository) Delete(ctx context.Context, id string) error {
+	now := time.Now()
+
+	query := `
+		UPDATE artists
+		SET deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete artist: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("artist not found or already deleted: %s", id)
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently removes artists soft-deleted more than olderThan ago.
+// A zero olderThan purges every soft-deleted row. Returns the number of artists removed.
+func (r *ArtistRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return purgeDeleted(ctx, r.db, "artists", olderThan)
+}
+
+// Restore undoes the soft delete of an artist by ID, rejecting conflicts on service+service_id
+func (r *ArtistRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, r.db, "artists", id, "service", "service_id")
+}
+
+// ListDeleted retrieves all soft-deleted artists, most recently deleted first
+func (r *ArtistRepository) ListDeleted(ctx context.Context) ([]*models.PersistedArtist, error) {
+	query := `
+		SELECT id, sequence, service, service_id, name, created_at, updated_at, deleted_at
+		FROM artists
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted artists: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []*models.PersistedArtist
+	for rows.Next() {
+		item, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// List retrieves all artists matching the given criteria, excluding soft-deleted artists
+func (r *ArtistRepository) List(ctx context.Context, criteria map[string]any, opts models.ListOpts) ([]*models.PersistedArtist, error) {
+	query := `
+		SELECT id, sequence, service, service_id, name, created_at, updated_at, deleted_at
+		FROM artists
+		WHERE deleted_at IS NULL
+	`
+
+	where, args := r.filter(criteria)
+	query += where
+
+	clause, pageArgs, err := listClause(opts, "sequence ASC")
+	if err != nil {
+		return nil, err
+	}
+	query += clause
+	args = append(args, pageArgs...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query artists: %w", err)
+	}
+	defer rows.Close()
+
+	var artists []*models.PersistedArtist
+	for rows.Next() {
+		artist, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		artists = append(artists, artist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return artists, nil
+}
+
+// Count returns the number of artists matching the given criteria, excluding soft-deleted artists
+func (r *ArtistRepository) Count(ctx context.Context, criteria map[string]any) (int, error) {
+	where, args := r.filter(criteria)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM artists WHERE deleted_at IS NULL"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count artists: %w", err)
+	}
+
+	return count, nil
+}
+
+// filter matches artists by "service" and exact "name"
+func (r *ArtistRepository) filter(criteria map[string]any) (string, []any) {
+	query := ""
+	args := []any{}
+
+	if service, ok := criteria["service"].(string); ok && service != "" {
+		query += " AND service = ?"
+		args = append(args, service)
+	}
+
+	if name, ok := criteria["name"].(string); ok && name != "" {
+		query += " AND name = ?"
+		args = append(args, name)
+	}
+
+	return query, args
+}
+
+// scanOne scans a single [sql.Row] into a [models.PersistedArtist]
+func (r *ArtistRepository) scanOne(row *sql.Row) (*models.PersistedArtist, error) {
+	var (
+		id        string
+		sequence  int
+		service   string
+		serviceID string
+		name      string
+		createdAt time.Time
+		updatedAt time.Time
+		deletedAt sql.NullTime
+	)
+
+	err := row.Scan(&id, &sequence, &service, &serviceID, &name, &createdAt, &updatedAt, &deletedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("artist not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan artist: %w", err)
+	}
+
+	artist := models.NewPersistedArtist(sequence, service, serviceID, models.Artist{ID: serviceID, Name: name})
+	artist.SetID(id)
+	artist.SetUpdatedAt(updatedAt)
+	if deletedAt.Valid {
+		artist.SetDeletedAt(&deletedAt.Time)
+	}
+
+	return artist, nil
+}
+
+// scanRow scans a row from [sql.Rows] into a [models.PersistedArtist]
+func (r *ArtistRepository) scanRow(rows *sql.Rows) (*models.PersistedArtist, error) {
+	var (
+		id        string
+		sequence  int
+		service   string
+		serviceID string
+		name      string
+		createdAt time.Time
+		updatedAt time.Time
+		deletedAt sql.NullTime
+	)
+
+	err := rows.Scan(&id, &sequence, &service, &serviceID, &name, &createdAt, &updatedAt, &deletedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan artist: %w", err)
+	}
+
+	artist := models.NewPersistedArtist(sequence, service, serviceID, models.Artist{ID: serviceID, Name: name})
+	artist.SetID(id)
+	artist.SetUpdatedAt(updatedAt)
+	if deletedAt.Valid {
+		artist.SetDeletedAt(&deletedAt.Time)
+	}
+
+	return artist, nil
+}
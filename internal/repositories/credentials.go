@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+	"golang.org/x/oauth2"
+)
+
+// CredentialRepository stores each user's service credentials for the web server: OAuth tokens and the auth headers
+// the YouTube Music proxy is called with. Rows are keyed by user and service and hard-deleted, like the ignore list.
+//
+// Every value is encrypted with a key derived from the server secret and sealed for its own user, service, and
+// column, so a value copied to another user's row cannot be read there.
+type CredentialRepository struct {
+	db  *sql.DB
+	box *shared.SecretBox
+}
+
+// NewCredentialRepository creates a new CredentialRepository encrypting with a key derived from secret
+func NewCredentialRepository(db *sql.DB, secret []byte) (*CredentialRepository, error) {
+	box, err := shared.NewSecretBox(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &CredentialRepository{db: db, box: box}, nil
+}
+
+// SaveToken stores a user's OAuth token for service, replacing any previous one
+func (r *CredentialRepository) SaveToken(ctx context.Context, userID, service string, token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	if err := r.save(ctx, "token", userID, service, string(data)); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	return nil
+}
+
+// Token returns a user's OAuth token for service.
+//
+// Returns [shared.ErrRecordNotFound] when the user has not connected the service.
+func (r *CredentialRepository) Token(ctx context.Context, userID, service string) (*oauth2.Token, error) {
+	data, err := r.column(ctx, "token", userID, service)
+	if err != nil {
+		return nil, err
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token: %w", err)
+	}
+	return &token, nil
+}
+
+// SaveAuthHeaders stores the contents of a user's auth file for service (browser headers or OAuth JSON), replacing
+// any previous one
+func (r *CredentialRepository) SaveAuthHeaders(ctx context.Context, userID, service string, headers []byte) error {
+	if err := r.save(ctx, "auth_headers", userID, service, string(headers)); err != nil {
+		return fmt.Errorf("failed to save auth headers: %w", err)
+	}
+	return nil
+}
+
+// AuthHeaders returns the contents of a user's auth file for service.
+//
+// Returns [shared.ErrRecordNotFound] when the user has not connected the service.
+func (r *CredentialRepository) AuthHeaders(ctx context.Context, userID, service string) ([]byte, error) {
+	headers, err := r.column(ctx, "auth_headers", userID, service)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(headers), nil
+}
+
+// Services returns the services a user has stored credentials for, in name order
+func (r *CredentialRepository) Services(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT service FROM user_credentials WHERE user_id = ? ORDER BY service", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query credentials: %w", err)
+	}
+	defer rows.Close()
+
+	var services []string
+	for rows.Next() {
+		var service string
+		if err := rows.Scan(&service); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, service)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return services, nil
+}
+
+// Delete removes a user's credentials for service.
+//
+// Returns [shared.ErrRecordNotFound] when there were none.
+func (r *CredentialRepository) Delete(ctx context.Context, userID, service string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM user_credentials WHERE user_id = ? AND service = ?", userID, service)
+	if err != nil {
+		return fmt.Errorf("failed to delete credentials: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: no %s credentials for user %s", shared.ErrRecordNotFound, service, userID)
+	}
+	return nil
+}
+
+// column reads and decrypts one nullable credential column, treating NULL as missing
+func (r *CredentialRepository) column(ctx context.Context, column, userID, service string) (string, error) {
+	var value sql.NullString
+	query := fmt.Sprintf("SELECT %s FROM user_credentials WHERE user_id = ? AND service = ?", column)
+	err := r.db.QueryRowContext(ctx, query, userID, service).Scan(&value)
+	if err == sql.ErrNoRows || (err == nil && !value.Valid) {
+		return "", fmt.Errorf("%w: no %s %s for user %s", shared.ErrRecordNotFound, service, column, userID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query credentials: %w", err)
+	}
+
+	plaintext, err := r.box.Open(value.String, credentialLabel(column, userID, service))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// save encrypts and sets one credential column of a user's row for service, creating the row when needed
+func (r *CredentialRepository) save(ctx context.Context, column, userID, service, value string) error {
+	sealed, err := r.box.Seal([]byte(value), credentialLabel(column, userID, service))
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO user_credentials (user_id, service, %s, updated_at) VALUES (?, ?, ?, ?) %s",
+		column, shared.DialectOf(r.db).Upsert([]string{"user_id", "service"}, column, "updated_at"))
+	_, err = r.db.ExecContext(ctx, query, userID, service, sealed, time.Now())
+	return err
+}
+
+// credentialLabel names the cell a credential is sealed for
+func credentialLabel(column, userID, service string) string {
+	return fmt.Sprintf("%s %s of user %s", service, column, userID)
+}
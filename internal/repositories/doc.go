@@ -2,13 +2,28 @@
 //
 // Each repository handles CRUD operations with atomic sequence generation for human-readable ordering.
 // All repositories support soft deletes via deleted_at timestamps and exclude deleted records from queries by default.
+// PurgeDeleted permanently removes rows soft-deleted beyond a retention window, cascading to dependent rows in the same transaction.
+// Restore and ListDeleted recover soft-deleted rows, refusing restores that would collide with a live row's unique key.
+// [TrackRepository] and [PlaylistRepository] provide Search over FTS4 indexes kept in sync by triggers.
+// List takes [models.ListOpts] for ordering and LIMIT/OFFSET pagination, and Count reports totals for the same criteria.
+// Every method accepts a [context.Context] that is passed to the underlying *Context database calls for cancellation and deadlines.
 //
 // Key Implementations:
 //   - [UserRepository] : User account persistence with email-based lookups
 //   - [PlaylistRepository] : Playlist caching with service-specific queries
 //   - [TrackRepository] : Track caching with ISRC-based cross-service matching
+//   - [AlbumRepository] : Album caching with UPC-based cross-service matching
+//   - [ArtistRepository] : Artist caching with name-based cross-service matching
 //   - [PlaylistTrackRepository] : Junction table managing playlist track membership
+//   - [SearchCacheRepository] : Expiring YouTube Music search matches (hard-deleted, no sequence)
+//   - [IgnoreListRepository] : ISRCs and artists skipped by transfers and diffs (hard-deleted, no sequence)
+//   - [CredentialRepository] : Per-user OAuth tokens and auth files for the web server (hard-deleted, no sequence)
 //   - [MigrationJobRepository] : Migration history with status tracking
+//   - [LibraryCacheAdapter] : Upserts dumped YouTube Music library data across repositories
+//   - [PlaylistCacheAdapter] : Persists transferred playlists with ordered membership
+//   - [MigrationHistoryAdapter] : Records transfers as migration jobs with live progress and unmatched tracks
+//
+// [CollectAnalytics] aggregates the migration history and cache into totals, daily match rates, failure rankings, and per-service counts.
 //
 // Sequence numbers provide stable, human-readable ordering (e.g., user #42, playlist #15) independent of UUIDs and creation timestamps.
 // The [NextSequence] function atomically increments per-table sequence counters in dedicated sequence tables.
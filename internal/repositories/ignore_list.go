@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// IgnoreListRepository stores ISRCs and artist names that transfers and diffs skip.
+//
+// Values are matched case-insensitively: ISRCs are stored uppercased and artist names lowercased
+// with whitespace collapsed. Like the search cache, entries are hard-deleted.
+type IgnoreListRepository struct {
+	db *sql.DB
+}
+
+// NewIgnoreListRepository creates a new IgnoreListRepository
+func NewIgnoreListRepository(db *sql.DB) *IgnoreListRepository {
+	return &IgnoreListRepository{db: db}
+}
+
+// Add records an ignore entry, updating the reason when the value is already listed.
+//
+// Returns [shared.ErrInvalidInput] for an unknown kind or an empty value.
+func (r *IgnoreListRepository) Add(ctx context.Context, entry *models.IgnoreEntry) error {
+	value, err := normalizeIgnoreValue(entry.Kind, entry.Value)
+	if err != nil {
+		return err
+	}
+
+	var reason any = entry.Reason
+	if entry.Reason == "" {
+		reason = nil
+	}
+
+	entry.Value = strings.TrimSpace(entry.Value)
+	if entry.Kind == models.IgnoreKindISRC {
+		entry.Value = value
+	}
+	entry.CreatedAt = time.Now().UTC()
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO ignore_list (id, kind, value, display, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(kind, value) DO UPDATE SET
+			display = excluded.display,
+			reason = excluded.reason
+		RETURNING id, created_at
+	`, shared.GenerateID(), entry.Kind, value, entry.Value, reason, entry.CreatedAt).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add ignore entry: %w", err)
+	}
+
+	return nil
+}
+
+// Remove permanently deletes the entry for kind and value.
+//
+// Returns [shared.ErrRecordNotFound] when the value is not listed.
+func (r *IgnoreListRepository) Remove(ctx context.Context, kind, value string) error {
+	normalized, err := normalizeIgnoreValue(kind, value)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM ignore_list WHERE kind = ? AND value = ?", kind, normalized)
+	if err != nil {
+		return fmt.Errorf("failed to remove ignore entry: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %s %q is not ignored", shared.ErrRecordNotFound, kind, value)
+	}
+
+	return nil
+}
+
+// List returns every ignore entry ordered by kind, then value
+func (r *IgnoreListRepository) List(ctx context.Context) ([]*models.IgnoreEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, kind, display, reason, created_at
+		FROM ignore_list
+		ORDER BY kind, value
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ignore entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.IgnoreEntry
+	for rows.Next() {
+		var (
+			entry  models.IgnoreEntry
+			reason sql.NullString
+		)
+		if err := rows.Scan(&entry.ID, &entry.Kind, &entry.Value, &reason, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ignore entry: %w", err)
+		}
+		entry.Reason = reason.String
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ignore entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Ignores reports whether track's ISRC or artist is on the ignore list.
+//
+// Multi-artist credits ("A, B" or "A & B") match when any credited artist is ignored.
+func (r *IgnoreListRepository) Ignores(ctx context.Context, track models.Track) (bool, error) {
+	args := []any{strings.ToUpper(strings.TrimSpace(track.ISRC))}
+	placeholders := make([]string, 0, 1)
+	for _, artist := range splitArtists(track.Artist()) {
+		args = append(args, artist)
+		placeholders = append(placeholders, "?")
+	}
+
+	query := "SELECT EXISTS (SELECT 1 FROM ignore_list WHERE (kind = 'isrc' AND value = ?)"
+	if len(placeholders) > 0 {
+		query += " OR (kind = 'artist' AND value IN (" + strings.Join(placeholders, ", ") + "))"
+	}
+	query += ")"
+
+	var ignored bool
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&ignored); err != nil {
+		return false, fmt.Errorf("failed to check ignore list: %w", err)
+	}
+
+	return ignored, nil
+}
+
+// normalizeIgnoreValue returns the stored form of value for kind
+func normalizeIgnoreValue(kind, value string) (string, error) {
+	switch kind {
+	case models.IgnoreKindISRC:
+		value = strings.ToUpper(strings.TrimSpace(value))
+	case models.IgnoreKindArtist:
+		value = normalizeArtist(value)
+	default:
+		return "", fmt.Errorf("%w: unknown ignore kind %q", shared.ErrInvalidInput, kind)
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("%w: %s is required", shared.ErrInvalidInput, kind)
+	}
+	return value, nil
+}
+
+// splitArtists returns the normalized full artist credit followed by each individually credited artist
+func splitArtists(credit string) []string {
+	full := normalizeArtist(credit)
+	if full == "" {
+		return nil
+	}
+
+	artists := []string{full}
+	parts := strings.FieldsFunc(credit, func(r rune) bool { return r == ',' || r == '&' || r == ';' })
+	if len(parts) > 1 {
+		for _, part := range parts {
+			if artist := normalizeArtist(part); artist != "" {
+				artists = append(artists, artist)
+			}
+		}
+	}
+	return artists
+}
+
+// normalizeArtist lowercases name and collapses its whitespace
+func normalizeArtist(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/desertthunder/ytx/internal/models"
+)
+
+// LibraryCacheAdapter implements tasks.LibraryPersister using the playlist, track, album, and artist repositories.
+//
+// Each upsert looks up the entity by service+service_id and updates it in place when present,
+// otherwise creates a new record. Playlists are owned by, and looked up for, the user the adapter was created for.
+type LibraryCacheAdapter struct {
+	userID    string
+	playlists *PlaylistRepository
+	tracks    *TrackRepository
+	albums    *AlbumRepository
+	artists   *ArtistRepository
+}
+
+// NewLibraryCacheAdapter creates a new LibraryCacheAdapter owning cached playlists as userID
+func NewLibraryCacheAdapter(userID string, playlists *PlaylistRepository, tracks *TrackRepository, albums *AlbumRepository, artists *ArtistRepository) *LibraryCacheAdapter {
+	return &LibraryCacheAdapter{
+		userID:    userID,
+		playlists: playlists,
+		tracks:    tracks,
+		albums:    albums,
+		artists:   artists,
+	}
+}
+
+// UpsertPlaylist creates or refreshes a cached playlist
+func (a *LibraryCacheAdapter) UpsertPlaylist(ctx context.Context, service string, playlist models.Playlist) error {
+	incoming := models.NewPersistedPlaylist(0, service, playlist.ID, a.userID, playlist)
+
+	existing, err := a.playlists.GetByServiceID(ctx, a.userID, service, playlist.ID)
+	if err == nil && existing != nil {
+		incoming.SetID(existing.ID())
+		return a.playlists.Update(ctx, incoming)
+	}
+
+	if err := a.playlists.Create(ctx, incoming); err != nil {
+		return fmt.Errorf("failed to cache playlist: %w", err)
+	}
+	return nil
+}
+
+// UpsertTrack creates or refreshes a cached track
+func (a *LibraryCacheAdapter) UpsertTrack(ctx context.Context, service string, track models.Track) error {
+	incoming := models.NewPersistedTrack(0, service, track.ID, track)
+
+	existing, err := a.tracks.GetByServiceID(ctx, service, track.ID)
+	if err == nil && existing != nil {
+		incoming.SetID(existing.ID())
+		return a.tracks.Update(ctx, incoming)
+	}
+
+	if err := a.tracks.Create(ctx, incoming); err != nil {
+		return fmt.Errorf("failed to cache track: %w", err)
+	}
+	return nil
+}
+
+// UpsertAlbum creates or refreshes a cached album
+func (a *LibraryCacheAdapter) UpsertAlbum(ctx context.Context, service string, album models.Album) error {
+	incoming := models.NewPersistedAlbum(0, service, album.ID, album)
+
+	existing, err := a.albums.GetByServiceID(ctx, service, album.ID)
+	if err == nil && existing != nil {
+		incoming.SetID(existing.ID())
+		return a.albums.Update(ctx, incoming)
+	}
+
+	if err := a.albums.Create(ctx, incoming); err != nil {
+		return fmt.Errorf("failed to cache album: %w", err)
+	}
+	return nil
+}
+
+// UpsertArtist creates or refreshes a cached artist
+func (a *LibraryCacheAdapter) UpsertArtist(ctx context.Context, service string, artist models.Artist) error {
+	incoming := models.NewPersistedArtist(0, service, artist.ID, artist)
+
+	existing, err := a.artists.GetByServiceID(ctx, service, artist.ID)
+	if err == nil && existing != nil {
+		incoming.SetID(existing.ID())
+		return a.artists.Update(ctx, incoming)
+	}
+
+	if err := a.artists.Create(ctx, incoming); err != nil {
+		return fmt.Errorf("failed to cache artist: %w", err)
+	}
+	return nil
+}
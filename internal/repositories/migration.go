@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -22,8 +23,8 @@ func NewMigrationRepository(db *sql.DB) *MigrationRepository {
 }
 
 // Create inserts a new migration job into the database with generated ID and sequence
-func (r *MigrationRepository) Create(migration *models.MigrationJob) error {
-	sequence, err := NextSequence(r.db, "migrations")
+func (r *MigrationRepository) Create(ctx context.Context, migration *models.MigrationJob) error {
+	sequence, err := NextSequence(ctx, r.db, "migrations")
 	if err != nil {
 		return fmt.Errorf("failed to generate sequence: %w", err)
 	}
@@ -40,9 +41,10 @@ func (r *MigrationRepository) Create(migration *models.MigrationJob) error {
 			id, sequence, user_id, source_service, source_playlist_id,
 			target_service, target_playlist_id, status, tracks_total,
 			tracks_migrated, tracks_failed, error_message, started_at,
-			completed_at, created_at, updated_at
+			completed_at, progress_current, progress_total, last_progress_at,
+			created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var targetPlaylistID any = migration.TargetPlaylistID()
@@ -55,7 +57,7 @@ func (r *MigrationRepository) Create(migration *models.MigrationJob) error {
 		errorMessage = nil
 	}
 
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		id,
 		sequence,
 		migration.UserID(),
@@ -70,6 +72,9 @@ func (r *MigrationRepository) Create(migration *models.MigrationJob) error {
 		errorMessage,
 		migration.StartedAt(),
 		migration.CompletedAt(),
+		migration.ProgressCurrent(),
+		migration.ProgressTotal(),
+		migration.LastProgressAt(),
 		migration.CreatedAt(),
 		migration.UpdatedAt(),
 	)
@@ -81,22 +86,23 @@ func (r *MigrationRepository) Create(migration *models.MigrationJob) error {
 }
 
 // Get retrieves a migration job by ID, excluding soft-deleted migrations
-func (r *MigrationRepository) Get(id string) (*models.MigrationJob, error) {
+func (r *MigrationRepository) Get(ctx context.Context, id string) (*models.MigrationJob, error) {
 	query := `
 		SELECT
 			id, sequence, user_id, source_service, source_playlist_id,
 			target_service, target_playlist_id, status, tracks_total,
 			tracks_migrated, tracks_failed, error_message, started_at,
-			completed_at, created_at, updated_at, deleted_at
+			completed_at, progress_current, progress_total, last_progress_at,
+			created_at, updated_at, deleted_at
 		FROM migrations
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	return r.scanOne(r.db.QueryRow(query, id))
+	return r.scanOne(r.db.QueryRowContext(ctx, query, id))
 }
 
 // Update modifies an existing migration job in the database
-func (r *MigrationRepository) Update(migration *models.MigrationJob) error {
+func (r *MigrationRepository) Update(ctx context.Context, migration *models.MigrationJob) error {
 	if err := migration.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
@@ -108,7 +114,8 @@ func (r *MigrationRepository) Update(migration *models.MigrationJob) error {
 		UPDATE migrations
 		SET target_playlist_id = ?, status = ?, tracks_total = ?,
 			tracks_migrated = ?, tracks_failed = ?, error_message = ?,
-			started_at = ?, completed_at = ?, updated_at = ?
+			started_at = ?, completed_at = ?, progress_current = ?,
+			progress_total = ?, last_progress_at = ?, updated_at = ?
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
@@ -122,7 +129,7 @@ func (r *MigrationRepository) Update(migration *models.MigrationJob) error {
 		errorMessage = nil
 	}
 
-	result, err := r.db.Exec(query,
+	result, err := r.db.ExecContext(ctx, query,
 		targetPlaylistID,
 		migration.Status(),
 		migration.TracksTotal(),
@@ -131,6 +138,9 @@ func (r *MigrationRepository) Update(migration *models.MigrationJob) error {
 		errorMessage,
 		migration.StartedAt(),
 		migration.CompletedAt(),
+		migration.ProgressCurrent(),
+		migration.ProgressTotal(),
+		migration.LastProgressAt(),
 		now,
 		migration.ID(),
 	)
@@ -149,8 +159,40 @@ func (r *MigrationRepository) Update(migration *models.MigrationJob) error {
 	return nil
 }
 
+// UpdateProgress persists how many of total tracks a running migration has processed, stamping last_progress_at.
+//
+// Only the progress columns are written so frequent calls stay cheap and never clobber other fields.
+func (r *MigrationRepository) UpdateProgress(ctx context.Context, id string, current, total int) error {
+	if current < 0 || total < 0 {
+		return fmt.Errorf("%w: progress cannot be negative", shared.ErrInvalidInput)
+	}
+
+	now := time.Now()
+
+	query := `
+		UPDATE migrations
+		SET progress_current = ?, progress_total = ?, last_progress_at = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, current, total, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update migration progress: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("migration not found or already deleted: %s", id)
+	}
+
+	return nil
+}
+
 // Delete soft-deletes a migration job by ID
-func (r *MigrationRepository) Delete(id string) error {
+func (r *MigrationRepository) Delete(ctx context.Context, id string) error {
 	now := time.Now()
 
 	query := `
@@ -159,7 +201,7 @@ func (r *MigrationRepository) Delete(id string) error {
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, now, id)
+	result, err := r.db.ExecContext(ctx, query, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete migration: %w", err)
 	}
@@ -175,43 +217,160 @@ func (r *MigrationRepository) Delete(id string) error {
 	return nil
 }
 
-// List retrieves all migration jobs matching the given criteria, excluding soft-deleted migrations
-func (r *MigrationRepository) List(criteria map[string]any) ([]*models.MigrationJob, error) {
+// PurgeDeleted permanently removes migration jobs soft-deleted more than olderThan ago, along with their recorded failures.
+// A zero olderThan purges every soft-deleted row. Returns the number of migrations removed.
+func (r *MigrationRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return purgeDeleted(ctx, r.db, "migrations", olderThan,
+		`DELETE FROM migration_failures WHERE migration_id IN (SELECT id FROM migrations WHERE deleted_at IS NOT NULL AND deleted_at <= ?)`,
+	)
+}
+
+// CreateFailures records the source tracks a migration could not match in a single transaction.
+//
+// Failures are hard rows owned by the migration: they have no sequence and are removed when the migration is purged.
+func (r *MigrationRepository) CreateFailures(ctx context.Context, migrationID string, tracks []models.Track) error {
+	if migrationID == "" {
+		return fmt.Errorf("validation failed: migration ID is required")
+	}
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO migration_failures (id, migration_id, title, artist, album, isrc, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, track := range tracks {
+		if track.Title == "" {
+			return fmt.Errorf("validation failed: failed track title is required")
+		}
+
+		_, err := stmt.ExecContext(ctx, shared.GenerateID(), migrationID, track.Title, track.Artist(), track.Album, track.ISRC, now)
+		if err != nil {
+			return fmt.Errorf("failed to insert migration failure: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return nil
+}
+
+// ListFailures retrieves the source tracks a migration could not match, in the order they were recorded
+func (r *MigrationRepository) ListFailures(ctx context.Context, migrationID string) ([]models.Track, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT title, artist, album, isrc
+		FROM migration_failures
+		WHERE migration_id = ?
+		ORDER BY rowid ASC
+	`, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migration failures: %w", err)
+	}
+	defer rows.Close()
+
+	var failures []models.Track
+	for rows.Next() {
+		var (
+			title  string
+			artist sql.NullString
+			album  sql.NullString
+			isrc   sql.NullString
+		)
+		if err := rows.Scan(&title, &artist, &album, &isrc); err != nil {
+			return nil, fmt.Errorf("failed to scan migration failure: %w", err)
+		}
+		failure := models.Track{Title: title, Album: album.String, ISRC: isrc.String}
+		failure.SetArtists(artist.String)
+		failures = append(failures, failure)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return failures, nil
+}
+
+// Restore undoes the soft delete of a migration job by ID
+func (r *MigrationRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, r.db, "migrations", id)
+}
+
+// ListDeleted retrieves all soft-deleted migrations, most recently deleted first
+func (r *MigrationRepository) ListDeleted(ctx context.Context) ([]*models.MigrationJob, error) {
 	query := `
 		SELECT
 			id, sequence, user_id, source_service, source_playlist_id,
 			target_service, target_playlist_id, status, tracks_total,
 			tracks_migrated, tracks_failed, error_message, started_at,
-			completed_at, created_at, updated_at, deleted_at
+			completed_at, progress_current, progress_total, last_progress_at,
+			created_at, updated_at, deleted_at
 		FROM migrations
-		WHERE deleted_at IS NULL
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
 	`
 
-	args := []any{}
-
-	if userID, ok := criteria["user_id"].(string); ok && userID != "" {
-		query += " AND user_id = ?"
-		args = append(args, userID)
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted migrations: %w", err)
 	}
+	defer rows.Close()
 
-	if status, ok := criteria["status"].(string); ok && status != "" {
-		query += " AND status = ?"
-		args = append(args, status)
+	var deleted []*models.MigrationJob
+	for rows.Next() {
+		item, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, item)
 	}
 
-	if sourceService, ok := criteria["source_service"].(string); ok && sourceService != "" {
-		query += " AND source_service = ?"
-		args = append(args, sourceService)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	if targetService, ok := criteria["target_service"].(string); ok && targetService != "" {
-		query += " AND target_service = ?"
-		args = append(args, targetService)
-	}
+	return deleted, nil
+}
+
+// List retrieves all migration jobs matching the given criteria, excluding soft-deleted migrations
+func (r *MigrationRepository) List(ctx context.Context, criteria map[string]any, opts models.ListOpts) ([]*models.MigrationJob, error) {
+	query := `
+		SELECT
+			id, sequence, user_id, source_service, source_playlist_id,
+			target_service, target_playlist_id, status, tracks_total,
+			tracks_migrated, tracks_failed, error_message, started_at,
+			completed_at, progress_current, progress_total, last_progress_at,
+			created_at, updated_at, deleted_at
+		FROM migrations
+		WHERE deleted_at IS NULL
+	`
+
+	where, args := r.filter(criteria)
+	query += where
 
-	query += " ORDER BY sequence DESC"
+	clause, pageArgs, err := listClause(opts, "sequence DESC")
+	if err != nil {
+		return nil, err
+	}
+	query += clause
+	args = append(args, pageArgs...)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query migrations: %w", err)
 	}
@@ -233,6 +392,47 @@ func (r *MigrationRepository) List(criteria map[string]any) ([]*models.Migration
 	return migrations, nil
 }
 
+// Count returns the number of migration jobs matching the given criteria, excluding soft-deleted migration jobs
+func (r *MigrationRepository) Count(ctx context.Context, criteria map[string]any) (int, error) {
+	where, args := r.filter(criteria)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM migrations WHERE deleted_at IS NULL"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count migration jobs: %w", err)
+	}
+
+	return count, nil
+}
+
+// filter narrows migrations by "user_id", "status", "source_service", and "target_service"
+func (r *MigrationRepository) filter(criteria map[string]any) (string, []any) {
+	query := ""
+	args := []any{}
+
+	if userID, ok := criteria["user_id"].(string); ok && userID != "" {
+		query += " AND user_id = ?"
+		args = append(args, userID)
+	}
+
+	if status, ok := criteria["status"].(string); ok && status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+
+	if sourceService, ok := criteria["source_service"].(string); ok && sourceService != "" {
+		query += " AND source_service = ?"
+		args = append(args, sourceService)
+	}
+
+	if targetService, ok := criteria["target_service"].(string); ok && targetService != "" {
+		query += " AND target_service = ?"
+		args = append(args, targetService)
+	}
+
+	return query, args
+}
+
 // scanOne scans a single [sql.Row] into a [models.MigrationJob]
 func (r *MigrationRepository) scanOne(row *sql.Row) (*models.MigrationJob, error) {
 	var (
@@ -250,6 +450,9 @@ func (r *MigrationRepository) scanOne(row *sql.Row) (*models.MigrationJob, error
 		errorMessage     sql.NullString
 		startedAt        sql.NullTime
 		completedAt      sql.NullTime
+		progressCurrent  int
+		progressTotal    int
+		lastProgressAt   sql.NullTime
 		createdAt        time.Time
 		updatedAt        time.Time
 		deletedAt        sql.NullTime
@@ -259,7 +462,8 @@ func (r *MigrationRepository) scanOne(row *sql.Row) (*models.MigrationJob, error
 		&id, &sequence, &userID, &sourceService, &sourcePlaylistID,
 		&targetService, &targetPlaylistID, &status, &tracksTotal,
 		&tracksMigrated, &tracksFailed, &errorMessage, &startedAt,
-		&completedAt, &createdAt, &updatedAt, &deletedAt,
+		&completedAt, &progressCurrent, &progressTotal, &lastProgressAt,
+		&createdAt, &updatedAt, &deletedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("migration not found")
@@ -288,6 +492,11 @@ func (r *MigrationRepository) scanOne(row *sql.Row) (*models.MigrationJob, error
 	if completedAt.Valid {
 		migration.SetCompletedAt(&completedAt.Time)
 	}
+	if lastProgressAt.Valid {
+		migration.SetProgress(progressCurrent, progressTotal, &lastProgressAt.Time)
+	} else {
+		migration.SetProgress(progressCurrent, progressTotal, nil)
+	}
 	if deletedAt.Valid {
 		migration.SetDeletedAt(&deletedAt.Time)
 	}
@@ -312,6 +521,9 @@ func (r *MigrationRepository) scanRow(rows *sql.Rows) (*models.MigrationJob, err
 		errorMessage     sql.NullString
 		startedAt        sql.NullTime
 		completedAt      sql.NullTime
+		progressCurrent  int
+		progressTotal    int
+		lastProgressAt   sql.NullTime
 		createdAt        time.Time
 		updatedAt        time.Time
 		deletedAt        sql.NullTime
@@ -321,7 +533,8 @@ func (r *MigrationRepository) scanRow(rows *sql.Rows) (*models.MigrationJob, err
 		&id, &sequence, &userID, &sourceService, &sourcePlaylistID,
 		&targetService, &targetPlaylistID, &status, &tracksTotal,
 		&tracksMigrated, &tracksFailed, &errorMessage, &startedAt,
-		&completedAt, &createdAt, &updatedAt, &deletedAt,
+		&completedAt, &progressCurrent, &progressTotal, &lastProgressAt,
+		&createdAt, &updatedAt, &deletedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan migration: %w", err)
@@ -347,6 +560,11 @@ func (r *MigrationRepository) scanRow(rows *sql.Rows) (*models.MigrationJob, err
 	if completedAt.Valid {
 		migration.SetCompletedAt(&completedAt.Time)
 	}
+	if lastProgressAt.Valid {
+		migration.SetProgress(progressCurrent, progressTotal, &lastProgressAt.Time)
+	} else {
+		migration.SetProgress(progressCurrent, progressTotal, nil)
+	}
 	if deletedAt.Valid {
 		migration.SetDeletedAt(&deletedAt.Time)
 	}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+)
+
+// MigrationHistoryAdapter implements tasks.MigrationRecorder using the migration repository.
+//
+// Each transfer becomes an in-progress migration job owned by the adapter's user, updated with live
+// progress and then marked completed or failed, with its unmatched tracks stored alongside for analytics. Migration jobs reference playlists by
+// their local IDs, so playlists that are not cached yet are cached without tracks first.
+type MigrationHistoryAdapter struct {
+	userID     string
+	playlists  *PlaylistRepository
+	migrations *MigrationRepository
+}
+
+// NewMigrationHistoryAdapter creates a new MigrationHistoryAdapter recording migrations as userID
+func NewMigrationHistoryAdapter(userID string, playlists *PlaylistRepository, migrations *MigrationRepository) *MigrationHistoryAdapter {
+	return &MigrationHistoryAdapter{userID: userID, playlists: playlists, migrations: migrations}
+}
+
+// StartTransfer records a running transfer as an in-progress migration job and returns its ID
+func (a *MigrationHistoryAdapter) StartTransfer(ctx context.Context, record models.TransferRecord) (string, error) {
+	sourceID, err := a.resolvePlaylist(ctx, record.SourceService, record.SourcePlaylist)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	job := models.NewMigrationJob(0, a.userID, record.SourceService, sourceID, record.TargetService)
+	job.SetStatus("in_progress")
+	job.SetTracksTotal(record.TracksTotal)
+	job.SetStartedAt(&record.StartedAt)
+	job.SetProgress(0, record.TracksTotal, &now)
+
+	if err := a.migrations.Create(ctx, job); err != nil {
+		return "", fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	return job.ID(), nil
+}
+
+// RecordProgress persists how many tracks a running migration has processed
+func (a *MigrationHistoryAdapter) RecordProgress(ctx context.Context, migrationID string, current, total int) error {
+	return a.migrations.UpdateProgress(ctx, migrationID, current, total)
+}
+
+// FinishTransfer marks a migration job completed or failed and records its unmatched tracks
+func (a *MigrationHistoryAdapter) FinishTransfer(ctx context.Context, migrationID string, record models.TransferRecord) error {
+	job, err := a.migrations.Get(ctx, migrationID)
+	if err != nil {
+		return err
+	}
+
+	if record.TargetPlaylist != nil {
+		targetID, err := a.resolvePlaylist(ctx, record.TargetService, *record.TargetPlaylist)
+		if err != nil {
+			return err
+		}
+		job.SetTargetPlaylistID(targetID)
+	}
+
+	job.SetTracksTotal(record.TracksTotal)
+	job.SetTracksMigrated(record.TracksMigrated)
+	job.SetTracksFailed(len(record.Failures))
+	job.SetCompletedAt(&record.CompletedAt)
+
+	if record.Error != "" {
+		job.SetStatus("failed")
+		job.SetErrorMessage(record.Error)
+	} else {
+		job.SetStatus("completed")
+	}
+
+	if err := a.migrations.Update(ctx, job); err != nil {
+		return fmt.Errorf("failed to record migration outcome: %w", err)
+	}
+
+	if err := a.migrations.CreateFailures(ctx, job.ID(), record.Failures); err != nil {
+		return fmt.Errorf("failed to record unmatched tracks: %w", err)
+	}
+
+	return nil
+}
+
+// resolvePlaylist returns the local ID of a service playlist, caching its metadata when it is not cached yet
+func (a *MigrationHistoryAdapter) resolvePlaylist(ctx context.Context, service string, playlist models.Playlist) (string, error) {
+	if playlist.ID == "" {
+		return "", fmt.Errorf("playlist ID is required")
+	}
+
+	if cached, err := a.playlists.GetByServiceID(ctx, a.userID, service, playlist.ID); err == nil {
+		return cached.ID(), nil
+	}
+
+	persisted := models.NewPersistedPlaylist(0, service, playlist.ID, a.userID, playlist)
+	if err := a.playlists.UpsertBatch(ctx, []*models.PersistedPlaylist{persisted}); err != nil {
+		return "", fmt.Errorf("failed to cache %s playlist %s: %w", service, playlist.ID, err)
+	}
+
+	return persisted.ID(), nil
+}
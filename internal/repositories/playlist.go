@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -22,8 +23,8 @@ func NewPlaylistRepository(db *sql.DB) *PlaylistRepository {
 }
 
 // Create inserts a new playlist into the database with generated ID and sequence
-func (r *PlaylistRepository) Create(playlist *models.PersistedPlaylist) error {
-	sequence, err := NextSequence(r.db, "playlists")
+func (r *PlaylistRepository) Create(ctx context.Context, playlist *models.PersistedPlaylist) error {
+	sequence, err := NextSequence(ctx, r.db, "playlists")
 	if err != nil {
 		return fmt.Errorf("failed to generate sequence: %w", err)
 	}
@@ -40,7 +41,7 @@ func (r *PlaylistRepository) Create(playlist *models.PersistedPlaylist) error {
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		id,
 		sequence,
 		playlist.Service(),
@@ -61,29 +62,29 @@ func (r *PlaylistRepository) Create(playlist *models.PersistedPlaylist) error {
 }
 
 // Get retrieves a playlist by ID, excluding soft-deleted playlists
-func (r *PlaylistRepository) Get(id string) (*models.PersistedPlaylist, error) {
+func (r *PlaylistRepository) Get(ctx context.Context, id string) (*models.PersistedPlaylist, error) {
 	query := `
 		SELECT id, sequence, service, service_id, user_id, name, description, track_count, public, created_at, updated_at, deleted_at
 		FROM playlists
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	return r.scanOne(r.db.QueryRow(query, id))
+	return r.scanOne(r.db.QueryRowContext(ctx, query, id))
 }
 
-// GetByServiceID retrieves a playlist by service and service_id
-func (r *PlaylistRepository) GetByServiceID(service, serviceID string) (*models.PersistedPlaylist, error) {
+// GetByServiceID retrieves userID's copy of a playlist by service and service_id
+func (r *PlaylistRepository) GetByServiceID(ctx context.Context, userID, service, serviceID string) (*models.PersistedPlaylist, error) {
 	query := `
 		SELECT id, sequence, service, service_id, user_id, name, description, track_count, public, created_at, updated_at, deleted_at
 		FROM playlists
-		WHERE service = ? AND service_id = ? AND deleted_at IS NULL
+		WHERE user_id = ? AND service = ? AND service_id = ? AND deleted_at IS NULL
 	`
 
-	return r.scanOne(r.db.QueryRow(query, service, serviceID))
+	return r.scanOne(r.db.QueryRowContext(ctx, query, userID, service, serviceID))
 }
 
 // Update modifies an existing playlist in the database
-func (r *PlaylistRepository) Update(playlist *models.PersistedPlaylist) error {
+func (r *PlaylistRepository) Update(ctx context.Context, playlist *models.PersistedPlaylist) error {
 	if err := playlist.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
@@ -97,7 +98,7 @@ func (r *PlaylistRepository) Update(playlist *models.PersistedPlaylist) error {
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query,
+	result, err := r.db.ExecContext(ctx, query,
 		playlist.Name(),
 		playlist.Description(),
 		playlist.TrackCount(),
@@ -121,7 +122,7 @@ func (r *PlaylistRepository) Update(playlist *models.PersistedPlaylist) error {
 }
 
 // Delete soft-deletes a playlist by ID
-func (r *PlaylistRepository) Delete(id string) error {
+func (r *PlaylistRepository) Delete(ctx context.Context, id string) error {
 	now := time.Now()
 
 	query := `
@@ -130,7 +131,7 @@ func (r *PlaylistRepository) Delete(id string) error {
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, now, id)
+	result, err := r.db.ExecContext(ctx, query, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete playlist: %w", err)
 	}
@@ -146,29 +147,94 @@ func (r *PlaylistRepository) Delete(id string) error {
 	return nil
 }
 
-// List retrieves all playlists matching the given criteria, excluding soft-deleted playlists
-func (r *PlaylistRepository) List(criteria map[string]any) ([]*models.PersistedPlaylist, error) {
+// PurgeDeleted permanently removes playlists soft-deleted more than olderThan ago, along with their playlist entries.
+// A zero olderThan purges every soft-deleted row. Returns the number of playlists removed.
+func (r *PlaylistRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return purgeDeleted(ctx, r.db, "playlists", olderThan,
+		`DELETE FROM playlist_tracks WHERE playlist_id IN (SELECT id FROM playlists WHERE deleted_at IS NOT NULL AND deleted_at <= ?)`,
+	)
+}
+
+// Restore undoes the soft delete of a playlist by ID, rejecting conflicts on service+service_id
+func (r *PlaylistRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, r.db, "playlists", id, "service", "service_id")
+}
+
+// ListDeleted retrieves all soft-deleted playlists, most recently deleted first
+func (r *PlaylistRepository) ListDeleted(ctx context.Context) ([]*models.PersistedPlaylist, error) {
 	query := `
 		SELECT id, sequence, service, service_id, user_id, name, description, track_count, public, created_at, updated_at, deleted_at
 		FROM playlists
-		WHERE deleted_at IS NULL
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
 	`
 
-	args := []any{}
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted playlists: %w", err)
+	}
+	defer rows.Close()
 
-	if userID, ok := criteria["user_id"].(string); ok && userID != "" {
-		query += " AND user_id = ?"
-		args = append(args, userID)
+	var deleted []*models.PersistedPlaylist
+	for rows.Next() {
+		item, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, item)
 	}
 
-	if service, ok := criteria["service"].(string); ok && service != "" {
-		query += " AND service = ?"
-		args = append(args, service)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// ListByTrack retrieves the playlists containing the given track, excluding soft-deleted playlists and entries
+func (r *PlaylistRepository) ListByTrack(ctx context.Context, trackID string) ([]*models.PersistedPlaylist, error) {
+	query := `
+		SELECT p.id, p.sequence, p.service, p.service_id, p.user_id, p.name, p.description, p.track_count, p.public, p.created_at, p.updated_at, p.deleted_at
+		FROM playlists p
+		JOIN playlist_tracks pt ON pt.playlist_id = p.id
+		WHERE pt.track_id = ? AND pt.deleted_at IS NULL AND p.deleted_at IS NULL
+		ORDER BY p.sequence ASC
+	`
+
+	return r.queryPlaylists(ctx, query, trackID)
+}
+
+// Search finds cached playlists whose name or description match every word of the query (prefix matching),
+// excluding soft-deleted playlists. A limit of 0 returns all matches.
+func (r *PlaylistRepository) Search(ctx context.Context, text string, limit int) ([]*models.PersistedPlaylist, error) {
+	dialect := shared.DialectOf(r.db)
+	match, err := ftsQuery(dialect, text)
+	if err != nil {
+		return nil, err
+	}
+
+	source := "FROM playlists_fts JOIN playlists p ON p.sequence = playlists_fts.docid WHERE playlists_fts MATCH ?"
+	if dialect == shared.Postgres {
+		source = "FROM playlists p WHERE p.search @@ to_tsquery('simple', ?)"
+	}
+	query := `
+		SELECT p.id, p.sequence, p.service, p.service_id, p.user_id, p.name, p.description, p.track_count, p.public, p.created_at, p.updated_at, p.deleted_at
+		` + source + ` AND p.deleted_at IS NULL
+		ORDER BY p.name, p.sequence
+	`
+	args := []any{match}
+
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
 	}
 
-	query += " ORDER BY sequence ASC"
+	return r.queryPlaylists(ctx, query, args...)
+}
 
-	rows, err := r.db.Query(query, args...)
+// queryPlaylists runs a playlist SELECT and scans every resulting row
+func (r *PlaylistRepository) queryPlaylists(ctx context.Context, query string, args ...any) ([]*models.PersistedPlaylist, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query playlists: %w", err)
 	}
@@ -190,6 +256,77 @@ func (r *PlaylistRepository) List(criteria map[string]any) ([]*models.PersistedP
 	return playlists, nil
 }
 
+// List retrieves all playlists matching the given criteria, excluding soft-deleted playlists
+func (r *PlaylistRepository) List(ctx context.Context, criteria map[string]any, opts models.ListOpts) ([]*models.PersistedPlaylist, error) {
+	query := `
+		SELECT id, sequence, service, service_id, user_id, name, description, track_count, public, created_at, updated_at, deleted_at
+		FROM playlists
+		WHERE deleted_at IS NULL
+	`
+
+	where, args := r.filter(criteria)
+	query += where
+
+	clause, pageArgs, err := listClause(opts, "sequence ASC")
+	if err != nil {
+		return nil, err
+	}
+	query += clause
+	args = append(args, pageArgs...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query playlists: %w", err)
+	}
+	defer rows.Close()
+
+	var playlists []*models.PersistedPlaylist
+	for rows.Next() {
+		playlist, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return playlists, nil
+}
+
+// Count returns the number of playlists matching the given criteria, excluding soft-deleted playlists
+func (r *PlaylistRepository) Count(ctx context.Context, criteria map[string]any) (int, error) {
+	where, args := r.filter(criteria)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM playlists WHERE deleted_at IS NULL"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count playlists: %w", err)
+	}
+
+	return count, nil
+}
+
+// filter narrows playlists to an owner ("user_id") and a service ("service"), ignoring empty values
+func (r *PlaylistRepository) filter(criteria map[string]any) (string, []any) {
+	query := ""
+	args := []any{}
+
+	if userID, ok := criteria["user_id"].(string); ok && userID != "" {
+		query += " AND user_id = ?"
+		args = append(args, userID)
+	}
+
+	if service, ok := criteria["service"].(string); ok && service != "" {
+		query += " AND service = ?"
+		args = append(args, service)
+	}
+
+	return query, args
+}
+
 // scanOne scans a single row into a [models.PersistedPlaylist]
 func (r *PlaylistRepository) scanOne(row *sql.Row) (*models.PersistedPlaylist, error) {
 	var (
@@ -272,3 +409,142 @@ func (r *PlaylistRepository) scanRow(rows *sql.Rows) (*models.PersistedPlaylist,
 
 	return playlist, nil
 }
+
+// CreateBatch inserts multiple playlists in a single transaction using a prepared statement.
+//
+// Either every playlist is inserted or none are; sequence numbers are reserved as one contiguous block.
+func (r *PlaylistRepository) CreateBatch(ctx context.Context, playlists []*models.PersistedPlaylist) error {
+	if len(playlists) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sequence, err := reserveSequences(ctx, tx, "playlists", len(playlists))
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO playlists (id, sequence, service, service_id, user_id, name, description, track_count, public, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, playlist := range playlists {
+		id := shared.GenerateID()
+		playlist.SetID(id)
+
+		if err := playlist.Validate(); err != nil {
+			return fmt.Errorf("validation failed for playlist %s: %w", playlist.ServiceID(), err)
+		}
+
+		_, err := stmt.ExecContext(ctx,
+			id,
+			sequence+i,
+			playlist.Service(),
+			playlist.ServiceID(),
+			playlist.UserID(),
+			playlist.Name(),
+			playlist.Description(),
+			playlist.TrackCount(),
+			playlist.Public(),
+			playlist.CreatedAt(),
+			playlist.UpdatedAt(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert playlist %s: %w", playlist.ServiceID(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return nil
+}
+
+// Upsert inserts playlist, or refreshes the name, description, track count, and visibility of the playlist its
+// user already stored with the same service+service_id, restoring it if soft-deleted. The model's ID is set to the
+// stored row's ID.
+func (r *PlaylistRepository) Upsert(ctx context.Context, playlist *models.PersistedPlaylist) error {
+	return r.UpsertBatch(ctx, []*models.PersistedPlaylist{playlist})
+}
+
+// UpsertBatch inserts or refreshes multiple playlists in a single transaction using a prepared statement.
+//
+// Playlists their user already stored (by user_id+service+service_id) have their metadata updated and are restored if soft-deleted.
+// Each model's ID is set to the stored row's ID. Sequence numbers reserved for updated rows are left unused.
+func (r *PlaylistRepository) UpsertBatch(ctx context.Context, playlists []*models.PersistedPlaylist) error {
+	if len(playlists) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sequence, err := reserveSequences(ctx, tx, "playlists", len(playlists))
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO playlists (id, sequence, service, service_id, user_id, name, description, track_count, public, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, service, service_id) DO UPDATE SET
+			name = excluded.name,
+			description = excluded.description,
+			track_count = excluded.track_count,
+			public = excluded.public,
+			updated_at = excluded.updated_at,
+			deleted_at = NULL
+		RETURNING id
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, playlist := range playlists {
+		playlist.SetID(shared.GenerateID())
+
+		if err := playlist.Validate(); err != nil {
+			return fmt.Errorf("validation failed for playlist %s: %w", playlist.ServiceID(), err)
+		}
+
+		var id string
+		err := stmt.QueryRowContext(ctx,
+			playlist.ID(),
+			sequence+i,
+			playlist.Service(),
+			playlist.ServiceID(),
+			playlist.UserID(),
+			playlist.Name(),
+			playlist.Description(),
+			playlist.TrackCount(),
+			playlist.Public(),
+			playlist.CreatedAt(),
+			playlist.UpdatedAt(),
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to upsert playlist %s: %w", playlist.ServiceID(), err)
+		}
+		playlist.SetID(id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/desertthunder/ytx/internal/models"
+)
+
+// PlaylistCacheAdapter implements tasks.PlaylistCacher and tasks.PlaylistStore using the playlist, track, and playlist track repositories.
+//
+// Caching a playlist upserts its metadata and tracks, then replaces its ordered membership so the
+// cached copy mirrors the service. Playlists are owned by the user the adapter was created for.
+type PlaylistCacheAdapter struct {
+	userID         string
+	playlists      *PlaylistRepository
+	tracks         *TrackRepository
+	playlistTracks *PlaylistTrackRepository
+}
+
+// NewPlaylistCacheAdapter creates a new PlaylistCacheAdapter owning cached playlists as userID
+func NewPlaylistCacheAdapter(userID string, playlists *PlaylistRepository, tracks *TrackRepository, playlistTracks *PlaylistTrackRepository) *PlaylistCacheAdapter {
+	return &PlaylistCacheAdapter{
+		userID:         userID,
+		playlists:      playlists,
+		tracks:         tracks,
+		playlistTracks: playlistTracks,
+	}
+}
+
+// CachePlaylist persists a playlist and its ordered tracks from a service.
+//
+// Tracks missing an ID, title, or artist cannot be cached and are left out of the membership.
+func (a *PlaylistCacheAdapter) CachePlaylist(ctx context.Context, service string, playlist models.Playlist, tracks []models.Track) error {
+	if playlist.ID == "" {
+		return fmt.Errorf("playlist ID is required")
+	}
+
+	playlist.TrackCount = len(tracks)
+	persistedPlaylist := models.NewPersistedPlaylist(0, service, playlist.ID, a.userID, playlist)
+	if err := a.playlists.Upsert(ctx, persistedPlaylist); err != nil {
+		return fmt.Errorf("failed to cache playlist: %w", err)
+	}
+
+	persistedTracks := make([]*models.PersistedTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if track.ID == "" || track.Title == "" || track.Artist() == "" {
+			continue
+		}
+		persistedTracks = append(persistedTracks, models.NewPersistedTrack(0, service, track.ID, track))
+	}
+
+	if err := a.tracks.UpsertBatch(ctx, persistedTracks); err != nil {
+		return fmt.Errorf("failed to cache playlist tracks: %w", err)
+	}
+
+	entries := make([]*models.PlaylistTrack, len(persistedTracks))
+	for i, track := range persistedTracks {
+		entries[i] = models.NewPlaylistTrack(0, persistedPlaylist.ID(), track.ID(), i)
+	}
+
+	if err := a.playlistTracks.ReplacePlaylist(ctx, persistedPlaylist.ID(), entries); err != nil {
+		return fmt.Errorf("failed to cache playlist membership: %w", err)
+	}
+
+	return nil
+}
+
+// LoadPlaylist loads a cached playlist with its tracks in playlist order
+func (a *PlaylistCacheAdapter) LoadPlaylist(ctx context.Context, service, playlistID string) (*models.PlaylistExport, error) {
+	playlist, err := a.playlists.GetByServiceID(ctx, a.userID, service, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := a.tracks.ListByPlaylist(ctx, playlist.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cached tracks: %w", err)
+	}
+
+	export := &models.PlaylistExport{
+		Playlist: playlist.ToPlaylist(),
+		Tracks:   make([]models.Track, len(tracks)),
+	}
+	for i, track := range tracks {
+		export.Tracks[i] = track.ToTrack()
+	}
+
+	return export, nil
+}
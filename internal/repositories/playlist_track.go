@@ -0,0 +1,431 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// PlaylistTrackRepository implements models.Repository[*models.PlaylistTrack] for playlist membership.
+//
+// Handles the playlist_tracks junction table with soft delete support and position-based ordering.
+// Each track may appear in a playlist once (playlist_id+track_id uniqueness).
+type PlaylistTrackRepository struct {
+	db *sql.DB
+}
+
+// NewPlaylistTrackRepository creates a new PlaylistTrackRepository with the given database connection
+func NewPlaylistTrackRepository(db *sql.DB) *PlaylistTrackRepository {
+	return &PlaylistTrackRepository{db: db}
+}
+
+// Create inserts a new [models.PlaylistTrack] into the database with generated ID and sequence
+func (r *PlaylistTrackRepository) Create(ctx context.Context, pt *models.PlaylistTrack) error {
+	sequence, err := NextSequence(ctx, r.db, "playlist_tracks")
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	id := shared.GenerateID()
+	pt.SetID(id)
+
+	if err := pt.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	query := `
+		INSERT INTO playlist_tracks (id, sequence, playlist_id, track_id, position, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query, id, sequence, pt.PlaylistID(), pt.TrackID(), pt.Position(), pt.CreatedAt())
+	if err != nil {
+		return fmt.Errorf("failed to insert playlist track: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a playlist track by ID, excluding soft-deleted entries
+func (r *PlaylistTrackRepository) Get(ctx context.Context, id string) (*models.PlaylistTrack, error) {
+	query := `
+		SELECT id, sequence, playlist_id, track_id, position, created_at, deleted_at
+		FROM playlist_tracks
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// Update modifies the position of an existing playlist track
+func (r *PlaylistTrackRepository) Update(ctx context.Context, pt *models.PlaylistTrack) error {
+	if err := pt.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	query := `
+		UPDATE playlist_tracks
+		SET position = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, pt.Position(), pt.ID())
+	if err != nil {
+		return fmt.Errorf("failed to update playlist track: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("playlist track not found or already deleted: %s", pt.ID())
+	}
+
+	return nil
+}
+
+// Delete soft-deletes a playlist track by ID
+func (r *PlaylistTrackRepository) Delete(ctx context.Context, id string) error {
+	now := time.Now()
+
+	query := `
+		UPDATE playlist_tracks
+		SET deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete playlist track: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("playlist track not found or already deleted: %s", id)
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently removes playlist tracks soft-deleted more than olderThan ago.
+// A zero olderThan purges every soft-deleted row. Returns the number of playlist tracks removed.
+func (r *PlaylistTrackRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return purgeDeleted(ctx, r.db, "playlist_tracks", olderThan)
+}
+
+// Restore undoes the soft delete of a playlist track by ID, rejecting conflicts on playlist_id+track_id
+func (r *PlaylistTrackRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, r.db, "playlist_tracks", id, "playlist_id", "track_id")
+}
+
+// ListDeleted retrieves all soft-deleted playlist tracks, most recently deleted first
+func (r *PlaylistTrackRepository) ListDeleted(ctx context.Context) ([]*models.PlaylistTrack, error) {
+	query := `
+		SELECT id, sequence, playlist_id, track_id, position, created_at, deleted_at
+		FROM playlist_tracks
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []*models.PlaylistTrack
+	for rows.Next() {
+		item, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// List retrieves all playlist tracks matching the given criteria ordered by position, excluding soft-deleted entries
+func (r *PlaylistTrackRepository) List(ctx context.Context, criteria map[string]any, opts models.ListOpts) ([]*models.PlaylistTrack, error) {
+	query := `
+		SELECT id, sequence, playlist_id, track_id, position, created_at, deleted_at
+		FROM playlist_tracks
+		WHERE deleted_at IS NULL
+	`
+
+	where, args := r.filter(criteria)
+	query += where
+
+	clause, pageArgs, err := listClause(opts, "playlist_id, position ASC")
+	if err != nil {
+		return nil, err
+	}
+	query += clause
+	args = append(args, pageArgs...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var playlistTracks []*models.PlaylistTrack
+	for rows.Next() {
+		pt, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		playlistTracks = append(playlistTracks, pt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return playlistTracks, nil
+}
+
+// Count returns the number of playlist tracks matching the given criteria, excluding soft-deleted playlist tracks
+func (r *PlaylistTrackRepository) Count(ctx context.Context, criteria map[string]any) (int, error) {
+	where, args := r.filter(criteria)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM playlist_tracks WHERE deleted_at IS NULL"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count playlist tracks: %w", err)
+	}
+
+	return count, nil
+}
+
+// filter narrows entries to one playlist ("playlist_id") or the playlists holding one track ("track_id")
+func (r *PlaylistTrackRepository) filter(criteria map[string]any) (string, []any) {
+	query := ""
+	args := []any{}
+
+	if playlistID, ok := criteria["playlist_id"].(string); ok && playlistID != "" {
+		query += " AND playlist_id = ?"
+		args = append(args, playlistID)
+	}
+
+	if trackID, ok := criteria["track_id"].(string); ok && trackID != "" {
+		query += " AND track_id = ?"
+		args = append(args, trackID)
+	}
+
+	return query, args
+}
+
+// CreateBatch inserts multiple playlist tracks in a single transaction using a prepared statement.
+//
+// Either every entry is inserted or none are; sequence numbers are reserved as one contiguous block.
+func (r *PlaylistTrackRepository) CreateBatch(ctx context.Context, pts []*models.PlaylistTrack) error {
+	if len(pts) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sequence, err := reserveSequences(ctx, tx, "playlist_tracks", len(pts))
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO playlist_tracks (id, sequence, playlist_id, track_id, position, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, pt := range pts {
+		id := shared.GenerateID()
+		pt.SetID(id)
+
+		if err := pt.Validate(); err != nil {
+			return fmt.Errorf("validation failed for playlist track at position %d: %w", pt.Position(), err)
+		}
+
+		_, err := stmt.ExecContext(ctx, id, sequence+i, pt.PlaylistID(), pt.TrackID(), pt.Position(), pt.CreatedAt())
+		if err != nil {
+			return fmt.Errorf("failed to insert playlist track at position %d: %w", pt.Position(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertBatch inserts or repositions multiple playlist tracks in a single transaction using a prepared statement.
+//
+// Entries that already exist (by playlist_id+track_id) have their position updated and are restored if soft-deleted.
+// Each model's ID is set to the stored row's ID. Sequence numbers reserved for updated rows are left unused.
+func (r *PlaylistTrackRepository) UpsertBatch(ctx context.Context, pts []*models.PlaylistTrack) error {
+	if len(pts) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.upsertTx(ctx, tx, pts); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return nil
+}
+
+// ReplacePlaylist atomically replaces a playlist's membership with the given ordered entries.
+//
+// Existing entries are soft-deleted and the new entries upserted in the same transaction,
+// so entries that remain in the playlist are restored with their new positions.
+func (r *PlaylistTrackRepository) ReplacePlaylist(ctx context.Context, playlistID string, pts []*models.PlaylistTrack) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE playlist_tracks
+		SET deleted_at = ?
+		WHERE playlist_id = ? AND deleted_at IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, query, time.Now(), playlistID); err != nil {
+		return fmt.Errorf("failed to clear playlist tracks: %w", err)
+	}
+
+	if len(pts) > 0 {
+		if err := r.upsertTx(ctx, tx, pts); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit playlist replacement: %w", err)
+	}
+
+	return nil
+}
+
+// upsertTx upserts playlist tracks within an existing transaction
+func (r *PlaylistTrackRepository) upsertTx(ctx context.Context, tx *sql.Tx, pts []*models.PlaylistTrack) error {
+	sequence, err := reserveSequences(ctx, tx, "playlist_tracks", len(pts))
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO playlist_tracks (id, sequence, playlist_id, track_id, position, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(playlist_id, track_id) DO UPDATE SET
+			position = excluded.position,
+			deleted_at = NULL
+		RETURNING id
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, pt := range pts {
+		pt.SetID(shared.GenerateID())
+
+		if err := pt.Validate(); err != nil {
+			return fmt.Errorf("validation failed for playlist track at position %d: %w", pt.Position(), err)
+		}
+
+		var id string
+		err := stmt.QueryRowContext(ctx, pt.ID(), sequence+i, pt.PlaylistID(), pt.TrackID(), pt.Position(), pt.CreatedAt()).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to upsert playlist track at position %d: %w", pt.Position(), err)
+		}
+		pt.SetID(id)
+	}
+
+	return nil
+}
+
+// scanOne scans a single [sql.Row] into a [models.PlaylistTrack]
+func (r *PlaylistTrackRepository) scanOne(row *sql.Row) (*models.PlaylistTrack, error) {
+	var (
+		id         string
+		sequence   int
+		playlistID string
+		trackID    string
+		position   int
+		createdAt  time.Time
+		deletedAt  sql.NullTime
+	)
+
+	err := row.Scan(&id, &sequence, &playlistID, &trackID, &position, &createdAt, &deletedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("playlist track not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan playlist track: %w", err)
+	}
+
+	pt := models.NewPlaylistTrack(sequence, playlistID, trackID, position)
+	pt.SetID(id)
+	if deletedAt.Valid {
+		pt.SetDeletedAt(&deletedAt.Time)
+	}
+
+	return pt, nil
+}
+
+// scanRow scans a row from [sql.Rows] into a [models.PlaylistTrack]
+func (r *PlaylistTrackRepository) scanRow(rows *sql.Rows) (*models.PlaylistTrack, error) {
+	var (
+		id         string
+		sequence   int
+		playlistID string
+		trackID    string
+		position   int
+		createdAt  time.Time
+		deletedAt  sql.NullTime
+	)
+
+	err := rows.Scan(&id, &sequence, &playlistID, &trackID, &position, &createdAt, &deletedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan playlist track: %w", err)
+	}
+
+	pt := models.NewPlaylistTrack(sequence, playlistID, trackID, position)
+	pt.SetID(id)
+	if deletedAt.Valid {
+		pt.SetDeletedAt(&deletedAt.Time)
+	}
+
+	return pt, nil
+}
@@ -5,37 +5,217 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
 )
 
 // NextSequence atomically increments and returns the next sequence number for the given table.
 //
 // Sequence numbers provide human-readable ordering for entities (e.g., user #42, playlist #15).
 // They are NOT exposed in CLI output but used internally for sorting and debugging.
-func NextSequence(db *sql.DB, table string) (int, error) {
-	tx, err := db.Begin()
+func NextSequence(ctx context.Context, db *sql.DB, table string) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	sequence, err := reserveSequences(ctx, tx, table, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit sequence transaction: %w", err)
+	}
+
+	return sequence, nil
+}
+
+// reserveSequences increments the table's sequence counter by n within tx and returns the first reserved value.
+//
+// Batch operations use this to claim a contiguous block of sequence numbers in the same transaction as their inserts.
+func reserveSequences(ctx context.Context, tx *sql.Tx, table string, n int) (int, error) {
 	sequenceTable := table + "_sequence"
 
-	_, err = tx.Exec(fmt.Sprintf("UPDATE %s SET value = value + 1 WHERE id = 1", sequenceTable))
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET value = value + ? WHERE id = 1", sequenceTable), n)
 	if err != nil {
 		return 0, fmt.Errorf("failed to increment sequence: %w", err)
 	}
 
-	var sequence int
-	err = tx.QueryRow(fmt.Sprintf("SELECT value FROM %s WHERE id = 1", sequenceTable)).Scan(&sequence)
+	var last int
+	err = tx.QueryRowContext(ctx, fmt.Sprintf("SELECT value FROM %s WHERE id = 1", sequenceTable)).Scan(&last)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get sequence value: %w", err)
 	}
 
+	return last - n + 1, nil
+}
+
+// purgeDeleted permanently removes rows from table that were soft-deleted more than olderThan ago,
+// or every soft-deleted row when olderThan is zero.
+//
+// SQLite foreign keys are not enforced, so cascades holds DELETE statements for dependent rows. Each is run
+// first in the same transaction with the cutoff as its only parameter. Returns the number of rows removed
+// from table, not counting cascaded rows.
+func purgeDeleted(ctx context.Context, db *sql.DB, table string, olderThan time.Duration, cascades ...string) (int64, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, cascade := range cascades {
+		if _, err := tx.ExecContext(ctx, cascade, cutoff); err != nil {
+			return 0, fmt.Errorf("failed to purge rows dependent on %s: %w", table, err)
+		}
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at IS NOT NULL AND deleted_at <= ?", table)
+	result, err := tx.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge %s: %w", table, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("failed to commit sequence transaction: %w", err)
+		return 0, fmt.Errorf("failed to commit purge: %w", err)
 	}
 
-	return sequence, nil
+	return rows, nil
+}
+
+// restoreDeleted clears deleted_at on a soft-deleted row in table within a transaction.
+//
+// uniqueKey lists the columns of the table's unique constraint, if any. The restore fails with
+// [shared.ErrRestoreConflict] when a live row already holds the same key, and with
+// [shared.ErrRecordNotFound] when no soft-deleted row has the given ID.
+func restoreDeleted(ctx context.Context, db *sql.DB, table, id string, uniqueKey ...string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(uniqueKey) > 0 {
+		joins := make([]string, len(uniqueKey))
+		for i, column := range uniqueKey {
+			joins[i] = fmt.Sprintf("live.%[1]s = restored.%[1]s", column)
+		}
+
+		query := fmt.Sprintf(`
+			SELECT live.id FROM %[1]s AS live
+			JOIN %[1]s AS restored ON %[2]s
+			WHERE restored.id = ? AND live.id != restored.id AND live.deleted_at IS NULL
+			LIMIT 1
+		`, table, strings.Join(joins, " AND "))
+
+		var conflictID string
+		err := tx.QueryRowContext(ctx, query, id).Scan(&conflictID)
+		if err == nil {
+			return fmt.Errorf("%w: %s %s has the same %s as %s", shared.ErrRestoreConflict, table, conflictID, strings.Join(uniqueKey, "+"), id)
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check %s for conflicts: %w", table, err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", table), id)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", table, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: no deleted %s row with id %s", shared.ErrRecordNotFound, table, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	return nil
+}
+
+// listClause builds the ORDER BY, LIMIT, and OFFSET clauses for a List query.
+//
+// defaultOrder is used verbatim when opts.OrderBy is empty. Ties on created_at are broken by sequence
+// so pages stay stable.
+func listClause(opts models.ListOpts, defaultOrder string) (string, []any, error) {
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return "", nil, fmt.Errorf("%w: limit and offset cannot be negative", shared.ErrInvalidInput)
+	}
+
+	direction := "ASC"
+	if opts.Desc {
+		direction = "DESC"
+	}
+
+	var clause string
+	switch opts.OrderBy {
+	case "":
+		clause = " ORDER BY " + defaultOrder
+	case models.OrderBySequence:
+		clause = " ORDER BY sequence " + direction
+	case models.OrderByCreatedAt:
+		clause = fmt.Sprintf(" ORDER BY created_at %[1]s, sequence %[1]s", direction)
+	default:
+		return "", nil, fmt.Errorf("%w: cannot order by %q", shared.ErrInvalidInput, opts.OrderBy)
+	}
+
+	var args []any
+	if opts.Limit > 0 || opts.Offset > 0 {
+		limit := opts.Limit
+		if limit == 0 {
+			limit = math.MaxInt64 // OFFSET needs a LIMIT in SQLite; this one is unbounded in every dialect
+		}
+		clause += " LIMIT ? OFFSET ?"
+		args = append(args, limit, opts.Offset)
+	}
+
+	return clause, args, nil
+}
+
+// ftsQuery converts free-form user input into a full-text query for the dialect: a MATCH expression for SQLite's
+// FTS tables, or a tsquery for Postgres.
+//
+// Punctuation is dropped so user input cannot inject query syntax, and each remaining word becomes a
+// prefix term; the terms are ANDed. Returns [shared.ErrInvalidInput] if no words remain.
+func ftsQuery(d shared.Dialect, text string) (string, error) {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "", fmt.Errorf("%w: search query must contain letters or digits", shared.ErrInvalidInput)
+	}
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		if d == shared.Postgres {
+			terms[i] = strings.ToLower(word) + ":*"
+		} else {
+			terms[i] = strings.ToLower(word) + "*"
+		}
+	}
+	if d == shared.Postgres {
+		return strings.Join(terms, " & "), nil
+	}
+	return strings.Join(terms, " "), nil
 }
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -18,7 +19,7 @@ func TestUserRepositoryErrors(t *testing.T) {
 
 			user.SetID("test-id")
 
-			if err := repo.Create(user); err == nil {
+			if err := repo.Create(t.Context(), user); err == nil {
 				t.Fatal("expected validation error for empty email")
 			}
 		})
@@ -30,12 +31,12 @@ func TestUserRepositoryErrors(t *testing.T) {
 			repo := NewUserRepository(db)
 			user1 := models.NewUser(0, "test@example.com", "User One")
 
-			if err := repo.Create(user1); err != nil {
+			if err := repo.Create(t.Context(), user1); err != nil {
 				t.Fatalf("failed to create first user: %v", err)
 			}
 
 			user2 := models.NewUser(0, "test@example.com", "User Two")
-			err := repo.Create(user2)
+			err := repo.Create(t.Context(), user2)
 			if err == nil {
 				t.Fatal("expected error when creating user with duplicate email")
 			}
@@ -49,7 +50,7 @@ func TestUserRepositoryErrors(t *testing.T) {
 
 			repo := NewUserRepository(db)
 
-			_, err := repo.Get("nonexistent-id")
+			_, err := repo.Get(t.Context(), "nonexistent-id")
 			if err == nil {
 				t.Fatal("expected error when getting nonexistent user")
 			}
@@ -65,7 +66,7 @@ func TestUserRepositoryErrors(t *testing.T) {
 			user := models.NewUser(0, "test@example.com", "Test User")
 			user.SetID("nonexistent-id")
 
-			err := repo.Update(user)
+			err := repo.Update(t.Context(), user)
 			if err == nil {
 				t.Fatal("expected error when updating nonexistent user")
 			}
@@ -78,15 +79,15 @@ func TestUserRepositoryErrors(t *testing.T) {
 			repo := NewUserRepository(db)
 			user := models.NewUser(0, "test@example.com", "Test User")
 
-			if err := repo.Create(user); err != nil {
+			if err := repo.Create(t.Context(), user); err != nil {
 				t.Fatalf("failed to create user: %v", err)
 			}
 
-			if err := repo.Delete(user.ID()); err != nil {
+			if err := repo.Delete(t.Context(), user.ID()); err != nil {
 				t.Fatalf("failed to delete user: %v", err)
 			}
 
-			err := repo.Update(user)
+			err := repo.Update(t.Context(), user)
 			if err == nil {
 				t.Fatal("expected error when updating deleted user")
 			}
@@ -100,7 +101,7 @@ func TestUserRepositoryErrors(t *testing.T) {
 
 			repo := NewUserRepository(db)
 
-			err := repo.Delete("nonexistent-id")
+			err := repo.Delete(t.Context(), "nonexistent-id")
 			if err == nil {
 				t.Fatal("expected error when deleting nonexistent user")
 			}
@@ -113,15 +114,15 @@ func TestUserRepositoryErrors(t *testing.T) {
 			repo := NewUserRepository(db)
 			user := models.NewUser(0, "test@example.com", "Test User")
 
-			if err := repo.Create(user); err != nil {
+			if err := repo.Create(t.Context(), user); err != nil {
 				t.Fatalf("failed to create user: %v", err)
 			}
 
-			if err := repo.Delete(user.ID()); err != nil {
+			if err := repo.Delete(t.Context(), user.ID()); err != nil {
 				t.Fatalf("failed to delete user: %v", err)
 			}
 
-			err := repo.Delete(user.ID())
+			err := repo.Delete(t.Context(), user.ID())
 			if err == nil {
 				t.Fatal("expected error when deleting already deleted user")
 			}
@@ -138,18 +139,18 @@ func TestUserRepositoryErrors(t *testing.T) {
 			user1 := models.NewUser(0, "user1@example.com", "User One")
 			user2 := models.NewUser(0, "user2@example.com", "User Two")
 
-			if err := repo.Create(user1); err != nil {
+			if err := repo.Create(t.Context(), user1); err != nil {
 				t.Fatalf("failed to create user1: %v", err)
 			}
-			if err := repo.Create(user2); err != nil {
+			if err := repo.Create(t.Context(), user2); err != nil {
 				t.Fatalf("failed to create user2: %v", err)
 			}
 
-			if err := repo.Delete(user1.ID()); err != nil {
+			if err := repo.Delete(t.Context(), user1.ID()); err != nil {
 				t.Fatalf("failed to delete user1: %v", err)
 			}
 
-			users, err := repo.List(map[string]any{})
+			users, err := repo.List(t.Context(), map[string]any{}, models.ListOpts{})
 			if err != nil {
 				t.Fatalf("failed to list users: %v", err)
 			}
@@ -175,20 +176,20 @@ func TestTrackRepositoryErrors(t *testing.T) {
 			trackDTO := models.Track{
 				ID:       "spotify123",
 				Title:    "Test Song",
-				Artist:   "Test Artist",
+				Artists:  []string{"Test Artist"},
 				Album:    "Test Album",
 				Duration: 180,
 				ISRC:     "USTEST1234567",
 			}
 
 			track1 := models.NewPersistedTrack(0, "spotify", "spotify123", trackDTO)
-			if err := repo.Create(track1); err != nil {
+			if err := repo.Create(t.Context(), track1); err != nil {
 				t.Fatalf("failed to create first track: %v", err)
 			}
 
 			// Try to create another track with same service+service_id
 			track2 := models.NewPersistedTrack(0, "spotify", "spotify123", trackDTO)
-			err := repo.Create(track2)
+			err := repo.Create(t.Context(), track2)
 			if err == nil {
 				t.Fatal("expected error when creating track with duplicate service+service_id")
 			}
@@ -201,14 +202,13 @@ func TestTrackRepositoryErrors(t *testing.T) {
 			repo := NewTrackRepository(db)
 
 			trackDTO := models.Track{
-				ID:     "spotify123",
-				Title:  "",
-				Artist: "",
+				ID:    "spotify123",
+				Title: "",
 			}
 			track := models.NewPersistedTrack(0, "spotify", "spotify123", trackDTO)
 			track.SetID("test-id")
 
-			err := repo.Create(track)
+			err := repo.Create(t.Context(), track)
 			if err == nil {
 				t.Fatal("expected validation error for track with empty title and artist")
 			}
@@ -223,7 +223,7 @@ func TestTrackRepositoryErrors(t *testing.T) {
 
 			repo := NewTrackRepository(db)
 
-			_, err := repo.GetByServiceID("spotify", "nonexistent")
+			_, err := repo.GetByServiceID(t.Context(), "spotify", "nonexistent")
 			if err == nil {
 				t.Fatal("expected error when getting nonexistent track")
 			}
@@ -235,7 +235,7 @@ func TestTrackRepositoryErrors(t *testing.T) {
 
 			repo := NewTrackRepository(db)
 
-			_, err := repo.GetByISRC("NONEXISTENT")
+			_, err := repo.GetByISRC(t.Context(), "NONEXISTENT")
 			if err == nil {
 				t.Fatal("expected error when getting track by nonexistent ISRC")
 			}
@@ -247,14 +247,14 @@ func TestTrackRepositoryErrors(t *testing.T) {
 
 			repo := NewTrackRepository(db)
 			trackDTO := models.Track{
-				ID:     "spotify123",
-				Title:  "Test Song",
-				Artist: "Test Artist",
+				ID:      "spotify123",
+				Title:   "Test Song",
+				Artists: []string{"Test Artist"},
 			}
 			track := models.NewPersistedTrack(0, "spotify", "spotify123", trackDTO)
 			track.SetID("nonexistent-id")
 
-			err := repo.Update(track)
+			err := repo.Update(t.Context(), track)
 			if err == nil {
 				t.Fatal("expected error when updating nonexistent track")
 			}
@@ -266,7 +266,7 @@ func TestTrackRepositoryErrors(t *testing.T) {
 
 			repo := NewTrackRepository(db)
 
-			err := repo.Delete("nonexistent-id")
+			err := repo.Delete(t.Context(), "nonexistent-id")
 			if err == nil {
 				t.Fatal("expected error when deleting nonexistent track")
 			}
@@ -282,7 +282,7 @@ func TestPlaylistRepositoryErrors(t *testing.T) {
 
 			userRepo := NewUserRepository(db)
 			user := models.NewUser(0, "test@example.com", "Test User")
-			if err := userRepo.Create(user); err != nil {
+			if err := userRepo.Create(t.Context(), user); err != nil {
 				t.Fatalf("failed to create user: %v", err)
 			}
 
@@ -296,12 +296,12 @@ func TestPlaylistRepositoryErrors(t *testing.T) {
 			}
 
 			playlist1 := models.NewPersistedPlaylist(0, "spotify", "spotify123", user.ID(), playlistDTO)
-			if err := playlistRepo.Create(playlist1); err != nil {
+			if err := playlistRepo.Create(t.Context(), playlist1); err != nil {
 				t.Fatalf("failed to create first playlist: %v", err)
 			}
 
 			playlist2 := models.NewPersistedPlaylist(0, "spotify", "spotify123", user.ID(), playlistDTO)
-			err := playlistRepo.Create(playlist2)
+			err := playlistRepo.Create(t.Context(), playlist2)
 			if err == nil {
 				t.Fatal("expected error when creating playlist with duplicate service+service_id")
 			}
@@ -321,7 +321,7 @@ func TestPlaylistRepositoryErrors(t *testing.T) {
 			}
 
 			playlist := models.NewPersistedPlaylist(0, "spotify", "spotify123", "nonexistent-user", playlistDTO)
-			err := playlistRepo.Create(playlist)
+			err := playlistRepo.Create(t.Context(), playlist)
 			if err == nil {
 				t.Fatal("expected error when creating playlist with invalid user_id")
 			}
@@ -335,7 +335,7 @@ func TestPlaylistRepositoryErrors(t *testing.T) {
 
 			playlistRepo := NewPlaylistRepository(db)
 
-			_, err := playlistRepo.GetByServiceID("spotify", "nonexistent")
+			_, err := playlistRepo.GetByServiceID(t.Context(), "user", "spotify", "nonexistent")
 			if err == nil {
 				t.Fatal("expected error when getting nonexistent playlist")
 			}
@@ -347,7 +347,7 @@ func TestPlaylistRepositoryErrors(t *testing.T) {
 
 			userRepo := NewUserRepository(db)
 			user := models.NewUser(0, "test@example.com", "Test User")
-			if err := userRepo.Create(user); err != nil {
+			if err := userRepo.Create(t.Context(), user); err != nil {
 				t.Fatalf("failed to create user: %v", err)
 			}
 
@@ -362,7 +362,7 @@ func TestPlaylistRepositoryErrors(t *testing.T) {
 			playlist := models.NewPersistedPlaylist(0, "spotify", "spotify123", user.ID(), playlistDTO)
 			playlist.SetID("nonexistent-id")
 
-			err := playlistRepo.Update(playlist)
+			err := playlistRepo.Update(t.Context(), playlist)
 			if err == nil {
 				t.Fatal("expected error when updating nonexistent playlist")
 			}
@@ -374,7 +374,7 @@ func TestPlaylistRepositoryErrors(t *testing.T) {
 
 			playlistRepo := NewPlaylistRepository(db)
 
-			err := playlistRepo.Delete("nonexistent-id")
+			err := playlistRepo.Delete(t.Context(), "nonexistent-id")
 			if err == nil {
 				t.Fatal("expected error when deleting nonexistent playlist")
 			}
@@ -391,7 +391,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 			migrationRepo := NewMigrationRepository(db)
 
 			migration := models.NewMigrationJob(0, "nonexistent-user", "spotify", "playlist123", "youtube")
-			err := migrationRepo.Create(migration)
+			err := migrationRepo.Create(t.Context(), migration)
 			if err == nil {
 				t.Fatal("expected error when creating migration with invalid user_id")
 			}
@@ -405,7 +405,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 
 			migrationRepo := NewMigrationRepository(db)
 
-			_, err := migrationRepo.Get("nonexistent-id")
+			_, err := migrationRepo.Get(t.Context(), "nonexistent-id")
 			if err == nil {
 				t.Fatal("expected error when getting nonexistent migration")
 			}
@@ -417,7 +417,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 
 			userRepo := NewUserRepository(db)
 			user := models.NewUser(0, "test@example.com", "Test User")
-			if err := userRepo.Create(user); err != nil {
+			if err := userRepo.Create(t.Context(), user); err != nil {
 				t.Fatalf("failed to create user: %v", err)
 			}
 
@@ -425,7 +425,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 			migration := models.NewMigrationJob(0, user.ID(), "spotify", "playlist123", "youtube")
 			migration.SetID("nonexistent-id")
 
-			err := migrationRepo.Update(migration)
+			err := migrationRepo.Update(t.Context(), migration)
 			if err == nil {
 				t.Fatal("expected error when updating nonexistent migration")
 			}
@@ -437,7 +437,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 
 			migrationRepo := NewMigrationRepository(db)
 
-			err := migrationRepo.Delete("nonexistent-id")
+			err := migrationRepo.Delete(t.Context(), "nonexistent-id")
 			if err == nil {
 				t.Fatal("expected error when deleting nonexistent migration")
 			}
@@ -451,7 +451,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 
 			userRepo := NewUserRepository(db)
 			user := models.NewUser(0, "test@example.com", "Test User")
-			if err := userRepo.Create(user); err != nil {
+			if err := userRepo.Create(t.Context(), user); err != nil {
 				t.Fatalf("failed to create user: %v", err)
 			}
 
@@ -465,7 +465,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 					TrackCount:  10,
 					Public:      false,
 				})
-				if err := playlistRepo.Create(pl); err != nil {
+				if err := playlistRepo.Create(t.Context(), pl); err != nil {
 					t.Fatalf("failed to create playlist%d: %v", i+1, err)
 				}
 				playlists[i] = pl
@@ -475,23 +475,23 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 
 			migration1 := models.NewMigrationJob(0, user.ID(), "spotify", playlists[0].ID(), "youtube")
 			migration1.SetStatus("pending")
-			if err := migrationRepo.Create(migration1); err != nil {
+			if err := migrationRepo.Create(t.Context(), migration1); err != nil {
 				t.Fatalf("failed to create migration1: %v", err)
 			}
 
 			migration2 := models.NewMigrationJob(0, user.ID(), "spotify", playlists[1].ID(), "youtube")
 			migration2.SetStatus("completed")
-			if err := migrationRepo.Create(migration2); err != nil {
+			if err := migrationRepo.Create(t.Context(), migration2); err != nil {
 				t.Fatalf("failed to create migration2: %v", err)
 			}
 
 			migration3 := models.NewMigrationJob(0, user.ID(), "spotify", playlists[2].ID(), "youtube")
 			migration3.SetStatus("completed")
-			if err := migrationRepo.Create(migration3); err != nil {
+			if err := migrationRepo.Create(t.Context(), migration3); err != nil {
 				t.Fatalf("failed to create migration3: %v", err)
 			}
 
-			completed, err := migrationRepo.List(map[string]any{"status": "completed"})
+			completed, err := migrationRepo.List(t.Context(), map[string]any{"status": "completed"}, models.ListOpts{})
 			if err != nil {
 				t.Fatalf("failed to list completed migrations: %v", err)
 			}
@@ -500,7 +500,7 @@ func TestMigrationRepositoryErrors(t *testing.T) {
 				t.Errorf("expected 2 completed migrations, got %d", len(completed))
 			}
 
-			pending, err := migrationRepo.List(map[string]any{"status": "pending"})
+			pending, err := migrationRepo.List(t.Context(), map[string]any{"status": "pending"}, models.ListOpts{})
 			if err != nil {
 				t.Fatalf("failed to list pending migrations: %v", err)
 			}
@@ -520,12 +520,34 @@ func TestTrackCacheAdapter_CacheTrack_InvalidTrack(t *testing.T) {
 	adapter := NewTrackCacheAdapter(repo)
 
 	trackDTO := models.Track{
-		ID:     "spotify123",
-		Title:  "",
-		Artist: "",
+		ID:    "spotify123",
+		Title: "",
 	}
 
-	if err := adapter.CacheTrack("spotify", "spotify123", trackDTO); err == nil {
+	if err := adapter.CacheTrack(t.Context(), "spotify", "spotify123", trackDTO); err == nil {
 		t.Fatal("expected error when caching invalid track")
 	}
 }
+
+func TestRepositories_CancelledContext(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	repo := NewTrackRepository(db)
+	track := models.NewPersistedTrack(0, "spotify", "sp1", models.Track{ID: "sp1", Title: "Song", Artists: []string{"Artist"}})
+
+	if err := repo.Create(ctx, track); err == nil {
+		t.Error("expected error when creating with cancelled context")
+	}
+
+	if _, err := repo.List(ctx, map[string]any{}, models.ListOpts{}); err == nil {
+		t.Error("expected error when listing with cancelled context")
+	}
+
+	if _, err := NextSequence(ctx, db, "tracks"); err == nil {
+		t.Error("expected error from NextSequence with cancelled context")
+	}
+}
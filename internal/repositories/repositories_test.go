@@ -2,16 +2,28 @@ package repositories
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
 	"github.com/desertthunder/ytx/internal/shared"
+	"golang.org/x/oauth2"
 )
 
-// setupTestDB creates an in-memory SQLite database with migrations applied
+// setupTestDB returns a migrated in-memory SQLite database, or, when YTX_TEST_POSTGRES_DSN is set, the Postgres
+// database it names with its schema reset, so the suite runs against either backend.
 func setupTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 
+	if dsn := os.Getenv("YTX_TEST_POSTGRES_DSN"); dsn != "" {
+		return setupPostgresTestDB(t, dsn)
+	}
+
 	db, err := shared.NewDatabase(":memory:")
 	if err != nil {
 		t.Fatalf("failed to create test database: %v", err)
@@ -30,6 +42,24 @@ func setupTestDB(t *testing.T) *sql.DB {
 	return db
 }
 
+func setupPostgresTestDB(t *testing.T, dsn string) *sql.DB {
+	t.Helper()
+
+	db, err := shared.NewDatabase(dsn)
+	if err != nil {
+		t.Fatalf("failed to open Postgres test database: %v", err)
+	}
+	if _, err := db.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public"); err != nil {
+		db.Close()
+		t.Fatalf("failed to reset schema: %v", err)
+	}
+	if err := shared.RunMigrations(db); err != nil {
+		db.Close()
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	return db
+}
+
 func TestUserRepository(t *testing.T) {
 	t.Run("Create", func(t *testing.T) {
 		db := setupTestDB(t)
@@ -38,7 +68,7 @@ func TestUserRepository(t *testing.T) {
 		repo := NewUserRepository(db)
 		user := models.NewUser(0, "test@example.com", "Test User")
 
-		err := repo.Create(user)
+		err := repo.Create(t.Context(), user)
 		if err != nil {
 			t.Fatalf("failed to create user: %v", err)
 		}
@@ -55,11 +85,11 @@ func TestUserRepository(t *testing.T) {
 		repo := NewUserRepository(db)
 		user := models.NewUser(0, "test@example.com", "Test User")
 
-		if err := repo.Create(user); err != nil {
+		if err := repo.Create(t.Context(), user); err != nil {
 			t.Fatalf("failed to create user: %v", err)
 		}
 
-		retrieved, err := repo.Get(user.ID())
+		retrieved, err := repo.Get(t.Context(), user.ID())
 		if err != nil {
 			t.Fatalf("failed to get user: %v", err)
 		}
@@ -80,16 +110,16 @@ func TestUserRepository(t *testing.T) {
 		repo := NewUserRepository(db)
 		user := models.NewUser(0, "test@example.com", "Test User")
 
-		if err := repo.Create(user); err != nil {
+		if err := repo.Create(t.Context(), user); err != nil {
 			t.Fatalf("failed to create user: %v", err)
 		}
 
-		retrieved, err := repo.Get(user.ID())
+		retrieved, err := repo.Get(t.Context(), user.ID())
 		if err != nil {
 			t.Fatalf("failed to get user: %v", err)
 		}
 
-		if err := repo.Update(retrieved); err != nil {
+		if err := repo.Update(t.Context(), retrieved); err != nil {
 			t.Fatalf("failed to update user: %v", err)
 		}
 	})
@@ -101,15 +131,15 @@ func TestUserRepository(t *testing.T) {
 		repo := NewUserRepository(db)
 		user := models.NewUser(0, "test@example.com", "Test User")
 
-		if err := repo.Create(user); err != nil {
+		if err := repo.Create(t.Context(), user); err != nil {
 			t.Fatalf("failed to create user: %v", err)
 		}
 
-		if err := repo.Delete(user.ID()); err != nil {
+		if err := repo.Delete(t.Context(), user.ID()); err != nil {
 			t.Fatalf("failed to delete user: %v", err)
 		}
 
-		_, err := repo.Get(user.ID())
+		_, err := repo.Get(t.Context(), user.ID())
 		if err == nil {
 			t.Error("expected error when getting deleted user")
 		}
@@ -128,12 +158,12 @@ func TestUserRepository(t *testing.T) {
 		}
 
 		for _, user := range users {
-			if err := repo.Create(user); err != nil {
+			if err := repo.Create(t.Context(), user); err != nil {
 				t.Fatalf("failed to create user: %v", err)
 			}
 		}
 
-		retrieved, err := repo.List(map[string]any{})
+		retrieved, err := repo.List(t.Context(), map[string]any{}, models.ListOpts{})
 		if err != nil {
 			t.Fatalf("failed to list users: %v", err)
 		}
@@ -142,7 +172,7 @@ func TestUserRepository(t *testing.T) {
 			t.Errorf("expected 3 users, got %d", len(retrieved))
 		}
 
-		filtered, err := repo.List(map[string]any{"email": "user2@example.com"})
+		filtered, err := repo.List(t.Context(), map[string]any{"email": "user2@example.com"}, models.ListOpts{})
 		if err != nil {
 			t.Fatalf("failed to list filtered users: %v", err)
 		}
@@ -166,7 +196,7 @@ func TestTrackRepository(t *testing.T) {
 		trackDTO := models.Track{
 			ID:       "spotify123",
 			Title:    "Test Song",
-			Artist:   "Test Artist",
+			Artists:  []string{"Test Artist"},
 			Album:    "Test Album",
 			Duration: 180,
 			ISRC:     "USTEST1234567",
@@ -174,11 +204,11 @@ func TestTrackRepository(t *testing.T) {
 
 		track := models.NewPersistedTrack(0, "spotify", "spotify123", trackDTO)
 
-		if err := repo.Create(track); err != nil {
+		if err := repo.Create(t.Context(), track); err != nil {
 			t.Fatalf("failed to create track: %v", err)
 		}
 
-		retrieved, err := repo.GetByServiceID("spotify", "spotify123")
+		retrieved, err := repo.GetByServiceID(t.Context(), "spotify", "spotify123")
 		if err != nil {
 			t.Fatalf("failed to get track: %v", err)
 		}
@@ -199,17 +229,17 @@ func TestTrackRepository(t *testing.T) {
 		repo := NewTrackRepository(db)
 
 		spotifyTrack := models.NewPersistedTrack(0, "spotify", "spotify123", models.Track{
-			ID:     "spotify123",
-			Title:  "Test Song",
-			Artist: "Test Artist",
-			ISRC:   "USTEST1234567",
+			ID:      "spotify123",
+			Title:   "Test Song",
+			Artists: []string{"Test Artist"},
+			ISRC:    "USTEST1234567",
 		})
 
-		if err := repo.Create(spotifyTrack); err != nil {
+		if err := repo.Create(t.Context(), spotifyTrack); err != nil {
 			t.Fatalf("failed to create Spotify track: %v", err)
 		}
 
-		retrieved, err := repo.GetByISRC("USTEST1234567")
+		retrieved, err := repo.GetByISRC(t.Context(), "USTEST1234567")
 		if err != nil {
 			t.Fatalf("failed to get track by ISRC: %v", err)
 		}
@@ -230,67 +260,1456 @@ func TestTrackCacheAdapter_CacheTrack(t *testing.T) {
 	trackDTO := models.Track{
 		ID:       "spotify123",
 		Title:    "Test Song",
-		Artist:   "Test Artist",
+		Artists:  []string{"Test Artist"},
 		Album:    "Test Album",
 		Duration: 180,
 		ISRC:     "USTEST1234567",
 	}
 
-	if err := adapter.CacheTrack("spotify", "spotify123", trackDTO); err != nil {
+	if err := adapter.CacheTrack(t.Context(), "spotify", "spotify123", trackDTO); err != nil {
 		t.Fatalf("failed to cache track: %v", err)
 	}
 
-	if err := adapter.CacheTrack("spotify", "spotify123", trackDTO); err != nil {
+	cached, err := repo.GetByServiceID(t.Context(), "spotify", "spotify123")
+	if err != nil {
+		t.Fatalf("failed to retrieve cached track: %v", err)
+	}
+
+	trackDTO.Title = "Test Song (Remastered)"
+	trackDTO.Album = "Test Album (Deluxe)"
+	trackDTO.Duration = 184
+	trackDTO.ISRC = "USTEST7654321"
+	if err := adapter.CacheTrack(t.Context(), "spotify", "spotify123", trackDTO); err != nil {
 		t.Fatalf("caching duplicate track should not error: %v", err)
 	}
 
-	retrieved, err := repo.GetByServiceID("spotify", "spotify123")
+	retrieved, err := repo.GetByServiceID(t.Context(), "spotify", "spotify123")
 	if err != nil {
 		t.Fatalf("failed to retrieve cached track: %v", err)
 	}
 
-	if retrieved.Title() != "Test Song" {
-		t.Errorf("expected title 'Test Song', got %s", retrieved.Title())
+	if retrieved.ID() != cached.ID() {
+		t.Errorf("expected the cached track updated in place, got %s then %s", cached.ID(), retrieved.ID())
+	}
+	if retrieved.Title() != "Test Song (Remastered)" || retrieved.Album() != "Test Album (Deluxe)" ||
+		retrieved.Duration() != 184 || retrieved.ISRC() != "USTEST7654321" {
+		t.Errorf("expected refreshed metadata, got %s / %s / %d / %s", retrieved.Title(), retrieved.Album(), retrieved.Duration(), retrieved.ISRC())
 	}
 }
 
-func TestPlaylistRepository_CreateAndGet(t *testing.T) {
+func TestAlbumRepository(t *testing.T) {
+	t.Run("CreateAndGetByUPC", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		repo := NewAlbumRepository(db)
+		albumDTO := models.Album{
+			ID:         "spotifyAlbum1",
+			Title:      "Test Album",
+			Artist:     "Test Artist",
+			Year:       "2021",
+			TrackCount: 12,
+			UPC:        "00602537518357",
+		}
+
+		album := models.NewPersistedAlbum(0, "spotify", "spotifyAlbum1", albumDTO)
+		if err := repo.Create(t.Context(), album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+
+		retrieved, err := repo.GetByUPC(t.Context(), "00602537518357")
+		if err != nil {
+			t.Fatalf("failed to get album by UPC: %v", err)
+		}
+
+		if retrieved.ServiceID() != "spotifyAlbum1" {
+			t.Errorf("expected service ID spotifyAlbum1, got %s", retrieved.ServiceID())
+		}
+
+		if retrieved.ToAlbum().UPC != albumDTO.UPC {
+			t.Errorf("expected UPC %s, got %s", albumDTO.UPC, retrieved.UPC())
+		}
+	})
+
+	t.Run("GetByEmptyUPC", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		repo := NewAlbumRepository(db)
+		album := models.NewPersistedAlbum(0, "youtube", "ytAlbum1", models.Album{ID: "ytAlbum1", Title: "No UPC"})
+		if err := repo.Create(t.Context(), album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+
+		retrieved, err := repo.GetByUPC(t.Context(), "")
+		if !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Fatalf("expected ErrRecordNotFound, got album %v and error %v", retrieved, err)
+		}
+	})
+
+	t.Run("DuplicateServiceID", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		repo := NewAlbumRepository(db)
+		albumDTO := models.Album{ID: "yt1", Title: "Test Album"}
+
+		if err := repo.Create(t.Context(), models.NewPersistedAlbum(0, "youtube", "yt1", albumDTO)); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+
+		if err := repo.Create(t.Context(), models.NewPersistedAlbum(0, "youtube", "yt1", albumDTO)); err == nil {
+			t.Error("expected error for duplicate service+service_id")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		repo := NewAlbumRepository(db)
+		album := models.NewPersistedAlbum(0, "youtube", "yt1", models.Album{ID: "yt1", Title: "Test Album"})
+		if err := repo.Create(t.Context(), album); err != nil {
+			t.Fatalf("failed to create album: %v", err)
+		}
+
+		if err := repo.Delete(t.Context(), album.ID()); err != nil {
+			t.Fatalf("failed to delete album: %v", err)
+		}
+
+		if _, err := repo.Get(t.Context(), album.ID()); err == nil {
+			t.Error("expected error when getting deleted album")
+		}
+	})
+}
+
+func TestArtistRepository(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewArtistRepository(db)
+
+	spotifyArtist := models.NewPersistedArtist(0, "spotify", "sp1", models.Artist{ID: "sp1", Name: "Test Artist"})
+	if err := repo.Create(t.Context(), spotifyArtist); err != nil {
+		t.Fatalf("failed to create artist: %v", err)
+	}
+
+	youtubeArtist := models.NewPersistedArtist(0, "youtube", "UC1", models.Artist{ID: "UC1", Name: "Test Artist"})
+	if err := repo.Create(t.Context(), youtubeArtist); err != nil {
+		t.Fatalf("failed to create artist: %v", err)
+	}
+
+	first, err := repo.Get(t.Context(), spotifyArtist.ID())
+	if err != nil {
+		t.Fatalf("failed to get artist: %v", err)
+	}
+	second, err := repo.Get(t.Context(), youtubeArtist.ID())
+	if err != nil {
+		t.Fatalf("failed to get artist: %v", err)
+	}
+	if second.Sequence() <= first.Sequence() {
+		t.Errorf("expected increasing sequence numbers, got %d then %d", first.Sequence(), second.Sequence())
+	}
+
+	matched, err := repo.GetByName(t.Context(), "test artist")
+	if err != nil {
+		t.Fatalf("failed to get artist by name: %v", err)
+	}
+	if matched.ID() != spotifyArtist.ID() {
+		t.Errorf("expected first cached artist %s, got %s", spotifyArtist.ID(), matched.ID())
+	}
+
+	artists, err := repo.List(t.Context(), map[string]any{"service": "youtube"}, models.ListOpts{})
+	if err != nil {
+		t.Fatalf("failed to list artists: %v", err)
+	}
+	if len(artists) != 1 {
+		t.Errorf("expected 1 youtube artist, got %d", len(artists))
+	}
+}
+
+func TestLibraryCacheAdapter_Upsert(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	user, err := EnsureLocalUser(t.Context(), NewUserRepository(db))
+	if err != nil {
+		t.Fatalf("failed to ensure local user: %v", err)
+	}
+
+	again, err := EnsureLocalUser(t.Context(), NewUserRepository(db))
+	if err != nil {
+		t.Fatalf("failed to ensure local user twice: %v", err)
+	}
+	if again.ID() != user.ID() {
+		t.Errorf("expected local user to be reused, got %s and %s", user.ID(), again.ID())
+	}
+
+	albums := NewAlbumRepository(db)
+	artists := NewArtistRepository(db)
+	playlists := NewPlaylistRepository(db)
+	adapter := NewLibraryCacheAdapter(user.ID(), playlists, NewTrackRepository(db), albums, artists)
+
+	if err := adapter.UpsertPlaylist(t.Context(), "youtube", models.Playlist{ID: "PL1", Name: "Old"}); err != nil {
+		t.Fatalf("failed to upsert playlist: %v", err)
+	}
+	if err := adapter.UpsertPlaylist(t.Context(), "youtube", models.Playlist{ID: "PL1", Name: "New"}); err != nil {
+		t.Fatalf("failed to re-upsert playlist: %v", err)
+	}
+
+	playlist, err := playlists.GetByServiceID(t.Context(), user.ID(), "youtube", "PL1")
+	if err != nil {
+		t.Fatalf("failed to get playlist: %v", err)
+	}
+	if playlist.Name() != "New" {
+		t.Errorf("expected refreshed name 'New', got %s", playlist.Name())
+	}
+
+	if err := adapter.UpsertTrack(t.Context(), "youtube", models.Track{ID: "vid1", Title: "Song", Artists: []string{"Artist"}}); err != nil {
+		t.Fatalf("failed to upsert track: %v", err)
+	}
+
+	if err := adapter.UpsertAlbum(t.Context(), "youtube", models.Album{ID: "MPRE1", Title: "Album", Year: "2020"}); err != nil {
+		t.Fatalf("failed to upsert album: %v", err)
+	}
+	if err := adapter.UpsertAlbum(t.Context(), "youtube", models.Album{ID: "MPRE1", Title: "Album", Year: "2021"}); err != nil {
+		t.Fatalf("failed to re-upsert album: %v", err)
+	}
+
+	cachedAlbums, err := albums.List(t.Context(), map[string]any{"service": "youtube"}, models.ListOpts{})
+	if err != nil {
+		t.Fatalf("failed to list albums: %v", err)
+	}
+	if len(cachedAlbums) != 1 || cachedAlbums[0].Year() != "2021" {
+		t.Errorf("expected one refreshed album, got %d", len(cachedAlbums))
+	}
+
+	if err := adapter.UpsertArtist(t.Context(), "youtube", models.Artist{ID: "UC1", Name: "Artist"}); err != nil {
+		t.Fatalf("failed to upsert artist: %v", err)
+	}
+
+	artist, err := artists.GetByServiceID(t.Context(), "youtube", "UC1")
+	if err != nil {
+		t.Fatalf("failed to get artist: %v", err)
+	}
+	if artist.Name() != "Artist" {
+		t.Errorf("expected artist name 'Artist', got %s", artist.Name())
+	}
+}
+
+func TestTrackRepository_Batch(t *testing.T) {
+	t.Run("CreateBatch", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		repo := NewTrackRepository(db)
+		tracks := []*models.PersistedTrack{
+			models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Song 1", Artists: []string{"Artist"}}),
+			models.NewPersistedTrack(0, "spotify", "sp2", models.Track{Title: "Song 2", Artists: []string{"Artist"}}),
+		}
+
+		if err := repo.CreateBatch(t.Context(), tracks); err != nil {
+			t.Fatalf("failed to create batch: %v", err)
+		}
+
+		cached, err := repo.List(t.Context(), map[string]any{"service": "spotify"}, models.ListOpts{})
+		if err != nil {
+			t.Fatalf("failed to list tracks: %v", err)
+		}
+		if len(cached) != 2 {
+			t.Fatalf("expected 2 tracks, got %d", len(cached))
+		}
+		if cached[1].Sequence() != cached[0].Sequence()+1 {
+			t.Errorf("expected contiguous sequences, got %d and %d", cached[0].Sequence(), cached[1].Sequence())
+		}
+	})
+
+	t.Run("CreateBatchRollsBack", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		repo := NewTrackRepository(db)
+		tracks := []*models.PersistedTrack{
+			models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Song 1", Artists: []string{"Artist"}}),
+			models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Duplicate", Artists: []string{"Artist"}}),
+		}
+
+		if err := repo.CreateBatch(t.Context(), tracks); err == nil {
+			t.Fatal("expected error for duplicate track in batch")
+		}
+
+		cached, err := repo.List(t.Context(), map[string]any{}, models.ListOpts{})
+		if err != nil {
+			t.Fatalf("failed to list tracks: %v", err)
+		}
+		if len(cached) != 0 {
+			t.Errorf("expected failed batch to insert nothing, got %d tracks", len(cached))
+		}
+	})
+
+	t.Run("UpsertBatch", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		repo := NewTrackRepository(db)
+		original := models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Old", Artists: []string{"Artist"}})
+		if err := repo.Create(t.Context(), original); err != nil {
+			t.Fatalf("failed to create track: %v", err)
+		}
+
+		tracks := []*models.PersistedTrack{
+			models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "New", Artists: []string{"Artist"}}),
+			models.NewPersistedTrack(0, "spotify", "sp2", models.Track{Title: "Song 2", Artists: []string{"Artist"}}),
+		}
+
+		if err := repo.UpsertBatch(t.Context(), tracks); err != nil {
+			t.Fatalf("failed to upsert batch: %v", err)
+		}
+
+		if tracks[0].ID() != original.ID() {
+			t.Errorf("expected upserted ID %s, got %s", original.ID(), tracks[0].ID())
+		}
+
+		retrieved, err := repo.Get(t.Context(), original.ID())
+		if err != nil {
+			t.Fatalf("failed to get track: %v", err)
+		}
+		if retrieved.Title() != "New" {
+			t.Errorf("expected refreshed title 'New', got %s", retrieved.Title())
+		}
+	})
+}
+
+func TestTrackRepository_Enrichment(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTrackRepository(db)
+	features := &models.AudioFeatures{Danceability: 0.8, Energy: 0.6, Tempo: 121.5, Loudness: -5.2}
+	enriched := models.Track{Title: "Song", Artists: []string{"Artist"}, Genres: []string{"indie pop", "shoegaze"}, Features: features}
+	if err := repo.Upsert(t.Context(), models.NewPersistedTrack(0, "spotify", "sp1", enriched)); err != nil {
+		t.Fatalf("failed to upsert track: %v", err)
+	}
+
+	// Caching the track again without enrichment, as transfers do, keeps what was found
+	plain := models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Song (Remastered)", Artists: []string{"Artist"}})
+	if err := repo.Upsert(t.Context(), plain); err != nil {
+		t.Fatalf("failed to upsert track: %v", err)
+	}
+
+	retrieved, err := repo.GetByServiceID(t.Context(), "spotify", "sp1")
+	if err != nil {
+		t.Fatalf("failed to get track: %v", err)
+	}
+	track := retrieved.ToTrack()
+	if track.Title != "Song (Remastered)" {
+		t.Errorf("expected refreshed title, got %s", track.Title)
+	}
+	if len(track.Genres) != 2 || track.Genres[1] != "shoegaze" {
+		t.Errorf("expected genres to be kept, got %v", track.Genres)
+	}
+	if track.Features == nil || *track.Features != *features {
+		t.Errorf("expected audio features %+v, got %+v", features, track.Features)
+	}
+}
+
+func TestTrackCacheAdapter_BulkCacheTracks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTrackRepository(db)
+	adapter := NewTrackCacheAdapter(repo)
+
+	tracks := []models.Track{
+		{ID: "yt1", Title: "Song 1", Artists: []string{"Artist"}},
+		{ID: "yt2", Title: "Song 2", Artists: []string{"Artist"}},
+		{ID: "", Title: "No ID", Artists: []string{"Artist"}},
+	}
+
+	if err := adapter.BulkCacheTracks(t.Context(), "youtube", tracks); err != nil {
+		t.Fatalf("failed to bulk cache tracks: %v", err)
+	}
+	if err := adapter.BulkCacheTracks(t.Context(), "youtube", tracks); err != nil {
+		t.Fatalf("re-caching tracks should not error: %v", err)
+	}
+
+	cached, err := repo.List(t.Context(), map[string]any{"service": "youtube"}, models.ListOpts{})
+	if err != nil {
+		t.Fatalf("failed to list tracks: %v", err)
+	}
+	if len(cached) != 2 {
+		t.Errorf("expected 2 cached tracks, got %d", len(cached))
+	}
+}
+
+func TestPlaylistTrackRepository(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
-	userRepo := NewUserRepository(db)
 	user := models.NewUser(0, "test@example.com", "Test User")
-	if err := userRepo.Create(user); err != nil {
+	if err := NewUserRepository(db).Create(t.Context(), user); err != nil {
 		t.Fatalf("failed to create user: %v", err)
 	}
 
-	// Create playlist
 	playlistRepo := NewPlaylistRepository(db)
-	playlistDTO := models.Playlist{
-		ID:          "spotify123",
-		Name:        "Test Playlist",
-		Description: "Test Description",
-		TrackCount:  10,
-		Public:      true,
+	playlists := []*models.PersistedPlaylist{
+		models.NewPersistedPlaylist(0, "youtube", "PL1", user.ID(), models.Playlist{Name: "Playlist"}),
+	}
+	if err := playlistRepo.UpsertBatch(t.Context(), playlists); err != nil {
+		t.Fatalf("failed to upsert playlists: %v", err)
 	}
+	playlist := playlists[0]
 
-	playlist := models.NewPersistedPlaylist(0, "spotify", "spotify123", user.ID(), playlistDTO)
+	trackRepo := NewTrackRepository(db)
+	tracks := []*models.PersistedTrack{
+		models.NewPersistedTrack(0, "youtube", "yt1", models.Track{Title: "Song 1", Artists: []string{"Artist"}}),
+		models.NewPersistedTrack(0, "youtube", "yt2", models.Track{Title: "Song 2", Artists: []string{"Artist"}}),
+	}
+	if err := trackRepo.CreateBatch(t.Context(), tracks); err != nil {
+		t.Fatalf("failed to create tracks: %v", err)
+	}
 
-	if err := playlistRepo.Create(playlist); err != nil {
-		t.Fatalf("failed to create playlist: %v", err)
+	repo := NewPlaylistTrackRepository(db)
+	entries := []*models.PlaylistTrack{
+		models.NewPlaylistTrack(0, playlist.ID(), tracks[1].ID(), 0),
+		models.NewPlaylistTrack(0, playlist.ID(), tracks[0].ID(), 1),
+	}
+	if err := repo.CreateBatch(t.Context(), entries); err != nil {
+		t.Fatalf("failed to create playlist tracks: %v", err)
 	}
 
-	retrieved, err := playlistRepo.GetByServiceID("spotify", "spotify123")
+	listed, err := repo.List(t.Context(), map[string]any{"playlist_id": playlist.ID()}, models.ListOpts{})
 	if err != nil {
-		t.Fatalf("failed to get playlist: %v", err)
+		t.Fatalf("failed to list playlist tracks: %v", err)
+	}
+	if len(listed) != 2 || listed[0].TrackID() != tracks[1].ID() {
+		t.Fatalf("expected playlist tracks ordered by position")
 	}
 
-	if retrieved.Name() != "Test Playlist" {
-		t.Errorf("expected name 'Test Playlist', got %s", retrieved.Name())
+	reordered := []*models.PlaylistTrack{
+		models.NewPlaylistTrack(0, playlist.ID(), tracks[0].ID(), 0),
+		models.NewPlaylistTrack(0, playlist.ID(), tracks[1].ID(), 1),
+	}
+	if err := repo.UpsertBatch(t.Context(), reordered); err != nil {
+		t.Fatalf("failed to upsert playlist tracks: %v", err)
 	}
 
-	if retrieved.UserID() != user.ID() {
-		t.Errorf("expected user ID %s, got %s", user.ID(), retrieved.UserID())
+	listed, err = repo.List(t.Context(), map[string]any{"playlist_id": playlist.ID()}, models.ListOpts{})
+	if err != nil {
+		t.Fatalf("failed to list playlist tracks: %v", err)
+	}
+	if len(listed) != 2 || listed[0].TrackID() != tracks[0].ID() {
+		t.Errorf("expected upsert to reorder playlist tracks")
+	}
+
+	if err := repo.Delete(t.Context(), listed[0].ID()); err != nil {
+		t.Fatalf("failed to delete playlist track: %v", err)
+	}
+	if _, err := repo.Get(t.Context(), listed[0].ID()); err == nil {
+		t.Error("expected error when getting deleted playlist track")
+	}
+}
+
+func TestPlaylistCacheAdapter_CachePlaylist(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	user, err := EnsureLocalUser(t.Context(), NewUserRepository(db))
+	if err != nil {
+		t.Fatalf("failed to ensure local user: %v", err)
+	}
+
+	playlists := NewPlaylistRepository(db)
+	tracks := NewTrackRepository(db)
+	playlistTracks := NewPlaylistTrackRepository(db)
+	adapter := NewPlaylistCacheAdapter(user.ID(), playlists, tracks, playlistTracks)
+
+	playlist := models.Playlist{ID: "PL1", Name: "Road Trip"}
+	first := []models.Track{
+		{ID: "yt1", Title: "Song 1", Artists: []string{"Artist"}},
+		{ID: "yt2", Title: "Song 2", Artists: []string{"Artist"}},
+		{ID: "yt3", Title: "Song 3", Artists: []string{"Artist"}},
+	}
+
+	if err := adapter.CachePlaylist(t.Context(), "youtube", playlist, first); err != nil {
+		t.Fatalf("failed to cache playlist: %v", err)
+	}
+
+	second := []models.Track{first[2], first[0]}
+	if err := adapter.CachePlaylist(t.Context(), "youtube", playlist, second); err != nil {
+		t.Fatalf("failed to re-cache playlist: %v", err)
+	}
+
+	cached, err := playlists.GetByServiceID(t.Context(), user.ID(), "youtube", "PL1")
+	if err != nil {
+		t.Fatalf("failed to get cached playlist: %v", err)
+	}
+	if cached.TrackCount() != 2 {
+		t.Errorf("expected track count 2, got %d", cached.TrackCount())
+	}
+
+	entries, err := playlistTracks.List(t.Context(), map[string]any{"playlist_id": cached.ID()}, models.ListOpts{})
+	if err != nil {
+		t.Fatalf("failed to list playlist tracks: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 playlist tracks, got %d", len(entries))
+	}
+
+	for i, want := range []string{"yt3", "yt1"} {
+		track, err := tracks.Get(t.Context(), entries[i].TrackID())
+		if err != nil {
+			t.Fatalf("failed to get track: %v", err)
+		}
+		if track.ServiceID() != want {
+			t.Errorf("position %d: expected %s, got %s", i, want, track.ServiceID())
+		}
+	}
+
+	export, err := adapter.LoadPlaylist(t.Context(), "youtube", "PL1")
+	if err != nil {
+		t.Fatalf("failed to load cached playlist: %v", err)
+	}
+	if export.Playlist.Name != "Road Trip" || len(export.Tracks) != 2 || export.Tracks[0].ID != "yt3" {
+		t.Errorf("unexpected cached export: %+v", export)
+	}
+
+	if _, err := adapter.LoadPlaylist(t.Context(), "youtube", "missing"); err == nil {
+		t.Error("expected error loading uncached playlist")
+	}
+}
+
+func TestPlaylistCacheAdapter_SharedPlaylist(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	users := NewUserRepository(db)
+	alice, bob := models.NewUser(0, "alice@example.com", "Alice"), models.NewUser(0, "bob@example.com", "Bob")
+	for _, user := range []*models.User{alice, bob} {
+		if err := users.Create(t.Context(), user); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	playlists := NewPlaylistRepository(db)
+	tracks := []models.Track{{ID: "sp1", Title: "Song 1", Artists: []string{"Artist"}}}
+	for _, user := range []*models.User{alice, bob} {
+		adapter := NewPlaylistCacheAdapter(user.ID(), playlists, NewTrackRepository(db), NewPlaylistTrackRepository(db))
+		if err := adapter.CachePlaylist(t.Context(), "spotify", models.Playlist{ID: "public1", Name: "Top Hits"}, tracks); err != nil {
+			t.Fatalf("failed to cache playlist for %s: %v", user.Email(), err)
+		}
+	}
+
+	migrations := NewMigrationRepository(db)
+	for _, user := range []*models.User{alice, bob} {
+		owned, err := playlists.List(t.Context(), map[string]any{"user_id": user.ID()}, models.ListOpts{})
+		if err != nil {
+			t.Fatalf("failed to list playlists: %v", err)
+		}
+		if len(owned) != 1 || owned[0].ServiceID() != "public1" {
+			t.Fatalf("expected %s to see their copy of the playlist, got %d playlists", user.Email(), len(owned))
+		}
+
+		export, err := NewPlaylistCacheAdapter(user.ID(), playlists, NewTrackRepository(db), NewPlaylistTrackRepository(db)).LoadPlaylist(t.Context(), "spotify", "public1")
+		if err != nil || len(export.Tracks) != 1 {
+			t.Errorf("expected %s's cached tracks loaded, got %+v, %v", user.Email(), export, err)
+		}
+
+		history := NewMigrationHistoryAdapter(user.ID(), playlists, migrations)
+		id, err := history.StartTransfer(t.Context(), models.TransferRecord{
+			SourceService: "spotify", SourcePlaylist: models.Playlist{ID: "public1", Name: "Top Hits"},
+			TargetService: "youtube", StartedAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("failed to start transfer: %v", err)
+		}
+		job, err := migrations.Get(t.Context(), id)
+		if err != nil {
+			t.Fatalf("failed to get migration: %v", err)
+		}
+		if job.SourcePlaylistID() != owned[0].ID() {
+			t.Errorf("expected %s's migration to reference their own playlist %s, got %s", user.Email(), owned[0].ID(), job.SourcePlaylistID())
+		}
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTrackRepository(db)
+	var tracks []*models.PersistedTrack
+	for i := range 5 {
+		service := "spotify"
+		if i == 4 {
+			service = "youtube"
+		}
+		id := fmt.Sprintf("t%d", i)
+		tracks = append(tracks, models.NewPersistedTrack(0, service, id, models.Track{Title: id, Artists: []string{"Artist"}}))
+	}
+	if err := repo.CreateBatch(t.Context(), tracks); err != nil {
+		t.Fatalf("failed to create tracks: %v", err)
+	}
+
+	count, err := repo.Count(t.Context(), map[string]any{"service": "spotify"})
+	if err != nil {
+		t.Fatalf("failed to count tracks: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("expected 4 spotify tracks, got %d", count)
+	}
+
+	page, err := repo.List(t.Context(), map[string]any{"service": "spotify"}, models.ListOpts{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("failed to list page: %v", err)
+	}
+	if len(page) != 2 || page[0].ServiceID() != "t1" || page[1].ServiceID() != "t2" {
+		t.Errorf("expected page [t1 t2], got %d tracks", len(page))
+	}
+
+	desc, err := repo.List(t.Context(), nil, models.ListOpts{OrderBy: models.OrderBySequence, Desc: true, Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to list descending: %v", err)
+	}
+	if len(desc) != 1 || desc[0].ServiceID() != "t4" {
+		t.Errorf("expected newest track t4 first")
+	}
+
+	rest, err := repo.List(t.Context(), nil, models.ListOpts{OrderBy: models.OrderByCreatedAt, Offset: 3})
+	if err != nil {
+		t.Fatalf("failed to list with offset only: %v", err)
+	}
+	if len(rest) != 2 {
+		t.Errorf("expected 2 tracks after offset 3, got %d", len(rest))
+	}
+
+	if _, err := repo.List(t.Context(), nil, models.ListOpts{OrderBy: "title"}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for unsupported order, got %v", err)
+	}
+	if _, err := repo.List(t.Context(), nil, models.ListOpts{Limit: -1}); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for negative limit, got %v", err)
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	user := models.NewUser(0, "test@example.com", "Test User")
+	if err := NewUserRepository(db).Create(t.Context(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	playlistRepo := NewPlaylistRepository(db)
+	playlists := []*models.PersistedPlaylist{
+		models.NewPersistedPlaylist(0, "youtube", "PL1", user.ID(), models.Playlist{Name: "Doomed"}),
+		models.NewPersistedPlaylist(0, "youtube", "PL2", user.ID(), models.Playlist{Name: "Kept"}),
+	}
+	if err := playlistRepo.CreateBatch(t.Context(), playlists); err != nil {
+		t.Fatalf("failed to create playlists: %v", err)
+	}
+
+	trackRepo := NewTrackRepository(db)
+	tracks := []*models.PersistedTrack{
+		models.NewPersistedTrack(0, "youtube", "yt1", models.Track{Title: "Song 1", Artists: []string{"Artist"}}),
+		models.NewPersistedTrack(0, "youtube", "yt2", models.Track{Title: "Song 2", Artists: []string{"Artist"}}),
+	}
+	if err := trackRepo.CreateBatch(t.Context(), tracks); err != nil {
+		t.Fatalf("failed to create tracks: %v", err)
+	}
+
+	playlistTrackRepo := NewPlaylistTrackRepository(db)
+	entries := []*models.PlaylistTrack{
+		models.NewPlaylistTrack(0, playlists[0].ID(), tracks[0].ID(), 0),
+		models.NewPlaylistTrack(0, playlists[1].ID(), tracks[0].ID(), 0),
+		models.NewPlaylistTrack(0, playlists[1].ID(), tracks[1].ID(), 1),
+	}
+	if err := playlistTrackRepo.CreateBatch(t.Context(), entries); err != nil {
+		t.Fatalf("failed to create playlist tracks: %v", err)
+	}
+
+	if err := playlistRepo.Delete(t.Context(), playlists[0].ID()); err != nil {
+		t.Fatalf("failed to delete playlist: %v", err)
+	}
+	if err := trackRepo.Delete(t.Context(), tracks[1].ID()); err != nil {
+		t.Fatalf("failed to delete track: %v", err)
+	}
+
+	removed, err := playlistRepo.PurgeDeleted(t.Context(), time.Hour)
+	if err != nil {
+		t.Fatalf("failed to purge playlists: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("expected recently deleted playlist to be retained, purged %d", removed)
+	}
+
+	removed, err = playlistRepo.PurgeDeleted(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("failed to purge playlists: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 purged playlist, got %d", removed)
+	}
+
+	removed, err = trackRepo.PurgeDeleted(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("failed to purge tracks: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 purged track, got %d", removed)
+	}
+
+	var remaining int
+	if err := db.QueryRowContext(t.Context(), "SELECT COUNT(*) FROM playlist_tracks").Scan(&remaining); err != nil {
+		t.Fatalf("failed to count playlist tracks: %v", err)
+	}
+	if remaining != 1 {
+		t.Errorf("expected purges to cascade to playlist tracks leaving 1 entry, got %d", remaining)
+	}
+
+	if _, err := playlistRepo.Get(t.Context(), playlists[1].ID()); err != nil {
+		t.Errorf("expected live playlist to survive purge: %v", err)
+	}
+}
+
+func TestRestoreDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTrackRepository(db)
+	track := models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Song", Artists: []string{"Artist"}})
+	if err := repo.Create(t.Context(), track); err != nil {
+		t.Fatalf("failed to create track: %v", err)
+	}
+
+	if err := repo.Restore(t.Context(), track.ID()); !errors.Is(err, shared.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound restoring a live track, got %v", err)
+	}
+
+	if err := repo.Delete(t.Context(), track.ID()); err != nil {
+		t.Fatalf("failed to delete track: %v", err)
+	}
+
+	deleted, err := repo.ListDeleted(t.Context())
+	if err != nil {
+		t.Fatalf("failed to list deleted tracks: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID() != track.ID() || deleted[0].DeletedAt() == nil {
+		t.Fatalf("expected deleted track %s to be listed, got %d entries", track.ID(), len(deleted))
+	}
+
+	if err := repo.Restore(t.Context(), track.ID()); err != nil {
+		t.Fatalf("failed to restore track: %v", err)
+	}
+	if _, err := repo.Get(t.Context(), track.ID()); err != nil {
+		t.Errorf("expected restored track to be retrievable: %v", err)
+	}
+
+	t.Run("rejects unique key conflicts", func(t *testing.T) {
+		_, err := db.ExecContext(t.Context(), `
+			CREATE TABLE restorables (id TEXT PRIMARY KEY, name TEXT, deleted_at TIMESTAMP);
+			INSERT INTO restorables VALUES ('a', 'dup', CURRENT_TIMESTAMP), ('b', 'dup', NULL), ('c', 'solo', CURRENT_TIMESTAMP);
+		`)
+		if err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+
+		if err := restoreDeleted(t.Context(), db, "restorables", "a", "name"); !errors.Is(err, shared.ErrRestoreConflict) {
+			t.Errorf("expected ErrRestoreConflict, got %v", err)
+		}
+		if err := restoreDeleted(t.Context(), db, "restorables", "c", "name"); err != nil {
+			t.Errorf("expected non-conflicting restore to succeed, got %v", err)
+		}
+	})
+}
+
+func TestFullTextSearch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	user := models.NewUser(0, "test@example.com", "Test User")
+	if err := NewUserRepository(db).Create(t.Context(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	playlistRepo := NewPlaylistRepository(db)
+	playlists := []*models.PersistedPlaylist{
+		models.NewPersistedPlaylist(0, "spotify", "sp-pl1", user.ID(), models.Playlist{Name: "Road Trip", Description: "Songs for the highway"}),
+		models.NewPersistedPlaylist(0, "youtube", "yt-pl1", user.ID(), models.Playlist{Name: "Focus"}),
+	}
+	if err := playlistRepo.UpsertBatch(t.Context(), playlists); err != nil {
+		t.Fatalf("failed to upsert playlists: %v", err)
+	}
+
+	trackRepo := NewTrackRepository(db)
+	tracks := []*models.PersistedTrack{
+		models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Bohemian Rhapsody", Artists: []string{"Queen"}, Album: "A Night at the Opera"}),
+		models.NewPersistedTrack(0, "spotify", "sp2", models.Track{Title: "Dancing Queen", Artists: []string{"ABBA"}, Album: "Arrival"}),
+		models.NewPersistedTrack(0, "spotify", "sp3", models.Track{Title: "Hey Jude", Artists: []string{"The Beatles"}}),
+	}
+	if err := trackRepo.CreateBatch(t.Context(), tracks); err != nil {
+		t.Fatalf("failed to create tracks: %v", err)
+	}
+
+	entries := []*models.PlaylistTrack{
+		models.NewPlaylistTrack(0, playlists[0].ID(), tracks[0].ID(), 0),
+		models.NewPlaylistTrack(0, playlists[1].ID(), tracks[0].ID(), 0),
+	}
+	if err := NewPlaylistTrackRepository(db).CreateBatch(t.Context(), entries); err != nil {
+		t.Fatalf("failed to create playlist tracks: %v", err)
+	}
+
+	t.Run("tracks match title, artist, and album prefixes", func(t *testing.T) {
+		tests := []struct {
+			query string
+			want  int
+		}{
+			{"queen", 2},
+			{"bohem", 1},
+			{"QUEEN rhapsody", 1},
+			{"opera", 1},
+			{"arrival", 1},
+			{"zeppelin", 0},
+			{`"queen -abba*`, 1},
+		}
+
+		for _, tt := range tests {
+			found, err := trackRepo.Search(t.Context(), tt.query, 0)
+			if err != nil {
+				t.Fatalf("failed to search %q: %v", tt.query, err)
+			}
+			if len(found) != tt.want {
+				t.Errorf("search %q: expected %d tracks, got %d", tt.query, tt.want, len(found))
+			}
+		}
+
+		limited, err := trackRepo.Search(t.Context(), "queen", 1)
+		if err != nil {
+			t.Fatalf("failed to search with limit: %v", err)
+		}
+		if len(limited) != 1 {
+			t.Errorf("expected limit to cap results at 1, got %d", len(limited))
+		}
+	})
+
+	t.Run("playlists match name and description", func(t *testing.T) {
+		found, err := playlistRepo.Search(t.Context(), "highway", 0)
+		if err != nil {
+			t.Fatalf("failed to search playlists: %v", err)
+		}
+		if len(found) != 1 || found[0].ID() != playlists[0].ID() {
+			t.Errorf("expected description match on %s, got %d playlists", playlists[0].ID(), len(found))
+		}
+	})
+
+	t.Run("rejects empty queries", func(t *testing.T) {
+		if _, err := trackRepo.Search(t.Context(), " -*- ", 0); !errors.Is(err, shared.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("index follows updates and deletes", func(t *testing.T) {
+		updated := models.NewPersistedTrack(tracks[2].Sequence(), "spotify", "sp3", models.Track{Title: "Let It Be", Artists: []string{"The Beatles"}})
+		updated.SetID(tracks[2].ID())
+		if err := trackRepo.Update(t.Context(), updated); err != nil {
+			t.Fatalf("failed to update track: %v", err)
+		}
+
+		if found, _ := trackRepo.Search(t.Context(), "jude", 0); len(found) != 0 {
+			t.Errorf("expected old title to be removed from the index, got %d tracks", len(found))
+		}
+		if found, _ := trackRepo.Search(t.Context(), "let it be", 0); len(found) != 1 {
+			t.Errorf("expected new title to be indexed, got %d tracks", len(found))
+		}
+
+		if err := trackRepo.Delete(t.Context(), tracks[1].ID()); err != nil {
+			t.Fatalf("failed to delete track: %v", err)
+		}
+		if found, _ := trackRepo.Search(t.Context(), "queen", 0); len(found) != 1 {
+			t.Errorf("expected soft-deleted track to be excluded, got %d tracks", len(found))
+		}
+	})
+
+	t.Run("lists playlists containing a track", func(t *testing.T) {
+		containing, err := playlistRepo.ListByTrack(t.Context(), tracks[0].ID())
+		if err != nil {
+			t.Fatalf("failed to list playlists by track: %v", err)
+		}
+		if len(containing) != 2 {
+			t.Fatalf("expected 2 playlists, got %d", len(containing))
+		}
+
+		if err := playlistRepo.Delete(t.Context(), playlists[1].ID()); err != nil {
+			t.Fatalf("failed to delete playlist: %v", err)
+		}
+		containing, err = playlistRepo.ListByTrack(t.Context(), tracks[0].ID())
+		if err != nil {
+			t.Fatalf("failed to list playlists by track: %v", err)
+		}
+		if len(containing) != 1 || containing[0].ID() != playlists[0].ID() {
+			t.Errorf("expected only the live playlist, got %d", len(containing))
+		}
+	})
+}
+
+func TestMigrationHistoryAdapter(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	user := models.NewUser(0, "test@example.com", "Test User")
+	if err := NewUserRepository(db).Create(t.Context(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	migrations := NewMigrationRepository(db)
+	history := NewMigrationHistoryAdapter(user.ID(), NewPlaylistRepository(db), migrations)
+
+	record := models.TransferRecord{
+		SourceService: "spotify", SourcePlaylist: models.Playlist{ID: "sp1", Name: "Mix"},
+		TargetService: "youtube",
+		TracksTotal:   3,
+		StartedAt:     time.Now(),
+	}
+
+	id, err := history.StartTransfer(t.Context(), record)
+	if err != nil {
+		t.Fatalf("failed to start transfer: %v", err)
+	}
+
+	running, err := migrations.Get(t.Context(), id)
+	if err != nil {
+		t.Fatalf("failed to get running migration: %v", err)
+	}
+	if running.Status() != "in_progress" || running.ProgressCurrent() != 0 || running.ProgressTotal() != 3 {
+		t.Errorf("expected in_progress at 0/3, got %s at %d/%d", running.Status(), running.ProgressCurrent(), running.ProgressTotal())
+	}
+
+	if err := history.RecordProgress(t.Context(), id, 2, 3); err != nil {
+		t.Fatalf("failed to record progress: %v", err)
+	}
+
+	running, err = migrations.Get(t.Context(), id)
+	if err != nil {
+		t.Fatalf("failed to get running migration: %v", err)
+	}
+	if running.ProgressCurrent() != 2 || running.LastProgressAt() == nil {
+		t.Errorf("expected progress 2 with a timestamp, got %d at %v", running.ProgressCurrent(), running.LastProgressAt())
+	}
+	if got := running.ProgressPercent(); got < 66 || got > 67 {
+		t.Errorf("expected ~66.7%% complete, got %.1f", got)
+	}
+
+	if err := migrations.UpdateProgress(t.Context(), id, -1, 3); !errors.Is(err, shared.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for negative progress, got %v", err)
+	}
+
+	record.TargetPlaylist = &models.Playlist{ID: "yt1", Name: "Mix"}
+	record.TracksMigrated = 2
+	record.Failures = []models.Track{{Title: "Missing", Artists: []string{"Band"}}}
+	record.CompletedAt = time.Now()
+	if err := history.FinishTransfer(t.Context(), id, record); err != nil {
+		t.Fatalf("failed to finish transfer: %v", err)
+	}
+
+	finished, err := migrations.Get(t.Context(), id)
+	if err != nil {
+		t.Fatalf("failed to get finished migration: %v", err)
+	}
+	if finished.Status() != "completed" || finished.TargetPlaylistID() == "" || finished.TracksFailed() != 1 {
+		t.Errorf("unexpected finished migration: status %s, target %q, failed %d", finished.Status(), finished.TargetPlaylistID(), finished.TracksFailed())
+	}
+	if finished.ProgressCurrent() != 2 {
+		t.Errorf("expected finishing to keep recorded progress, got %d", finished.ProgressCurrent())
+	}
+}
+
+func TestCollectAnalytics(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	empty, err := CollectAnalytics(t.Context(), db, AnalyticsOpts{})
+	if err != nil {
+		t.Fatalf("failed to collect empty analytics: %v", err)
+	}
+	if empty.Migrations != 0 || empty.MatchRate != 0 || len(empty.MatchRateByDay) != 0 {
+		t.Errorf("expected empty analytics, got %+v", empty)
+	}
+
+	user := models.NewUser(0, "test@example.com", "Test User")
+	if err := NewUserRepository(db).Create(t.Context(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	migrations := NewMigrationRepository(db)
+	history := NewMigrationHistoryAdapter(user.ID(), NewPlaylistRepository(db), migrations)
+	started := time.Now().Add(-time.Minute)
+
+	records := []models.TransferRecord{
+		{
+			SourceService: "spotify", SourcePlaylist: models.Playlist{ID: "sp1", Name: "Mix"},
+			TargetService: "youtube", TargetPlaylist: &models.Playlist{ID: "yt1", Name: "Mix"},
+			TracksTotal: 4, TracksMigrated: 2,
+			Failures:  []models.Track{{Title: "A", Artists: []string{"Band"}}, {Title: "B", Artists: []string{"Band"}}},
+			StartedAt: started, CompletedAt: started.Add(10 * time.Second),
+		},
+		{
+			SourceService: "spotify", SourcePlaylist: models.Playlist{ID: "sp1", Name: "Mix"},
+			TargetService: "youtube",
+			TracksTotal:   1,
+			Failures:      []models.Track{{Title: "C", Artists: []string{"Solo"}}},
+			StartedAt:     started, CompletedAt: started.Add(30 * time.Second),
+			Error: "no tracks were matched",
+		},
+	}
+	for _, record := range records {
+		id, err := history.StartTransfer(t.Context(), record)
+		if err != nil {
+			t.Fatalf("failed to start transfer: %v", err)
+		}
+		if err := history.FinishTransfer(t.Context(), id, record); err != nil {
+			t.Fatalf("failed to finish transfer: %v", err)
+		}
+	}
+
+	tracks := []*models.PersistedTrack{
+		models.NewPersistedTrack(0, "spotify", "t1", models.Track{Title: "Song", Artists: []string{"Band"}}),
+		models.NewPersistedTrack(0, "youtube", "t2", models.Track{Title: "Song", Artists: []string{"Band"}}),
+	}
+	if err := NewTrackRepository(db).CreateBatch(t.Context(), tracks); err != nil {
+		t.Fatalf("failed to create tracks: %v", err)
+	}
+
+	analytics, err := CollectAnalytics(t.Context(), db, AnalyticsOpts{Days: 7, TopArtists: 1})
+	if err != nil {
+		t.Fatalf("failed to collect analytics: %v", err)
+	}
+
+	if analytics.Migrations != 2 || analytics.Completed != 1 || analytics.Failed != 1 {
+		t.Errorf("expected 2 migrations (1 completed, 1 failed), got %+v", analytics)
+	}
+	if analytics.TracksTotal != 5 || analytics.TracksMigrated != 2 || analytics.TracksFailed != 3 {
+		t.Errorf("unexpected track totals: %d/%d, %d failed", analytics.TracksMigrated, analytics.TracksTotal, analytics.TracksFailed)
+	}
+	if analytics.MatchRate != 40 {
+		t.Errorf("expected 40%% match rate, got %.1f", analytics.MatchRate)
+	}
+	if analytics.AverageDuration != 20 {
+		t.Errorf("expected 20s average duration, got %.1f", analytics.AverageDuration)
+	}
+	if len(analytics.MatchRateByDay) != 1 || analytics.MatchRateByDay[0].Date != started.Format("2006-01-02") {
+		t.Errorf("expected a single day in the trend, got %+v", analytics.MatchRateByDay)
+	}
+	if len(analytics.MostFailedArtists) != 1 || analytics.MostFailedArtists[0] != (ArtistFailures{Artist: "Band", Failures: 2}) {
+		t.Errorf("expected Band to top the failures, got %+v", analytics.MostFailedArtists)
+	}
+
+	if len(analytics.Services) != 2 {
+		t.Fatalf("expected 2 services, got %+v", analytics.Services)
+	}
+	if spotify := analytics.Services[0]; spotify.Service != "spotify" || spotify.Tracks != 1 || spotify.MigrationsFrom != 2 {
+		t.Errorf("unexpected spotify counts: %+v", spotify)
+	}
+	if youtube := analytics.Services[1]; youtube.Service != "youtube" || youtube.Tracks != 1 || youtube.MigrationsTo != 2 {
+		t.Errorf("unexpected youtube counts: %+v", youtube)
+	}
+
+	t.Run("purging migrations removes their failures", func(t *testing.T) {
+		jobs, err := migrations.List(t.Context(), map[string]any{}, models.ListOpts{})
+		if err != nil {
+			t.Fatalf("failed to list migrations: %v", err)
+		}
+		for _, job := range jobs {
+			if err := migrations.Delete(t.Context(), job.ID()); err != nil {
+				t.Fatalf("failed to delete migration: %v", err)
+			}
+		}
+
+		if _, err := migrations.PurgeDeleted(t.Context(), 0); err != nil {
+			t.Fatalf("failed to purge migrations: %v", err)
+		}
+
+		for _, job := range jobs {
+			failures, err := migrations.ListFailures(t.Context(), job.ID())
+			if err != nil {
+				t.Fatalf("failed to list failures: %v", err)
+			}
+			if len(failures) != 0 {
+				t.Errorf("expected failures of %s to be purged, got %d", job.ID(), len(failures))
+			}
+		}
+	})
+}
+
+func TestTrackRepository_Artists(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTrackRepository(db)
+	duet := models.Track{Title: "Under Pressure", Artists: []string{"Queen", "David Bowie"}}
+	if err := repo.Upsert(t.Context(), models.NewPersistedTrack(0, "spotify", "sp1", duet)); err != nil {
+		t.Fatalf("failed to upsert track: %v", err)
+	}
+
+	retrieved, err := repo.GetByServiceID(t.Context(), "spotify", "sp1")
+	if err != nil {
+		t.Fatalf("failed to get track: %v", err)
+	}
+	if got := retrieved.ToTrack(); !slices.Equal(got.Artists, duet.Artists) || got.Artist() != "Queen" {
+		t.Errorf("expected every artist kept, got %q", got.Artists)
+	}
+
+	solo := models.NewPersistedTrack(0, "spotify", "sp1", models.Track{Title: "Under Pressure", Artists: []string{"Queen"}})
+	if err := repo.Upsert(t.Context(), solo); err != nil {
+		t.Fatalf("failed to upsert track: %v", err)
+	}
+	retrieved, err = repo.GetByServiceID(t.Context(), "spotify", "sp1")
+	if err != nil {
+		t.Fatalf("failed to get track: %v", err)
+	}
+	if got := retrieved.Artists(); !slices.Equal(got, []string{"Queen"}) {
+		t.Errorf("expected the refreshed credit, got %q", got)
+	}
+}
+
+func TestSearchCacheRepository(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewSearchCacheRepository(db, time.Hour)
+
+	query := models.Track{ID: "sp1", Title: "Song One", Artists: []string{"Artist"}, ISRC: "USABC1234567"}
+	match := models.Track{ID: "yt1", Title: "Song One", Artists: []string{"Artist", "Guest"}, Duration: 200}
+
+	if _, err := repo.Lookup(t.Context(), query); !errors.Is(err, shared.ErrTrackNotFound) {
+		t.Fatalf("expected ErrTrackNotFound before storing, got %v", err)
+	}
+
+	if err := repo.Store(t.Context(), query, match); err != nil {
+		t.Fatalf("failed to store search: %v", err)
+	}
+
+	cached, err := repo.Lookup(t.Context(), models.Track{Title: "  song one ", Artists: []string{"ARTIST"}})
+	if err != nil {
+		t.Fatalf("failed to look up by normalized key: %v", err)
+	}
+	if cached.ID != "yt1" || cached.Duration != 200 || !slices.Equal(cached.Artists, match.Artists) {
+		t.Errorf("unexpected cached match: %+v", cached)
+	}
+
+	byISRC, err := repo.Lookup(t.Context(), models.Track{Title: "Song One (Remastered)", Artists: []string{"Artist"}, ISRC: "USABC1234567"})
+	if err != nil {
+		t.Fatalf("failed to look up by ISRC: %v", err)
+	}
+	if byISRC.ID != "yt1" {
+		t.Errorf("expected ISRC match yt1, got %s", byISRC.ID)
+	}
+
+	if err := repo.Store(t.Context(), query, models.Track{ID: "yt2", Title: "Song One", Artists: []string{"Artist"}}); err != nil {
+		t.Fatalf("failed to refresh search: %v", err)
+	}
+	refreshed, err := repo.Lookup(t.Context(), query)
+	if err != nil || refreshed.ID != "yt2" {
+		t.Errorf("expected refreshed match yt2, got %+v (err %v)", refreshed, err)
+	}
+
+	if _, err := db.ExecContext(t.Context(), "UPDATE search_cache SET fetched_at = ?", time.Now().UTC().Add(-2*time.Hour)); err != nil {
+		t.Fatalf("failed to age cache entry: %v", err)
+	}
+	if _, err := repo.Lookup(t.Context(), query); !errors.Is(err, shared.ErrTrackNotFound) {
+		t.Errorf("expected expired entry to miss, got %v", err)
+	}
+
+	removed, err := repo.PurgeExpired(t.Context())
+	if err != nil {
+		t.Fatalf("failed to purge search cache: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 purged entry, got %d", removed)
+	}
+}
+
+func TestIgnoreListRepository(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewIgnoreListRepository(db)
+
+	entries := []*models.IgnoreEntry{
+		{Kind: models.IgnoreKindISRC, Value: " usabc1234567 ", Reason: "local file"},
+		{Kind: models.IgnoreKindArtist, Value: "Some  Podcast"},
+	}
+	for _, entry := range entries {
+		if err := repo.Add(t.Context(), entry); err != nil {
+			t.Fatalf("failed to add ignore entry: %v", err)
+		}
+		if entry.ID == "" {
+			t.Error("ignore entry ID should be set after adding")
+		}
+	}
+
+	for _, invalid := range []*models.IgnoreEntry{{Kind: "album", Value: "X"}, {Kind: models.IgnoreKindArtist, Value: "  "}} {
+		if err := repo.Add(t.Context(), invalid); !errors.Is(err, shared.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput for %+v, got %v", invalid, err)
+		}
+	}
+
+	readd := &models.IgnoreEntry{Kind: models.IgnoreKindISRC, Value: "USABC1234567", Reason: "podcast"}
+	if err := repo.Add(t.Context(), readd); err != nil {
+		t.Fatalf("failed to re-add ignore entry: %v", err)
+	}
+	if readd.ID != entries[0].ID {
+		t.Errorf("expected re-adding to keep ID %s, got %s", entries[0].ID, readd.ID)
+	}
+
+	list, err := repo.List(t.Context())
+	if err != nil {
+		t.Fatalf("failed to list ignore entries: %v", err)
+	}
+	if len(list) != 2 || list[0].Kind != models.IgnoreKindArtist || list[1].Reason != "podcast" {
+		t.Errorf("unexpected ignore list: %+v, %+v", list[0], list[1])
+	}
+
+	tests := []struct {
+		name  string
+		track models.Track
+		want  bool
+	}{
+		{"isrc", models.Track{Title: "Song", Artists: []string{"Artist"}, ISRC: "USABC1234567"}, true},
+		{"artist ignores case", models.Track{Title: "Episode 1", Artists: []string{"some podcast"}}, true},
+		{"credited artist", models.Track{Title: "Crossover", Artists: []string{"Artist, Some Podcast"}}, true},
+		{"not ignored", models.Track{Title: "Song", Artists: []string{"Artist"}, ISRC: "USXYZ7654321"}, false},
+		{"no metadata", models.Track{Title: "Song"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Ignores(t.Context(), tt.track)
+			if err != nil || got != tt.want {
+				t.Errorf("Ignores() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	if err := repo.Remove(t.Context(), models.IgnoreKindArtist, "SOME PODCAST"); err != nil {
+		t.Fatalf("failed to remove ignore entry: %v", err)
+	}
+	if err := repo.Remove(t.Context(), models.IgnoreKindArtist, "Some Podcast"); !errors.Is(err, shared.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound for a removed entry, got %v", err)
+	}
+	if ignored, _ := repo.Ignores(t.Context(), models.Track{Artists: []string{"Some Podcast"}}); ignored {
+		t.Error("expected removed artist to no longer be ignored")
+	}
+}
+
+func TestCredentialRepository(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	users := NewUserRepository(db)
+	user := models.NewUser(0, "listener@example.com", "Listener")
+	if err := users.Create(t.Context(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	t.Run("looks up users and passwords", func(t *testing.T) {
+		found, err := users.GetByEmail(t.Context(), "listener@example.com")
+		if err != nil || found.ID() != user.ID() {
+			t.Fatalf("GetByEmail() = %v, %v", found, err)
+		}
+		if _, err := users.GetByEmail(t.Context(), "nobody@example.com"); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound for an unknown email, got %v", err)
+		}
+
+		if hash, err := users.PasswordHash(t.Context(), user.ID()); err != nil || hash != "" {
+			t.Errorf("expected no password yet, got %q, %v", hash, err)
+		}
+		if err := users.SetPasswordHash(t.Context(), user.ID(), "hash"); err != nil {
+			t.Fatalf("SetPasswordHash() error = %v", err)
+		}
+		if hash, err := users.PasswordHash(t.Context(), user.ID()); err != nil || hash != "hash" {
+			t.Errorf("PasswordHash() = %q, %v", hash, err)
+		}
+		if err := users.SetPasswordHash(t.Context(), "missing", "hash"); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound for an unknown user, got %v", err)
+		}
+
+		registered := models.NewUser(0, "registered@example.com", "")
+		if err := users.CreateWithPassword(t.Context(), registered, "registered-hash"); err != nil {
+			t.Fatalf("CreateWithPassword() error = %v", err)
+		}
+		if hash, err := users.PasswordHash(t.Context(), registered.ID()); err != nil || hash != "registered-hash" {
+			t.Errorf("PasswordHash() = %q, %v", hash, err)
+		}
+		if err := users.CreateWithPassword(t.Context(), models.NewUser(0, "", ""), "hash"); err == nil {
+			t.Error("expected a user without an email to be refused")
+		}
+	})
+
+	secret := []byte("a session secret of at least 32 bytes")
+	repo, err := NewCredentialRepository(db, secret)
+	if err != nil {
+		t.Fatalf("NewCredentialRepository() error = %v", err)
+	}
+
+	t.Run("stores tokens and auth headers per user", func(t *testing.T) {
+
+		if _, err := repo.Token(t.Context(), user.ID(), "spotify"); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound before connecting, got %v", err)
+		}
+		for _, access := range []string{"first", "second"} {
+			if err := repo.SaveToken(t.Context(), user.ID(), "spotify", &oauth2.Token{AccessToken: access, RefreshToken: "refresh"}); err != nil {
+				t.Fatalf("SaveToken() error = %v", err)
+			}
+		}
+		token, err := repo.Token(t.Context(), user.ID(), "spotify")
+		if err != nil || token.AccessToken != "second" || token.RefreshToken != "refresh" {
+			t.Errorf("Token() = %+v, %v", token, err)
+		}
+
+		if err := repo.SaveAuthHeaders(t.Context(), user.ID(), "youtube", []byte(`{"cookie":"SID=1"}`)); err != nil {
+			t.Fatalf("SaveAuthHeaders() error = %v", err)
+		}
+		if headers, err := repo.AuthHeaders(t.Context(), user.ID(), "youtube"); err != nil || string(headers) != `{"cookie":"SID=1"}` {
+			t.Errorf("AuthHeaders() = %q, %v", headers, err)
+		}
+		if _, err := repo.AuthHeaders(t.Context(), user.ID(), "spotify"); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected a service with only a token to have no auth headers, got %v", err)
+		}
+
+		services, err := repo.Services(t.Context(), user.ID())
+		if err != nil || fmt.Sprint(services) != "[spotify youtube]" {
+			t.Errorf("Services() = %v, %v", services, err)
+		}
+
+		if err := repo.Delete(t.Context(), user.ID(), "spotify"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := repo.Delete(t.Context(), user.ID(), "spotify"); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound for removed credentials, got %v", err)
+		}
+	})
+
+	t.Run("encrypts values for their own user", func(t *testing.T) {
+		var stored string
+		if err := db.QueryRow("SELECT auth_headers FROM user_credentials WHERE user_id = ?", user.ID()).Scan(&stored); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(stored, "SID=1") {
+			t.Errorf("expected auth headers to be stored encrypted, got %q", stored)
+		}
+
+		other := models.NewUser(0, "other@example.com", "Other")
+		if err := users.Create(t.Context(), other); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		if _, err := repo.AuthHeaders(t.Context(), other.ID(), "youtube"); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected another user to have no auth headers, got %v", err)
+		}
+		if _, err := db.Exec("INSERT INTO user_credentials (user_id, service, auth_headers) VALUES (?, 'youtube', ?)", other.ID(), stored); err != nil {
+			t.Fatal(err)
+		}
+		if headers, err := repo.AuthHeaders(t.Context(), other.ID(), "youtube"); !errors.Is(err, shared.ErrSecretStore) {
+			t.Errorf("expected headers copied from another user to be unreadable, got %q, %v", headers, err)
+		}
+
+		rotated, err := NewCredentialRepository(db, []byte("another session secret, 32 bytes or more"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rotated.AuthHeaders(t.Context(), user.ID(), "youtube"); !errors.Is(err, shared.ErrSecretStore) {
+			t.Errorf("expected headers to be unreadable with another secret, got %v", err)
+		}
+	})
+}
+
+func TestTrackRepository_ISRC(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewTrackRepository(db)
+	adapter := NewTrackCacheAdapter(repo)
+	song := models.Track{Title: "Around the World", Artists: []string{"Daft Punk"}, ISRC: "GBDUW0000053"}
+	for _, variant := range []struct{ service, id string }{{"youtube", "yt1"}, {"spotify", "sp1"}, {"youtube", "yt2"}} {
+		song.ID = variant.id
+		if err := adapter.CacheTrack(t.Context(), variant.service, variant.id, song); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tracks, err := repo.ListByISRC(t.Context(), "GBDUW0000053")
+	if err != nil {
+		t.Fatalf("ListByISRC() error = %v", err)
+	}
+	var got []string
+	for _, track := range tracks {
+		got = append(got, track.Service()+":"+track.ServiceID())
+	}
+	if strings.Join(got, ",") != "spotify:sp1,youtube:yt1,youtube:yt2" {
+		t.Errorf("expected every variant by service, got %v", got)
+	}
+
+	counterpart, err := adapter.FindCounterpart(t.Context(), "youtube", "GBDUW0000053")
+	if err != nil || counterpart.ID != "yt1" || counterpart.Title != "Around the World" {
+		t.Errorf("expected the first cached YouTube variant, got %+v (%v)", counterpart, err)
+	}
+	if _, err := repo.FindCounterpart(t.Context(), "youtube", "USUM71703861"); !errors.Is(err, shared.ErrRecordNotFound) {
+		t.Errorf("expected an unknown ISRC to be not found, got %v", err)
+	}
+	if _, err := repo.FindCounterpart(t.Context(), "spotify", ""); !errors.Is(err, shared.ErrRecordNotFound) {
+		t.Errorf("expected a blank ISRC to be not found, got %v", err)
+	}
+}
+
+func TestPlaylistRepository_CreateAndGet(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	userRepo := NewUserRepository(db)
+	user := models.NewUser(0, "test@example.com", "Test User")
+	if err := userRepo.Create(t.Context(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	// Create playlist
+	playlistRepo := NewPlaylistRepository(db)
+	playlistDTO := models.Playlist{
+		ID:          "spotify123",
+		Name:        "Test Playlist",
+		Description: "Test Description",
+		TrackCount:  10,
+		Public:      true,
+	}
+
+	playlist := models.NewPersistedPlaylist(0, "spotify", "spotify123", user.ID(), playlistDTO)
+
+	if err := playlistRepo.Create(t.Context(), playlist); err != nil {
+		t.Fatalf("failed to create playlist: %v", err)
+	}
+
+	retrieved, err := playlistRepo.GetByServiceID(t.Context(), user.ID(), "spotify", "spotify123")
+	if err != nil {
+		t.Fatalf("failed to get playlist: %v", err)
+	}
+
+	if retrieved.Name() != "Test Playlist" {
+		t.Errorf("expected name 'Test Playlist', got %s", retrieved.Name())
+	}
+
+	if retrieved.UserID() != user.ID() {
+		t.Errorf("expected user ID %s, got %s", user.ID(), retrieved.UserID())
+	}
+
+	playlistDTO.Name = "Renamed Playlist"
+	playlistDTO.TrackCount = 12
+	renamed := models.NewPersistedPlaylist(0, "spotify", "spotify123", user.ID(), playlistDTO)
+	if err := playlistRepo.Upsert(t.Context(), renamed); err != nil {
+		t.Fatalf("failed to upsert playlist: %v", err)
+	}
+	if renamed.ID() != playlist.ID() {
+		t.Errorf("expected the upsert to keep ID %s, got %s", playlist.ID(), renamed.ID())
+	}
+	retrieved, err = playlistRepo.Get(t.Context(), playlist.ID())
+	if err != nil || retrieved.Name() != "Renamed Playlist" || retrieved.TrackCount() != 12 {
+		t.Errorf("expected the refreshed playlist, got %+v (%v)", retrieved, err)
 	}
 }
 
@@ -300,7 +1719,7 @@ func TestMigrationRepository_CreateAndUpdate(t *testing.T) {
 
 	userRepo := NewUserRepository(db)
 	user := models.NewUser(0, "test@example.com", "Test User")
-	if err := userRepo.Create(user); err != nil {
+	if err := userRepo.Create(t.Context(), user); err != nil {
 		t.Fatalf("failed to create user: %v", err)
 	}
 
@@ -312,14 +1731,14 @@ func TestMigrationRepository_CreateAndUpdate(t *testing.T) {
 		TrackCount:  10,
 		Public:      false,
 	})
-	if err := playlistRepo.Create(sourcePlaylist); err != nil {
+	if err := playlistRepo.Create(t.Context(), sourcePlaylist); err != nil {
 		t.Fatalf("failed to create source playlist: %v", err)
 	}
 
 	migrationRepo := NewMigrationRepository(db)
 	migration := models.NewMigrationJob(0, user.ID(), "spotify", sourcePlaylist.ID(), "youtube")
 
-	if err := migrationRepo.Create(migration); err != nil {
+	if err := migrationRepo.Create(t.Context(), migration); err != nil {
 		t.Fatalf("failed to create migration: %v", err)
 	}
 
@@ -331,11 +1750,11 @@ func TestMigrationRepository_CreateAndUpdate(t *testing.T) {
 	migration.SetTracksTotal(10)
 	migration.SetTracksMigrated(5)
 
-	if err := migrationRepo.Update(migration); err != nil {
+	if err := migrationRepo.Update(t.Context(), migration); err != nil {
 		t.Fatalf("failed to update migration: %v", err)
 	}
 
-	retrieved, err := migrationRepo.Get(migration.ID())
+	retrieved, err := migrationRepo.Get(t.Context(), migration.ID())
 	if err != nil {
 		t.Fatalf("failed to get migration: %v", err)
 	}
@@ -357,7 +1776,7 @@ func TestNextSequence(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
-	seq1, err := NextSequence(db, "users")
+	seq1, err := NextSequence(t.Context(), db, "users")
 	if err != nil {
 		t.Fatalf("failed to get first sequence: %v", err)
 	}
@@ -367,7 +1786,7 @@ func TestNextSequence(t *testing.T) {
 	}
 
 	// Get second sequence
-	seq2, err := NextSequence(db, "users")
+	seq2, err := NextSequence(t.Context(), db, "users")
 	if err != nil {
 		t.Fatalf("failed to get second sequence: %v", err)
 	}
@@ -376,7 +1795,7 @@ func TestNextSequence(t *testing.T) {
 		t.Errorf("expected second sequence to be 2, got %d", seq2)
 	}
 
-	trackSeq, err := NextSequence(db, "tracks")
+	trackSeq, err := NextSequence(t.Context(), db, "tracks")
 	if err != nil {
 		t.Fatalf("failed to get track sequence: %v", err)
 	}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// SearchCacheRepository stores the best YouTube Music match for a track search.
+//
+// Entries are keyed by the normalized title/artist of the query and, when known, the query's ISRC.
+// Entries older than the TTL are treated as misses. Unlike the other repositories, cache entries
+// are hard-deleted: they are cheap to rebuild and carry no history worth restoring.
+type SearchCacheRepository struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// NewSearchCacheRepository creates a new SearchCacheRepository whose entries expire after ttl
+func NewSearchCacheRepository(db *sql.DB, ttl time.Duration) *SearchCacheRepository {
+	return &SearchCacheRepository{db: db, ttl: ttl}
+}
+
+// Lookup returns the cached match for a query track that was fetched within the TTL.
+//
+// A matching ISRC takes precedence over the normalized title/artist key.
+// Returns [shared.ErrTrackNotFound] when there is no fresh entry.
+func (r *SearchCacheRepository) Lookup(ctx context.Context, query models.Track) (*models.Track, error) {
+	cutoff := time.Now().UTC().Add(-r.ttl)
+
+	if query.ISRC != "" {
+		match, err := r.scanMatch(r.db.QueryRowContext(ctx, `
+			SELECT service_id, title, artist, artists, album, duration, isrc
+			FROM search_cache
+			WHERE query_isrc = ? AND fetched_at >= ?
+			ORDER BY fetched_at DESC
+			LIMIT 1
+		`, query.ISRC, cutoff))
+		if err == nil {
+			return match, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to look up cached search: %w", err)
+		}
+	}
+
+	match, err := r.scanMatch(r.db.QueryRowContext(ctx, `
+		SELECT service_id, title, artist, artists, album, duration, isrc
+		FROM search_cache
+		WHERE query_key = ? AND fetched_at >= ?
+	`, shared.NormalizeTrackKey(query.Title, query.Artist()), cutoff))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: no cached search for %s - %s", shared.ErrTrackNotFound, query.Title, query.Artist())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up cached search: %w", err)
+	}
+
+	return match, nil
+}
+
+// Store records match as the best result for the query track, replacing any previous entry
+func (r *SearchCacheRepository) Store(ctx context.Context, query, match models.Track) error {
+	if match.ID == "" {
+		return fmt.Errorf("validation failed: match ID is required")
+	}
+
+	var queryISRC any = query.ISRC
+	if query.ISRC == "" {
+		queryISRC = nil
+	}
+	artists, err := encodeArtists(match.Artists)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO search_cache (id, query_key, query_isrc, service, service_id, title, artist, artists, album, duration, isrc, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(query_key) DO UPDATE SET
+			query_isrc = excluded.query_isrc,
+			service_id = excluded.service_id,
+			title = excluded.title,
+			artist = excluded.artist,
+			artists = excluded.artists,
+			album = excluded.album,
+			duration = excluded.duration,
+			isrc = excluded.isrc,
+			fetched_at = excluded.fetched_at
+	`,
+		shared.GenerateID(),
+		shared.NormalizeTrackKey(query.Title, query.Artist()),
+		queryISRC,
+		"youtube",
+		match.ID,
+		match.Title,
+		match.Artist(),
+		artists,
+		match.Album,
+		match.Duration,
+		match.ISRC,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store cached search: %w", err)
+	}
+
+	return nil
+}
+
+// Purge permanently removes entries fetched more than olderThan ago, or every entry when olderThan is zero.
+//
+// Returns the number of entries removed.
+func (r *SearchCacheRepository) Purge(ctx context.Context, olderThan time.Duration) (int64, error) {
+	query := "DELETE FROM search_cache"
+	args := []any{}
+
+	if olderThan > 0 {
+		query += " WHERE fetched_at < ?"
+		args = append(args, time.Now().UTC().Add(-olderThan))
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge search cache: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// PurgeExpired permanently removes entries older than the repository's TTL
+func (r *SearchCacheRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	return r.Purge(ctx, r.ttl)
+}
+
+// scanMatch scans a single [sql.Row] into a [models.Track], passing through [sql.ErrNoRows]
+func (r *SearchCacheRepository) scanMatch(row *sql.Row) (*models.Track, error) {
+	var (
+		serviceID string
+		title     string
+		artist    sql.NullString
+		artists   sql.NullString
+		album     sql.NullString
+		duration  int
+		isrc      sql.NullString
+	)
+
+	if err := row.Scan(&serviceID, &title, &artist, &artists, &album, &duration, &isrc); err != nil {
+		return nil, err
+	}
+
+	match := &models.Track{
+		ID:       serviceID,
+		Title:    title,
+		Album:    album.String,
+		Duration: duration,
+		ISRC:     isrc.String,
+	}
+	if err := decodeArtists(match, artist.String, artists); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -23,8 +25,8 @@ func NewTrackRepository(db *sql.DB) *TrackRepository {
 }
 
 // Create inserts a new [models.PersistedTrack] into the database with generated ID and sequence
-func (r *TrackRepository) Create(track *models.PersistedTrack) error {
-	sequence, err := NextSequence(r.db, "tracks")
+func (r *TrackRepository) Create(ctx context.Context, track *models.PersistedTrack) error {
+	sequence, err := NextSequence(ctx, r.db, "tracks")
 	if err != nil {
 		return fmt.Errorf("failed to generate sequence: %w", err)
 	}
@@ -35,22 +37,29 @@ func (r *TrackRepository) Create(track *models.PersistedTrack) error {
 	if err := track.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	artists, genres, features, err := encodeJSONColumns(track)
+	if err != nil {
+		return err
+	}
 
 	query := `
-		INSERT INTO tracks (id, sequence, service, service_id, title, artist, album, duration, isrc, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO tracks (id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		id,
 		sequence,
 		track.Service(),
 		track.ServiceID(),
 		track.Title(),
 		track.Artist(),
+		artists,
 		track.Album(),
 		track.Duration(),
 		track.ISRC(),
+		genres,
+		features,
 		track.CreatedAt(),
 		track.UpdatedAt(),
 	)
@@ -62,60 +71,126 @@ func (r *TrackRepository) Create(track *models.PersistedTrack) error {
 }
 
 // Get retrieves a track by ID, excluding soft-deleted tracks
-func (r *TrackRepository) Get(id string) (*models.PersistedTrack, error) {
+func (r *TrackRepository) Get(ctx context.Context, id string) (*models.PersistedTrack, error) {
 	query := `
-		SELECT id, sequence, service, service_id, title, artist, album, duration, isrc, created_at, updated_at, deleted_at
+		SELECT id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at, deleted_at
 		FROM tracks
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	return r.scanOne(r.db.QueryRow(query, id))
+	return r.scanOne(r.db.QueryRowContext(ctx, query, id))
 }
 
 // GetByServiceID retrieves a track by service and service_id
-func (r *TrackRepository) GetByServiceID(service, serviceID string) (*models.PersistedTrack, error) {
+func (r *TrackRepository) GetByServiceID(ctx context.Context, service, serviceID string) (*models.PersistedTrack, error) {
 	query := `
-		SELECT id, sequence, service, service_id, title, artist, album, duration, isrc, created_at, updated_at, deleted_at
+		SELECT id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at, deleted_at
 		FROM tracks
 		WHERE service = ? AND service_id = ? AND deleted_at IS NULL
 	`
 
-	return r.scanOne(r.db.QueryRow(query, service, serviceID))
+	return r.scanOne(r.db.QueryRowContext(ctx, query, service, serviceID))
+}
+
+// GetByISRC retrieves a track by ISRC code across any service.
+//
+// An ISRC is usually cached once per service; use [TrackRepository.ListByISRC] for every variant or
+// [TrackRepository.FindCounterpart] for a specific service's.
+func (r *TrackRepository) GetByISRC(ctx context.Context, isrc string) (*models.PersistedTrack, error) {
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at, deleted_at
+		FROM tracks
+		WHERE isrc = ? AND deleted_at IS NULL
+		ORDER BY sequence ASC
+		LIMIT 1
+	`
+
+	return r.scanOne(r.db.QueryRowContext(ctx, query, isrc))
 }
 
-// GetByISRC retrieves a track by ISRC code across any service
-func (r *TrackRepository) GetByISRC(isrc string) (*models.PersistedTrack, error) {
+// ListByISRC retrieves every cached track with the ISRC code, one or more per service, ordered by service
+// and then by when they were cached. Soft-deleted tracks are excluded.
+func (r *TrackRepository) ListByISRC(ctx context.Context, isrc string) ([]*models.PersistedTrack, error) {
+	if isrc == "" {
+		return nil, nil
+	}
+
 	query := `
-		SELECT id, sequence, service, service_id, title, artist, album, duration, isrc, created_at, updated_at, deleted_at
+		SELECT id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at, deleted_at
 		FROM tracks
 		WHERE isrc = ? AND deleted_at IS NULL
+		ORDER BY service ASC, sequence ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, isrc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracks by ISRC: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []*models.PersistedTrack
+	for rows.Next() {
+		track, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// FindCounterpart retrieves the cached track of service with the ISRC code, translating a track from another
+// service without searching. When service has several, the earliest cached is returned.
+// Returns [shared.ErrRecordNotFound] when service has no cached track with the ISRC.
+func (r *TrackRepository) FindCounterpart(ctx context.Context, service, isrc string) (*models.PersistedTrack, error) {
+	if isrc == "" {
+		return nil, fmt.Errorf("%w: no ISRC to look up", shared.ErrRecordNotFound)
+	}
+
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at, deleted_at
+		FROM tracks
+		WHERE service = ? AND isrc = ? AND deleted_at IS NULL
+		ORDER BY sequence ASC
 		LIMIT 1
 	`
 
-	return r.scanOne(r.db.QueryRow(query, isrc))
+	return r.scanOne(r.db.QueryRowContext(ctx, query, service, isrc))
 }
 
 // Update modifies an existing track in the database
-func (r *TrackRepository) Update(track *models.PersistedTrack) error {
+func (r *TrackRepository) Update(ctx context.Context, track *models.PersistedTrack) error {
 	if err := track.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	artists, genres, features, err := encodeJSONColumns(track)
+	if err != nil {
+		return err
+	}
 
 	now := time.Now()
 	track.SetUpdatedAt(now)
 
 	query := `
 		UPDATE tracks
-		SET title = ?, artist = ?, album = ?, duration = ?, isrc = ?, updated_at = ?
+		SET title = ?, artist = ?, artists = ?, album = ?, duration = ?, isrc = ?, genres = ?, audio_features = ?, updated_at = ?
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query,
+	result, err := r.db.ExecContext(ctx, query,
 		track.Title(),
 		track.Artist(),
+		artists,
 		track.Album(),
 		track.Duration(),
 		track.ISRC(),
+		genres,
+		features,
 		now,
 		track.ID(),
 	)
@@ -135,7 +210,7 @@ func (r *TrackRepository) Update(track *models.PersistedTrack) error {
 }
 
 // Delete soft-deletes a track by ID
-func (r *TrackRepository) Delete(id string) error {
+func (r *TrackRepository) Delete(ctx context.Context, id string) error {
 	now := time.Now()
 
 	query := `
@@ -144,7 +219,7 @@ func (r *TrackRepository) Delete(id string) error {
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, now, id)
+	result, err := r.db.ExecContext(ctx, query, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete track: %w", err)
 	}
@@ -160,14 +235,106 @@ func (r *TrackRepository) Delete(id string) error {
 	return nil
 }
 
+// PurgeDeleted permanently removes tracks soft-deleted more than olderThan ago, along with any playlist entries referencing them.
+// A zero olderThan purges every soft-deleted row. Returns the number of tracks removed.
+func (r *TrackRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return purgeDeleted(ctx, r.db, "tracks", olderThan,
+		`DELETE FROM playlist_tracks WHERE track_id IN (SELECT id FROM tracks WHERE deleted_at IS NOT NULL AND deleted_at <= ?)`,
+	)
+}
+
+// Restore undoes the soft delete of a track by ID, rejecting conflicts on service+service_id
+func (r *TrackRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, r.db, "tracks", id, "service", "service_id")
+}
+
+// ListDeleted retrieves all soft-deleted tracks, most recently deleted first
+func (r *TrackRepository) ListDeleted(ctx context.Context) ([]*models.PersistedTrack, error) {
+	query := `
+		SELECT id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at, deleted_at
+		FROM tracks
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []*models.PersistedTrack
+	for rows.Next() {
+		item, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return deleted, nil
+}
+
 // List retrieves all tracks matching the given criteria, excluding soft-deleted tracks
-func (r *TrackRepository) List(criteria map[string]any) ([]*models.PersistedTrack, error) {
+func (r *TrackRepository) List(ctx context.Context, criteria map[string]any, opts models.ListOpts) ([]*models.PersistedTrack, error) {
 	query := `
-		SELECT id, sequence, service, service_id, title, artist, album, duration, isrc, created_at, updated_at, deleted_at
+		SELECT id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at, deleted_at
 		FROM tracks
 		WHERE deleted_at IS NULL
 	`
 
+	where, args := r.filter(criteria)
+	query += where
+
+	clause, pageArgs, err := listClause(opts, "sequence ASC")
+	if err != nil {
+		return nil, err
+	}
+	query += clause
+	args = append(args, pageArgs...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []*models.PersistedTrack
+	for rows.Next() {
+		track, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// Count returns the number of tracks matching the given criteria, excluding soft-deleted tracks
+func (r *TrackRepository) Count(ctx context.Context, criteria map[string]any) (int, error) {
+	where, args := r.filter(criteria)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tracks WHERE deleted_at IS NULL"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tracks: %w", err)
+	}
+
+	return count, nil
+}
+
+// filter narrows tracks to a "service" and an "isrc", so every service's version of a recording can be listed
+func (r *TrackRepository) filter(criteria map[string]any) (string, []any) {
+	query := ""
 	args := []any{}
 
 	if service, ok := criteria["service"].(string); ok && service != "" {
@@ -180,11 +347,69 @@ func (r *TrackRepository) List(criteria map[string]any) ([]*models.PersistedTrac
 		args = append(args, isrc)
 	}
 
-	query += " ORDER BY sequence ASC"
+	return query, args
+}
 
-	rows, err := r.db.Query(query, args...)
+// ListByPlaylist retrieves a cached playlist's tracks ordered by position, excluding soft-deleted tracks and entries
+func (r *TrackRepository) ListByPlaylist(ctx context.Context, playlistID string) ([]*models.PersistedTrack, error) {
+	query := `
+		SELECT t.id, t.sequence, t.service, t.service_id, t.title, t.artist, t.artists, t.album, t.duration, t.isrc, t.genres, t.audio_features, t.created_at, t.updated_at, t.deleted_at
+		FROM tracks t
+		JOIN playlist_tracks pt ON pt.track_id = t.id
+		WHERE pt.playlist_id = ? AND pt.deleted_at IS NULL AND t.deleted_at IS NULL
+		ORDER BY pt.position ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, playlistID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query tracks: %w", err)
+		return nil, fmt.Errorf("failed to query playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []*models.PersistedTrack
+	for rows.Next() {
+		track, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// Search finds cached tracks whose title, artist, or album match every word of the query (prefix matching),
+// excluding soft-deleted tracks. A limit of 0 returns all matches.
+func (r *TrackRepository) Search(ctx context.Context, text string, limit int) ([]*models.PersistedTrack, error) {
+	dialect := shared.DialectOf(r.db)
+	match, err := ftsQuery(dialect, text)
+	if err != nil {
+		return nil, err
+	}
+
+	source := "FROM tracks_fts JOIN tracks t ON t.sequence = tracks_fts.docid WHERE tracks_fts MATCH ?"
+	if dialect == shared.Postgres {
+		source = "FROM tracks t WHERE t.search @@ to_tsquery('simple', ?)"
+	}
+	query := `
+		SELECT t.id, t.sequence, t.service, t.service_id, t.title, t.artist, t.artists, t.album, t.duration, t.isrc, t.genres, t.audio_features, t.created_at, t.updated_at, t.deleted_at
+		` + source + ` AND t.deleted_at IS NULL
+		ORDER BY t.artist, t.title, t.sequence
+	`
+	args := []any{match}
+
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tracks: %w", err)
 	}
 	defer rows.Close()
 
@@ -213,17 +438,20 @@ func (r *TrackRepository) scanOne(row *sql.Row) (*models.PersistedTrack, error)
 		serviceID string
 		title     string
 		artist    string
+		artists   sql.NullString
 		album     string
 		duration  int
 		isrc      string
+		genres    sql.NullString
+		features  sql.NullString
 		createdAt time.Time
 		updatedAt time.Time
 		deletedAt sql.NullTime
 	)
 
-	err := row.Scan(&id, &sequence, &service, &serviceID, &title, &artist, &album, &duration, &isrc, &createdAt, &updatedAt, &deletedAt)
+	err := row.Scan(&id, &sequence, &service, &serviceID, &title, &artist, &artists, &album, &duration, &isrc, &genres, &features, &createdAt, &updatedAt, &deletedAt)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("track not found")
+		return nil, fmt.Errorf("%w: track", shared.ErrRecordNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan track: %w", err)
@@ -232,11 +460,13 @@ func (r *TrackRepository) scanOne(row *sql.Row) (*models.PersistedTrack, error)
 	dto := models.Track{
 		ID:       serviceID,
 		Title:    title,
-		Artist:   artist,
 		Album:    album,
 		Duration: duration,
 		ISRC:     isrc,
 	}
+	if err := decodeJSONColumns(&dto, artist, artists, genres, features); err != nil {
+		return nil, err
+	}
 
 	track := models.NewPersistedTrack(sequence, service, serviceID, dto)
 	track.SetID(id)
@@ -257,15 +487,18 @@ func (r *TrackRepository) scanRow(rows *sql.Rows) (*models.PersistedTrack, error
 		serviceID string
 		title     string
 		artist    string
+		artists   sql.NullString
 		album     string
 		duration  int
 		isrc      string
+		genres    sql.NullString
+		features  sql.NullString
 		createdAt time.Time
 		updatedAt time.Time
 		deletedAt sql.NullTime
 	)
 
-	err := rows.Scan(&id, &sequence, &service, &serviceID, &title, &artist, &album, &duration, &isrc, &createdAt, &updatedAt, &deletedAt)
+	err := rows.Scan(&id, &sequence, &service, &serviceID, &title, &artist, &artists, &album, &duration, &isrc, &genres, &features, &createdAt, &updatedAt, &deletedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan track: %w", err)
 	}
@@ -273,11 +506,13 @@ func (r *TrackRepository) scanRow(rows *sql.Rows) (*models.PersistedTrack, error
 	dto := models.Track{
 		ID:       serviceID,
 		Title:    title,
-		Artist:   artist,
 		Album:    album,
 		Duration: duration,
 		ISRC:     isrc,
 	}
+	if err := decodeJSONColumns(&dto, artist, artists, genres, features); err != nil {
+		return nil, err
+	}
 
 	track := models.NewPersistedTrack(sequence, service, serviceID, dto)
 	track.SetID(id)
@@ -288,3 +523,229 @@ func (r *TrackRepository) scanRow(rows *sql.Rows) (*models.PersistedTrack, error
 
 	return track, nil
 }
+
+// CreateBatch inserts multiple tracks in a single transaction using a prepared statement.
+//
+// Either every track is inserted or none are; sequence numbers are reserved as one contiguous block.
+func (r *TrackRepository) CreateBatch(ctx context.Context, tracks []*models.PersistedTrack) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sequence, err := reserveSequences(ctx, tx, "tracks", len(tracks))
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO tracks (id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, track := range tracks {
+		id := shared.GenerateID()
+		track.SetID(id)
+
+		if err := track.Validate(); err != nil {
+			return fmt.Errorf("validation failed for track %s: %w", track.ServiceID(), err)
+		}
+		artists, genres, features, err := encodeJSONColumns(track)
+		if err != nil {
+			return err
+		}
+
+		_, err = stmt.ExecContext(ctx,
+			id,
+			sequence+i,
+			track.Service(),
+			track.ServiceID(),
+			track.Title(),
+			track.Artist(),
+			artists,
+			track.Album(),
+			track.Duration(),
+			track.ISRC(),
+			genres,
+			features,
+			track.CreatedAt(),
+			track.UpdatedAt(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert track %s: %w", track.ServiceID(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return nil
+}
+
+// Upsert inserts track, or refreshes the title, artists, album, duration, and ISRC of the track already stored
+// with the same service+service_id, restoring it if soft-deleted. Genres and audio features are refreshed only when
+// track has them, so caching an unenriched copy keeps an earlier enrichment. The model's ID is set to the stored
+// row's ID.
+func (r *TrackRepository) Upsert(ctx context.Context, track *models.PersistedTrack) error {
+	return r.UpsertBatch(ctx, []*models.PersistedTrack{track})
+}
+
+// UpsertBatch inserts or refreshes multiple tracks in a single transaction using a prepared statement.
+//
+// Tracks that already exist (by service+service_id) have their metadata updated and are restored if soft-deleted.
+// Each model's ID is set to the stored row's ID. Sequence numbers reserved for updated rows are left unused.
+func (r *TrackRepository) UpsertBatch(ctx context.Context, tracks []*models.PersistedTrack) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sequence, err := reserveSequences(ctx, tx, "tracks", len(tracks))
+	if err != nil {
+		return fmt.Errorf("failed to generate sequence: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO tracks (id, sequence, service, service_id, title, artist, artists, album, duration, isrc, genres, audio_features, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(service, service_id) DO UPDATE SET
+			title = excluded.title,
+			artist = excluded.artist,
+			artists = excluded.artists,
+			album = excluded.album,
+			duration = excluded.duration,
+			isrc = excluded.isrc,
+			genres = COALESCE(excluded.genres, tracks.genres),
+			audio_features = COALESCE(excluded.audio_features, tracks.audio_features),
+			updated_at = excluded.updated_at,
+			deleted_at = NULL
+		RETURNING id
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, track := range tracks {
+		track.SetID(shared.GenerateID())
+
+		if err := track.Validate(); err != nil {
+			return fmt.Errorf("validation failed for track %s: %w", track.ServiceID(), err)
+		}
+		artists, genres, features, err := encodeJSONColumns(track)
+		if err != nil {
+			return err
+		}
+
+		var id string
+		err = stmt.QueryRowContext(ctx,
+			track.ID(),
+			sequence+i,
+			track.Service(),
+			track.ServiceID(),
+			track.Title(),
+			track.Artist(),
+			artists,
+			track.Album(),
+			track.Duration(),
+			track.ISRC(),
+			genres,
+			features,
+			track.CreatedAt(),
+			track.UpdatedAt(),
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to upsert track %s: %w", track.ServiceID(), err)
+		}
+		track.SetID(id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return nil
+}
+
+// encodeArtists returns every credited artist as a JSON column value, NULL when there is only the primary artist
+// stored in the artist column
+func encodeArtists(artists []string) (any, error) {
+	if len(artists) < 2 {
+		return nil, nil
+	}
+	data, err := json.Marshal(artists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode artists: %w", err)
+	}
+	return string(data), nil
+}
+
+// decodeArtists credits track with every artist from the JSON artists column, or with artist alone when it is NULL
+func decodeArtists(track *models.Track, artist string, artists sql.NullString) error {
+	if !artists.Valid || artists.String == "" {
+		track.SetArtists(artist)
+		return nil
+	}
+	if err := json.Unmarshal([]byte(artists.String), &track.Artists); err != nil {
+		return fmt.Errorf("failed to decode artists of track %s: %w", track.ID, err)
+	}
+	return nil
+}
+
+// encodeJSONColumns returns the artists (see [encodeArtists]), genres, and audio features of track as JSON column
+// values; genres and audio features are NULL when not enriched
+func encodeJSONColumns(track *models.PersistedTrack) (artists, genres, features any, err error) {
+	if artists, err = encodeArtists(track.Artists()); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(track.Genres()) > 0 {
+		data, err := json.Marshal(track.Genres())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to encode genres: %w", err)
+		}
+		genres = string(data)
+	}
+	if track.AudioFeatures() != nil {
+		data, err := json.Marshal(track.AudioFeatures())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to encode audio features: %w", err)
+		}
+		features = string(data)
+	}
+	return artists, genres, features, nil
+}
+
+// decodeJSONColumns sets the artists (see [decodeArtists]), genres, and audio features of track from their column
+// values
+func decodeJSONColumns(track *models.Track, artist string, artists, genres, features sql.NullString) error {
+	if err := decodeArtists(track, artist, artists); err != nil {
+		return err
+	}
+	if genres.Valid && genres.String != "" {
+		if err := json.Unmarshal([]byte(genres.String), &track.Genres); err != nil {
+			return fmt.Errorf("failed to decode genres of track %s: %w", track.ID, err)
+		}
+	}
+	if features.Valid && features.String != "" {
+		track.Features = &models.AudioFeatures{}
+		if err := json.Unmarshal([]byte(features.String), track.Features); err != nil {
+			return fmt.Errorf("failed to decode audio features of track %s: %w", track.ID, err)
+		}
+	}
+	return nil
+}
@@ -1,16 +1,16 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
 	"github.com/desertthunder/ytx/internal/models"
 )
 
 // TrackCacheAdapter implements tasks.TrackCacher using TrackRepository.
 //
-// Provides automatic track caching with deduplication via service+service_id constraints.
-// Duplicate tracks are silently ignored (UNIQUE constraint violations).
+// Tracks are deduplicated by service+service_id: caching a track again refreshes its stored metadata,
+// so the cache follows title, album, or ISRC corrections made on the service.
 type TrackCacheAdapter struct {
 	repo *TrackRepository
 }
@@ -20,23 +20,39 @@ func NewTrackCacheAdapter(repo *TrackRepository) *TrackCacheAdapter {
 	return &TrackCacheAdapter{repo: repo}
 }
 
-// CacheTrack caches a track from a service.
-// Returns nil if the track already exists (deduplication).
-// Only returns errors for actual failures (not constraint violations).
-func (a *TrackCacheAdapter) CacheTrack(service, serviceID string, track models.Track) error {
-	existing, err := a.repo.GetByServiceID(service, serviceID)
-	if err == nil && existing != nil {
-		return nil
+// CacheTrack caches a track from a service, updating the cached copy when it already exists.
+func (a *TrackCacheAdapter) CacheTrack(ctx context.Context, service, serviceID string, track models.Track) error {
+	if err := a.repo.Upsert(ctx, models.NewPersistedTrack(0, service, serviceID, track)); err != nil {
+		return fmt.Errorf("failed to cache track: %w", err)
 	}
+	return nil
+}
 
-	persistedTrack := models.NewPersistedTrack(0, service, serviceID, track)
-
-	err = a.repo.Create(persistedTrack)
+// FindCounterpart returns the cached track of service with the ISRC, implementing tasks.CounterpartFinder.
+func (a *TrackCacheAdapter) FindCounterpart(ctx context.Context, service, isrc string) (*models.Track, error) {
+	persisted, err := a.repo.FindCounterpart(ctx, service, isrc)
 	if err != nil {
-		if strings.Contains(err.Error(), "UNIQUE constraint") {
-			return nil
+		return nil, err
+	}
+	track := persisted.ToTrack()
+	return &track, nil
+}
+
+// BulkCacheTracks caches many tracks from a service in a single transaction.
+//
+// Existing tracks are refreshed rather than skipped. Tracks missing an ID, title, or artist cannot be
+// cached and are left out of the batch instead of failing it.
+func (a *TrackCacheAdapter) BulkCacheTracks(ctx context.Context, service string, tracks []models.Track) error {
+	batch := make([]*models.PersistedTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if track.ID == "" || track.Title == "" || track.Artist() == "" {
+			continue
 		}
-		return fmt.Errorf("failed to cache track: %w", err)
+		batch = append(batch, models.NewPersistedTrack(0, service, track.ID, track))
+	}
+
+	if err := a.repo.UpsertBatch(ctx, batch); err != nil {
+		return fmt.Errorf("failed to cache tracks: %w", err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -20,8 +21,25 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 // Create inserts a new user into the database with generated ID and sequence
-func (r *UserRepository) Create(user *models.User) error {
-	sequence, err := NextSequence(r.db, "users")
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.create(ctx, user, sql.NullString{})
+}
+
+// CreateWithPassword inserts a new user together with the password hash they log in with, as made by
+// [shared.HashPassword], in one transaction, so a failed registration never leaves an account without a password.
+func (r *UserRepository) CreateWithPassword(ctx context.Context, user *models.User, hash string) error {
+	return r.create(ctx, user, sql.NullString{String: hash, Valid: true})
+}
+
+// create reserves the user's sequence and inserts it in one transaction
+func (r *UserRepository) create(ctx context.Context, user *models.User, hash sql.NullString) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sequence, err := reserveSequences(ctx, tx, "users", 1)
 	if err != nil {
 		return fmt.Errorf("failed to generate sequence: %w", err)
 	}
@@ -34,19 +52,22 @@ func (r *UserRepository) Create(user *models.User) error {
 	}
 
 	query := `
-		INSERT INTO users (id, sequence, email, name, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO users (id, sequence, email, name, password_hash, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err = r.db.Exec(query, id, sequence, user.Email(), user.Name(), user.CreatedAt(), user.UpdatedAt())
+	_, err = tx.ExecContext(ctx, query, id, sequence, user.Email(), user.Name(), hash, user.CreatedAt(), user.UpdatedAt())
 	if err != nil {
 		return fmt.Errorf("failed to insert user: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user: %w", err)
+	}
 	return nil
 }
 
 // Get retrieves a user by ID, excluding soft-deleted users
-func (r *UserRepository) Get(id string) (*models.User, error) {
+func (r *UserRepository) Get(ctx context.Context, id string) (*models.User, error) {
 	query := `
 		SELECT id, sequence, email, name, created_at, updated_at, deleted_at
 		FROM users
@@ -63,7 +84,7 @@ func (r *UserRepository) Get(id string) (*models.User, error) {
 		deletedAt sql.NullTime
 	)
 
-	err := r.db.QueryRow(query, id).Scan(&userID, &sequence, &email, &name, &createdAt, &updatedAt, &deletedAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&userID, &sequence, &email, &name, &createdAt, &updatedAt, &deletedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found: %s", id)
 	}
@@ -81,8 +102,69 @@ func (r *UserRepository) Get(id string) (*models.User, error) {
 	return user, nil
 }
 
+// GetByEmail retrieves a user by email, excluding soft-deleted users.
+//
+// Returns [shared.ErrRecordNotFound] when no live user has the email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `
+		SELECT id, sequence, email, name, created_at, updated_at, deleted_at
+		FROM users
+		WHERE email = ? AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("row iteration error: %w", err)
+		}
+		return nil, fmt.Errorf("%w: no user with email %s", shared.ErrRecordNotFound, email)
+	}
+	return r.scanRow(rows)
+}
+
+// SetPasswordHash stores the password hash a user logs in with, as made by [shared.HashPassword].
+//
+// Returns [shared.ErrRecordNotFound] when the user does not exist or is deleted.
+func (r *UserRepository) SetPasswordHash(ctx context.Context, id, hash string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL
+	`, hash, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: user %s", shared.ErrRecordNotFound, id)
+	}
+	return nil
+}
+
+// PasswordHash returns a user's password hash, which is empty when the user cannot log in with a password.
+//
+// Returns [shared.ErrRecordNotFound] when the user does not exist or is deleted.
+func (r *UserRepository) PasswordHash(ctx context.Context, id string) (string, error) {
+	var hash sql.NullString
+	err := r.db.QueryRowContext(ctx, "SELECT password_hash FROM users WHERE id = ? AND deleted_at IS NULL", id).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("%w: user %s", shared.ErrRecordNotFound, id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query password: %w", err)
+	}
+	return hash.String, nil
+}
+
 // Update modifies an existing user in the database
-func (r *UserRepository) Update(user *models.User) error {
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	if err := user.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
@@ -96,7 +178,7 @@ func (r *UserRepository) Update(user *models.User) error {
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, user.Email(), user.Name(), now, user.ID())
+	result, err := r.db.ExecContext(ctx, query, user.Email(), user.Name(), now, user.ID())
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -113,7 +195,7 @@ func (r *UserRepository) Update(user *models.User) error {
 }
 
 // Delete soft-deletes a user by ID
-func (r *UserRepository) Delete(id string) error {
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	now := time.Now()
 
 	query := `
@@ -122,7 +204,7 @@ func (r *UserRepository) Delete(id string) error {
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(query, now, id)
+	result, err := r.db.ExecContext(ctx, query, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -138,24 +220,73 @@ func (r *UserRepository) Delete(id string) error {
 	return nil
 }
 
+// PurgeDeleted permanently removes users soft-deleted more than olderThan ago, along with their playlists, playlist entries, migration jobs, migration failures, and credentials.
+// A zero olderThan purges every soft-deleted row. Returns the number of users removed.
+func (r *UserRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return purgeDeleted(ctx, r.db, "users", olderThan,
+		`DELETE FROM playlist_tracks WHERE playlist_id IN (SELECT id FROM playlists WHERE user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at <= ?))`,
+		`DELETE FROM playlists WHERE user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at <= ?)`,
+		`DELETE FROM migration_failures WHERE migration_id IN (SELECT id FROM migrations WHERE user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at <= ?))`,
+		`DELETE FROM migrations WHERE user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at <= ?)`,
+		`DELETE FROM user_credentials WHERE user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at <= ?)`,
+	)
+}
+
+// Restore undoes the soft delete of a user by ID, rejecting conflicts on email
+func (r *UserRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, r.db, "users", id, "email")
+}
+
+// ListDeleted retrieves all soft-deleted users, most recently deleted first
+func (r *UserRepository) ListDeleted(ctx context.Context) ([]*models.User, error) {
+	query := `
+		SELECT id, sequence, email, name, created_at, updated_at, deleted_at
+		FROM users
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted users: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []*models.User
+	for rows.Next() {
+		user, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return deleted, nil
+}
+
 // List retrieves all users matching the given criteria, excluding soft-deleted users
-func (r *UserRepository) List(criteria map[string]any) ([]*models.User, error) {
+func (r *UserRepository) List(ctx context.Context, criteria map[string]any, opts models.ListOpts) ([]*models.User, error) {
 	query := `
 		SELECT id, sequence, email, name, created_at, updated_at, deleted_at
 		FROM users
 		WHERE deleted_at IS NULL
 	`
 
-	args := []any{}
+	where, args := r.filter(criteria)
+	query += where
 
-	if email, ok := criteria["email"].(string); ok && email != "" {
-		query += " AND email = ?"
-		args = append(args, email)
+	clause, pageArgs, err := listClause(opts, "sequence ASC")
+	if err != nil {
+		return nil, err
 	}
+	query += clause
+	args = append(args, pageArgs...)
 
-	query += " ORDER BY sequence ASC"
-
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
@@ -163,28 +294,10 @@ func (r *UserRepository) List(criteria map[string]any) ([]*models.User, error) {
 
 	var users []*models.User
 	for rows.Next() {
-		var (
-			userID    string
-			sequence  int
-			email     string
-			name      string
-			createdAt time.Time
-			updatedAt time.Time
-			deletedAt sql.NullTime
-		)
-
-		err := rows.Scan(&userID, &sequence, &email, &name, &createdAt, &updatedAt, &deletedAt)
+		user, err := r.scanRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
-		}
-
-		user := models.NewUser(sequence, email, name)
-		user.SetID(userID)
-		user.SetUpdatedAt(updatedAt)
-		if deletedAt.Valid {
-			user.SetDeletedAt(&deletedAt.Time)
+			return nil, err
 		}
-
 		users = append(users, user)
 	}
 
@@ -194,3 +307,76 @@ func (r *UserRepository) List(criteria map[string]any) ([]*models.User, error) {
 
 	return users, nil
 }
+
+// Count returns the number of users matching the given criteria, excluding soft-deleted users
+func (r *UserRepository) Count(ctx context.Context, criteria map[string]any) (int, error) {
+	where, args := r.filter(criteria)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE deleted_at IS NULL"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return count, nil
+}
+
+// filter matches users by exact "email"
+func (r *UserRepository) filter(criteria map[string]any) (string, []any) {
+	query := ""
+	args := []any{}
+
+	if email, ok := criteria["email"].(string); ok && email != "" {
+		query += " AND email = ?"
+		args = append(args, email)
+	}
+
+	return query, args
+}
+
+// scanRow scans a row from [sql.Rows] into a [models.User]
+func (r *UserRepository) scanRow(rows *sql.Rows) (*models.User, error) {
+	var (
+		userID    string
+		sequence  int
+		email     string
+		name      string
+		createdAt time.Time
+		updatedAt time.Time
+		deletedAt sql.NullTime
+	)
+
+	err := rows.Scan(&userID, &sequence, &email, &name, &createdAt, &updatedAt, &deletedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	user := models.NewUser(sequence, email, name)
+	user.SetID(userID)
+	user.SetUpdatedAt(updatedAt)
+	if deletedAt.Valid {
+		user.SetDeletedAt(&deletedAt.Time)
+	}
+
+	return user, nil
+}
+
+// LocalUserEmail identifies the implicit user that owns data cached by the CLI
+const LocalUserEmail = "local@ytx"
+
+// EnsureLocalUser returns the local CLI user, creating it on first use
+func EnsureLocalUser(ctx context.Context, repo *UserRepository) (*models.User, error) {
+	users, err := repo.List(ctx, map[string]any{"email": LocalUserEmail}, models.ListOpts{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) > 0 {
+		return users[0], nil
+	}
+
+	user := models.NewUser(0, LocalUserEmail, "Local")
+	if err := repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -6,7 +6,9 @@
 //
 // [Middleware] wraps handlers in reverse order (last added executes first), following the standard Go pattern.
 //
-// The [BasicRouter] implementation uses [http.ServeMux] internally with method filtering.
+// The [BasicRouter] implementation uses [http.ServeMux] internally, registering routes as method patterns, so paths
+// may hold wildcards such as /api/jobs/{id} that handlers read with [PathParam], and a request with an unregistered
+// method is answered 405 with an Allow header.
 //
 // # OAuth Callback Handler
 //
@@ -17,19 +19,65 @@
 //
 // It only processes one callback to prevent replay attacks.
 //
+// # Server Lifecycle
+//
+// [Server] runs a handler on a listener and shuts it down gracefully on SIGINT, SIGTERM, or a cancelled context,
+// waiting for in-flight requests up to a timeout. It serves /healthz and /readyz ahead of the handler's middleware;
+// /readyz runs the [ReadinessCheck] functions registered with [Server.AddReadinessCheck]. The OAuth callback server,
+// the metrics server, and the serve command all run on it.
+//
 // # Current Usage
 //
 // The server package currently supports CLI OAuth flows for Spotify authentication.
-// When the user runs authentication commands, a temporary HTTP server starts on localhost:3000, handles the callback,
-// and shuts down after receiving the OAuth token.
+// When the user runs authentication commands, a temporary HTTP server starts on the configured port, handles the
+// callback, and shuts down after receiving the OAuth token.
+//
+// [ListenCallback] falls back through a port range (or an ephemeral port) when the preferred port is taken, and
+// [CallbackURL] builds the matching redirect URI.
+//
+// # Metrics
+//
+// [Metrics] is a Prometheus registry served at /metrics in the text exposition format. It counts and times outgoing
+// API requests through [Metrics.InstrumentTransport], and records track searches, transfer durations, and the
+// transfer queue depth reported by the playlist engine. The CLI serves it while running when server.metrics_addr is set.
+//
+// # API Keys
+//
+// [RequireAPIKey] guards a router with a shared key, sent as an X-API-Key header or a bearer token and compared in
+// constant time, so the web server can be exposed beyond localhost. Paths in [DefaultAPIKeyExemptions], the health
+// check and the OAuth callback, stay reachable without it. [RequireAPIKeyOr] also lets through requests a handler
+// vouches for, such as the web app's session cookie, which browsers send where they cannot send a header.
+//
+// # Browser Headers
+//
+// [CORS] answers preflight requests and lets the configured origins call the server with credentials, and
+// [SecurityHeaders] sets nosniff, referrer, framing, and Content-Security-Policy headers on every response.
+// Both are added to the router ahead of [RequireAPIKey] so they wrap it.
+//
+// # Access Logs and Recovery
+//
+// [AccessLog] logs each request's method, path, status, size, duration, and request ID, assigning the ID (or keeping
+// a client's [RequestIDHeader]) for handlers to read with [RequestID]. [Recover] turns a panicking handler into a
+// 500 JSON error carrying that ID. The serve command registers both ahead of every other middleware.
+//
+// # Server-Sent Events
+//
+// [SSEWriter] implements the text/event-stream protocol for handlers streaming progress: it writes named events
+// with JSON or multi-line text data and flushes each one, sends keepalive comments with [SSEWriter.KeepAlive], and
+// reports [ErrClientDisconnected] once the client goes away. [Stream] forwards a channel, such as the playlist
+// engine's progress updates, until it is closed or the client disconnects.
+//
+// # WebSockets
+//
+// [UpgradeWebSocket] completes an RFC 6455 handshake, checking the Origin so other sites cannot open sockets with
+// a user's cookies, and returns a [WebSocket] for bidirectional messages: JSON or text out, control messages in,
+// with pings answered and fragments reassembled. It implements only what browsers need, without extensions.
 //
 // # Web Application Integration
 //
-// The web package (internal/web) will extend this infrastructure with:
-//   - Session middleware for persistent authentication state
+// The web package (internal/web) serves accounts with per-user credentials through a [Handler] registered on a
+// [BasicRouter] by the serve command, along with its job queue, and will extend this infrastructure with:
 //   - Playlist handlers rendering HTMX templates
-//   - SSE streaming for real-time transfer progress
-//   - Migration job management with repositories
 //
 // # Handler Interface
 //
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultContentSecurityPolicy allows the web pages their own scripts and styles plus HTMX and missing.css from
+// unpkg, and forbids framing.
+const DefaultContentSecurityPolicy = "default-src 'self'; script-src 'self' https://unpkg.com; " +
+	"style-src 'self' https://unpkg.com; img-src 'self' https: data:; frame-ancestors 'none'; base-uri 'self'"
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight response
+const corsMaxAge = 600
+
+var (
+	corsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	corsHeaders = []string{"Content-Type", "Authorization", APIKeyHeader, "HX-Request", "HX-Target", "HX-Current-URL"}
+)
+
+// SecurityHeaders returns [Middleware] setting the standard hardening headers on every response: nosniff, a strict
+// referrer policy, no framing, and csp as the Content-Security-Policy unless it is empty.
+func SecurityHeaders(csp string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			h.Set("X-Frame-Options", "DENY")
+			if csp != "" {
+				h.Set("Content-Security-Policy", csp)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORS returns [Middleware] letting browser frontends on origins call the server.
+//
+// Allowed origins are echoed back with credentials allowed, so session cookies are sent; "*" allows any origin,
+// but without credentials. Preflight requests are answered directly, before any API key check, since browsers
+// never send credentials with them. No origins disables CORS, leaving browsers to enforce the same-origin policy.
+func CORS(origins []string) Middleware {
+	wildcard := slices.Contains(origins, "*")
+
+	return func(next http.Handler) http.Handler {
+		if len(origins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			h := w.Header()
+			h.Add("Vary", "Origin")
+
+			switch {
+			case origin == "":
+			case slices.Contains(origins, origin):
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Set("Access-Control-Allow-Credentials", "true")
+			case wildcard:
+				h.Set("Access-Control-Allow-Origin", "*")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				if h.Get("Access-Control-Allow-Origin") != "" {
+					h.Set("Access-Control-Allow-Methods", strings.Join(corsMethods, ", "))
+					h.Set("Access-Control-Allow-Headers", strings.Join(corsHeaders, ", "))
+					h.Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultShutdownTimeout bounds how long [Server.Shutdown] waits for in-flight requests
+	DefaultShutdownTimeout = 5 * time.Second
+	// readinessTimeout bounds all readiness checks of one /readyz request
+	readinessTimeout = 5 * time.Second
+)
+
+// ReadinessCheck reports whether a dependency of the server, such as the database, can serve requests.
+type ReadinessCheck func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// Server owns the lifecycle of an HTTP server: serving on a listener, reporting serve errors, and shutting down
+// gracefully on a signal or cancelled context.
+//
+// Besides its handler it serves /healthz, which answers 200 while the process is up, and /readyz, which runs the
+// registered readiness checks and answers 503 when any fails. Both bypass the handler's middleware, so probes need
+// no API key and are not access logged.
+type Server struct {
+	http            *http.Server
+	shutdownTimeout time.Duration
+	errs            chan error
+
+	mu     sync.Mutex
+	checks []namedCheck
+}
+
+// NewServer creates a [Server] for handler, typically a [BasicRouter].
+func NewServer(handler http.Handler) *Server {
+	s := &Server{shutdownTimeout: DefaultShutdownTimeout, errs: make(chan error, 1)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /readyz", s.readyz)
+	mux.Handle("/", handler)
+	s.http = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// AddReadinessCheck registers a check run by /readyz, reported under name.
+func (s *Server) AddReadinessCheck(name string, check ReadinessCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, namedCheck{name, check})
+}
+
+// SetShutdownTimeout sets how long [Server.Shutdown] waits for in-flight requests (default [DefaultShutdownTimeout]).
+func (s *Server) SetShutdownTimeout(d time.Duration) {
+	s.shutdownTimeout = d
+}
+
+// ServeHTTP serves a request as the running server would, including the probe endpoints.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.http.Handler.ServeHTTP(w, r)
+}
+
+// Start serves on listener in the background. An error stopping the server early is sent on [Server.Errors].
+func (s *Server) Start(listener net.Listener) {
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.errs <- err
+		}
+	}()
+}
+
+// Errors returns the channel receiving the error that stopped a server started with [Server.Start].
+func (s *Server) Errors() <-chan error {
+	return s.errs
+}
+
+// Run serves on listener until ctx is cancelled, SIGINT or SIGTERM is received, or serving fails, then shuts down
+// gracefully. It returns the serve or shutdown error, or nil after a clean shutdown.
+func (s *Server) Run(ctx context.Context, listener net.Listener) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s.Start(listener)
+	select {
+	case err := <-s.errs:
+		return err
+	case <-ctx.Done():
+		return s.Shutdown(context.WithoutCancel(ctx))
+	}
+}
+
+// ListenAndServe listens on addr and calls [Server.Run].
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Run(ctx, listener)
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests, up to the shutdown timeout.
+func (s *Server) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
+
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	checks := append([]namedCheck(nil), s.checks...)
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	status, code := "ready", http.StatusOK
+	results := make(map[string]string, len(checks))
+	for _, c := range checks {
+		if err := c.check(ctx); err != nil {
+			results[c.name] = err.Error()
+			status, code = "unavailable", http.StatusServiceUnavailable
+			continue
+		}
+		results[c.name] = "ok"
+	}
+	writeStatus(w, code, map[string]any{"status": status, "checks": results})
+}
+
+func writeStatus(w http.ResponseWriter, code int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
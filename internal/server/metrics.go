@@ -0,0 +1,212 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/tracing"
+)
+
+// DefaultBuckets are the latency histogram upper bounds, in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// TransferBuckets are the transfer duration histogram upper bounds, in seconds.
+var TransferBuckets = []float64{10, 30, 60, 120, 300, 600, 1800, 3600}
+
+// histogram accumulates observations into cumulative buckets
+type histogram struct {
+	buckets []float64
+	counts  []uint64 // counts[i] is the number of observations <= buckets[i]
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// Metrics is a registry of ytx metrics served in the Prometheus text exposition format.
+//
+// It implements [Handler] for /metrics and the engine's metrics recorder, and instruments outgoing API requests
+// through [Metrics.InstrumentTransport]. Match success rate is derived from ytx_searches_total by result.
+type Metrics struct {
+	mu               sync.Mutex
+	apiRequests      map[string]map[string]uint64 // service → status code → count
+	apiDuration      map[string]*histogram        // service
+	searchDuration   map[string]*histogram        // service
+	searches         map[string]map[string]uint64 // service → "matched" or "failed" → count
+	transferDuration map[string]*histogram        // status
+	queueDepth       int
+}
+
+// NewMetrics creates an empty [Metrics] registry.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		apiRequests:      map[string]map[string]uint64{},
+		apiDuration:      map[string]*histogram{},
+		searchDuration:   map[string]*histogram{},
+		searches:         map[string]map[string]uint64{},
+		transferDuration: map[string]*histogram{},
+	}
+}
+
+// Routes returns the HTTP routes this handler serves.
+func (m *Metrics) Routes() []string {
+	return []string{"/metrics"}
+}
+
+// ServeHTTP writes every metric in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(m.Expose())
+}
+
+// ObserveAPIRequest records an outgoing API request to service. code is the HTTP status code, or "error" when
+// no response was received.
+func (m *Metrics) ObserveAPIRequest(service, code string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	increment(m.apiRequests, service, code)
+	observe(m.apiDuration, service, DefaultBuckets, duration.Seconds())
+}
+
+// ObserveSearch records the latency and outcome of a track search on service.
+func (m *Metrics) ObserveSearch(service string, duration time.Duration, matched bool) {
+	result := "failed"
+	if matched {
+		result = "matched"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	increment(m.searches, service, result)
+	observe(m.searchDuration, service, DefaultBuckets, duration.Seconds())
+}
+
+// ObserveTransfer records the duration of a finished transfer by status.
+func (m *Metrics) ObserveTransfer(status string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	observe(m.transferDuration, status, TransferBuckets, duration.Seconds())
+}
+
+// ObserveQueueDepth sets the number of queued transfers that have yet to finish.
+func (m *Metrics) ObserveQueueDepth(depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queueDepth = depth
+}
+
+// InstrumentTransport wraps next (or [http.DefaultTransport] when nil) so that every request is counted and
+// timed under service.
+func (m *Metrics) InstrumentTransport(service string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return tracing.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		m.ObserveAPIRequest(service, code, time.Since(start))
+		return resp, err
+	})
+}
+
+// Expose renders every metric in the Prometheus text exposition format, with series sorted by label.
+func (m *Metrics) Expose() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var buf bytes.Buffer
+	writeCounter(&buf, "ytx_api_requests_total", "Outgoing API requests by service and HTTP status code.",
+		"service", "code", m.apiRequests)
+	writeHistogram(&buf, "ytx_api_request_duration_seconds", "Outgoing API request latency by service.",
+		"service", m.apiDuration)
+	writeCounter(&buf, "ytx_searches_total", "Track searches by destination service and result (matched or failed).",
+		"service", "result", m.searches)
+	writeHistogram(&buf, "ytx_search_duration_seconds", "Track search latency by destination service.",
+		"service", m.searchDuration)
+	writeHistogram(&buf, "ytx_transfer_duration_seconds", "Playlist transfer duration by status (completed or failed).",
+		"status", m.transferDuration)
+	fmt.Fprintf(&buf, "# HELP ytx_job_queue_depth Queued transfers that have yet to finish.\n")
+	fmt.Fprintf(&buf, "# TYPE ytx_job_queue_depth gauge\n")
+	fmt.Fprintf(&buf, "ytx_job_queue_depth %d\n", m.queueDepth)
+	return buf.Bytes()
+}
+
+func increment(counters map[string]map[string]uint64, outer, inner string) {
+	if counters[outer] == nil {
+		counters[outer] = map[string]uint64{}
+	}
+	counters[outer][inner]++
+}
+
+func observe(histograms map[string]*histogram, label string, buckets []float64, v float64) {
+	h, ok := histograms[label]
+	if !ok {
+		h = newHistogram(buckets)
+		histograms[label] = h
+	}
+	h.observe(v)
+}
+
+func writeCounter(buf *bytes.Buffer, name, help, outerLabel, innerLabel string, counters map[string]map[string]uint64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, outer := range sortedKeys(counters) {
+		for _, inner := range sortedKeys(counters[outer]) {
+			fmt.Fprintf(buf, "%s{%s=%s,%s=%s} %d\n", name,
+				outerLabel, quoteLabel(outer), innerLabel, quoteLabel(inner), counters[outer][inner])
+		}
+	}
+}
+
+func writeHistogram(buf *bytes.Buffer, name, help, label string, histograms map[string]*histogram) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, value := range sortedKeys(histograms) {
+		h := histograms[value]
+		labels := label + "=" + quoteLabel(value)
+		for i, bound := range h.buckets {
+			fmt.Fprintf(buf, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(buf, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(buf, "%s_sum{%s} %s\n", name, labels, formatFloat(h.sum))
+		fmt.Fprintf(buf, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// quoteLabel quotes a label value, escaping backslashes, quotes, and newlines
+func quoteLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
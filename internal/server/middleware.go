@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// RequestIDHeader carries the request ID assigned by [AccessLog], echoed on the response.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// RequestID returns the ID [AccessLog] assigned to the request carrying ctx, or "" outside it.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// AccessLog returns [Middleware] logging every request with its method, path, status, response size, duration,
+// and request ID. The ID is taken from an incoming [RequestIDHeader] header when it looks like one, or generated,
+// and is set on the response and available to handlers through [RequestID].
+//
+// Only the path is logged: query strings can carry OAuth codes and other secrets.
+func AccessLog(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+
+			logger.Info("request", "method", r.Method, "path", r.URL.Path, "status", rec.Status(), "bytes", rec.bytes,
+				"duration", time.Since(start), "request_id", id)
+		})
+	}
+}
+
+// Recover returns [Middleware] turning a panicking handler into a 500 JSON error response with the request ID,
+// logging the panic with its stack trace. A response already started when the handler panicked is left as is.
+func Recover(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				// Aborting a response on purpose is not a bug; let net/http handle it
+				if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+
+				id := RequestID(r.Context())
+				logger.Error("handler panicked", "method", r.Method, "path", r.URL.Path, "request_id", id, "panic", v,
+					"stack", string(debug.Stack()))
+				if rec.status != 0 {
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": "internal server error", "request_id": id})
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// statusRecorder records the status and size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(p []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(p)
+	s.bytes += n
+	return n, err
+}
+
+// Status returns the response status, which is 200 when the handler wrote nothing
+func (s *statusRecorder) Status() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+// Flush supports streaming responses, such as server-sent events, through the recorder
+func (s *statusRecorder) Flush() {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	http.NewResponseController(s.ResponseWriter).Flush()
+}
+
+// Unwrap lets [http.ResponseController] reach the underlying writer
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID accepts short IDs of letters, digits, and dashes, so client-supplied IDs cannot inject into logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -2,13 +2,50 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 
 	"golang.org/x/oauth2"
 )
 
+// ListenCallback binds the OAuth callback server to the first free port of ports on host.
+//
+// A port of 0 lets the OS pick an ephemeral port.
+func ListenCallback(host string, ports []int) (net.Listener, error) {
+	var errs []error
+	for _, port := range ports {
+		listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err == nil {
+			return listener, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("no free port for the OAuth callback server on %s: %w", host, errors.Join(errs...))
+}
+
+// CallbackURL returns the redirect URI of a callback server listening on port.
+//
+// The scheme and host of the configured redirect URI are kept, with the port replaced and the path set to the
+// route served by [OAuthHandler]; an empty configured URI falls back to http://127.0.0.1.
+func CallbackURL(configured string, port int) (string, error) {
+	if configured == "" {
+		configured = "http://127.0.0.1/callback"
+	}
+
+	u, err := url.Parse(configured)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid redirect URI '%s'", configured)
+	}
+	u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(port))
+	u.Path = "/callback"
+	return u.String(), nil
+}
+
 // OAuthResult contains the result of an OAuth authorization flow.
 type OAuthResult struct {
 	Token *oauth2.Token
@@ -22,21 +59,25 @@ func (o *OAuthResult) Error() error {
 // OAuthHandler handles OAuth2 callback requests for authorization code flow.
 // Implements the Handler interface for registration with a Router.
 type OAuthHandler struct {
-	config      *oauth2.Config
-	state       string
-	resultChan  chan OAuthResult
-	once        sync.Once
-	callbackHit bool
-	mu          sync.Mutex
+	config       *oauth2.Config
+	state        string
+	exchangeOpts []oauth2.AuthCodeOption
+	resultChan   chan OAuthResult
+	once         sync.Once
+	callbackHit  bool
+	mu           sync.Mutex
 }
 
 // NewOAuthHandler creates a new OAuth handler with the given OAuth2 config and state token.
 // The state token should be cryptographically random for CSRF protection.
-func NewOAuthHandler(config *oauth2.Config, state string) *OAuthHandler {
+//
+// opts are passed to the code exchange, e.g. the PKCE code verifier.
+func NewOAuthHandler(config *oauth2.Config, state string, opts ...oauth2.AuthCodeOption) *OAuthHandler {
 	return &OAuthHandler{
-		config:     config,
-		state:      state,
-		resultChan: make(chan OAuthResult, 1),
+		config:       config,
+		state:        state,
+		exchangeOpts: opts,
+		resultChan:   make(chan OAuthResult, 1),
 	}
 }
 
@@ -77,7 +118,7 @@ func (h *OAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.config.Exchange(context.Background(), code)
+	token, err := h.config.Exchange(context.Background(), code, h.exchangeOpts...)
 	if err != nil {
 		h.Send(OAuthResult{err: fmt.Errorf("token exchange failed: %w", err)})
 		http.Error(w, "Token exchange failed", http.StatusInternalServerError)
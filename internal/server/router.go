@@ -26,26 +26,28 @@ func (r *BasicRouter) Use(middleware ...Middleware) {
 	r.middlewares = append(r.middlewares, middleware...)
 }
 
-// Handle registers a [Handler] for the specified HTTP method and path.
+// Handle registers a handler for the specified HTTP method and path; an empty method matches any method.
 //
-// The handler is wrapped with all registered middleware.
+// The path is a [http.ServeMux] pattern, so it may hold wildcards such as /api/jobs/{id} or /files/{path...},
+// read in the handler with [PathParam]. Requests for the path with another method are answered 405 with an
+// Allow header, and GET routes also serve HEAD. The handler is wrapped with all registered middleware.
 func (r *BasicRouter) Handle(method, path string, handler http.Handler) {
-	wrapped := r.Apply(handler)
-
-	methodHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		if !strings.EqualFold(req.Method, method) {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		wrapped.ServeHTTP(w, req)
-	})
+	pattern := path
+	if method != "" {
+		pattern = strings.ToUpper(method) + " " + path
+	}
+	r.mux.Handle(pattern, r.Apply(handler))
+}
 
-	r.mux.Handle(path, methodHandler)
+// HandleFunc registers a handler function for the specified HTTP method and path, as [BasicRouter.Handle] does.
+func (r *BasicRouter) HandleFunc(method, path string, handler http.HandlerFunc) {
+	r.Handle(method, path, handler)
 }
 
 // Handler registers a custom Handler implementation.
 //
-// All routes returned by [Handler.Routes] are registered with this handler.
+// All routes returned by [Handler.Routes] are registered with this handler. Routes are [http.ServeMux] patterns
+// and may include a method and wildcards, e.g. "GET /api/jobs/{id}".
 func (r *BasicRouter) Handler(handler Handler) {
 	wrapped := r.Apply(handler)
 
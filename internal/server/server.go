@@ -11,6 +11,8 @@ type Middleware func(http.Handler) http.Handler
 
 // Handler defines the interface for HTTP request handlers in the song migration service.
 // Implementations handle specific endpoints (auth, playlist operations, migrations).
+//
+// Routes are [http.ServeMux] patterns, optionally with a method and wildcards, e.g. "DELETE /api/jobs/{id}".
 type Handler interface {
 	http.Handler      // ServeHTTP handles the HTTP request and writes the response
 	Routes() []string // Routes returns the path patterns this handler serves
@@ -18,9 +20,19 @@ type Handler interface {
 
 // Router defines the interface for HTTP routing and middleware management.
 // Implementations register handlers, apply middleware, and configure the HTTP server.
+//
+// Paths are [http.ServeMux] patterns: wildcards such as /api/jobs/{id} are read in handlers with [PathParam].
 type Router interface {
-	Use(middleware ...Middleware)                     // Use adds middleware to the router's middleware stack
-	Handle(method, path string, handler http.Handler) // Handle registers a handler for the specified method and path
-	Handler(handler Handler)                          // Handler registers a custom Handler implementation
-	ServeHTTP(w http.ResponseWriter, r *http.Request) // ServeHTTP implements http.Handler for the entire router
+	Use(middleware ...Middleware)                             // Use adds middleware to the router's middleware stack
+	Handle(method, path string, handler http.Handler)         // Handle registers a handler for the specified method and path
+	HandleFunc(method, path string, handler http.HandlerFunc) // HandleFunc registers a handler function for the specified method and path
+	Handler(handler Handler)                                  // Handler registers a custom Handler implementation
+	ServeHTTP(w http.ResponseWriter, r *http.Request)         // ServeHTTP implements http.Handler for the entire router
+}
+
+// PathParam returns the value of the wildcard name in the path of a request routed by a [Router], e.g. the ID of
+// a request for /api/jobs/42 matched by /api/jobs/{id}; it is empty when the route has no such wildcard.
+// Values are unescaped, so "%2F" in the request path yields "/".
+func PathParam(r *http.Request, name string) string {
+	return r.PathValue(name)
 }
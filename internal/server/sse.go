@@ -0,0 +1,194 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultKeepAlive is the keepalive interval suggested for [SSEWriter.KeepAlive]: short enough to stop proxies
+// closing an idle stream, which many do after 60 seconds.
+const DefaultKeepAlive = 15 * time.Second
+
+var (
+	// ErrStreamingUnsupported is returned by [NewSSEWriter] when the response cannot be flushed.
+	ErrStreamingUnsupported = errors.New("streaming unsupported")
+	// ErrClientDisconnected is returned by [SSEWriter] methods once the client has gone away.
+	ErrClientDisconnected = errors.New("client disconnected")
+)
+
+// Event is one server-sent event.
+type Event struct {
+	ID    string        // Sets the client's last event ID, sent back as Last-Event-ID when it reconnects
+	Name  string        // Event type, dispatched to listeners for that name; empty for the default "message"
+	Data  any           // Sent as is when a string or []byte, as JSON otherwise
+	Retry time.Duration // Reconnection delay suggested to the client; zero leaves it unchanged
+}
+
+// SSEWriter streams server-sent events (text/event-stream) on a response, flushing each event as it is written.
+//
+// It is safe for concurrent use, so a keepalive can run alongside the handler's events. Writes fail with
+// [ErrClientDisconnected] once the request's context is done.
+type SSEWriter struct {
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	done <-chan struct{}
+	mu   sync.Mutex
+}
+
+// NewSSEWriter starts an event stream on w for r, sending the stream headers immediately.
+//
+// It fails with [ErrStreamingUnsupported] when w cannot be flushed, before anything is written.
+func NewSSEWriter(w http.ResponseWriter, r *http.Request) (*SSEWriter, error) {
+	rc := http.NewResponseController(w)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // Stops nginx buffering the stream
+	if err := rc.Flush(); err != nil {
+		for _, name := range []string{"Content-Type", "Cache-Control", "Connection", "X-Accel-Buffering"} {
+			h.Del(name)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrStreamingUnsupported, err)
+	}
+	// Long-lived streams outlast the server's write timeout; an unsupported deadline is not an error
+	rc.SetWriteDeadline(time.Time{})
+
+	return &SSEWriter{w: w, rc: rc, done: r.Context().Done()}, nil
+}
+
+// Done is closed when the client disconnects.
+func (s *SSEWriter) Done() <-chan struct{} {
+	return s.done
+}
+
+// Send writes an event named name carrying data.
+func (s *SSEWriter) Send(name string, data any) error {
+	return s.SendEvent(Event{Name: name, Data: data})
+}
+
+// SendEvent writes ev. Multi-line data is split across data fields, which clients join back with newlines.
+func (s *SSEWriter) SendEvent(ev Event) error {
+	data, err := eventData(ev.Data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if ev.ID != "" {
+		fmt.Fprintf(&buf, "id: %s\n", sanitizeField(ev.ID))
+	}
+	if ev.Name != "" {
+		fmt.Fprintf(&buf, "event: %s\n", sanitizeField(ev.Name))
+	}
+	if ev.Retry > 0 {
+		fmt.Fprintf(&buf, "retry: %s\n", strconv.FormatInt(ev.Retry.Milliseconds(), 10))
+	}
+	for line := range strings.SplitSeq(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+	buf.WriteByte('\n')
+	return s.write(buf.Bytes())
+}
+
+// Comment writes a comment line, which clients ignore; used to keep the connection alive.
+func (s *SSEWriter) Comment(text string) error {
+	return s.write([]byte(": " + sanitizeField(text) + "\n\n"))
+}
+
+// KeepAlive writes a comment every interval until the client disconnects or the returned stop function is called.
+// Stop must be called before the handler returns.
+func (s *SSEWriter) KeepAlive(interval time.Duration) (stop func()) {
+	quit := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if s.Comment("keepalive") != nil {
+					return
+				}
+			case <-quit:
+				return
+			case <-s.done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(quit) })
+		<-finished
+	}
+}
+
+// Stream sends an event made by toEvent for every value received on ch, until ch is closed or the client
+// disconnects. Returns [ErrClientDisconnected] in the latter case, leaving ch to be drained by its sender.
+func Stream[T any](s *SSEWriter, ch <-chan T, toEvent func(T) Event) error {
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := s.SendEvent(toEvent(v)); err != nil {
+				return err
+			}
+		case <-s.done:
+			return ErrClientDisconnected
+		}
+	}
+}
+
+func (s *SSEWriter) write(p []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return ErrClientDisconnected
+	default:
+	}
+	if _, err := s.w.Write(p); err != nil {
+		return fmt.Errorf("%w: %v", ErrClientDisconnected, err)
+	}
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("%w: %v", ErrClientDisconnected, err)
+	}
+	return nil
+}
+
+// eventData renders event data: strings and bytes as is, anything else as JSON
+func eventData(data any) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode event data: %w", err)
+		}
+		return string(b), nil
+	}
+}
+
+// sanitizeField keeps single-line fields from breaking the stream framing
+func sanitizeField(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
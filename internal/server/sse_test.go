@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEvents reads n events (or comments) from an event stream, each as its lines joined with newlines
+func readEvents(t *testing.T, scanner *bufio.Scanner, n int) []string {
+	t.Helper()
+	var events []string
+	var lines []string
+	for len(events) < n && scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+			continue
+		}
+		events = append(events, strings.Join(lines, "\n"))
+		lines = nil
+	}
+	if len(events) < n {
+		t.Fatalf("stream ended after %d of %d events: %v", len(events), n, scanner.Err())
+	}
+	return events
+}
+
+func TestSSEWriter(t *testing.T) {
+	t.Run("formats events", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sse, err := NewSSEWriter(w, r)
+			if err != nil {
+				t.Errorf("NewSSEWriter() error = %v", err)
+				return
+			}
+			sse.SendEvent(Event{ID: "1", Name: "progress", Data: map[string]int{"step": 2}, Retry: 3 * time.Second})
+			sse.Send("", "line one\nline two")
+			sse.Send("bad\nname", nil)
+		}))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		if got := resp.Header.Get("Cache-Control"); got != "no-cache" {
+			t.Errorf("Cache-Control = %q", got)
+		}
+
+		events := readEvents(t, bufio.NewScanner(resp.Body), 3)
+		want := []string{
+			"id: 1\nevent: progress\nretry: 3000\ndata: {\"step\":2}",
+			"data: line one\ndata: line two",
+			"event: bad name\ndata: ",
+		}
+		for i := range want {
+			if events[i] != want[i] {
+				t.Errorf("event %d = %q, want %q", i, events[i], want[i])
+			}
+		}
+	})
+
+	t.Run("streams a channel with keepalives", func(t *testing.T) {
+		updates := make(chan int)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sse, err := NewSSEWriter(w, r)
+			if err != nil {
+				t.Errorf("NewSSEWriter() error = %v", err)
+				return
+			}
+			stop := sse.KeepAlive(10 * time.Millisecond)
+			defer stop()
+			if err := Stream(sse, updates, func(n int) Event { return Event{Name: "step", Data: n} }); err != nil {
+				t.Errorf("Stream() error = %v", err)
+			}
+		}))
+		defer srv.Close()
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			updates <- 1
+			updates <- 2
+			close(updates)
+		}()
+
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var steps []string
+		sawKeepAlive := false
+		scanner := bufio.NewScanner(resp.Body)
+		for len(steps) < 2 {
+			event := readEvents(t, scanner, 1)[0]
+			if event == ": keepalive" {
+				sawKeepAlive = true
+				continue
+			}
+			steps = append(steps, event)
+		}
+		if !sawKeepAlive {
+			t.Error("expected a keepalive comment while idle")
+		}
+		if steps[0] != "event: step\ndata: 1" || steps[1] != "event: step\ndata: 2" {
+			t.Errorf("unexpected events %q", steps)
+		}
+	})
+
+	t.Run("detects client disconnects", func(t *testing.T) {
+		result := make(chan error, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sse, err := NewSSEWriter(w, r)
+			if err != nil {
+				result <- err
+				return
+			}
+			sse.Send("ready", "")
+			result <- Stream(sse, make(chan int), func(n int) Event { return Event{Data: n} })
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readEvents(t, bufio.NewScanner(resp.Body), 1)
+		cancel()
+		resp.Body.Close()
+
+		select {
+		case err := <-result:
+			if !errors.Is(err, ErrClientDisconnected) {
+				t.Errorf("Stream() error = %v, want ErrClientDisconnected", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("handler did not notice the disconnect")
+		}
+	})
+
+	t.Run("requires a flushable response", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		w := struct{ http.ResponseWriter }{rec} // Hides the recorder's Flush
+		if _, err := NewSSEWriter(w, httptest.NewRequest(http.MethodGet, "/", nil)); !errors.Is(err, ErrStreamingUnsupported) {
+			t.Errorf("expected ErrStreamingUnsupported, got %v", err)
+		}
+		if rec.Header().Get("Content-Type") != "" {
+			t.Error("expected no stream headers on failure")
+		}
+	})
+}
@@ -0,0 +1,327 @@
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket message types.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+// WebSocket close codes sent with [WebSocket.Close].
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseTooLarge      = 1009
+	CloseInternalError = 1011
+)
+
+const (
+	opContinuation = 0
+	opClose        = 8
+	opPing         = 9
+	opPong         = 10
+
+	// maxMessageSize bounds incoming messages; control messages from browsers are tiny
+	maxMessageSize = 64 << 10
+	// websocketGUID is appended to the client key to compute the handshake accept value (RFC 6455 section 1.3)
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+var (
+	// ErrWebSocketClosed is returned by [WebSocket] reads and writes once the connection is closed.
+	ErrWebSocketClosed = errors.New("websocket closed")
+	// ErrBadHandshake is returned by [UpgradeWebSocket] for requests that are not a valid WebSocket upgrade.
+	ErrBadHandshake = errors.New("bad websocket handshake")
+	// ErrInvalidMessage is returned by [WebSocket.WriteMessage] for unknown message types.
+	ErrInvalidMessage = errors.New("invalid websocket message")
+)
+
+// WebSocket is a server-side WebSocket connection (RFC 6455), without extensions or subprotocols.
+//
+// Reads must come from a single goroutine; writes are safe for concurrent use. Pings are answered while reading.
+type WebSocket struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// UpgradeWebSocket completes a WebSocket handshake on a GET request and takes over its connection.
+//
+// Browsers send cookies with WebSocket requests from any site, so the Origin header, when present, must match the
+// request's host or one of allowedOrigins ("*" allows any); other origins are answered 403. Invalid upgrades are
+// answered 400 and fail with [ErrBadHandshake].
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request, allowedOrigins []string) (*WebSocket, error) {
+	if r.Method != http.MethodGet || !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Expected a WebSocket upgrade", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: not an upgrade request", ErrBadHandshake)
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrBadHandshake, r.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "Invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: invalid key", ErrBadHandshake)
+	}
+	if !originAllowed(r, allowedOrigins) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return nil, fmt.Errorf("%w: origin %s not allowed", ErrBadHandshake, r.Header.Get("Origin"))
+	}
+
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "WebSocket unsupported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("failed to take over connection: %w", err)
+	}
+	// The server's read and write deadlines do not apply to a hijacked connection's lifetime
+	conn.SetDeadline(time.Time{})
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := brw.WriteString(response); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write handshake: %w", err)
+	}
+	if err := brw.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write handshake: %w", err)
+	}
+	return &WebSocket{conn: conn, br: brw.Reader}, nil
+}
+
+// ReadMessage returns the next text or binary message, reassembling fragments and answering pings on the way.
+//
+// When the client closes the connection, the close is acknowledged and [ErrWebSocketClosed] returned.
+func (ws *WebSocket) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		message     []byte
+	)
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			ws.closeConn()
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code := CloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			ws.Close(code, "")
+			return 0, nil, ErrWebSocketClosed
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				ws.Close(CloseProtocolError, "expected a continuation frame")
+				return 0, nil, fmt.Errorf("%w: interleaved message", ErrWebSocketClosed)
+			}
+			messageType = int(opcode)
+		case opContinuation:
+			if messageType == 0 {
+				ws.Close(CloseProtocolError, "unexpected continuation frame")
+				return 0, nil, fmt.Errorf("%w: unexpected continuation", ErrWebSocketClosed)
+			}
+		default:
+			ws.Close(CloseProtocolError, "unknown opcode")
+			return 0, nil, fmt.Errorf("%w: unknown opcode %d", ErrWebSocketClosed, opcode)
+		}
+
+		if len(message)+len(payload) > maxMessageSize {
+			ws.Close(CloseTooLarge, "message too large")
+			return 0, nil, fmt.Errorf("%w: message over %d bytes", ErrWebSocketClosed, maxMessageSize)
+		}
+		message = append(message, payload...)
+		if fin {
+			return messageType, message, nil
+		}
+	}
+}
+
+// ReadJSON reads the next message and decodes it as JSON into v.
+func (ws *WebSocket) ReadJSON(v any) error {
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteMessage sends a text or binary message in a single frame.
+func (ws *WebSocket) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("%w: invalid message type %d", ErrInvalidMessage, messageType)
+	}
+	return ws.writeFrame(byte(messageType), data)
+}
+
+// WriteJSON sends v as a JSON text message.
+func (ws *WebSocket) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	return ws.WriteMessage(TextMessage, data)
+}
+
+// Ping sends a ping, which clients answer with a pong; used to keep idle connections open through proxies.
+func (ws *WebSocket) Ping() error {
+	return ws.writeFrame(opPing, nil)
+}
+
+// Close sends a close frame with code and reason, then closes the connection. Closing twice is a no-op.
+func (ws *WebSocket) Close(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+
+	err := ws.writeFrame(opClose, payload)
+	ws.closeConn()
+	if errors.Is(err, ErrWebSocketClosed) {
+		return nil
+	}
+	return err
+}
+
+// readFrame reads one frame, which must be masked as all client frames are
+func (ws *WebSocket) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.br, header[:]); err != nil {
+		return false, 0, nil, fmt.Errorf("%w: %v", ErrWebSocketClosed, err)
+	}
+	fin, opcode = header[0]&0x80 != 0, header[0]&0x0f
+	if header[0]&0x70 != 0 || header[1]&0x80 == 0 {
+		ws.Close(CloseProtocolError, "unmasked frame or reserved bits set")
+		return false, 0, nil, fmt.Errorf("%w: invalid frame header", ErrWebSocketClosed)
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("%w: %v", ErrWebSocketClosed, err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("%w: %v", ErrWebSocketClosed, err)
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageSize {
+		ws.Close(CloseTooLarge, "message too large")
+		return false, 0, nil, fmt.Errorf("%w: frame over %d bytes", ErrWebSocketClosed, maxMessageSize)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.br, mask[:]); err != nil {
+		return false, 0, nil, fmt.Errorf("%w: %v", ErrWebSocketClosed, err)
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(ws.br, payload); err != nil {
+		return false, 0, nil, fmt.Errorf("%w: %v", ErrWebSocketClosed, err)
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame sends one unmasked, unfragmented frame, as servers do
+func (ws *WebSocket) writeFrame(opcode byte, payload []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.closed {
+		return ErrWebSocketClosed
+	}
+
+	frame := make([]byte, 0, 10+len(payload))
+	frame = append(frame, 0x80|opcode)
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+
+	ws.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := ws.conn.Write(frame); err != nil {
+		return fmt.Errorf("%w: %v", ErrWebSocketClosed, err)
+	}
+	return nil
+}
+
+func (ws *WebSocket) closeConn() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if !ws.closed {
+		ws.closed = true
+		ws.conn.Close()
+	}
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHasToken reports whether a comma-separated header contains token, ignoring case
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for part := range strings.SplitSeq(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// originAllowed accepts requests without an Origin (non-browser clients), from the request's own host, or from
+// an allowed origin
+func originAllowed(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(allowed, "*") || slices.Contains(allowed, origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wsClient is a minimal client speaking the frames [WebSocket] expects
+type wsClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, srv *httptest.Server, header http.Header) (*wsClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &wsClient{conn: conn, br: br}, resp
+}
+
+// send writes a masked frame
+func (c *wsClient) send(t *testing.T, fin bool, opcode byte, payload []byte) {
+	t.Helper()
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{first, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read returns the opcode and payload of the next frame
+func (c *wsClient) read(t *testing.T) (byte, []byte) {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		t.Fatalf("failed to read frame: %v", err)
+	}
+	if header[1]&0x80 != 0 {
+		t.Fatal("server frames must not be masked")
+	}
+	length := int(header[1] & 0x7f)
+	if length == 126 {
+		var ext [2]byte
+		io.ReadFull(c.br, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0f, payload
+}
+
+func TestWebSocket(t *testing.T) {
+	closed := make(chan error, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := UpgradeWebSocket(w, r, []string{"https://app.example.com"})
+		if err != nil {
+			return
+		}
+		for {
+			messageType, data, err := ws.ReadMessage()
+			if err != nil {
+				closed <- err
+				return
+			}
+			ws.WriteMessage(messageType, append([]byte("echo: "), data...))
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("handshake and echo", func(t *testing.T) {
+		client, resp := dialWebSocket(t, srv, nil)
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("status %d, want 101", resp.StatusCode)
+		}
+		// The accept value for the RFC 6455 sample key
+		if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			t.Errorf("Sec-WebSocket-Accept = %q", got)
+		}
+
+		client.send(t, true, TextMessage, []byte("hello"))
+		if op, payload := client.read(t); op != TextMessage || string(payload) != "echo: hello" {
+			t.Errorf("got opcode %d %q", op, payload)
+		}
+
+		// Fragments are reassembled, with a ping answered in between
+		client.send(t, false, TextMessage, []byte("frag"))
+		client.send(t, true, opPing, []byte("are you there"))
+		client.send(t, true, opContinuation, []byte("mented"))
+		if op, payload := client.read(t); op != opPong || string(payload) != "are you there" {
+			t.Errorf("expected a pong, got opcode %d %q", op, payload)
+		}
+		if _, payload := client.read(t); string(payload) != "echo: fragmented" {
+			t.Errorf("got %q", payload)
+		}
+
+		client.send(t, true, opClose, []byte{0x03, 0xe8})
+		if op, payload := client.read(t); op != opClose || binary.BigEndian.Uint16(payload) != CloseNormal {
+			t.Errorf("expected the close acknowledged, got opcode %d %v", op, payload)
+		}
+		if err := <-closed; !errors.Is(err, ErrWebSocketClosed) {
+			t.Errorf("ReadMessage() error = %v, want ErrWebSocketClosed", err)
+		}
+	})
+
+	t.Run("rejects unmasked frames", func(t *testing.T) {
+		client, _ := dialWebSocket(t, srv, nil)
+		client.conn.Write([]byte{0x81, 0x02, 'h', 'i'})
+		if op, payload := client.read(t); op != opClose || binary.BigEndian.Uint16(payload) != CloseProtocolError {
+			t.Errorf("expected a protocol error close, got opcode %d %v", op, payload)
+		}
+		<-closed
+	})
+
+	t.Run("checks the origin", func(t *testing.T) {
+		for origin, want := range map[string]int{
+			"https://app.example.com":                http.StatusSwitchingProtocols,
+			"http://" + srv.Listener.Addr().String(): http.StatusSwitchingProtocols,
+			"https://evil.example.com":               http.StatusForbidden,
+		} {
+			_, resp := dialWebSocket(t, srv, http.Header{"Origin": {origin}})
+			if resp.StatusCode != want {
+				t.Errorf("origin %s: status %d, want %d", origin, resp.StatusCode, want)
+			}
+		}
+	})
+
+	t.Run("rejects plain requests", func(t *testing.T) {
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("status %d, want 400", resp.StatusCode)
+		}
+	})
+}
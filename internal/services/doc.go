@@ -4,6 +4,9 @@
 //
 // All music providers implement a common abstraction, enabling playlist operations to work uniformly across providers.
 //
+// AddTracks, RemoveTracks, and ReorderTracks modify existing playlists in place, so syncs can update a playlist
+// instead of creating a new one.
+//
 // # Spotify Implementation
 //
 // [SpotifyService] uses OAuth2 for authentication with automatic token refresh.
@@ -30,7 +33,9 @@
 //   - [shared.ErrNotAuthenticated] : Authenticate() not called
 //   - [shared.ErrTokenExpired] : OAuth token expired, reauthorization needed
 //   - [shared.ErrAPIRequest] : HTTP request failed
-//   - [shared.ErrPlaylistNotFound] : Playlist ID not found
+//   - [shared.ErrForbidden], [shared.ErrRateLimited] : Spotify answered 403 or 429, matched by a [SpotifyAPIError]
+//     carrying the reason from the response body
+//   - [shared.ErrPlaylistNotFound] : Playlist ID not found, matched by an API error for a 404 response
 //
 // # API Mappings
 //
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a GET response body stored with the validators needed to revalidate it.
+type CachedResponse struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Expires      time.Time `json:"expires"` // Served without a request until then, per Cache-Control max-age
+}
+
+// Fresh reports whether the response can be served without revalidating it.
+func (c CachedResponse) Fresh(now time.Time) bool {
+	return now.Before(c.Expires)
+}
+
+// ResponseCache stores GET responses by URL for [SpotifyService.SetResponseCache].
+//
+// Implementations must be safe for concurrent use. Failures are not reported: a cache miss just means a request.
+type ResponseCache interface {
+	Get(key string) (CachedResponse, bool)
+	Set(key string, response CachedResponse)
+	Clear() error
+}
+
+// cacheableResponse reports whether a response may be stored and until when it is fresh, per its Cache-Control
+// header. Responses without a validator or a max-age are not worth storing.
+func cacheableResponse(header http.Header, now time.Time) (time.Time, bool) {
+	var maxAge int
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store":
+			return time.Time{}, false
+		case "no-cache":
+			maxAge = 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+				maxAge = seconds
+			}
+		}
+	}
+
+	hasValidator := header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+	if maxAge == 0 && !hasValidator {
+		return time.Time{}, false
+	}
+	return now.Add(time.Duration(maxAge) * time.Second), true
+}
+
+// MemoryResponseCache keeps responses for the life of the process.
+type MemoryResponseCache struct {
+	entries map[string]CachedResponse
+	mu      sync.RWMutex
+}
+
+// NewMemoryResponseCache creates an empty in-memory [ResponseCache].
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{entries: map[string]CachedResponse{}}
+}
+
+func (c *MemoryResponseCache) Get(key string) (CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	response, ok := c.entries[key]
+	return response, ok
+}
+
+func (c *MemoryResponseCache) Set(key string, response CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = response
+}
+
+func (c *MemoryResponseCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]CachedResponse{}
+	return nil
+}
+
+// DiskResponseCache stores responses as JSON files in a directory, one per URL, so they outlive the process.
+type DiskResponseCache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewDiskResponseCache creates a [ResponseCache] in dir, which is created on the first write.
+func NewDiskResponseCache(dir string) *DiskResponseCache {
+	return &DiskResponseCache{dir: dir}
+}
+
+func (c *DiskResponseCache) Get(key string) (CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return CachedResponse{}, false
+	}
+	var response CachedResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return CachedResponse{}, false
+	}
+	return response, true
+}
+
+func (c *DiskResponseCache) Set(key string, response CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return
+	}
+	// Written to a temporary file first so a concurrent reader never sees a partial entry
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, c.path(key))
+}
+
+// Clear removes every cached response, leaving other files in the directory alone.
+func (c *DiskResponseCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to clear response cache: %w", err)
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to clear response cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// path returns the file of a cache key, named after its hash so URLs never need escaping
+func (c *DiskResponseCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
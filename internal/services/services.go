@@ -5,9 +5,17 @@ package services
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/charmbracelet/log"
 	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tracing"
 	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
 )
 
 // Service defines the interface for music service providers (Spotify, YouTube Music) that can export and import playlists and songs.
@@ -21,15 +29,174 @@ type Service interface {
 	// ExportPlaylist exports a playlist with all its tracks.
 	ExportPlaylist(ctx context.Context, playlistID string) (*models.PlaylistExport, error)
 	// ImportPlaylist imports a playlist into the service, by creating a new playlist and populates it with the provided tracks.
+	// Tracks must be added in slice order.
 	ImportPlaylist(ctx context.Context, playlist *models.PlaylistExport) (*models.Playlist, error)
 	// SearchTrack searches for a track by title and artist and returns the best match or an error if no match is found.
 	SearchTrack(ctx context.Context, title, artist string) (*models.Track, error)
+	// AddTracks appends tracks, identified by their service-specific IDs, to the end of a playlist in slice order.
+	AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error
+	// RemoveTracks removes every occurrence of the tracks, identified by their service-specific IDs, from a playlist.
+	RemoveTracks(ctx context.Context, playlistID string, tracks []models.Track) error
+	// ReorderTracks moves the rangeLength items starting at position rangeStart to before position insertBefore.
+	// Positions are zero-based; an insertBefore equal to the playlist length moves the items to the end.
+	ReorderTracks(ctx context.Context, playlistID string, rangeStart, rangeLength, insertBefore int) error
 	// Name returns the name of the service (e.g., "Spotify", "YouTube Music")
 	Name() string
 }
 
+// TrackSearcher is an optional extension of [Service] for services that can return several ranked candidates
+// for a track, best match first.
+type TrackSearcher interface {
+	SearchTracks(ctx context.Context, title, artist string, limit int) ([]models.Track, error)
+}
+
+// ISRCSearcher is an optional extension of [Service] for services that can look tracks up by their International
+// Standard Recording Code, returning every release of the recording, best match first.
+type ISRCSearcher interface {
+	SearchISRC(ctx context.Context, isrc string) ([]models.Track, error)
+}
+
+// PlaylistSizeLimiter is an optional extension of [Service] for services that cap the number of tracks a playlist
+// can hold.
+type PlaylistSizeLimiter interface {
+	MaxPlaylistSize() int
+}
+
+// PlaylistCoverSource is an optional extension of [Service] for services that expose a playlist's cover image.
+type PlaylistCoverSource interface {
+	// CoverImageURL returns the URL of the playlist's cover image.
+	CoverImageURL(ctx context.Context, playlistID string) (string, error)
+}
+
+// PlaylistCoverSetter is an optional extension of [Service] for services that can replace a playlist's cover image.
+type PlaylistCoverSetter interface {
+	// SetPlaylistCover uploads image, of the given MIME type, as the playlist's cover.
+	SetPlaylistCover(ctx context.Context, playlistID string, image []byte, contentType string) error
+}
+
+// PlaylistDeleter is an optional extension of [Service] for services that can delete playlists.
+type PlaylistDeleter interface {
+	DeletePlaylist(ctx context.Context, playlistID string) error
+}
+
+// PlaylistUpdate is a change to a playlist's metadata; nil fields are left as they are.
+type PlaylistUpdate struct {
+	Name        *string
+	Description *string
+	Public      *bool
+}
+
+// PlaylistEditor is an optional extension of [Service] for services that can change a playlist's metadata.
+type PlaylistEditor interface {
+	UpdatePlaylistMetadata(ctx context.Context, playlistID string, update PlaylistUpdate) error
+}
+
 type OAuthService interface {
-	GetAuthURL(state string) string
+	GetAuthURL(state string, opts ...oauth2.AuthCodeOption) string
 	GetOAuthConfig() *oauth2.Config
 	OAuthenticate(ctx context.Context, credentials *oauth2.Token) error
+	// UsesPKCE reports whether the authorization code flow must use PKCE, i.e. the client has no secret.
+	UsesPKCE() bool
+}
+
+// PKCE holds the code verifier of one Authorization Code with PKCE flow (RFC 7636).
+//
+// The challenge derived from the verifier is sent with the authorization request and the verifier itself with
+// the code exchange, so public clients can authenticate without a client secret.
+type PKCE struct {
+	Verifier string
+}
+
+// NewPKCE generates a random code verifier.
+func NewPKCE() PKCE {
+	return PKCE{Verifier: oauth2.GenerateVerifier()}
+}
+
+// Challenge returns the S256 code challenge for the verifier.
+func (p PKCE) Challenge() string {
+	return oauth2.S256ChallengeFromVerifier(p.Verifier)
+}
+
+// AuthURLOption adds the code challenge to an authorization URL.
+func (p PKCE) AuthURLOption() oauth2.AuthCodeOption {
+	return oauth2.S256ChallengeOption(p.Verifier)
+}
+
+// ExchangeOption adds the code verifier to an authorization code exchange.
+func (p PKCE) ExchangeOption() oauth2.AuthCodeOption {
+	return oauth2.VerifierOption(p.Verifier)
+}
+
+// RetryAfter reports whether err is a rate limit (429) response from either service, and how long the service asked
+// callers to wait before trying again; the wait is zero when it did not say.
+func RetryAfter(err error) (time.Duration, bool) {
+	if !errors.Is(err, shared.ErrRateLimited) {
+		return 0, false
+	}
+	var spotifyErr *SpotifyAPIError
+	if errors.As(err, &spotifyErr) {
+		return spotifyErr.RetryAfter, true
+	}
+	var youtubeErr *YouTubeAPIError
+	if errors.As(err, &youtubeErr) {
+		return youtubeErr.RetryAfter, true
+	}
+	return 0, true
+}
+
+// retryAfter parses a Retry-After header given in seconds, the form both Spotify and the proxy send
+func retryAfter(header http.Header) time.Duration {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// apiErrorIs matches the shared errors for an unsuccessful response's status code. A 404 matches
+// [shared.ErrPlaylistNotFound], since every request naming a playlist does so by its ID.
+func apiErrorIs(statusCode int, target error) bool {
+	switch target {
+	case shared.ErrAPIRequest:
+		return true
+	case shared.ErrPlaylistNotFound:
+		return statusCode == http.StatusNotFound
+	case shared.ErrForbidden:
+		return statusCode == http.StatusForbidden
+	case shared.ErrRateLimited:
+		return statusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// LoggingTransport wraps next (or [http.DefaultTransport] when nil) to log every request to service at debug level,
+// with credentials in the URL redacted.
+func LoggingTransport(logger *log.Logger, service string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return tracing.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			logger.Debug("request failed", "service", service, "method", req.Method, "url", req.URL.Redacted(), "duration", time.Since(start), "err", err)
+			return resp, err
+		}
+		logger.Debug("request", "service", service, "method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode, "duration", time.Since(start))
+		return resp, nil
+	})
+}
+
+// RateLimitedTransport wraps next (or [http.DefaultTransport] when nil) to wait for limiter before every request,
+// so callers can pace one job's requests and change the pace while it runs with [rate.Limiter.SetLimit].
+func RateLimitedTransport(limiter *rate.Limiter, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return tracing.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		return next.RoundTrip(req)
+	})
 }
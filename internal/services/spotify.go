@@ -4,12 +4,20 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
 	"github.com/desertthunder/ytx/internal/shared"
@@ -21,6 +29,13 @@ const (
 	spotifyTokenURL    = "https://accounts.spotify.com/api/token"
 	spotifyBaseURL     = "https://api.spotify.com/v1"
 	DefaultRedirectURI = "http://localhost:3000/callback"
+
+	// spotifySearchCandidates is how many results [SpotifyService.SearchTrack] ranks before picking the best.
+	spotifySearchCandidates = 5
+	spotifyMaxSearchLimit   = 50
+
+	// SpotifyMaxPlaylistSize is the most tracks a Spotify playlist can hold.
+	SpotifyMaxPlaylistSize = 10000
 )
 
 type followers struct {
@@ -49,37 +64,89 @@ type externalIDs struct {
 	ISRC string `json:"isrc"`
 }
 
+type externalURLs struct {
+	Spotify string `json:"spotify"`
+}
+
 // SpotifyTrack represents a Spotify track.
 type SpotifyTrack struct {
-	ID          string          `json:"id"`
-	Name        string          `json:"name"`
-	Artists     []SpotifyArtist `json:"artists"`
-	Album       SpotifyAlbum    `json:"album"`
-	DurationMS  int             `json:"duration_ms"`
-	Explicit    bool            `json:"explicit"`
-	ExternalIDs externalIDs     `json:"external_ids"`
-	Popularity  int             `json:"popularity"`
-	URI         string          `json:"uri"`
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Artists      []SpotifyArtist `json:"artists"`
+	Album        SpotifyAlbum    `json:"album"`
+	DurationMS   int             `json:"duration_ms"`
+	Explicit     bool            `json:"explicit"`
+	IsPlayable   *bool           `json:"is_playable,omitempty"` // Only reported for requests with a market
+	ExternalIDs  externalIDs     `json:"external_ids"`
+	ExternalURLs externalURLs    `json:"external_urls"` // Also set for podcast episodes, unlike a URL built from the ID
+	Popularity   int             `json:"popularity"`
+	URI          string          `json:"uri"`
 }
 
 // SpotifyArtist represents a Spotify artist.
+//
+// Followers and Popularity are only set on full artist objects.
 type SpotifyArtist struct {
-	ID     string         `json:"id"`
-	Name   string         `json:"name"`
-	Genres []string       `json:"genres"`
-	Images []SpotifyImage `json:"images"`
-	URI    string         `json:"uri"`
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Genres     []string       `json:"genres"`
+	Images     []SpotifyImage `json:"images"`
+	Followers  *followers     `json:"followers,omitempty"`
+	Popularity int            `json:"popularity,omitempty"`
+	URI        string         `json:"uri"`
 }
 
 // SpotifyAlbum represents a Spotify album.
+//
+// Label, Popularity and Tracks are only set on full album objects.
 type SpotifyAlbum struct {
-	ID          string          `json:"id"`
-	Name        string          `json:"name"`
-	Artists     []SpotifyArtist `json:"artists"`
-	ReleaseDate string          `json:"release_date"`
-	TotalTracks int             `json:"total_tracks"`
-	Images      []SpotifyImage  `json:"images"`
-	URI         string          `json:"uri"`
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	AlbumType   string              `json:"album_type"`
+	Artists     []SpotifyArtist     `json:"artists"`
+	ReleaseDate string              `json:"release_date"`
+	TotalTracks int                 `json:"total_tracks"`
+	Label       string              `json:"label,omitempty"`
+	Popularity  int                 `json:"popularity,omitempty"`
+	Images      []SpotifyImage      `json:"images"`
+	Tracks      *SpotifyAlbumTracks `json:"tracks,omitempty"`
+	URI         string              `json:"uri"`
+}
+
+// SpotifyAlbumTracks represents a page of an album's tracks. Album tracks have no album field.
+type SpotifyAlbumTracks struct {
+	Items  []SpotifyTrack `json:"items"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	Next   *string        `json:"next"`
+}
+
+// SpotifySavedAlbum is an album in the user's library.
+type SpotifySavedAlbum struct {
+	AddedAt string       `json:"added_at"`
+	Album   SpotifyAlbum `json:"album"`
+}
+
+// SpotifyPaginatedAlbums represents a page of saved albums.
+type SpotifyPaginatedAlbums struct {
+	Items    []SpotifySavedAlbum `json:"items"`
+	Total    int                 `json:"total"`
+	Limit    int                 `json:"limit"`
+	Offset   int                 `json:"offset"`
+	Next     *string             `json:"next"`
+	Previous *string             `json:"previous"`
+}
+
+// SpotifyFollowedArtists represents a cursor-paginated page of followed artists.
+type SpotifyFollowedArtists struct {
+	Items   []SpotifyArtist `json:"items"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Next    *string         `json:"next"`
+	Cursors struct {
+		After string `json:"after"`
+	} `json:"cursors"`
 }
 
 type Owner struct {
@@ -87,6 +154,11 @@ type Owner struct {
 	DisplayName string `json:"display_name"`
 }
 
+// name returns the owner's display name, or their user ID when they have none
+func (o Owner) name() string {
+	return cmp.Or(o.DisplayName, o.ID)
+}
+
 type playlistTrack struct {
 	Total int                    `json:"total"`
 	Items []SpotifyPlaylistTrack `json:"items"`
@@ -94,14 +166,31 @@ type playlistTrack struct {
 
 // SpotifyPlaylist represents a Spotify playlist.
 type SpotifyPlaylist struct {
-	ID          string         `json:"id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Owner       Owner          `json:"owner"`
-	Public      bool           `json:"public"`
-	Tracks      playlistTrack  `json:"tracks"`
-	Images      []SpotifyImage `json:"images"`
-	URI         string         `json:"uri"`
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	Owner         Owner          `json:"owner"`
+	Public        bool           `json:"public"`
+	Collaborative bool           `json:"collaborative"`
+	Followers     followers      `json:"followers"`
+	Tracks        playlistTrack  `json:"tracks"`
+	Images        []SpotifyImage `json:"images"`
+	URI           string         `json:"uri"`
+}
+
+// toPlaylist maps the playlist to a [models.Playlist], without its tracks
+func (sp SpotifyPlaylist) toPlaylist() models.Playlist {
+	return models.Playlist{
+		ID:            sp.ID,
+		Name:          sp.Name,
+		Description:   sp.Description,
+		TrackCount:    sp.Tracks.Total,
+		Public:        sp.Public,
+		ExternalURL:   models.PlaylistURL("spotify", sp.ID),
+		Owner:         sp.Owner.name(),
+		Collaborative: sp.Collaborative,
+		Followers:     sp.Followers.Total,
+	}
 }
 
 // SpotifyPlaylistTrack represents a track within a playlist context.
@@ -140,22 +229,25 @@ type simplePlaylistTrack struct {
 	Total int `json:"total"`
 }
 
-// SpotifySimplePlaylist represents a simplified playlist object (used in lists).
+// SpotifySimplePlaylist represents a simplified playlist object (used in lists). Unlike [SpotifyPlaylist], it
+// has no follower count.
 type SpotifySimplePlaylist struct {
-	ID          string              `json:"id"`
-	Name        string              `json:"name"`
-	Description string              `json:"description"`
-	Owner       Owner               `json:"owner"`
-	Public      bool                `json:"public"`
-	Tracks      simplePlaylistTrack `json:"tracks"`
-	Images      []SpotifyImage      `json:"images"`
-	URI         string              `json:"uri"`
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Description   string              `json:"description"`
+	Owner         Owner               `json:"owner"`
+	Public        bool                `json:"public"`
+	Collaborative bool                `json:"collaborative"`
+	Tracks        simplePlaylistTrack `json:"tracks"`
+	Images        []SpotifyImage      `json:"images"`
+	URI           string              `json:"uri"`
 }
 
 type createPlaylistReq struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Public      bool   `json:"public"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Public        bool   `json:"public"`
+	Collaborative bool   `json:"collaborative,omitempty"`
 }
 
 // SpotifySearchResults represents the response from Spotify's search API.
@@ -197,9 +289,14 @@ func (r *refreshableTokenSource) Token() (*oauth2.Token, error) {
 //
 // Uses [oauth2] for authentication and provides methods for playlist and track operations.
 type SpotifyService struct {
+	baseURL        string
+	market         string // ISO 3166-1 alpha-2 country code or "from_token"; empty for no market filter
 	config         *oauth2.Config
 	token          *oauth2.Token
 	httpClient     *http.Client
+	baseClient     *http.Client  // Optional: carries API and token requests beneath the OAuth2 transport
+	responseCache  ResponseCache // Optional: GET responses are reused and revalidated if provided
+	revalidate     atomic.Bool   // Set after a write so fresh cached responses are revalidated anyway
 	credentials    map[string]string
 	onTokenRefresh tokenRefreshCallback
 }
@@ -209,16 +306,47 @@ func (s *SpotifyService) SetTokenRefreshCallback(callback tokenRefreshCallback)
 	s.onTokenRefresh = callback
 }
 
+// SetHTTPClient sets the client that API and token requests are sent through, e.g. to instrument its transport.
+//
+// Call it before authenticating: the OAuth2 client built on authentication wraps it.
+func (s *SpotifyService) SetHTTPClient(client *http.Client) {
+	s.baseClient = client
+}
+
+// SetResponseCache enables caching of GET responses, keyed by URL and honoring Cache-Control and ETag.
+//
+// Fresh responses are served without a request; stale ones are revalidated with If-None-Match and reused on a
+// 304 Not Modified. After any write through this service, cached responses are always revalidated.
+func (s *SpotifyService) SetResponseCache(cache ResponseCache) {
+	s.responseCache = cache
+}
+
+// clientContext makes the base client, if set, available to [oauth2] token sources and clients
+func (s *SpotifyService) clientContext(ctx context.Context) context.Context {
+	if s.baseClient == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, s.baseClient)
+}
+
 // NewSpotifyService creates a new Spotify service with the given OAuth2 credentials.
+//
+// Without a client_secret the service acts as a public client: authorization must use PKCE and the client ID
+// is sent in the body of token requests.
 func NewSpotifyService(credentials map[string]string) (*SpotifyService, error) {
 	clientID, ok := credentials["client_id"]
 	if !ok || clientID == "" {
 		return nil, fmt.Errorf("missing client_id in credentials")
 	}
 
-	clientSecret, ok := credentials["client_secret"]
-	if !ok || clientSecret == "" {
-		return nil, fmt.Errorf("missing client_secret in credentials")
+	clientSecret := credentials["client_secret"]
+
+	market := strings.TrimSpace(credentials["market"])
+	if market != "" && market != "from_token" {
+		market = strings.ToUpper(market)
+		if len(market) != 2 {
+			return nil, fmt.Errorf("invalid market '%s': use an ISO 3166-1 alpha-2 country code or from_token", credentials["market"])
+		}
 	}
 
 	redirectURI, ok := credentials["redirect_uri"]
@@ -238,15 +366,21 @@ func NewSpotifyService(credentials map[string]string) (*SpotifyService, error) {
 			"playlist-modify-public",
 			"playlist-modify-private",
 			"user-library-read",
+			"user-follow-read",
 		},
 		Endpoint: oauth2.Endpoint{
 			AuthURL:  spotifyAuthURL,
 			TokenURL: spotifyTokenURL,
 		},
 	}
+	if clientSecret == "" {
+		config.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	}
 
 	return &SpotifyService{
 		config:      config,
+		baseURL:     spotifyBaseURL,
+		market:      market,
 		httpClient:  http.DefaultClient,
 		credentials: credentials,
 	}, nil
@@ -254,7 +388,8 @@ func NewSpotifyService(credentials map[string]string) (*SpotifyService, error) {
 
 // Authenticate performs OAuth2 authentication with Spotify.
 //
-// Expects either an "access_token" or "auth_code" in credentials. Optionally accepts a "refresh_token" to enable automatic token refresh.
+// Expects either an "access_token" or "auth_code" in credentials. Optionally accepts a "refresh_token" to enable automatic token refresh,
+// and a "code_verifier" to exchange an auth code obtained with PKCE.
 func (s *SpotifyService) Authenticate(ctx context.Context, credentials map[string]string) error {
 	if accessToken, ok := credentials["access_token"]; ok && accessToken != "" {
 		s.token = &oauth2.Token{
@@ -266,7 +401,12 @@ func (s *SpotifyService) Authenticate(ctx context.Context, credentials map[strin
 	}
 
 	if authCode, ok := credentials["auth_code"]; ok && authCode != "" {
-		token, err := s.config.Exchange(ctx, authCode)
+		var opts []oauth2.AuthCodeOption
+		if verifier := credentials["code_verifier"]; verifier != "" {
+			opts = append(opts, PKCE{Verifier: verifier}.ExchangeOption())
+		}
+
+		token, err := s.config.Exchange(s.clientContext(ctx), authCode, opts...)
 		if err != nil {
 			return fmt.Errorf("failed to exchange auth code: %w", err)
 		}
@@ -280,6 +420,7 @@ func (s *SpotifyService) Authenticate(ctx context.Context, credentials map[strin
 
 // createClientWithRefreshCallback creates an HTTP client with a TokenSource that captures token refreshes
 func (s *SpotifyService) createClientWithRefreshCallback(ctx context.Context, token *oauth2.Token) *http.Client {
+	ctx = s.clientContext(ctx)
 	tokenSource := s.config.TokenSource(ctx, token)
 
 	if s.onTokenRefresh != nil {
@@ -297,14 +438,26 @@ func (s *SpotifyService) Name() string {
 	return "Spotify"
 }
 
+// MaxPlaylistSize returns the most tracks a Spotify playlist can hold.
+func (s *SpotifyService) MaxPlaylistSize() int {
+	return SpotifyMaxPlaylistSize
+}
+
 // GetOAuthConfig returns the OAuth2 config for external use (e.g., OAuth handler).
 func (s *SpotifyService) GetOAuthConfig() *oauth2.Config {
 	return s.config
 }
 
 // GetAuthURL returns the OAuth2 authorization URL for user login.
-func (s *SpotifyService) GetAuthURL(state string) string {
-	return s.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+//
+// Pass [PKCE.AuthURLOption] when [SpotifyService.UsesPKCE] reports true.
+func (s *SpotifyService) GetAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	return s.config.AuthCodeURL(state, append([]oauth2.AuthCodeOption{oauth2.AccessTypeOffline}, opts...)...)
+}
+
+// UsesPKCE reports whether the service was created without a client secret.
+func (s *SpotifyService) UsesPKCE() bool {
+	return s.config.ClientSecret == ""
 }
 
 // GetToken returns the current OAuth2 token (may have been refreshed automatically).
@@ -323,6 +476,29 @@ func (s *SpotifyService) OAuthenticate(ctx context.Context, token *oauth2.Token)
 	return nil
 }
 
+// RefreshToken exchanges the stored refresh token for a new access token.
+//
+// The token refresh callback is invoked with the new token, as for refreshes made during requests.
+func (s *SpotifyService) RefreshToken(ctx context.Context) error {
+	if s.token == nil || s.token.RefreshToken == "" {
+		return fmt.Errorf("%w: no refresh token stored", shared.ErrNoRefreshToken)
+	}
+
+	expired := *s.token
+	expired.Expiry = time.Now().Add(-time.Minute)
+	token, err := s.config.TokenSource(s.clientContext(ctx), &expired).Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrRefreshFailed, err)
+	}
+
+	s.token = token
+	s.httpClient = s.createClientWithRefreshCallback(ctx, token)
+	if s.onTokenRefresh != nil {
+		s.onTokenRefresh(token)
+	}
+	return nil
+}
+
 // doRequest performs an authenticated HTTP request to the Spotify API.
 // The oauth2 client automatically handles token refresh on 401 responses.
 func (s *SpotifyService) doRequest(ctx context.Context, method, endpoint string, body any, result any) error {
@@ -330,7 +506,7 @@ func (s *SpotifyService) doRequest(ctx context.Context, method, endpoint string,
 		return fmt.Errorf("%w: call Authenticate first", shared.ErrNotAuthenticated)
 	}
 
-	apiURL := spotifyBaseURL + endpoint
+	apiURL := s.baseURL + endpoint
 
 	var req *http.Request
 	var err error
@@ -353,6 +529,25 @@ func (s *SpotifyService) doRequest(ctx context.Context, method, endpoint string,
 
 	req.Header.Set("Content-Type", "application/json")
 
+	cache := s.responseCache
+	if method != http.MethodGet {
+		cache = nil
+	}
+	cached, hit := CachedResponse{}, false
+	if cache != nil {
+		if cached, hit = cache.Get(apiURL); hit {
+			if !s.revalidate.Load() && cached.Fresh(time.Now()) {
+				return decodeResponse(cached.Body, result)
+			}
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+	}
+
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
@@ -363,16 +558,113 @@ func (s *SpotifyService) doRequest(ctx context.Context, method, endpoint string,
 		return fmt.Errorf("%w: %s", shared.ErrTokenExpired, "Spotify returned 401 - reauthorization required")
 	}
 
+	if resp.StatusCode == http.StatusNotModified && hit {
+		if expires, ok := cacheableResponse(resp.Header, time.Now()); ok {
+			cached.Expires = expires
+			cache.Set(apiURL, cached)
+		}
+		return decodeResponse(cached.Body, result)
+	}
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("spotify API error: status %d", resp.StatusCode)
+		return parseSpotifyError(resp)
+	}
+	if method != http.MethodGet {
+		s.revalidate.Store(true)
 	}
 
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	if cache == nil {
+		if result != nil {
+			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
 		}
+		return nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if expires, ok := cacheableResponse(resp.Header, time.Now()); ok {
+		cache.Set(apiURL, CachedResponse{
+			Body:         data,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Expires:      expires,
+		})
+	}
+	return decodeResponse(data, result)
+}
+
+// SpotifyAPIError is an unsuccessful Spotify Web API response, with the reason given in its error body.
+//
+// It matches [shared.ErrAPIRequest] with errors.Is, and also [shared.ErrForbidden] for 403 responses (a missing
+// scope, a market restriction) and [shared.ErrRateLimited] for 429 responses.
+type SpotifyAPIError struct {
+	StatusCode int
+	Message    string        // error.message (or error_description) from the body; empty when there was none
+	Reason     string        // error.reason, given by some 403 responses
+	RetryAfter time.Duration // From the Retry-After header, for 429 responses
+}
+
+func (e *SpotifyAPIError) Error() string {
+	msg := fmt.Sprintf("spotify API error: status %d", e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Reason != "" {
+		msg += fmt.Sprintf(" (%s)", e.Reason)
+	}
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg
+}
+
+func (e *SpotifyAPIError) Is(target error) bool {
+	return apiErrorIs(e.StatusCode, target)
+}
+
+// parseSpotifyError builds a [SpotifyAPIError] from a response, reading its body as either the Web API's
+// {"error": {"status", "message", "reason"}} or the accounts service's {"error", "error_description"}.
+func parseSpotifyError(resp *http.Response) *SpotifyAPIError {
+	apiErr := &SpotifyAPIError{StatusCode: resp.StatusCode, RetryAfter: retryAfter(resp.Header)}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return apiErr
+	}
+	var body struct {
+		Error       json.RawMessage `json:"error"`
+		Description string          `json:"error_description"`
+	}
+	if json.Unmarshal(data, &body) != nil || len(body.Error) == 0 {
+		return apiErr
 	}
 
+	var detail struct {
+		Message string `json:"message"`
+		Reason  string `json:"reason"`
+	}
+	var code string
+	switch {
+	case json.Unmarshal(body.Error, &detail) == nil:
+		apiErr.Message, apiErr.Reason = detail.Message, detail.Reason
+	case json.Unmarshal(body.Error, &code) == nil:
+		apiErr.Message = cmp.Or(body.Description, code)
+	}
+	return apiErr
+}
+
+// decodeResponse decodes a response body read in full into result, unless result is nil
+func decodeResponse(data []byte, result any) error {
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
 	return nil
 }
 
@@ -437,6 +729,35 @@ func (s *SpotifyService) SavedTracks(ctx context.Context, limit, offset int) (*S
 	return &response, nil
 }
 
+// LikedSongs retrieves every track saved in the user's library, most recently saved first, with AddedAt set to when
+// it was saved.
+//
+// Requires OAuth scope: user-library-read
+func (s *SpotifyService) LikedSongs(ctx context.Context) ([]models.Track, error) {
+	const limit = 50
+
+	var tracks []models.Track
+	for offset := 0; ; offset += limit {
+		page, err := s.SavedTracks(ctx, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			if item.Track.ID == "" {
+				continue
+			}
+			track := spotifyTrackModel(item.Track)
+			if addedAt, err := time.Parse(time.RFC3339, item.AddedAt); err == nil {
+				track.AddedAt = addedAt
+			}
+			tracks = append(tracks, track)
+		}
+		if page.Next == nil || len(page.Items) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
 // UserPlaylists retrieves the current user's playlists with pagination.
 func (s *SpotifyService) UserPlaylists(ctx context.Context, limit, offset int) (*SpotifyPaginatedPlaylists, error) {
 	if limit <= 0 {
@@ -458,7 +779,8 @@ func (s *SpotifyService) UserPlaylists(ctx context.Context, limit, offset int) (
 
 // Playlist retrieves a playlist by ID.
 func (s *SpotifyService) Playlist(ctx context.Context, playlistID string) (*SpotifyPlaylist, error) {
-	endpoint := fmt.Sprintf("/playlists/%s", playlistID)
+	// Episodes are requested explicitly so they come back as episodes rather than being dropped
+	endpoint := fmt.Sprintf("/playlists/%s?additional_types=track,episode", playlistID)
 
 	var playlist SpotifyPlaylist
 	if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &playlist); err != nil {
@@ -468,59 +790,429 @@ func (s *SpotifyService) Playlist(ctx context.Context, playlistID string) (*Spot
 	return &playlist, nil
 }
 
-// Album retrieves an album by ID (stub for future implementation).
+// CoverImageURL returns the URL of a playlist's cover image.
+func (s *SpotifyService) CoverImageURL(ctx context.Context, playlistID string) (string, error) {
+	pl, err := s.Playlist(ctx, playlistID)
+	if err != nil {
+		return "", err
+	}
+	if len(pl.Images) == 0 {
+		return "", fmt.Errorf("no image available")
+	}
+	return pl.Images[0].URL, nil
+}
+
+// Spotify batch limits for the several-items endpoints.
+const (
+	spotifySeveralAlbumsLimit  = 20
+	spotifySeveralArtistsLimit = 50
+	spotifyAlbumTracksLimit    = 50
+	spotifySeveralTracksLimit  = 50
+	spotifyAudioFeaturesLimit  = 100
+)
+
+// Album retrieves an album by ID, including every page of its tracks.
 func (s *SpotifyService) Album(ctx context.Context, albumID string) (*SpotifyAlbum, error) {
-	// TODO: implement album retrieval
-	return nil, shared.ErrNotImplemented
+	if albumID == "" {
+		return nil, fmt.Errorf("%w: album ID", shared.ErrMissingArgument)
+	}
+
+	var album SpotifyAlbum
+	endpoint := fmt.Sprintf("/albums/%s%s", url.PathEscape(albumID), s.marketQuery("?"))
+	if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &album); err != nil {
+		return nil, err
+	}
+
+	if err := s.fillAlbumTracks(ctx, &album); err != nil {
+		return nil, err
+	}
+	return &album, nil
 }
 
-// SeveralAlbums retrieves multiple albums by IDs (stub for future implementation).
+// SeveralAlbums retrieves albums by ID, including every page of their tracks.
+//
+// IDs are requested in batches of 20. Unknown IDs are skipped.
 func (s *SpotifyService) SeveralAlbums(ctx context.Context, albumIDs []string) ([]SpotifyAlbum, error) {
-	// TODO: implement multiple album retrieval
-	return nil, shared.ErrNotImplemented
+	if len(albumIDs) == 0 {
+		return nil, fmt.Errorf("no album IDs provided")
+	}
+
+	var albums []SpotifyAlbum
+	for start := 0; start < len(albumIDs); start += spotifySeveralAlbumsLimit {
+		end := min(start+spotifySeveralAlbumsLimit, len(albumIDs))
+		ids := strings.Join(albumIDs[start:end], ",")
+		endpoint := fmt.Sprintf("/albums?ids=%s%s", url.QueryEscape(ids), s.marketQuery("&"))
+
+		var response struct {
+			Albums []*SpotifyAlbum `json:"albums"`
+		}
+		if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, album := range response.Albums {
+			if album == nil {
+				continue
+			}
+			if err := s.fillAlbumTracks(ctx, album); err != nil {
+				return nil, err
+			}
+			albums = append(albums, *album)
+		}
+	}
+
+	return albums, nil
+}
+
+// fillAlbumTracks fetches the album track pages missing from a full album object.
+func (s *SpotifyService) fillAlbumTracks(ctx context.Context, album *SpotifyAlbum) error {
+	if album.Tracks == nil {
+		return nil
+	}
+
+	for album.Tracks.Next != nil && len(album.Tracks.Items) < album.Tracks.Total {
+		endpoint := fmt.Sprintf("/albums/%s/tracks?limit=%d&offset=%d%s",
+			url.PathEscape(album.ID), spotifyAlbumTracksLimit, len(album.Tracks.Items), s.marketQuery("&"))
+
+		var page SpotifyAlbumTracks
+		if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &page); err != nil {
+			return fmt.Errorf("failed to get tracks of album %s: %w", album.ID, err)
+		}
+		if len(page.Items) == 0 {
+			break
+		}
+
+		album.Tracks.Items = append(album.Tracks.Items, page.Items...)
+		album.Tracks.Next = page.Next
+	}
+
+	album.Tracks.Next = nil
+	return nil
 }
 
-// Artist retrieves an artist by ID (stub for future implementation).
+// Artist retrieves an artist by ID.
 func (s *SpotifyService) Artist(ctx context.Context, artistID string) (*SpotifyArtist, error) {
-	// TODO: implement artist retrieval
-	return nil, shared.ErrNotImplemented
+	if artistID == "" {
+		return nil, fmt.Errorf("%w: artist ID", shared.ErrMissingArgument)
+	}
+
+	var artist SpotifyArtist
+	endpoint := fmt.Sprintf("/artists/%s", url.PathEscape(artistID))
+	if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &artist); err != nil {
+		return nil, err
+	}
+	return &artist, nil
 }
 
-// SeveralArtists retrieves multiple artists by IDs (stub for future implementation).
+// SeveralArtists retrieves artists by ID.
+//
+// IDs are requested in batches of 50. Unknown IDs are skipped.
 func (s *SpotifyService) SeveralArtists(ctx context.Context, artistIDs []string) ([]SpotifyArtist, error) {
-	// TODO: implement multiple artist retrieval
-	return nil, shared.ErrNotImplemented
+	if len(artistIDs) == 0 {
+		return nil, fmt.Errorf("no artist IDs provided")
+	}
+
+	var artists []SpotifyArtist
+	for start := 0; start < len(artistIDs); start += spotifySeveralArtistsLimit {
+		end := min(start+spotifySeveralArtistsLimit, len(artistIDs))
+		endpoint := fmt.Sprintf("/artists?ids=%s", url.QueryEscape(strings.Join(artistIDs[start:end], ",")))
+
+		var response struct {
+			Artists []*SpotifyArtist `json:"artists"`
+		}
+		if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, artist := range response.Artists {
+			if artist != nil {
+				artists = append(artists, *artist)
+			}
+		}
+	}
+
+	return artists, nil
+}
+
+// SpotifyAudioFeatures represents the audio features of a track.
+type SpotifyAudioFeatures struct {
+	ID               string  `json:"id"`
+	Danceability     float64 `json:"danceability"`
+	Energy           float64 `json:"energy"`
+	Valence          float64 `json:"valence"`
+	Acousticness     float64 `json:"acousticness"`
+	Instrumentalness float64 `json:"instrumentalness"`
+	Speechiness      float64 `json:"speechiness"`
+	Liveness         float64 `json:"liveness"`
+	Loudness         float64 `json:"loudness"`
+	Tempo            float64 `json:"tempo"`
+}
+
+// AudioFeatures retrieves the audio features of tracks by ID.
+//
+// IDs are requested in batches of 100. Tracks Spotify has no features for are skipped.
+func (s *SpotifyService) AudioFeatures(ctx context.Context, trackIDs []string) ([]SpotifyAudioFeatures, error) {
+	if len(trackIDs) == 0 {
+		return nil, fmt.Errorf("no track IDs provided")
+	}
+
+	var features []SpotifyAudioFeatures
+	for start := 0; start < len(trackIDs); start += spotifyAudioFeaturesLimit {
+		end := min(start+spotifyAudioFeaturesLimit, len(trackIDs))
+		endpoint := fmt.Sprintf("/audio-features?ids=%s", url.QueryEscape(strings.Join(trackIDs[start:end], ",")))
+
+		var response struct {
+			AudioFeatures []*SpotifyAudioFeatures `json:"audio_features"`
+		}
+		if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, f := range response.AudioFeatures {
+			if f != nil {
+				features = append(features, *f)
+			}
+		}
+	}
+
+	return features, nil
+}
+
+// EnrichTracks fills in the Genres and Features of Spotify tracks, looking up their artists' genres and their audio
+// features. Tracks without an ID, such as local files and podcast episodes, are left as they are.
+//
+// Each 50 tracks cost a request for their artists and another per 50 distinct artists, plus one per 100 tracks for
+// audio features. Genres are kept when only the audio features fail, as they do for Spotify apps created after
+// the endpoint was deprecated in November 2024; the error is still returned.
+func (s *SpotifyService) EnrichTracks(ctx context.Context, tracks []models.Track) error {
+	var trackIDs []string
+	seen := map[string]bool{}
+	for _, track := range tracks {
+		if track.ID != "" && !strings.HasPrefix(track.URI, "spotify:episode:") && !seen[track.ID] {
+			seen[track.ID] = true
+			trackIDs = append(trackIDs, track.ID)
+		}
+	}
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	artistsOf := map[string][]string{}
+	var artistIDs []string
+	seenArtists := map[string]bool{}
+	for start := 0; start < len(trackIDs); start += spotifySeveralTracksLimit {
+		batch, err := s.SeveralTracks(ctx, trackIDs[start:min(start+spotifySeveralTracksLimit, len(trackIDs))])
+		if err != nil {
+			return fmt.Errorf("failed to get tracks: %w", err)
+		}
+		for _, track := range batch {
+			for _, artist := range track.Artists {
+				if artist.ID == "" {
+					continue
+				}
+				artistsOf[track.ID] = append(artistsOf[track.ID], artist.ID)
+				if !seenArtists[artist.ID] {
+					seenArtists[artist.ID] = true
+					artistIDs = append(artistIDs, artist.ID)
+				}
+			}
+		}
+	}
+
+	genresOf := map[string][]string{}
+	if len(artistIDs) > 0 {
+		artists, err := s.SeveralArtists(ctx, artistIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get artists: %w", err)
+		}
+		for _, artist := range artists {
+			genresOf[artist.ID] = artist.Genres
+		}
+	}
+	for i := range tracks {
+		var genres []string
+		for _, artistID := range artistsOf[tracks[i].ID] {
+			for _, genre := range genresOf[artistID] {
+				if !slices.Contains(genres, genre) {
+					genres = append(genres, genre)
+				}
+			}
+		}
+		tracks[i].Genres = genres
+	}
+
+	features, err := s.AudioFeatures(ctx, trackIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get audio features: %w", err)
+	}
+	featuresOf := make(map[string]*models.AudioFeatures, len(features))
+	for _, f := range features {
+		featuresOf[f.ID] = &models.AudioFeatures{
+			Danceability:     f.Danceability,
+			Energy:           f.Energy,
+			Valence:          f.Valence,
+			Acousticness:     f.Acousticness,
+			Instrumentalness: f.Instrumentalness,
+			Speechiness:      f.Speechiness,
+			Liveness:         f.Liveness,
+			Loudness:         f.Loudness,
+			Tempo:            f.Tempo,
+		}
+	}
+	for i := range tracks {
+		if f, ok := featuresOf[tracks[i].ID]; ok {
+			tracks[i].Features = f
+		}
+	}
+	return nil
+}
+
+// SavedAlbums retrieves a page of albums saved in the user's library.
+//
+// Requires OAuth scope: user-library-read
+func (s *SpotifyService) SavedAlbums(ctx context.Context, limit, offset int) (*SpotifyPaginatedAlbums, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	endpoint := fmt.Sprintf("/me/albums?limit=%d&offset=%d%s", limit, offset, s.marketQuery("&"))
+
+	var response SpotifyPaginatedAlbums
+	if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// FollowedArtists retrieves a page of artists the user follows, starting after the given artist ID.
+//
+// The page's Cursors.After is the cursor for the next page and is empty on the last one.
+//
+// Requires OAuth scope: user-follow-read
+func (s *SpotifyService) FollowedArtists(ctx context.Context, limit int, after string) (*SpotifyFollowedArtists, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	endpoint := fmt.Sprintf("/me/following?type=artist&limit=%d", limit)
+	if after != "" {
+		endpoint += "&after=" + url.QueryEscape(after)
+	}
+
+	var response struct {
+		Artists SpotifyFollowedArtists `json:"artists"`
+	}
+	if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Artists, nil
+}
+
+// marketQuery returns the market query parameter prefixed with sep, or an empty string without a market.
+func (s *SpotifyService) marketQuery(sep string) string {
+	if s.market == "" {
+		return ""
+	}
+	return sep + "market=" + url.QueryEscape(s.market)
 }
 
+// playlistPageWorkers bounds how many pages of the user's playlists are fetched at once.
+const playlistPageWorkers = 4
+
 // GetPlaylists retrieves all playlists for the authenticated user.
+//
+// The first page gives the total, after which the remaining pages are fetched concurrently and merged in order.
 func (s *SpotifyService) GetPlaylists(ctx context.Context) ([]models.Playlist, error) {
-	var allPlaylists []models.Playlist
-	limit := 50
-	offset := 0
+	const limit = 50
+
+	first, err := s.UserPlaylists(ctx, limit, 0)
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		response, err := s.UserPlaylists(ctx, limit, offset)
+	pages := [][]SpotifySimplePlaylist{first.Items}
+	if first.Next != nil && first.Total > limit {
+		rest, err := s.playlistPages(ctx, limit, (first.Total-1)/limit)
 		if err != nil {
 			return nil, err
 		}
+		pages = append(pages, rest...)
+	}
 
-		for _, sp := range response.Items {
+	allPlaylists := make([]models.Playlist, 0, first.Total)
+	for _, page := range pages {
+		for _, sp := range page {
 			allPlaylists = append(allPlaylists, models.Playlist{
-				ID:          sp.ID,
-				Name:        sp.Name,
-				Description: sp.Description,
-				TrackCount:  sp.Tracks.Total,
-				Public:      sp.Public,
+				ID:            sp.ID,
+				Name:          sp.Name,
+				Description:   sp.Description,
+				TrackCount:    sp.Tracks.Total,
+				Public:        sp.Public,
+				ExternalURL:   models.PlaylistURL("spotify", sp.ID),
+				Owner:         sp.Owner.name(),
+				Collaborative: sp.Collaborative,
 			})
 		}
+	}
+	return allPlaylists, nil
+}
+
+// playlistPages fetches count pages of the user's playlists following the first, at most [playlistPageWorkers] at
+// a time. Pages are returned in order; the first failure cancels the pages still in flight.
+func (s *SpotifyService) playlistPages(ctx context.Context, limit, count int) ([][]SpotifySimplePlaylist, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]SpotifySimplePlaylist, count)
+	indexes := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for range min(playlistPageWorkers, count) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				response, err := s.UserPlaylists(ctx, limit, (i+1)*limit)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				pages[i] = response.Items
+			}
+		}()
+	}
 
-		if response.Next == nil {
+	for i := range count {
+		if ctx.Err() != nil {
 			break
 		}
-		offset += limit
+		indexes <- i
 	}
+	close(indexes)
+	wg.Wait()
 
-	return allPlaylists, nil
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pages, nil
 }
 
 // GetPlaylist retrieves a specific playlist by ID.
@@ -530,13 +1222,8 @@ func (s *SpotifyService) GetPlaylist(ctx context.Context, playlistID string) (*m
 		return nil, err
 	}
 
-	return &models.Playlist{
-		ID:          sp.ID,
-		Name:        sp.Name,
-		Description: sp.Description,
-		TrackCount:  sp.Tracks.Total,
-		Public:      sp.Public,
-	}, nil
+	playlist := sp.toPlaylist()
+	return &playlist, nil
 }
 
 // ExportPlaylist exports a playlist with all its tracks.
@@ -546,30 +1233,31 @@ func (s *SpotifyService) ExportPlaylist(ctx context.Context, playlistID string)
 		return nil, err
 	}
 
-	playlist := models.Playlist{
-		ID:          sp.ID,
-		Name:        sp.Name,
-		Description: sp.Description,
-		TrackCount:  sp.Tracks.Total,
-		Public:      sp.Public,
-	}
+	playlist := sp.toPlaylist()
 
 	var tracks []models.Track
 	for _, item := range sp.Tracks.Items {
 		track := models.Track{
-			ID:       item.Track.ID,
-			Title:    item.Track.Name,
-			Duration: item.Track.DurationMS / 1000,
-			ISRC:     item.Track.ExternalIDs.ISRC,
+			ID:          item.Track.ID,
+			Title:       item.Track.Name,
+			Duration:    item.Track.DurationMS / 1000,
+			ISRC:        item.Track.ExternalIDs.ISRC,
+			Explicit:    item.Track.Explicit,
+			URI:         item.Track.URI,
+			ExternalURL: item.Track.ExternalURLs.Spotify,
+			Unavailable: item.Track.IsPlayable != nil && !*item.Track.IsPlayable,
 		}
 
-		if len(item.Track.Artists) > 0 {
-			track.Artist = item.Track.Artists[0].Name
-		}
+		track.SetArtists(spotifyArtistNames(item.Track.Artists)...)
 
 		if item.Track.Album.Name != "" {
 			track.Album = item.Track.Album.Name
 		}
+		track.ReleaseDate = item.Track.Album.ReleaseDate
+
+		if addedAt, err := time.Parse(time.RFC3339, item.AddedAt); err == nil {
+			track.AddedAt = addedAt
+		}
 
 		tracks = append(tracks, track)
 	}
@@ -582,6 +1270,8 @@ func (s *SpotifyService) ExportPlaylist(ctx context.Context, playlistID string)
 
 // ImportPlaylist imports a playlist into Spotify by creating a new playlist and adding tracks.
 //
+// A collaborative playlist is created private, as Spotify requires; its collaborators are not invited.
+//
 // Requires OAuth scopes: playlist-modify-public, playlist-modify-private
 func (s *SpotifyService) ImportPlaylist(ctx context.Context, playlist *models.PlaylistExport) (*models.Playlist, error) {
 	user, err := s.UserProfile(ctx)
@@ -590,9 +1280,10 @@ func (s *SpotifyService) ImportPlaylist(ctx context.Context, playlist *models.Pl
 	}
 
 	createReq := createPlaylistReq{
-		Name:        playlist.Playlist.Name,
-		Description: playlist.Playlist.Description,
-		Public:      playlist.Playlist.Public,
+		Name:          playlist.Playlist.Name,
+		Description:   playlist.Playlist.Description,
+		Public:        playlist.Playlist.Public && !playlist.Playlist.Collaborative,
+		Collaborative: playlist.Playlist.Collaborative,
 	}
 
 	var createdPlaylist SpotifyPlaylist
@@ -601,68 +1292,245 @@ func (s *SpotifyService) ImportPlaylist(ctx context.Context, playlist *models.Pl
 		return nil, fmt.Errorf("failed to create playlist: %w", err)
 	}
 
-	if len(playlist.Tracks) > 0 {
-		const batchSize = 100
-		for i := 0; i < len(playlist.Tracks); i += batchSize {
-			end := min(i+batchSize, len(playlist.Tracks))
+	if err := s.AddTracks(ctx, createdPlaylist.ID, playlist.Tracks); err != nil {
+		return nil, err
+	}
 
-			batch := playlist.Tracks[i:end]
-			trackURIs := make([]string, len(batch))
-			for j, track := range batch {
-				trackURIs[j] = fmt.Sprintf("spotify:track:%s", track.ID)
-			}
+	created := createdPlaylist.toPlaylist()
+	created.TrackCount = len(playlist.Tracks)
+	return &created, nil
+}
 
-			addReq := struct {
-				URIs []string `json:"uris"`
-			}{
-				URIs: trackURIs,
-			}
+// AddTracks appends tracks to an existing playlist in batches of 100, the most Spotify accepts per request.
+//
+// Batches are sent one at a time so the playlist keeps the order of tracks.
+//
+// Requires OAuth scopes: playlist-modify-public, playlist-modify-private
+func (s *SpotifyService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	const batchSize = 100
+	for i := 0; i < len(tracks); i += batchSize {
+		end := min(i+batchSize, len(tracks))
+
+		batch := tracks[i:end]
+		trackURIs := make([]string, len(batch))
+		for j, track := range batch {
+			trackURIs[j] = fmt.Sprintf("spotify:track:%s", track.ID)
+		}
 
-			addEndpoint := fmt.Sprintf("/playlists/%s/tracks", createdPlaylist.ID)
-			if err := s.doRequest(ctx, http.MethodPost, addEndpoint, addReq, nil); err != nil {
-				return nil, fmt.Errorf("failed to add tracks (batch %d-%d): %w", i, end, err)
-			}
+		addReq := struct {
+			URIs []string `json:"uris"`
+		}{
+			URIs: trackURIs,
+		}
+
+		addEndpoint := fmt.Sprintf("/playlists/%s/tracks", playlistID)
+		if err := s.doRequest(ctx, http.MethodPost, addEndpoint, addReq, nil); err != nil {
+			return fmt.Errorf("failed to add tracks (batch %d-%d): %w", i, end, err)
 		}
 	}
+	return nil
+}
 
-	return &models.Playlist{
-		ID:          createdPlaylist.ID,
-		Name:        createdPlaylist.Name,
-		Description: createdPlaylist.Description,
-		TrackCount:  len(playlist.Tracks),
-		Public:      createdPlaylist.Public,
-	}, nil
+// RemoveTracks removes every occurrence of tracks from a playlist in batches of 100, the most Spotify accepts per request.
+//
+// Requires OAuth scopes: playlist-modify-public, playlist-modify-private
+func (s *SpotifyService) RemoveTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	const batchSize = 100
+	for i := 0; i < len(tracks); i += batchSize {
+		end := min(i+batchSize, len(tracks))
+
+		type trackURI struct {
+			URI string `json:"uri"`
+		}
+		removeReq := struct {
+			Tracks []trackURI `json:"tracks"`
+		}{
+			Tracks: make([]trackURI, 0, end-i),
+		}
+		for _, track := range tracks[i:end] {
+			removeReq.Tracks = append(removeReq.Tracks, trackURI{URI: fmt.Sprintf("spotify:track:%s", track.ID)})
+		}
+
+		endpoint := fmt.Sprintf("/playlists/%s/tracks", playlistID)
+		if err := s.doRequest(ctx, http.MethodDelete, endpoint, removeReq, nil); err != nil {
+			return fmt.Errorf("failed to remove tracks (batch %d-%d): %w", i, end, err)
+		}
+	}
+	return nil
+}
+
+// ReorderTracks moves rangeLength items starting at rangeStart to before insertBefore.
+//
+// Requires OAuth scopes: playlist-modify-public, playlist-modify-private
+func (s *SpotifyService) ReorderTracks(ctx context.Context, playlistID string, rangeStart, rangeLength, insertBefore int) error {
+	if rangeStart < 0 || rangeLength < 1 || insertBefore < 0 {
+		return fmt.Errorf("%w: invalid range %d+%d before %d", shared.ErrInvalidArgument, rangeStart, rangeLength, insertBefore)
+	}
+
+	reorderReq := struct {
+		RangeStart   int `json:"range_start"`
+		RangeLength  int `json:"range_length"`
+		InsertBefore int `json:"insert_before"`
+	}{
+		RangeStart:   rangeStart,
+		RangeLength:  rangeLength,
+		InsertBefore: insertBefore,
+	}
+
+	endpoint := fmt.Sprintf("/playlists/%s/tracks", playlistID)
+	if err := s.doRequest(ctx, http.MethodPut, endpoint, reorderReq, nil); err != nil {
+		return fmt.Errorf("failed to reorder tracks: %w", err)
+	}
+	return nil
+}
+
+// UnfollowPlaylist removes a playlist from the user's library. Spotify has no deletion: unfollowing a playlist the
+// user owns is how its apps delete it.
+//
+// Calls DELETE /playlists/{id}/followers.
+func (s *SpotifyService) UnfollowPlaylist(ctx context.Context, playlistID string) error {
+	endpoint := fmt.Sprintf("/playlists/%s/followers", playlistID)
+	if err := s.doRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
+		return fmt.Errorf("failed to unfollow playlist: %w", err)
+	}
+	return nil
+}
+
+// ChangePlaylistDetails changes a playlist's name, description, or visibility, leaving the fields update omits.
+//
+// Calls PUT /playlists/{id}.
+func (s *SpotifyService) ChangePlaylistDetails(ctx context.Context, playlistID string, update PlaylistUpdate) error {
+	if update.Name == nil && update.Description == nil && update.Public == nil {
+		return nil
+	}
+	if update.Name != nil && *update.Name == "" {
+		return fmt.Errorf("%w: playlist name cannot be empty", shared.ErrInvalidArgument)
+	}
+
+	detailsReq := struct {
+		Name        *string `json:"name,omitempty"`
+		Description *string `json:"description,omitempty"`
+		Public      *bool   `json:"public,omitempty"`
+	}{
+		Name:        update.Name,
+		Description: update.Description,
+		Public:      update.Public,
+	}
+
+	endpoint := fmt.Sprintf("/playlists/%s", playlistID)
+	if err := s.doRequest(ctx, http.MethodPut, endpoint, detailsReq, nil); err != nil {
+		return fmt.Errorf("failed to change playlist details: %w", err)
+	}
+	return nil
+}
+
+// DeletePlaylist implements [PlaylistDeleter] by unfollowing the playlist.
+func (s *SpotifyService) DeletePlaylist(ctx context.Context, playlistID string) error {
+	return s.UnfollowPlaylist(ctx, playlistID)
+}
+
+// UpdatePlaylistMetadata implements [PlaylistEditor] with [SpotifyService.ChangePlaylistDetails].
+func (s *SpotifyService) UpdatePlaylistMetadata(ctx context.Context, playlistID string, update PlaylistUpdate) error {
+	return s.ChangePlaylistDetails(ctx, playlistID, update)
 }
 
 // SearchTrack searches for a track by title and artist and returns the best match.
+//
+// The best match is the top-ranked of several candidates from [SpotifyService.SearchTracks].
 func (s *SpotifyService) SearchTrack(ctx context.Context, title, artist string) (*models.Track, error) {
-	query := fmt.Sprintf("track:%s artist:%s", title, artist)
-	endpoint := fmt.Sprintf("/search?q=%s&type=track&limit=1", url.QueryEscape(query))
+	candidates, err := s.SearchTracks(ctx, title, artist, spotifySearchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no results found for track '%s' by artist '%s'", shared.ErrTrackNotFound, title, artist)
+	}
+	return &candidates[0], nil
+}
+
+// SearchTracks searches for up to limit tracks by title and artist, restricted to the configured market.
+//
+// Candidates whose title and artist match exactly are ranked first, then those with a matching title; ties
+// keep Spotify's relevance order. limit is clamped to Spotify's 1-50 range.
+func (s *SpotifyService) SearchTracks(ctx context.Context, title, artist string, limit int) ([]models.Track, error) {
+	limit = min(max(limit, 1), spotifyMaxSearchLimit)
+
+	query := "track:" + title
+	if artist != "" {
+		query += " artist:" + artist
+	}
+	params := url.Values{"q": {query}, "type": {"track"}, "limit": {strconv.Itoa(limit)}}
+	if s.market != "" {
+		params.Set("market", s.market)
+	}
 
 	var results SpotifySearchResults
-	if err := s.doRequest(ctx, http.MethodGet, endpoint, nil, &results); err != nil {
+	if err := s.doRequest(ctx, http.MethodGet, "/search?"+params.Encode(), nil, &results); err != nil {
 		return nil, err
 	}
 
-	if len(results.Tracks.Items) == 0 {
-		return nil, fmt.Errorf("no results found for track '%s' by artist '%s'", title, artist)
+	candidates := make([]models.Track, len(results.Tracks.Items))
+	for i, item := range results.Tracks.Items {
+		candidates[i] = spotifyTrackModel(item)
 	}
 
-	spotifyTrack := results.Tracks.Items[0]
-	track := &models.Track{
-		ID:       spotifyTrack.ID,
-		Title:    spotifyTrack.Name,
-		Duration: spotifyTrack.DurationMS / 1000,
-		ISRC:     spotifyTrack.ExternalIDs.ISRC,
+	rank := func(track models.Track) int {
+		switch {
+		case shared.NormalizeTrackKey(track.Title, track.Artist()) == shared.NormalizeTrackKey(title, artist):
+			return 2
+		case shared.NormalizeTrackKey(track.Title, "") == shared.NormalizeTrackKey(title, ""):
+			return 1
+		}
+		return 0
 	}
+	sort.SliceStable(candidates, func(i, j int) bool { return rank(candidates[i]) > rank(candidates[j]) })
 
-	if len(spotifyTrack.Artists) > 0 {
-		track.Artist = spotifyTrack.Artists[0].Name
+	return candidates, nil
+}
+
+// SearchISRC implements [ISRCSearcher], searching for the tracks with an ISRC in the configured market.
+// Tracks Spotify can relink to a playable release come back playable; others are marked [models.Track.Unavailable].
+func (s *SpotifyService) SearchISRC(ctx context.Context, isrc string) ([]models.Track, error) {
+	params := url.Values{"q": {"isrc:" + isrc}, "type": {"track"}, "limit": {strconv.Itoa(spotifySearchCandidates)}}
+	if s.market != "" {
+		params.Set("market", s.market)
 	}
 
-	if spotifyTrack.Album.Name != "" {
-		track.Album = spotifyTrack.Album.Name
+	var results SpotifySearchResults
+	if err := s.doRequest(ctx, http.MethodGet, "/search?"+params.Encode(), nil, &results); err != nil {
+		return nil, err
 	}
 
-	return track, nil
+	tracks := make([]models.Track, len(results.Tracks.Items))
+	for i, item := range results.Tracks.Items {
+		tracks[i] = spotifyTrackModel(item)
+	}
+	return tracks, nil
+}
+
+// spotifyTrackModel converts a Spotify search result to a [models.Track], crediting every artist.
+func spotifyTrackModel(spotifyTrack SpotifyTrack) models.Track {
+	track := models.Track{
+		ID:          spotifyTrack.ID,
+		Title:       spotifyTrack.Name,
+		Duration:    spotifyTrack.DurationMS / 1000,
+		ISRC:        spotifyTrack.ExternalIDs.ISRC,
+		Explicit:    spotifyTrack.Explicit,
+		Album:       spotifyTrack.Album.Name,
+		ReleaseDate: spotifyTrack.Album.ReleaseDate,
+		ExternalURL: spotifyTrack.ExternalURLs.Spotify,
+		Unavailable: spotifyTrack.IsPlayable != nil && !*spotifyTrack.IsPlayable,
+	}
+	track.SetArtists(spotifyArtistNames(spotifyTrack.Artists)...)
+	return track
+}
+
+// spotifyArtistNames returns the names of artists in credit order
+func spotifyArtistNames(artists []SpotifyArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
 }
@@ -2,10 +2,24 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+	tu "github.com/desertthunder/ytx/internal/testing"
 	"golang.org/x/oauth2"
 )
 
@@ -43,14 +57,20 @@ func TestSpotifyService(t *testing.T) {
 			}
 		})
 
-		t.Run("Missing Client Secret", func(t *testing.T) {
+		t.Run("Missing Client Secret uses PKCE", func(t *testing.T) {
 			credentials := map[string]string{
 				"client_id": "test_client_id",
 			}
 
-			_, err := NewSpotifyService(credentials)
-			if err == nil {
-				t.Error("expected error for missing client_secret")
+			srv, err := NewSpotifyService(credentials)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !srv.UsesPKCE() {
+				t.Error("expected service without client_secret to use PKCE")
+			}
+			if srv.config.Endpoint.AuthStyle != oauth2.AuthStyleInParams {
+				t.Error("expected client_id to be sent in the token request body")
 			}
 		})
 
@@ -98,6 +118,771 @@ func TestSpotifyService(t *testing.T) {
 		}
 	})
 
+	t.Run("Authenticate exchanges auth code with PKCE verifier", func(t *testing.T) {
+		var form url.Values
+		var authHeader string
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			form = r.PostForm
+			authHeader = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"pkce_access","refresh_token":"pkce_refresh","token_type":"Bearer","expires_in":3600}`))
+		}))
+		defer tokenServer.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "test_client_id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.config.Endpoint.TokenURL = tokenServer.URL
+
+		err = srv.Authenticate(context.Background(), map[string]string{"auth_code": "code", "code_verifier": "verifier"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if form.Get("code_verifier") != "verifier" {
+			t.Errorf("expected code_verifier in token request, got %q", form.Get("code_verifier"))
+		}
+		if form.Get("client_id") != "test_client_id" {
+			t.Errorf("expected client_id in token request body, got %q", form.Get("client_id"))
+		}
+		if form.Has("client_secret") || authHeader != "" {
+			t.Error("expected no client secret to be sent")
+		}
+		if srv.GetToken().AccessToken != "pkce_access" {
+			t.Errorf("expected exchanged access token, got %s", srv.GetToken().AccessToken)
+		}
+	})
+
+	t.Run("RefreshToken", func(t *testing.T) {
+		var form url.Values
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			form = r.PostForm
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"new_access","token_type":"Bearer","expires_in":3600}`))
+		}))
+		defer tokenServer.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id", "client_secret": "secret"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.config.Endpoint.TokenURL = tokenServer.URL
+
+		if err := srv.RefreshToken(context.Background()); !errors.Is(err, shared.ErrNoRefreshToken) {
+			t.Errorf("expected ErrNoRefreshToken before authenticating, got %v", err)
+		}
+
+		var saved *oauth2.Token
+		srv.SetTokenRefreshCallback(func(token *oauth2.Token) { saved = token })
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "old", RefreshToken: "refresh"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		if err := srv.RefreshToken(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if form.Get("grant_type") != "refresh_token" || form.Get("refresh_token") != "refresh" {
+			t.Errorf("expected refresh_token grant, got %v", form)
+		}
+		if saved == nil || saved.AccessToken != "new_access" {
+			t.Errorf("expected refresh callback with the new token, got %v", saved)
+		}
+		if saved.Expiry.IsZero() {
+			t.Error("expected the refreshed token to carry its expiry")
+		}
+	})
+
+	t.Run("SearchTracks", func(t *testing.T) {
+		var query url.Values
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"tracks": {"total": 3, "items": [
+				{"id": "1", "name": "Derezzed (Remix)", "artists": [{"name": "Daft Punk"}]},
+				{"id": "2", "name": "derezzed", "artists": [{"name": "Someone Else"}]},
+				{"id": "3", "name": "Derezzed", "artists": [{"name": "Daft Punk"}], "album": {"name": "TRON: Legacy"}}
+			]}}`))
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id", "market": "de"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		candidates, err := srv.SearchTracks(context.Background(), "Derezzed", "Daft Punk", 100)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if query.Get("market") != "DE" {
+			t.Errorf("expected market DE, got %q", query.Get("market"))
+		}
+		if query.Get("limit") != "50" {
+			t.Errorf("expected limit clamped to 50, got %q", query.Get("limit"))
+		}
+		if query.Get("q") != "track:Derezzed artist:Daft Punk" {
+			t.Errorf("unexpected query %q", query.Get("q"))
+		}
+
+		var ids []string
+		for _, track := range candidates {
+			ids = append(ids, track.ID)
+		}
+		if strings.Join(ids, ",") != "3,2,1" {
+			t.Errorf("expected exact match, then title match, then the rest; got %v", ids)
+		}
+		if candidates[0].Album != "TRON: Legacy" {
+			t.Errorf("expected album on candidate, got %q", candidates[0].Album)
+		}
+
+		best, err := srv.SearchTrack(context.Background(), "Derezzed", "Daft Punk")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if best.ID != "3" {
+			t.Errorf("expected SearchTrack to return the top-ranked candidate, got %s", best.ID)
+		}
+		if query.Get("limit") != "5" {
+			t.Errorf("expected SearchTrack to rank 5 candidates, got limit %q", query.Get("limit"))
+		}
+	})
+
+	t.Run("SearchISRC", func(t *testing.T) {
+		var query url.Values
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"tracks": {"total": 2, "items": [
+				{"id": "1", "name": "Derezzed", "artists": [{"name": "Daft Punk"}], "is_playable": false},
+				{"id": "2", "name": "Derezzed", "artists": [{"name": "Daft Punk"}], "is_playable": true, "explicit": true}
+			]}}`))
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id", "market": "jp"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		tracks, err := srv.SearchISRC(context.Background(), "USWD11000001")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if query.Get("q") != "isrc:USWD11000001" || query.Get("market") != "JP" {
+			t.Errorf("unexpected query %v", query)
+		}
+		if len(tracks) != 2 || !tracks[0].Unavailable || tracks[1].Unavailable || !tracks[1].Explicit {
+			t.Errorf("expected playability and explicit flags mapped, got %+v", tracks)
+		}
+	})
+
+	t.Run("SeveralAlbums", func(t *testing.T) {
+		var batches []int
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/albums":
+				ids := strings.Split(r.URL.Query().Get("ids"), ",")
+				batches = append(batches, len(ids))
+				var albums []string
+				for _, id := range ids {
+					switch id {
+					case "missing":
+						albums = append(albums, "null")
+					case "long":
+						albums = append(albums, `{"id": "long", "name": "Long", "tracks": {"total": 3, "next": "more", "items": [{"id": "t1"}, {"id": "t2"}]}}`)
+					default:
+						albums = append(albums, `{"id": "`+id+`", "name": "Album", "tracks": {"total": 1, "items": [{"id": "t"}]}}`)
+					}
+				}
+				w.Write([]byte(`{"albums": [` + strings.Join(albums, ",") + `]}`))
+			case "/albums/long/tracks":
+				if r.URL.Query().Get("offset") != "2" {
+					t.Errorf("expected the second page at offset 2, got %q", r.URL.Query().Get("offset"))
+				}
+				w.Write([]byte(`{"total": 3, "items": [{"id": "t3"}]}`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		ids := []string{"long", "missing"}
+		for i := range 23 {
+			ids = append(ids, fmt.Sprintf("a%d", i))
+		}
+
+		albums, err := srv.SeveralAlbums(context.Background(), ids)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(batches) != 2 || batches[0] != 20 || batches[1] != 5 {
+			t.Errorf("expected batches of 20 and 5 IDs, got %v", batches)
+		}
+		if len(albums) != 24 {
+			t.Errorf("expected the missing album to be skipped, got %d albums", len(albums))
+		}
+		if got := len(albums[0].Tracks.Items); got != 3 {
+			t.Errorf("expected every page of tracks, got %d", got)
+		}
+		if albums[0].Tracks.Next != nil {
+			t.Error("expected Next to be cleared once all tracks are fetched")
+		}
+	})
+
+	t.Run("SeveralArtists and FollowedArtists", func(t *testing.T) {
+		var batches []int
+		var afters []string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/artists":
+				ids := strings.Split(r.URL.Query().Get("ids"), ",")
+				batches = append(batches, len(ids))
+				var artists []string
+				for _, id := range ids {
+					artists = append(artists, `{"id": "`+id+`", "name": "Artist", "followers": {"total": 7}}`)
+				}
+				w.Write([]byte(`{"artists": [` + strings.Join(artists, ",") + `]}`))
+			case "/me/following":
+				afters = append(afters, r.URL.Query().Get("after"))
+				if r.URL.Query().Get("type") != "artist" {
+					t.Errorf("expected type=artist, got %q", r.URL.Query().Get("type"))
+				}
+				w.Write([]byte(`{"artists": {"total": 2, "items": [{"id": "x"}], "cursors": {"after": "x"}}}`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		ids := make([]string, 51)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("ar%d", i)
+		}
+		artists, err := srv.SeveralArtists(context.Background(), ids)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(batches) != 2 || batches[0] != 50 || batches[1] != 1 {
+			t.Errorf("expected batches of 50 and 1 IDs, got %v", batches)
+		}
+		if len(artists) != 51 || artists[0].Followers == nil || artists[0].Followers.Total != 7 {
+			t.Errorf("unexpected artists %+v", artists)
+		}
+
+		page, err := srv.FollowedArtists(context.Background(), 100, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := srv.FollowedArtists(context.Background(), 100, page.Cursors.After); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if strings.Join(afters, ",") != ",x" {
+			t.Errorf("expected the cursor to be passed on, got %v", afters)
+		}
+	})
+
+	t.Run("ImportPlaylist creates collaborative playlists private", func(t *testing.T) {
+		var created map[string]any
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.URL.Path == "/me":
+				w.Write([]byte(`{"id": "ada"}`))
+			case r.URL.Path == "/users/ada/playlists":
+				json.NewDecoder(r.Body).Decode(&created)
+				w.Write([]byte(`{"id": "new", "name": "Shared", "collaborative": true, "owner": {"id": "ada", "display_name": "Ada"}}`))
+			default:
+				w.Write([]byte(`{"snapshot_id": "s"}`))
+			}
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		playlist, err := srv.ImportPlaylist(context.Background(), &models.PlaylistExport{
+			Playlist: models.Playlist{Name: "Shared", Public: true, Collaborative: true},
+			Tracks:   []models.Track{{ID: "t1"}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if created["collaborative"] != true || created["public"] != false {
+			t.Errorf("expected a private collaborative playlist requested, got %v", created)
+		}
+		if !playlist.Collaborative || playlist.Owner != "Ada" || playlist.TrackCount != 1 {
+			t.Errorf("unexpected playlist %+v", playlist)
+		}
+	})
+
+	t.Run("ExportPlaylist keeps order and added_at", func(t *testing.T) {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": "pl", "name": "Road Trip", "tracks": {"total": 2, "items": [
+				{"added_at": "2024-03-01T12:30:00Z", "track": {"id": "b", "name": "Second"}},
+				{"added_at": "", "track": {"id": "a", "name": "First"}}
+			]}}`))
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		export, err := srv.ExportPlaylist(context.Background(), "pl")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(export.Tracks) != 2 || export.Tracks[0].ID != "b" || export.Tracks[1].ID != "a" {
+			t.Fatalf("expected tracks in playlist order, got %+v", export.Tracks)
+		}
+		if want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC); !export.Tracks[0].AddedAt.Equal(want) {
+			t.Errorf("expected added_at %v, got %v", want, export.Tracks[0].AddedAt)
+		}
+		if !export.Tracks[1].AddedAt.IsZero() {
+			t.Errorf("expected zero added_at when missing, got %v", export.Tracks[1].AddedAt)
+		}
+	})
+
+	t.Run("GetPlaylists fetches pages concurrently in order", func(t *testing.T) {
+		const total = 230
+		var failOffset atomic.Int64
+		failOffset.Store(-1)
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			if int64(offset) == failOffset.Load() {
+				http.Error(w, `{"error": {"status": 500, "message": "boom"}}`, http.StatusInternalServerError)
+				return
+			}
+			// Earlier pages answer last, so results arrive out of order
+			time.Sleep(time.Duration(total-offset) * time.Millisecond / 10)
+
+			items := []map[string]any{}
+			for i := offset; i < min(offset+50, total); i++ {
+				items = append(items, map[string]any{"id": fmt.Sprintf("p%d", i), "tracks": map[string]int{"total": i}})
+			}
+			page := map[string]any{"items": items, "total": total, "limit": 50, "offset": offset}
+			if offset+50 < total {
+				page["next"] = "more"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(page)
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		playlists, err := srv.GetPlaylists(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(playlists) != total {
+			t.Fatalf("expected %d playlists, got %d", total, len(playlists))
+		}
+		for i, playlist := range playlists {
+			if playlist.ID != fmt.Sprintf("p%d", i) || playlist.TrackCount != i {
+				t.Fatalf("expected playlist p%d at index %d, got %+v", i, i, playlist)
+			}
+		}
+
+		failOffset.Store(100)
+		if _, err := srv.GetPlaylists(context.Background()); err == nil {
+			t.Error("expected an error when a page fails")
+		}
+	})
+
+	t.Run("ExportPlaylist detects episodes and local files", func(t *testing.T) {
+		var query string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("additional_types")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": "pl", "name": "Mixed", "tracks": {"total": 3, "items": [
+				{"track": {"id": "t1", "name": "Song", "uri": "spotify:track:t1", "external_ids": {"isrc": "USRC1"}}},
+				{"track": {"id": "e1", "name": "Episode 12", "type": "episode", "uri": "spotify:episode:e1", "external_urls": {"spotify": "https://open.spotify.com/episode/e1"}}},
+				{"is_local": true, "track": {"id": null, "name": "Demo", "uri": "spotify:local:Artist:Album:Demo:180"}}
+			]}}`))
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		export, err := srv.ExportPlaylist(context.Background(), "pl")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !strings.Contains(query, "episode") {
+			t.Errorf("expected episodes to be requested, got additional_types=%q", query)
+		}
+
+		want := []string{"", models.UnmatchableEpisode, models.UnmatchableLocalFile}
+		for i, track := range export.Tracks {
+			if got := track.Unmatchable(); got != want[i] {
+				t.Errorf("track %d: Unmatchable() = %q, want %q", i, got, want[i])
+			}
+		}
+		if got := export.Tracks[1].ExternalURL; got != "https://open.spotify.com/episode/e1" {
+			t.Errorf("expected the episode's own link, got %q", got)
+		}
+		if got := export.Playlist.ExternalURL; got != "https://open.spotify.com/playlist/pl" {
+			t.Errorf("expected the playlist link, got %q", got)
+		}
+	})
+
+	t.Run("RemoveTracks and ReorderTracks", func(t *testing.T) {
+		type request struct {
+			method string
+			body   map[string]any
+		}
+		var requests []request
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/playlists/pl/tracks" {
+				http.NotFound(w, r)
+				return
+			}
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, request{method: r.Method, body: body})
+			w.Write([]byte(`{"snapshot_id": "snap"}`))
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		tracks := make([]models.Track, 150)
+		for i := range tracks {
+			tracks[i] = models.Track{ID: fmt.Sprintf("t%d", i)}
+		}
+		if err := srv.RemoveTracks(context.Background(), "pl", tracks); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(requests) != 2 || requests[0].method != http.MethodDelete {
+			t.Fatalf("expected two DELETE batches, got %+v", requests)
+		}
+		if got := requests[0].body["tracks"].([]any); len(got) != 100 || got[0].(map[string]any)["uri"] != "spotify:track:t0" {
+			t.Errorf("unexpected first batch %v", got)
+		}
+
+		if err := srv.ReorderTracks(context.Background(), "pl", 5, 2, 0); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		last := requests[len(requests)-1]
+		if last.method != http.MethodPut || last.body["range_start"] != 5.0 || last.body["range_length"] != 2.0 || last.body["insert_before"] != 0.0 {
+			t.Errorf("unexpected reorder request %+v", last)
+		}
+
+		if err := srv.ReorderTracks(context.Background(), "pl", 0, 0, 3); !errors.Is(err, shared.ErrInvalidArgument) {
+			t.Errorf("expected ErrInvalidArgument for an empty range, got %v", err)
+		}
+	})
+
+	t.Run("UnfollowPlaylist and ChangePlaylistDetails", func(t *testing.T) {
+		type request struct {
+			method string
+			path   string
+			body   map[string]any
+		}
+		var requests []request
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, request{method: r.Method, path: r.URL.Path, body: body})
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		if err := srv.UnfollowPlaylist(context.Background(), "pl"); err != nil {
+			t.Fatalf("UnfollowPlaylist() error = %v", err)
+		}
+		name, public := "Renamed", false
+		if err := srv.ChangePlaylistDetails(context.Background(), "pl", PlaylistUpdate{Name: &name, Public: &public}); err != nil {
+			t.Fatalf("ChangePlaylistDetails() error = %v", err)
+		}
+
+		if len(requests) != 2 || requests[0].method != http.MethodDelete || requests[0].path != "/playlists/pl/followers" {
+			t.Fatalf("expected an unfollow request first, got %+v", requests)
+		}
+		if last := requests[1]; last.method != http.MethodPut || last.path != "/playlists/pl" || len(last.body) != 2 ||
+			last.body["name"] != "Renamed" || last.body["public"] != false {
+			t.Errorf("expected only the name and visibility to change, got %+v", last)
+		}
+
+		empty := ""
+		if err := srv.ChangePlaylistDetails(context.Background(), "pl", PlaylistUpdate{Name: &empty}); !errors.Is(err, shared.ErrInvalidArgument) {
+			t.Errorf("expected ErrInvalidArgument for an empty name, got %v", err)
+		}
+		if len(requests) != 2 {
+			t.Errorf("expected no request for an invalid change, got %d", len(requests))
+		}
+	})
+
+	t.Run("LikedSongs follows pages", func(t *testing.T) {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/me/tracks" {
+				t.Errorf("unexpected request %s", r.URL.Path)
+			}
+			switch r.URL.Query().Get("offset") {
+			case "0":
+				w.Write([]byte(`{"items": [
+					{"added_at": "2026-03-01T10:00:00Z", "track": {"id": "t1", "name": "One", "artists": [{"name": "Band"}], "album": {"name": "LP", "release_date": "1999-04-01"}}},
+					{"added_at": "2026-02-01T10:00:00Z", "track": {"id": ""}}
+				], "total": 3, "next": "page-2"}`))
+			default:
+				w.Write([]byte(`{"items": [{"added_at": "bad", "track": {"id": "t2", "name": "Two", "artists": [{"name": "Band"}]}}], "total": 3}`))
+			}
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		tracks, err := srv.LikedSongs(context.Background())
+		if err != nil {
+			t.Fatalf("LikedSongs() error = %v", err)
+		}
+		if len(tracks) != 2 || tracks[0].ID != "t1" || tracks[1].ID != "t2" {
+			t.Fatalf("expected tracks t1 and t2, got %+v", tracks)
+		}
+		if want := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC); !tracks[0].AddedAt.Equal(want) || tracks[0].ReleaseDate != "1999-04-01" {
+			t.Errorf("expected t1 saved at %v from 1999, got %v and %q", want, tracks[0].AddedAt, tracks[0].ReleaseDate)
+		}
+		if !tracks[1].AddedAt.IsZero() {
+			t.Errorf("expected no save time for an unparseable date, got %v", tracks[1].AddedAt)
+		}
+	})
+
+	t.Run("EnrichTracks adds artist genres and audio features", func(t *testing.T) {
+		var paths []string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			switch r.URL.Path {
+			case "/tracks":
+				if ids := r.URL.Query().Get("ids"); ids != "t1,t2" {
+					t.Errorf("expected each track requested once, got %s", ids)
+				}
+				w.Write([]byte(`{"tracks": [
+					{"id": "t1", "artists": [{"id": "a1"}, {"id": "a2"}]},
+					{"id": "t2", "artists": [{"id": "a2"}]}
+				]}`))
+			case "/artists":
+				w.Write([]byte(`{"artists": [{"id": "a1", "genres": ["indie pop", "shoegaze"]}, {"id": "a2", "genres": ["shoegaze", "dream pop"]}]}`))
+			case "/audio-features":
+				if r.URL.Query().Get("ids") == "fail" {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.Write([]byte(`{"audio_features": [{"id": "t1", "danceability": 0.5, "energy": 0.9, "tempo": 128.1}, null]}`))
+			}
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		tracks := []models.Track{
+			{ID: "t1", Title: "One"},
+			{ID: "t2", Title: "Two"},
+			{ID: "t1", Title: "One again"},
+			{ID: "ep", URI: "spotify:episode:ep"},
+		}
+		if err := srv.EnrichTracks(context.Background(), tracks); err != nil {
+			t.Fatalf("EnrichTracks() error = %v", err)
+		}
+
+		if got := strings.Join(tracks[0].Genres, ","); got != "indie pop,shoegaze,dream pop" {
+			t.Errorf("expected the genres of both artists without repeats, got %s", got)
+		}
+		if got := strings.Join(tracks[1].Genres, ","); got != "shoegaze,dream pop" {
+			t.Errorf("expected the second track's genres, got %s", got)
+		}
+		if tracks[0].Features == nil || tracks[0].Features.Energy != 0.9 || tracks[0].Features.Tempo != 128.1 {
+			t.Errorf("expected audio features for t1, got %+v", tracks[0].Features)
+		}
+		if tracks[1].Features != nil || tracks[3].Genres != nil {
+			t.Errorf("expected no features for t2 and nothing for the episode, got %+v and %+v", tracks[1].Features, tracks[3])
+		}
+		if tracks[2].Features == nil || len(tracks[2].Genres) != 3 {
+			t.Errorf("expected a repeated track enriched too, got %+v", tracks[2])
+		}
+		if len(paths) != 3 {
+			t.Errorf("expected one request per endpoint, got %v", paths)
+		}
+	})
+
+	t.Run("API errors carry the reason from the body", func(t *testing.T) {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/playlists/scope":
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error": {"status": 403, "message": "Insufficient client scope"}}`))
+			case "/playlists/busy":
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusTooManyRequests)
+			case "/playlists/gone":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error": {"status": 404, "message": "Resource not found"}}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "invalid_request", "error_description": "Bad market"}`))
+			}
+		}))
+		defer api.Close()
+
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		srv.baseURL = api.URL
+		if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+			t.Fatalf("failed to authenticate: %v", err)
+		}
+
+		tests := []struct {
+			id      string
+			want    SpotifyAPIError
+			matches error
+		}{
+			{"scope", SpotifyAPIError{StatusCode: 403, Message: "Insufficient client scope"}, shared.ErrForbidden},
+			{"busy", SpotifyAPIError{StatusCode: 429, RetryAfter: 30 * time.Second}, shared.ErrRateLimited},
+			{"gone", SpotifyAPIError{StatusCode: 404, Message: "Resource not found"}, shared.ErrPlaylistNotFound},
+			{"other", SpotifyAPIError{StatusCode: 400, Message: "Bad market"}, shared.ErrAPIRequest},
+		}
+		for _, tt := range tests {
+			_, err := srv.GetPlaylist(context.Background(), tt.id)
+			var apiErr *SpotifyAPIError
+			if !errors.As(err, &apiErr) || *apiErr != tt.want {
+				t.Errorf("GetPlaylist(%s) error = %v, want %+v", tt.id, err, tt.want)
+			}
+			if !errors.Is(err, tt.matches) || !errors.Is(err, shared.ErrAPIRequest) {
+				t.Errorf("GetPlaylist(%s) error %v does not match %v", tt.id, err, tt.matches)
+			}
+			if tt.id != "gone" && errors.Is(err, shared.ErrPlaylistNotFound) {
+				t.Errorf("GetPlaylist(%s) error %v reports a missing playlist", tt.id, err)
+			}
+		}
+	})
+
+	t.Run("invalid market", func(t *testing.T) {
+		if _, err := NewSpotifyService(map[string]string{"client_id": "id", "market": "germany"}); err == nil {
+			t.Error("expected error for invalid market")
+		}
+		srv, err := NewSpotifyService(map[string]string{"client_id": "id", "market": "from_token"})
+		if err != nil || srv.market != "from_token" {
+			t.Errorf("expected from_token market to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("GetAuthURL with PKCE", func(t *testing.T) {
+		srv, err := NewSpotifyService(map[string]string{"client_id": "test_client_id"})
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+
+		pkce := NewPKCE()
+		if len(pkce.Verifier) < 43 {
+			t.Errorf("expected a verifier of at least 43 characters, got %d", len(pkce.Verifier))
+		}
+
+		authURL, err := url.Parse(srv.GetAuthURL("test_state", pkce.AuthURLOption()))
+		if err != nil {
+			t.Fatalf("failed to parse auth URL: %v", err)
+		}
+
+		query := authURL.Query()
+		if query.Get("code_challenge") != pkce.Challenge() {
+			t.Errorf("expected code_challenge %s, got %s", pkce.Challenge(), query.Get("code_challenge"))
+		}
+		if query.Get("code_challenge_method") != "S256" {
+			t.Errorf("expected code_challenge_method S256, got %s", query.Get("code_challenge_method"))
+		}
+		if pkce.Challenge() == pkce.Verifier {
+			t.Error("expected the challenge to be derived from the verifier")
+		}
+	})
+
 	t.Run("Authenticate", func(t *testing.T) {
 		credentials := map[string]string{
 			"client_id":     "test_client_id",
@@ -360,6 +1145,203 @@ func TestSpotifyService(t *testing.T) {
 	})
 }
 
+func TestSpotifyService_ResponseCache(t *testing.T) {
+	requests := map[string]int{}
+	notModified := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		switch r.URL.Path {
+		case "/me":
+			w.Header().Set("Cache-Control", "private, max-age=0")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(`{"id": "user1", "display_name": "User"}`))
+		case "/tracks/1":
+			w.Header().Set("Cache-Control", "public, max-age=60")
+			w.Write([]byte(`{"id": "1", "name": "Song"}`))
+		case "/tracks/2":
+			w.Header().Set("Cache-Control", "no-store")
+			w.Write([]byte(`{"id": "2", "name": "Song 2"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer api.Close()
+
+	srv, err := NewSpotifyService(map[string]string{"client_id": "id"})
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	srv.baseURL = api.URL
+	srv.SetResponseCache(NewDiskResponseCache(t.TempDir()))
+	if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: "token"}); err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+	ctx := context.Background()
+
+	for range 2 {
+		user, err := srv.UserProfile(ctx)
+		if err != nil || user.ID != "user1" {
+			t.Fatalf("UserProfile() = %+v, %v", user, err)
+		}
+	}
+	if requests["GET /me"] != 2 || notModified != 1 {
+		t.Errorf("expected the second profile request to be revalidated, got %d requests and %d 304s", requests["GET /me"], notModified)
+	}
+
+	for _, id := range []string{"1", "1", "2", "2"} {
+		if track, err := srv.Track(ctx, id); err != nil || track.ID != id {
+			t.Fatalf("Track(%s) = %+v, %v", id, track, err)
+		}
+	}
+	if requests["GET /tracks/1"] != 1 {
+		t.Errorf("expected a fresh response to be served from the cache, got %d requests", requests["GET /tracks/1"])
+	}
+	if requests["GET /tracks/2"] != 2 {
+		t.Errorf("expected no-store responses to skip the cache, got %d requests", requests["GET /tracks/2"])
+	}
+
+	if err := srv.doRequest(ctx, http.MethodPost, "/playlists/p1/tracks", map[string]any{"uris": []string{}}, nil); err != nil {
+		t.Fatalf("write error = %v", err)
+	}
+	if _, err := srv.Track(ctx, "1"); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if requests["GET /tracks/1"] != 2 {
+		t.Errorf("expected fresh responses to be revalidated after a write, got %d requests", requests["GET /tracks/1"])
+	}
+}
+
+func TestDiskResponseCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewDiskResponseCache(dir)
+	if _, ok := cache.Get("https://api.spotify.com/v1/me"); ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+
+	cache.Set("https://api.spotify.com/v1/me", CachedResponse{Body: []byte(`{"id": "user1"}`), ETag: `"v1"`})
+	got, ok := NewDiskResponseCache(dir).Get("https://api.spotify.com/v1/me")
+	if !ok || string(got.Body) != `{"id": "user1"}` || got.ETag != `"v1"` {
+		t.Errorf("expected the response to survive a new cache instance, got %+v, %v", got, ok)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, ok := cache.Get("https://api.spotify.com/v1/me"); ok {
+		t.Error("expected a miss after Clear")
+	}
+}
+
+// spotifyFixture returns a Spotify service replaying the fixture testdata/spotify/<name>.json.
+//
+// With YTX_RECORD set, it records the fixture from the live API instead, using the access token in
+// YTX_SPOTIFY_TOKEN and replacing the user ID in YTX_SPOTIFY_USER with "user".
+func spotifyFixture(t *testing.T, name string) *SpotifyService {
+	t.Helper()
+	recorder := tu.NewRecorder(t, filepath.Join("testdata", "spotify", name+".json"))
+
+	srv, err := NewSpotifyService(map[string]string{"client_id": "fixture"})
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	srv.SetHTTPClient(recorder.Client())
+
+	token := "fixture"
+	if recorder.Recording() {
+		token = os.Getenv("YTX_SPOTIFY_TOKEN")
+		recorder.Redact(os.Getenv("YTX_SPOTIFY_USER"), "user")
+	}
+	if err := srv.OAuthenticate(context.Background(), &oauth2.Token{AccessToken: token}); err != nil {
+		t.Fatalf("failed to authenticate: %v", err)
+	}
+	return srv
+}
+
+func TestSpotifyFixtures(t *testing.T) {
+	t.Run("GetPlaylists fetches every page in order", func(t *testing.T) {
+		playlists, err := spotifyFixture(t, "playlists").GetPlaylists(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		names := make([]string, len(playlists))
+		for i, p := range playlists {
+			names[i] = p.Name
+		}
+		if want := []string{"Commute", "Empty", "Road Trip", "Discover Weekly"}; !slices.Equal(names, want) {
+			t.Fatalf("expected playlists %q, got %q", want, names)
+		}
+		if !playlists[0].Public || playlists[0].TrackCount != 42 {
+			t.Errorf("expected a public playlist of 42 tracks, got %+v", playlists[0])
+		}
+		if playlists[3].ExternalURL != "https://open.spotify.com/playlist/37i9dQZEVXcJZyENOWUFo7" {
+			t.Errorf("unexpected link %s", playlists[3].ExternalURL)
+		}
+	})
+
+	t.Run("ExportPlaylist reads tracks, unavailable tracks, and episodes", func(t *testing.T) {
+		export, err := spotifyFixture(t, "export").ExportPlaylist(context.Background(), "3cEYpjA9oz9GiPac4AsH4n")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if export.Playlist.Name != "Commute" || len(export.Tracks) != 3 {
+			t.Fatalf("expected Commute with 3 tracks, got %q with %d", export.Playlist.Name, len(export.Tracks))
+		}
+		if export.Playlist.Owner != "user" || export.Playlist.Followers != 3 || export.Playlist.Collaborative {
+			t.Errorf("expected an owned, non-collaborative playlist with 3 followers, got %+v", export.Playlist)
+		}
+
+		track := export.Tracks[0]
+		if track.Artist() != "Drake" || !slices.Equal(track.Artists, []string{"Drake", "Kanye West"}) {
+			t.Errorf("expected Drake credited first with Kanye West, got %q %q", track.Artist(), track.Artists)
+		}
+		if track.Duration != 217 || track.ISRC != "USCM51800212" || !track.Explicit || track.Album != "Scorpion" {
+			t.Errorf("unexpected track %+v", track)
+		}
+		if want := time.Date(2024, 3, 2, 18, 21, 7, 0, time.UTC); !track.AddedAt.Equal(want) {
+			t.Errorf("expected added at %v, got %v", want, track.AddedAt)
+		}
+
+		if export.Tracks[0].Unavailable || !export.Tracks[1].Unavailable {
+			t.Error("expected only the second track to be unavailable")
+		}
+		if got := export.Tracks[2].ExternalURL; got != "https://open.spotify.com/episode/512ojhOuo1ktJprKbVcKyQ" {
+			t.Errorf("expected the episode's link, got %s", got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		srv := spotifyFixture(t, "errors")
+		ctx := context.Background()
+
+		var apiErr *SpotifyAPIError
+		_, err := srv.ExportPlaylist(ctx, "0000000000000000000000")
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Resource not found" {
+			t.Errorf("expected a 404 with Spotify's message, got %v", err)
+		}
+
+		_, err = srv.SearchTrack(ctx, "Hoppípolla", "Sigur Rós")
+		if after, ok := RetryAfter(err); !errors.Is(err, shared.ErrRateLimited) || !ok || after != 7*time.Second {
+			t.Errorf("expected rate limiting with a 7s Retry-After despite the plain text body, got %v", err)
+		}
+
+		_, err = srv.AudioFeatures(ctx, []string{"4uLU6hMCjMI75M1A2tKUQC"})
+		if !errors.Is(err, shared.ErrForbidden) {
+			t.Errorf("expected a forbidden error, got %v", err)
+		}
+
+		_, err = srv.UserProfile(ctx)
+		if !errors.Is(err, shared.ErrTokenExpired) {
+			t.Errorf("expected an expired token, got %v", err)
+		}
+	})
+}
+
 // mockTokenSource implements [oauth2.TokenSource] for testing
 type mockTokenSource struct {
 	token *oauth2.Token
@@ -5,18 +5,36 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
 )
 
 const defaultYTBaseURL string = "http://localhost:8080"
 
+// YouTubeMaxPlaylistSize is the most tracks a YouTube Music playlist can hold; adds beyond it fail.
+const YouTubeMaxPlaylistSize = 5000
+
+const (
+	// YouTubeAddBatchSize is the most video IDs sent to the proxy per request when adding tracks
+	YouTubeAddBatchSize = 50
+	// youtubeAddRetries is how many times a failed batch is retried before it is reported
+	youtubeAddRetries = 2
+	// defaultYTRetryDelay is the wait before the first retry of a batch, doubled for each later one
+	defaultYTRetryDelay = time.Second
+)
+
 // YouTubeImage represents an image/thumbnail from YouTube Music.
 type YouTubeImage struct {
 	URL    string `json:"url"`
@@ -44,8 +62,37 @@ type YouTubeTrack struct {
 	Duration    string          `json:"duration"`
 	DurationSec int             `json:"duration_seconds"` // Duration in seconds
 	Thumbnails  []YouTubeImage  `json:"thumbnails"`
-	ISRC        string          `json:"isrc,omitempty"`       // TODO: use ISRC for MusicBrainz matching
-	SetVideoID  string          `json:"setVideoId,omitempty"` // Unique ID of this playlist item, needed for moving/removing playlist items
+	ISRC        string          `json:"isrc,omitempty"`        // TODO: use ISRC for MusicBrainz matching
+	IsExplicit  bool            `json:"isExplicit,omitempty"`  // Shown with the explicit badge on YouTube Music
+	IsAvailable *bool           `json:"isAvailable,omitempty"` // False for uploads blocked in the account's region
+	SetVideoID  string          `json:"setVideoId,omitempty"`  // Unique ID of this playlist item, needed for moving/removing playlist items
+}
+
+// toTrack converts a proxy track to a [models.Track], crediting every artist.
+func (t YouTubeTrack) toTrack() models.Track {
+	track := models.Track{
+		ID:          t.VideoID,
+		Title:       t.Title,
+		Duration:    t.DurationSec,
+		ISRC:        t.ISRC,
+		Explicit:    t.IsExplicit,
+		Unavailable: t.IsAvailable != nil && !*t.IsAvailable,
+		ExternalURL: models.TrackURL("youtube", t.VideoID),
+	}
+	track.SetArtists(youtubeArtistNames(t.Artists)...)
+	if t.Album != nil {
+		track.Album = t.Album.Name
+	}
+	return track
+}
+
+// youtubeArtistNames returns the names of artists in credit order
+func youtubeArtistNames(artists []YouTubeArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
 }
 
 // YouTubePlaylist represents a playlist from YouTube Music.
@@ -59,11 +106,49 @@ type YouTubePlaylist struct {
 	Tracks      []YouTubeTrack `json:"tracks,omitempty"`
 }
 
+// DefaultLibraryPageSize is how many items are requested per page of a paginated library endpoint.
+const DefaultLibraryPageSize = 100
+
+// LibraryPage is one page of a proxy library endpoint.
+type LibraryPage struct {
+	Items        []any
+	Continuation string // Token for the next page, empty on the last one
+}
+
+// LibraryPath returns a library endpoint path requesting limit items from the page after continuation.
+func LibraryPath(path string, limit int, continuation string) string {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if continuation != "" {
+		query.Set("continuation", continuation)
+	}
+	return path + "?" + query.Encode()
+}
+
+// ParseLibraryPage reads a page from a decoded library response, either {"items": [...], "continuation": "..."}
+// or a bare array from a proxy without pagination, which is taken as the last page.
+func ParseLibraryPage(data any) (LibraryPage, bool) {
+	switch v := data.(type) {
+	case []any:
+		return LibraryPage{Items: v}, true
+	case map[string]any:
+		items, ok := v["items"].([]any)
+		if !ok {
+			return LibraryPage{}, false
+		}
+		continuation, _ := v["continuation"].(string)
+		return LibraryPage{Items: items, Continuation: continuation}, true
+	default:
+		return LibraryPage{}, false
+	}
+}
+
 // YouTubeService implements the Service interface for YouTube Music via proxy.
 type YouTubeService struct {
 	baseURL    string
 	authFile   string
 	httpClient *http.Client
+	retryDelay time.Duration
 }
 
 // NewYouTubeService creates a new YouTube Music service instance.
@@ -75,14 +160,25 @@ func NewYouTubeService(baseURL string) *YouTubeService {
 	return &YouTubeService{
 		baseURL:    baseURL,
 		httpClient: http.DefaultClient,
+		retryDelay: defaultYTRetryDelay,
 	}
 }
 
+// SetHTTPClient sets the client that proxy requests are sent through, e.g. to instrument its transport.
+func (y *YouTubeService) SetHTTPClient(client *http.Client) {
+	y.httpClient = client
+}
+
 // Name returns the service name.
 func (y *YouTubeService) Name() string {
 	return "YouTube Music"
 }
 
+// MaxPlaylistSize returns the most tracks a YouTube Music playlist can hold.
+func (y *YouTubeService) MaxPlaylistSize() int {
+	return YouTubeMaxPlaylistSize
+}
+
 // Authenticate stores the authentication file path for subsequent requests.
 //
 // Expects credentials["auth_file"] to contain the path to browser.json or oauth.json.
@@ -96,10 +192,32 @@ func (y *YouTubeService) Authenticate(ctx context.Context, credentials map[strin
 	return nil
 }
 
-func (y *YouTubeService) doRequest(ctx context.Context, method, endpoint string, _, result any) error {
+// rawBody is a request body sent to the proxy as is rather than encoded as JSON
+type rawBody struct {
+	data        []byte
+	contentType string
+}
+
+// doRequest performs a request to the proxy with the auth file header, sending body as JSON when non-nil (or as is
+// when a [rawBody]) and decoding the response into result when non-nil.
+func (y *YouTubeService) doRequest(ctx context.Context, method, endpoint string, body, result any) error {
 	apiURL := y.baseURL + endpoint
 
-	req, err := http.NewRequestWithContext(ctx, method, apiURL, nil)
+	var reqBody io.Reader
+	contentType := ""
+	switch body := body.(type) {
+	case nil:
+	case rawBody:
+		reqBody, contentType = bytes.NewReader(body.data), body.contentType
+	default:
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody, contentType = bytes.NewReader(jsonBody), "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -107,8 +225,9 @@ func (y *YouTubeService) doRequest(ctx context.Context, method, endpoint string,
 	if y.authFile != "" {
 		req.Header.Set("X-Auth-File", y.authFile)
 	}
-
-	req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
 	resp, err := y.httpClient.Do(req)
 	if err != nil {
@@ -117,13 +236,7 @@ func (y *YouTubeService) doRequest(ctx context.Context, method, endpoint string,
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var errResp struct {
-			Detail string `json:"detail"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Detail != "" {
-			return fmt.Errorf("youtube music API error (status %d): %s", resp.StatusCode, errResp.Detail)
-		}
-		return fmt.Errorf("youtube music API error: status %d", resp.StatusCode)
+		return parseYouTubeError(resp)
 	}
 
 	if result != nil {
@@ -135,9 +248,85 @@ func (y *YouTubeService) doRequest(ctx context.Context, method, endpoint string,
 	return nil
 }
 
+// YouTubeAPIError is an unsuccessful response from the proxy, with the detail given in its error body.
+//
+// Like [SpotifyAPIError], it matches [shared.ErrAPIRequest], and [shared.ErrForbidden] or [shared.ErrRateLimited]
+// for 403 and 429 responses.
+type YouTubeAPIError struct {
+	StatusCode int
+	Detail     string        // detail from the proxy's error body; empty when there was none
+	RetryAfter time.Duration // From the Retry-After header, for 429 responses
+}
+
+func (e *YouTubeAPIError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("youtube music API error (status %d): %s", e.StatusCode, e.Detail)
+	}
+	return fmt.Sprintf("youtube music API error: status %d", e.StatusCode)
+}
+
+func (e *YouTubeAPIError) Is(target error) bool {
+	return apiErrorIs(e.StatusCode, target)
+}
+
+// parseYouTubeError builds a [YouTubeAPIError] from a response and its {"detail"} body, as FastAPI writes errors
+func parseYouTubeError(resp *http.Response) *YouTubeAPIError {
+	apiErr := &YouTubeAPIError{StatusCode: resp.StatusCode, RetryAfter: retryAfter(resp.Header)}
+	var errResp struct {
+		Detail string `json:"detail"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errResp); err == nil {
+		apiErr.Detail = errResp.Detail
+	}
+	return apiErr
+}
+
+// libraryItems fetches every page of a proxy library endpoint, following continuations, and decodes the items into
+// items, which must be a pointer to a slice.
+func (y *YouTubeService) libraryItems(ctx context.Context, path string, items any) error {
+	var all []json.RawMessage
+	continuation := ""
+	for {
+		var raw json.RawMessage
+		if err := y.doRequest(ctx, http.MethodGet, LibraryPath(path, DefaultLibraryPageSize, continuation), nil, &raw); err != nil {
+			return err
+		}
+
+		var page struct {
+			Items        []json.RawMessage `json:"items"`
+			Continuation string            `json:"continuation"`
+		}
+		var err error
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(trimmed, &page.Items)
+		} else {
+			err = json.Unmarshal(raw, &page)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		all = append(all, page.Items...)
+		// A repeated token would loop forever, so it ends the listing like a missing one
+		if page.Continuation == "" || page.Continuation == continuation {
+			break
+		}
+		continuation = page.Continuation
+	}
+
+	data, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if err := json.Unmarshal(data, items); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
 // GetPlaylists retrieves all playlists for the authenticated user.
 //
-// Calls GET /api/library/playlists on the proxy.
+// Calls GET /api/library/playlists on the proxy, following continuations until every page is fetched.
 func (y *YouTubeService) GetPlaylists(ctx context.Context) ([]models.Playlist, error) {
 	var ytPlaylists []struct {
 		PlaylistID  string         `json:"playlistId"`
@@ -148,7 +337,7 @@ func (y *YouTubeService) GetPlaylists(ctx context.Context) ([]models.Playlist, e
 		Thumbnails  []YouTubeImage `json:"thumbnails"`
 	}
 
-	if err := y.doRequest(ctx, http.MethodGet, "/api/library/playlists", nil, &ytPlaylists); err != nil {
+	if err := y.libraryItems(ctx, "/api/library/playlists", &ytPlaylists); err != nil {
 		return nil, err
 	}
 
@@ -160,12 +349,87 @@ func (y *YouTubeService) GetPlaylists(ctx context.Context) ([]models.Playlist, e
 			Description: ytp.Description,
 			TrackCount:  ytp.Count,
 			Public:      ytp.Privacy == "PUBLIC",
+			ExternalURL: models.PlaylistURL("youtube", ytp.PlaylistID),
 		}
 	}
 
 	return playlists, nil
 }
 
+// History retrieves the listening history, most recent first.
+//
+// Calls GET /api/library/history on the proxy, following continuations. Each track's AddedAt is when it was played,
+// as far as the proxy's "played" label dates it; zero when it cannot be dated.
+func (y *YouTubeService) History(ctx context.Context) ([]models.Track, error) {
+	var items []struct {
+		YouTubeTrack
+		Played string `json:"played"`
+	}
+	if err := y.libraryItems(ctx, "/api/library/history", &items); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tracks := make([]models.Track, len(items))
+	for i, item := range items {
+		tracks[i] = item.toTrack()
+		tracks[i].AddedAt = playedAt(item.Played, now)
+	}
+	return tracks, nil
+}
+
+// LikedSongs retrieves the songs the user has liked.
+//
+// Calls GET /api/library/liked-songs on the proxy, following continuations.
+func (y *YouTubeService) LikedSongs(ctx context.Context) ([]models.Track, error) {
+	var items []YouTubeTrack
+	if err := y.libraryItems(ctx, "/api/library/liked-songs", &items); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]models.Track, len(items))
+	for i, item := range items {
+		tracks[i] = item.toTrack()
+	}
+	return tracks, nil
+}
+
+// playedAt dates a history entry's "played" label relative to now, in now's location.
+//
+// YouTube Music only groups history coarsely ("Today", "Last week", "March 2024"), so each group is dated to its
+// start, weeks beginning on Monday. Proxies that report exact RFC 3339 times or dates are used as is. Labels that
+// cannot be dated give the zero time.
+func playedAt(label string, now time.Time) time.Time {
+	label = strings.TrimSpace(label)
+	if t, err := time.Parse(time.RFC3339, label); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation("2006-01-02", label, now.Location()); err == nil {
+		return t
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weekStart := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	switch strings.ToLower(label) {
+	case "today":
+		return today
+	case "yesterday":
+		return today.AddDate(0, 0, -1)
+	case "this week":
+		return weekStart
+	case "last week":
+		return weekStart.AddDate(0, 0, -7)
+	case "this month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	case "last month":
+		return time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+	}
+	if t, err := time.ParseInLocation("January 2006", label, now.Location()); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
 // GetPlaylist retrieves a specific playlist by ID without tracks.
 //
 // Calls GET /api/playlists/{id} on the proxy.
@@ -185,18 +449,48 @@ func (y *YouTubeService) GetPlaylist(ctx context.Context, playlistID string) (*m
 		DurationSeconds int    `json:"duration_seconds,omitempty"`
 	}
 
-	endpoint := fmt.Sprintf("/api/playlists/%s", playlistID)
+	endpoint := fmt.Sprintf("/api/playlists/%s", url.PathEscape(playlistID))
 	if err := y.doRequest(ctx, http.MethodGet, endpoint, nil, &ytPlaylist); err != nil {
 		return nil, err
 	}
 
-	return &models.Playlist{
+	playlist := &models.Playlist{
 		ID:          ytPlaylist.ID,
 		Name:        ytPlaylist.Title,
 		Description: ytPlaylist.Description,
 		TrackCount:  ytPlaylist.TrackCount,
 		Public:      ytPlaylist.Privacy == "PUBLIC",
-	}, nil
+		ExternalURL: models.PlaylistURL("youtube", ytPlaylist.ID),
+	}
+	if ytPlaylist.Author != nil {
+		playlist.Owner = ytPlaylist.Author.Name
+	}
+	return playlist, nil
+}
+
+// CoverImageURL returns the URL of a playlist's largest cover thumbnail.
+//
+// Calls GET /api/playlists/{id} on the proxy.
+func (y *YouTubeService) CoverImageURL(ctx context.Context, playlistID string) (string, error) {
+	var ytPlaylist struct {
+		Thumbnails []YouTubeImage `json:"thumbnails"`
+	}
+
+	endpoint := fmt.Sprintf("/api/playlists/%s", url.PathEscape(playlistID))
+	if err := y.doRequest(ctx, http.MethodGet, endpoint, nil, &ytPlaylist); err != nil {
+		return "", err
+	}
+	if len(ytPlaylist.Thumbnails) == 0 {
+		return "", fmt.Errorf("no image available")
+	}
+
+	largest := ytPlaylist.Thumbnails[0]
+	for _, thumbnail := range ytPlaylist.Thumbnails[1:] {
+		if thumbnail.Width*thumbnail.Height > largest.Width*largest.Height {
+			largest = thumbnail
+		}
+	}
+	return largest.URL, nil
 }
 
 // ExportPlaylist exports a playlist with all its tracks.
@@ -219,7 +513,7 @@ func (y *YouTubeService) ExportPlaylist(ctx context.Context, playlistID string)
 		DurationSeconds int    `json:"duration_seconds,omitempty"`
 	}
 
-	endpoint := fmt.Sprintf("/api/playlists/%s", playlistID)
+	endpoint := fmt.Sprintf("/api/playlists/%s", url.PathEscape(playlistID))
 	if err := y.doRequest(ctx, http.MethodGet, endpoint, nil, &ytPlaylist); err != nil {
 		return nil, err
 	}
@@ -230,26 +524,15 @@ func (y *YouTubeService) ExportPlaylist(ctx context.Context, playlistID string)
 		Description: ytPlaylist.Description,
 		TrackCount:  ytPlaylist.TrackCount,
 		Public:      ytPlaylist.Privacy == "PUBLIC",
+		ExternalURL: models.PlaylistURL("youtube", ytPlaylist.ID),
+	}
+	if ytPlaylist.Author != nil {
+		playlist.Owner = ytPlaylist.Author.Name
 	}
 
 	tracks := make([]models.Track, len(ytPlaylist.Tracks))
 	for i, ytt := range ytPlaylist.Tracks {
-		track := models.Track{
-			ID:       ytt.VideoID,
-			Title:    ytt.Title,
-			Duration: ytt.DurationSec,
-			ISRC:     ytt.ISRC,
-		}
-
-		if len(ytt.Artists) > 0 {
-			track.Artist = ytt.Artists[0].Name
-		}
-
-		if ytt.Album != nil {
-			track.Album = ytt.Album.Name
-		}
-
-		tracks[i] = track
+		tracks[i] = ytt.toTrack()
 	}
 
 	return &models.PlaylistExport{
@@ -260,7 +543,9 @@ func (y *YouTubeService) ExportPlaylist(ctx context.Context, playlistID string)
 
 // ImportPlaylist imports a playlist into YouTube Music.
 //
-// Creates the playlist via POST /api/playlists and adds tracks via POST /api/playlists/{id}/items.
+// Creates the playlist via POST /api/playlists and adds tracks via POST /api/playlists/{id}/items, in batches.
+// When adding tracks fails after the playlist was created, the playlist is returned with the error, e.g. a
+// [*BatchAddError] listing the batches that were not added.
 func (y *YouTubeService) ImportPlaylist(ctx context.Context, playlist *models.PlaylistExport) (*models.Playlist, error) {
 	createReq := struct {
 		Title         string `json:"title"`
@@ -269,139 +554,333 @@ func (y *YouTubeService) ImportPlaylist(ctx context.Context, playlist *models.Pl
 	}{
 		Title:         playlist.Playlist.Name,
 		Description:   playlist.Playlist.Description,
-		PrivacyStatus: "PRIVATE",
+		PrivacyStatus: privacyStatus(playlist.Playlist.Public),
 	}
 
-	if playlist.Playlist.Public {
-		createReq.PrivacyStatus = "PUBLIC"
+	var createResp struct {
+		PlaylistID string `json:"playlist_id"`
+	}
+	if err := y.doRequest(ctx, http.MethodPost, "/api/playlists", createReq, &createResp); err != nil {
+		return nil, fmt.Errorf("failed to create playlist: %w", err)
 	}
 
-	reqBody := fmt.Sprintf(`{"title":"%s","description":"%s","privacy_status":"%s"}`,
-		createReq.Title, createReq.Description, createReq.PrivacyStatus)
-
-	apiURL := y.baseURL + "/api/playlists"
-	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	created := &models.Playlist{
+		ID:          createResp.PlaylistID,
+		Name:        playlist.Playlist.Name,
+		Description: playlist.Playlist.Description,
+		TrackCount:  len(playlist.Tracks),
+		Public:      playlist.Playlist.Public,
+		ExternalURL: models.PlaylistURL("youtube", createResp.PlaylistID),
 	}
 
-	if y.authFile != "" {
-		req.Header.Set("X-Auth-File", y.authFile)
+	if err := y.AddTracks(ctx, createResp.PlaylistID, playlist.Tracks); err != nil {
+		created.TrackCount = 0
+		var batchErr *BatchAddError
+		if errors.As(err, &batchErr) {
+			created.TrackCount = batchErr.Added
+		}
+		return created, err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return created, nil
+}
 
-	resp, err := y.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+// AddTracks appends tracks to an existing playlist by video ID.
+//
+// Video IDs are sent in batches of [YouTubeAddBatchSize], one at a time and in order, so the playlist keeps the
+// order of tracks. A batch failing with a network error, a rate limit, or a server error is retried with a growing
+// delay; the batches that still fail are skipped and reported in a [*BatchAddError] once the rest were added.
+// Cancelling ctx stops between batches and reports the tracks not yet added the same way.
+//
+// Calls POST /api/playlists/{playlistID}/items on the proxy.
+func (y *YouTubeService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	batchErr := &BatchAddError{PlaylistID: playlistID, Total: len(tracks)}
+	for start := 0; start < len(tracks); start += YouTubeAddBatchSize {
+		end := min(start+YouTubeAddBatchSize, len(tracks))
+		if err := y.addBatch(ctx, playlistID, tracks[start:end]); err != nil {
+			// Once cancelled, the rest of the tracks are reported as one batch
+			if ctx.Err() != nil {
+				end = len(tracks)
+			}
+			batchErr.Failed = append(batchErr.Failed, FailedBatch{Start: start, End: end, Err: err})
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		batchErr.Added += end - start
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("failed to create playlist: status %d", resp.StatusCode)
+	if len(batchErr.Failed) > 0 {
+		return batchErr
 	}
+	return nil
+}
 
-	var createResp struct {
-		PlaylistID string `json:"playlist_id"`
+// addBatch posts one batch of video IDs, retrying it while it fails with a retryable error
+func (y *YouTubeService) addBatch(ctx context.Context, playlistID string, batch []models.Track) error {
+	videoIDs := make([]string, len(batch))
+	for i, track := range batch {
+		videoIDs[i] = track.ID
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
-		return nil, fmt.Errorf("failed to decode create response: %w", err)
+	addReq := struct {
+		VideoIDs []string `json:"video_ids"`
+	}{
+		VideoIDs: videoIDs,
 	}
+	endpoint := fmt.Sprintf("/api/playlists/%s/items", url.PathEscape(playlistID))
 
-	if len(playlist.Tracks) > 0 {
-		videoIDs := make([]string, len(playlist.Tracks))
-		for i, track := range playlist.Tracks {
-			videoIDs[i] = track.ID
+	delay := y.retryDelay
+	for attempt := 0; ; attempt++ {
+		err := y.doRequest(ctx, http.MethodPost, endpoint, addReq, nil)
+		if err == nil || attempt == youtubeAddRetries || !retryableAdd(err) {
+			return err
 		}
 
-		addReq := struct {
-			VideoIDs []string `json:"video_ids"`
-		}{
-			VideoIDs: videoIDs,
+		wait := delay
+		if after, ok := RetryAfter(err); ok {
+			wait = max(wait, after)
 		}
-
-		addBody, err := json.Marshal(addReq)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal add tracks request: %w", err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
 		}
+		delay *= 2
+	}
+}
 
-		addURL := fmt.Sprintf("%s/api/playlists/%s/items", y.baseURL, createResp.PlaylistID)
-		addReqHTTP, err := http.NewRequestWithContext(ctx, "POST", addURL, strings.NewReader(string(addBody)))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create add tracks request: %w", err)
-		}
+// retryableAdd reports whether a failed addition may succeed when sent again
+func retryableAdd(err error) bool {
+	var apiErr *YouTubeAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	// Network errors, e.g. a proxy timing out
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
 
-		if y.authFile != "" {
-			addReqHTTP.Header.Set("X-Auth-File", y.authFile)
-		}
-		addReqHTTP.Header.Set("Content-Type", "application/json")
+// BatchAddError reports the batches of tracks that could not be added to a playlist, even after retrying.
+// The tracks outside them were added.
+//
+// It unwraps to each batch's error, so [errors.Is], [errors.As], and [RetryAfter] see the errors it collects.
+type BatchAddError struct {
+	PlaylistID string
+	Total      int // Tracks that were to be added
+	Added      int // Tracks added by the batches that succeeded
+	Failed     []FailedBatch
+}
 
-		addResp, err := y.httpClient.Do(addReqHTTP)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add tracks: %w", err)
+// FailedBatch is a batch of tracks that could not be added, as the range [Start, End) of the tracks being added.
+type FailedBatch struct {
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *BatchAddError) Error() string {
+	ranges := make([]string, len(e.Failed))
+	for i, batch := range e.Failed {
+		ranges[i] = fmt.Sprintf("tracks %d-%d: %v", batch.Start+1, batch.End, batch.Err)
+	}
+	return fmt.Sprintf("added %d of %d tracks to playlist %s; %d batches failed (%s)",
+		e.Added, e.Total, e.PlaylistID, len(e.Failed), strings.Join(ranges, "; "))
+}
+
+func (e *BatchAddError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, batch := range e.Failed {
+		errs[i] = batch.Err
+	}
+	return errs
+}
+
+// playlistItems returns the items of a playlist with their setVideoId, in playlist order.
+//
+// Calls GET /api/playlists/{id} on the proxy.
+func (y *YouTubeService) playlistItems(ctx context.Context, playlistID string) ([]YouTubeTrack, error) {
+	var ytPlaylist struct {
+		Tracks []YouTubeTrack `json:"tracks"`
+	}
+
+	endpoint := fmt.Sprintf("/api/playlists/%s", url.PathEscape(playlistID))
+	if err := y.doRequest(ctx, http.MethodGet, endpoint, nil, &ytPlaylist); err != nil {
+		return nil, err
+	}
+	return ytPlaylist.Tracks, nil
+}
+
+// playlistItem identifies one entry of a playlist for the proxy
+type playlistItem struct {
+	VideoID    string `json:"video_id"`
+	SetVideoID string `json:"set_video_id"`
+}
+
+// RemoveTracks removes every occurrence of tracks from a playlist by video ID.
+//
+// Looks up the playlist's setVideoIds, then calls DELETE /api/playlists/{playlistID}/items on the proxy.
+func (y *YouTubeService) RemoveTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	remove := make(map[string]bool, len(tracks))
+	for _, track := range tracks {
+		remove[track.ID] = true
+	}
+
+	items, err := y.playlistItems(ctx, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist items: %w", err)
+	}
+
+	removeReq := struct {
+		Items []playlistItem `json:"items"`
+	}{}
+	for _, item := range items {
+		if remove[item.VideoID] {
+			removeReq.Items = append(removeReq.Items, playlistItem{VideoID: item.VideoID, SetVideoID: item.SetVideoID})
 		}
-		defer addResp.Body.Close()
+	}
+	if len(removeReq.Items) == 0 {
+		return nil
+	}
 
-		if addResp.StatusCode < 200 || addResp.StatusCode >= 300 {
-			return nil, fmt.Errorf("failed to add tracks to playlist: status %d", addResp.StatusCode)
+	endpoint := fmt.Sprintf("/api/playlists/%s/items", url.PathEscape(playlistID))
+	if err := y.doRequest(ctx, http.MethodDelete, endpoint, removeReq, nil); err != nil {
+		return fmt.Errorf("failed to remove tracks: %w", err)
+	}
+	return nil
+}
+
+// ReorderTracks moves rangeLength items starting at rangeStart to before insertBefore.
+//
+// YouTube Music moves one item at a time, so each item in the range is moved before the same anchor item,
+// keeping their order. Calls POST /api/playlists/{playlistID}/items/move on the proxy once per item.
+func (y *YouTubeService) ReorderTracks(ctx context.Context, playlistID string, rangeStart, rangeLength, insertBefore int) error {
+	items, err := y.playlistItems(ctx, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist items: %w", err)
+	}
+
+	rangeEnd := rangeStart + rangeLength
+	if rangeStart < 0 || rangeLength < 1 || rangeEnd > len(items) || insertBefore < 0 || insertBefore > len(items) {
+		return fmt.Errorf("%w: invalid range %d+%d before %d in a playlist of %d items",
+			shared.ErrInvalidArgument, rangeStart, rangeLength, insertBefore, len(items))
+	}
+	if insertBefore >= rangeStart && insertBefore <= rangeEnd {
+		return nil // Already in place
+	}
+
+	var before string // Empty moves items to the end of the playlist
+	if insertBefore < len(items) {
+		before = items[insertBefore].SetVideoID
+	}
+
+	endpoint := fmt.Sprintf("/api/playlists/%s/items/move", url.PathEscape(playlistID))
+	for _, item := range items[rangeStart:rangeEnd] {
+		moveReq := struct {
+			SetVideoID       string `json:"set_video_id"`
+			BeforeSetVideoID string `json:"before_set_video_id,omitempty"`
+		}{
+			SetVideoID:       item.SetVideoID,
+			BeforeSetVideoID: before,
+		}
+		if err := y.doRequest(ctx, http.MethodPost, endpoint, moveReq, nil); err != nil {
+			return fmt.Errorf("failed to move track %s: %w", item.VideoID, err)
 		}
 	}
+	return nil
+}
 
-	return &models.Playlist{
-		ID:          createResp.PlaylistID,
-		Name:        playlist.Playlist.Name,
-		Description: playlist.Playlist.Description,
-		TrackCount:  len(playlist.Tracks),
-		Public:      playlist.Playlist.Public,
-	}, nil
+// DeletePlaylist deletes a playlist owned by the user.
+//
+// Calls DELETE /api/playlists/{playlistID} on the proxy.
+func (y *YouTubeService) DeletePlaylist(ctx context.Context, playlistID string) error {
+	endpoint := fmt.Sprintf("/api/playlists/%s", url.PathEscape(playlistID))
+	if err := y.doRequest(ctx, http.MethodDelete, endpoint, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete playlist: %w", err)
+	}
+	return nil
 }
 
-// SearchTrack searches for a track by title and artist, returning the best match.
+// UpdatePlaylistMetadata changes a playlist's title, description, or privacy, leaving the fields update omits.
 //
-// Calls GET /api/search?q={title} {artist}&filter=songs on the proxy.
-func (y *YouTubeService) SearchTrack(ctx context.Context, title, artist string) (*models.Track, error) {
-	query := fmt.Sprintf("%s %s", title, artist)
-	endpoint := fmt.Sprintf("/api/search?q=%s&filter=songs", url.QueryEscape(query))
+// Calls PATCH /api/playlists/{playlistID} on the proxy.
+func (y *YouTubeService) UpdatePlaylistMetadata(ctx context.Context, playlistID string, update PlaylistUpdate) error {
+	editReq := struct {
+		Title         *string `json:"title,omitempty"`
+		Description   *string `json:"description,omitempty"`
+		PrivacyStatus string  `json:"privacy_status,omitempty"`
+	}{
+		Title:       update.Name,
+		Description: update.Description,
+	}
+	if update.Public != nil {
+		editReq.PrivacyStatus = privacyStatus(*update.Public)
+	}
+	if editReq.Title == nil && editReq.Description == nil && editReq.PrivacyStatus == "" {
+		return nil
+	}
 
-	var results []struct {
-		VideoID string          `json:"videoId"`
-		Title   string          `json:"title"`
-		Artists []YouTubeArtist `json:"artists"`
-		Album   *struct {
-			Name string `json:"name"`
-		} `json:"album"`
-		Duration       string `json:"duration"`
-		DurationSec    int    `json:"duration_seconds"`
-		ISRC           string `json:"isrc,omitempty"`
-		IsExplicit     bool   `json:"isExplicit,omitempty"`
-		ResultType     string `json:"resultType,omitempty"`
-		FeedbackTokens *struct {
-			Add    *string `json:"add"`
-			Remove *string `json:"remove"`
-		} `json:"feedbackTokens,omitempty"`
+	endpoint := fmt.Sprintf("/api/playlists/%s", url.PathEscape(playlistID))
+	if err := y.doRequest(ctx, http.MethodPatch, endpoint, editReq, nil); err != nil {
+		return fmt.Errorf("failed to update playlist: %w", err)
 	}
+	return nil
+}
 
-	if err := y.doRequest(ctx, http.MethodGet, endpoint, nil, &results); err != nil {
-		return nil, err
+// privacyStatus returns the proxy's privacy status for a public or private playlist
+func privacyStatus(public bool) string {
+	if public {
+		return "PUBLIC"
 	}
-	if len(results) == 0 {
-		return nil, fmt.Errorf("no results found for '%s' by '%s'", title, artist)
+	return "PRIVATE"
+}
+
+// SetPlaylistCover uploads a custom cover image for a playlist.
+//
+// Calls POST /api/playlists/{playlistID}/cover on the proxy with the raw image as the request body.
+func (y *YouTubeService) SetPlaylistCover(ctx context.Context, playlistID string, image []byte, contentType string) error {
+	if len(image) == 0 {
+		return fmt.Errorf("empty cover image")
 	}
 
-	result := results[0]
-	track := &models.Track{
-		ID:       result.VideoID,
-		Title:    result.Title,
-		Duration: result.DurationSec,
-		ISRC:     result.ISRC,
+	endpoint := fmt.Sprintf("/api/playlists/%s/cover", url.PathEscape(playlistID))
+	if err := y.doRequest(ctx, http.MethodPost, endpoint, rawBody{data: image, contentType: contentType}, nil); err != nil {
+		return fmt.Errorf("failed to upload cover: %w", err)
 	}
+	return nil
+}
 
-	if len(result.Artists) > 0 {
-		track.Artist = result.Artists[0].Name
+// SearchTrack searches for a track by title and artist, returning the best match.
+//
+// The best match is the top result of [YouTubeService.SearchTracks].
+func (y *YouTubeService) SearchTrack(ctx context.Context, title, artist string) (*models.Track, error) {
+	candidates, err := y.SearchTracks(ctx, title, artist, 1)
+	if err != nil {
+		return nil, err
 	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no results found for '%s' by '%s'", shared.ErrTrackNotFound, title, artist)
+	}
+	return &candidates[0], nil
+}
+
+// SearchTracks searches for up to limit songs by title and artist, in YouTube Music's relevance order.
+//
+// Calls GET /api/search?q={title} {artist}&filter=songs on the proxy.
+func (y *YouTubeService) SearchTracks(ctx context.Context, title, artist string, limit int) ([]models.Track, error) {
+	query := fmt.Sprintf("%s %s", title, artist)
+	endpoint := fmt.Sprintf("/api/search?q=%s&filter=songs", url.QueryEscape(query))
 
-	if result.Album != nil {
-		track.Album = result.Album.Name
+	var results []YouTubeTrack
+	if err := y.doRequest(ctx, http.MethodGet, endpoint, nil, &results); err != nil {
+		return nil, err
 	}
 
-	return track, nil
+	candidates := make([]models.Track, 0, min(len(results), max(limit, 1)))
+	for _, result := range results[:cap(candidates)] {
+		candidates = append(candidates, result.toTrack())
+	}
+	return candidates, nil
 }
@@ -3,11 +3,21 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+	tu "github.com/desertthunder/ytx/internal/testing"
 )
 
 func TestYouTubeService(t *testing.T) {
@@ -118,6 +128,74 @@ func TestYouTubeService(t *testing.T) {
 		}
 	})
 
+	t.Run("GetPlaylists follows continuations", func(t *testing.T) {
+		var continuations []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("limit") != "100" {
+				t.Errorf("expected limit=100, got %s", r.URL.RawQuery)
+			}
+			continuation := r.URL.Query().Get("continuation")
+			continuations = append(continuations, continuation)
+
+			page := map[string]any{"items": []map[string]any{{"playlistId": "PL1"}}, "continuation": "next"}
+			if continuation == "next" {
+				page = map[string]any{"items": []map[string]any{{"playlistId": "PL2"}, {"playlistId": "PL3"}}}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(page)
+		}))
+		defer server.Close()
+
+		playlists, err := NewYouTubeService(server.URL).GetPlaylists(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(playlists) != 3 || playlists[0].ID != "PL1" || playlists[2].ID != "PL3" {
+			t.Errorf("expected playlists from both pages in order, got %+v", playlists)
+		}
+		if len(continuations) != 2 || continuations[0] != "" || continuations[1] != "next" {
+			t.Errorf("unexpected continuations %q", continuations)
+		}
+	})
+
+	t.Run("History and LikedSongs", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/api/library/history":
+				w.Write([]byte(`[
+					{"videoId": "v1", "title": "Now", "artists": [{"name": "A"}], "played": "2024-03-01T12:00:00Z"},
+					{"videoId": "v2", "title": "Ages Ago", "played": "Some time"}
+				]`))
+			case "/api/library/liked-songs":
+				w.Write([]byte(`{"items": [{"videoId": "v3", "title": "Liked", "album": {"name": "LP"}}]}`))
+			default:
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+		history, err := svc.History(context.Background())
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		if len(history) != 2 || history[0].Artist() != "A" || !history[0].AddedAt.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected history %+v", history)
+		}
+		if !history[1].AddedAt.IsZero() {
+			t.Errorf("expected an undatable entry to have no play time, got %v", history[1].AddedAt)
+		}
+
+		liked, err := svc.LikedSongs(context.Background())
+		if err != nil {
+			t.Fatalf("LikedSongs() error = %v", err)
+		}
+		if len(liked) != 1 || liked[0].ID != "v3" || liked[0].Album != "LP" {
+			t.Errorf("unexpected liked songs %+v", liked)
+		}
+	})
+
 	t.Run("GetPlaylist", func(t *testing.T) {
 		mockPlaylist := map[string]any{
 			"id":          "PL123",
@@ -151,6 +229,38 @@ func TestYouTubeService(t *testing.T) {
 		}
 	})
 
+	t.Run("CoverImageURL", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/playlists/PL123" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "PL123",
+				"thumbnails": []map[string]any{
+					{"url": "https://img/small.jpg", "width": 60, "height": 60},
+					{"url": "https://img/large.jpg", "width": 544, "height": 544},
+					{"url": "https://img/medium.jpg", "width": 226, "height": 226},
+				},
+			})
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+		url, err := svc.CoverImageURL(context.Background(), "PL123")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if url != "https://img/large.jpg" {
+			t.Errorf("expected the largest thumbnail, got %s", url)
+		}
+
+		if _, err := svc.CoverImageURL(context.Background(), "missing"); err == nil {
+			t.Error("expected an error for a missing playlist")
+		}
+	})
+
 	t.Run("ExportPlaylist", func(t *testing.T) {
 		mockPlaylist := map[string]any{
 			"id":          "PL123",
@@ -209,8 +319,8 @@ func TestYouTubeService(t *testing.T) {
 		if track1.Title != "Song 1" {
 			t.Errorf("expected track title 'Song 1', got %s", track1.Title)
 		}
-		if track1.Artist != "Artist 1" {
-			t.Errorf("expected artist 'Artist 1', got %s", track1.Artist)
+		if track1.Artist() != "Artist 1" {
+			t.Errorf("expected artist 'Artist 1', got %s", track1.Artist())
 		}
 		if track1.Album != "Album 1" {
 			t.Errorf("expected album 'Album 1', got %s", track1.Album)
@@ -221,9 +331,237 @@ func TestYouTubeService(t *testing.T) {
 		if track1.ISRC != "USABC1234567" {
 			t.Errorf("expected ISRC USABC1234567, got %s", track1.ISRC)
 		}
+		if track1.ExternalURL != "https://music.youtube.com/watch?v=vid1" {
+			t.Errorf("expected the track link, got %s", track1.ExternalURL)
+		}
+		if export.Playlist.ExternalURL != "https://music.youtube.com/playlist?list=PL123" {
+			t.Errorf("expected the playlist link, got %s", export.Playlist.ExternalURL)
+		}
+	})
+
+	t.Run("escapes playlist IDs in paths", func(t *testing.T) {
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.EscapedPath())
+			json.NewEncoder(w).Encode(map[string]any{"id": "PL/1?x", "tracks": []map[string]string{{"videoId": "a", "setVideoId": "sa"}}})
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+		if _, err := svc.ExportPlaylist(context.Background(), "PL/1?x"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := svc.RemoveTracks(context.Background(), "PL/1?x", []models.Track{{ID: "a"}}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, path := range paths {
+			if !strings.HasPrefix(path, "/api/playlists/PL%2F1%3Fx") {
+				t.Errorf("expected the playlist ID escaped, got %s", path)
+			}
+		}
+	})
+
+	t.Run("RemoveTracks and ReorderTracks", func(t *testing.T) {
+		var removed []map[string]string
+		var moves []map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/playlists/PL1" && r.Method == http.MethodGet:
+				json.NewEncoder(w).Encode(map[string]any{"id": "PL1", "tracks": []map[string]string{
+					{"videoId": "a", "setVideoId": "sa"},
+					{"videoId": "b", "setVideoId": "sb"},
+					{"videoId": "a", "setVideoId": "sa2"},
+					{"videoId": "c", "setVideoId": "sc"},
+				}})
+			case r.URL.Path == "/api/playlists/PL1/items" && r.Method == http.MethodDelete:
+				var req struct {
+					Items []map[string]string `json:"items"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				removed = req.Items
+			case r.URL.Path == "/api/playlists/PL1/items/move" && r.Method == http.MethodPost:
+				var req map[string]string
+				json.NewDecoder(r.Body).Decode(&req)
+				moves = append(moves, req)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+
+		if err := svc.RemoveTracks(context.Background(), "PL1", []models.Track{{ID: "a"}}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(removed) != 2 || removed[0]["set_video_id"] != "sa" || removed[1]["set_video_id"] != "sa2" {
+			t.Errorf("expected every occurrence of a to be removed, got %v", removed)
+		}
+
+		// Move a, b to the end, then c to the front
+		if err := svc.ReorderTracks(context.Background(), "PL1", 0, 2, 4); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := svc.ReorderTracks(context.Background(), "PL1", 3, 1, 0); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := []map[string]string{
+			{"set_video_id": "sa"},
+			{"set_video_id": "sb"},
+			{"set_video_id": "sc", "before_set_video_id": "sa"},
+		}
+		if len(moves) != len(want) {
+			t.Fatalf("expected %d moves, got %v", len(want), moves)
+		}
+		for i := range want {
+			if moves[i]["set_video_id"] != want[i]["set_video_id"] || moves[i]["before_set_video_id"] != want[i]["before_set_video_id"] {
+				t.Errorf("move %d = %v, want %v", i, moves[i], want[i])
+			}
+		}
+
+		if err := svc.ReorderTracks(context.Background(), "PL1", 1, 1, 2); err != nil || len(moves) != len(want) {
+			t.Errorf("expected a no-op for a range already in place, got %v", err)
+		}
+		if err := svc.ReorderTracks(context.Background(), "PL1", 3, 2, 0); !errors.Is(err, shared.ErrInvalidArgument) {
+			t.Errorf("expected ErrInvalidArgument for a range past the end, got %v", err)
+		}
+	})
+
+	t.Run("DeletePlaylist and UpdatePlaylistMetadata", func(t *testing.T) {
+		var methods []string
+		var bodies []map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/playlists/PL1" {
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			methods = append(methods, r.Method)
+			if r.Method == http.MethodPatch {
+				var body map[string]any
+				json.NewDecoder(r.Body).Decode(&body)
+				bodies = append(bodies, body)
+			}
+			w.Write([]byte(`{"status": "success"}`))
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+		ctx := context.Background()
+		if err := svc.DeletePlaylist(ctx, "PL1"); err != nil {
+			t.Fatalf("DeletePlaylist() error = %v", err)
+		}
+
+		title, public := "Renamed", false
+		if err := svc.UpdatePlaylistMetadata(ctx, "PL1", PlaylistUpdate{Name: &title, Public: &public}); err != nil {
+			t.Fatalf("UpdatePlaylistMetadata() error = %v", err)
+		}
+		empty := ""
+		if err := svc.UpdatePlaylistMetadata(ctx, "PL1", PlaylistUpdate{Description: &empty}); err != nil {
+			t.Fatalf("UpdatePlaylistMetadata() error = %v", err)
+		}
+		if err := svc.UpdatePlaylistMetadata(ctx, "PL1", PlaylistUpdate{}); err != nil {
+			t.Fatalf("UpdatePlaylistMetadata() error = %v", err)
+		}
+
+		if len(methods) != 3 || methods[0] != http.MethodDelete || methods[1] != http.MethodPatch {
+			t.Fatalf("expected a DELETE and two PATCH requests, got %v", methods)
+		}
+		if len(bodies[0]) != 2 || bodies[0]["title"] != "Renamed" || bodies[0]["privacy_status"] != "PRIVATE" {
+			t.Errorf("expected only the title and privacy, got %v", bodies[0])
+		}
+		if description, ok := bodies[1]["description"]; len(bodies[1]) != 1 || !ok || description != "" {
+			t.Errorf("expected an empty description to be sent, got %v", bodies[1])
+		}
+	})
+
+	t.Run("DeletePlaylist not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail": "Playlist not found"}`))
+		}))
+		defer server.Close()
+
+		err := NewYouTubeService(server.URL).DeletePlaylist(context.Background(), "missing")
+		if !errors.Is(err, shared.ErrAPIRequest) || !strings.Contains(err.Error(), "Playlist not found") {
+			t.Errorf("expected the proxy's error, got %v", err)
+		}
+	})
+
+	t.Run("SetPlaylistCover", func(t *testing.T) {
+		var body []byte
+		var contentType, authFile string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/playlists/PL123/cover" || r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{"detail": "unknown playlist"})
+				return
+			}
+			body, _ = io.ReadAll(r.Body)
+			contentType = r.Header.Get("Content-Type")
+			authFile = r.Header.Get("X-Auth-File")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+		svc.Authenticate(context.Background(), map[string]string{"auth_file": "browser.json"})
+
+		if err := svc.SetPlaylistCover(context.Background(), "PL123", []byte("jpeg"), "image/jpeg"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if string(body) != "jpeg" || contentType != "image/jpeg" || authFile != "browser.json" {
+			t.Errorf("unexpected upload: body %q, content type %q, auth file %q", body, contentType, authFile)
+		}
+
+		err := svc.SetPlaylistCover(context.Background(), "missing", []byte("jpeg"), "image/jpeg")
+		if err == nil || !strings.Contains(err.Error(), "unknown playlist") {
+			t.Errorf("expected proxy error detail, got %v", err)
+		}
+	})
+
+	t.Run("doRequest", func(t *testing.T) {
+		type seen struct{ method, contentType, authFile, body string }
+		var requests []seen
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, seen{r.Method, r.Header.Get("Content-Type"), r.Header.Get("X-Auth-File"), string(body)})
+			w.Write([]byte(`{"ok": true}`))
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+		svc.authFile = "/path/to/auth.json"
+
+		var result struct {
+			OK bool `json:"ok"`
+		}
+		if err := svc.doRequest(context.Background(), http.MethodGet, "/api/x", nil, &result); err != nil || !result.OK {
+			t.Fatalf("GET failed: %v", err)
+		}
+		if err := svc.doRequest(context.Background(), http.MethodDelete, "/api/x", map[string]string{"a": "b"}, nil); err != nil {
+			t.Fatalf("DELETE failed: %v", err)
+		}
+		if err := svc.doRequest(context.Background(), http.MethodPut, "/api/x", rawBody{data: []byte("raw"), contentType: "text/plain"}, nil); err != nil {
+			t.Fatalf("PUT failed: %v", err)
+		}
+
+		want := []seen{
+			{http.MethodGet, "", "/path/to/auth.json", ""},
+			{http.MethodDelete, "application/json", "/path/to/auth.json", `{"a":"b"}`},
+			{http.MethodPut, "text/plain", "/path/to/auth.json", "raw"},
+		}
+		if len(requests) != len(want) {
+			t.Fatalf("expected %d requests, got %d", len(want), len(requests))
+		}
+		for i := range want {
+			if requests[i] != want[i] {
+				t.Errorf("request %d = %+v, want %+v", i, requests[i], want[i])
+			}
+		}
 	})
 
 	t.Run("ImportPlaylist", func(t *testing.T) {
+		const importTitle = `Import "Test" \ Mix`
+		const importDescription = "Test \"import\"\nline two\t<b>&</b> — ünïcode"
 		var createdPlaylistID string
 		var receivedTracks []string
 
@@ -236,8 +574,11 @@ func TestYouTubeService(t *testing.T) {
 				}
 				json.NewDecoder(r.Body).Decode(&req)
 
-				if req.Title != "Import Test" {
-					t.Errorf("expected title 'Import Test', got %s", req.Title)
+				if req.Title != importTitle {
+					t.Errorf("expected title %q to round-trip, got %q", importTitle, req.Title)
+				}
+				if req.Description != importDescription {
+					t.Errorf("expected description %q to round-trip, got %q", importDescription, req.Description)
 				}
 				if req.PrivacyStatus != "PUBLIC" {
 					t.Errorf("expected privacy_status PUBLIC, got %s", req.PrivacyStatus)
@@ -270,7 +611,7 @@ func TestYouTubeService(t *testing.T) {
 		svc.authFile = "/path/to/auth.json"
 
 		export := &models.PlaylistExport{
-			Playlist: models.Playlist{Name: "Import Test", Description: "Test import", Public: true},
+			Playlist: models.Playlist{Name: importTitle, Description: importDescription, Public: true},
 			Tracks:   []models.Track{{ID: "vid1", Title: "Track 1"}, {ID: "vid2", Title: "Track 2"}},
 		}
 
@@ -282,8 +623,8 @@ func TestYouTubeService(t *testing.T) {
 		if result.ID != "PL_NEW_123" {
 			t.Errorf("expected playlist ID PL_NEW_123, got %s", result.ID)
 		}
-		if result.Name != "Import Test" {
-			t.Errorf("expected name 'Import Test', got %s", result.Name)
+		if result.Name != importTitle || result.Description != importDescription {
+			t.Errorf("expected the name and description as imported, got %q and %q", result.Name, result.Description)
 		}
 		if !result.Public {
 			t.Error("expected playlist to be public")
@@ -297,15 +638,72 @@ func TestYouTubeService(t *testing.T) {
 		}
 	})
 
+	t.Run("ImportPlaylist adds tracks in batches", func(t *testing.T) {
+		attempts := make(map[string]int)
+		var added []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/playlists" {
+				json.NewEncoder(w).Encode(map[string]string{"playlist_id": "PL1"})
+				return
+			}
+
+			var req struct {
+				VideoIDs []string `json:"video_ids"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			first := req.VideoIDs[0]
+			attempts[first]++
+			switch {
+			case first == "v50" && attempts[first] == 1:
+				w.WriteHeader(http.StatusBadGateway)
+				w.Write([]byte(`{"detail": "proxy timed out"}`))
+			case first == "v100":
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"detail": "invalid video id"}`))
+			default:
+				added = append(added, req.VideoIDs...)
+				w.Write([]byte(`{"status": "success"}`))
+			}
+		}))
+		defer server.Close()
+
+		svc := NewYouTubeService(server.URL)
+		svc.retryDelay = 0
+
+		tracks := make([]models.Track, 120)
+		for i := range tracks {
+			tracks[i] = models.Track{ID: "v" + strconv.Itoa(i)}
+		}
+		playlist, err := svc.ImportPlaylist(context.Background(), &models.PlaylistExport{Playlist: models.Playlist{Name: "Big"}, Tracks: tracks})
+
+		var batchErr *BatchAddError
+		if !errors.As(err, &batchErr) || !errors.Is(err, shared.ErrAPIRequest) {
+			t.Fatalf("expected a BatchAddError, got %v", err)
+		}
+		if playlist == nil || playlist.ID != "PL1" || playlist.TrackCount != 100 {
+			t.Errorf("expected the created playlist with 100 tracks, got %+v", playlist)
+		}
+		if batchErr.Added != 100 || len(batchErr.Failed) != 1 || batchErr.Failed[0].Start != 100 || batchErr.Failed[0].End != 120 {
+			t.Errorf("expected only tracks 101-120 to fail, got %+v", batchErr)
+		}
+		if len(added) != 100 || added[0] != "v0" || added[50] != "v50" || added[99] != "v99" {
+			t.Errorf("expected the first 100 tracks added in order, got %d", len(added))
+		}
+		if attempts["v0"] != 1 || attempts["v50"] != 2 || attempts["v100"] != 1 {
+			t.Errorf("expected only the server error to be retried, got %v", attempts)
+		}
+	})
+
 	t.Run("SearchTrack", func(t *testing.T) {
 		mockResults := []map[string]any{
 			{
 				"videoId":          "vid123",
 				"title":            "Harder Better Faster Stronger",
-				"artists":          []map[string]any{{"name": "Daft Punk", "id": "art1"}},
+				"artists":          []map[string]any{{"name": "Daft Punk", "id": "art1"}, {"name": "Pharrell Williams", "id": "art2"}},
 				"album":            map[string]any{"name": "Discovery"},
 				"duration_seconds": 224,
 				"isrc":             "USVIRGIN01234",
+				"isExplicit":       true,
 			},
 		}
 
@@ -341,8 +739,14 @@ func TestYouTubeService(t *testing.T) {
 		if track.Title != "Harder Better Faster Stronger" {
 			t.Errorf("expected title 'Harder Better Faster Stronger', got %s", track.Title)
 		}
-		if track.Artist != "Daft Punk" {
-			t.Errorf("expected artist 'Daft Punk', got %s", track.Artist)
+		if track.Artist() != "Daft Punk" {
+			t.Errorf("expected artist 'Daft Punk', got %s", track.Artist())
+		}
+		if !slices.Equal(track.Artists, []string{"Daft Punk", "Pharrell Williams"}) {
+			t.Errorf("expected every artist credited, got %v", track.Artists)
+		}
+		if !track.Explicit {
+			t.Errorf("expected the explicit flag kept")
 		}
 		if track.Album != "Discovery" {
 			t.Errorf("expected album 'Discovery', got %s", track.Album)
@@ -392,6 +796,24 @@ func TestYouTubeService(t *testing.T) {
 			}
 		})
 
+		t.Run("handles 429 rate limited", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "5")
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(map[string]string{"detail": "Too many requests"})
+			}))
+			defer server.Close()
+
+			svc := NewYouTubeService(server.URL)
+			_, err := svc.SearchTrack(context.Background(), "Song", "Artist")
+			if wait, ok := RetryAfter(err); !ok || wait != 5*time.Second {
+				t.Fatalf("expected a 5s rate limit, got %v, %v (error %v)", wait, ok, err)
+			}
+			if !strings.Contains(err.Error(), "Too many requests") {
+				t.Errorf("expected the detail in the error, got %v", err)
+			}
+		})
+
 		t.Run("handles 500 internal error", func(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(http.StatusInternalServerError)
@@ -406,3 +828,126 @@ func TestYouTubeService(t *testing.T) {
 		})
 	})
 }
+
+// youtubeFixture returns a YouTube Music service replaying the fixture testdata/youtube/<name>.json.
+//
+// With YTX_RECORD set, it records the fixture from the proxy at YTX_PROXY_URL instead, authenticated with the
+// auth file at YTX_AUTH_FILE.
+func youtubeFixture(t *testing.T, name string) *YouTubeService {
+	t.Helper()
+	recorder := tu.NewRecorder(t, filepath.Join("testdata", "youtube", name+".json"))
+
+	svc := NewYouTubeService("")
+	svc.SetHTTPClient(recorder.Client())
+	svc.authFile = "/fixture/browser.json"
+	svc.retryDelay = time.Millisecond
+	if recorder.Recording() {
+		svc = NewYouTubeService(os.Getenv("YTX_PROXY_URL"))
+		svc.SetHTTPClient(recorder.Client())
+		svc.authFile = os.Getenv("YTX_AUTH_FILE")
+	}
+	return svc
+}
+
+func TestYouTubeFixtures(t *testing.T) {
+	t.Run("GetPlaylists follows continuations until there are none", func(t *testing.T) {
+		playlists, err := youtubeFixture(t, "playlists").GetPlaylists(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		names := make([]string, len(playlists))
+		for i, p := range playlists {
+			names[i] = p.Name
+		}
+		if want := []string{"Liked Music", "Late Night", "Gym"}; !slices.Equal(names, want) {
+			t.Fatalf("expected playlists %q, got %q", want, names)
+		}
+		if playlists[0].Public || !playlists[1].Public || playlists[2].Public {
+			t.Error("expected only Late Night to be public, as Liked Music has no privacy")
+		}
+		if playlists[0].TrackCount != 912 {
+			t.Errorf("expected 912 liked songs, got %d", playlists[0].TrackCount)
+		}
+	})
+
+	t.Run("ExportPlaylist reads tracks without albums and unavailable tracks", func(t *testing.T) {
+		export, err := youtubeFixture(t, "export").ExportPlaylist(context.Background(), "PLQwVIlKxHM6qv-o99iX8R3T-nMXpL6ad2")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if export.Playlist.Name != "Late Night" || !export.Playlist.Public || len(export.Tracks) != 3 {
+			t.Fatalf("expected public Late Night with 3 tracks, got %+v with %d", export.Playlist, len(export.Tracks))
+		}
+		if export.Playlist.Owner != "user" {
+			t.Errorf("expected the author as owner, got %q", export.Playlist.Owner)
+		}
+
+		if track := export.Tracks[0]; track.Album != "In Rainbows" || track.Duration != 255 || track.ExternalURL == "" {
+			t.Errorf("unexpected track %+v", track)
+		}
+		remix := export.Tracks[1]
+		if remix.Album != "" || !remix.Explicit || !slices.Equal(remix.Artists, []string{"The Weeknd", "Ariana Grande"}) {
+			t.Errorf("expected an explicit track without album crediting both artists, got %+v", remix)
+		}
+		if export.Tracks[1].Unavailable || !export.Tracks[2].Unavailable {
+			t.Error("expected only the last track to be unavailable")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		svc := youtubeFixture(t, "errors")
+		ctx := context.Background()
+
+		var apiErr *YouTubeAPIError
+		_, err := svc.ExportPlaylist(ctx, "PLmissing")
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Detail != "Playlist not found: PLmissing" {
+			t.Errorf("expected a 404 with the proxy's detail, got %v", err)
+		}
+
+		_, err = svc.GetPlaylists(ctx)
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || !strings.Contains(apiErr.Detail, "expired") {
+			t.Errorf("expected a 401 with the proxy's detail, got %v", err)
+		}
+
+		_, err = svc.SearchTrack(ctx, "Nude", "Radiohead")
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError || apiErr.Detail != "" {
+			t.Errorf("expected a 500 without detail from a plain text body, got %v", err)
+		}
+
+		export := &models.PlaylistExport{
+			Playlist: models.Playlist{Name: "Late Night"},
+			Tracks:   []models.Track{{ID: "lYBUbBu4W08", Title: "Nude"}},
+		}
+		playlist, err := svc.ImportPlaylist(ctx, export)
+		if err != nil {
+			t.Fatalf("expected the rate limited batch to be retried, got %v", err)
+		}
+		if playlist.ID != "PLQwVIlKxHM6pCreated0000000000000" {
+			t.Errorf("unexpected playlist %+v", playlist)
+		}
+	})
+}
+
+func TestPlayedAt(t *testing.T) {
+	now := time.Date(2024, 3, 14, 18, 30, 0, 0, time.UTC) // A Thursday
+	tests := []struct {
+		label string
+		want  time.Time
+	}{
+		{"Today", time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"This week", time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"Last week", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"Last month", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"January 2023", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-02-29", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"", time.Time{}},
+		{"Long ago", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := playedAt(tt.label, now); !got.Equal(tt.want) {
+			t.Errorf("playedAt(%q) = %v, want %v", tt.label, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RestoredTable reports the rows a restore copied into one table.
+type RestoredTable struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// RestoreResult summarizes a restore from a backup archive.
+type RestoreResult struct {
+	BackupVersion int             `json:"backup_version"` // Schema version the backup was made at
+	SchemaVersion int             `json:"schema_version"` // Schema version of the restored database
+	Tables        []RestoredTable `json:"tables"`
+}
+
+// CreateBackup writes a consistent copy of the whole database to a new SQLite file at path, with VACUUM INTO.
+//
+// The archive is a portable, self-contained database that [RestoreBackup] can load on another machine.
+// It fails rather than overwrite an existing file.
+func CreateBackup(ctx context.Context, db *sql.DB, path string) error {
+	if err := RequireSQLite(db, "backup"); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%w: %s already exists", ErrInvalidArgument, path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to check backup path: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// RestoreBackup replaces the contents of every table of db, which must be migrated, with the rows of a backup
+// made by [CreateBackup], in a single transaction so a failed restore leaves db as it was.
+//
+// A backup from an older schema is migrated first, on a temporary copy so the archive itself is left untouched.
+// Backups from a newer schema, and files that are not ytx databases, fail with [ErrInvalidInput]. Full-text
+// indexes are rebuilt by their triggers as rows are copied; the migration history of db is kept.
+func RestoreBackup(ctx context.Context, db *sql.DB, path string) (*RestoreResult, error) {
+	if err := RequireSQLite(db, "restore"); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("%w: backup %s: %w", ErrInvalidArgument, path, err)
+	}
+
+	dir, err := os.MkdirTemp("", "ytx-restore-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	copyPath := filepath.Join(dir, "backup.db")
+	backupVersion, err := migrateBackupCopy(ctx, path, copyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestoreResult{BackupVersion: backupVersion}
+	if result.SchemaVersion, err = SchemaVersion(db); err != nil {
+		return nil, err
+	}
+	if backupVersion > result.SchemaVersion {
+		return nil, fmt.Errorf("%w: backup is at schema version %d, newer than this database's %d; upgrade ytx first",
+			ErrInvalidInput, backupVersion, result.SchemaVersion)
+	}
+
+	// ATTACH applies to a single connection and cannot run inside a transaction
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS backup", copyPath); err != nil {
+		return nil, fmt.Errorf("failed to attach backup: %w", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "DETACH DATABASE backup")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tables, err := backupTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		owners[table] = true
+	}
+	for _, table := range tables {
+		rows, err := restoreTable(ctx, tx, table)
+		if err != nil {
+			return nil, err
+		}
+		// Sequence counters are restored with their tables but not reported, as in [CollectStats]
+		if !strings.HasSuffix(table, "_sequence") || !owners[strings.TrimSuffix(table, "_sequence")] {
+			result.Tables = append(result.Tables, RestoredTable{Name: table, Rows: rows})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return result, nil
+}
+
+// migrateBackupCopy copies the backup at path to copyPath and migrates the copy, returning the backup's schema version
+func migrateBackupCopy(ctx context.Context, path, copyPath string) (int, error) {
+	// Opened read-only and immutable, as the settings of NewDatabase, such as write-ahead logging, would be written
+	// to the archive
+	backup, err := sql.Open(tracedDriverName, "file:"+(&url.URL{Path: path}).EscapedPath()+"?mode=ro&immutable=1")
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to open backup: %w", ErrInvalidInput, err)
+	}
+	_, err = backup.ExecContext(ctx, "VACUUM INTO ?", copyPath)
+	backup.Close()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s is not a readable SQLite database: %w", ErrInvalidInput, path, err)
+	}
+
+	copyDB, err := NewDatabase(copyPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open backup copy: %w", err)
+	}
+	defer copyDB.Close()
+
+	version, err := SchemaVersion(copyDB)
+	if err != nil {
+		return 0, err
+	}
+	if version < 0 {
+		return 0, fmt.Errorf("%w: %s is not a ytx database backup", ErrInvalidInput, path)
+	}
+	if err := RunMigrations(copyDB); err != nil {
+		return 0, fmt.Errorf("failed to migrate backup from version %d: %w", version, err)
+	}
+	return version, nil
+}
+
+// backupTables lists the tables of the main database to restore, in name order: every ordinary table except the
+// migration history, SQLite internals, and full-text index tables, which are kept in sync by triggers.
+func backupTables(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT name, COALESCE(sql, '') FROM main.sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'schema_migrations'
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var names, virtual []string
+	for rows.Next() {
+		var name, ddl string
+		if err := rows.Scan(&name, &ddl); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		if strings.HasPrefix(strings.ToUpper(ddl), "CREATE VIRTUAL TABLE") {
+			virtual = append(virtual, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	tables := make([]string, 0, len(names))
+	for _, name := range names {
+		shadow := false
+		for _, vt := range virtual {
+			shadow = shadow || strings.HasPrefix(name, vt+"_")
+		}
+		if !shadow {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+// restoreTable replaces the rows of table with those of the attached backup's table, copying the columns both share
+func restoreTable(ctx context.Context, tx *sql.Tx, table string) (int64, error) {
+	mainColumns, err := tableColumns(ctx, tx, "main", table)
+	if err != nil {
+		return 0, err
+	}
+	backupColumns, err := tableColumns(ctx, tx, "backup", table)
+	if err != nil {
+		return 0, err
+	}
+	inBackup := make(map[string]bool, len(backupColumns))
+	for _, column := range backupColumns {
+		inBackup[column] = true
+	}
+
+	var columns []string
+	for _, column := range mainColumns {
+		if inBackup[column] {
+			columns = append(columns, fmt.Sprintf("%q", column))
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM main.%q", table)); err != nil {
+		return 0, fmt.Errorf("failed to clear %s: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	list := strings.Join(columns, ", ")
+	result, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO main.%q (%s) SELECT %s FROM backup.%q", table, list, list, table))
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore %s: %w", table, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
+
+// tableColumns returns the column names of table in the given schema, or none when it has no such table
+func tableColumns(ctx context.Context, tx *sql.Tx, schema, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT name FROM pragma_table_info(?, %q)", schema), table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to scan column name: %w", err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return columns, nil
+}
@@ -0,0 +1,176 @@
+package shared
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newMigratedDatabase(t *testing.T, name string) *sql.DB {
+	t.Helper()
+	db, err := NewDatabase(filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	return db
+}
+
+func TestBackup(t *testing.T) {
+	source := newMigratedDatabase(t, "source.db")
+	if _, err := source.Exec(`
+		INSERT INTO tracks (id, sequence, service, service_id, title, artist, album, created_at) VALUES ('t1', 1, 'spotify', 'sp1', 'Harder Better', 'Daft Punk', 'Discovery', '2024-03-01 12:00:00');
+		UPDATE tracks_sequence SET value = 1 WHERE id = 1;
+	`); err != nil {
+		t.Fatalf("failed to insert track: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := CreateBackup(t.Context(), source, path); err != nil {
+		t.Fatalf("CreateBackup() error = %v", err)
+	}
+	if err := CreateBackup(t.Context(), source, path); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an existing file to be refused, got %v", err)
+	}
+
+	t.Run("restores every table and its search index", func(t *testing.T) {
+		dest := newMigratedDatabase(t, "dest.db")
+		if _, err := dest.Exec(`INSERT INTO tracks (id, sequence, service, service_id, title, artist) VALUES ('stale', 7, 'youtube', 'yt7', 'Stale', 'Nobody')`); err != nil {
+			t.Fatalf("failed to insert track: %v", err)
+		}
+
+		archive, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := RestoreBackup(t.Context(), dest, path)
+		if err != nil {
+			t.Fatalf("RestoreBackup() error = %v", err)
+		}
+		if after, err := os.ReadFile(path); err != nil || !bytes.Equal(after, archive) {
+			t.Errorf("expected the archive left untouched, got %v", err)
+		}
+		for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+			if _, err := os.Stat(path + suffix); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("expected no %s file next to the archive, got %v", suffix, err)
+			}
+		}
+		if result.BackupVersion != result.SchemaVersion {
+			t.Errorf("expected matching schema versions, got %+v", result)
+		}
+
+		var ids []string
+		rows, err := dest.Query("SELECT id FROM tracks")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+			var id string
+			rows.Scan(&id)
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if len(ids) != 1 || ids[0] != "t1" {
+			t.Errorf("expected only the backed up track, got %v", ids)
+		}
+
+		var sequence int
+		if err := dest.QueryRow("SELECT value FROM tracks_sequence WHERE id = 1").Scan(&sequence); err != nil || sequence != 1 {
+			t.Errorf("expected the sequence restored to 1, got %d (%v)", sequence, err)
+		}
+		var matches int
+		if err := dest.QueryRow("SELECT COUNT(*) FROM tracks_fts WHERE tracks_fts MATCH 'daft'").Scan(&matches); err != nil || matches != 1 {
+			t.Errorf("expected the search index rebuilt, got %d matches (%v)", matches, err)
+		}
+		for _, table := range result.Tables {
+			if table.Name == "tracks_fts" || table.Name == "schema_migrations" {
+				t.Errorf("expected %s to be skipped", table.Name)
+			}
+		}
+	})
+
+	t.Run("migrates an older backup", func(t *testing.T) {
+		older := filepath.Join(t.TempDir(), "older.db")
+		if err := CreateBackup(t.Context(), source, older); err != nil {
+			t.Fatal(err)
+		}
+		backup, err := NewDatabase(older)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := RollbackMigration(backup); err != nil {
+			t.Fatal(err)
+		}
+		olderVersion, _ := SchemaVersion(backup)
+		backup.Close()
+
+		dest := newMigratedDatabase(t, "dest.db")
+		result, err := RestoreBackup(t.Context(), dest, older)
+		if err != nil {
+			t.Fatalf("RestoreBackup() error = %v", err)
+		}
+		if result.BackupVersion != olderVersion || result.SchemaVersion <= olderVersion {
+			t.Errorf("expected a backup at version %d restored to a newer schema, got %+v", olderVersion, result)
+		}
+
+		var count int
+		if err := dest.QueryRow("SELECT COUNT(*) FROM tracks").Scan(&count); err != nil || count != 1 {
+			t.Errorf("expected the backed up track, got %d (%v)", count, err)
+		}
+		backup, err = NewDatabase(older)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer backup.Close()
+		if version, _ := SchemaVersion(backup); version != olderVersion {
+			t.Errorf("expected the archive left at version %d, got %d", olderVersion, version)
+		}
+	})
+
+	t.Run("rejects newer and foreign files", func(t *testing.T) {
+		dest := newMigratedDatabase(t, "dest.db")
+
+		newer := filepath.Join(t.TempDir(), "newer.db")
+		if err := CreateBackup(t.Context(), source, newer); err != nil {
+			t.Fatal(err)
+		}
+		backup, err := NewDatabase(newer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := backup.Exec("INSERT INTO schema_migrations (version) VALUES (9999)"); err != nil {
+			t.Fatal(err)
+		}
+		backup.Close()
+
+		notDB := filepath.Join(t.TempDir(), "notes.txt")
+		if err := os.WriteFile(notDB, []byte("not a database"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		empty := filepath.Join(t.TempDir(), "empty.db")
+		emptyDB, err := NewDatabase(empty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		emptyDB.Exec("CREATE TABLE other (id TEXT)")
+		emptyDB.Close()
+
+		for _, file := range []string{newer, notDB, empty} {
+			if _, err := RestoreBackup(t.Context(), dest, file); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("RestoreBackup(%s) error = %v, want ErrInvalidInput", filepath.Base(file), err)
+			}
+		}
+
+		var count int
+		if err := dest.QueryRow("SELECT COUNT(*) FROM tracks").Scan(&count); err != nil || count != 0 {
+			t.Errorf("expected a failed restore to leave the database unchanged, got %d tracks (%v)", count, err)
+		}
+	})
+}
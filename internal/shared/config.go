@@ -4,6 +4,10 @@ import (
 	_ "embed"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"golang.org/x/oauth2"
@@ -14,9 +18,25 @@ var exampleConf []byte
 
 // Config represents the application configuration loaded from a TOML file.
 type Config struct {
-	Credentials CredentialsConfig `toml:"credentials"`
-	Database    DatabaseConfig    `toml:"database"`
-	Server      ServerConfig      `toml:"server"`
+	Credentials   CredentialsConfig        `toml:"credentials"`
+	Database      DatabaseConfig           `toml:"database"`
+	Cache         CacheConfig              `toml:"cache"`
+	Server        ServerConfig             `toml:"server"`
+	Transfer      TransferConfig           `toml:"transfer,omitempty"`
+	Notifications NotificationsConfig      `toml:"notifications,omitempty"`
+	Logging       LoggingConfig            `toml:"logging,omitempty"`
+	Tracing       TracingConfig            `toml:"tracing,omitempty"`
+	Secrets       SecretsConfig            `toml:"secrets,omitempty"`
+	Profiles      map[string]ProfileConfig `toml:"profiles,omitempty"`
+}
+
+// ProfileConfig contains the credentials and database of a named account profile.
+//
+// A service's credentials replace the top-level ones when the profile sets any of them, so tokens are never
+// shared between profiles; otherwise the top-level credentials are used.
+type ProfileConfig struct {
+	Credentials  CredentialsConfig `toml:"credentials"`
+	DatabasePath string            `toml:"database_path,omitempty"` // Default: the top-level path suffixed with the profile name
 }
 
 // CredentialsConfig contains service-specific credentials.
@@ -27,11 +47,13 @@ type CredentialsConfig struct {
 
 // SpotifyConfig contains Spotify API credentials.
 type SpotifyConfig struct {
-	ClientID     string `toml:"client_id"`
-	ClientSecret string `toml:"client_secret"`
-	RedirectURI  string `toml:"redirect_uri"`
-	AccessToken  string `toml:"access_token,omitempty"`
-	RefreshToken string `toml:"refresh_token,omitempty"`
+	ClientID     string    `toml:"client_id"`
+	ClientSecret string    `toml:"client_secret"` // Optional; without it authorization uses PKCE
+	RedirectURI  string    `toml:"redirect_uri"`
+	AccessToken  string    `toml:"access_token,omitempty"`
+	RefreshToken string    `toml:"refresh_token,omitempty"`
+	TokenExpiry  time.Time `toml:"token_expiry,omitempty"` // Zero when unknown
+	Market       string    `toml:"market,omitempty"`       // ISO 3166-1 alpha-2 country code or "from_token" for search results
 }
 
 // YouTubeConfig contains YouTube Music API credentials.
@@ -46,12 +68,135 @@ type DatabaseConfig struct {
 	Path         string `toml:"path"`
 	MaxOpenConns int    `toml:"max_open_conns"`
 	MaxIdleConns int    `toml:"max_idle_conns"`
+	QueryTimeout int    `toml:"query_timeout"` // Seconds before database operations are cancelled
+	JournalMode  string `toml:"journal_mode"`  // SQLite journal mode; Default: wal
+	BusyTimeout  int    `toml:"busy_timeout"`  // Milliseconds a SQLite write waits for a lock before failing
+}
+
+// DefaultQueryTimeout is used when [DatabaseConfig.QueryTimeout] is unset.
+const DefaultQueryTimeout = 30 * time.Second
+
+// Timeout returns the configured database operation timeout, falling back to [DefaultQueryTimeout].
+func (d DatabaseConfig) Timeout() time.Duration {
+	if d.QueryTimeout <= 0 {
+		return DefaultQueryTimeout
+	}
+	return time.Duration(d.QueryTimeout) * time.Second
+}
+
+// DefaultBusyTimeout is used when [DatabaseConfig.BusyTimeout] is unset.
+const DefaultBusyTimeout = 5 * time.Second
+
+// LockTimeout returns how long a SQLite write waits for another connection's lock, falling back to [DefaultBusyTimeout].
+func (d DatabaseConfig) LockTimeout() time.Duration {
+	if d.BusyTimeout <= 0 {
+		return DefaultBusyTimeout
+	}
+	return time.Duration(d.BusyTimeout) * time.Millisecond
+}
+
+// CacheConfig contains local cache settings.
+type CacheConfig struct {
+	SearchTTL int    `toml:"search_ttl"`         // Hours before cached search matches expire
+	HTTPDir   string `toml:"http_dir,omitempty"` // Spotify GET responses are cached here across runs; in memory when empty
+}
+
+// DefaultSearchTTL is used when [CacheConfig.SearchTTL] is unset.
+const DefaultSearchTTL = 7 * 24 * time.Hour
+
+// SearchCacheTTL returns the configured search cache lifetime, falling back to [DefaultSearchTTL].
+func (c CacheConfig) SearchCacheTTL() time.Duration {
+	if c.SearchTTL <= 0 {
+		return DefaultSearchTTL
+	}
+	return time.Duration(c.SearchTTL) * time.Hour
 }
 
 // ServerConfig contains HTTP server settings.
 type ServerConfig struct {
-	Host string `toml:"host"`
-	Port int    `toml:"port"`
+	Host        string   `toml:"host"`
+	Port        int      `toml:"port"`                   // 0 picks a free ephemeral port
+	PortRange   string   `toml:"port_range,omitempty"`   // e.g. "3000-3010"; the first free port is used instead of Port
+	MetricsAddr string   `toml:"metrics_addr,omitempty"` // e.g. "127.0.0.1:9090"; serves Prometheus metrics when set
+	APIKey      string   `toml:"api_key,omitempty"`      // Required by the web server on every route but /health and the OAuth callback
+	CORSOrigins []string `toml:"cors_origins,omitempty"` // Browser origins allowed to call the web server, e.g. "https://app.example.com"
+	ListenAddr  string   `toml:"listen_addr,omitempty"`  // Address 'ytx serve' listens on, e.g. ":8080"; --addr takes precedence
+}
+
+// TransferConfig contains defaults for transfers.
+type TransferConfig struct {
+	NameTemplate        string `toml:"name_template,omitempty"`        // Destination playlist name, e.g. "{{.SourceName}} ({{.Date}})"
+	DescriptionTemplate string `toml:"description_template,omitempty"` // Destination playlist description
+	MaxDurationDelta    int    `toml:"max_duration_delta,omitempty"`   // Seconds a match's duration may differ from the source track's; unchecked when unset
+	Explicit            string `toml:"explicit,omitempty"`             // Version taken when a search finds both: "explicit", "clean", or "none"
+	FlagUnavailable     bool   `toml:"flag_unavailable,omitempty"`     // Refuse region-unavailable matches and report them as unavailable
+}
+
+// DurationDelta returns how far a match's duration may be from the source track's, or zero when unchecked.
+func (t TransferConfig) DurationDelta() time.Duration {
+	return time.Duration(max(t.MaxDurationDelta, 0)) * time.Second
+}
+
+// NotificationsConfig contains the endpoints notified when a transfer finishes.
+type NotificationsConfig struct {
+	WebhookURL string `toml:"webhook_url,omitempty"` // Receives the transfer summary as a JSON POST
+	DiscordURL string `toml:"discord_url,omitempty"` // Discord channel webhook
+	SlackURL   string `toml:"slack_url,omitempty"`   // Slack incoming webhook
+	Timeout    int    `toml:"timeout,omitempty"`     // Seconds before a notification request is abandoned
+}
+
+// DefaultNotificationTimeout is used when [NotificationsConfig.Timeout] is unset.
+const DefaultNotificationTimeout = 10 * time.Second
+
+// Enabled reports whether any notification endpoint is configured.
+func (n NotificationsConfig) Enabled() bool {
+	return n.WebhookURL != "" || n.DiscordURL != "" || n.SlackURL != ""
+}
+
+// RequestTimeout returns the configured notification request timeout, falling back to [DefaultNotificationTimeout].
+func (n NotificationsConfig) RequestTimeout() time.Duration {
+	if n.Timeout <= 0 {
+		return DefaultNotificationTimeout
+	}
+	return time.Duration(n.Timeout) * time.Second
+}
+
+// TracingConfig contains the OTLP collector that trace spans are exported to.
+type TracingConfig struct {
+	Endpoint    string            `toml:"endpoint,omitempty"`     // OTLP/HTTP collector, e.g. "http://localhost:4318"; tracing is off when empty
+	ServiceName string            `toml:"service_name,omitempty"` // Reported as service.name; defaults to "ytx"
+	Headers     map[string]string `toml:"headers,omitempty"`      // Sent with every export, e.g. for collector authentication
+}
+
+// DefaultTracingServiceName is used when [TracingConfig.ServiceName] is unset.
+const DefaultTracingServiceName = "ytx"
+
+// Service returns the configured service name, falling back to [DefaultTracingServiceName].
+func (t TracingConfig) Service() string {
+	if t.ServiceName == "" {
+		return DefaultTracingServiceName
+	}
+	return t.ServiceName
+}
+
+// CallbackPorts returns the ports to try, in order, for the OAuth callback server.
+func (s ServerConfig) CallbackPorts() ([]int, error) {
+	if s.PortRange == "" {
+		return []int{s.Port}, nil
+	}
+
+	lo, hi, ok := strings.Cut(s.PortRange, "-")
+	first, err1 := strconv.Atoi(strings.TrimSpace(lo))
+	last, err2 := strconv.Atoi(strings.TrimSpace(hi))
+	if !ok || err1 != nil || err2 != nil || first < 1 || last > 65535 || first > last {
+		return nil, fmt.Errorf("%w: server.port_range must look like 3000-3010, got '%s'", ErrInvalidConfig, s.PortRange)
+	}
+
+	ports := make([]int, 0, last-first+1)
+	for port := first; port <= last; port++ {
+		ports = append(ports, port)
+	}
+	return ports, nil
 }
 
 func (s SpotifyConfig) Map() map[string]string {
@@ -59,6 +204,7 @@ func (s SpotifyConfig) Map() map[string]string {
 		"client_id":     s.ClientID,
 		"client_secret": s.ClientSecret,
 		"redirect_uri":  s.RedirectURI,
+		"market":        s.Market,
 	}
 }
 
@@ -69,19 +215,154 @@ func (s *SpotifyConfig) Update(t *oauth2.Token) error {
 	}
 	s.AccessToken = t.AccessToken
 	s.RefreshToken = t.RefreshToken
+	s.TokenExpiry = t.Expiry
 	return nil
 }
 
+// ExpiresWithin reports whether the access token is known to expire within d.
+func (s SpotifyConfig) ExpiresWithin(d time.Duration) bool {
+	return !s.TokenExpiry.IsZero() && time.Until(s.TokenExpiry) < d
+}
+
 func (s *SpotifyConfig) Token() *oauth2.Token {
 	return &oauth2.Token{
 		AccessToken:  s.AccessToken,
 		RefreshToken: s.RefreshToken,
+		Expiry:       s.TokenExpiry,
 	}
 }
 
+// WithProfile returns the configuration for the named profile, or c itself when name is empty.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	if name == "" {
+		return c, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: profile '%s' is not defined in [profiles]", ErrMissingConfig, name)
+	}
+
+	resolved := *c
+	if profile.Credentials.Spotify != (SpotifyConfig{}) {
+		resolved.Credentials.Spotify = profile.Credentials.Spotify
+	}
+	if profile.Credentials.YouTube != (YouTubeConfig{}) {
+		resolved.Credentials.YouTube = profile.Credentials.YouTube
+	}
+
+	resolved.Database.Path = profile.DatabasePath
+	if resolved.Database.Path == "" {
+		resolved.Database.Path = profileDatabasePath(c.Database.Path, name)
+	}
+
+	return &resolved, nil
+}
+
+// profileDatabasePath suffixes a database file name with a profile name, e.g. ./ytx.db → ./ytx-work.db
+//
+// Postgres DSNs are kept as is, so profiles share the database unless they set their own database_path.
+func profileDatabasePath(path, profile string) string {
+	if IsPostgresDSN(path) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + profile + ext
+}
+
+// SetSpotifyToken stores Spotify tokens for the named profile, or the top-level credentials when the
+// profile uses them.
+func (c *Config) SetSpotifyToken(profile string, t *oauth2.Token) error {
+	p, ok := c.Profiles[profile]
+	if !ok || p.Credentials.Spotify == (SpotifyConfig{}) {
+		return c.Credentials.Spotify.Update(t)
+	}
+
+	if err := p.Credentials.Spotify.Update(t); err != nil {
+		return err
+	}
+	c.Profiles[profile] = p
+	return nil
+}
+
+// ConfigOverride overrides a single config value from an environment variable or global flag.
+type ConfigOverride struct {
+	Key   string // TOML key of the overridden value
+	Env   string
+	Flag  string
+	Usage string
+	set   func(c *Config, value string) error
+}
+
+// Apply sets the overridden value on c.
+func (o ConfigOverride) Apply(c *Config, value string) error {
+	return o.set(c, value)
+}
+
+// ConfigOverrides lists every config value that can be overridden.
+//
+// Overrides are applied after the profile is resolved, so they take precedence over both the profile and the
+// top-level values in config.toml.
+var ConfigOverrides = []ConfigOverride{
+	stringOverride("credentials.spotify.client_id", "YTX_SPOTIFY_CLIENT_ID", "spotify-client-id", "Spotify client ID", func(c *Config) *string { return &c.Credentials.Spotify.ClientID }),
+	stringOverride("credentials.spotify.client_secret", "YTX_SPOTIFY_CLIENT_SECRET", "spotify-client-secret", "Spotify client secret", func(c *Config) *string { return &c.Credentials.Spotify.ClientSecret }),
+	stringOverride("credentials.spotify.redirect_uri", "YTX_SPOTIFY_REDIRECT_URI", "spotify-redirect-uri", "Spotify OAuth redirect URI", func(c *Config) *string { return &c.Credentials.Spotify.RedirectURI }),
+	stringOverride("credentials.spotify.access_token", "YTX_SPOTIFY_ACCESS_TOKEN", "spotify-access-token", "Spotify access token", func(c *Config) *string { return &c.Credentials.Spotify.AccessToken }),
+	stringOverride("credentials.spotify.refresh_token", "YTX_SPOTIFY_REFRESH_TOKEN", "spotify-refresh-token", "Spotify refresh token", func(c *Config) *string { return &c.Credentials.Spotify.RefreshToken }),
+	stringOverride("credentials.spotify.market", "YTX_SPOTIFY_MARKET", "spotify-market", "Spotify market (country code) for search results", func(c *Config) *string { return &c.Credentials.Spotify.Market }),
+	stringOverride("credentials.youtube.api_key", "YTX_YT_API_KEY", "yt-api-key", "YouTube API key", func(c *Config) *string { return &c.Credentials.YouTube.APIKey }),
+	stringOverride("credentials.youtube.proxy_url", "YTX_YT_PROXY_URL", "yt-proxy-url", "YouTube Music API proxy URL", func(c *Config) *string { return &c.Credentials.YouTube.ProxyURL }),
+	pathOverride("credentials.youtube.headers_path", "YTX_YT_HEADERS_PATH", "yt-headers-path", "YouTube Music browser headers file", func(c *Config) *string { return &c.Credentials.YouTube.HeadersPath }),
+	pathOverride("database.path", "YTX_DATABASE_PATH", "database-path", "SQLite database file", func(c *Config) *string { return &c.Database.Path }),
+	intOverride("database.max_open_conns", "YTX_DATABASE_MAX_OPEN_CONNS", "database-max-open-conns", "Maximum open database connections", func(c *Config) *int { return &c.Database.MaxOpenConns }),
+	intOverride("database.max_idle_conns", "YTX_DATABASE_MAX_IDLE_CONNS", "database-max-idle-conns", "Maximum idle database connections", func(c *Config) *int { return &c.Database.MaxIdleConns }),
+	intOverride("database.query_timeout", "YTX_DATABASE_QUERY_TIMEOUT", "database-query-timeout", "Seconds before database operations are cancelled", func(c *Config) *int { return &c.Database.QueryTimeout }),
+	stringOverride("database.journal_mode", "YTX_DATABASE_JOURNAL_MODE", "database-journal-mode", "SQLite journal mode: wal, delete, truncate, persist, memory, or off", func(c *Config) *string { return &c.Database.JournalMode }),
+	intOverride("database.busy_timeout", "YTX_DATABASE_BUSY_TIMEOUT", "database-busy-timeout", "Milliseconds a SQLite write waits for a lock before failing", func(c *Config) *int { return &c.Database.BusyTimeout }),
+	intOverride("cache.search_ttl", "YTX_CACHE_SEARCH_TTL", "cache-search-ttl", "Hours before cached search matches expire", func(c *Config) *int { return &c.Cache.SearchTTL }),
+	pathOverride("cache.http_dir", "YTX_CACHE_HTTP_DIR", "cache-http-dir", "Directory caching Spotify GET responses across runs", func(c *Config) *string { return &c.Cache.HTTPDir }),
+	stringOverride("server.host", "YTX_SERVER_HOST", "server-host", "OAuth callback server host", func(c *Config) *string { return &c.Server.Host }),
+	intOverride("server.port", "YTX_SERVER_PORT", "server-port", "OAuth callback server port", func(c *Config) *int { return &c.Server.Port }),
+	stringOverride("server.port_range", "YTX_SERVER_PORT_RANGE", "server-port-range", "OAuth callback server port range, e.g. 3000-3010", func(c *Config) *string { return &c.Server.PortRange }),
+	stringOverride("server.metrics_addr", "YTX_METRICS_ADDR", "metrics-addr", "Address to serve Prometheus metrics on, e.g. 127.0.0.1:9090", func(c *Config) *string { return &c.Server.MetricsAddr }),
+	stringOverride("server.api_key", "YTX_API_KEY", "api-key", "API key the web server requires, as an X-API-Key header or bearer token", func(c *Config) *string { return &c.Server.APIKey }),
+	stringOverride("notifications.webhook_url", "YTX_NOTIFY_WEBHOOK_URL", "notify-webhook-url", "Webhook receiving a JSON summary when a transfer finishes", func(c *Config) *string { return &c.Notifications.WebhookURL }),
+	stringOverride("notifications.discord_url", "YTX_NOTIFY_DISCORD_URL", "notify-discord-url", "Discord webhook notified when a transfer finishes", func(c *Config) *string { return &c.Notifications.DiscordURL }),
+	stringOverride("notifications.slack_url", "YTX_NOTIFY_SLACK_URL", "notify-slack-url", "Slack webhook notified when a transfer finishes", func(c *Config) *string { return &c.Notifications.SlackURL }),
+	pathOverride("logging.file", "YTX_LOG_FILE", "log-file", "Rotating log file written instead of stderr", func(c *Config) *string { return &c.Logging.File }),
+	stringOverride("logging.format", "YTX_LOG_FORMAT", "log-format", "Log format: text, json, or logfmt", func(c *Config) *string { return &c.Logging.Format }),
+	stringOverride("tracing.endpoint", "YTX_OTLP_ENDPOINT", "otlp-endpoint", "OTLP/HTTP collector that trace spans are exported to", func(c *Config) *string { return &c.Tracing.Endpoint }),
+	stringOverride("logging.level", "YTX_LOG_LEVEL", "log-level", "Default log level: debug, info, warn, or error", func(c *Config) *string { return &c.Logging.Level }),
+}
+
+func stringOverride(key, env, flag, usage string, field func(*Config) *string) ConfigOverride {
+	return ConfigOverride{Key: key, Env: env, Flag: flag, Usage: usage, set: func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}}
+}
+
+func pathOverride(key, env, flag, usage string, field func(*Config) *string) ConfigOverride {
+	return ConfigOverride{Key: key, Env: env, Flag: flag, Usage: usage, set: func(c *Config, value string) error {
+		*field(c) = ExpandPath(value)
+		return nil
+	}}
+}
+
+func intOverride(key, env, flag, usage string, field func(*Config) *int) ConfigOverride {
+	return ConfigOverride{Key: key, Env: env, Flag: flag, Usage: usage, set: func(c *Config, value string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%w: %s must be an integer, got '%s'", ErrInvalidConfig, key, value)
+		}
+		*field(c) = n
+		return nil
+	}}
+}
+
 // LoadConfig reads and parses a TOML configuration file from the specified path.
 //
-// Expands ~ in file paths to the user's home directory.
+// Expands ~ in file paths to the user's home directory and reads tokens from the [SecretsConfig] store.
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -95,6 +376,23 @@ func LoadConfig(path string) (*Config, error) {
 
 	config.Credentials.YouTube.HeadersPath = ExpandPath(config.Credentials.YouTube.HeadersPath)
 	config.Database.Path = ExpandPath(config.Database.Path)
+	for name, profile := range config.Profiles {
+		profile.Credentials.YouTube.HeadersPath = ExpandPath(profile.Credentials.YouTube.HeadersPath)
+		profile.DatabasePath = ExpandPath(profile.DatabasePath)
+		config.Profiles[name] = profile
+	}
+
+	store, err := config.Secrets.NewSecretStore()
+	if err != nil {
+		return nil, err
+	}
+	if store != nil {
+		secrets, err := store.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tokens from %s: %w", store, err)
+		}
+		config.setTokenSecrets(secrets)
+	}
 
 	return &config, nil
 }
@@ -122,7 +420,20 @@ func CreateConfigFile(path string) error {
 }
 
 // SaveConfig writes a Config struct to a TOML file at the specified path.
+//
+// When a [SecretsConfig] store is selected, tokens are written to the store and left out of the file.
 func SaveConfig(path string, config *Config) error {
+	store, err := config.Secrets.NewSecretStore()
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		if err := store.Save(config.tokenSecrets()); err != nil {
+			return fmt.Errorf("failed to save tokens to %s: %w", store, err)
+		}
+		config = config.withoutTokens()
+	}
+
 	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to open config file for writing: %w", err)
@@ -0,0 +1,276 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configFieldType returns the type of the Config value addressed by a dotted TOML key, e.g.
+// "credentials.youtube.proxy_url" or "profiles.work.database_path"
+func configFieldType(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(Config{})
+	for _, segment := range strings.Split(key, ".") {
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := tomlField(t, segment)
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown config key '%s'", ErrInvalidArgument, key)
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("%w: unknown config key '%s'", ErrInvalidArgument, key)
+		}
+	}
+	return t, nil
+}
+
+// configFieldValue returns the value of c addressed by a dotted TOML key, or the zero value when it addresses a
+// map entry c doesn't have
+func configFieldValue(c *Config, key string) (reflect.Value, error) {
+	t, err := configFieldType(key)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	v := reflect.ValueOf(*c)
+	for _, segment := range strings.Split(key, ".") {
+		if v.Kind() == reflect.Map {
+			if v = v.MapIndex(reflect.ValueOf(segment)); !v.IsValid() {
+				return reflect.Zero(t), nil
+			}
+			continue
+		}
+		field, _ := tomlField(v.Type(), segment)
+		v = v.FieldByIndex(field.Index)
+	}
+	return v, nil
+}
+
+// tomlField finds the field of struct type t with the given TOML key
+func tomlField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		if name == key && field.IsExported() {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// ConfigValue returns the value of c addressed by a dotted TOML key as text: strings as they are, lists comma
+// separated as [SetConfigValue] accepts them, times in RFC 3339, and tables as JSON.
+func ConfigValue(c *Config, key string) (string, error) {
+	v, err := configFieldValue(c, key)
+	if err != nil {
+		return "", err
+	}
+
+	switch value := v.Interface().(type) {
+	case string:
+		return value, nil
+	case int:
+		return strconv.Itoa(value), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case []string:
+		return strings.Join(value, ","), nil
+	case time.Time:
+		if value.IsZero() {
+			return "", nil
+		}
+		return value.Format(time.RFC3339), nil
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		return string(data), nil
+	}
+}
+
+// parseConfigValue parses text as the value of the config key of type t
+func parseConfigValue(key, text string, t reflect.Type) (any, error) {
+	switch t {
+	case reflect.TypeOf(""):
+		return text, nil
+	case reflect.TypeOf(0):
+		n, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be an integer, got '%s'", ErrInvalidArgument, key, text)
+		}
+		return n, nil
+	case reflect.TypeOf(false):
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be true or false, got '%s'", ErrInvalidArgument, key, text)
+		}
+		return b, nil
+	case reflect.TypeOf([]string{}):
+		items := []string{}
+		for item := range strings.SplitSeq(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	case reflect.TypeOf(time.Time{}):
+		ts, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be an RFC 3339 time, got '%s'", ErrInvalidArgument, key, text)
+		}
+		return ts, nil
+	default:
+		return nil, fmt.Errorf("%w: %s is a table; set its keys one at a time, e.g. %s.<name>", ErrInvalidArgument, key, key)
+	}
+}
+
+// tableHeaderPattern matches a [table] header line, capturing its name; [[array]] headers don't match
+var tableHeaderPattern = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+
+// SetConfigValue sets the dotted TOML key of the config file at path to text, parsed for the key's type: integers,
+// true or false, comma-separated lists, and RFC 3339 times.
+//
+// The file is edited in place, so its comments and layout are kept: an existing key has its value replaced, and a
+// new one is added at the end of its table, or in a new table at the end of the file. Tokens kept in a [SecretsConfig]
+// store are refused, as they would be written to the file.
+func SetConfigValue(path, key, text string) error {
+	t, err := configFieldType(key)
+	if err != nil {
+		return err
+	}
+	value, err := parseConfigValue(key, text, t)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMissingConfig, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var current Config
+	if _, err := toml.Decode(string(data), &current); err != nil {
+		return fmt.Errorf("%w: failed to parse config: %v", ErrInvalidConfig, err)
+	}
+	if current.Secrets.Store != "" && current.Secrets.Store != SecretStoreConfig && isTokenKey(key) {
+		return fmt.Errorf("%w: %s is kept in the %s secrets store; run 'ytx spotify auth' to replace it", ErrInvalidArgument, key, current.Secrets.Store)
+	}
+
+	literal, err := tomlLiteral(value)
+	if err != nil {
+		return err
+	}
+	edited := setTOMLKey(string(data), key, literal)
+
+	var updated Config
+	if _, err := toml.Decode(edited, &updated); err != nil {
+		return fmt.Errorf("%w: cannot set %s in place: %v", ErrInvalidConfig, key, err)
+	}
+	if got, _ := configFieldValue(&updated, key); !reflect.DeepEqual(got.Interface(), value) {
+		return fmt.Errorf("%w: cannot set %s in place; edit the file instead", ErrInvalidConfig, key)
+	}
+
+	if err := os.WriteFile(path, []byte(edited), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// isTokenKey reports whether key addresses Spotify tokens, which a secrets store keeps out of the config file
+func isTokenKey(key string) bool {
+	return strings.HasSuffix(key, ".access_token") || strings.HasSuffix(key, ".refresh_token") || strings.HasSuffix(key, ".token_expiry")
+}
+
+// tomlLiteral renders value as TOML, e.g. "text" with quotes and escapes, or ["a", "b"]
+func tomlLiteral(value any) (string, error) {
+	var b bytes.Buffer
+	if err := toml.NewEncoder(&b).Encode(map[string]any{"v": value}); err != nil {
+		return "", fmt.Errorf("failed to encode value: %w", err)
+	}
+	return strings.TrimSpace(strings.TrimPrefix(b.String(), "v = ")), nil
+}
+
+// setTOMLKey returns text with the dotted key set to literal, replacing the value of an existing key and keeping
+// its trailing comment, or adding the key to the end of its table, creating the table when there is none
+func setTOMLKey(text, key, literal string) string {
+	dot := strings.LastIndex(key, ".")
+	table, name := key[:dot], key[dot+1:]
+	keyPattern := regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(name) + `\s*=\s*)(.*)$`)
+
+	lines := strings.Split(text, "\n")
+	current, found, last := "", false, -1
+	for i, line := range lines {
+		if match := tableHeaderPattern.FindStringSubmatch(line); match != nil {
+			if found {
+				break
+			}
+			current = strings.Join(strings.Fields(strings.ReplaceAll(match[1], ".", " . ")), "")
+			if found = current == table; found {
+				last = i
+			}
+			continue
+		}
+		if !found {
+			continue
+		}
+		if match := keyPattern.FindStringSubmatch(line); match != nil {
+			lines[i] = match[1] + literal + trailingComment(match[2])
+			return strings.Join(lines, "\n")
+		}
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			last = i
+		}
+	}
+
+	entry := name + " = " + literal
+	if found {
+		lines = append(lines[:last+1], append([]string{entry}, lines[last+1:]...)...)
+		return strings.Join(lines, "\n")
+	}
+	text = strings.TrimRight(text, "\n")
+	if text != "" {
+		text += "\n\n"
+	}
+	return text + "[" + table + "]\n" + entry + "\n"
+}
+
+// trailingComment returns the comment after the TOML value at the start of rest, with the spacing before it, or
+// "" when there is none. Quotes are skipped so a # inside a string is not taken for a comment.
+func trailingComment(rest string) string {
+	var quote byte
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			start := i
+			for start > 0 && (rest[start-1] == ' ' || rest[start-1] == '\t') {
+				start--
+			}
+			return rest[start:]
+		}
+	}
+	return ""
+}
@@ -1,9 +1,14 @@
 package shared
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
 )
 
 func TestConfig(t *testing.T) {
@@ -25,6 +30,41 @@ func TestConfig(t *testing.T) {
 		if config.Credentials.Spotify.ClientID != "your_spotify_client_id" {
 			t.Errorf("expected spotify client_id your_spotify_client_id, got %s", config.Credentials.Spotify.ClientID)
 		}
+
+		if config.Database.Timeout() != 30*time.Second {
+			t.Errorf("expected database timeout 30s, got %v", config.Database.Timeout())
+		}
+	})
+
+	t.Run("DatabaseTimeoutFallback", func(t *testing.T) {
+		config := DatabaseConfig{}
+		if config.Timeout() != DefaultQueryTimeout {
+			t.Errorf("expected default timeout %v, got %v", DefaultQueryTimeout, config.Timeout())
+		}
+	})
+
+	t.Run("SearchCacheTTL", func(t *testing.T) {
+		if ttl := DefaultConfig().Cache.SearchCacheTTL(); ttl != 168*time.Hour {
+			t.Errorf("expected search cache TTL 168h, got %v", ttl)
+		}
+		if ttl := (CacheConfig{}).SearchCacheTTL(); ttl != DefaultSearchTTL {
+			t.Errorf("expected default search cache TTL %v, got %v", DefaultSearchTTL, ttl)
+		}
+	})
+
+	t.Run("Notifications", func(t *testing.T) {
+		notifications := DefaultConfig().Notifications
+		if notifications.Enabled() {
+			t.Error("expected notifications to be disabled by default")
+		}
+		if timeout := notifications.RequestTimeout(); timeout != DefaultNotificationTimeout {
+			t.Errorf("expected default notification timeout %v, got %v", DefaultNotificationTimeout, timeout)
+		}
+
+		notifications = NotificationsConfig{SlackURL: "https://hooks.slack.com/services/x", Timeout: 3}
+		if !notifications.Enabled() || notifications.RequestTimeout() != 3*time.Second {
+			t.Errorf("expected enabled notifications with a 3s timeout, got %+v", notifications)
+		}
 	})
 
 	t.Run("CreateConfigFile", func(t *testing.T) {
@@ -98,4 +138,335 @@ headers_path = "/path/to/headers.json"
 			t.Errorf("expected spotify client_id test_client_id, got %s", config.Credentials.Spotify.ClientID)
 		}
 	})
+
+	t.Run("WithProfile", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.toml")
+
+		testConfig := `[database]
+path = "/data/ytx.db"
+
+[credentials.spotify]
+client_id = "base_id"
+client_secret = "base_secret"
+access_token = "base_token"
+
+[credentials.youtube]
+proxy_url = "http://localhost:8080"
+
+[profiles.work.credentials.spotify]
+client_id = "work_id"
+client_secret = "work_secret"
+
+[profiles.family]
+database_path = "/data/family.db"
+
+[profiles.family.credentials.youtube]
+proxy_url = "http://localhost:9090"
+`
+		if err := os.WriteFile(configPath, []byte(testConfig), 0644); err != nil {
+			t.Fatalf("failed to write test config: %v", err)
+		}
+
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("failed to load config: %v", err)
+		}
+
+		t.Run("empty name returns base config", func(t *testing.T) {
+			resolved, err := config.WithProfile("")
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if resolved != config {
+				t.Error("expected the base config to be returned")
+			}
+		})
+
+		t.Run("replaces credentials without inheriting tokens", func(t *testing.T) {
+			resolved, err := config.WithProfile("work")
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if resolved.Credentials.Spotify.ClientID != "work_id" {
+				t.Errorf("expected client_id work_id, got %s", resolved.Credentials.Spotify.ClientID)
+			}
+			if resolved.Credentials.Spotify.AccessToken != "" {
+				t.Errorf("expected no access token, got %s", resolved.Credentials.Spotify.AccessToken)
+			}
+			if resolved.Credentials.YouTube.ProxyURL != "http://localhost:8080" {
+				t.Errorf("expected base proxy_url, got %s", resolved.Credentials.YouTube.ProxyURL)
+			}
+			if resolved.Database.Path != "/data/ytx-work.db" {
+				t.Errorf("expected derived database path /data/ytx-work.db, got %s", resolved.Database.Path)
+			}
+			if config.Credentials.Spotify.ClientID != "base_id" {
+				t.Error("expected base config to be unchanged")
+			}
+		})
+
+		t.Run("uses profile database path", func(t *testing.T) {
+			resolved, err := config.WithProfile("family")
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if resolved.Database.Path != "/data/family.db" {
+				t.Errorf("expected database path /data/family.db, got %s", resolved.Database.Path)
+			}
+			if resolved.Credentials.Spotify.AccessToken != "base_token" {
+				t.Errorf("expected base spotify credentials, got %+v", resolved.Credentials.Spotify)
+			}
+			if resolved.Credentials.YouTube.ProxyURL != "http://localhost:9090" {
+				t.Errorf("expected profile proxy_url, got %s", resolved.Credentials.YouTube.ProxyURL)
+			}
+		})
+
+		t.Run("unknown profile", func(t *testing.T) {
+			_, err := config.WithProfile("missing")
+			if !errors.Is(err, ErrMissingConfig) {
+				t.Errorf("expected ErrMissingConfig, got %v", err)
+			}
+		})
+	})
+
+	t.Run("ConfigOverrides", func(t *testing.T) {
+		overrides := map[string]ConfigOverride{}
+		for _, o := range ConfigOverrides {
+			if _, ok := overrides[o.Flag]; ok {
+				t.Errorf("duplicate override flag %s", o.Flag)
+			}
+			overrides[o.Flag] = o
+		}
+
+		config := DefaultConfig()
+		if err := overrides["yt-proxy-url"].Apply(config, "http://proxy:8080"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if config.Credentials.YouTube.ProxyURL != "http://proxy:8080" {
+			t.Errorf("expected proxy_url to be overridden, got %s", config.Credentials.YouTube.ProxyURL)
+		}
+
+		if err := overrides["server-port"].Apply(config, "4000"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if config.Server.Port != 4000 {
+			t.Errorf("expected port 4000, got %d", config.Server.Port)
+		}
+
+		if err := overrides["server-port"].Apply(config, "abc"); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for a non-integer, got %v", err)
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			t.Skipf("no home directory: %v", err)
+		}
+		if err := overrides["database-path"].Apply(config, "~/ytx.db"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if want := filepath.Join(home, "ytx.db"); config.Database.Path != want {
+			t.Errorf("expected expanded path %s, got %s", want, config.Database.Path)
+		}
+	})
+
+	t.Run("TokenExpiry", func(t *testing.T) {
+		var spotify SpotifyConfig
+		if spotify.ExpiresWithin(time.Hour) {
+			t.Error("expected unknown expiry not to be reported as expiring")
+		}
+
+		expiry := time.Now().Add(2 * time.Minute)
+		if err := spotify.Update(&oauth2.Token{AccessToken: "access", Expiry: expiry}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !spotify.Token().Expiry.Equal(expiry) {
+			t.Errorf("expected expiry %v to round trip, got %v", expiry, spotify.Token().Expiry)
+		}
+		if !spotify.ExpiresWithin(5 * time.Minute) {
+			t.Error("expected token to expire within 5 minutes")
+		}
+		if spotify.ExpiresWithin(time.Minute) {
+			t.Error("expected token not to expire within 1 minute")
+		}
+	})
+
+	t.Run("CallbackPorts", func(t *testing.T) {
+		ports, err := ServerConfig{Port: 3000}.CallbackPorts()
+		if err != nil || len(ports) != 1 || ports[0] != 3000 {
+			t.Errorf("expected [3000], got %v, %v", ports, err)
+		}
+
+		ports, err = ServerConfig{Port: 3000, PortRange: "8000-8002"}.CallbackPorts()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(ports) != 3 || ports[0] != 8000 || ports[2] != 8002 {
+			t.Errorf("expected [8000 8001 8002], got %v", ports)
+		}
+
+		for _, invalid := range []string{"8000", "8002-8000", "a-b", "0-10", "65000-70000"} {
+			if _, err := (ServerConfig{PortRange: invalid}).CallbackPorts(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig for %q, got %v", invalid, err)
+			}
+		}
+	})
+
+	t.Run("SetSpotifyToken", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Profiles = map[string]ProfileConfig{
+			"work":   {Credentials: CredentialsConfig{Spotify: SpotifyConfig{ClientID: "work_id"}}},
+			"family": {DatabasePath: "family.db"},
+		}
+		token := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}
+
+		if err := config.SetSpotifyToken("work", token); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := config.Profiles["work"].Credentials.Spotify.AccessToken; got != "access" {
+			t.Errorf("expected work profile token to be updated, got %q", got)
+		}
+		if config.Credentials.Spotify.AccessToken != "" {
+			t.Error("expected top-level token to be unchanged")
+		}
+
+		if err := config.SetSpotifyToken("family", token); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if config.Credentials.Spotify.AccessToken != "access" {
+			t.Error("expected top-level token to be updated for a profile without spotify credentials")
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		valid := func(t *testing.T) *Config {
+			t.Helper()
+			headersPath := filepath.Join(t.TempDir(), "headers_auth.json")
+			if err := os.WriteFile(headersPath, []byte("{}"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			config := DefaultConfig()
+			config.Credentials.Spotify.ClientID = "client"
+			config.Credentials.Spotify.ClientSecret = ""
+			config.Credentials.YouTube.HeadersPath = headersPath
+			return config
+		}
+
+		if err := valid(t).Validate(); err != nil {
+			t.Errorf("expected a complete config to be valid, got %v", err)
+		}
+
+		t.Run("example credentials", func(t *testing.T) {
+			err := DefaultConfig().Validate()
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || !errors.Is(err, ErrMissingCredentials) {
+				t.Fatalf("expected a ConfigError of missing credentials, got %v", err)
+			}
+			for _, key := range []string{"credentials.spotify.client_id", "credentials.spotify.client_secret", "credentials.youtube.headers_path"} {
+				if !strings.Contains(err.Error(), key) {
+					t.Errorf("expected a problem with %s, got %v", key, err)
+				}
+			}
+		})
+
+		t.Run("aggregates every problem", func(t *testing.T) {
+			config := valid(t)
+			config.Credentials.YouTube.ProxyURL = "127.0.0.1:8080"
+			config.Credentials.YouTube.HeadersPath = filepath.Join(t.TempDir(), "missing.json")
+			config.Server.PortRange = "10-1"
+			config.Notifications.SlackURL = "hooks.slack.com"
+			config.Transfer.Explicit = "radio"
+			config.Logging.Format = "xml"
+			config.Profiles = map[string]ProfileConfig{
+				"work": {Credentials: CredentialsConfig{Spotify: SpotifyConfig{RedirectURI: "callback"}}},
+			}
+
+			var configErr *ConfigError
+			if err := config.Validate(); !errors.As(err, &configErr) {
+				t.Fatalf("expected a ConfigError, got %v", err)
+			}
+			want := []string{
+				"credentials.youtube.proxy_url",
+				"credentials.youtube.headers_path cannot be read",
+				"server.port_range",
+				"notifications.slack_url",
+				"transfer.explicit",
+				"logging.format",
+				"profiles.work.credentials.spotify.client_id",
+				"profiles.work.credentials.spotify.redirect_uri",
+			}
+			if len(configErr.Problems) != len(want) {
+				t.Fatalf("expected %d problems, got %v", len(want), configErr.Problems)
+			}
+			for i, key := range want {
+				if !strings.Contains(configErr.Problems[i].Error(), key) {
+					t.Errorf("expected problem %d about %s, got %v", i, key, configErr.Problems[i])
+				}
+			}
+			if !errors.Is(configErr, ErrInvalidConfig) {
+				t.Error("expected the ConfigError to match ErrInvalidConfig")
+			}
+		})
+	})
+
+	t.Run("SetConfigValue", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := CreateConfigFile(path); err != nil {
+			t.Fatal(err)
+		}
+
+		for key, value := range map[string]string{
+			"server.port":                   "4000",
+			"credentials.youtube.proxy_url": "http://proxy#1:8080",
+			"server.cors_origins":           "https://a.example, https://b.example",
+			"transfer.flag_unavailable":     "true",
+			"profiles.work.database_path":   "./work.db",
+		} {
+			if err := SetConfigValue(path, key, value); err != nil {
+				t.Fatalf("SetConfigValue(%s) failed: %v", key, err)
+			}
+		}
+
+		data, _ := os.ReadFile(path)
+		for _, line := range []string{
+			"port = 4000 # 0 picks a free port",
+			`proxy_url = "http://proxy#1:8080"`,
+			`# Leave client_secret empty to authorize with PKCE using only the client ID`,
+			"[transfer]\nflag_unavailable = true",
+			"[profiles.work]\ndatabase_path = \"./work.db\"",
+		} {
+			if !strings.Contains(string(data), line) {
+				t.Errorf("expected %q in the edited file, got:\n%s", line, data)
+			}
+		}
+
+		config, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("failed to reload edited config: %v", err)
+		}
+		for key, want := range map[string]string{
+			"server.port":                   "4000",
+			"credentials.youtube.proxy_url": "http://proxy#1:8080",
+			"server.cors_origins":           "https://a.example,https://b.example",
+			"transfer.flag_unavailable":     "true",
+			"profiles.work.database_path":   "./work.db",
+			"profiles.home.database_path":   "",
+		} {
+			if got, err := ConfigValue(config, key); err != nil || got != want {
+				t.Errorf("ConfigValue(%s) = %q, %v, want %q", key, got, err, want)
+			}
+		}
+
+		for _, tt := range []struct{ key, value string }{
+			{"server.port", "many"},
+			{"server.nope", "1"},
+			{"logging.modules", "debug"},
+		} {
+			if err := SetConfigValue(path, tt.key, tt.value); !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("expected SetConfigValue(%s, %s) to fail with ErrInvalidArgument, got %v", tt.key, tt.value, err)
+			}
+		}
+	})
+
 }
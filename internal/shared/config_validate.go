@@ -0,0 +1,208 @@
+package shared
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Example values of config.example.toml that must be replaced before the credentials work
+const (
+	exampleSpotifyClientID     = "your_spotify_client_id"
+	exampleSpotifyClientSecret = "your_spotify_client_secret"
+)
+
+// ConfigError lists every problem [Config.Validate] found, so they can all be fixed in one pass.
+//
+// Each problem wraps a sentinel such as [ErrMissingCredentials] or [ErrInvalidConfig], which errors.Is finds
+// through the ConfigError.
+type ConfigError struct {
+	Problems []error
+}
+
+func (e *ConfigError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		messages[i] = problem.Error()
+	}
+	noun := "problems"
+	if len(e.Problems) == 1 {
+		noun = "problem"
+	}
+	return fmt.Sprintf("%d configuration %s: %s", len(e.Problems), noun, strings.Join(messages, "; "))
+}
+
+func (e *ConfigError) Unwrap() []error {
+	return e.Problems
+}
+
+// Validate checks c for settings that would otherwise fail later and less clearly: missing or example
+// credentials, malformed URLs and addresses, an unreadable YouTube Music headers file, and values outside the
+// ones a setting accepts. Profiles are checked with the credentials they set.
+//
+// Every problem is reported in a [*ConfigError]; nil means none were found.
+func (c *Config) Validate() error {
+	var problems []error
+	problem := func(sentinel error, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%w: "+format, append([]any{sentinel}, args...)...))
+	}
+
+	validateSpotify(c.Credentials.Spotify, "credentials.spotify", problem)
+	validateYouTube(c.Credentials.YouTube, "credentials.youtube", problem)
+
+	if c.Database.Path == "" {
+		problem(ErrMissingConfig, "database.path is not set")
+	} else if !IsPostgresDSN(c.Database.Path) && c.Database.JournalMode != "" && !slices.Contains(JournalModes, strings.ToLower(c.Database.JournalMode)) {
+		problem(ErrInvalidConfig, "database.journal_mode must be one of %s, got '%s'", strings.Join(JournalModes, ", "), c.Database.JournalMode)
+	}
+	for _, setting := range []struct {
+		key   string
+		value int
+	}{
+		{"database.max_open_conns", c.Database.MaxOpenConns},
+		{"database.max_idle_conns", c.Database.MaxIdleConns},
+		{"database.query_timeout", c.Database.QueryTimeout},
+		{"database.busy_timeout", c.Database.BusyTimeout},
+		{"cache.search_ttl", c.Cache.SearchTTL},
+		{"notifications.timeout", c.Notifications.Timeout},
+	} {
+		if setting.value < 0 {
+			problem(ErrInvalidConfig, "%s cannot be negative, got %d", setting.key, setting.value)
+		}
+	}
+
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		problem(ErrInvalidConfig, "server.port must be between 0 and 65535, got %d", c.Server.Port)
+	}
+	if _, err := c.Server.CallbackPorts(); err != nil {
+		problems = append(problems, err)
+	}
+	for _, setting := range []configSetting{
+		{"server.metrics_addr", c.Server.MetricsAddr},
+		{"server.listen_addr", c.Server.ListenAddr},
+	} {
+		if _, _, err := net.SplitHostPort(setting.value); setting.value != "" && err != nil {
+			problem(ErrInvalidConfig, "%s must be host:port, e.g. 127.0.0.1:9090, got '%s'", setting.key, setting.value)
+		}
+	}
+
+	for _, setting := range []configSetting{
+		{"notifications.webhook_url", c.Notifications.WebhookURL},
+		{"notifications.discord_url", c.Notifications.DiscordURL},
+		{"notifications.slack_url", c.Notifications.SlackURL},
+		{"tracing.endpoint", c.Tracing.Endpoint},
+	} {
+		if setting.value != "" && !isHTTPURL(setting.value) {
+			problem(ErrInvalidConfig, "%s must be an http or https URL, got '%s'", setting.key, setting.value)
+		}
+	}
+
+	for _, setting := range []configSetting{
+		{"transfer.name_template", c.Transfer.NameTemplate},
+		{"transfer.description_template", c.Transfer.DescriptionTemplate},
+	} {
+		if _, err := template.New(setting.key).Parse(setting.value); err != nil {
+			problem(ErrInvalidConfig, "%s: %v", setting.key, err)
+		}
+	}
+	switch strings.ToLower(c.Transfer.Explicit) {
+	case "", "none", "explicit", "clean":
+	default:
+		problem(ErrInvalidConfig, "transfer.explicit must be explicit, clean, or none, got '%s'", c.Transfer.Explicit)
+	}
+
+	if err := c.Logging.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	switch c.Secrets.Store {
+	case "", SecretStoreConfig, SecretStoreKeychain, SecretStoreFile:
+	default:
+		problem(ErrInvalidConfig, "secrets.store must be config, keychain, or file, got '%s'", c.Secrets.Store)
+	}
+
+	for _, name := range sortedKeys(c.Profiles) {
+		// A profile falls back to the top-level credentials, checked above, for services it sets none for
+		profile := c.Profiles[name]
+		prefix := "profiles." + name + ".credentials"
+		if profile.Credentials.Spotify != (SpotifyConfig{}) {
+			validateSpotify(profile.Credentials.Spotify, prefix+".spotify", problem)
+		}
+		if profile.Credentials.YouTube != (YouTubeConfig{}) {
+			validateYouTube(profile.Credentials.YouTube, prefix+".youtube", problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: problems}
+}
+
+// configSetting is a string setting checked by [Config.Validate], named by its TOML key
+type configSetting struct {
+	key   string
+	value string
+}
+
+// validateSpotify reports the problems of Spotify credentials under the TOML table prefix
+func validateSpotify(s SpotifyConfig, prefix string, problem func(error, string, ...any)) {
+	switch s.ClientID {
+	case "":
+		problem(ErrMissingCredentials, "%s.client_id is not set; create an app at https://developer.spotify.com/dashboard", prefix)
+	case exampleSpotifyClientID:
+		problem(ErrMissingCredentials, "%s.client_id is still the example value; use your Spotify app's client ID", prefix)
+	}
+	if s.ClientSecret == exampleSpotifyClientSecret {
+		problem(ErrMissingCredentials, "%s.client_secret is still the example value; use your app's secret, or leave it empty for PKCE", prefix)
+	}
+	if s.RedirectURI != "" && !isHTTPURL(s.RedirectURI) {
+		problem(ErrInvalidConfig, "%s.redirect_uri must be an http or https URL, e.g. http://127.0.0.1:3000/callback, got '%s'", prefix, s.RedirectURI)
+	}
+	if market := strings.TrimSpace(s.Market); market != "" && market != "from_token" && len(market) != 2 {
+		problem(ErrInvalidConfig, "%s.market must be a two-letter country code or from_token, got '%s'", prefix, s.Market)
+	}
+}
+
+// validateYouTube reports the problems of YouTube Music credentials under the TOML table prefix
+func validateYouTube(y YouTubeConfig, prefix string, problem func(error, string, ...any)) {
+	switch {
+	case y.ProxyURL == "":
+		problem(ErrMissingCredentials, "%s.proxy_url is not set; YouTube Music requests go through the proxy", prefix)
+	case !isHTTPURL(y.ProxyURL):
+		problem(ErrInvalidConfig, "%s.proxy_url must be an http or https URL, e.g. http://127.0.0.1:8080, got '%s'", prefix, y.ProxyURL)
+	}
+
+	if y.HeadersPath == "" {
+		problem(ErrMissingCredentials, "%s.headers_path is not set; run 'ytx setup youtube' to create it", prefix)
+		return
+	}
+	file, err := os.Open(y.HeadersPath)
+	if err != nil {
+		problem(ErrMissingCredentials, "%s.headers_path cannot be read: %v; run 'ytx setup youtube' to create it", prefix, err)
+		return
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		problem(ErrInvalidConfig, "%s.headers_path %s is a directory, not a headers file", prefix, y.HeadersPath)
+	}
+}
+
+// isHTTPURL reports whether value is an absolute http or https URL with a host
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// sortedKeys returns the keys of m in order, so problems are reported the same way on every run
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
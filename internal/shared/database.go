@@ -1,17 +1,69 @@
 package shared
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/desertthunder/ytx/internal/tracing"
+	"github.com/mattn/go-sqlite3"
 )
 
+// tracedDriverName is the SQLite driver with a trace span per query, registered by this package
+const tracedDriverName = "sqlite3-traced"
+
+func init() {
+	sql.Register(tracedDriverName, tracing.WrapDriver(&sqlite3.SQLiteDriver{}))
+}
+
+// JournalModes are the SQLite journal modes accepted as [DatabaseConfig.JournalMode].
+var JournalModes = []string{"wal", "delete", "truncate", "persist", "memory", "off"}
+
 // NewDatabase opens a connection to a SQLite database at the specified path.
-// The path can be ":memory:" for an in-memory database.
+// The path can be ":memory:" for an in-memory database, or a postgres:// URL to open a Postgres database
+// with a linked driver (see [PostgresDrivers]) and [Postgres] as its [Dialect].
 // Returns an open database connection or an error if connection fails.
+//
+// SQLite databases use the defaults of [OpenDatabase]. Queries made with a context are traced when a
+// [tracing.Tracer] is installed.
 func NewDatabase(path string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", path)
+	return openDatabase(DatabaseConfig{Path: path})
+}
+
+// OpenDatabase opens the database of config and sizes its connection pool with [ConfigureDatabase].
+//
+// SQLite connections use config's journal mode, write-ahead logging by default, so readers never block the
+// writer, and wait [DatabaseConfig.LockTimeout] for a lock instead of failing with "database is locked".
+// Transactions begin IMMEDIATE, taking the write lock up front: a transaction that read first and wrote
+// later could otherwise fail at its first write when another connection wrote in between, without waiting.
+// Writes are serialized this way across connections and processes sharing the file.
+func OpenDatabase(config DatabaseConfig) (*sql.DB, error) {
+	db, err := openDatabase(config)
+	if err != nil {
+		return nil, err
+	}
+	ConfigureDatabase(db, config.MaxOpenConns, config.MaxIdleConns)
+	return db, nil
+}
+
+func openDatabase(config DatabaseConfig) (*sql.DB, error) {
+	var (
+		db  *sql.DB
+		err error
+	)
+	if IsPostgresDSN(config.Path) {
+		db, err = openPostgres(config.Path)
+	} else {
+		var dsn string
+		if dsn, err = sqliteDSN(config); err != nil {
+			return nil, err
+		}
+		db, err = sql.Open(tracedDriverName, dsn)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -24,9 +76,178 @@ func NewDatabase(path string) (*sql.DB, error) {
 	return db, nil
 }
 
+// sqliteDSN adds the connection settings of config to its path as go-sqlite3 parameters, which apply them to
+// every connection of the pool
+func sqliteDSN(config DatabaseConfig) (string, error) {
+	mode := strings.ToLower(config.JournalMode)
+	if mode == "" {
+		mode = "wal"
+	}
+	if !slices.Contains(JournalModes, mode) {
+		return "", fmt.Errorf("%w: journal mode %q, expected one of %s", ErrInvalidArgument, config.JournalMode, strings.Join(JournalModes, ", "))
+	}
+
+	params := url.Values{}
+	params.Set("_journal_mode", mode)
+	params.Set("_busy_timeout", strconv.FormatInt(config.LockTimeout().Milliseconds(), 10))
+	params.Set("_txlock", "immediate")
+
+	separator := "?"
+	if strings.Contains(config.Path, "?") {
+		separator = "&"
+	}
+	return config.Path + separator + params.Encode(), nil
+}
+
 // ConfigureDatabase sets connection pool settings for the database.
 // Recommended for production use to limit connections and improve performance.
 func ConfigureDatabase(db *sql.DB, maxOpenConns, maxIdleConns int) {
 	db.SetMaxOpenConns(maxOpenConns)
 	db.SetMaxIdleConns(maxIdleConns)
 }
+
+// TableStats summarizes a single table's contents.
+type TableStats struct {
+	Name     string `json:"name"`
+	Rows     int64  `json:"rows"`
+	Deleted  int64  `json:"deleted"`            // Soft-deleted rows, included in Rows
+	Sequence *int64 `json:"sequence,omitempty"` // Last issued sequence number, if the table has a sequence counter
+}
+
+// DatabaseStats summarizes the size and contents of a SQLite database.
+type DatabaseStats struct {
+	SizeBytes int64        `json:"size_bytes"`
+	PageSize  int64        `json:"page_size"`
+	PageCount int64        `json:"page_count"`
+	FreePages int64        `json:"free_pages"` // Unused pages reclaimable by VACUUM
+	Tables    []TableStats `json:"tables"`
+}
+
+// CollectStats reports page usage and per-table row counts for the database.
+//
+// Sequence counter tables and SQLite internals are folded into their owning tables or skipped.
+func CollectStats(ctx context.Context, db *sql.DB) (*DatabaseStats, error) {
+	if err := RequireSQLite(db, "statistics"); err != nil {
+		return nil, err
+	}
+	stats := &DatabaseStats{}
+
+	if err := db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&stats.PageSize); err != nil {
+		return nil, fmt.Errorf("failed to read page size: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&stats.PageCount); err != nil {
+		return nil, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&stats.FreePages); err != nil {
+		return nil, fmt.Errorf("failed to read free page count: %w", err)
+	}
+	stats.SizeBytes = stats.PageSize * stats.PageCount
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	tables := make(map[string]bool, len(names))
+	for _, name := range names {
+		tables[name] = true
+	}
+
+	for _, name := range names {
+		if strings.HasSuffix(name, "_sequence") && tables[strings.TrimSuffix(name, "_sequence")] {
+			continue
+		}
+
+		table := TableStats{Name: name}
+		if err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %q", name)).Scan(&table.Rows); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", name, err)
+		}
+
+		var softDeletes bool
+		err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pragma_table_info(?) WHERE name = 'deleted_at')", name).Scan(&softDeletes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", name, err)
+		}
+		if softDeletes {
+			query := fmt.Sprintf("SELECT COUNT(*) FROM %q WHERE deleted_at IS NOT NULL", name)
+			if err := db.QueryRowContext(ctx, query).Scan(&table.Deleted); err != nil {
+				return nil, fmt.Errorf("failed to count deleted %s: %w", name, err)
+			}
+		}
+
+		if tables[name+"_sequence"] {
+			var sequence int64
+			query := fmt.Sprintf("SELECT value FROM %q WHERE id = 1", name+"_sequence")
+			if err := db.QueryRowContext(ctx, query).Scan(&sequence); err != nil {
+				return nil, fmt.Errorf("failed to read %s sequence: %w", name, err)
+			}
+			table.Sequence = &sequence
+		}
+
+		stats.Tables = append(stats.Tables, table)
+	}
+
+	return stats, nil
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns the problems it reports.
+//
+// An empty result means the database passed.
+func IntegrityCheck(ctx context.Context, db *sql.DB) ([]string, error) {
+	if err := RequireSQLite(db, "the integrity check"); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var message string
+		if err := rows.Scan(&message); err != nil {
+			return nil, fmt.Errorf("failed to scan integrity check result: %w", err)
+		}
+		if message != "ok" {
+			problems = append(problems, message)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return problems, nil
+}
+
+// Vacuum rebuilds the database file to reclaim free pages, then refreshes query planner statistics with ANALYZE.
+func Vacuum(ctx context.Context, db *sql.DB) error {
+	if err := RequireSQLite(db, "vacuum"); err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, "ANALYZE"); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+	return nil
+}
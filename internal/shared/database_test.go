@@ -0,0 +1,147 @@
+package shared
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestDatabaseMaintenance(t *testing.T) {
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "ytx.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO users (id, sequence, email, name) VALUES ('u1', 1, 'a@example.com', 'A'), ('u2', 2, 'b@example.com', 'B')"); err != nil {
+		t.Fatalf("failed to insert users: %v", err)
+	}
+	if _, err := db.Exec("UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = 'u2'"); err != nil {
+		t.Fatalf("failed to soft delete user: %v", err)
+	}
+	if _, err := db.Exec("UPDATE users_sequence SET value = 2 WHERE id = 1"); err != nil {
+		t.Fatalf("failed to update sequence: %v", err)
+	}
+
+	t.Run("CollectStats", func(t *testing.T) {
+		stats, err := CollectStats(t.Context(), db)
+		if err != nil {
+			t.Fatalf("failed to collect stats: %v", err)
+		}
+
+		if stats.SizeBytes <= 0 || stats.SizeBytes != stats.PageSize*stats.PageCount {
+			t.Errorf("unexpected size: %d bytes (%d pages of %d)", stats.SizeBytes, stats.PageCount, stats.PageSize)
+		}
+
+		var users *TableStats
+		for i := range stats.Tables {
+			if stats.Tables[i].Name == "users_sequence" {
+				t.Error("sequence tables should be folded into their owning table")
+			}
+			if stats.Tables[i].Name == "users" {
+				users = &stats.Tables[i]
+			}
+		}
+		if users == nil {
+			t.Fatal("expected users table in stats")
+		}
+		if users.Rows != 2 || users.Deleted != 1 {
+			t.Errorf("expected 2 rows with 1 deleted, got %d rows with %d deleted", users.Rows, users.Deleted)
+		}
+		if users.Sequence == nil || *users.Sequence != 2 {
+			t.Errorf("expected users sequence 2, got %v", users.Sequence)
+		}
+	})
+
+	t.Run("IntegrityCheck", func(t *testing.T) {
+		problems, err := IntegrityCheck(t.Context(), db)
+		if err != nil {
+			t.Fatalf("failed to run integrity check: %v", err)
+		}
+		if len(problems) != 0 {
+			t.Errorf("expected healthy database, got %v", problems)
+		}
+	})
+
+	t.Run("Vacuum", func(t *testing.T) {
+		if err := Vacuum(t.Context(), db); err != nil {
+			t.Fatalf("failed to vacuum: %v", err)
+		}
+
+		var freePages int
+		if err := db.QueryRow("PRAGMA freelist_count").Scan(&freePages); err != nil {
+			t.Fatalf("failed to read freelist: %v", err)
+		}
+		if freePages != 0 {
+			t.Errorf("expected no free pages after vacuum, got %d", freePages)
+		}
+	})
+}
+
+func TestOpenDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ytx.db")
+	db, err := OpenDatabase(DatabaseConfig{Path: path, MaxOpenConns: 8, MaxIdleConns: 8, BusyTimeout: 10000})
+	if err != nil {
+		t.Fatalf("OpenDatabase() error = %v", err)
+	}
+	defer db.Close()
+
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Errorf("expected write-ahead logging, got %q (%v)", mode, err)
+	}
+	if _, err := db.Exec("CREATE TABLE counter (value INTEGER); INSERT INTO counter VALUES (0)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Transactions that read before writing fail with "database is locked" unless they take the write lock up front
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 25 {
+				tx, err := db.Begin()
+				if err != nil {
+					errs <- err
+					return
+				}
+				var value int
+				if err := tx.QueryRow("SELECT value FROM counter").Scan(&value); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				if _, err := tx.Exec("UPDATE counter SET value = ?", value+1); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				if err := tx.Commit(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent write failed: %v", err)
+	}
+
+	var value int
+	if err := db.QueryRow("SELECT value FROM counter").Scan(&value); err != nil || value != 200 {
+		t.Errorf("expected every increment kept, got %d (%v)", value, err)
+	}
+
+	if _, err := OpenDatabase(DatabaseConfig{Path: path, JournalMode: "shadow"}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an unknown journal mode to be refused, got %v", err)
+	}
+}
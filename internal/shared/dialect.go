@@ -0,0 +1,240 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/tracing"
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" driver
+)
+
+// Dialect is the SQL flavor of a database opened with [NewDatabase].
+//
+// Queries are written once for both: placeholders are always ?, rewritten to $1, $2, ... for Postgres by the
+// connection, and upserts use ON CONFLICT ... DO UPDATE SET col = excluded.col, which both accept. The methods
+// of Dialect cover what is spelled differently, mostly around timestamps, which SQLite stores as text.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// PostgresDrivers are the database/sql driver names [NewDatabase] looks for, in order, to open a Postgres DSN.
+// ytx links pgx's; a build may register another under one of the later names.
+var PostgresDrivers = []string{"pgx", "postgres"}
+
+// IsPostgresDSN reports whether dsn is a Postgres connection URL rather than a SQLite path.
+func IsPostgresDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// DialectOf returns the dialect of db, which must have been opened with [NewDatabase].
+func DialectOf(db *sql.DB) Dialect {
+	if _, ok := db.Driver().(postgresDriver); ok {
+		return Postgres
+	}
+	return SQLite
+}
+
+// RequireSQLite fails with [ErrInvalidArgument] when db is not a SQLite database, for operations such as backups
+// that work on the database file.
+func RequireSQLite(db *sql.DB, operation string) error {
+	if d := DialectOf(db); d != SQLite {
+		return fmt.Errorf("%w: %s needs a SQLite database, not %s; use the database's own tools", ErrInvalidArgument, operation, d)
+	}
+	return nil
+}
+
+// Day returns an expression for the calendar day (YYYY-MM-DD) of the timestamp expression expr.
+func (d Dialect) Day(expr string) string {
+	if d == Postgres {
+		return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", expr)
+	}
+	// Timestamps are stored as text starting with the date
+	return fmt.Sprintf("substr(%s, 1, 10)", expr)
+}
+
+// FoldCase returns a comparison of the text expression expr with a placeholder that ignores case.
+func (d Dialect) FoldCase(expr string) string {
+	if d == Postgres {
+		return fmt.Sprintf("LOWER(%s) = LOWER(?)", expr)
+	}
+	return expr + " = ? COLLATE NOCASE"
+}
+
+// Upsert returns an ON CONFLICT clause updating columns from the proposed row when a row with the same conflict
+// key exists, e.g. "ON CONFLICT(service, service_id) DO UPDATE SET title = excluded.title".
+func (d Dialect) Upsert(conflict []string, columns ...string) string {
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = fmt.Sprintf("%[1]s = excluded.%[1]s", column)
+	}
+	return fmt.Sprintf("ON CONFLICT(%s) DO UPDATE SET %s", strings.Join(conflict, ", "), strings.Join(sets, ", "))
+}
+
+// rebind rewrites ? placeholders to Postgres's numbered $n, leaving question marks in string literals, quoted
+// identifiers, and comments alone.
+func rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+		case c == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// openPostgres opens dsn with the first registered driver of [PostgresDrivers], rewriting placeholders and
+// tracing queries as for SQLite
+func openPostgres(dsn string) (*sql.DB, error) {
+	registered := sql.Drivers()
+	for _, name := range PostgresDrivers {
+		if !slices.Contains(registered, name) {
+			continue
+		}
+		// sql.Open only looks up the driver; nothing is connected until the pool needs a connection
+		probe, err := sql.Open(name, dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		d := postgresDriver{probe.Driver()}
+		probe.Close()
+		return sql.OpenDB(postgresConnector{dsn: dsn, driver: d}), nil
+	}
+	return nil, fmt.Errorf("%w: no Postgres driver is linked into this build (looked for %s)", ErrServiceUnavailable, strings.Join(PostgresDrivers, ", "))
+}
+
+// postgresDriver opens connections of the underlying Postgres driver that accept ? placeholders
+type postgresDriver struct {
+	driver.Driver
+}
+
+func (d postgresDriver) Open(dsn string) (driver.Conn, error) {
+	return tracing.WrapDriver(rebindDriver{d.Driver}).Open(dsn)
+}
+
+type postgresConnector struct {
+	dsn    string
+	driver postgresDriver
+}
+
+func (c postgresConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c postgresConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// rebindDriver wraps the connections of a driver with numbered placeholders in [rebindConn]
+type rebindDriver struct {
+	driver.Driver
+}
+
+func (d rebindDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &rebindConn{conn}, nil
+}
+
+// rebindConn forwards to the wrapped connection with every statement's placeholders rewritten by [rebind]. The
+// optional interfaces database/sql checks for, such as [driver.NamedValueChecker], are passed through too.
+type rebindConn struct {
+	driver.Conn
+}
+
+func (c *rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rebind(query))
+}
+
+func (c *rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, rebind(query))
+	}
+	return c.Conn.Prepare(rebind(query))
+}
+
+func (c *rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *rebindConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // Fallback for drivers without BeginTx
+}
+
+func (c *rebindConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// CheckNamedValue lets the driver convert its own argument types, such as pgx's arrays, falling back to
+// database/sql's conversions
+func (c *rebindConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *rebindConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *rebindConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package shared
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestDialect(t *testing.T) {
+	t.Run("rebind", func(t *testing.T) {
+		for query, want := range map[string]string{
+			"SELECT id FROM users":                                       "SELECT id FROM users",
+			"UPDATE t SET a = ?, b = ? WHERE id = ?":                     "UPDATE t SET a = $1, b = $2 WHERE id = $3",
+			"SELECT '?' AS q, \"odd?\" FROM t WHERE a = ?":               "SELECT '?' AS q, \"odd?\" FROM t WHERE a = $1",
+			"SELECT 'it''s?' FROM t -- why?\nWHERE a = ? AND b = ?":      "SELECT 'it''s?' FROM t -- why?\nWHERE a = $1 AND b = $2",
+			"INSERT INTO t VALUES (?, ?) ON CONFLICT(a) DO NOTHING -- ?": "INSERT INTO t VALUES ($1, $2) ON CONFLICT(a) DO NOTHING -- ?",
+		} {
+			if got := rebind(query); got != want {
+				t.Errorf("rebind(%q) = %q, want %q", query, got, want)
+			}
+		}
+	})
+
+	t.Run("spellings", func(t *testing.T) {
+		if got := Postgres.Day("created_at"); got != "to_char(created_at, 'YYYY-MM-DD')" {
+			t.Errorf("unexpected Postgres day %q", got)
+		}
+		if got := SQLite.FoldCase("name"); got != "name = ? COLLATE NOCASE" {
+			t.Errorf("unexpected SQLite case folding %q", got)
+		}
+		want := "ON CONFLICT(user_id, service) DO UPDATE SET token = excluded.token, updated_at = excluded.updated_at"
+		if got := Postgres.Upsert([]string{"user_id", "service"}, "token", "updated_at"); got != want {
+			t.Errorf("Upsert() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("databases", func(t *testing.T) {
+		db, err := NewDatabase(":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if d := DialectOf(db); d != SQLite {
+			t.Errorf("expected SQLite, got %s", d)
+		}
+		if err := RequireSQLite(db, "backup"); err != nil {
+			t.Errorf("RequireSQLite() error = %v", err)
+		}
+
+		if !IsPostgresDSN("postgresql://ytx@localhost/ytx") || IsPostgresDSN("./postgres.db") {
+			t.Error("expected only postgres:// URLs to be DSNs")
+		}
+		// Opening only looks up the linked driver; nothing connects until a query needs a connection
+		pg, err := openPostgres("postgres://ytx@localhost/ytx")
+		if err != nil {
+			t.Fatalf("expected the pgx driver to be linked, got %v", err)
+		}
+		defer pg.Close()
+		if d := DialectOf(pg); d != Postgres {
+			t.Errorf("expected Postgres, got %s", d)
+		}
+		if err := RequireSQLite(pg, "backup"); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("expected backups of Postgres to be refused, got %v", err)
+		}
+	})
+
+	t.Run("passes optional interfaces through", func(t *testing.T) {
+		inner := &checkingConn{valid: false}
+		var conn driver.Conn = &rebindConn{inner}
+
+		nv := &driver.NamedValue{Ordinal: 1, Value: []string{"a"}}
+		if err := conn.(driver.NamedValueChecker).CheckNamedValue(nv); err != nil || inner.checked != 1 {
+			t.Errorf("CheckNamedValue() = %v after %d checks", err, inner.checked)
+		}
+		if err := conn.(driver.SessionResetter).ResetSession(t.Context()); err != nil || !inner.reset {
+			t.Errorf("expected the session reset to reach the driver, got %v", err)
+		}
+		if conn.(driver.Validator).IsValid() {
+			t.Error("expected the driver's invalid connection to be reported")
+		}
+
+		// Drivers without a checker get database/sql's own conversions
+		var plain driver.Conn = &rebindConn{struct{ driver.Conn }{}}
+		if err := plain.(driver.NamedValueChecker).CheckNamedValue(nv); !errors.Is(err, driver.ErrSkip) {
+			t.Errorf("expected ErrSkip without a checker, got %v", err)
+		}
+		if !plain.(driver.Validator).IsValid() {
+			t.Error("expected connections of drivers without a validator to stay valid")
+		}
+	})
+}
+
+// checkingConn is a connection implementing the optional interfaces database/sql checks for
+type checkingConn struct {
+	driver.Conn
+	checked int
+	reset   bool
+	valid   bool
+}
+
+func (c *checkingConn) CheckNamedValue(*driver.NamedValue) error {
+	c.checked++
+	return nil
+}
+
+func (c *checkingConn) ResetSession(context.Context) error {
+	c.reset = true
+	return nil
+}
+
+func (c *checkingConn) IsValid() bool {
+	return c.valid
+}
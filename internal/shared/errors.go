@@ -10,6 +10,7 @@ var (
 	ErrInvalidConfig      = fmt.Errorf("invalid configuration")
 	ErrMissingCredentials = fmt.Errorf("missing credentials")
 	ErrInvalidCredentials = fmt.Errorf("invalid credentials")
+	ErrSecretStore        = fmt.Errorf("secret store unavailable")
 
 	// Authentication errors
 	ErrAuthFailed       = fmt.Errorf("authentication failed")
@@ -24,10 +25,17 @@ var (
 	ErrServiceUnavailable = fmt.Errorf("service unavailable")
 	ErrPlaylistNotFound   = fmt.Errorf("playlist not found")
 	ErrTrackNotFound      = fmt.Errorf("track not found")
+	ErrTrackUnavailable   = fmt.Errorf("track unavailable in this region")
+	ErrForbidden          = fmt.Errorf("permission denied")
+	ErrRateLimited        = fmt.Errorf("rate limited")
 
 	// Input validation errors
 	ErrInvalidInput    = fmt.Errorf("invalid input")
 	ErrMissingArgument = fmt.Errorf("missing required argument")
 	ErrInvalidArgument = fmt.Errorf("invalid argument")
 	ErrInvalidFlag     = fmt.Errorf("invalid flag value")
+
+	// Persistence errors
+	ErrRecordNotFound  = fmt.Errorf("record not found")
+	ErrRestoreConflict = fmt.Errorf("restore conflicts with an existing record")
 )
@@ -0,0 +1,280 @@
+package shared
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Log formats accepted by [LoggingConfig.Format]
+const (
+	LogFormatText   = "text"
+	LogFormatJSON   = "json"
+	LogFormatLogfmt = "logfmt"
+)
+
+// Log file rotation defaults
+const (
+	DefaultLogMaxSize  = 10 // Megabytes
+	DefaultLogMaxFiles = 3
+)
+
+// LogModules are the module names accepted in [LoggingConfig.Modules].
+//
+// "cmd" is the CLI itself, "services" the Spotify and YouTube Music requests, "tasks" the playlist engine, and
+// "server" the metrics server.
+var LogModules = []string{"cmd", "services", "tasks", "server"}
+
+// LoggingConfig contains log sink, format, and level settings.
+type LoggingConfig struct {
+	Level    string            `toml:"level,omitempty"`     // debug, info (default), warn, or error
+	Format   string            `toml:"format,omitempty"`    // text (default), json, or logfmt
+	File     string            `toml:"file,omitempty"`      // Logs are written here instead of stderr when set
+	MaxSize  int               `toml:"max_size,omitempty"`  // Megabytes before the log file is rotated
+	MaxFiles int               `toml:"max_files,omitempty"` // Rotated log files kept alongside the current one
+	Modules  map[string]string `toml:"modules,omitempty"`   // Per-module levels overriding Level, e.g. services = "debug"
+}
+
+// Validate checks the levels, format, and module names.
+func (c LoggingConfig) Validate() error {
+	if c.Level != "" {
+		if _, err := log.ParseLevel(c.Level); err != nil {
+			return fmt.Errorf("%w: logging.level: %v", ErrInvalidConfig, err)
+		}
+	}
+	switch c.Format {
+	case "", LogFormatText, LogFormatJSON, LogFormatLogfmt:
+	default:
+		return fmt.Errorf("%w: logging.format must be text, json, or logfmt, got '%s'", ErrInvalidConfig, c.Format)
+	}
+	if c.MaxSize < 0 || c.MaxFiles < 0 {
+		return fmt.Errorf("%w: logging.max_size and logging.max_files cannot be negative", ErrInvalidConfig)
+	}
+	for module, level := range c.Modules {
+		if !slices.Contains(LogModules, module) {
+			return fmt.Errorf("%w: unknown logging module '%s', use one of %s", ErrInvalidConfig, module, strings.Join(LogModules, ", "))
+		}
+		if _, err := log.ParseLevel(level); err != nil {
+			return fmt.Errorf("%w: logging.modules.%s: %v", ErrInvalidConfig, module, err)
+		}
+	}
+	return nil
+}
+
+// ModuleLevel returns the level of a module, falling back to Level and then info.
+func (c LoggingConfig) ModuleLevel(module string) log.Level {
+	for _, value := range []string{c.Modules[module], c.Level} {
+		if level, err := log.ParseLevel(value); err == nil && value != "" {
+			return level
+		}
+	}
+	return log.InfoLevel
+}
+
+func (c LoggingConfig) formatter() log.Formatter {
+	switch c.Format {
+	case LogFormatJSON:
+		return log.JSONFormatter
+	case LogFormatLogfmt:
+		return log.LogfmtFormatter
+	}
+	return log.TextFormatter
+}
+
+// Logging builds one [log.Logger] per module, all writing to the same sink.
+//
+// Loggers keep their sink when [Logging.SetFile] redirects the output, so they can be handed out once.
+type Logging struct {
+	config  LoggingConfig
+	output  io.Writer
+	file    *RotatingFile // Nil unless logging to a file
+	loggers map[string]*log.Logger
+	mu      sync.Mutex
+}
+
+// NewLogging validates config and opens its log file, writing to w (stderr when nil) if no file is set.
+func NewLogging(config LoggingConfig, w io.Writer) (*Logging, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if w == nil {
+		w = os.Stderr
+	}
+
+	l := &Logging{config: config, output: w, loggers: map[string]*log.Logger{}}
+	if config.File != "" {
+		if err := l.SetFile(config.File); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Logger returns the logger of module, prefixed with the module name except for "cmd".
+func (l *Logging) Logger(module string) *log.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if logger, ok := l.loggers[module]; ok {
+		return logger
+	}
+
+	opts := log.Options{
+		ReportTimestamp: true,
+		ReportCaller:    true,
+		TimeFormat:      time.Kitchen,
+		Level:           l.config.ModuleLevel(module),
+		Formatter:       l.config.formatter(),
+	}
+	// Files and machine-readable logs outlive the day, so they get full timestamps
+	if l.file != nil || opts.Formatter != log.TextFormatter {
+		opts.TimeFormat = time.RFC3339
+	}
+	if module != "cmd" {
+		opts.Prefix = module
+	}
+
+	logger := log.NewWithOptions(l.output, opts)
+	l.loggers[module] = logger
+	return logger
+}
+
+// SetFile redirects every module logger to a rotating log file at path, closing any previous log file.
+func (l *Logging) SetFile(path string) error {
+	file, err := OpenRotatingFile(ExpandPath(path), l.config.MaxSize, l.config.MaxFiles)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous := l.file
+	l.file = file
+	l.output = file
+	for _, logger := range l.loggers {
+		logger.SetOutput(file)
+		logger.SetTimeFormat(time.RFC3339)
+	}
+	if previous != nil {
+		_ = previous.Close()
+	}
+	return nil
+}
+
+// Close closes the log file, if any.
+func (l *Logging) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// RotatingFile is an [io.WriteCloser] appending to a log file that is rotated once it grows past a size limit.
+//
+// The current file is renamed to path.1, path.1 to path.2, and so on, with the oldest beyond the limit removed.
+type RotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+	mu       sync.Mutex
+}
+
+// OpenRotatingFile opens (or creates) the log file at path along with its directory.
+//
+// maxSizeMB and maxFiles fall back to [DefaultLogMaxSize] and [DefaultLogMaxFiles] when zero.
+func OpenRotatingFile(path string, maxSizeMB, maxFiles int) (*RotatingFile, error) {
+	if maxSizeMB <= 0 {
+		maxSizeMB = DefaultLogMaxSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultLogMaxFiles
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	f := &RotatingFile{path: path, maxSize: int64(maxSizeMB) << 20, maxFiles: maxFiles}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the log file, rotating first if p would push it past the size limit.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, fmt.Errorf("log file %s is closed", f.path)
+	}
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate shifts the numbered backups up by one, moves the current file to path.1, and starts a new file
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	f.file = nil
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", f.path, f.maxFiles))
+	for i := f.maxFiles - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return f.open()
+}
@@ -0,0 +1,125 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestLoggingConfig_Validate(t *testing.T) {
+	valid := LoggingConfig{Level: "warn", Format: LogFormatJSON, Modules: map[string]string{"services": "debug"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := map[string]LoggingConfig{
+		"level":          {Level: "loud"},
+		"format":         {Format: "xml"},
+		"unknown module": {Modules: map[string]string{"web": "debug"}},
+		"module level":   {Modules: map[string]string{"tasks": "chatty"}},
+		"negative size":  {MaxSize: -1},
+	}
+	for name, config := range invalid {
+		if err := config.Validate(); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: expected ErrInvalidConfig, got %v", name, err)
+		}
+	}
+}
+
+func TestLoggingConfig_ModuleLevel(t *testing.T) {
+	config := LoggingConfig{Level: "warn", Modules: map[string]string{"services": "debug"}}
+	if got := config.ModuleLevel("services"); got != log.DebugLevel {
+		t.Errorf("ModuleLevel(services) = %v, want debug", got)
+	}
+	if got := config.ModuleLevel("tasks"); got != log.WarnLevel {
+		t.Errorf("ModuleLevel(tasks) = %v, want warn", got)
+	}
+	if got := (LoggingConfig{}).ModuleLevel("cmd"); got != log.InfoLevel {
+		t.Errorf("ModuleLevel(cmd) = %v, want info by default", got)
+	}
+}
+
+func TestLogging_ModuleLevelsAndJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logging, err := NewLogging(LoggingConfig{Format: LogFormatJSON, Modules: map[string]string{"services": "debug"}}, &buf)
+	if err != nil {
+		t.Fatalf("NewLogging() error = %v", err)
+	}
+
+	logging.Logger("services").Debug("request", "status", 200)
+	logging.Logger("tasks").Debug("matched track")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected only the services debug line, got %q", buf.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("expected a JSON log line, got %q: %v", lines[0], err)
+	}
+	if entry["prefix"] != "services" || entry["msg"] != "request" {
+		t.Errorf("unexpected log entry %v", entry)
+	}
+	if logging.Logger("services") != logging.Logger("services") {
+		t.Error("expected module loggers to be reused")
+	}
+}
+
+func TestLogging_SetFile(t *testing.T) {
+	var buf bytes.Buffer
+	logging, err := NewLogging(LoggingConfig{}, &buf)
+	if err != nil {
+		t.Fatalf("NewLogging() error = %v", err)
+	}
+	logger := logging.Logger("cmd")
+
+	path := filepath.Join(t.TempDir(), "logs", "ytx.log")
+	if err := logging.SetFile(path); err != nil {
+		t.Fatalf("SetFile() error = %v", err)
+	}
+	defer logging.Close()
+	logger.Info("redirected")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "redirected") || buf.Len() != 0 {
+		t.Errorf("expected the existing logger to write to the file only, file %q, buffer %q", data, buf.String())
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ytx.log")
+	f, err := OpenRotatingFile(path, 1, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile() error = %v", err)
+	}
+	defer f.Close()
+
+	chunk := bytes.Repeat([]byte("x"), 600<<10) // Two chunks exceed 1 MB
+	for i := range 4 {
+		if _, err := f.Write(chunk); err != nil {
+			t.Fatalf("Write(%d) error = %v", i, err)
+		}
+	}
+
+	for _, name := range []string{"ytx.log", "ytx.log.1", "ytx.log.2"} {
+		info, err := os.Stat(filepath.Join(filepath.Dir(path), name))
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+		if info.Size() != int64(len(chunk)) {
+			t.Errorf("%s size = %d, want %d", name, info.Size(), len(chunk))
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 rotated files, stat ytx.log.3: %v", err)
+	}
+}
@@ -4,25 +4,44 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
-	"path/filepath"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
-//go:embed sql/*.sql
+//go:embed sql/*.sql sql/postgres/*.sql
 var migrationFiles embed.FS
 
+// migrationDir holds the migrations written for a dialect, with the same versions and names in each
+func migrationDir(d Dialect) string {
+	if d == Postgres {
+		return "sql/postgres"
+	}
+	return "sql"
+}
+
 // Migration represents a database migration with up and down SQL.
 type Migration struct {
 	Version int
+	Name    string
 	Up      string
 	Down    string
 }
 
-// loadMigrations reads all migration files from the embedded filesystem and returns them sorted by version.
-func loadMigrations() ([]Migration, error) {
-	entries, err := migrationFiles.ReadDir("sql")
+// MigrationStatus reports whether an embedded migration has been applied to a database.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// loadMigrations reads the dialect's migration files from the embedded filesystem and returns them sorted by version.
+func loadMigrations(d Dialect) ([]Migration, error) {
+	dir := migrationDir(d)
+	entries, err := migrationFiles.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read migration directory: %w", err)
 	}
@@ -50,13 +69,13 @@ func loadMigrations() ([]Migration, error) {
 			continue
 		}
 
-		content, err := migrationFiles.ReadFile(filepath.Join("sql", name))
+		content, err := migrationFiles.ReadFile(path.Join(dir, name))
 		if err != nil {
 			return nil, fmt.Errorf("failed to read migration file %s: %w", name, err)
 		}
 
 		if migrationMap[version] == nil {
-			migrationMap[version] = &Migration{Version: version}
+			migrationMap[version] = &Migration{Version: version, Name: migrationName(name)}
 		}
 
 		if strings.Contains(name, "_up.sql") {
@@ -85,7 +104,7 @@ func loadMigrations() ([]Migration, error) {
 // RunMigrations executes all pending migrations on the database.
 // Creates a schema_migrations table to track applied migrations.
 func RunMigrations(db *sql.DB) error {
-	migrations, err := loadMigrations()
+	migrations, err := loadMigrations(DialectOf(db))
 	if err != nil {
 		return fmt.Errorf("failed to load migrations: %w", err)
 	}
@@ -114,7 +133,7 @@ func RunMigrations(db *sql.DB) error {
 
 // RollbackMigration rolls back the most recent migration.
 func RollbackMigration(db *sql.DB) error {
-	migrations, err := loadMigrations()
+	migrations, err := loadMigrations(DialectOf(db))
 	if err != nil {
 		return fmt.Errorf("failed to load migrations: %w", err)
 	}
@@ -148,6 +167,78 @@ func RollbackMigration(db *sql.DB) error {
 	return fmt.Errorf("migration version %d not found", currentVersion)
 }
 
+// MigrationStatuses reports every embedded migration in version order along with when it was applied.
+func MigrationStatuses(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(DialectOf(db))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	if err := createMigrationsTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			version   int
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// SchemaVersion returns the highest applied migration version, or -1 if no migrations have been applied.
+func SchemaVersion(db *sql.DB) (int, error) {
+	if err := createMigrationsTable(db); err != nil {
+		return 0, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	var version sql.NullInt64
+	if err := db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if !version.Valid {
+		return -1, nil
+	}
+	return int(version.Int64), nil
+}
+
+// migrationName extracts the descriptive part of a migration filename
+// (e.g., "0002_add_albums_artists_up.sql" -> "add_albums_artists").
+func migrationName(filename string) string {
+	name := strings.TrimSuffix(filename, ".sql")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "_up"), "_down")
+	if _, rest, ok := strings.Cut(name, "_"); ok {
+		return rest
+	}
+	return name
+}
+
 // createMigrationsTable creates the schema_migrations table if it doesn't exist.
 func createMigrationsTable(db *sql.DB) error {
 	query := `
@@ -179,14 +270,7 @@ func applyMigration(db *sql.DB, migration Migration) error {
 	defer tx.Rollback()
 
 	// Execute each statement separately
-	statements := strings.Split(migration.Up, ";")
-	for _, stmt := range statements {
-		// Remove comments and trim whitespace
-		stmt = removeComments(stmt)
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
-		}
+	for _, stmt := range splitStatements(migration.Up) {
 		if _, err := tx.Exec(stmt); err != nil {
 			return fmt.Errorf("failed to execute statement: %w\nStatement: %s", err, stmt)
 		}
@@ -200,6 +284,40 @@ func applyMigration(db *sql.DB, migration Migration) error {
 	return tx.Commit()
 }
 
+// splitStatements splits migration SQL into individual statements with comments removed.
+//
+// Semicolons inside CREATE TRIGGER ... BEGIN ... END bodies do not end the statement.
+func splitStatements(sql string) []string {
+	var (
+		statements []string
+		pending    string
+	)
+
+	for _, part := range strings.Split(sql, ";") {
+		stmt := strings.TrimSpace(removeComments(part))
+		if pending != "" {
+			stmt = pending + ";\n" + stmt
+		}
+
+		upper := strings.ToUpper(stmt)
+		if strings.HasPrefix(upper, "CREATE TRIGGER") && !strings.HasSuffix(upper, "END") {
+			pending = stmt
+			continue
+		}
+
+		pending = ""
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+
+	if pending != "" {
+		statements = append(statements, pending)
+	}
+
+	return statements
+}
+
 // removeComments removes SQL comments from a statement.
 func removeComments(sql string) string {
 	lines := strings.Split(sql, "\n")
@@ -226,14 +344,7 @@ func rollbackMigration(db *sql.DB, migration Migration) error {
 	defer tx.Rollback()
 
 	// Execute each statement separately
-	statements := strings.Split(migration.Down, ";")
-	for _, stmt := range statements {
-		// Remove comments and trim whitespace
-		stmt = removeComments(stmt)
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
-		}
+	for _, stmt := range splitStatements(migration.Down) {
 		if _, err := tx.Exec(stmt); err != nil {
 			return fmt.Errorf("failed to execute statement: %w\nStatement: %s", err, stmt)
 		}
@@ -245,4 +356,4 @@ func rollbackMigration(db *sql.DB, migration Migration) error {
 	}
 
 	return tx.Commit()
-}
\ No newline at end of file
+}
@@ -1,12 +1,13 @@
 package shared
 
 import (
+	"strings"
 	"testing"
 )
 
 func TestMigrationRunner(t *testing.T) {
 	t.Run("loadMigrations", func(t *testing.T) {
-		migrations, err := loadMigrations()
+		migrations, err := loadMigrations(SQLite)
 		if err != nil {
 			t.Fatalf("failed to load migrations: %v", err)
 		}
@@ -31,6 +32,50 @@ func TestMigrationRunner(t *testing.T) {
 		}
 	})
 
+	t.Run("Postgres migrations match SQLite", func(t *testing.T) {
+		sqlite, err := loadMigrations(SQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		postgres, err := loadMigrations(Postgres)
+		if err != nil {
+			t.Fatalf("failed to load Postgres migrations: %v", err)
+		}
+		if len(postgres) != len(sqlite) {
+			t.Fatalf("expected %d Postgres migrations, got %d", len(sqlite), len(postgres))
+		}
+		for i := range sqlite {
+			if postgres[i].Version != sqlite[i].Version || postgres[i].Name != sqlite[i].Name {
+				t.Errorf("migration %d: Postgres has %d %s, SQLite %d %s", i, postgres[i].Version, postgres[i].Name, sqlite[i].Version, sqlite[i].Name)
+			}
+			if strings.Contains(postgres[i].Up, "COLLATE NOCASE") || strings.Contains(postgres[i].Up, "VIRTUAL TABLE") {
+				t.Errorf("Postgres migration %d uses SQLite syntax", postgres[i].Version)
+			}
+		}
+	})
+
+	t.Run("splitStatements", func(t *testing.T) {
+		statements := splitStatements(`
+			-- leading comment
+			CREATE TABLE a (id TEXT);
+			CREATE TRIGGER a_insert AFTER INSERT ON a BEGIN
+			    INSERT INTO b VALUES (new.id);
+			    DELETE FROM c;
+			END;
+			DROP TABLE c; -- trailing comment
+		`)
+
+		if len(statements) != 3 {
+			t.Fatalf("expected 3 statements, got %d: %q", len(statements), statements)
+		}
+		if !strings.HasPrefix(statements[1], "CREATE TRIGGER") || !strings.HasSuffix(statements[1], "END") {
+			t.Errorf("expected trigger body to stay intact, got %q", statements[1])
+		}
+		if statements[2] != "DROP TABLE c" {
+			t.Errorf("expected comment to be stripped, got %q", statements[2])
+		}
+	})
+
 	t.Run("RunMigrations And Rollback", func(t *testing.T) {
 		db, err := NewDatabase(":memory:")
 		if err != nil {
@@ -91,9 +136,52 @@ func TestMigrationRunner(t *testing.T) {
 			t.Fatalf("failed to query schema_migrations: %v", err)
 		}
 
-		migrations, _ := loadMigrations()
+		migrations, _ := loadMigrations(SQLite)
 		if count != len(migrations) {
 			t.Errorf("expected %d migrations to be applied, got %d", len(migrations), count)
 		}
 	})
+	t.Run("Status And Schema Version", func(t *testing.T) {
+		db, err := NewDatabase(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create database: %v", err)
+		}
+		defer db.Close()
+
+		version, err := SchemaVersion(db)
+		if err != nil {
+			t.Fatalf("failed to get schema version: %v", err)
+		}
+		if version != -1 {
+			t.Errorf("expected version -1 on empty database, got %d", version)
+		}
+
+		if err := RunMigrations(db); err != nil {
+			t.Fatalf("failed to run migrations: %v", err)
+		}
+		if err := RollbackMigration(db); err != nil {
+			t.Fatalf("failed to rollback migration: %v", err)
+		}
+
+		statuses, err := MigrationStatuses(db)
+		if err != nil {
+			t.Fatalf("failed to get migration statuses: %v", err)
+		}
+
+		last := statuses[len(statuses)-1]
+		if last.Applied || last.AppliedAt != nil {
+			t.Errorf("expected latest migration %d to be pending after rollback", last.Version)
+		}
+		if statuses[0].Name != "create_tables" || !statuses[0].Applied {
+			t.Errorf("expected applied create_tables migration first, got %+v", statuses[0])
+		}
+
+		version, err = SchemaVersion(db)
+		if err != nil {
+			t.Fatalf("failed to get schema version: %v", err)
+		}
+		if version != statuses[len(statuses)-2].Version {
+			t.Errorf("expected schema version %d, got %d", statuses[len(statuses)-2].Version, version)
+		}
+	})
 }
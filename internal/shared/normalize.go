@@ -0,0 +1,180 @@
+package shared
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// foldReplacer maps typographic dashes and quotes to their ASCII forms, accented Latin letters to their base letters,
+// and "&" to "and". Input is lowercased first.
+var foldReplacer = func() *strings.Replacer {
+	pairs := []string{
+		"‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "―", "-", "−", "-",
+		"‘", "'", "’", "'", "‚", "'", "‛", "'", "`", "'", "´", "'",
+		"“", `"`, "”", `"`, "„", `"`,
+		"&", " and ", "ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "đ", "d", "ł", "l", "þ", "th",
+	}
+	accented := []rune("àáâãäåāăąçćĉċčďèéêëēĕėęěĝğġģĥìíîïĩīĭįıĵķĺļľñńņňòóôõöōŏőŕŗřśŝşšţťùúûüũūŭůűųŵýÿŷźżž")
+	base := []rune("aaaaaaaaacccccdeeeeeeeeegggghiiiiiiiiijklllnnnnoooooooorrrssssttuuuuuuuuuuwyyyzzz")
+	for i, r := range accented {
+		pairs = append(pairs, string(r), string(base[i]))
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+var (
+	// versionPattern matches the release decorations services add to titles of the same recording
+	versionPattern = regexp.MustCompile(`\b(remaster(ed)?|radio edit|single edit|(single|album|radio|mono|stereo|\d{4}) (version|mix)|mono|stereo|explicit|clean|bonus track|deluxe( edition)?|official (music )?(video|audio)|lyrics?( video)?|visuali[sz]er|hd|hq)\b`)
+
+	// featuringPattern matches a featured artist credit up to the end of the text
+	featuringPattern = regexp.MustCompile(`(^|\s)(feat\.?|ft\.?|featuring)\s.*$`)
+
+	// creditPattern matches a title note that credits other artists, such as "feat. X" or Spotify's "with X"
+	creditPattern = regexp.MustCompile(`^(feat\.?|ft\.?|featuring|with)\s`)
+
+	// bracketPattern matches a parenthesized or bracketed group
+	bracketPattern = regexp.MustCompile(`\s*[(\[]([^()\[\]]*)[)\]]`)
+
+	// dashSuffixPattern matches a " - " separated suffix, such as "- Radio Edit" or "- 2011 Remaster"
+	dashSuffixPattern = regexp.MustCompile(`\s+-\s+([^-]*)$`)
+)
+
+// NormalizeText folds text for comparison: lowercased, accents and typographic dashes and quotes folded to ASCII,
+// "&" spelled "and", apostrophes dropped, other punctuation treated as space, and whitespace collapsed.
+func NormalizeText(text string) string {
+	folded := foldReplacer.Replace(strings.ToLower(text))
+	folded = strings.ReplaceAll(folded, "'", "")
+	return strings.Join(strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// NormalizeTitle folds a track title with [NormalizeText] after removing what differs between releases of the
+// same recording: featured artist credits ("feat. X", "ft. X", or a bracketed "with X"), and parenthesized,
+// bracketed, or dash-separated version notes such as "(Remastered 2011)", "[Official Video]", or "- Radio Edit".
+//
+// Notes naming a different recording, such as "(Live)", "(Acoustic)", or "- Extended Mix", are kept.
+// A title that is nothing but decoration is folded as is.
+func NormalizeTitle(title string) string {
+	stripped := foldReplacer.Replace(strings.ToLower(title))
+
+	stripped = bracketPattern.ReplaceAllStringFunc(stripped, func(group string) string {
+		inner := bracketPattern.FindStringSubmatch(group)[1]
+		if isDecoration(inner) {
+			return ""
+		}
+		return group
+	})
+	for {
+		match := dashSuffixPattern.FindStringSubmatchIndex(stripped)
+		if match == nil || !isDecoration(stripped[match[2]:match[3]]) {
+			break
+		}
+		stripped = stripped[:match[0]]
+	}
+	stripped = featuringPattern.ReplaceAllString(stripped, "")
+
+	if normalized := NormalizeText(stripped); normalized != "" {
+		return normalized
+	}
+	return NormalizeText(title)
+}
+
+// isDecoration reports whether a bracketed or dash-separated title note is a version note or featured credit
+func isDecoration(note string) bool {
+	note = strings.TrimSpace(note)
+	return versionPattern.MatchString(note) || creditPattern.MatchString(note)
+}
+
+// NormalizeArtist folds the primary artist of a credit with [NormalizeText]: the first of several artists
+// separated by ",", "&", or ";", without featured artists or a YouTube " - Topic" channel suffix.
+func NormalizeArtist(artist string) string {
+	primary := strings.ToLower(artist)
+	primary = strings.TrimSuffix(strings.TrimSpace(primary), " - topic")
+	primary = featuringPattern.ReplaceAllString(primary, "")
+	if i := strings.IndexAny(primary, ",&;"); i > 0 {
+		primary = primary[:i]
+	}
+
+	if normalized := NormalizeText(primary); normalized != "" {
+		return normalized
+	}
+	return NormalizeText(artist)
+}
+
+// transliterations spell lowercase Cyrillic and Greek letters in Latin; uppercase letters are derived from them
+var transliterations = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye", 'ж': "zh", 'з': "z",
+	'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i", 'ή': "i", 'θ': "th",
+	'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'ό': "o",
+	'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'ύ': "y", 'ϋ': "y", 'ΰ': "y", 'φ': "f", 'χ': "ch",
+	'ψ': "ps", 'ω': "o", 'ώ': "o",
+}
+
+// Transliterate spells text in plain Latin letters for services that index titles and artists that way: accented
+// Latin letters lose their accents, and Cyrillic and Greek letters are romanized, keeping case. Other characters,
+// such as CJK, are kept, so text with nothing to transliterate is returned as is.
+func Transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		lower := unicode.ToLower(r)
+		latin, ok := transliterations[lower]
+		if !ok {
+			latin = foldReplacer.Replace(string(lower))
+			ok = latin != string(lower) && !strings.Contains(latin, " ")
+		}
+		switch {
+		case !ok:
+			b.WriteRune(r)
+		case lower != r && latin != "":
+			b.WriteString(strings.ToUpper(latin[:1]) + latin[1:])
+		default:
+			b.WriteString(latin)
+		}
+	}
+	return b.String()
+}
+
+// creditSeparatorPattern matches what separates artists within a single credit, such as "A & B" or "A feat. B"
+var creditSeparatorPattern = regexp.MustCompile(`\s*(,|&|;|\s(feat\.?|ft\.?|featuring)\s)\s*`)
+
+// NormalizeArtists folds every artist credited in names with [NormalizeText], in credit order without
+// duplicates. Credits naming several artists, such as "A & B" or "A feat. B", are split, so a service crediting
+// ["A", "B"] and one crediting "A & B" name the same artists.
+func NormalizeArtists(names []string) []string {
+	var artists []string
+	for _, name := range names {
+		name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), " - topic")
+		for _, part := range creditSeparatorPattern.Split(name, -1) {
+			if artist := NormalizeText(part); artist != "" && !slices.Contains(artists, artist) {
+				artists = append(artists, artist)
+			}
+		}
+	}
+	return artists
+}
+
+// ArtistsMatch reports whether one artist credit names every artist of the other after [NormalizeArtists], as the
+// same credit does and as a duet credited in full on one service and to one of its artists on the other does.
+// Credits that each name an artist the other lacks, such as two songs sharing a featured artist, do not match.
+// Two empty credits match.
+func ArtistsMatch(a, b []string) bool {
+	fewer, more := NormalizeArtists(a), NormalizeArtists(b)
+	if len(fewer) > len(more) {
+		fewer, more = more, fewer
+	}
+	if len(fewer) == 0 {
+		return len(more) == 0
+	}
+	for _, artist := range fewer {
+		if !slices.Contains(more, artist) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package shared
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tc := []struct {
+		title string
+		want  string
+	}{
+		{"Bohemian Rhapsody", "bohemian rhapsody"},
+		{"Here Comes the Sun (Remastered 2009)", "here comes the sun"},
+		{"Here Comes the Sun - Remastered 2009", "here comes the sun"},
+		{"Here Comes the Sun - 2009 Remaster", "here comes the sun"},
+		{"Blinding Lights - Radio Edit", "blinding lights"},
+		{"Get Lucky (feat. Pharrell Williams)", "get lucky"},
+		{"Get Lucky [feat. Pharrell Williams] [Official Video]", "get lucky"},
+		{"Get Lucky ft. Pharrell Williams", "get lucky"},
+		{"Stay (with Justin Bieber)", "stay"},
+		{"Dance With Me", "dance with me"},
+		{"Paint It, Black - Mono Version", "paint it black"},
+		{"Don’t Stop Me Now", "dont stop me now"},
+		{"Rock & Roll", "rock and roll"},
+		{"Café del Mar – Energy 52", "cafe del mar energy 52"},
+		{"Hurt (Live)", "hurt live"},
+		{"Strobe - Extended Mix", "strobe extended mix"},
+		{"Fortnight (feat. Post Malone) - Explicit", "fortnight"},
+		{"(Remastered)", "remastered"},
+		{"夜に駆ける", "夜に駆ける"},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := NormalizeTitle(tt.title); got != tt.want {
+				t.Errorf("NormalizeTitle(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeArtist(t *testing.T) {
+	tc := []struct {
+		artist string
+		want   string
+	}{
+		{"Daft Punk", "daft punk"},
+		{"Daft Punk, Pharrell Williams", "daft punk"},
+		{"Daft Punk & Pharrell Williams", "daft punk"},
+		{"Daft Punk feat. Pharrell Williams", "daft punk"},
+		{"Daft Punk ft. Pharrell Williams", "daft punk"},
+		{"Daft Punk - Topic", "daft punk"},
+		{"Beyoncé", "beyonce"},
+		{"Sigur Rós", "sigur ros"},
+		{"  The   Beatles ", "the beatles"},
+		{"& Friends", "and friends"},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.artist, func(t *testing.T) {
+			if got := NormalizeArtist(tt.artist); got != tt.want {
+				t.Errorf("NormalizeArtist(%q) = %q, want %q", tt.artist, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArtistsMatch(t *testing.T) {
+	tc := []struct {
+		name string
+		a, b []string
+		want bool
+	}{
+		{"same artists", []string{"Daft Punk", "Pharrell Williams"}, []string{"daft punk", "pharrell williams"}, true},
+		{"joined credit", []string{"Daft Punk", "Pharrell Williams"}, []string{"Pharrell Williams & Daft Punk"}, true},
+		{"featured credit", []string{"Daft Punk feat. Pharrell Williams"}, []string{"Pharrell Williams"}, true},
+		{"one artist only", []string{"Queen", "David Bowie"}, []string{"David Bowie - Topic"}, true},
+		{"shared featured artist", []string{"Calvin Harris", "Rihanna"}, []string{"Kygo feat. Rihanna"}, false},
+		{"disjoint", []string{"Queen", "David Bowie"}, []string{"Annie Lennox"}, false},
+		{"one empty", []string{"Queen"}, nil, false},
+		{"both empty", nil, []string{" "}, true},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArtistsMatch(tt.a, tt.b); got != tt.want {
+				t.Errorf("ArtistsMatch(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	if got := NormalizeArtists([]string{"Beyoncé & JAY-Z", "Jay-Z"}); !slices.Equal(got, []string{"beyonce", "jay z"}) {
+		t.Errorf("NormalizeArtists() = %q, want split and deduplicated artists", got)
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	tc := []struct {
+		text string
+		want string
+	}{
+		{"Группа крови", "Gruppa krovi"},
+		{"Кино", "Kino"},
+		{"Щедрик", "Shchedrik"},
+		{"Βίκυ Λέανδρος", "Viky Leandros"},
+		{"Sigur Rós", "Sigur Ros"},
+		{"Mötley Crüe", "Motley Crue"},
+		{"Ærø", "Aero"},
+		{"Simon & Garfunkel", "Simon & Garfunkel"},
+		{"宇多田ヒカル", "宇多田ヒカル"},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := Transliterate(tt.text); got != tt.want {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
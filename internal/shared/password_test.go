@@ -0,0 +1,38 @@
+package shared
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if !strings.HasPrefix(hash, "pbkdf2-sha256$600000$") {
+		t.Errorf("unexpected hash format %q", hash)
+	}
+	if other, _ := HashPassword("correct horse"); other == hash {
+		t.Error("expected a fresh salt for every hash")
+	}
+
+	if !VerifyPassword(hash, "correct horse") {
+		t.Error("expected the password to match its hash")
+	}
+	for _, tc := range []struct{ name, hash, password string }{
+		{"wrong password", hash, "battery staple"},
+		{"empty hash", "", "correct horse"},
+		{"unknown scheme", strings.Replace(hash, "pbkdf2-sha256", "md5", 1), "correct horse"},
+		{"bad iterations", "pbkdf2-sha256$x$c2FsdA$a2V5", "correct horse"},
+	} {
+		if VerifyPassword(tc.hash, tc.password) {
+			t.Errorf("%s: expected no match", tc.name)
+		}
+	}
+
+	if _, err := HashPassword(""); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an empty password to be rejected, got %v", err)
+	}
+}
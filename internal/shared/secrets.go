@@ -0,0 +1,393 @@
+package shared
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Token storage backends selectable with [SecretsConfig.Store].
+const (
+	SecretStoreConfig   = "config"   // Plaintext in config.toml
+	SecretStoreKeychain = "keychain" // macOS Keychain or the Secret Service (libsecret) on Linux
+	SecretStoreFile     = "file"     // AES-256-GCM encrypted file unlocked with [SecretsPassphraseEnv]
+)
+
+// SecretsPassphraseEnv holds the passphrase of the encrypted credentials file.
+const SecretsPassphraseEnv = "YTX_SECRETS_PASSPHRASE"
+
+// DefaultSecretsPath is used when [SecretsConfig.Path] is unset.
+const DefaultSecretsPath = "./credentials.enc"
+
+const (
+	keychainService = "ytx"
+	keychainAccount = "tokens"
+	kdfIterations   = 600_000
+)
+
+// SecretsConfig selects where OAuth tokens are stored.
+type SecretsConfig struct {
+	Store string `toml:"store,omitempty"` // "config" (default), "keychain", or "file"
+	Path  string `toml:"path,omitempty"`  // Encrypted credentials file for the "file" store. Default: ./credentials.enc
+}
+
+// SecretStore persists tokens outside config.toml, keyed by their dotted config path.
+type SecretStore interface {
+	Load() (map[string]string, error)
+	Save(secrets map[string]string) error
+	String() string
+}
+
+// NewSecretStore returns the store selected by s, or nil when tokens are kept in config.toml.
+func (s SecretsConfig) NewSecretStore() (SecretStore, error) {
+	switch s.Store {
+	case "", SecretStoreConfig:
+		return nil, nil
+	case SecretStoreKeychain:
+		return keychainStore{}, nil
+	case SecretStoreFile:
+		passphrase := os.Getenv(SecretsPassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("%w: %s must be set to unlock the encrypted credentials file", ErrSecretStore, SecretsPassphraseEnv)
+		}
+		path := s.Path
+		if path == "" {
+			path = DefaultSecretsPath
+		}
+		return encryptedFileStore{path: ExpandPath(path), passphrase: passphrase}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown secrets store '%s' (use config, keychain, or file)", ErrInvalidConfig, s.Store)
+	}
+}
+
+// keychainItemNotFound is the exit status of `security find-generic-password` when no entry exists
+// (errSecItemNotFound).
+const keychainItemNotFound = 44
+
+// runSecretCommand runs a keychain helper, writing stdin to it when non-empty.
+//
+// A helper that exits unsuccessfully, or that writes to its error output, fails with a [secretCommandError]. The
+// helpers only write there when something went wrong, and `security -i` reports a failed command that way while
+// still exiting with status 0.
+var runSecretCommand = func(stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return out, &secretCommandError{name: name, exitCode: exitErr.ExitCode(), stderr: strings.TrimSpace(stderr.String())}
+	case err == nil && stderr.Len() > 0:
+		return out, &secretCommandError{name: name, stderr: strings.TrimSpace(stderr.String())}
+	}
+	return out, err
+}
+
+// secretCommandError is a keychain helper that failed, with its exit status and error output.
+type secretCommandError struct {
+	name     string
+	exitCode int
+	stderr   string
+}
+
+func (e *secretCommandError) Error() string {
+	if e.stderr != "" {
+		return fmt.Sprintf("%s exited with status %d: %s", e.name, e.exitCode, e.stderr)
+	}
+	return fmt.Sprintf("%s exited with status %d", e.name, e.exitCode)
+}
+
+// notFound reports whether err is the keychain helper of rt saying no entry is saved.
+//
+// secret-tool exits with status 1 and no output for a missing entry, and with an error message when the keyring is
+// locked or the Secret Service is unavailable, which must not pass for having no tokens.
+func (e *secretCommandError) notFound(rt string) bool {
+	switch rt {
+	case "darwin":
+		return e.exitCode == keychainItemNotFound
+	case "linux":
+		return e.exitCode == 1 && e.stderr == ""
+	}
+	return false
+}
+
+// keychainStore keeps the tokens as a single JSON entry in the OS keychain.
+//
+// Uses the security CLI on macOS and secret-tool on Linux.
+type keychainStore struct{}
+
+func (keychainStore) String() string {
+	return "OS keychain"
+}
+
+func (k keychainStore) Load() (map[string]string, error) {
+	var out []byte
+	var err error
+	rt := getRuntime()
+	switch rt {
+	case "darwin":
+		out, err = runSecretCommand("", "security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w")
+	case "linux":
+		out, err = runSecretCommand("", "secret-tool", "lookup", "service", keychainService, "account", keychainAccount)
+	default:
+		return nil, fmt.Errorf("%w: keychain is not supported on %s", ErrSecretStore, rt)
+	}
+
+	if err != nil {
+		var cmdErr *secretCommandError
+		if errors.As(err, &cmdErr) && cmdErr.notFound(rt) {
+			return map[string]string{}, nil // No entry saved yet
+		}
+		return nil, fmt.Errorf("%w: failed to read keychain: %v", ErrSecretStore, err)
+	}
+
+	secrets := map[string]string{}
+	if data := bytes.TrimSpace(out); len(data) > 0 {
+		if err := json.Unmarshal(data, &secrets); err != nil {
+			return nil, fmt.Errorf("%w: keychain entry is not valid JSON: %v", ErrSecretStore, err)
+		}
+	}
+	return secrets, nil
+}
+
+func (k keychainStore) Save(secrets map[string]string) error {
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+
+	switch rt := getRuntime(); rt {
+	case "darwin":
+		// The tokens go to `security -i` on stdin, hex-encoded so they need no quoting, to keep them out of the
+		// process list
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", keychainService, keychainAccount, hex.EncodeToString(data))
+		_, err = runSecretCommand(command, "security", "-i")
+	case "linux":
+		_, err = runSecretCommand(string(data), "secret-tool", "store", "--label=ytx tokens", "service", keychainService, "account", keychainAccount)
+	default:
+		return fmt.Errorf("%w: keychain is not supported on %s", ErrSecretStore, rt)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: failed to write keychain: %v", ErrSecretStore, err)
+	}
+	return nil
+}
+
+// encryptedFileStore keeps the tokens in a file encrypted with AES-256-GCM, using a key derived from a
+// passphrase with PBKDF2-SHA256.
+type encryptedFileStore struct {
+	path       string
+	passphrase string
+}
+
+// encryptedSecrets is the on-disk format of [encryptedFileStore]
+type encryptedSecrets struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func (f encryptedFileStore) String() string {
+	return "encrypted file " + f.path
+}
+
+func (f encryptedFileStore) gcm(salt []byte, iterations int) (cipher.AEAD, error) {
+	return deriveGCM(f.passphrase, salt, iterations)
+}
+
+// deriveGCM returns an AES-256-GCM cipher keyed with PBKDF2-SHA256 of passphrase
+func deriveGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func (f encryptedFileStore) Load() (map[string]string, error) {
+	raw, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var file encryptedSecrets
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("%w: credentials file %s is corrupted: %v", ErrSecretStore, f.path, err)
+	}
+
+	gcm, err := f.gcm(file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: credentials file %s is corrupted", ErrSecretStore, f.path)
+	}
+
+	data, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong passphrase or corrupted credentials file %s", ErrInvalidCredentials, f.path)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("%w: credentials file %s is corrupted: %v", ErrSecretStore, f.path, err)
+	}
+	return secrets, nil
+}
+
+func (f encryptedFileStore) Save(secrets map[string]string) error {
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+
+	file := encryptedSecrets{Version: 1, Iterations: kdfIterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := f.gcm(file.Salt, file.Iterations)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	file.Data = gcm.Seal(nil, file.Nonce, data, nil)
+
+	raw, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials file: %w", err)
+	}
+	if err := os.WriteFile(f.path, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+// secretBoxSalt salts the key of every [SecretBox]; the server secret it is derived from is what keeps it unique
+var secretBoxSalt = []byte("ytx secret box")
+
+// SecretBox encrypts secrets kept in the database with AES-256-GCM, like the encrypted credentials file, under a key
+// derived once from a server secret. Each value is sealed for a label naming where it is stored, so a value copied
+// to another row, such as another user's, cannot be opened there.
+type SecretBox struct {
+	gcm cipher.AEAD
+}
+
+// NewSecretBox derives a [SecretBox] key from secret.
+func NewSecretBox(secret []byte) (*SecretBox, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("%w: an encryption secret is required", ErrInvalidArgument)
+	}
+	gcm, err := deriveGCM(string(secret), secretBoxSalt, kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{gcm: gcm}, nil
+}
+
+// Seal encrypts plaintext for label, returning the nonce and ciphertext base64 encoded.
+func (b *SecretBox) Seal(plaintext []byte, label string) (string, error) {
+	nonce := make([]byte, b.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(b.gcm.Seal(nonce, nonce, plaintext, []byte(label))), nil
+}
+
+// Open decrypts a value sealed for label.
+//
+// Returns [ErrSecretStore] when the value was sealed with another secret or for another label, or is corrupted.
+func (b *SecretBox) Open(sealed, label string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < b.gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: %s is corrupted", ErrSecretStore, label)
+	}
+	nonce, ciphertext := data[:b.gcm.NonceSize()], data[b.gcm.NonceSize():]
+	plaintext, err := b.gcm.Open(nil, nonce, ciphertext, []byte(label))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s was sealed with another secret or for another owner", ErrSecretStore, label)
+	}
+	return plaintext, nil
+}
+
+// tokenSecrets collects the Spotify tokens of c and its profiles, keyed by their dotted config path.
+func (c *Config) tokenSecrets() map[string]string {
+	secrets := map[string]string{}
+	add := func(prefix string, s SpotifyConfig) {
+		if s.AccessToken != "" {
+			secrets[prefix+"spotify.access_token"] = s.AccessToken
+		}
+		if s.RefreshToken != "" {
+			secrets[prefix+"spotify.refresh_token"] = s.RefreshToken
+		}
+	}
+
+	add("credentials.", c.Credentials.Spotify)
+	for name, profile := range c.Profiles {
+		add("profiles."+name+".credentials.", profile.Credentials.Spotify)
+	}
+	return secrets
+}
+
+// setTokenSecrets fills the Spotify tokens of c and its profiles from secrets.
+func (c *Config) setTokenSecrets(secrets map[string]string) {
+	set := func(prefix string, s *SpotifyConfig) {
+		if v, ok := secrets[prefix+"spotify.access_token"]; ok {
+			s.AccessToken = v
+		}
+		if v, ok := secrets[prefix+"spotify.refresh_token"]; ok {
+			s.RefreshToken = v
+		}
+	}
+
+	set("credentials.", &c.Credentials.Spotify)
+	for name, profile := range c.Profiles {
+		set("profiles."+name+".credentials.", &profile.Credentials.Spotify)
+		c.Profiles[name] = profile
+	}
+}
+
+// withoutTokens returns a copy of c with every Spotify token cleared.
+func (c *Config) withoutTokens() *Config {
+	stripped := *c
+	stripped.Credentials.Spotify.AccessToken = ""
+	stripped.Credentials.Spotify.RefreshToken = ""
+
+	if c.Profiles != nil {
+		stripped.Profiles = make(map[string]ProfileConfig, len(c.Profiles))
+		for name, profile := range c.Profiles {
+			profile.Credentials.Spotify.AccessToken = ""
+			profile.Credentials.Spotify.RefreshToken = ""
+			stripped.Profiles[name] = profile
+		}
+	}
+	return &stripped
+}
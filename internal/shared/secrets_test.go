@@ -0,0 +1,241 @@
+package shared
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSecrets(t *testing.T) {
+	t.Run("NewSecretStore", func(t *testing.T) {
+		t.Run("config store keeps tokens in config.toml", func(t *testing.T) {
+			for _, name := range []string{"", SecretStoreConfig} {
+				store, err := SecretsConfig{Store: name}.NewSecretStore()
+				if err != nil || store != nil {
+					t.Errorf("expected no store for %q, got %v, %v", name, store, err)
+				}
+			}
+		})
+
+		t.Run("file store requires a passphrase", func(t *testing.T) {
+			t.Setenv(SecretsPassphraseEnv, "")
+			if _, err := (SecretsConfig{Store: SecretStoreFile}).NewSecretStore(); !errors.Is(err, ErrSecretStore) {
+				t.Errorf("expected ErrSecretStore, got %v", err)
+			}
+		})
+
+		t.Run("unknown store", func(t *testing.T) {
+			if _, err := (SecretsConfig{Store: "vault"}).NewSecretStore(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	})
+
+	t.Run("encrypted file round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "credentials.enc")
+		store := encryptedFileStore{path: path, passphrase: "correct horse"}
+
+		if secrets, err := store.Load(); err != nil || len(secrets) != 0 {
+			t.Fatalf("expected empty secrets before the first save, got %v, %v", secrets, err)
+		}
+
+		want := map[string]string{"credentials.spotify.access_token": "secret_access"}
+		if err := store.Save(want); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read credentials file: %v", err)
+		}
+		if strings.Contains(string(raw), "secret_access") {
+			t.Error("expected the token to be encrypted on disk")
+		}
+
+		got, err := store.Load()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got["credentials.spotify.access_token"] != "secret_access" {
+			t.Errorf("expected decrypted token, got %v", got)
+		}
+
+		wrong := encryptedFileStore{path: path, passphrase: "wrong"}
+		if _, err := wrong.Load(); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("expected ErrInvalidCredentials for a wrong passphrase, got %v", err)
+		}
+	})
+
+	t.Run("keychain", func(t *testing.T) {
+		originalRuntime, originalRun := getRuntime, runSecretCommand
+		t.Cleanup(func() { getRuntime, runSecretCommand = originalRuntime, originalRun })
+
+		var entry string
+		var calls [][]string
+		runSecretCommand = func(stdin string, name string, args ...string) ([]byte, error) {
+			calls = append(calls, append([]string{name}, args...))
+			switch {
+			case len(args) > 0 && args[0] == "store":
+				entry = stdin
+			case len(args) > 0 && args[0] == "lookup" && entry == "":
+				return nil, &secretCommandError{name: name, exitCode: 1}
+			}
+			return []byte(entry), nil
+		}
+		getRuntime = func() string { return "linux" }
+
+		store := keychainStore{}
+		if secrets, err := store.Load(); err != nil || len(secrets) != 0 {
+			t.Fatalf("expected empty secrets for a missing entry, got %v, %v", secrets, err)
+		}
+
+		if err := store.Save(map[string]string{"credentials.spotify.refresh_token": "refresh"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if calls[1][0] != "secret-tool" || calls[1][1] != "store" {
+			t.Errorf("expected secret-tool store, got %v", calls[1])
+		}
+
+		secrets, err := store.Load()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if secrets["credentials.spotify.refresh_token"] != "refresh" {
+			t.Errorf("expected stored token, got %v", secrets)
+		}
+
+		// A locked keyring must not pass for an empty one
+		runSecretCommand = func(stdin string, name string, args ...string) ([]byte, error) {
+			return nil, &secretCommandError{name: name, exitCode: 1, stderr: "Cannot create an item in a locked collection"}
+		}
+		if _, err := store.Load(); !errors.Is(err, ErrSecretStore) || !strings.Contains(err.Error(), "locked collection") {
+			t.Errorf("expected ErrSecretStore for a locked keyring, got %v", err)
+		}
+
+		getRuntime = func() string { return "plan9" }
+		if _, err := store.Load(); !errors.Is(err, ErrSecretStore) {
+			t.Errorf("expected ErrSecretStore on unsupported platforms, got %v", err)
+		}
+	})
+
+	t.Run("runSecretCommand", func(t *testing.T) {
+		var cmdErr *secretCommandError
+		if _, err := runSecretCommand("", "sh", "-c", "exit 1"); !errors.As(err, &cmdErr) || !cmdErr.notFound("linux") {
+			t.Errorf("expected a silent exit 1 to mean no entry, got %v", err)
+		}
+		if _, err := runSecretCommand("", "sh", "-c", "echo locked >&2; exit 1"); !errors.As(err, &cmdErr) || cmdErr.notFound("linux") {
+			t.Errorf("expected an error message to mean failure, got %v", err)
+		}
+		if _, err := runSecretCommand("", "sh", "-c", "echo failed >&2"); !errors.As(err, &cmdErr) || cmdErr.stderr != "failed" {
+			t.Errorf("expected error output to fail the command, got %v", err)
+		}
+		if out, err := runSecretCommand("input", "cat"); err != nil || string(out) != "input" {
+			t.Errorf("expected stdin passed through, got %q, %v", out, err)
+		}
+	})
+
+	t.Run("macOS keychain", func(t *testing.T) {
+		originalRuntime, originalRun := getRuntime, runSecretCommand
+		t.Cleanup(func() { getRuntime, runSecretCommand = originalRuntime, originalRun })
+		getRuntime = func() string { return "darwin" }
+
+		var stdins []string
+		var calls [][]string
+		exitCode := keychainItemNotFound
+		runSecretCommand = func(stdin string, name string, args ...string) ([]byte, error) {
+			stdins, calls = append(stdins, stdin), append(calls, append([]string{name}, args...))
+			if exitCode != 0 {
+				return nil, &secretCommandError{name: name, exitCode: exitCode}
+			}
+			return nil, nil
+		}
+
+		store := keychainStore{}
+		if secrets, err := store.Load(); err != nil || len(secrets) != 0 {
+			t.Fatalf("expected empty secrets for a missing entry, got %v, %v", secrets, err)
+		}
+
+		exitCode = 0
+		if err := store.Save(map[string]string{"credentials.spotify.refresh_token": "refresh"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !slices.Equal(calls[1], []string{"security", "-i"}) {
+			t.Errorf("expected the tokens kept off the command line, got %v", calls[1])
+		}
+		hexTokens := hex.EncodeToString([]byte(`{"credentials.spotify.refresh_token":"refresh"}`))
+		if !strings.HasPrefix(stdins[1], "add-generic-password ") || !strings.Contains(stdins[1], "-X "+hexTokens) {
+			t.Errorf("expected the tokens on stdin, got %q", stdins[1])
+		}
+
+		exitCode = 51 // errSecInteractionNotAllowed: the keychain is locked
+		if _, err := store.Load(); !errors.Is(err, ErrSecretStore) {
+			t.Errorf("expected ErrSecretStore for a locked keychain, got %v", err)
+		}
+	})
+
+	t.Run("SaveConfig and LoadConfig use the store", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "config.toml")
+		t.Setenv(SecretsPassphraseEnv, "passphrase")
+
+		config := DefaultConfig()
+		config.Secrets = SecretsConfig{Store: SecretStoreFile, Path: filepath.Join(dir, "credentials.enc")}
+		config.Credentials.Spotify.AccessToken = "base_access"
+		config.Credentials.Spotify.RefreshToken = "base_refresh"
+		config.Profiles = map[string]ProfileConfig{
+			"work": {Credentials: CredentialsConfig{Spotify: SpotifyConfig{ClientID: "work_id", AccessToken: "work_access"}}},
+		}
+
+		if err := SaveConfig(configPath, config); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if config.Credentials.Spotify.AccessToken != "base_access" {
+			t.Error("expected the saved config to keep its tokens in memory")
+		}
+
+		raw, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("failed to read config: %v", err)
+		}
+		for _, token := range []string{"base_access", "base_refresh", "work_access"} {
+			if strings.Contains(string(raw), token) {
+				t.Errorf("expected %s to be left out of config.toml", token)
+			}
+		}
+
+		loaded, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if loaded.Credentials.Spotify.RefreshToken != "base_refresh" {
+			t.Errorf("expected refresh token from the store, got %q", loaded.Credentials.Spotify.RefreshToken)
+		}
+		if got := loaded.Profiles["work"].Credentials.Spotify.AccessToken; got != "work_access" {
+			t.Errorf("expected profile token from the store, got %q", got)
+		}
+
+		t.Setenv(SecretsPassphraseEnv, "")
+		if _, err := LoadConfig(configPath); !errors.Is(err, ErrSecretStore) {
+			t.Errorf("expected ErrSecretStore without a passphrase, got %v", err)
+		}
+	})
+
+	t.Run("default config has no secrets section", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		if err := SaveConfig(configPath, DefaultConfig()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		raw, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("failed to read config: %v", err)
+		}
+		if strings.Contains(string(raw), "[secrets]") {
+			t.Errorf("expected no [secrets] section, got:\n%s", raw)
+		}
+	})
+}
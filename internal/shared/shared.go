@@ -27,25 +27,6 @@ func NewLogger(w io.Writer) *log.Logger {
 	return log.NewWithOptions(w, opts)
 }
 
-// NewFileLogger creates a new [log.Logger] that writes to a file at the given path.
-//
-// If the directory doesn't exist, it will be created. The logger uses the same
-// timestamp and caller reporting settings as [NewLogger].
-func NewFileLogger(path string) (*log.Logger, error) {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create log directory: %w", err)
-	}
-
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
-	}
-
-	opts := log.Options{ReportTimestamp: true, ReportCaller: true, TimeFormat: time.Kitchen, Level: log.DebugLevel}
-	return log.NewWithOptions(file, opts), nil
-}
-
 // WithLogger creates a child [log.Logger] with the specified key-value pairs added to all log entries.
 func WithLogger(l *log.Logger, kv ...any) *log.Logger {
 	return l.With(kv...)
@@ -68,12 +49,13 @@ func MarshalJSON(data any, pretty bool) ([]byte, error) {
 	return json.Marshal(data)
 }
 
-// NormalizeTrackKey creates a normalized key for track comparison.
+// NormalizeTrackKey creates a normalized key for track comparison, "title|artist", from [NormalizeTitle] and
+// [NormalizeArtist].
 //
-// Converts to lowercase and removes extra whitespace for fuzzy matching.
+// Releases of the same recording share a key across services: "Song (Remastered 2011)" by "Artist feat. Guest"
+// and "Song - Radio Edit" by "Artist & Guest" are both "song|artist".
 func NormalizeTrackKey(title, artist string) string {
-	normalized := strings.ToLower(strings.TrimSpace(title)) + "|" + strings.ToLower(strings.TrimSpace(artist))
-	return strings.Join(strings.Fields(normalized), " ")
+	return NormalizeTitle(title) + "|" + NormalizeArtist(artist)
 }
 
 // GenerateState generates a cryptographically secure random state token for CSRF protection.
@@ -136,6 +118,20 @@ func FormatDuration(seconds int) string {
 	return fmt.Sprintf("%d:%02d", minutes, secs)
 }
 
+// FormatBytes converts a byte count to a human-readable size (e.g. "1.5 MB")
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 // VisibilityString converts a boolean public flag to a readable string
 func VisibilityString(public bool) string {
 	if public {
@@ -31,6 +31,18 @@ func TestNormalizeTrackKey(t *testing.T) {
 			artist: "ArTiSt NaMe",
 			want:   "song title|artist name",
 		},
+		{
+			name:   "remaster and featured artist",
+			title:  "Song Title (Remastered 2011)",
+			artist: "Artist Name feat. Guest",
+			want:   "song title|artist name",
+		},
+		{
+			name:   "radio edit and multiple artists",
+			title:  "Song Title - Radio Edit",
+			artist: "Artist Name & Guest",
+			want:   "song title|artist name",
+		},
 	}
 
 	for _, tt := range tc {
@@ -169,6 +181,26 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+	}
+
+	for _, tt := range tests {
+		result := FormatBytes(tt.bytes)
+		if result != tt.expected {
+			t.Errorf("FormatBytes(%d) = %s; want %s", tt.bytes, result, tt.expected)
+		}
+	}
+}
+
 func TestVisibilityString(t *testing.T) {
 	tests := []struct {
 		public   bool
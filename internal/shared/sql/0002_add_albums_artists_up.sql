@@ -0,0 +1,48 @@
+-- Albums and artists tables for library mirroring
+
+-- Albums table (cached album metadata)
+CREATE TABLE IF NOT EXISTS albums (
+    id TEXT PRIMARY KEY,
+    sequence INTEGER NOT NULL UNIQUE,
+    service TEXT NOT NULL,
+    service_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    artist TEXT,
+    year TEXT,
+    track_count INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    UNIQUE(service, service_id)
+);
+
+-- Sequence counter for albums
+CREATE TABLE IF NOT EXISTS albums_sequence (
+    id INTEGER PRIMARY KEY,
+    value INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO albums_sequence (id, value) VALUES (1, 0);
+CREATE INDEX IF NOT EXISTS idx_albums_service ON albums(service);
+CREATE INDEX IF NOT EXISTS idx_albums_deleted_at ON albums(deleted_at);
+
+-- Artists table (cached artist metadata)
+CREATE TABLE IF NOT EXISTS artists (
+    id TEXT PRIMARY KEY,
+    sequence INTEGER NOT NULL UNIQUE,
+    service TEXT NOT NULL,
+    service_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    UNIQUE(service, service_id)
+);
+
+-- Sequence counter for artists
+CREATE TABLE IF NOT EXISTS artists_sequence (
+    id INTEGER PRIMARY KEY,
+    value INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO artists_sequence (id, value) VALUES (1, 0);
+CREATE INDEX IF NOT EXISTS idx_artists_service ON artists(service);
+CREATE INDEX IF NOT EXISTS idx_artists_deleted_at ON artists(deleted_at);
@@ -0,0 +1,26 @@
+-- Rollback album UPC keys
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_artists_name;
+DROP INDEX IF EXISTS idx_albums_upc;
+
+-- Remove upc column (SQLite requires creating a new table without the column)
+CREATE TABLE albums_new (
+    id TEXT PRIMARY KEY,
+    sequence INTEGER NOT NULL UNIQUE,
+    service TEXT NOT NULL,
+    service_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    artist TEXT,
+    year TEXT,
+    track_count INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    UNIQUE(service, service_id)
+);
+INSERT INTO albums_new SELECT id, sequence, service, service_id, title, artist, year, track_count, created_at, updated_at, deleted_at FROM albums;
+DROP TABLE albums;
+ALTER TABLE albums_new RENAME TO albums;
+CREATE INDEX IF NOT EXISTS idx_albums_service ON albums(service);
+CREATE INDEX IF NOT EXISTS idx_albums_deleted_at ON albums(deleted_at);
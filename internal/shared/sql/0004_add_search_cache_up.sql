@@ -0,0 +1,19 @@
+-- Search cache for YouTube Music track matches
+
+-- Search cache table (best match per normalized title/artist query)
+CREATE TABLE IF NOT EXISTS search_cache (
+    id TEXT PRIMARY KEY,
+    query_key TEXT NOT NULL UNIQUE,
+    query_isrc TEXT DEFAULT NULL,
+    service TEXT NOT NULL,
+    service_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    artist TEXT,
+    album TEXT,
+    duration INTEGER DEFAULT 0,
+    isrc TEXT,
+    fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_cache_query_isrc ON search_cache(query_isrc);
+CREATE INDEX IF NOT EXISTS idx_search_cache_fetched_at ON search_cache(fetched_at);
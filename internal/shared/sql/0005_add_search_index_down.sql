@@ -0,0 +1,11 @@
+-- Rollback full-text search indexes
+
+DROP TRIGGER IF EXISTS playlists_fts_delete;
+DROP TRIGGER IF EXISTS playlists_fts_update;
+DROP TRIGGER IF EXISTS playlists_fts_insert;
+DROP TABLE IF EXISTS playlists_fts;
+
+DROP TRIGGER IF EXISTS tracks_fts_delete;
+DROP TRIGGER IF EXISTS tracks_fts_update;
+DROP TRIGGER IF EXISTS tracks_fts_insert;
+DROP TABLE IF EXISTS tracks_fts;
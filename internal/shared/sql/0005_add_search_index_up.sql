@@ -0,0 +1,40 @@
+-- Full-text search over cached tracks and playlists
+--
+-- FTS4 is used because it is compiled into the default go-sqlite3 build (FTS5 needs a build tag).
+-- Index rows are keyed by the owning row's sequence, which unlike rowid is stable across VACUUM.
+
+-- Track search index (title, artist, album)
+CREATE VIRTUAL TABLE IF NOT EXISTS tracks_fts USING fts4(title, artist, album, tokenize=unicode61);
+
+CREATE TRIGGER IF NOT EXISTS tracks_fts_insert AFTER INSERT ON tracks BEGIN
+    INSERT INTO tracks_fts (docid, title, artist, album) VALUES (new.sequence, new.title, new.artist, new.album);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tracks_fts_update AFTER UPDATE OF title, artist, album ON tracks BEGIN
+    DELETE FROM tracks_fts WHERE docid = old.sequence;
+    INSERT INTO tracks_fts (docid, title, artist, album) VALUES (new.sequence, new.title, new.artist, new.album);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tracks_fts_delete AFTER DELETE ON tracks BEGIN
+    DELETE FROM tracks_fts WHERE docid = old.sequence;
+END;
+
+INSERT INTO tracks_fts (docid, title, artist, album) SELECT sequence, title, artist, album FROM tracks;
+
+-- Playlist search index (name, description)
+CREATE VIRTUAL TABLE IF NOT EXISTS playlists_fts USING fts4(name, description, tokenize=unicode61);
+
+CREATE TRIGGER IF NOT EXISTS playlists_fts_insert AFTER INSERT ON playlists BEGIN
+    INSERT INTO playlists_fts (docid, name, description) VALUES (new.sequence, new.name, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS playlists_fts_update AFTER UPDATE OF name, description ON playlists BEGIN
+    DELETE FROM playlists_fts WHERE docid = old.sequence;
+    INSERT INTO playlists_fts (docid, name, description) VALUES (new.sequence, new.name, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS playlists_fts_delete AFTER DELETE ON playlists BEGIN
+    DELETE FROM playlists_fts WHERE docid = old.sequence;
+END;
+
+INSERT INTO playlists_fts (docid, name, description) SELECT sequence, name, description FROM playlists;
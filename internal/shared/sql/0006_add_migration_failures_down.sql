@@ -0,0 +1,5 @@
+-- Rollback migration failures table
+
+DROP INDEX IF EXISTS idx_migration_failures_artist;
+DROP INDEX IF EXISTS idx_migration_failures_migration_id;
+DROP TABLE IF EXISTS migration_failures;
@@ -0,0 +1,16 @@
+-- Unmatched tracks recorded per migration for transfer analytics
+
+-- Migration failures table (one row per source track that could not be matched)
+CREATE TABLE IF NOT EXISTS migration_failures (
+    id TEXT PRIMARY KEY,
+    migration_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    artist TEXT,
+    album TEXT,
+    isrc TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (migration_id) REFERENCES migrations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_migration_failures_migration_id ON migration_failures(migration_id);
+CREATE INDEX IF NOT EXISTS idx_migration_failures_artist ON migration_failures(artist);
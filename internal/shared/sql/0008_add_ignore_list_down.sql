@@ -0,0 +1,3 @@
+-- Rollback ignore list table
+
+DROP TABLE IF EXISTS ignore_list;
@@ -0,0 +1,6 @@
+-- Rollback web server accounts
+
+DROP TABLE IF EXISTS user_credentials;
+
+-- Remove password hash (DROP COLUMN keeps dependent rows, which a table rebuild would cascade-delete)
+ALTER TABLE users DROP COLUMN password_hash;
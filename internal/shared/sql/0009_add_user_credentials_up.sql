@@ -0,0 +1,15 @@
+-- Web server accounts: password logins and per-user service credentials
+
+-- Add password hash to users (NULL for the CLI's local user, which never logs in)
+ALTER TABLE users ADD COLUMN password_hash TEXT DEFAULT NULL;
+
+-- Service credentials table (one row per user and service)
+CREATE TABLE IF NOT EXISTS user_credentials (
+    user_id TEXT NOT NULL,
+    service TEXT NOT NULL, -- 'spotify' or 'youtube'
+    token TEXT, -- OAuth token as JSON
+    auth_file TEXT, -- Path to the YouTube Music proxy's auth file
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, service),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+-- Rollback track enrichment
+
+-- Remove enrichment columns (DROP COLUMN keeps playlist_tracks rows, which a table rebuild would cascade-delete)
+ALTER TABLE tracks DROP COLUMN audio_features;
+ALTER TABLE tracks DROP COLUMN genres;
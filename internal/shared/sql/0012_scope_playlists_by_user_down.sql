@@ -0,0 +1,40 @@
+-- Rollback per-user playlists
+
+-- Fails while two users have the same service playlist cached, since the old key allows only one row
+CREATE TABLE playlists_new (
+    id TEXT PRIMARY KEY,
+    sequence INTEGER NOT NULL UNIQUE,
+    service TEXT NOT NULL, -- 'spotify' or 'youtube'
+    service_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    track_count INTEGER DEFAULT 0,
+    public BOOLEAN DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(service, service_id)
+);
+INSERT INTO playlists_new SELECT id, sequence, service, service_id, user_id, name, description, track_count, public, created_at, updated_at, deleted_at FROM playlists;
+DROP TABLE playlists;
+ALTER TABLE playlists_new RENAME TO playlists;
+
+CREATE INDEX IF NOT EXISTS idx_playlists_user_id ON playlists(user_id);
+CREATE INDEX IF NOT EXISTS idx_playlists_service ON playlists(service);
+CREATE INDEX IF NOT EXISTS idx_playlists_deleted_at ON playlists(deleted_at);
+
+-- Dropping the table dropped its search index triggers. The index is keyed by sequence, which is unchanged.
+CREATE TRIGGER IF NOT EXISTS playlists_fts_insert AFTER INSERT ON playlists BEGIN
+    INSERT INTO playlists_fts (docid, name, description) VALUES (new.sequence, new.name, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS playlists_fts_update AFTER UPDATE OF name, description ON playlists BEGIN
+    DELETE FROM playlists_fts WHERE docid = old.sequence;
+    INSERT INTO playlists_fts (docid, name, description) VALUES (new.sequence, new.name, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS playlists_fts_delete AFTER DELETE ON playlists BEGIN
+    DELETE FROM playlists_fts WHERE docid = old.sequence;
+END;
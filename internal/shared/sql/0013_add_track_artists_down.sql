@@ -0,0 +1,5 @@
+-- Rollback track artists
+
+-- Remove the artists columns (DROP COLUMN keeps playlist_tracks rows, which a table rebuild would cascade-delete)
+ALTER TABLE search_cache DROP COLUMN artists;
+ALTER TABLE tracks DROP COLUMN artists;
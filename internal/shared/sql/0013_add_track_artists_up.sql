@@ -0,0 +1,5 @@
+-- Every artist credited on a cached track or search match, as a JSON array with the primary artist (also in
+-- artist) first. NULL when only the primary artist is credited.
+
+ALTER TABLE tracks ADD COLUMN artists TEXT DEFAULT NULL;
+ALTER TABLE search_cache ADD COLUMN artists TEXT DEFAULT NULL;
@@ -0,0 +1,15 @@
+-- Rollback soft delete support
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_migrations_deleted_at;
+DROP INDEX IF EXISTS idx_playlist_tracks_deleted_at;
+DROP INDEX IF EXISTS idx_tracks_deleted_at;
+DROP INDEX IF EXISTS idx_playlists_deleted_at;
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+-- Remove deleted_at columns
+ALTER TABLE migrations DROP COLUMN deleted_at;
+ALTER TABLE playlist_tracks DROP COLUMN deleted_at;
+ALTER TABLE tracks DROP COLUMN deleted_at;
+ALTER TABLE playlists DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
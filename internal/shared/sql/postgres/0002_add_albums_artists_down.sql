@@ -0,0 +1,11 @@
+-- Rollback albums and artists tables
+
+DROP INDEX IF EXISTS idx_artists_deleted_at;
+DROP INDEX IF EXISTS idx_artists_service;
+DROP TABLE IF EXISTS artists_sequence;
+DROP TABLE IF EXISTS artists;
+
+DROP INDEX IF EXISTS idx_albums_deleted_at;
+DROP INDEX IF EXISTS idx_albums_service;
+DROP TABLE IF EXISTS albums_sequence;
+DROP TABLE IF EXISTS albums;
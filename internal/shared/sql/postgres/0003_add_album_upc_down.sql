@@ -0,0 +1,8 @@
+-- Rollback album UPC keys
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_artists_name;
+DROP INDEX IF EXISTS idx_albums_upc;
+
+-- Remove upc column
+ALTER TABLE albums DROP COLUMN upc;
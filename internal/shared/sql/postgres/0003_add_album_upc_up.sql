@@ -0,0 +1,8 @@
+-- Add UPC keys to albums for cross-service matching
+
+-- Add upc column to albums
+ALTER TABLE albums ADD COLUMN upc TEXT DEFAULT NULL;
+
+-- Create indexes for cross-service lookups (artist names are compared case-insensitively with LOWER)
+CREATE INDEX IF NOT EXISTS idx_albums_upc ON albums(upc);
+CREATE INDEX IF NOT EXISTS idx_artists_name ON artists(LOWER(name));
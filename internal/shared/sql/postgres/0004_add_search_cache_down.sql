@@ -0,0 +1,5 @@
+-- Rollback search cache table
+
+DROP INDEX IF EXISTS idx_search_cache_fetched_at;
+DROP INDEX IF EXISTS idx_search_cache_query_isrc;
+DROP TABLE IF EXISTS search_cache;
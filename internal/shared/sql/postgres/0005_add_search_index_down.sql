@@ -0,0 +1,7 @@
+-- Rollback full-text search indexes
+
+DROP INDEX IF EXISTS idx_playlists_search;
+ALTER TABLE playlists DROP COLUMN search;
+
+DROP INDEX IF EXISTS idx_tracks_search;
+ALTER TABLE tracks DROP COLUMN search;
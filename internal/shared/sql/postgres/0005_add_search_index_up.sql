@@ -0,0 +1,14 @@
+-- Full-text search over cached tracks and playlists
+--
+-- Postgres keeps each row's document in a generated tsvector column, so no triggers are needed as with SQLite's
+-- FTS4 tables. The 'simple' configuration matches SQLite's unicode61 tokenizer: no stemming or stop words.
+
+-- Track search index (title, artist, album)
+ALTER TABLE tracks ADD COLUMN search tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', title || ' ' || artist || ' ' || COALESCE(album, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_tracks_search ON tracks USING GIN (search);
+
+-- Playlist search index (name, description)
+ALTER TABLE playlists ADD COLUMN search tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name || ' ' || COALESCE(description, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_playlists_search ON playlists USING GIN (search);
@@ -0,0 +1,6 @@
+-- Rollback migration progress tracking
+
+-- Remove progress columns
+ALTER TABLE migrations DROP COLUMN last_progress_at;
+ALTER TABLE migrations DROP COLUMN progress_total;
+ALTER TABLE migrations DROP COLUMN progress_current;
@@ -0,0 +1,6 @@
+-- Live progress tracking for running migrations
+
+-- Add progress columns to migrations
+ALTER TABLE migrations ADD COLUMN progress_current INTEGER DEFAULT 0;
+ALTER TABLE migrations ADD COLUMN progress_total INTEGER DEFAULT 0;
+ALTER TABLE migrations ADD COLUMN last_progress_at TIMESTAMPTZ DEFAULT NULL;
@@ -0,0 +1,12 @@
+-- Ignore list of ISRCs and artists skipped by transfers and diffs
+
+-- Ignore list table (value is normalized: uppercase ISRCs, lowercase artist names)
+CREATE TABLE IF NOT EXISTS ignore_list (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('isrc', 'artist')),
+    value TEXT NOT NULL,
+    display TEXT NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (kind, value)
+);
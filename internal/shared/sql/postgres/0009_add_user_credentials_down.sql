@@ -0,0 +1,6 @@
+-- Rollback web server accounts
+
+DROP TABLE IF EXISTS user_credentials;
+
+-- Remove password hash
+ALTER TABLE users DROP COLUMN password_hash;
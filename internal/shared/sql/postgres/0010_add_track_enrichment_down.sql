@@ -0,0 +1,4 @@
+-- Rollback track enrichment
+
+ALTER TABLE tracks DROP COLUMN audio_features;
+ALTER TABLE tracks DROP COLUMN genres;
@@ -0,0 +1,4 @@
+-- Spotify enrichment of cached tracks: artist genres and audio features, both JSON (NULL until enriched)
+
+ALTER TABLE tracks ADD COLUMN genres TEXT DEFAULT NULL;
+ALTER TABLE tracks ADD COLUMN audio_features TEXT DEFAULT NULL;
@@ -0,0 +1,6 @@
+-- Rollback credential encryption
+
+-- Sealed values cannot be read back as plaintext tokens or paths
+DELETE FROM user_credentials;
+
+ALTER TABLE user_credentials RENAME COLUMN auth_headers TO auth_file;
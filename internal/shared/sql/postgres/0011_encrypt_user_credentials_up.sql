@@ -0,0 +1,7 @@
+-- Encrypt stored service credentials and keep YouTube Music auth headers instead of server paths
+
+-- Earlier rows hold plaintext tokens and paths a client chose, so users connect their services again
+DELETE FROM user_credentials;
+
+-- Credentials are now sealed with the server's session secret
+ALTER TABLE user_credentials RENAME COLUMN auth_file TO auth_headers;
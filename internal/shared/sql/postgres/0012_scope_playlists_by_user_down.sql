@@ -0,0 +1,5 @@
+-- Rollback per-user playlists
+
+-- Fails while two users have the same service playlist cached, since the old key allows only one row
+ALTER TABLE playlists DROP CONSTRAINT playlists_user_id_service_service_id_key;
+ALTER TABLE playlists ADD CONSTRAINT playlists_service_service_id_key UNIQUE (service, service_id);
@@ -0,0 +1,4 @@
+-- Key cached playlists by user, so users who cache the same service playlist each keep their own row
+
+ALTER TABLE playlists DROP CONSTRAINT playlists_service_service_id_key;
+ALTER TABLE playlists ADD CONSTRAINT playlists_user_id_service_service_id_key UNIQUE (user_id, service, service_id);
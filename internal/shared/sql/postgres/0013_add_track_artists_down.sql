@@ -0,0 +1,4 @@
+-- Rollback track artists
+
+ALTER TABLE search_cache DROP COLUMN artists;
+ALTER TABLE tracks DROP COLUMN artists;
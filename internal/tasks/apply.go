@@ -0,0 +1,389 @@
+package tasks
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSyncFile is the sync file `ytx apply` reads when none is given
+const DefaultSyncFile = "ytx.yaml"
+
+// Sync directions of a [SyncPair]
+const (
+	OneWay = "one-way" // Tracks added to the source are added to the destination
+	TwoWay = "two-way" // Changes made on either side are merged into the other, as by [PlaylistEngine.Merge]
+)
+
+// Collision policies of a [SyncPair] without a destination ID, for when the destination service already has a
+// playlist of the name it would create
+const (
+	CollisionUpdate = "update" // Sync into the existing playlist
+	CollisionSkip   = "skip"   // Leave the existing playlist, and the pair, alone
+	CollisionCreate = "create" // Create another playlist of the same name
+	CollisionFail   = "fail"   // Fail the pair
+)
+
+// What [PlaylistEngine.Apply] did, or would do in a dry run, for a [SyncPair]
+const (
+	ApplyCreated = "created" // Transferred the source into a new destination playlist
+	ApplyUpdated = "updated" // Synced the source with an existing destination playlist, which may have been in sync
+	ApplySkipped = "skipped" // Left an existing playlist alone under [CollisionSkip]
+)
+
+// SyncOptions are the sync settings of a [SyncPair], given per pair or as the defaults of a [SyncFile].
+type SyncOptions struct {
+	From        string `yaml:"from,omitempty"`         // Source service: spotify (default) or youtube
+	To          string `yaml:"to,omitempty"`           // Destination service: youtube (default) or spotify
+	Direction   string `yaml:"direction,omitempty"`    // [OneWay] (default) or [TwoWay]
+	Dedupe      *bool  `yaml:"dedupe,omitempty"`       // Add each track once, leaving out repeats in the source
+	Schedule    string `yaml:"schedule,omitempty"`     // How often `ytx apply --watch` applies the pair, e.g. 6h
+	OnCollision string `yaml:"on_collision,omitempty"` // Collision policy, [CollisionUpdate] by default
+}
+
+// SyncPair is a source playlist kept in sync with a destination playlist.
+type SyncPair struct {
+	Name   string `yaml:"name,omitempty"`  // Identifies the pair in output and `ytx apply --only`; defaults to Source
+	Source string `yaml:"source"`          // Source playlist ID or name
+	Dest   string `yaml:"dest,omitempty"`  // Destination playlist ID; empty finds or creates one named after the source
+	Title  string `yaml:"title,omitempty"` // Name template of a created destination; see [PlaylistTemplateData]
+
+	SyncOptions `yaml:",inline"`
+}
+
+// Deduped reports whether repeats of a source track are left out
+func (p SyncPair) Deduped() bool {
+	return p.Dedupe != nil && *p.Dedupe
+}
+
+// Interval returns how often the pair is applied on a schedule, or zero when it has none
+func (p SyncPair) Interval() time.Duration {
+	interval, _ := time.ParseDuration(p.Schedule)
+	return interval
+}
+
+// SyncFile is a declarative sync setup listing playlist pairs and their options, read from a ytx.yaml with
+// [LoadSyncFile], so a library's sync setup can be kept in version control and applied again anywhere. Pairs sync
+// between Spotify and YouTube Music; two accounts of one service are transferred between with `ytx transfer run
+// --to-profile` instead.
+//
+//	defaults:
+//	  dedupe: true
+//	  schedule: 24h
+//	playlists:
+//	  - source: Road Trip
+//	  - name: focus
+//	    source: 37i9dQZF1DX0XUsuxWHRQd
+//	    dest: PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf
+//	    direction: two-way
+type SyncFile struct {
+	Defaults  SyncOptions `yaml:"defaults,omitempty"`
+	Playlists []SyncPair  `yaml:"playlists"`
+}
+
+// LoadSyncFile reads and validates a sync file, returning its pairs with the defaults applied.
+func LoadSyncFile(path string) ([]SyncPair, error) {
+	data, err := os.ReadFile(shared.ExpandPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync file: %w", err)
+	}
+
+	var file SyncFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse sync file %s: %v", shared.ErrInvalidInput, path, err)
+	}
+
+	pairs, err := file.Pairs()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", shared.ErrInvalidInput, path, err)
+	}
+	return pairs, nil
+}
+
+// Pairs validates the file and returns its pairs with unset options taken from the defaults, then the built-in
+// defaults: Spotify → YouTube Music, one-way, without dedupe or a schedule, updating colliding playlists.
+func (f *SyncFile) Pairs() ([]SyncPair, error) {
+	if len(f.Playlists) == 0 {
+		return nil, fmt.Errorf("no playlists to sync")
+	}
+
+	pairs := make([]SyncPair, len(f.Playlists))
+	names := make(map[string]bool, len(f.Playlists))
+	for i, pair := range f.Playlists {
+		if pair.Source == "" {
+			return nil, fmt.Errorf("playlist %d needs a source", i+1)
+		}
+		pair.Name = cmp.Or(pair.Name, pair.Source)
+		if names[pair.Name] {
+			return nil, fmt.Errorf("playlist %d: more than one pair is named %q; give them distinct names", i+1, pair.Name)
+		}
+		names[pair.Name] = true
+
+		pair.From = cmp.Or(pair.From, f.Defaults.From, "spotify")
+		pair.To = cmp.Or(pair.To, f.Defaults.To, "youtube")
+		pair.Direction = cmp.Or(pair.Direction, f.Defaults.Direction, OneWay)
+		pair.Schedule = cmp.Or(pair.Schedule, f.Defaults.Schedule)
+		pair.OnCollision = cmp.Or(pair.OnCollision, f.Defaults.OnCollision, CollisionUpdate)
+		if pair.Dedupe == nil {
+			pair.Dedupe = f.Defaults.Dedupe
+		}
+
+		if err := pair.validate(); err != nil {
+			return nil, fmt.Errorf("playlist %q: %w", pair.Name, err)
+		}
+		pairs[i] = pair
+	}
+	return pairs, nil
+}
+
+// validate checks the options of a pair with its defaults applied
+func (p SyncPair) validate() error {
+	services := []string{"spotify", "youtube"}
+	if !slices.Contains(services, p.From) || !slices.Contains(services, p.To) {
+		return fmt.Errorf("from and to must be spotify or youtube, got %q and %q", p.From, p.To)
+	}
+	if p.From == p.To {
+		return fmt.Errorf("from and to are both %s; pairs sync between Spotify and YouTube Music, so use `ytx transfer run --to-profile` for two accounts of one service", p.From)
+	}
+	if p.Direction != OneWay && p.Direction != TwoWay {
+		return fmt.Errorf("direction must be %s or %s, got %q", OneWay, TwoWay, p.Direction)
+	}
+	if !slices.Contains([]string{CollisionUpdate, CollisionSkip, CollisionCreate, CollisionFail}, p.OnCollision) {
+		return fmt.Errorf("on_collision must be update, skip, create, or fail, got %q", p.OnCollision)
+	}
+	if p.Schedule != "" {
+		if interval, err := time.ParseDuration(p.Schedule); err != nil || interval <= 0 {
+			return fmt.Errorf("schedule must be a positive duration such as 6h, got %q", p.Schedule)
+		}
+	}
+	return nil
+}
+
+// SyncState records the destination playlists [PlaylistEngine.Apply] created for pairs without a destination ID,
+// keyed by pair name, so later applies sync into them instead of creating the playlist again. It is kept in a file
+// next to the sync file, at [SyncStatePath].
+type SyncState struct {
+	Destinations map[string]string `yaml:"destinations"` // Created destination playlist ID by pair name
+
+	path string
+}
+
+// SyncStatePath returns where the state of the sync file at path is kept, e.g. ytx.state.yaml for ytx.yaml
+func SyncStatePath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".state" + cmp.Or(ext, ".yaml")
+}
+
+// LoadSyncState reads the state of the sync file at path, which is empty until an apply first creates a playlist.
+func LoadSyncState(path string) (*SyncState, error) {
+	state := &SyncState{Destinations: map[string]string{}, path: shared.ExpandPath(SyncStatePath(path))}
+	data, err := os.ReadFile(state.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse sync state %s: %v", shared.ErrInvalidInput, state.path, err)
+	}
+	if state.Destinations == nil {
+		state.Destinations = map[string]string{}
+	}
+	return state, nil
+}
+
+// Destination returns the destination playlist recorded for the named pair, or "" when there is none or s is nil
+func (s *SyncState) Destination(name string) string {
+	if s == nil {
+		return ""
+	}
+	return s.Destinations[name]
+}
+
+// RecordDestination records the destination playlist created for the named pair and saves the state. A nil state
+// records nothing.
+func (s *SyncState) RecordDestination(name, playlistID string) error {
+	if s == nil {
+		return nil
+	}
+	s.Destinations[name] = playlistID
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+	header := "# Playlists created by ytx apply, by pair name; remove an entry to have apply find or create it again\n"
+	if err := os.WriteFile(s.path, append([]byte(header), data...), 0o644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return nil
+}
+
+// ApplyResult reports what [PlaylistEngine.Apply] did, or would do in a dry run, for one [SyncPair].
+type ApplyResult struct {
+	Pair       SyncPair
+	Action     string                 // [ApplyCreated], [ApplyUpdated], or [ApplySkipped]
+	Source     *models.PlaylistExport // Source playlist
+	DestID     string                 // Destination playlist; empty when one would be created in a dry run
+	DestName   string                 // Name of the destination playlist, when it is created or was found by name
+	Missing    []models.Track         // Source tracks missing from the destination, added to it unless in a dry run
+	Duplicates int                    // Repeats of a source track left out because the pair is deduped
+	Transfer   *TransferRunResult     // Transfer that created the destination
+	Push       *PushResult            // Missing tracks searched for and added to the destination
+	Merge      *MergeResult           // Two-way merge; in a dry run, only its ThreeWay changes are set
+}
+
+// Apply brings pair's destination in line with its source.
+//
+// A pair without a destination ID syncs into the playlist state recorded as created for it by an earlier apply.
+// Without one, it looks for a destination playlist with the name it would create, per [PlaylistEngine.Destination].
+// When there is none, or under [CollisionCreate], the source is transferred into a new playlist, which is recorded
+// in state; otherwise the pair's collision policy applies. A nil state records nothing, so every apply of such a
+// pair may create another playlist.
+//
+// One-way pairs add the source tracks missing from the destination to it; tracks are never removed. Two-way pairs
+// are merged with [PlaylistEngine.Merge] once store holds a snapshot of either playlist. Until then they are synced
+// one way, or created, and both playlists are stored as the snapshots the next merge compares with, so store is
+// required.
+//
+// With dryRun set, nothing is created or changed and the result lists what would be.
+func (e *PlaylistEngine) Apply(ctx context.Context, store PlaylistStore, state *SyncState, pair SyncPair, dryRun bool, progress chan<- ProgressUpdate) (result *ApplyResult, err error) {
+	ctx, span := tracing.Start(ctx, "apply", tracing.String("ytx.pair", pair.Name))
+	defer func() { span.RecordError(err); span.End() }()
+
+	opts := TransferOpts{Name: pair.Title, Reverse: pair.From == "youtube", Dedupe: pair.Deduped()}
+	route := e.route(opts)
+	if route.source == nil {
+		return nil, fmt.Errorf("%w: source %s service not initialized", shared.ErrServiceUnavailable, route.sourceKey)
+	}
+	if route.dest == nil {
+		return nil, fmt.Errorf("%w: destination %s service not initialized", shared.ErrServiceUnavailable, route.destKey)
+	}
+	if pair.Direction == TwoWay && store == nil {
+		return nil, fmt.Errorf("%w: two-way sync needs the playlist store", shared.ErrServiceUnavailable)
+	}
+
+	source, err := exportPlaylist(ctx, route.source, pair.Source)
+	if err != nil {
+		return nil, err
+	}
+	result = &ApplyResult{Pair: pair, Source: source, DestID: cmp.Or(pair.Dest, state.Destination(pair.Name))}
+
+	if result.DestID == "" {
+		destination, err := e.Destination(source, opts)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := playlistNamed(ctx, route.dest, destination.Name)
+		if err != nil {
+			return nil, err
+		}
+		result.DestName = destination.Name
+
+		switch {
+		case existing == nil || pair.OnCollision == CollisionCreate:
+			result.Action = ApplyCreated
+			if dryRun {
+				result.Missing, result.Duplicates = e.applyTracks(ctx, source.Tracks, pair)
+				return result, nil
+			}
+			result.Transfer, err = e.RunWithOpts(ctx, source.Playlist.ID, opts, progress)
+			if result.Transfer != nil {
+				result.Duplicates = result.Transfer.Duplicates
+				if result.Transfer.DestPlaylist != nil {
+					result.DestID = result.Transfer.DestPlaylist.ID
+				}
+			}
+			if result.DestID == "" {
+				return result, err
+			}
+			// Recorded even when the transfer failed partway, so the next apply fills the playlist in rather than
+			// creating another
+			if recordErr := state.RecordDestination(pair.Name, result.DestID); recordErr != nil {
+				return result, errors.Join(err, recordErr)
+			}
+			if err == nil && pair.Direction == TwoWay {
+				e.storeSnapshots(ctx,
+					DiffTarget{ServiceKey: route.sourceKey, PlaylistID: source.Playlist.ID, Service: route.source},
+					DiffTarget{ServiceKey: route.destKey, PlaylistID: result.DestID, Service: route.dest})
+			}
+			return result, err
+		case pair.OnCollision == CollisionSkip:
+			result.Action, result.DestID = ApplySkipped, existing.ID
+			return result, nil
+		case pair.OnCollision == CollisionFail:
+			return result, fmt.Errorf("%w: %s already has a playlist named %q (on_collision: fail)", shared.ErrInvalidInput, route.dest.Name(), destination.Name)
+		}
+		result.DestID = existing.ID
+	}
+
+	result.Action = ApplyUpdated
+	sourceTarget := DiffTarget{ServiceKey: route.sourceKey, PlaylistID: source.Playlist.ID, Service: route.source}
+	destTarget := DiffTarget{ServiceKey: route.destKey, PlaylistID: result.DestID, Service: route.dest}
+
+	if pair.Direction == TwoWay && hasSnapshot(ctx, store, sourceTarget, destTarget) {
+		if dryRun {
+			threeWay, err := e.ThreeWayDiff(ctx, store, sourceTarget, destTarget, progress)
+			result.Merge = &MergeResult{ThreeWay: threeWay}
+			return result, err
+		}
+		result.Merge, err = e.Merge(ctx, store, sourceTarget, destTarget, progress)
+		return result, err
+	}
+
+	sourceTarget.Export = source
+	diff, err := e.DiffTargets(ctx, sourceTarget, destTarget, progress)
+	if err != nil {
+		return result, err
+	}
+	result.Missing, result.Duplicates = e.applyTracks(ctx, diff.Comparison.MissingInDest, pair)
+	if dryRun {
+		return result, nil
+	}
+
+	if len(result.Missing) > 0 {
+		if result.Push, err = e.pushAll(ctx, destTarget, result.Missing, progress); err != nil {
+			return result, err
+		}
+	}
+	if pair.Direction == TwoWay {
+		e.storeSnapshots(ctx, sourceTarget, destTarget)
+	}
+	return result, nil
+}
+
+// applyTracks returns the tracks a pair adds out of tracks, leaving out ignored tracks and, when the pair is
+// deduped, repeats, with the number of repeats left out
+func (e *PlaylistEngine) applyTracks(ctx context.Context, tracks []models.Track, pair SyncPair) ([]models.Track, int) {
+	tracks, _ = e.filterIgnored(ctx, tracks)
+	if !pair.Deduped() {
+		return tracks, 0
+	}
+	kept, repeats := dedupeTracks(tracks)
+	return kept, len(repeats)
+}
+
+// hasSnapshot reports whether store holds a snapshot of either target's playlist
+func hasSnapshot(ctx context.Context, store PlaylistStore, targets ...DiffTarget) bool {
+	for _, target := range targets {
+		if _, err := store.LoadPlaylist(ctx, target.ServiceKey, target.PlaylistID); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,300 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestLoadSyncFile(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "ytx.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write sync file: %v", err)
+		}
+		return path
+	}
+
+	t.Run("applies defaults", func(t *testing.T) {
+		pairs, err := LoadSyncFile(write(t, `
+defaults:
+  dedupe: true
+  schedule: 24h
+playlists:
+  - source: Road Trip
+  - name: focus
+    source: sp123
+    dest: PL456
+    from: youtube
+    to: spotify
+    direction: two-way
+    dedupe: false
+    schedule: 6h
+    on_collision: skip
+`))
+		if err != nil {
+			t.Fatalf("LoadSyncFile() error = %v", err)
+		}
+		if len(pairs) != 2 {
+			t.Fatalf("expected 2 pairs, got %d", len(pairs))
+		}
+
+		road := pairs[0]
+		if road.Name != "Road Trip" || road.From != "spotify" || road.To != "youtube" || road.Direction != OneWay || road.OnCollision != CollisionUpdate {
+			t.Errorf("expected the built-in defaults, got %+v", road)
+		}
+		if !road.Deduped() || road.Interval() != 24*time.Hour {
+			t.Errorf("expected the file's defaults, got dedupe %v every %v", road.Deduped(), road.Interval())
+		}
+
+		focus := pairs[1]
+		if focus.Name != "focus" || focus.Dest != "PL456" || focus.From != "youtube" || focus.Direction != TwoWay || focus.OnCollision != CollisionSkip {
+			t.Errorf("expected the pair's own options, got %+v", focus)
+		}
+		if focus.Deduped() || focus.Interval() != 6*time.Hour {
+			t.Errorf("expected the pair to override the defaults, got dedupe %v every %v", focus.Deduped(), focus.Interval())
+		}
+	})
+
+	invalid := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no playlists", "defaults:\n  dedupe: true\n", "no playlists"},
+		{"no source", "playlists:\n  - dest: PL1\n", "needs a source"},
+		{"duplicate names", "playlists:\n  - source: A\n  - source: A\n", "distinct names"},
+		{"same service", "playlists:\n  - source: A\n    to: spotify\n", "--to-profile"},
+		{"unknown service", "playlists:\n  - source: A\n    from: deezer\n", "spotify or youtube"},
+		{"unknown direction", "playlists:\n  - source: A\n    direction: sideways\n", "direction"},
+		{"unknown policy", "playlists:\n  - source: A\n    on_collision: merge\n", "on_collision"},
+		{"bad schedule", "playlists:\n  - source: A\n    schedule: daily\n", "schedule"},
+		{"unknown field", "playlists:\n  - source: A\n    mirror: true\n", "mirror"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSyncFile(write(t, tt.content))
+			if !errors.Is(err, shared.ErrInvalidInput) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an invalid input error mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := LoadSyncFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestPlaylistEngine_Apply(t *testing.T) {
+	song := func(id, title string) models.Track {
+		return models.Track{ID: id, Title: title, Artists: []string{"Band"}}
+	}
+	fixture := func() (*recordingService, *recordingService) {
+		spotify := &recordingService{mockService: &mockService{
+			name:      "Spotify",
+			playlists: []models.Playlist{{ID: "src", Name: "Road Trip"}},
+			playlistExports: map[string]*models.PlaylistExport{
+				"src": {Playlist: models.Playlist{ID: "src", Name: "Road Trip"}, Tracks: []models.Track{
+					song("sp-a", "A"), song("sp-b", "B"), song("sp-c", "C"), song("sp-c2", "C"),
+				}},
+			},
+		}}
+		youtube := &recordingService{mockService: &mockService{
+			name: "YouTube Music",
+			searchResults: map[string]*models.Track{
+				"A|Band": {ID: "yt-a", Title: "A", Artists: []string{"Band"}},
+				"B|Band": {ID: "yt-b", Title: "B", Artists: []string{"Band"}},
+				"C|Band": {ID: "yt-c", Title: "C", Artists: []string{"Band"}},
+			},
+			importResult: &models.Playlist{ID: "created", Name: "Road Trip"},
+		}}
+		return spotify, youtube
+	}
+	existing := func(youtube *recordingService) {
+		youtube.playlists = []models.Playlist{{ID: "dst", Name: "Road Trip"}}
+		youtube.playlistExports = map[string]*models.PlaylistExport{
+			"dst": {Playlist: models.Playlist{ID: "dst", Name: "Road Trip"}, Tracks: []models.Track{song("yt-a", "A")}},
+		}
+	}
+	pair := func(options SyncOptions) SyncPair {
+		file := SyncFile{Playlists: []SyncPair{{Source: "Road Trip", SyncOptions: options}}}
+		pairs, err := file.Pairs()
+		if err != nil {
+			t.Fatalf("Pairs() error = %v", err)
+		}
+		return pairs[0]
+	}
+	dedupe := true
+
+	t.Run("creates a missing destination", func(t *testing.T) {
+		spotify, youtube := fixture()
+		result, err := NewPlaylistEngine(spotify, youtube, nil).Apply(context.Background(), nil, nil, pair(SyncOptions{Dedupe: &dedupe}), false, nil)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if result.Action != ApplyCreated || result.DestID != "created" || result.DestName != "Road Trip" {
+			t.Errorf("expected the destination created, got %+v", result)
+		}
+		if result.Duplicates != 1 || youtube.imported == nil || !sameTitles(youtube.imported.Tracks, "A", "B", "C") {
+			t.Errorf("expected the repeat of C left out, got %d duplicates and %+v", result.Duplicates, youtube.imported)
+		}
+	})
+
+	t.Run("updates a colliding destination", func(t *testing.T) {
+		spotify, youtube := fixture()
+		existing(youtube)
+		result, err := NewPlaylistEngine(spotify, youtube, nil).Apply(context.Background(), nil, nil, pair(SyncOptions{Dedupe: &dedupe}), false, nil)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if result.Action != ApplyUpdated || result.DestID != "dst" || youtube.importCalled {
+			t.Errorf("expected the existing playlist updated, got %+v", result)
+		}
+		if !sameTitles(youtube.added, "B", "C") || result.Push == nil || result.Push.AddedCount != 2 || result.Duplicates != 1 {
+			t.Errorf("expected B and C added once, got %v (%d duplicates)", titles(youtube.added), result.Duplicates)
+		}
+	})
+
+	t.Run("collision policies", func(t *testing.T) {
+		spotify, youtube := fixture()
+		existing(youtube)
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+
+		result, err := engine.Apply(context.Background(), nil, nil, pair(SyncOptions{OnCollision: CollisionSkip}), false, nil)
+		if err != nil || result.Action != ApplySkipped || len(youtube.added) != 0 {
+			t.Errorf("expected the pair skipped, got %+v, %v", result, err)
+		}
+
+		if _, err := engine.Apply(context.Background(), nil, nil, pair(SyncOptions{OnCollision: CollisionFail}), false, nil); !errors.Is(err, shared.ErrInvalidInput) {
+			t.Errorf("expected a collision to fail, got %v", err)
+		}
+
+		result, err = engine.Apply(context.Background(), nil, nil, pair(SyncOptions{OnCollision: CollisionCreate}), false, nil)
+		if err != nil || result.Action != ApplyCreated || !youtube.importCalled {
+			t.Errorf("expected another playlist created, got %+v, %v", result, err)
+		}
+	})
+
+	t.Run("records a created destination", func(t *testing.T) {
+		spotify, youtube := fixture()
+		existing(youtube)
+		youtube.playlistExports["created"] = &models.PlaylistExport{Playlist: models.Playlist{ID: "created", Name: "Road Trip"}, Tracks: []models.Track{song("yt-a", "A")}}
+		syncFile := filepath.Join(t.TempDir(), "ytx.yaml")
+		state, err := LoadSyncState(syncFile)
+		if err != nil {
+			t.Fatalf("LoadSyncState() error = %v", err)
+		}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+		create := pair(SyncOptions{OnCollision: CollisionCreate})
+
+		result, err := engine.Apply(context.Background(), nil, state, create, false, nil)
+		if err != nil || result.Action != ApplyCreated || result.DestID != "created" {
+			t.Fatalf("expected the destination created, got %+v, %v", result, err)
+		}
+
+		// Later applies, here with the state read back as by the next run, sync into the recorded playlist
+		state, err = LoadSyncState(syncFile)
+		if err != nil || state.Destination("Road Trip") != "created" {
+			t.Fatalf("expected the created playlist recorded, got %+v, %v", state, err)
+		}
+		youtube.importCalled = false
+		result, err = engine.Apply(context.Background(), nil, state, create, false, nil)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if result.Action != ApplyUpdated || result.DestID != "created" || youtube.importCalled {
+			t.Errorf("expected the recorded playlist updated rather than another created, got %+v", result)
+		}
+		if !sameTitles(youtube.added, "B", "C", "C") {
+			t.Errorf("expected the missing tracks added to the recorded playlist, got %v", titles(youtube.added))
+		}
+
+		if _, err := os.Stat(filepath.Join(filepath.Dir(syncFile), "ytx.state.yaml")); err != nil {
+			t.Errorf("expected the state kept next to the sync file, got %v", err)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		spotify, youtube := fixture()
+		existing(youtube)
+		result, err := NewPlaylistEngine(spotify, youtube, nil).Apply(context.Background(), nil, nil, pair(SyncOptions{}), true, nil)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if result.Action != ApplyUpdated || !sameTitles(result.Missing, "B", "C", "C") {
+			t.Errorf("expected B and both Cs listed as missing, got %s %v", result.Action, titles(result.Missing))
+		}
+		if len(youtube.added) != 0 || result.Push != nil {
+			t.Error("expected nothing added in a dry run")
+		}
+	})
+
+	t.Run("two-way", func(t *testing.T) {
+		spotify, youtube := fixture()
+		existing(youtube)
+		store := &mockPlaylistStore{playlists: map[string]*models.PlaylistExport{}}
+		cacher := &mockPlaylistCacher{}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+		engine.SetPlaylistCacher(cacher)
+		twoWay := pair(SyncOptions{Direction: TwoWay, Dedupe: &dedupe})
+
+		if _, err := engine.Apply(context.Background(), nil, nil, twoWay, false, nil); !errors.Is(err, shared.ErrServiceUnavailable) {
+			t.Errorf("expected two-way sync to need a store, got %v", err)
+		}
+
+		// Without snapshots, the first sync is one way and stores both playlists
+		result, err := engine.Apply(context.Background(), store, nil, twoWay, false, nil)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if result.Merge != nil || !sameTitles(youtube.added, "B", "C") {
+			t.Errorf("expected a one-way sync, got %+v adding %v", result.Merge, titles(youtube.added))
+		}
+		if cacher.cached["spotify:src"] != 4 || cacher.cached["youtube:dst"] != 1 {
+			t.Errorf("expected both playlists stored as snapshots, got %v", cacher.cached)
+		}
+
+		// With a snapshot, changes are merged both ways
+		store.playlists["spotify:src"] = &models.PlaylistExport{Tracks: slices.Clone(spotify.playlistExports["src"].Tracks)}
+		store.playlists["youtube:dst"] = &models.PlaylistExport{Tracks: slices.Clone(youtube.playlistExports["dst"].Tracks)}
+		youtube.playlistExports["dst"].Tracks = append(youtube.playlistExports["dst"].Tracks, song("yt-d", "D"))
+		spotify.searchResults = map[string]*models.Track{"D|Band": {ID: "sp-d", Title: "D", Artists: []string{"Band"}}}
+
+		result, err = engine.Apply(context.Background(), store, nil, twoWay, false, nil)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if result.Merge == nil || !sameTitles(result.Merge.ThreeWay.AddedOnDest, "D") || !sameTitles(spotify.added, "D") {
+			t.Errorf("expected D merged into the source, got %+v", result.Merge)
+		}
+	})
+
+	t.Run("two-way creation stores both snapshots", func(t *testing.T) {
+		spotify, youtube := fixture()
+		// The service kept only some of the imported tracks, which is what the next merge must compare with
+		youtube.playlistExports = map[string]*models.PlaylistExport{
+			"created": {Playlist: models.Playlist{ID: "created", Name: "Road Trip"}, Tracks: []models.Track{song("yt-a", "A"), song("yt-b", "B")}},
+		}
+		store := &mockPlaylistStore{playlists: map[string]*models.PlaylistExport{}}
+		cacher := &mockPlaylistCacher{}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+		engine.SetPlaylistCacher(cacher)
+
+		result, err := engine.Apply(context.Background(), store, nil, pair(SyncOptions{Direction: TwoWay}), false, nil)
+		if err != nil || result.Action != ApplyCreated {
+			t.Fatalf("expected the destination created, got %+v, %v", result, err)
+		}
+		if cacher.cached["spotify:src"] != 4 || cacher.cached["youtube:created"] != 2 {
+			t.Errorf("expected both playlists stored as they now are, got %v", cacher.cached)
+		}
+	})
+}
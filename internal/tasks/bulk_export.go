@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/desertthunder/ytx/internal/formatter"
+	"github.com/desertthunder/ytx/internal/models"
 	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
 	"golang.org/x/time/rate"
@@ -16,17 +19,80 @@ import (
 
 // BulkExportOpts contains configuration for bulk playlist exports.
 type BulkExportOpts struct {
-	Format        string                                               // Export format: json, csv, markdown, txt
-	OutputDir     string                                               // Base output directory (default: spotify_export_{epoch})
-	NumWorkers    int                                                  // Concurrent workers (default: 5)
-	RateLimit     float64                                              // Requests per second (default: 5)
-	GetCoverImage func(ctx context.Context, id string) (string, error) // Fetcher function
+	Format        string                                                 // Export format: json, csv, markdown, txt
+	OutputDir     string                                                 // Base output directory (default: {service}_export_{epoch}, e.g. spotify_export_{epoch})
+	NumWorkers    int                                                    // Concurrent workers (default: 5)
+	RateLimit     float64                                                // Requests per second (default: 5)
+	GetCoverImage func(ctx context.Context, id string) (string, error)   // Fetcher function
+	Archive       string                                                 // Package the output directory as zip or tar.gz (default: none)
+	Covers        bool                                                   // Save cover images with json, csv, and txt exports; markdown always has them
+	EnrichTracks  func(ctx context.Context, tracks []models.Track) error // Fills in genres and audio features before writing (default: none)
+}
+
+// ExportFilter selects the playlists of a library that a bulk export includes. The zero value selects every one.
+type ExportFilter struct {
+	Match     string // Glob matched against playlist names, ignoring case, e.g. "Workout*"
+	MinTracks int    // Fewest tracks a playlist may have
+	MaxTracks int    // Most tracks a playlist may have (0: no limit)
+	Changed   bool   // Only playlists that differ from their cached snapshot, or have none
+}
+
+// Validate reports a malformed Match pattern or track counts that select nothing.
+func (f ExportFilter) Validate() error {
+	if _, err := path.Match(f.Match, ""); err != nil {
+		return fmt.Errorf("%w: match pattern '%s': %v", shared.ErrInvalidArgument, f.Match, err)
+	}
+	if f.MinTracks < 0 || f.MaxTracks < 0 {
+		return fmt.Errorf("%w: track counts cannot be negative", shared.ErrInvalidArgument)
+	}
+	if f.MaxTracks > 0 && f.MinTracks > f.MaxTracks {
+		return fmt.Errorf("%w: minimum of %d tracks is above the maximum of %d", shared.ErrInvalidArgument, f.MinTracks, f.MaxTracks)
+	}
+	return nil
+}
+
+// FilterPlaylists returns the playlists of service that filter selects, in their original order.
+//
+// A playlist counts as changed when there is no snapshot of it in store, or when its name, description, or track
+// count differs from the snapshot's; an edit that keeps all three, such as swapping one track for another, is not
+// seen. Snapshots are stored by transfers, diffs, and bulk exports with a playlist cacher. store is only used, and
+// required, for Changed.
+func FilterPlaylists(ctx context.Context, store PlaylistStore, service string, playlists []models.Playlist, filter ExportFilter) ([]models.Playlist, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter.Changed && store == nil {
+		return nil, fmt.Errorf("%w: playlist store not initialized", shared.ErrServiceUnavailable)
+	}
+
+	pattern := strings.ToLower(filter.Match)
+	selected := make([]models.Playlist, 0, len(playlists))
+	for _, pl := range playlists {
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, strings.ToLower(pl.Name)); !ok {
+				continue
+			}
+		}
+		if pl.TrackCount < filter.MinTracks || (filter.MaxTracks > 0 && pl.TrackCount > filter.MaxTracks) {
+			continue
+		}
+		if filter.Changed {
+			snapshot, err := store.LoadPlaylist(ctx, service, pl.ID)
+			if err == nil && snapshot.Playlist.Name == pl.Name && snapshot.Playlist.Description == pl.Description &&
+				snapshot.Playlist.TrackCount == pl.TrackCount {
+				continue
+			}
+		}
+		selected = append(selected, pl)
+	}
+	return selected, nil
 }
 
 // BulkExport exports multiple playlists concurrently with rate limiting and progress tracking.
 //
 // This method implements a worker pool pattern to efficiently export multiple playlists.
 // It respects API rate limits, handles partial failures gracefully, and generates a manifest file summarizing the export results.
+// Progress updates for finished playlists carry their [PlaylistExportResult] in Data.
 func (e *PlaylistEngine) BulkExport(
 	ctx context.Context,
 	prog chan<- ProgressUpdate,
@@ -34,12 +100,16 @@ func (e *PlaylistEngine) BulkExport(
 	ids []string,
 	opts BulkExportOpts,
 ) (*BulkExportResult, error) {
+	prog, flushProgress := e.dispatchProgress(prog)
+	defer flushProgress()
+
 	if srv == nil {
 		return nil, fmt.Errorf("%w: service not initialized", shared.ErrServiceUnavailable)
 	}
 
 	if opts.OutputDir == "" {
-		opts.OutputDir = fmt.Sprintf("spotify_export_%d", time.Now().Unix())
+		service := strings.ReplaceAll(strings.ToLower(srv.Name()), " ", "_")
+		opts.OutputDir = fmt.Sprintf("%s_export_%d", service, time.Now().Unix())
 	}
 	if opts.NumWorkers <= 0 {
 		opts.NumWorkers = 5
@@ -62,6 +132,7 @@ func (e *PlaylistEngine) BulkExport(
 	}
 
 	limiter := rate.NewLimiter(rate.Limit(opts.RateLimit), 1)
+	pause := &rateLimitPause{}
 
 	jobs := make(chan PlaylistExportJob, len(ids))
 	results := make(chan PlaylistExportResult, len(ids))
@@ -69,11 +140,11 @@ func (e *PlaylistEngine) BulkExport(
 	var wg sync.WaitGroup
 	for i := 0; i < opts.NumWorkers; i++ {
 		wg.Add(1)
-		go e.exportWorker(ctx, &wg, jobs, results, opts)
+		go e.exportWorker(ctx, &wg, pause, jobs, results, opts, e.serviceKey(srv))
 	}
 
 	go func() {
-		e.sendProgress(prog, fetchingSourceUpdate(1, len(ids)))
+		e.sendProgress(prog, fetchingSourceUpdate(1, len(ids), srv.Name()))
 		for i, playlistID := range ids {
 			select {
 			case <-ctx.Done():
@@ -87,7 +158,12 @@ func (e *PlaylistEngine) BulkExport(
 				return
 			}
 
-			export, err := srv.ExportPlaylist(ctx, playlistID)
+			// A rate-limited playlist is fetched again once the pause passes rather than reported failed
+			var export *models.PlaylistExport
+			err := e.withRateLimit(ctx, pause, prog, srv.Name(), func() (err error) {
+				export, err = srv.ExportPlaylist(ctx, playlistID)
+				return err
+			})
 			if err != nil {
 				results <- PlaylistExportResult{
 					PlaylistID:   playlistID,
@@ -120,28 +196,26 @@ func (e *PlaylistEngine) BulkExport(
 
 		if res.Success {
 			result.SuccessfulExports++
-			e.sendProgress(prog, exportCompletedUpdate(
-				completed,
-				len(ids),
-				res.PlaylistName,
-				len(res.Files),
-			))
+			e.sendProgress(prog, exportCompletedUpdate(completed, len(ids), res))
 		} else {
 			result.FailedExports++
-			e.sendProgress(prog, exportFailedUpdate(
-				completed,
-				len(ids),
-				res.PlaylistName,
-				res.Error,
-			))
+			e.sendProgress(prog, exportFailedUpdate(completed, len(ids), res))
 		}
 	}
 
-	manifestPath := filepath.Join(opts.OutputDir, "export_manifest.json")
+	manifestPath := filepath.Join(opts.OutputDir, formatter.ManifestFileName)
 	if err := formatter.WriteBulkExportManifest(result, opts.Format, manifestPath); err != nil {
 		return result, fmt.Errorf("export completed but failed to write manifest: %w", err)
 	}
 	result.ManifestPath = manifestPath
+
+	if opts.Archive != "" {
+		archivePath, err := formatter.ArchiveDirectory(opts.OutputDir, opts.Archive)
+		if err != nil {
+			return result, fmt.Errorf("export completed but failed to archive it: %w", err)
+		}
+		result.ArchivePath = archivePath
+	}
 	return result, nil
 }
 
@@ -149,20 +223,32 @@ func (e *PlaylistEngine) BulkExport(
 func (e *PlaylistEngine) exportWorker(
 	ctx context.Context,
 	wg *sync.WaitGroup,
+	pause *rateLimitPause,
 	jobs <-chan PlaylistExportJob,
 	results chan<- PlaylistExportResult,
 	opts BulkExportOpts,
+	serviceKey string,
 ) {
 	defer wg.Done()
 
 	for job := range jobs {
-		select {
-		case <-ctx.Done():
+		// Cover images are requested from the same service, so workers sit out its rate limit too
+		if err := pause.wait(ctx); err != nil {
 			return
-		default:
+		}
+
+		// Enrichment keeps what it found before failing, so the playlist is still exported
+		if opts.EnrichTracks != nil {
+			if err := opts.EnrichTracks(ctx, job.Export.Tracks); err != nil {
+				e.logger.Warn("tracks not fully enriched", "playlist", job.PlaylistID, "error", err)
+			}
 		}
 
 		res := e.exportSinglePlaylist(ctx, job, opts)
+		if res.Success {
+			// The exported playlist becomes the snapshot that ExportFilter.Changed compares with next time
+			e.cachePlaylist(ctx, serviceKey, job.Export.Playlist, job.Export.Tracks)
+		}
 		results <- res
 	}
 }
@@ -180,15 +266,30 @@ func (e *PlaylistEngine) exportSinglePlaylist(
 		Files:        []string{},
 	}
 
+	// The cover is saved first so the export can record it
+	export := j.Export
+	if opts.Covers && opts.Format != "markdown" && opts.GetCoverImage != nil {
+		coverPath := filepath.Join(opts.OutputDir, fmt.Sprintf("%s_cover.jpg", j.Export.Playlist.ID))
+		if err := saveCover(ctx, opts.GetCoverImage, j.PlaylistID, coverPath); err != nil {
+			e.logger.Warn("cover image not saved", "playlist", j.PlaylistID, "error", err)
+		} else {
+			withCover := *j.Export
+			withCover.CoverImage = filepath.Base(coverPath)
+			export = &withCover
+			result.CoverImage = coverPath
+			result.Files = append(result.Files, coverPath)
+		}
+	}
+
 	switch opts.Format {
 	case "csv":
 		baseFilepath := filepath.Join(opts.OutputDir, j.Export.Playlist.ID)
-		csvRes, err := formatter.WriteCSVExport(j.Export, baseFilepath)
+		csvRes, err := formatter.WriteCSVExport(export, baseFilepath)
 		if err != nil {
 			result.Error = fmt.Errorf("CSV export failed: %w", err)
 			return result
 		}
-		result.Files = []string{csvRes.TracksFile, csvRes.MetadataFile}
+		result.Files = append(result.Files, csvRes.TracksFile, csvRes.MetadataFile)
 		result.Success = true
 
 	case "markdown":
@@ -207,22 +308,23 @@ func (e *PlaylistEngine) exportSinglePlaylist(
 			return result
 		}
 		result.Files = mdRes.Files
+		result.CoverImage = mdRes.CoverImage
 		result.Success = true
 
 	case "txt":
 		txtPath := filepath.Join(opts.OutputDir, fmt.Sprintf("%s_tracks.txt", j.Export.Playlist.ID))
-		filepath, err := formatter.WriteTextExport(j.Export, txtPath)
+		filepath, err := formatter.WriteTextExport(export, txtPath)
 		if err != nil {
 			result.Error = fmt.Errorf("text export failed: %w", err)
 			return result
 		}
-		result.Files = []string{filepath}
+		result.Files = append(result.Files, filepath)
 		result.Success = true
 	case "json":
 		fallthrough
 	default:
 		jsonPath := filepath.Join(opts.OutputDir, fmt.Sprintf("%s.json", j.Export.Playlist.ID))
-		data, err := shared.MarshalJSON(j.Export, true)
+		data, err := shared.MarshalJSON(export, true)
 		if err != nil {
 			result.Error = fmt.Errorf("JSON marshal failed: %w", err)
 			return result
@@ -231,8 +333,17 @@ func (e *PlaylistEngine) exportSinglePlaylist(
 			result.Error = fmt.Errorf("JSON write failed: %w", err)
 			return result
 		}
-		result.Files = []string{jsonPath}
+		result.Files = append(result.Files, jsonPath)
 		result.Success = true
 	}
 	return result
 }
+
+// saveCover saves the cover image of a playlist to path
+func saveCover(ctx context.Context, getCoverImage func(context.Context, string) (string, error), playlistID, path string) error {
+	imageURL, err := getCoverImage(ctx, playlistID)
+	if err != nil {
+		return err
+	}
+	return formatter.WriteCoverImage(imageURL, path)
+}
@@ -3,15 +3,20 @@ package tasks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/desertthunder/ytx/internal/formatter"
 	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
 )
 
@@ -114,8 +119,8 @@ func TestBulkExport_SuccessfulExport(t *testing.T) {
 						TrackCount:  2,
 					},
 					Tracks: []models.Track{
-						{ID: fmt.Sprintf("track%d-1", i+1), Title: "Song 1", Artist: "Artist 1"},
-						{ID: fmt.Sprintf("track%d-2", i+1), Title: "Song 2", Artist: "Artist 2"},
+						{ID: fmt.Sprintf("track%d-1", i+1), Title: "Song 1", Artists: []string{"Artist 1"}},
+						{ID: fmt.Sprintf("track%d-2", i+1), Title: "Song 2", Artists: []string{"Artist 2"}},
 					},
 				}
 			}
@@ -207,11 +212,11 @@ func TestBulkExport_PartialFailures(t *testing.T) {
 		playlistExports: map[string]*models.PlaylistExport{
 			"playlist1": {
 				Playlist: models.Playlist{ID: "playlist1", Name: "Playlist 1"},
-				Tracks:   []models.Track{{ID: "t1", Title: "Song 1", Artist: "Artist 1"}},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song 1", Artists: []string{"Artist 1"}}},
 			},
 			"playlist3": {
 				Playlist: models.Playlist{ID: "playlist3", Name: "Playlist 3"},
-				Tracks:   []models.Track{{ID: "t3", Title: "Song 3", Artist: "Artist 3"}},
+				Tracks:   []models.Track{{ID: "t3", Title: "Song 3", Artists: []string{"Artist 3"}}},
 			},
 		},
 	}
@@ -303,11 +308,11 @@ func TestBulkExport_ContextCancellation(t *testing.T) {
 		playlistExports: map[string]*models.PlaylistExport{
 			"playlist1": {
 				Playlist: models.Playlist{ID: "playlist1", Name: "Playlist 1"},
-				Tracks:   []models.Track{{ID: "t1", Title: "Song 1", Artist: "Artist 1"}},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song 1", Artists: []string{"Artist 1"}}},
 			},
 			"playlist2": {
 				Playlist: models.Playlist{ID: "playlist2", Name: "Playlist 2"},
-				Tracks:   []models.Track{{ID: "t2", Title: "Song 2", Artist: "Artist 2"}},
+				Tracks:   []models.Track{{ID: "t2", Title: "Song 2", Artists: []string{"Artist 2"}}},
 			},
 		},
 	}
@@ -361,7 +366,7 @@ func TestBulkExport_DefaultOptions(t *testing.T) {
 		playlistExports: map[string]*models.PlaylistExport{
 			"playlist1": {
 				Playlist: models.Playlist{ID: "playlist1", Name: "Playlist 1"},
-				Tracks:   []models.Track{{ID: "t1", Title: "Song 1", Artist: "Artist 1"}},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song 1", Artists: []string{"Artist 1"}}},
 			},
 		},
 	}
@@ -457,7 +462,7 @@ func TestBulkExport_RateLimiting(t *testing.T) {
 		playlistIDs[i] = id
 		playlistExports[id] = &models.PlaylistExport{
 			Playlist: models.Playlist{ID: id, Name: fmt.Sprintf("Playlist %d", i+1)},
-			Tracks:   []models.Track{{ID: "t1", Title: "Song", Artist: "Artist"}},
+			Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
 		}
 	}
 
@@ -504,6 +509,97 @@ func TestBulkExport_RateLimiting(t *testing.T) {
 	}
 }
 
+// throttledService answers the first exports and searches with a 429 response
+type throttledService struct {
+	*mockService
+	mu       sync.Mutex
+	throttle int
+	calls    int
+}
+
+func (s *throttledService) throttled() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.throttle > 0 {
+		s.throttle--
+		return fmt.Errorf("failed: %w", &services.SpotifyAPIError{StatusCode: 429, RetryAfter: 20 * time.Millisecond})
+	}
+	return nil
+}
+
+func (s *throttledService) ExportPlaylist(ctx context.Context, playlistID string) (*models.PlaylistExport, error) {
+	if err := s.throttled(); err != nil {
+		return nil, err
+	}
+	return s.mockService.ExportPlaylist(ctx, playlistID)
+}
+
+func (s *throttledService) SearchTrack(ctx context.Context, title, artist string) (*models.Track, error) {
+	if err := s.throttled(); err != nil {
+		return nil, err
+	}
+	return s.mockService.SearchTrack(ctx, title, artist)
+}
+
+func TestBulkExport_RateLimited(t *testing.T) {
+	svc := &throttledService{
+		mockService: &mockService{
+			name: "Spotify",
+			playlistExports: map[string]*models.PlaylistExport{
+				"p1": {Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"}},
+				"p2": {Playlist: models.Playlist{ID: "p2", Name: "Playlist 2"}},
+			},
+		},
+		throttle: 2,
+	}
+
+	engine := NewPlaylistEngine(nil, nil, nil)
+	progressCh := make(chan ProgressUpdate, 100)
+	start := time.Now()
+	result, err := engine.BulkExport(context.Background(), progressCh, svc, []string{"p1", "p2"}, BulkExportOpts{
+		Format:    "json",
+		OutputDir: t.TempDir(),
+		RateLimit: 100,
+	})
+	close(progressCh)
+
+	if err != nil {
+		t.Fatalf("BulkExport() error = %v", err)
+	}
+	if result.SuccessfulExports != 2 || result.FailedExports != 0 {
+		t.Errorf("expected rate-limited playlists retried rather than failed, got %d/%d", result.SuccessfulExports, result.FailedExports)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected a pause for each 429, finished in %s", elapsed)
+	}
+
+	var pauses []time.Duration
+	for update := range progressCh {
+		if update.Phase == RateLimited {
+			pauses = append(pauses, update.Data.(time.Duration))
+		}
+	}
+	if len(pauses) != 2 || pauses[0] != 20*time.Millisecond {
+		t.Errorf("expected two rate limit updates with the advised pause, got %v", pauses)
+	}
+
+	t.Run("gives up after repeated 429s", func(t *testing.T) {
+		svc.throttle = rateLimitRetries + 1
+		result, err := engine.BulkExport(context.Background(), nil, svc, []string{"p1"}, BulkExportOpts{
+			Format:    "json",
+			OutputDir: t.TempDir(),
+			RateLimit: 100,
+		})
+		if err != nil {
+			t.Fatalf("BulkExport() error = %v", err)
+		}
+		if result.FailedExports != 1 || !errors.Is(result.Results[0].Error, shared.ErrRateLimited) {
+			t.Errorf("expected the playlist to fail as rate limited, got %+v", result.Results)
+		}
+	})
+}
+
 func TestBulkExport_ProgressUpdates(t *testing.T) {
 	tempDir := t.TempDir()
 	mockSvc := &mockService{
@@ -511,11 +607,11 @@ func TestBulkExport_ProgressUpdates(t *testing.T) {
 		playlistExports: map[string]*models.PlaylistExport{
 			"p1": {
 				Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"},
-				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artist: "Artist"}},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
 			},
 			"p2": {
 				Playlist: models.Playlist{ID: "p2", Name: "Playlist 2"},
-				Tracks:   []models.Track{{ID: "t2", Title: "Song", Artist: "Artist"}},
+				Tracks:   []models.Track{{ID: "t2", Title: "Song", Artists: []string{"Artist"}}},
 			},
 		},
 	}
@@ -551,12 +647,19 @@ func TestBulkExport_ProgressUpdates(t *testing.T) {
 		t.Error("expected progress updates to be sent")
 	}
 	phases := make(map[Phase]bool)
+	finished := 0
 	for _, update := range progressUpdates {
 		phases[update.Phase] = true
+		if res, ok := update.Data.(PlaylistExportResult); ok && res.Success {
+			finished++
+		}
 	}
 	if !phases[FetchSource] {
 		t.Error("expected FetchSource phase in progress updates")
 	}
+	if finished != 2 {
+		t.Errorf("progress updates carried %d successful results, want 2", finished)
+	}
 }
 
 func TestBulkExport_MarkdownWithCoverImage(t *testing.T) {
@@ -566,7 +669,7 @@ func TestBulkExport_MarkdownWithCoverImage(t *testing.T) {
 		playlistExports: map[string]*models.PlaylistExport{
 			"p1": {
 				Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"},
-				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artist: "Artist"}},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
 			},
 		},
 	}
@@ -607,6 +710,80 @@ func TestBulkExport_MarkdownWithCoverImage(t *testing.T) {
 	}
 }
 
+func TestBulkExport_Covers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("jpeg"))
+	}))
+	defer server.Close()
+
+	mockSvc := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"p1": {
+				Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
+			},
+			"p2": {
+				Playlist: models.Playlist{ID: "p2", Name: "Playlist 2"},
+				Tracks:   []models.Track{{ID: "t2", Title: "Song", Artists: []string{"Artist"}}},
+			},
+		},
+	}
+	getCoverImage := func(ctx context.Context, id string) (string, error) {
+		if id == "p2" {
+			return "", fmt.Errorf("no image available")
+		}
+		return server.URL + "/cover.jpg", nil
+	}
+
+	for _, format := range []string{"json", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			tempDir := t.TempDir()
+			engine := NewPlaylistEngine(nil, nil, nil)
+			result, err := engine.BulkExport(context.Background(), nil, mockSvc, []string{"p1", "p2"}, BulkExportOpts{
+				Format:        format,
+				OutputDir:     tempDir,
+				GetCoverImage: getCoverImage,
+				Covers:        true,
+			})
+			if err != nil {
+				t.Fatalf("BulkExport() error = %v", err)
+			}
+			if result.SuccessfulExports != 2 {
+				t.Fatalf("a missing cover should not fail the export, got %d successful", result.SuccessfulExports)
+			}
+
+			coverPath := filepath.Join(tempDir, "p1_cover.jpg")
+			if data, err := os.ReadFile(coverPath); err != nil || string(data) != "jpeg" {
+				t.Fatalf("cover image not saved: %v", err)
+			}
+
+			metadataPath := filepath.Join(tempDir, "p1.json")
+			if format == "csv" {
+				metadataPath = filepath.Join(tempDir, "p1_metadata.json")
+			}
+			metadata, err := os.ReadFile(metadataPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(metadata), `"CoverImage": "p1_cover.jpg"`) {
+				t.Errorf("expected the cover recorded in %s, got %s", metadataPath, metadata)
+			}
+
+			manifest, err := os.ReadFile(result.ManifestPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(manifest), `"cover_image": "`+coverPath) {
+				t.Errorf("expected the cover in the manifest, got %s", manifest)
+			}
+			if strings.Count(string(manifest), "cover_image") != 1 {
+				t.Errorf("expected only p1 to have a cover in the manifest, got %s", manifest)
+			}
+		})
+	}
+}
+
 func TestBulkExport_OutputDirectoryCreation(t *testing.T) {
 	// Create a temp dir but specify a subdirectory that doesn't exist
 	baseDir := t.TempDir()
@@ -617,7 +794,7 @@ func TestBulkExport_OutputDirectoryCreation(t *testing.T) {
 		playlistExports: map[string]*models.PlaylistExport{
 			"p1": {
 				Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"},
-				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artist: "Artist"}},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
 			},
 		},
 	}
@@ -649,13 +826,145 @@ func TestBulkExport_OutputDirectoryCreation(t *testing.T) {
 	}
 }
 
+func TestBulkExport_Archive(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "backup")
+	mockSvc := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"p1": {
+				Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
+			},
+		},
+	}
+
+	engine := NewPlaylistEngine(nil, nil, nil)
+	progressCh := make(chan ProgressUpdate, 100)
+	go func() {
+		for range progressCh {
+			// Drain progress channel
+		}
+	}()
+
+	opts := BulkExportOpts{
+		Format:    "csv",
+		OutputDir: outputDir,
+		Archive:   formatter.ArchiveTarGz,
+	}
+
+	result, err := engine.BulkExport(context.Background(), progressCh, mockSvc, []string{"p1"}, opts)
+	close(progressCh)
+
+	if err != nil {
+		t.Fatalf("BulkExport() error = %v", err)
+	}
+	if want := outputDir + ".tar.gz"; result.ArchivePath != want {
+		t.Fatalf("ArchivePath = %s, want %s", result.ArchivePath, want)
+	}
+	if info, err := os.Stat(result.ArchivePath); err != nil || info.Size() == 0 {
+		t.Errorf("archive was not written: %v", err)
+	}
+}
+
+func TestBulkExport_CachesSnapshots(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"p1": {
+				Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
+			},
+		},
+	}
+
+	engine := NewPlaylistEngine(spotify, nil, nil)
+	cacher := &mockPlaylistCacher{}
+	engine.SetPlaylistCacher(cacher)
+
+	_, err := engine.BulkExport(context.Background(), nil, spotify, []string{"p1", "missing"}, BulkExportOpts{
+		Format:    "json",
+		OutputDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("BulkExport() error = %v", err)
+	}
+	if len(cacher.cached) != 1 || cacher.cached["spotify:p1"] != 1 {
+		t.Errorf("expected only the exported playlist cached as a spotify snapshot, got %v", cacher.cached)
+	}
+}
+
+func TestFilterPlaylists(t *testing.T) {
+	playlists := []models.Playlist{
+		{ID: "p1", Name: "Workout Mix", TrackCount: 40},
+		{ID: "p2", Name: "workout chill", TrackCount: 5},
+		{ID: "p3", Name: "Road Trip", TrackCount: 120},
+	}
+	ids := func(selected []models.Playlist) string {
+		names := make([]string, len(selected))
+		for i, pl := range selected {
+			names[i] = pl.ID
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		name   string
+		filter ExportFilter
+		want   string
+	}{
+		{name: "no filter", want: "p1,p2,p3"},
+		{name: "name glob ignores case", filter: ExportFilter{Match: "Workout*"}, want: "p1,p2"},
+		{name: "minimum tracks", filter: ExportFilter{MinTracks: 40}, want: "p1,p3"},
+		{name: "maximum tracks", filter: ExportFilter{MaxTracks: 40}, want: "p1,p2"},
+		{name: "combined", filter: ExportFilter{Match: "*o*", MinTracks: 10, MaxTracks: 100}, want: "p1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := FilterPlaylists(context.Background(), nil, "spotify", playlists, tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := ids(selected); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("changed since snapshot", func(t *testing.T) {
+		store := &mockPlaylistStore{playlists: map[string]*models.PlaylistExport{
+			"spotify:p1": {Playlist: models.Playlist{ID: "p1", Name: "Workout Mix", TrackCount: 40}},
+			"spotify:p2": {Playlist: models.Playlist{ID: "p2", Name: "workout chill", TrackCount: 4}},
+			"youtube:p3": {Playlist: models.Playlist{ID: "p3", Name: "Road Trip", TrackCount: 120}},
+		}}
+		selected, err := FilterPlaylists(context.Background(), store, "spotify", playlists, ExportFilter{Changed: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// p2 gained a track; p3 has no spotify snapshot
+		if got := ids(selected); got != "p2,p3" {
+			t.Errorf("expected p2,p3, got %s", got)
+		}
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, filter := range []ExportFilter{{Match: "[a"}, {MinTracks: -1}, {MinTracks: 10, MaxTracks: 5}} {
+			if _, err := FilterPlaylists(context.Background(), nil, "spotify", playlists, filter); !errors.Is(err, shared.ErrInvalidArgument) {
+				t.Errorf("%+v: expected ErrInvalidArgument, got %v", filter, err)
+			}
+		}
+		if _, err := FilterPlaylists(context.Background(), nil, "spotify", playlists, ExportFilter{Changed: true}); !errors.Is(err, shared.ErrServiceUnavailable) {
+			t.Errorf("expected ErrServiceUnavailable without a store, got %v", err)
+		}
+	})
+}
+
 func TestBulkExport_InvalidOutputDirectory(t *testing.T) {
 	mockSvc := &mockService{
 		name: "Spotify",
 		playlistExports: map[string]*models.PlaylistExport{
 			"p1": {
 				Playlist: models.Playlist{ID: "p1", Name: "Playlist 1"},
-				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artist: "Artist"}},
+				Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
 			},
 		},
 	}
@@ -688,8 +997,8 @@ func TestExportSinglePlaylist_AllFormats(t *testing.T) {
 			TrackCount:  2,
 		},
 		Tracks: []models.Track{
-			{ID: "t1", Title: "Song 1", Artist: "Artist 1", Album: "Album 1", Duration: 180},
-			{ID: "t2", Title: "Song 2", Artist: "Artist 2", Album: "Album 2", Duration: 240},
+			{ID: "t1", Title: "Song 1", Artists: []string{"Artist 1"}, Album: "Album 1", Duration: 180},
+			{ID: "t2", Title: "Song 2", Artists: []string{"Artist 2"}, Album: "Album 2", Duration: 240},
 		},
 	}
 	job := PlaylistExportJob{
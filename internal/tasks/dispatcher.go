@@ -0,0 +1,184 @@
+package tasks
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// progressBufferSize is the number of updates queued for a slow consumer before superseded ones are evicted
+	progressBufferSize = 256
+	// progressFlushTimeout bounds how long a finished operation waits for its consumer to read the queued updates
+	progressFlushTimeout = time.Second
+)
+
+// progressDispatcher forwards progress updates to a consumer's channel from its own goroutine, so operations never
+// wait on a slow consumer and its updates are queued rather than dropped.
+//
+// Consecutive plain updates (without Data) of the same phase are coalesced to the latest. When the queue is full,
+// updates followed by a later update of the same phase are evicted, so the consumer always ends up with the latest
+// update of every phase. Updates carrying Data are never coalesced or evicted.
+type progressDispatcher struct {
+	in    chan ProgressUpdate
+	out   chan<- ProgressUpdate
+	queue progressRing
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// dispatchProgress starts a dispatcher for progress and returns the channel operations send on in its place, with a
+// function that delivers what is still queued and must be called before the operation returns, since the caller may
+// close progress then. A consumer that stops reading is waited for at most [progressFlushTimeout].
+func (e *PlaylistEngine) dispatchProgress(progress chan<- ProgressUpdate) (chan<- ProgressUpdate, func()) {
+	if progress == nil {
+		return nil, func() {}
+	}
+
+	d := &progressDispatcher{
+		in:    make(chan ProgressUpdate, progressBufferSize),
+		out:   progress,
+		queue: progressRing{buf: make([]ProgressUpdate, progressBufferSize)},
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go d.run()
+
+	return d.in, func() {
+		close(d.stop)
+		<-d.done
+	}
+}
+
+func (d *progressDispatcher) run() {
+	defer close(d.done)
+	for {
+		var out chan<- ProgressUpdate
+		var next ProgressUpdate
+		if d.queue.n > 0 {
+			out, next = d.out, d.queue.front()
+		}
+
+		// With nothing left to evict, senders are held back until the consumer catches up
+		in := d.in
+		if d.queue.full() && !d.queue.evictSuperseded() {
+			in = nil
+		}
+
+		select {
+		case update := <-in:
+			d.enqueue(update)
+		case out <- next:
+			d.queue.pop()
+		case <-d.stop:
+			d.flush()
+			return
+		}
+	}
+}
+
+func (d *progressDispatcher) enqueue(update ProgressUpdate) {
+	if !d.queue.coalesce(update) {
+		d.queue.push(update)
+	}
+}
+
+// flush delivers every queued update, including those still in the input channel, until the flush timeout
+func (d *progressDispatcher) flush() {
+	for len(d.in) > 0 {
+		d.enqueue(<-d.in)
+	}
+
+	timer := time.NewTimer(progressFlushTimeout)
+	defer timer.Stop()
+	for d.queue.n > 0 {
+		select {
+		case d.out <- d.queue.front():
+			d.queue.pop()
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// progressRing is a FIFO ring buffer of progress updates, which grows rather than overwrite an update
+type progressRing struct {
+	buf  []ProgressUpdate
+	head int
+	n    int
+}
+
+func (r *progressRing) full() bool {
+	return r.n == len(r.buf)
+}
+
+// at returns the ith queued update, oldest first
+func (r *progressRing) at(i int) *ProgressUpdate {
+	return &r.buf[(r.head+i)%len(r.buf)]
+}
+
+func (r *progressRing) front() ProgressUpdate {
+	return *r.at(0)
+}
+
+func (r *progressRing) pop() {
+	*r.at(0) = ProgressUpdate{}
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+}
+
+func (r *progressRing) push(update ProgressUpdate) {
+	if r.full() {
+		r.reset(r.items(), max(2*len(r.buf), progressBufferSize))
+	}
+	*r.at(r.n) = update
+	r.n++
+}
+
+// coalesce replaces the newest queued update with update when both are plain updates of the same phase
+func (r *progressRing) coalesce(update ProgressUpdate) bool {
+	if r.n == 0 || update.Data != nil {
+		return false
+	}
+	last := r.at(r.n - 1)
+	if last.Data != nil || last.Phase != update.Phase {
+		return false
+	}
+	*last = update
+	return true
+}
+
+// evictSuperseded removes the plain updates followed by a later update of the same phase, reporting whether any were
+func (r *progressRing) evictSuperseded() bool {
+	seen := make(map[Phase]bool)
+	kept := make([]ProgressUpdate, 0, r.n)
+	for i := r.n - 1; i >= 0; i-- {
+		update := *r.at(i)
+		if update.Data == nil && seen[update.Phase] {
+			continue
+		}
+		seen[update.Phase] = true
+		kept = append(kept, update)
+	}
+	if len(kept) == r.n {
+		return false
+	}
+
+	slices.Reverse(kept)
+	r.reset(kept, len(r.buf))
+	return true
+}
+
+// items returns the queued updates, oldest first
+func (r *progressRing) items() []ProgressUpdate {
+	items := make([]ProgressUpdate, r.n)
+	for i := range items {
+		items[i] = *r.at(i)
+	}
+	return items
+}
+
+func (r *progressRing) reset(items []ProgressUpdate, size int) {
+	r.buf = make([]ProgressUpdate, size)
+	copy(r.buf, items)
+	r.head, r.n = 0, len(items)
+}
@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"testing"
+)
+
+func TestDispatchProgress(t *testing.T) {
+	engine := NewPlaylistEngine(nil, nil, nil)
+
+	t.Run("slow consumer receives every data update and the final state", func(t *testing.T) {
+		progressCh := make(chan ProgressUpdate)
+		progress, flush := engine.dispatchProgress(progressCh)
+
+		// Nothing is read until every update is sent, far more than the queue holds
+		const total = 10 * progressBufferSize
+		for i := 1; i <= total; i++ {
+			progress <- ProgressUpdate{Phase: SearchTracks, Step: i, Total: total}
+			if i%100 == 0 {
+				progress <- ProgressUpdate{Phase: CreatePlaylist, Step: i / 100, Data: i}
+			}
+		}
+
+		var received []ProgressUpdate
+		done := make(chan bool)
+		go func() {
+			for update := range progressCh {
+				received = append(received, update)
+			}
+			done <- true
+		}()
+		flush()
+		close(progressCh)
+		<-done
+
+		var data []int
+		lastStep := 0
+		for _, update := range received {
+			switch update.Phase {
+			case CreatePlaylist:
+				data = append(data, update.Data.(int))
+			case SearchTracks:
+				if update.Step <= lastStep {
+					t.Fatalf("updates out of order: step %d after %d", update.Step, lastStep)
+				}
+				lastStep = update.Step
+			}
+		}
+		if len(data) != total/100 || data[0] != 100 || data[len(data)-1] != total/100*100 {
+			t.Errorf("expected all %d data updates in order, got %v", total/100, data)
+		}
+		if lastStep != total {
+			t.Errorf("expected the final search update, got step %d", lastStep)
+		}
+		if len(received) >= total {
+			t.Errorf("expected plain updates to be coalesced, got %d updates", len(received))
+		}
+	})
+
+	t.Run("nil channel", func(t *testing.T) {
+		progress, flush := engine.dispatchProgress(nil)
+		engine.sendProgress(progress, ProgressUpdate{Phase: Compare})
+		flush()
+		if progress != nil {
+			t.Error("expected no dispatcher for a nil channel")
+		}
+	})
+}
+
+func TestProgressRing(t *testing.T) {
+	ring := progressRing{buf: make([]ProgressUpdate, 4)}
+	ring.push(ProgressUpdate{Phase: FetchSource, Step: 1})
+	ring.push(ProgressUpdate{Phase: SearchTracks, Step: 1})
+	ring.push(ProgressUpdate{Phase: SearchTracks, Step: 2, Data: "match"})
+	ring.push(ProgressUpdate{Phase: FetchSource, Step: 2})
+
+	if !ring.coalesce(ProgressUpdate{Phase: FetchSource, Step: 3}) || ring.coalesce(ProgressUpdate{Phase: SearchTracks, Step: 3}) {
+		t.Fatal("expected only a plain update of the newest update's phase to coalesce")
+	}
+	if !ring.full() || !ring.evictSuperseded() {
+		t.Fatal("expected superseded updates to be evicted from the full ring")
+	}
+
+	items := ring.items()
+	if len(items) != 2 || items[0].Data != "match" || items[1].Phase != FetchSource || items[1].Step != 3 {
+		t.Errorf("expected the data update and the latest fetch update, got %+v", items)
+	}
+	if ring.evictSuperseded() {
+		t.Error("expected nothing left to evict")
+	}
+
+	// Pushing past the end wraps around, and a full ring grows rather than overwrite
+	for i := range 5 {
+		ring.push(ProgressUpdate{Phase: Compare, Step: i, Data: i})
+	}
+	if ring.n != 7 || ring.front().Data != "match" {
+		t.Errorf("expected 7 updates oldest first, got %+v", ring.items())
+	}
+}
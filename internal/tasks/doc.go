@@ -7,17 +7,24 @@
 //  1. [SyncEngine.Run] : Full Spotify → YouTube Music transfer
 //     - Fetches source playlist from Spotify
 //     - Searches each track on YouTube Music (ISRC or fuzzy match)
+//     - Uses pinned matches from [TrackOverrides] (loaded with [LoadTrackOverrides]) before searching
+//     - Skips source tracks on the optional [IgnoreList]
+//     - Skips podcast episodes and local files ([models.Track.Unmatchable]) without counting them as failures
 //     - Creates destination playlist with matched tracks
-//     - Returns detailed results including failed matches
+//     - Returns detailed results including failed matches, with each match's method and confidence
+//     - [TransferRunResult.Report] converts the result for [formatter.WriteTransferReport]
 //
 //  2. [SyncEngine.Diff] : Compare playlists across services
 //     - Exports both source and destination playlists
 //     - Matches tracks via ISRC (preferred) or normalized title/artist
 //     - Reports matched count, missing tracks, and extra tracks
+//     - Leaves out tracks on the optional [IgnoreList] from either playlist
+//     - [PlaylistEngine.DiffOffline] reads playlists from a [PlaylistStore] first, fetching live only on cache misses
 //
 //  3. [SyncEngine.Dump] : Fetch all YouTube Music library data
 //     - Retrieves playlists, songs, albums, artists, history, uploads
 //     - Returns structured data for backup or analysis
+//     - [PersistDump] upserts the result into a [LibraryPersister] for a queryable local mirror
 //
 // # Progress Reporting
 //
@@ -31,6 +38,14 @@
 // The optional [TrackCacher] interface enables automatic track persistence during transfers
 //
 // Tracks are cached silently (errors ignored) to avoid disrupting transfers.
+// Cachers that also implement [BulkTrackCacher] receive whole playlists in a single transactional call.
+// An optional [SearchCache] serves previous YouTube Music matches instead of searching again, and stores new matches.
+// An optional [PlaylistCacher] persists the source and destination playlists with their ordered tracks after a successful transfer.
+// An optional [MigrationRecorder] records every transfer that fetched its source, including unmatched tracks, for analytics.
+// An optional [Notifier], such as a [WebhookNotifier], announces every finished transfer with a [TransferSummary].
+// An optional [MetricsRecorder] measures searches and transfers.
+// Transfers, diffs, pushes, and dumps are traced with [tracing.Start] when a tracer is installed, with a child span
+// per transfer phase and per track search.
 
 // This supports ISRC-based matching across future operations and analytics on migration patterns.
 //
@@ -0,0 +1,248 @@
+package tasks
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tracing"
+)
+
+// Recipes [PlaylistEngine.Generate] builds playlists from
+const (
+	GenerateTopPlayed     = "top"            // Most played tracks of the listening history
+	GenerateUnsortedLikes = "unsorted-likes" // Liked tracks that are in none of the user's playlists
+)
+
+// DefaultGenerateLimit is the most tracks a generated playlist has when [GenerateOpts.Limit] is not set.
+const DefaultGenerateLimit = 100
+
+// ParseGenerateRecipe parses a recipe name, top or unsorted-likes.
+func ParseGenerateRecipe(s string) (string, error) {
+	switch recipe := strings.ToLower(strings.TrimSpace(s)); recipe {
+	case GenerateTopPlayed, GenerateUnsortedLikes:
+		return recipe, nil
+	case "":
+		return "", fmt.Errorf("%w: recipe (top or unsorted-likes)", shared.ErrMissingArgument)
+	default:
+		return "", fmt.Errorf("%w: recipe '%s' (want top or unsorted-likes)", shared.ErrInvalidArgument, s)
+	}
+}
+
+// ListeningHistory is implemented by services that report the tracks the user played, most recent first, with
+// AddedAt set to when each was played.
+type ListeningHistory interface {
+	History(ctx context.Context) ([]models.Track, error)
+}
+
+// LikedLibrary is implemented by services that list the tracks the user liked or saved, most recent first, with
+// AddedAt set to when each was liked where the service reports it.
+type LikedLibrary interface {
+	LikedSongs(ctx context.Context) ([]models.Track, error)
+}
+
+// GenerateOpts configures [PlaylistEngine.Generate].
+type GenerateOpts struct {
+	Recipe string           // GenerateTopPlayed or GenerateUnsortedLikes
+	Source services.Service // Where the listening history or liked tracks come from
+	Dest   services.Service // Where the playlist is created; the Source when nil
+	Since  time.Time        // Only plays or likes from this time on; tracks without a date are left out (zero: all time)
+	Limit  int              // Most tracks in the playlist (0: DefaultGenerateLimit)
+	Name   string           // Name of the created playlist
+	Public bool
+	DryRun bool // Only select the tracks, creating no playlist
+}
+
+// GenerateResult is the result of [PlaylistEngine.Generate].
+type GenerateResult struct {
+	Recipe   string
+	Tracks   []models.Track   // Selected from the source, in playlist order
+	Plays    []int            // Times each of Tracks was played, for GenerateTopPlayed
+	Playlist *models.Playlist // Created playlist; nil on a dry run
+	Push     *PushResult      // Tracks searched for on a Dest other than the Source; nil otherwise
+}
+
+// Generate builds a playlist from the user's listening data by opts.Recipe and, unless DryRun is set, creates it
+// on opts.Dest:
+//
+//   - [GenerateTopPlayed] ranks the tracks of the Source's [ListeningHistory] by how often they appear, breaking
+//     ties by the most recent play. YouTube Music dates its history only coarsely, so Since is approximate.
+//   - [GenerateUnsortedLikes] keeps the tracks of the Source's [LikedLibrary] that none of its playlists has, most
+//     recently liked first. Every playlist of the Source is fetched to find them.
+//
+// Tracks are added as they are when Dest is the Source; otherwise each is searched for on Dest, as
+// [PlaylistEngine.PushMissing] does, after the playlist is created.
+func (e *PlaylistEngine) Generate(ctx context.Context, opts GenerateOpts, progress chan<- ProgressUpdate) (result *GenerateResult, err error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	if opts.Source == nil {
+		return nil, fmt.Errorf("%w: service not initialized", shared.ErrServiceUnavailable)
+	}
+	if _, err := ParseGenerateRecipe(opts.Recipe); err != nil {
+		return nil, err
+	}
+	if opts.Dest == nil {
+		opts.Dest = opts.Source
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultGenerateLimit
+	}
+
+	ctx, span := tracing.Start(ctx, "generate", tracing.String("ytx.recipe", opts.Recipe), tracing.Int("ytx.limit", opts.Limit))
+	defer func() { span.RecordError(err); span.End() }()
+
+	result = &GenerateResult{Recipe: opts.Recipe}
+	switch opts.Recipe {
+	case GenerateTopPlayed:
+		history, ok := opts.Source.(ListeningHistory)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no listening history", shared.ErrInvalidArgument, opts.Source.Name())
+		}
+		e.sendProgress(progress, fetchLibraryUpdate(FetchHistory, "listening history", opts.Source.Name()))
+		played, err := history.History(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to fetch listening history: %w", shared.ErrAPIRequest, err)
+		}
+		result.Tracks, result.Plays = topPlayed(played, opts.Since, opts.Limit)
+	case GenerateUnsortedLikes:
+		library, ok := opts.Source.(LikedLibrary)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s cannot list liked tracks", shared.ErrInvalidArgument, opts.Source.Name())
+		}
+		e.sendProgress(progress, fetchLibraryUpdate(FetchLiked, "liked tracks", opts.Source.Name()))
+		liked, err := library.LikedSongs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to fetch liked tracks: %w", shared.ErrAPIRequest, err)
+		}
+		liked = likedSince(liked, opts.Since)
+
+		sorted, err := e.playlistTracks(ctx, opts.Source, progress)
+		if err != nil {
+			return nil, err
+		}
+		unsorted := missingFrom(liked, newTrackIndex(sorted))
+		result.Tracks = unsorted[:min(len(unsorted), opts.Limit)]
+	}
+
+	if opts.DryRun || len(result.Tracks) == 0 {
+		return result, nil
+	}
+
+	playlist := models.Playlist{
+		Name:        opts.Name,
+		Description: fmt.Sprintf("Generated by ytx on %s", time.Now().Format("2006-01-02")),
+		Public:      opts.Public,
+	}
+	tracks := result.Tracks
+	if opts.Dest != opts.Source {
+		tracks = nil // Added once they are found on the destination
+	}
+
+	e.sendProgress(progress, createDestinationUpdate(1, 1, opts.Dest.Name()))
+	result.Playlist, err = opts.Dest.ImportPlaylist(ctx, &models.PlaylistExport{Playlist: playlist, Tracks: tracks})
+	if err != nil {
+		return result, fmt.Errorf("%w: failed to create playlist %q: %w", shared.ErrAPIRequest, playlist.Name, err)
+	}
+	e.sendProgress(progress, createPlaylistUpdate(1, 1, result.Playlist))
+
+	if opts.Dest != opts.Source {
+		if result.Push, err = e.PushMissing(ctx, opts.Dest, result.Playlist.ID, result.Tracks, progress); err != nil {
+			return result, err
+		}
+		tracks = matchedTracks(result.Push)
+	}
+	e.cachePlaylist(ctx, e.serviceKey(opts.Dest), *result.Playlist, tracks)
+	return result, nil
+}
+
+// playlistTracks returns the tracks of every playlist of srv
+func (e *PlaylistEngine) playlistTracks(ctx context.Context, srv services.Service, progress chan<- ProgressUpdate) ([]models.Track, error) {
+	playlists, err := srv.GetPlaylists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get playlists: %w", shared.ErrAPIRequest, err)
+	}
+
+	var tracks []models.Track
+	for i, playlist := range playlists {
+		e.sendProgress(progress, scanPlaylistUpdate(i+1, len(playlists), playlist.Name))
+		export, err := srv.ExportPlaylist(ctx, playlist.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to export playlist %q: %w", shared.ErrAPIRequest, playlist.Name, err)
+		}
+		tracks = append(tracks, export.Tracks...)
+	}
+	return tracks, nil
+}
+
+// topPlayed ranks the tracks of history played since by how often they were played, then by the most recent play,
+// returning at most limit of them with their play counts
+func topPlayed(history []models.Track, since time.Time, limit int) ([]models.Track, []int) {
+	type ranked struct {
+		track models.Track // Most recent play
+		plays int
+	}
+
+	var order []*ranked
+	byKey := map[string]*ranked{}
+	for _, track := range history {
+		if !since.IsZero() && (track.AddedAt.IsZero() || track.AddedAt.Before(since)) {
+			continue
+		}
+		key := track.ID
+		if key == "" {
+			key = shared.NormalizeTitle(track.Title) + "\x00" + strings.ToLower(track.Artist())
+		}
+		r, ok := byKey[key]
+		if !ok {
+			r = &ranked{track: track}
+			byKey[key] = r
+			order = append(order, r)
+		}
+		r.plays++
+		if track.AddedAt.After(r.track.AddedAt) {
+			r.track = track
+		}
+	}
+
+	// History is most recent first, so a stable sort keeps ties in order of their last play
+	slices.SortStableFunc(order, func(a, b *ranked) int { return cmp.Compare(b.plays, a.plays) })
+
+	order = order[:min(len(order), limit)]
+	tracks, plays := make([]models.Track, len(order)), make([]int, len(order))
+	for i, r := range order {
+		tracks[i], plays[i] = r.track, r.plays
+	}
+	return tracks, plays
+}
+
+// likedSince keeps the tracks liked since, dropping those without a date; all of them when since is zero
+func likedSince(liked []models.Track, since time.Time) []models.Track {
+	if since.IsZero() {
+		return liked
+	}
+	var kept []models.Track
+	for _, track := range liked {
+		if !track.AddedAt.IsZero() && !track.AddedAt.Before(since) {
+			kept = append(kept, track)
+		}
+	}
+	return kept
+}
+
+// matchedTracks returns the destination tracks a push found
+func matchedTracks(push *PushResult) []models.Track {
+	var tracks []models.Track
+	for _, match := range push.TrackMatches {
+		if match.Matched != nil && match.Error == nil && match.Skipped == "" {
+			tracks = append(tracks, *match.Matched)
+		}
+	}
+	return tracks
+}
@@ -0,0 +1,106 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// libraryService reports a listening history and liked tracks and records the playlist created from them
+type libraryService struct {
+	*mockService
+	history []models.Track
+	liked   []models.Track
+	added   []models.Track
+}
+
+func (l *libraryService) History(ctx context.Context) ([]models.Track, error) { return l.history, nil }
+
+func (l *libraryService) LikedSongs(ctx context.Context) ([]models.Track, error) { return l.liked, nil }
+
+func (l *libraryService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	l.added = append(l.added, tracks...)
+	return nil
+}
+
+func TestPlaylistEngine_Generate(t *testing.T) {
+	now := time.Now()
+	played := func(id, title string, daysAgo int) models.Track {
+		return models.Track{ID: id, Title: title, Artists: []string{"Band"}, AddedAt: now.AddDate(0, 0, -daysAgo)}
+	}
+
+	t.Run("top played", func(t *testing.T) {
+		srv := &libraryService{
+			mockService: &mockService{name: "YouTube Music", importResult: &models.Playlist{ID: "top", Name: "Top"}},
+			history: []models.Track{
+				played("b", "B", 1), played("a", "A", 2), played("b", "B", 3), played("c", "C", 4),
+				played("a", "A", 5), played("c", "C", 400), played("c", "C", 401), played("c", "C", 402),
+			},
+		}
+		engine := NewPlaylistEngine(nil, nil, nil)
+		result, err := engine.Generate(context.Background(), GenerateOpts{
+			Recipe: GenerateTopPlayed, Source: srv, Since: now.AddDate(0, -6, 0), Limit: 2, Name: "Top",
+		}, nil)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if !sameTitles(result.Tracks, "B", "A") || len(result.Plays) != 2 || result.Plays[0] != 2 || result.Plays[1] != 2 {
+			t.Errorf("tracks = %v, plays = %v, want [B A] played twice each", titles(result.Tracks), result.Plays)
+		}
+		if !srv.importCalled || srv.imported.Playlist.Name != "Top" || !sameTitles(srv.imported.Tracks, "B", "A") {
+			t.Errorf("imported = %+v, want Top with B and A", srv.imported)
+		}
+	})
+
+	t.Run("unsorted likes are searched on another destination", func(t *testing.T) {
+		source := &libraryService{
+			mockService: &mockService{
+				name:            "Spotify",
+				playlists:       []models.Playlist{{ID: "pl", Name: "Sorted"}},
+				playlistExports: map[string]*models.PlaylistExport{"pl": {Tracks: []models.Track{{ID: "a", Title: "A", Artists: []string{"Band"}}}}},
+			},
+			liked: []models.Track{played("c", "C", 1), played("a", "A", 2), played("b", "B", 3), played("d", "D", 300)},
+		}
+		dest := &libraryService{mockService: &mockService{
+			name:          "YouTube Music",
+			importResult:  &models.Playlist{ID: "likes", Name: "Likes"},
+			searchResults: map[string]*models.Track{"C|Band": {ID: "yt-c", Title: "C", Artists: []string{"Band"}}},
+		}}
+
+		engine := NewPlaylistEngine(nil, nil, nil)
+		result, err := engine.Generate(context.Background(), GenerateOpts{
+			Recipe: GenerateUnsortedLikes, Source: source, Dest: dest, Since: now.AddDate(0, -6, 0), Name: "Likes",
+		}, nil)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if !sameTitles(result.Tracks, "C", "B") {
+			t.Errorf("tracks = %v, want [C B]", titles(result.Tracks))
+		}
+		if len(dest.imported.Tracks) != 0 {
+			t.Errorf("expected the playlist to be created empty, got %d tracks", len(dest.imported.Tracks))
+		}
+		if result.Push == nil || result.Push.AddedCount != 1 || result.Push.FailedCount != 1 || !sameTitles(dest.added, "C") {
+			t.Errorf("push = %+v, added %v, want C added and B not found", result.Push, titles(dest.added))
+		}
+	})
+
+	t.Run("dry run creates nothing", func(t *testing.T) {
+		srv := &libraryService{mockService: &mockService{name: "YouTube Music"}, history: []models.Track{played("a", "A", 1)}}
+		result, err := NewPlaylistEngine(nil, nil, nil).Generate(context.Background(), GenerateOpts{Recipe: GenerateTopPlayed, Source: srv, DryRun: true}, nil)
+		if err != nil || len(result.Tracks) != 1 || srv.importCalled {
+			t.Errorf("Generate() = %+v, %v, imported = %v", result, err, srv.importCalled)
+		}
+	})
+
+	t.Run("source without history", func(t *testing.T) {
+		_, err := NewPlaylistEngine(nil, nil, nil).Generate(context.Background(), GenerateOpts{Recipe: GenerateTopPlayed, Source: &mockService{name: "Spotify"}}, nil)
+		if !errors.Is(err, shared.ErrInvalidArgument) {
+			t.Errorf("Generate() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
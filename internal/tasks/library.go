@@ -0,0 +1,225 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
+)
+
+// LibraryPersister defines the interface for persisting dumped library entities.
+//
+// Implementations are expected to upsert by service + service ID so repeated dumps refresh the local mirror.
+type LibraryPersister interface {
+	UpsertPlaylist(ctx context.Context, service string, playlist models.Playlist) error
+	UpsertTrack(ctx context.Context, service string, track models.Track) error
+	UpsertAlbum(ctx context.Context, service string, album models.Album) error
+	UpsertArtist(ctx context.Context, service string, artist models.Artist) error
+}
+
+// PersistResult summarizes a library persistence run.
+type PersistResult struct {
+	Playlists int     // Playlists upserted
+	Songs     int     // Songs upserted
+	Albums    int     // Albums upserted
+	Artists   int     // Artists upserted
+	Skipped   int     // Entries skipped due to missing identifiers
+	Errors    []error // Non-fatal persistence errors
+}
+
+// ytmAlbumRef is an album reference embedded in YouTube Music library songs.
+type ytmAlbumRef struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// ytmLibraryPlaylist is a playlist entry from /api/library/playlists.
+type ytmLibraryPlaylist struct {
+	PlaylistID  string `json:"playlistId"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Privacy     string `json:"privacy"`
+	Count       int    `json:"count"`
+}
+
+// ytmLibrarySong is a song entry from /api/library/songs.
+type ytmLibrarySong struct {
+	VideoID     string                   `json:"videoId"`
+	Title       string                   `json:"title"`
+	Artists     []services.YouTubeArtist `json:"artists"`
+	Album       *ytmAlbumRef             `json:"album"`
+	DurationSec int                      `json:"duration_seconds"`
+	ISRC        string                   `json:"isrc"`
+}
+
+// ytmLibraryAlbum is an album entry from /api/library/albums.
+type ytmLibraryAlbum struct {
+	BrowseID   string                   `json:"browseId"`
+	Title      string                   `json:"title"`
+	Artists    []services.YouTubeArtist `json:"artists"`
+	Year       string                   `json:"year"`
+	TrackCount int                      `json:"trackCount"`
+}
+
+// ytmLibraryArtist is an artist entry from /api/library/artists.
+type ytmLibraryArtist struct {
+	BrowseID string `json:"browseId"`
+	Artist   string `json:"artist"`
+	Name     string `json:"name"`
+}
+
+// PersistDump upserts the playlists, songs, albums, and artists from a [DumpResult] into the persister.
+//
+// Entries are decoded from the raw proxy JSON. Entries without an ID are skipped, and individual
+// upsert failures are collected in [PersistResult.Errors] rather than aborting the run.
+// Cancelling ctx stops persistence between sections and returns the partial summary.
+func PersistDump(ctx context.Context, result *DumpResult, persister LibraryPersister) (*PersistResult, error) {
+	if result == nil {
+		return nil, fmt.Errorf("dump result cannot be nil")
+	}
+	if persister == nil {
+		return nil, fmt.Errorf("library persister cannot be nil")
+	}
+
+	const service = "youtube"
+	summary := &PersistResult{}
+
+	var playlists []ytmLibraryPlaylist
+	if err := decodeDumpSection(result.Playlists, &playlists); err != nil {
+		return nil, fmt.Errorf("failed to decode playlists: %w", err)
+	}
+	for _, p := range playlists {
+		if p.PlaylistID == "" {
+			summary.Skipped++
+			continue
+		}
+		playlist := models.Playlist{
+			ID:          p.PlaylistID,
+			Name:        p.Title,
+			Description: p.Description,
+			TrackCount:  p.Count,
+			Public:      p.Privacy == "PUBLIC",
+		}
+		if err := persister.UpsertPlaylist(ctx, service, playlist); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Errorf("playlist %s: %w", p.PlaylistID, err))
+			continue
+		}
+		summary.Playlists++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	var songs []ytmLibrarySong
+	if err := decodeDumpSection(result.Songs, &songs); err != nil {
+		return nil, fmt.Errorf("failed to decode songs: %w", err)
+	}
+	for _, s := range songs {
+		if s.VideoID == "" {
+			summary.Skipped++
+			continue
+		}
+		track := models.Track{
+			ID:       s.VideoID,
+			Title:    s.Title,
+			Duration: s.DurationSec,
+			ISRC:     s.ISRC,
+		}
+		track.SetArtists(artistNames(s.Artists)...)
+		if s.Album != nil {
+			track.Album = s.Album.Name
+		}
+		if err := persister.UpsertTrack(ctx, service, track); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Errorf("song %s: %w", s.VideoID, err))
+			continue
+		}
+		summary.Songs++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	var albums []ytmLibraryAlbum
+	if err := decodeDumpSection(result.Albums, &albums); err != nil {
+		return nil, fmt.Errorf("failed to decode albums: %w", err)
+	}
+	for _, a := range albums {
+		if a.BrowseID == "" {
+			summary.Skipped++
+			continue
+		}
+		album := models.Album{
+			ID:         a.BrowseID,
+			Title:      a.Title,
+			Artist:     primaryArtist(a.Artists),
+			Year:       a.Year,
+			TrackCount: a.TrackCount,
+		}
+		if err := persister.UpsertAlbum(ctx, service, album); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Errorf("album %s: %w", a.BrowseID, err))
+			continue
+		}
+		summary.Albums++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	var artists []ytmLibraryArtist
+	if err := decodeDumpSection(result.Artists, &artists); err != nil {
+		return nil, fmt.Errorf("failed to decode artists: %w", err)
+	}
+	for _, a := range artists {
+		if a.BrowseID == "" {
+			summary.Skipped++
+			continue
+		}
+		name := a.Artist
+		if name == "" {
+			name = a.Name
+		}
+		if err := persister.UpsertArtist(ctx, service, models.Artist{ID: a.BrowseID, Name: name}); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Errorf("artist %s: %w", a.BrowseID, err))
+			continue
+		}
+		summary.Artists++
+	}
+
+	return summary, nil
+}
+
+// decodeDumpSection converts a raw JSON section from the proxy into a typed slice.
+//
+// A nil section (e.g. from a failed endpoint) decodes to an empty slice.
+func decodeDumpSection(data any, target any) error {
+	if data == nil {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
+// primaryArtist returns the first artist's name, the one an album is credited to.
+func primaryArtist(artists []services.YouTubeArtist) string {
+	if len(artists) == 0 {
+		return ""
+	}
+	return artists[0].Name
+}
+
+// artistNames returns the names of artists in credit order, matching how [services.YouTubeService] maps tracks.
+func artistNames(artists []services.YouTubeArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
+}
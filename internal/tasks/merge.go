@@ -0,0 +1,265 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tracing"
+)
+
+// ThreeWayResult classifies how two playlists kept in sync have changed since their last stored snapshots.
+//
+// Each side is compared with its own snapshot, or with the other side's when it has none, so a track added on
+// one side and not the other is told apart from a track removed from the other side. A side without a snapshot of
+// its own has no known removals: a track in the other side's snapshot that it lacks may have been matched under
+// another title or artist, or never matched at all, rather than removed.
+type ThreeWayResult struct {
+	Source          *models.PlaylistExport // Live source playlist
+	Dest            *models.PlaylistExport // Live destination playlist
+	SourceBase      *models.PlaylistExport // Snapshot the source was compared with
+	DestBase        *models.PlaylistExport // Snapshot the destination was compared with
+	AddedOnSource   []models.Track         // Added to the source only
+	RemovedOnSource []models.Track         // Removed from the source only, as they were in its snapshot
+	AddedOnDest     []models.Track         // Added to the destination only
+	RemovedOnDest   []models.Track         // Removed from the destination only, as they were in its snapshot
+	AddedOnBoth     []models.Track         // Added to both sides, already in sync
+	RemovedOnBoth   []models.Track         // Removed from both sides, already in sync
+}
+
+// InSync reports whether neither side has a change the other lacks.
+func (r ThreeWayResult) InSync() bool {
+	return len(r.AddedOnSource) == 0 && len(r.RemovedOnSource) == 0 && len(r.AddedOnDest) == 0 && len(r.RemovedOnDest) == 0
+}
+
+// MergeResult reports how [PlaylistEngine.Merge] reconciled both sides of a [ThreeWayResult].
+type MergeResult struct {
+	ThreeWay          *ThreeWayResult
+	ToDest            *PushResult    // Source additions searched for and added to the destination; nil when none
+	ToSource          *PushResult    // Destination additions searched for and added to the source; nil when none
+	RemovedFromDest   []models.Track // Destination tracks removed because they were removed from the source
+	RemovedFromSource []models.Track // Source tracks removed because they were removed from the destination
+}
+
+// ThreeWayDiff compares two live playlists with their last stored snapshots, as cached after a transfer or an
+// offline diff, and classifies each change by the side it was made on.
+//
+// Both targets need a Service. It fails with [shared.ErrPlaylistNotFound] when neither playlist has a snapshot.
+func (e *PlaylistEngine) ThreeWayDiff(ctx context.Context, store PlaylistStore, source, dest DiffTarget, progress chan<- ProgressUpdate) (result *ThreeWayResult, err error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	if store == nil {
+		return nil, fmt.Errorf("%w: playlist store not initialized", shared.ErrServiceUnavailable)
+	}
+	if source.Service == nil || dest.Service == nil {
+		return nil, fmt.Errorf("%w: service not initialized", shared.ErrServiceUnavailable)
+	}
+
+	ctx, span := tracing.Start(ctx, "three_way_diff", tracing.String("ytx.source_id", source.PlaylistID), tracing.String("ytx.dest_id", dest.PlaylistID))
+	defer func() { span.RecordError(err); span.End() }()
+
+	sourceBase, sourceErr := store.LoadPlaylist(ctx, source.ServiceKey, source.PlaylistID)
+	destBase, destErr := store.LoadPlaylist(ctx, dest.ServiceKey, dest.PlaylistID)
+	switch {
+	case sourceErr != nil && destErr != nil:
+		return nil, fmt.Errorf("%w: no stored snapshot of either playlist to compare with; transfer or diff --offline first", shared.ErrPlaylistNotFound)
+	case sourceErr != nil:
+		sourceBase = destBase
+	case destErr != nil:
+		destBase = sourceBase
+	}
+
+	e.sendProgress(progress, fetchSourceUpdate(1, 2, source.name()))
+	live, err := source.Service.ExportPlaylist(ctx, source.PlaylistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export source playlist: %w", err)
+	}
+	result = &ThreeWayResult{Source: live, SourceBase: sourceBase, DestBase: destBase}
+
+	e.sendProgress(progress, fetchDestUpdate(2, 2, dest.name()))
+	if result.Dest, err = dest.Service.ExportPlaylist(ctx, dest.PlaylistID); err != nil {
+		return nil, fmt.Errorf("failed to export destination playlist: %w", err)
+	}
+
+	sourceTracks, _ := e.filterIgnored(ctx, result.Source.Tracks)
+	destTracks, _ := e.filterIgnored(ctx, result.Dest.Tracks)
+	sourceIndex, destIndex := newTrackIndex(sourceTracks), newTrackIndex(destTracks)
+	sourceBaseIndex, destBaseIndex := newTrackIndex(sourceBase.Tracks), newTrackIndex(destBase.Tracks)
+
+	addedOnSource := missingFrom(sourceTracks, sourceBaseIndex)
+	addedOnDest := missingFrom(destTracks, destBaseIndex)
+	var removedOnSource, removedOnDest []models.Track
+	if sourceErr == nil {
+		removedOnSource, _ = e.filterIgnored(ctx, missingFrom(sourceBase.Tracks, sourceIndex))
+	}
+	if destErr == nil {
+		removedOnDest, _ = e.filterIgnored(ctx, missingFrom(destBase.Tracks, destIndex))
+	}
+
+	// A change made on both sides needs no reconciling
+	result.AddedOnSource, result.AddedOnBoth = partition(addedOnSource, newTrackIndex(addedOnDest))
+	result.AddedOnDest, _ = partition(addedOnDest, newTrackIndex(addedOnSource))
+	result.RemovedOnSource, result.RemovedOnBoth = partition(removedOnSource, newTrackIndex(removedOnDest))
+	result.RemovedOnDest, _ = partition(removedOnDest, newTrackIndex(removedOnSource))
+
+	return result, nil
+}
+
+// Merge reconciles two playlists changed independently since their last snapshots: tracks added on one side are
+// searched for and added to the other, and tracks removed from one side are removed from the other. Both playlists
+// are then stored as the snapshots for the next merge.
+func (e *PlaylistEngine) Merge(ctx context.Context, store PlaylistStore, source, dest DiffTarget, progress chan<- ProgressUpdate) (result *MergeResult, err error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	ctx, span := tracing.Start(ctx, "merge", tracing.String("ytx.source_id", source.PlaylistID), tracing.String("ytx.dest_id", dest.PlaylistID))
+	defer func() { span.RecordError(err); span.End() }()
+
+	threeWay, err := e.ThreeWayDiff(ctx, store, source, dest, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &MergeResult{ThreeWay: threeWay}
+	sourceIndex, destIndex := newTrackIndex(threeWay.Source.Tracks), newTrackIndex(threeWay.Dest.Tracks)
+
+	// Tracks already on the other side, e.g. added there by hand, are not added twice
+	if toDest := missingFrom(threeWay.AddedOnSource, destIndex); len(toDest) > 0 {
+		if result.ToDest, err = e.pushAll(ctx, dest, toDest, progress); err != nil {
+			return result, err
+		}
+	}
+	if toSource := missingFrom(threeWay.AddedOnDest, sourceIndex); len(toSource) > 0 {
+		if result.ToSource, err = e.pushAll(ctx, source, toSource, progress); err != nil {
+			return result, err
+		}
+	}
+
+	if result.RemovedFromDest = matchesIn(threeWay.RemovedOnSource, destIndex); len(result.RemovedFromDest) > 0 {
+		if err := dest.Service.RemoveTracks(ctx, dest.PlaylistID, result.RemovedFromDest); err != nil {
+			return result, fmt.Errorf("%w: failed to remove tracks from destination: %w", shared.ErrAPIRequest, err)
+		}
+	}
+	if result.RemovedFromSource = matchesIn(threeWay.RemovedOnDest, sourceIndex); len(result.RemovedFromSource) > 0 {
+		if err := source.Service.RemoveTracks(ctx, source.PlaylistID, result.RemovedFromSource); err != nil {
+			return result, fmt.Errorf("%w: failed to remove tracks from source: %w", shared.ErrAPIRequest, err)
+		}
+	}
+
+	e.storeSnapshots(ctx, source, dest)
+	return result, nil
+}
+
+// storeSnapshots stores the playlists of targets as they are now, as the snapshots the next two-way merge
+// compares with. A playlist that cannot be exported keeps its previous snapshot.
+func (e *PlaylistEngine) storeSnapshots(ctx context.Context, targets ...DiffTarget) {
+	for _, target := range targets {
+		if export, err := target.Service.ExportPlaylist(ctx, target.PlaylistID); err == nil {
+			e.cachePlaylist(ctx, target.ServiceKey, export.Playlist, export.Tracks)
+		}
+	}
+}
+
+// pushAll searches for tracks on the target's service and adds the matches to its playlist.
+// Finding no match at all is reported in the result rather than as an error.
+func (e *PlaylistEngine) pushAll(ctx context.Context, target DiffTarget, tracks []models.Track, progress chan<- ProgressUpdate) (*PushResult, error) {
+	result, err := e.PushMissing(ctx, target.Service, target.PlaylistID, tracks, progress)
+	if err != nil && result != nil && result.FailedCount+result.SkippedCount == len(tracks) {
+		return result, nil
+	}
+	return result, err
+}
+
+// trackIndex finds tracks by ID, ISRC, or normalized title and matching artists, so tracks match within a service
+// by ID and across services the way [comparePlaylists] matches them.
+type trackIndex struct {
+	ids    map[string]models.Track
+	isrcs  map[string]models.Track
+	titles map[string][]models.Track // By [shared.NormalizeTitle], in playlist order
+}
+
+func newTrackIndex(tracks []models.Track) trackIndex {
+	idx := trackIndex{
+		ids:    make(map[string]models.Track, len(tracks)),
+		isrcs:  make(map[string]models.Track, len(tracks)),
+		titles: make(map[string][]models.Track, len(tracks)),
+	}
+	for _, track := range tracks {
+		idx.add(track)
+	}
+	return idx
+}
+
+func (idx trackIndex) add(track models.Track) {
+	if track.ID != "" {
+		idx.ids[track.ID] = track
+	}
+	if track.ISRC != "" {
+		idx.isrcs[track.ISRC] = track
+	}
+	title := shared.NormalizeTitle(track.Title)
+	idx.titles[title] = append(idx.titles[title], track)
+}
+
+// find returns the indexed track matching track: the same ID or ISRC, or the same normalized title with artists
+// that match by [shared.ArtistsMatch], so a duet credited to both artists matches the same duet credited to either
+func (idx trackIndex) find(track models.Track) (models.Track, bool) {
+	if match, ok := idx.ids[track.ID]; ok && track.ID != "" {
+		return match, true
+	}
+	if match, ok := idx.isrcs[track.ISRC]; ok && track.ISRC != "" {
+		return match, true
+	}
+	for _, candidate := range idx.titles[shared.NormalizeTitle(track.Title)] {
+		if shared.ArtistsMatch(track.Artists, candidate.Artists) {
+			return candidate, true
+		}
+	}
+	return models.Track{}, false
+}
+
+// dedupeTracks returns tracks without the repeats of a track earlier in the list, matched the way [trackIndex]
+// matches them, and the repeats it left out
+func dedupeTracks(tracks []models.Track) (kept, repeats []models.Track) {
+	seen := newTrackIndex(nil)
+	for _, track := range tracks {
+		if _, ok := seen.find(track); ok {
+			repeats = append(repeats, track)
+			continue
+		}
+		seen.add(track)
+		kept = append(kept, track)
+	}
+	return kept, repeats
+}
+
+// missingFrom returns the tracks without a match in idx, in order
+func missingFrom(tracks []models.Track, idx trackIndex) []models.Track {
+	missing, _ := partition(tracks, idx)
+	return missing
+}
+
+// partition splits tracks into those without and those with a match in idx, in order
+func partition(tracks []models.Track, idx trackIndex) (unmatched, matched []models.Track) {
+	for _, track := range tracks {
+		if _, ok := idx.find(track); ok {
+			matched = append(matched, track)
+		} else {
+			unmatched = append(unmatched, track)
+		}
+	}
+	return unmatched, matched
+}
+
+// matchesIn returns the indexed tracks matching tracks, as they appear in idx
+func matchesIn(tracks []models.Track, idx trackIndex) []models.Track {
+	var matches []models.Track
+	for _, track := range tracks {
+		if match, ok := idx.find(track); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,284 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// recordingService records the tracks added to and removed from its playlists
+type recordingService struct {
+	*mockService
+	added   []models.Track
+	removed []models.Track
+}
+
+func (r *recordingService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	r.added = append(r.added, tracks...)
+	return nil
+}
+
+func (r *recordingService) RemoveTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	r.removed = append(r.removed, tracks...)
+	return nil
+}
+
+func titles(tracks []models.Track) []string {
+	names := make([]string, len(tracks))
+	for i, track := range tracks {
+		names[i] = track.Title
+	}
+	return names
+}
+
+func sameTitles(tracks []models.Track, want ...string) bool {
+	got := titles(tracks)
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeFixture is a playlist transferred with tracks A, B, C, D, then edited on both sides:
+// the source added E and F and removed B and D; the destination added G and F and removed C and D.
+func mergeFixture() (*recordingService, *recordingService, *mockPlaylistStore) {
+	sp := func(id, title string) models.Track {
+		return models.Track{ID: "sp-" + id, Title: title, Artists: []string{"Band"}}
+	}
+	yt := func(id, title string) models.Track {
+		return models.Track{ID: "yt-" + id, Title: title, Artists: []string{"Band"}}
+	}
+
+	source := &recordingService{mockService: &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"src": {Playlist: models.Playlist{ID: "src"}, Tracks: []models.Track{sp("a", "A"), sp("c", "C"), sp("e", "E"), sp("f", "F")}},
+		},
+		searchResults: map[string]*models.Track{"G|Band": {ID: "sp-g", Title: "G", Artists: []string{"Band"}}},
+	}}
+	dest := &recordingService{mockService: &mockService{
+		name: "YouTube Music",
+		playlistExports: map[string]*models.PlaylistExport{
+			"dst": {Playlist: models.Playlist{ID: "dst"}, Tracks: []models.Track{yt("a", "A"), yt("b", "B"), yt("g", "G"), yt("f", "F")}},
+		},
+		searchResults: map[string]*models.Track{"E|Band": {ID: "yt-e", Title: "E", Artists: []string{"Band"}}},
+	}}
+	store := &mockPlaylistStore{playlists: map[string]*models.PlaylistExport{
+		"spotify:src": {Tracks: []models.Track{sp("a", "A"), sp("b", "B"), sp("c", "C"), sp("d", "D")}},
+		"youtube:dst": {Tracks: []models.Track{yt("a", "A"), yt("b", "B"), yt("c", "C"), yt("d", "D")}},
+	}}
+	return source, dest, store
+}
+
+func TestPlaylistEngine_ThreeWayDiff(t *testing.T) {
+	source, dest, store := mergeFixture()
+	engine := NewPlaylistEngine(nil, nil, nil)
+
+	result, err := engine.ThreeWayDiff(context.Background(), store,
+		DiffTarget{ServiceKey: "spotify", PlaylistID: "src", Service: source},
+		DiffTarget{ServiceKey: "youtube", PlaylistID: "dst", Service: dest},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("ThreeWayDiff() error = %v", err)
+	}
+
+	checks := []struct {
+		name   string
+		tracks []models.Track
+		want   []string
+	}{
+		{"AddedOnSource", result.AddedOnSource, []string{"E"}},
+		{"RemovedOnSource", result.RemovedOnSource, []string{"B"}},
+		{"AddedOnDest", result.AddedOnDest, []string{"G"}},
+		{"RemovedOnDest", result.RemovedOnDest, []string{"C"}},
+		{"AddedOnBoth", result.AddedOnBoth, []string{"F"}},
+		{"RemovedOnBoth", result.RemovedOnBoth, []string{"D"}},
+	}
+	for _, check := range checks {
+		if !sameTitles(check.tracks, check.want...) {
+			t.Errorf("%s = %v, want %v", check.name, titles(check.tracks), check.want)
+		}
+	}
+	if result.InSync() {
+		t.Error("expected the playlists to be out of sync")
+	}
+
+	t.Run("falls back to the other side's snapshot", func(t *testing.T) {
+		delete(store.playlists, "youtube:dst")
+		result, err := engine.ThreeWayDiff(context.Background(), store,
+			DiffTarget{ServiceKey: "spotify", PlaylistID: "src", Service: source},
+			DiffTarget{ServiceKey: "youtube", PlaylistID: "dst", Service: dest},
+			nil,
+		)
+		if err != nil {
+			t.Fatalf("ThreeWayDiff() error = %v", err)
+		}
+		if !sameTitles(result.AddedOnDest, "G") {
+			t.Errorf("expected destination additions matched across services, got %v", titles(result.AddedOnDest))
+		}
+		if len(result.RemovedOnDest) != 0 || !sameTitles(result.RemovedOnSource, "B", "D") {
+			t.Errorf("expected no removals known on the side without a snapshot, got %v and %v on the source",
+				titles(result.RemovedOnDest), titles(result.RemovedOnSource))
+		}
+	})
+
+	t.Run("no snapshot", func(t *testing.T) {
+		_, err := engine.ThreeWayDiff(context.Background(), &mockPlaylistStore{},
+			DiffTarget{ServiceKey: "spotify", PlaylistID: "src", Service: source},
+			DiffTarget{ServiceKey: "youtube", PlaylistID: "dst", Service: dest},
+			nil,
+		)
+		if !errors.Is(err, shared.ErrPlaylistNotFound) {
+			t.Errorf("expected ErrPlaylistNotFound, got %v", err)
+		}
+	})
+
+	t.Run("export failures keep their cause", func(t *testing.T) {
+		source, dest, store := mergeFixture()
+		for _, cause := range []error{shared.ErrTokenExpired, context.Canceled} {
+			dest.exportErr = cause
+			_, err := engine.ThreeWayDiff(context.Background(), store,
+				DiffTarget{ServiceKey: "spotify", PlaylistID: "src", Service: source},
+				DiffTarget{ServiceKey: "youtube", PlaylistID: "dst", Service: dest},
+				nil,
+			)
+			if !errors.Is(err, cause) || errors.Is(err, shared.ErrPlaylistNotFound) {
+				t.Errorf("expected %v reported as is, got %v", cause, err)
+			}
+		}
+	})
+}
+
+func TestPlaylistEngine_Merge(t *testing.T) {
+	source, dest, store := mergeFixture()
+	engine := NewPlaylistEngine(nil, nil, nil)
+	cacher := &mockPlaylistCacher{}
+	engine.SetPlaylistCacher(cacher)
+
+	result, err := engine.Merge(context.Background(), store,
+		DiffTarget{ServiceKey: "spotify", PlaylistID: "src", Service: source},
+		DiffTarget{ServiceKey: "youtube", PlaylistID: "dst", Service: dest},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if !sameTitles(dest.added, "E") || result.ToDest.AddedCount != 1 {
+		t.Errorf("expected E added to the destination, got %v", titles(dest.added))
+	}
+	if !sameTitles(source.added, "G") || result.ToSource.AddedCount != 1 {
+		t.Errorf("expected G added to the source, got %v", titles(source.added))
+	}
+	if !sameTitles(dest.removed, "B") || dest.removed[0].ID != "yt-b" {
+		t.Errorf("expected the destination's B removed, got %+v", dest.removed)
+	}
+	if !sameTitles(source.removed, "C") || source.removed[0].ID != "sp-c" {
+		t.Errorf("expected the source's C removed, got %+v", source.removed)
+	}
+	if _, ok := cacher.cached["spotify:src"]; !ok {
+		t.Error("expected the source to be stored as the next snapshot")
+	}
+	if _, ok := cacher.cached["youtube:dst"]; !ok {
+		t.Error("expected the destination to be stored as the next snapshot")
+	}
+}
+
+// snapshotStore keeps the playlists cached by the engine as the snapshots a merge loads
+type snapshotStore struct {
+	mockPlaylistStore
+}
+
+func (s *snapshotStore) CachePlaylist(ctx context.Context, service string, playlist models.Playlist, tracks []models.Track) error {
+	s.playlists[service+":"+playlist.ID] = &models.PlaylistExport{Playlist: playlist, Tracks: slices.Clone(tracks)}
+	return nil
+}
+
+func TestPlaylistEngine_MergeAfterTransfer(t *testing.T) {
+	// Hoppípolla is matched under another spelling, and the demo is not matched at all
+	setup := func(t *testing.T) (*PlaylistEngine, *recordingService, *recordingService, *snapshotStore) {
+		t.Helper()
+		spotify := &recordingService{mockService: &mockService{
+			name: "Spotify",
+			playlistExports: map[string]*models.PlaylistExport{
+				"src": {Playlist: models.Playlist{ID: "src", Name: "Road Trip"}, Tracks: []models.Track{
+					{ID: "sp-n", Title: "Nude", Artists: []string{"Radiohead"}},
+					{ID: "sp-h", Title: "Hoppípolla", Artists: []string{"Sigur Rós"}},
+					{ID: "sp-d", Title: "Unreleased Demo", Artists: []string{"Nobody"}},
+				}},
+			},
+		}}
+		youtube := &recordingService{mockService: &mockService{
+			name: "YouTube Music",
+			searchResults: map[string]*models.Track{
+				"Nude|Radiohead":       {ID: "yt-n", Title: "Nude", Artists: []string{"Radiohead"}},
+				"Hoppípolla|Sigur Rós": {ID: "yt-h", Title: "Hopipolla", Artists: []string{"Sigur Ros"}},
+			},
+			importResult: &models.Playlist{ID: "dst", Name: "Road Trip"},
+		}}
+		store := &snapshotStore{mockPlaylistStore{playlists: map[string]*models.PlaylistExport{}}}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+		engine.SetPlaylistCacher(store)
+
+		if _, err := engine.RunWithOpts(context.Background(), "src", TransferOpts{}, nil); err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		youtube.playlistExports = map[string]*models.PlaylistExport{"dst": youtube.imported}
+		return engine, spotify, youtube, store
+	}
+	merge := func(t *testing.T, engine *PlaylistEngine, spotify, youtube *recordingService, store *snapshotStore) *MergeResult {
+		t.Helper()
+		result, err := engine.Merge(context.Background(), store,
+			DiffTarget{ServiceKey: "spotify", PlaylistID: "src", Service: spotify},
+			DiffTarget{ServiceKey: "youtube", PlaylistID: "dst", Service: youtube},
+			nil,
+		)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		return result
+	}
+
+	t.Run("both snapshots", func(t *testing.T) {
+		engine, spotify, youtube, store := setup(t)
+		if _, ok := store.playlists["spotify:src"]; !ok {
+			t.Fatal("expected the transfer to store the source snapshot")
+		}
+
+		result := merge(t, engine, spotify, youtube, store)
+		if !result.ThreeWay.InSync() || result.ToDest != nil || len(youtube.removed) != 0 || len(spotify.added) != 0 {
+			t.Errorf("expected an untouched transfer to be in sync, got %+v", result.ThreeWay)
+		}
+	})
+
+	t.Run("destination snapshot only", func(t *testing.T) {
+		engine, spotify, youtube, store := setup(t)
+		delete(store.playlists, "spotify:src")
+
+		result := merge(t, engine, spotify, youtube, store)
+		if len(youtube.removed) != 0 || len(result.ThreeWay.RemovedOnSource) != 0 {
+			t.Errorf("expected nothing removed without a source snapshot, got %v", titles(youtube.removed))
+		}
+		if _, ok := store.playlists["spotify:src"]; !ok {
+			t.Error("expected the merge to store the source snapshot for the next one")
+		}
+
+		// With both snapshots stored, the unmatched demo is not searched for again
+		searches := 0
+		youtube.onSearch = func(string) { searches++ }
+		merge(t, engine, spotify, youtube, store)
+		if searches != 0 || len(youtube.removed) != 0 {
+			t.Errorf("expected the next merge to find no changes, got %d searches", searches)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/desertthunder/ytx/internal/services"
+)
+
+// ObserveQueueDepth reports how many queued transfers have yet to finish, including the running one.
+//
+// The engine runs one transfer at a time, so queues such as the TUI's report their depth through this method.
+func (e *PlaylistEngine) ObserveQueueDepth(depth int) {
+	if e.metrics == nil {
+		return
+	}
+	e.metrics.ObserveQueueDepth(depth)
+}
+
+// observeSearch records the latency and outcome of a live track search started at start
+func (e *PlaylistEngine) observeSearch(service string, start time.Time, err error) {
+	if e.metrics == nil {
+		return
+	}
+	e.metrics.ObserveSearch(service, time.Since(start), err == nil)
+}
+
+// observeTransfer records the duration and outcome of a transfer started at startedAt
+func (e *PlaylistEngine) observeTransfer(startedAt time.Time, runErr error) {
+	if e.metrics == nil {
+		return
+	}
+	status := TransferCompleted
+	if runErr != nil {
+		status = TransferFailed
+	}
+	e.metrics.ObserveTransfer(status, time.Since(startedAt))
+}
+
+// serviceKey returns the service key ("spotify" or "youtube") of one of the engine's services
+func (e *PlaylistEngine) serviceKey(svc services.Service) string {
+	if svc == e.spotify {
+		return "spotify"
+	}
+	return "youtube"
+}
@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+)
+
+// Mock metrics recorder for testing
+type mockMetrics struct {
+	searches   map[string]int // "service|matched" → count
+	transfers  []string
+	queueDepth int
+}
+
+func (m *mockMetrics) ObserveSearch(service string, duration time.Duration, matched bool) {
+	key := service + "|failed"
+	if matched {
+		key = service + "|matched"
+	}
+	m.searches[key]++
+}
+
+func (m *mockMetrics) ObserveTransfer(status string, duration time.Duration) {
+	m.transfers = append(m.transfers, status)
+}
+
+func (m *mockMetrics) ObserveQueueDepth(depth int) {
+	m.queueDepth = depth
+}
+
+func TestPlaylistEngine_RunWithOpts_Metrics(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Road Trip"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+					{ID: "ep1", Title: "Episode", URI: "spotify:episode:ep1"},
+				},
+			},
+		},
+	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Road Trip"},
+	}
+
+	metrics := &mockMetrics{searches: map[string]int{}}
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetMetricsRecorder(metrics)
+
+	if _, err := engine.Run(context.Background(), "playlist123", nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if _, err := engine.Run(context.Background(), "missing", nil); err == nil {
+		t.Fatal("expected an error for a missing playlist")
+	}
+
+	// The unmatched track is searched by title and artist, then by title alone
+	if metrics.searches["youtube|matched"] != 1 || metrics.searches["youtube|failed"] != 2 || len(metrics.searches) != 2 {
+		t.Errorf("expected one matched and two failed YouTube searches, got %v", metrics.searches)
+	}
+	if len(metrics.transfers) != 2 || metrics.transfers[0] != TransferCompleted || metrics.transfers[1] != TransferFailed {
+		t.Errorf("transfers = %v, want [completed failed]", metrics.transfers)
+	}
+
+	engine.ObserveQueueDepth(3)
+	if metrics.queueDepth != 3 {
+		t.Errorf("queueDepth = %d, want 3", metrics.queueDepth)
+	}
+	NewPlaylistEngine(spotify, youtube, nil).ObserveQueueDepth(1) // No recorder: must not panic
+}
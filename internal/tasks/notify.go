@@ -0,0 +1,174 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// Transfer summary statuses
+const (
+	TransferCompleted = "completed"
+	TransferFailed    = "failed"
+)
+
+// TransferSummary describes a finished transfer for a [Notifier].
+type TransferSummary struct {
+	Status           string    `json:"status"` // [TransferCompleted] or [TransferFailed]
+	SourceService    string    `json:"source_service,omitempty"`
+	SourcePlaylistID string    `json:"source_playlist_id"`
+	SourcePlaylist   string    `json:"source_playlist,omitempty"` // Empty when the source playlist was never fetched
+	DestService      string    `json:"dest_service,omitempty"`
+	DestPlaylistID   string    `json:"dest_playlist_id,omitempty"`
+	DestPlaylist     string    `json:"dest_playlist,omitempty"`
+	TotalTracks      int       `json:"total_tracks"`
+	Matched          int       `json:"matched"`
+	Failed           int       `json:"failed"`
+	Skipped          int       `json:"skipped"`
+	Ignored          int       `json:"ignored"`
+	MatchPercentage  float64   `json:"match_percentage"`
+	MigrationID      string    `json:"migration_id,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	StartedAt        time.Time `json:"started_at"`
+	CompletedAt      time.Time `json:"completed_at"`
+}
+
+// Text formats the summary as a short chat message.
+func (s TransferSummary) Text() string {
+	name := s.SourcePlaylist
+	if name == "" {
+		name = s.SourcePlaylistID
+	}
+
+	var b strings.Builder
+	if s.Status == TransferCompleted {
+		fmt.Fprintf(&b, "✓ Transfer completed: %s", name)
+	} else {
+		fmt.Fprintf(&b, "✗ Transfer failed: %s", name)
+	}
+	if s.SourceService != "" && s.DestService != "" {
+		fmt.Fprintf(&b, " (%s → %s)", s.SourceService, s.DestService)
+	}
+	if s.TotalTracks > 0 {
+		fmt.Fprintf(&b, "\nMatched %d/%d tracks (%.1f%%)", s.Matched, s.TotalTracks-s.Skipped, s.MatchPercentage)
+		if s.Failed > 0 {
+			fmt.Fprintf(&b, ", %d failed", s.Failed)
+		}
+		if s.Skipped > 0 {
+			fmt.Fprintf(&b, ", %d skipped", s.Skipped)
+		}
+	}
+	if s.Error != "" {
+		fmt.Fprintf(&b, "\nError: %s", s.Error)
+	}
+	return b.String()
+}
+
+// transferSummary summarizes a finished transfer of srcID. result is nil when the source playlist was never fetched.
+func transferSummary(srcID string, result *TransferRunResult, startedAt time.Time, runErr error) TransferSummary {
+	summary := TransferSummary{
+		Status:           TransferCompleted,
+		SourcePlaylistID: srcID,
+		StartedAt:        startedAt,
+		CompletedAt:      time.Now(),
+	}
+	if runErr != nil {
+		summary.Status = TransferFailed
+		summary.Error = runErr.Error()
+	}
+	if result == nil {
+		return summary
+	}
+
+	summary.SourceService = result.SourceService
+	summary.DestService = result.DestService
+	if result.SourcePlaylist != nil {
+		summary.SourcePlaylistID = result.SourcePlaylist.Playlist.ID
+		summary.SourcePlaylist = result.SourcePlaylist.Playlist.Name
+	}
+	if result.DestPlaylist != nil {
+		summary.DestPlaylistID = result.DestPlaylist.ID
+		summary.DestPlaylist = result.DestPlaylist.Name
+	}
+	summary.TotalTracks = result.TotalTracks
+	summary.Matched = result.SuccessCount
+	summary.Failed = result.FailedCount
+	summary.Skipped = result.SkippedCount
+	summary.Ignored = len(result.Ignored)
+	summary.MatchPercentage = result.MatchPercentage
+	summary.MigrationID = result.MigrationID
+	return summary
+}
+
+// notifyTransfer attempts to announce a finished transfer. Failures are silent.
+func (e *PlaylistEngine) notifyTransfer(ctx context.Context, srcID string, result *TransferRunResult, startedAt time.Time, runErr error) {
+	if e.notifier == nil {
+		return
+	}
+	_ = e.notifier.NotifyTransfer(ctx, transferSummary(srcID, result, startedAt, runErr))
+}
+
+// WebhookNotifier posts transfer summaries to the endpoints in a [shared.NotificationsConfig].
+//
+// The generic webhook receives {"event": "transfer.completed" | "transfer.failed", "transfer": summary},
+// while Discord and Slack receive [TransferSummary.Text] as a chat message.
+type WebhookNotifier struct {
+	config shared.NotificationsConfig
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier. A nil client uses one limited to the configured request timeout.
+func NewWebhookNotifier(config shared.NotificationsConfig, client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: config.RequestTimeout()}
+	}
+	return &WebhookNotifier{config: config, client: client}
+}
+
+// NotifyTransfer posts summary to every configured endpoint, returning the joined errors of failed posts.
+func (n *WebhookNotifier) NotifyTransfer(ctx context.Context, summary TransferSummary) error {
+	var errs []error
+	if n.config.WebhookURL != "" {
+		payload := map[string]any{"event": "transfer." + summary.Status, "transfer": summary}
+		errs = append(errs, n.post(ctx, "webhook", n.config.WebhookURL, payload))
+	}
+	if n.config.DiscordURL != "" {
+		errs = append(errs, n.post(ctx, "Discord", n.config.DiscordURL, map[string]string{"content": summary.Text()}))
+	}
+	if n.config.SlackURL != "" {
+		errs = append(errs, n.post(ctx, "Slack", n.config.SlackURL, map[string]string{"text": summary.Text()}))
+	}
+	return errors.Join(errs...)
+}
+
+// post sends payload to url as JSON
+func (n *WebhookNotifier) post(ctx context.Context, name, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s notification: %w", name, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: invalid %s URL: %v", shared.ErrInvalidConfig, name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s notification: %v", shared.ErrAPIRequest, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s notification returned status %d", shared.ErrAPIRequest, name, resp.StatusCode)
+	}
+	return nil
+}
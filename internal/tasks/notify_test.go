@@ -0,0 +1,135 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// Mock notifier for testing
+type mockNotifier struct {
+	summaries []TransferSummary
+}
+
+func (m *mockNotifier) NotifyTransfer(ctx context.Context, summary TransferSummary) error {
+	m.summaries = append(m.summaries, summary)
+	return errors.New("notification failed")
+}
+
+func TestPlaylistEngine_RunWithOpts_Notifier(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Road Trip"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
+			},
+		},
+	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Road Trip"},
+	}
+
+	notifier := &mockNotifier{}
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetNotifier(notifier)
+
+	if _, err := engine.Run(context.Background(), "playlist123", nil); err != nil {
+		t.Fatalf("expected a failing notifier not to fail the transfer, got %v", err)
+	}
+	if _, err := engine.Run(context.Background(), "missing", nil); err == nil {
+		t.Fatal("expected an error for a missing playlist")
+	}
+
+	if len(notifier.summaries) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifier.summaries))
+	}
+
+	completed := notifier.summaries[0]
+	if completed.Status != TransferCompleted || completed.SourcePlaylist != "Road Trip" || completed.DestPlaylistID != "yt_playlist" {
+		t.Errorf("unexpected completed summary %+v", completed)
+	}
+	if completed.Matched != 1 || completed.Failed != 1 || completed.TotalTracks != 2 || completed.CompletedAt.Before(completed.StartedAt) {
+		t.Errorf("unexpected completed counts %+v", completed)
+	}
+
+	failed := notifier.summaries[1]
+	if failed.Status != TransferFailed || failed.SourcePlaylistID != "missing" || failed.Error == "" {
+		t.Errorf("unexpected failed summary %+v", failed)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode notification: %v", err)
+		}
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	summary := TransferSummary{
+		Status:           TransferCompleted,
+		SourceService:    "spotify",
+		SourcePlaylistID: "sp_pl",
+		SourcePlaylist:   "Road Trip",
+		DestService:      "youtube",
+		TotalTracks:      10,
+		Matched:          8,
+		Failed:           1,
+		Skipped:          1,
+		MatchPercentage:  88.9,
+	}
+
+	notifier := NewWebhookNotifier(shared.NotificationsConfig{
+		WebhookURL: server.URL + "/hook",
+		DiscordURL: server.URL + "/discord",
+		SlackURL:   server.URL + "/slack",
+	}, server.Client())
+	if err := notifier.NotifyTransfer(context.Background(), summary); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if event := bodies["/hook"]["event"]; event != "transfer.completed" {
+		t.Errorf("expected event transfer.completed, got %v", event)
+	}
+	if transfer, _ := bodies["/hook"]["transfer"].(map[string]any); transfer["source_playlist"] != "Road Trip" || transfer["matched"] != float64(8) {
+		t.Errorf("unexpected webhook transfer %v", transfer)
+	}
+	want := "✓ Transfer completed: Road Trip (spotify → youtube)\nMatched 8/9 tracks (88.9%), 1 failed, 1 skipped"
+	if content := bodies["/discord"]["content"]; content != want {
+		t.Errorf("Discord content = %q, want %q", content, want)
+	}
+	if text := bodies["/slack"]["text"]; text != want {
+		t.Errorf("Slack text = %q, want %q", text, want)
+	}
+
+	broken := NewWebhookNotifier(shared.NotificationsConfig{WebhookURL: server.URL + "/broken"}, server.Client())
+	err := broken.NotifyTransfer(context.Background(), TransferSummary{Status: TransferFailed, SourcePlaylistID: "sp_pl", Error: "boom"})
+	if !errors.Is(err, shared.ErrAPIRequest) || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected ErrAPIRequest with the status code, got %v", err)
+	}
+}
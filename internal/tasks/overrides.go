@@ -0,0 +1,104 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// TrackOverride pins how one source track is matched, identified by its source ID or ISRC.
+//
+// Exactly one of DestID and Query is set.
+type TrackOverride struct {
+	SourceID string `json:"source_id,omitempty" yaml:"source_id,omitempty"`
+	ISRC     string `json:"isrc,omitempty" yaml:"isrc,omitempty"`
+	DestID   string `json:"dest_id,omitempty" yaml:"dest_id,omitempty"` // Destination track to use instead of searching
+	Query    string `json:"query,omitempty" yaml:"query,omitempty"`     // Search text used instead of the title and artist
+}
+
+// TrackOverrides maps chronic mismatches to explicit destination tracks or search queries.
+//
+// Overrides listed under a source playlist ID take precedence over the global Tracks list.
+type TrackOverrides struct {
+	Tracks    []TrackOverride            `json:"tracks,omitempty" yaml:"tracks,omitempty"`
+	Playlists map[string][]TrackOverride `json:"playlists,omitempty" yaml:"playlists,omitempty"`
+}
+
+// LoadTrackOverrides reads an overrides file, decoding it as YAML for .yaml/.yml files and as JSON otherwise.
+func LoadTrackOverrides(path string) (*TrackOverrides, error) {
+	data, err := os.ReadFile(shared.ExpandPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overrides file: %w", err)
+	}
+
+	var overrides TrackOverrides
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&overrides)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&overrides)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse overrides file %s: %v", shared.ErrInvalidInput, path, err)
+	}
+
+	if err := overrides.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", shared.ErrInvalidInput, path, err)
+	}
+	return &overrides, nil
+}
+
+// Validate checks that every override identifies a source track and sets exactly one of DestID and Query.
+func (o *TrackOverrides) Validate() error {
+	check := func(scope string, overrides []TrackOverride) error {
+		for i, override := range overrides {
+			if override.SourceID == "" && override.ISRC == "" {
+				return fmt.Errorf("%s override %d needs a source_id or isrc", scope, i+1)
+			}
+			if (override.DestID == "") == (override.Query == "") {
+				return fmt.Errorf("%s override %d needs exactly one of dest_id and query", scope, i+1)
+			}
+		}
+		return nil
+	}
+
+	if err := check("track", o.Tracks); err != nil {
+		return err
+	}
+	for playlistID, overrides := range o.Playlists {
+		if err := check("playlist "+playlistID, overrides); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the override for a track of the given source playlist. A nil receiver has no overrides.
+func (o *TrackOverrides) Lookup(playlistID string, track models.Track) (TrackOverride, bool) {
+	if o == nil {
+		return TrackOverride{}, false
+	}
+
+	for _, overrides := range [][]TrackOverride{o.Playlists[playlistID], o.Tracks} {
+		for _, override := range overrides {
+			if override.SourceID != "" && override.SourceID == track.ID {
+				return override, true
+			}
+			if override.ISRC != "" && strings.EqualFold(override.ISRC, track.ISRC) {
+				return override, true
+			}
+		}
+	}
+	return TrackOverride{}, false
+}
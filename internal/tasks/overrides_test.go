@@ -0,0 +1,165 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestLoadTrackOverrides(t *testing.T) {
+	write := func(t *testing.T, name, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write overrides file: %v", err)
+		}
+		return path
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		path := write(t, "overrides.yaml", `
+tracks:
+  - isrc: GBARL9300135
+    dest_id: dQw4w9WgXcQ
+playlists:
+  road-trip:
+    - source_id: sp1
+      query: "Song 1 live at Wembley"
+`)
+		overrides, err := LoadTrackOverrides(path)
+		if err != nil {
+			t.Fatalf("LoadTrackOverrides() error = %v", err)
+		}
+		if len(overrides.Tracks) != 1 || overrides.Tracks[0].DestID != "dQw4w9WgXcQ" {
+			t.Errorf("unexpected track overrides %+v", overrides.Tracks)
+		}
+		if got := overrides.Playlists["road-trip"]; len(got) != 1 || got[0].Query != "Song 1 live at Wembley" {
+			t.Errorf("unexpected playlist overrides %+v", got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := write(t, "overrides.json", `{"tracks": [{"source_id": "sp1", "dest_id": "yt1"}]}`)
+		overrides, err := LoadTrackOverrides(path)
+		if err != nil {
+			t.Fatalf("LoadTrackOverrides() error = %v", err)
+		}
+		if len(overrides.Tracks) != 1 || overrides.Tracks[0].SourceID != "sp1" {
+			t.Errorf("unexpected track overrides %+v", overrides.Tracks)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		files := map[string]string{
+			"unknown field":      `{"tracks": [{"source": "sp1", "dest_id": "yt1"}]}`,
+			"no source":          `{"tracks": [{"dest_id": "yt1"}]}`,
+			"dest and query":     `{"tracks": [{"isrc": "X", "dest_id": "yt1", "query": "q"}]}`,
+			"neither":            `{"playlists": {"pl": [{"isrc": "X"}]}}`,
+			"malformed document": `{"tracks": [`,
+		}
+		for name, content := range files {
+			if _, err := LoadTrackOverrides(write(t, "overrides.json", content)); !errors.Is(err, shared.ErrInvalidInput) {
+				t.Errorf("%s: expected ErrInvalidInput, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestTrackOverrides_Lookup(t *testing.T) {
+	overrides := &TrackOverrides{
+		Tracks: []TrackOverride{
+			{SourceID: "sp1", DestID: "global"},
+			{ISRC: "usrc1", DestID: "by-isrc"},
+		},
+		Playlists: map[string][]TrackOverride{
+			"pl": {{SourceID: "sp1", DestID: "playlist"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		playlistID string
+		track      models.Track
+		want       string
+	}{
+		{"playlist override wins", "pl", models.Track{ID: "sp1"}, "playlist"},
+		{"global override for other playlists", "other", models.Track{ID: "sp1"}, "global"},
+		{"isrc ignores case", "other", models.Track{ID: "sp2", ISRC: "USRC1"}, "by-isrc"},
+		{"no override", "pl", models.Track{ID: "sp3"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			override, ok := overrides.Lookup(tt.playlistID, tt.track)
+			if ok != (tt.want != "") || override.DestID != tt.want {
+				t.Errorf("Lookup() = %+v, %v, want dest %q", override, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := (*TrackOverrides)(nil).Lookup("pl", models.Track{ID: "sp1"}); ok {
+		t.Error("expected no overrides from a nil receiver")
+	}
+}
+
+func TestPlaylistEngine_RunWithOpts_Overrides(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Road Trip"},
+				Tracks: []models.Track{
+					{ID: "sp1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "sp2", Title: "Song 2", Artists: []string{"Artist 2"}, ISRC: "USRC2"},
+					{ID: "sp3", Title: "Song 3", Artists: []string{"Artist 3"}},
+				},
+			},
+		},
+	}
+	var searched []string
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 2 (Live)|":  {ID: "yt2"},
+			"Song 3|Artist 3": {ID: "yt3"},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist"},
+		onSearch:     func(title string) { searched = append(searched, title) },
+	}
+
+	opts := TransferOpts{Overrides: &TrackOverrides{
+		Tracks: []TrackOverride{
+			{SourceID: "sp1", DestID: "pinned"},
+			{ISRC: "USRC2", Query: "Song 2 (Live)"},
+		},
+	}}
+	result, err := NewPlaylistEngine(spotify, youtube, nil).RunWithOpts(context.Background(), "playlist123", opts, nil)
+	if err != nil {
+		t.Fatalf("RunWithOpts() error = %v", err)
+	}
+
+	if result.Overridden != 2 || result.SuccessCount != 3 {
+		t.Errorf("Overridden = %d, SuccessCount = %d, want 2 and 3", result.Overridden, result.SuccessCount)
+	}
+	if len(searched) != 2 || searched[0] != "Song 2 (Live)" || searched[1] != "Song 3" {
+		t.Errorf("expected the pinned track to skip search, searched %v", searched)
+	}
+
+	var ids []string
+	for _, track := range youtube.imported.Tracks {
+		ids = append(ids, track.ID)
+	}
+	if len(ids) != 3 || ids[0] != "pinned" || ids[1] != "yt2" || ids[2] != "yt3" {
+		t.Errorf("imported tracks = %v, want [pinned yt2 yt3]", ids)
+	}
+	if result.TrackMatches[0].Method != MatchOverride || result.TrackMatches[0].Confidence != 1 {
+		t.Errorf("expected a full-confidence override match, got %+v", result.TrackMatches[0])
+	}
+	if youtube.imported.Tracks[0].Title != "Song 1" {
+		t.Errorf("expected the pinned track to keep the source metadata, got %+v", youtube.imported.Tracks[0])
+	}
+}
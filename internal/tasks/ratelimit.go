@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"cmp"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/services"
+)
+
+const (
+	// DefaultRateLimitPause is how long operations pause after a rate limit response that did not say how long
+	DefaultRateLimitPause = 30 * time.Second
+	// rateLimitRetries is how many times a rate-limited request is retried before its error is returned
+	rateLimitRetries = 3
+)
+
+// rateLimitPause holds back every worker of an operation until a service's rate limit has passed, so one 429
+// response pauses them all instead of each worker running into it in turn.
+type rateLimitPause struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// wait blocks until the current pause, if any, has passed, failing only when ctx is done first
+func (p *rateLimitPause) wait(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		remaining := time.Until(p.until)
+		p.mu.Unlock()
+		if remaining <= 0 {
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// extend pauses for d from now unless already paused for longer, reporting whether the pause was extended
+func (p *rateLimitPause) extend(d time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until := time.Now().Add(d)
+	if !until.After(p.until) {
+		return false
+	}
+	p.until = until
+	return true
+}
+
+// withRateLimit calls fn once any pause has passed. When service answers with 429, every caller sharing pause is
+// held back for the advised duration, or [DefaultRateLimitPause], which is announced with a [RateLimited] update
+// before fn is tried again, up to [rateLimitRetries] times.
+func (e *PlaylistEngine) withRateLimit(ctx context.Context, pause *rateLimitPause, progress chan<- ProgressUpdate, service string, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := pause.wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn()
+		wait, limited := services.RetryAfter(err)
+		if !limited || attempt == rateLimitRetries {
+			return err
+		}
+
+		wait = cmp.Or(wait, DefaultRateLimitPause)
+		e.logger.Warn("rate limited", "service", service, "pause", wait, "attempt", attempt+1)
+		if pause.extend(wait) {
+			e.sendProgress(progress, rateLimitedUpdate(service, wait))
+		}
+	}
+}
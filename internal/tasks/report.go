@@ -0,0 +1,150 @@
+package tasks
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/desertthunder/ytx/internal/formatter"
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// Match methods reported in [TrackMatchResult.Method]
+const (
+	MatchSearch   = "search"   // Live search on the destination service
+	MatchCache    = "cache"    // Served from the [SearchCache]
+	MatchISRC     = "isrc"     // A cached destination track with the same ISRC, from a [CounterpartFinder]
+	MatchOverride = "override" // Pinned through [TransferOpts.Overrides]
+	MatchCopy     = "copy"     // The source track itself, between two accounts of the same service
+)
+
+// durationTolerance is the duration difference at which a match's duration stops contributing to its confidence
+const durationTolerance = 30
+
+// Report builds a transfer report from the result for [formatter.WriteTransferReport].
+//
+// Tracks appear in the order they were processed, followed by the tracks left out by the ignore list.
+func (r *TransferRunResult) Report(generatedAt time.Time) formatter.TransferReport {
+	report := formatter.TransferReport{
+		GeneratedAt:     generatedAt,
+		SourceService:   r.SourceService,
+		DestService:     r.DestService,
+		DestPlaylist:    r.DestPlaylist,
+		MatchPercentage: r.MatchPercentage,
+		Tracks:          make([]formatter.TransferReportTrack, 0, len(r.TrackMatches)+len(r.Ignored)),
+		StartedAt:       r.StartedAt,
+		CompletedAt:     r.CompletedAt,
+		Phases:          r.Timing.reportPhases(),
+	}
+	if r.SourcePlaylist != nil {
+		report.SourcePlaylist = r.SourcePlaylist.Playlist
+	}
+
+	for _, match := range r.TrackMatches {
+		track := formatter.TransferReportTrack{Source: match.Original}
+		switch {
+		case match.Skipped != "":
+			track.Status = formatter.ReportSkipped
+			track.Note = match.Skipped
+		case errors.Is(match.Error, shared.ErrTrackUnavailable):
+			track.Status = formatter.ReportUnavailable
+			track.Note = match.Error.Error()
+		case match.Error != nil:
+			track.Status = formatter.ReportFailed
+			track.Note = match.Error.Error()
+		case match.Matched != nil:
+			track.Status = formatter.ReportMatched
+			track.Match = match.Matched
+			track.Method = match.Method
+			if match.SearchStep != "" && match.SearchStep != SearchTitleArtist {
+				track.Method += " (" + match.SearchStep + ")"
+			}
+			track.Confidence = match.Confidence
+		default:
+			continue
+		}
+		report.Tracks = append(report.Tracks, track)
+	}
+
+	for _, ignored := range r.Ignored {
+		report.Tracks = append(report.Tracks, formatter.TransferReportTrack{
+			Source: ignored,
+			Status: formatter.ReportSkipped,
+			Note:   "on the ignore list",
+		})
+	}
+
+	return report
+}
+
+// matchConfidence scores how closely match resembles original, from 0 to 1.
+//
+// Equal ISRCs score 1. Otherwise the score averages the word overlap of the normalized titles, the overlap of
+// the credited artists (see [shared.NormalizeArtists]), and duration closeness, leaving out the artist and duration when
+// either track lacks them.
+func matchConfidence(original, match models.Track) float64 {
+	if original.ISRC != "" && strings.EqualFold(original.ISRC, match.ISRC) {
+		return 1
+	}
+
+	scores := []float64{wordOverlap(shared.NormalizeTitle(original.Title), shared.NormalizeTitle(match.Title))}
+	if original.Artist() != "" && match.Artist() != "" {
+		scores = append(scores, artistOverlap(original.Artists, match.Artists))
+	}
+	if original.Duration > 0 && match.Duration > 0 {
+		diff := math.Abs(float64(original.Duration - match.Duration))
+		scores = append(scores, math.Max(0, 1-diff/durationTolerance))
+	}
+
+	total := 0.0
+	for _, score := range scores {
+		total += score
+	}
+	return total / float64(len(scores))
+}
+
+// artistOverlap returns the share of the shorter artist credit that the other also credits, so a duet credited
+// to one of its artists still scores 1
+func artistOverlap(a, b []string) float64 {
+	left, right := shared.NormalizeArtists(a), shared.NormalizeArtists(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+	common := 0
+	for _, artist := range left {
+		if slices.Contains(right, artist) {
+			common++
+		}
+	}
+	return float64(common) / float64(min(len(left), len(right)))
+}
+
+// wordOverlap returns the Jaccard similarity of the lowercased words of a and b, ignoring punctuation
+func wordOverlap(a, b string) float64 {
+	words := func(s string) map[string]bool {
+		set := make(map[string]bool)
+		for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			set[word] = true
+		}
+		return set
+	}
+
+	left, right := words(a), words(b)
+	if len(left) == 0 && len(right) == 0 {
+		return 1
+	}
+
+	common := 0
+	for word := range left {
+		if right[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(left)+len(right)-common)
+}
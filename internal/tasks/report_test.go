@@ -0,0 +1,75 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/formatter"
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestMatchConfidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		original models.Track
+		match    models.Track
+		min, max float64
+	}{
+		{"same ISRC", models.Track{Title: "Song", ISRC: "USRC1"}, models.Track{Title: "Other", ISRC: "usrc1"}, 1, 1},
+		{"identical metadata", models.Track{Title: "Song One", Artists: []string{"Artist"}, Duration: 200}, models.Track{Title: "song one!", Artists: []string{"ARTIST"}, Duration: 200}, 1, 1},
+		{"live version", models.Track{Title: "Song One", Artists: []string{"Artist"}, Duration: 200}, models.Track{Title: "Song One (Live)", Artists: []string{"Artist"}, Duration: 215}, 0.6, 0.8},
+		{"different track", models.Track{Title: "Song One", Artists: []string{"Artist"}, Duration: 200}, models.Track{Title: "Other", Artists: []string{"Someone"}, Duration: 100}, 0, 0},
+		{"duet credited to one artist", models.Track{Title: "Under Pressure", Artists: []string{"Queen", "David Bowie"}, Duration: 248}, models.Track{Title: "Under Pressure", Artists: []string{"David Bowie"}, Duration: 248}, 1, 1},
+		{"missing artist and duration", models.Track{Title: "Song One", Artists: []string{"Artist"}}, models.Track{Title: "Song One"}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchConfidence(tt.original, tt.match); got < tt.min || got > tt.max {
+				t.Errorf("matchConfidence() = %.2f, want between %.2f and %.2f", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestTransferRunResult_Report(t *testing.T) {
+	result := &TransferRunResult{
+		SourcePlaylist:  &models.PlaylistExport{Playlist: models.Playlist{ID: "sp_pl", Name: "Road Trip"}},
+		DestPlaylist:    &models.Playlist{ID: "yt_pl", Name: "Road Trip"},
+		SourceService:   "spotify",
+		DestService:     "youtube",
+		MatchPercentage: 50,
+		TrackMatches: []TrackMatchResult{
+			{Original: models.Track{ID: "sp1"}, Matched: &models.Track{ID: "yt1"}, Method: MatchCache, Confidence: 0.9},
+			{Original: models.Track{ID: "sp2"}, Error: shared.ErrTrackNotFound},
+			{Original: models.Track{ID: "sp3"}, Skipped: models.UnmatchableLocalFile},
+		},
+		Ignored: []models.Track{{ID: "sp4"}},
+	}
+
+	generatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := result.Report(generatedAt)
+
+	if report.SourcePlaylist.ID != "sp_pl" || report.DestPlaylist.ID != "yt_pl" || !report.GeneratedAt.Equal(generatedAt) {
+		t.Errorf("unexpected report header: %+v", report)
+	}
+
+	want := []struct{ id, status, note string }{
+		{"sp1", formatter.ReportMatched, ""},
+		{"sp2", formatter.ReportFailed, shared.ErrTrackNotFound.Error()},
+		{"sp3", formatter.ReportSkipped, models.UnmatchableLocalFile},
+		{"sp4", formatter.ReportSkipped, "on the ignore list"},
+	}
+	if len(report.Tracks) != len(want) {
+		t.Fatalf("expected %d report tracks, got %+v", len(want), report.Tracks)
+	}
+	for i, w := range want {
+		track := report.Tracks[i]
+		if track.Source.ID != w.id || track.Status != w.status || track.Note != w.note {
+			t.Errorf("track %d = %+v, want %s %s %q", i, track, w.id, w.status, w.note)
+		}
+	}
+	if report.Tracks[0].Method != MatchCache || report.Tracks[0].Confidence != 0.9 || report.Tracks[0].Match.ID != "yt1" {
+		t.Errorf("expected the match details to be carried over, got %+v", report.Tracks[0])
+	}
+}
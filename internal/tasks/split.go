@@ -0,0 +1,191 @@
+package tasks
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tracing"
+)
+
+// Rules [SplitOpts.By] splits a playlist by
+const (
+	SplitByArtist = "artist" // One playlist per primary artist
+	SplitByDecade = "decade" // One playlist per decade of album release
+)
+
+// ParseSplitRule parses a split rule, artist or decade; an empty rule splits by size alone.
+func ParseSplitRule(s string) (string, error) {
+	switch rule := strings.ToLower(strings.TrimSpace(s)); rule {
+	case "", SplitByArtist, SplitByDecade:
+		return rule, nil
+	default:
+		return "", fmt.Errorf("%w: split rule '%s' (want artist or decade)", shared.ErrInvalidArgument, s)
+	}
+}
+
+// SplitOpts configures how [PlaylistEngine.Split] partitions a playlist.
+type SplitOpts struct {
+	By        string // SplitByArtist, SplitByDecade, or empty to split by MaxTracks alone
+	MaxTracks int    // Most tracks per playlist; larger partitions are split into numbered parts (0: no limit)
+	MinTracks int    // Partitions with fewer tracks are gathered into one "Other" partition (0: keep every one)
+	DryRun    bool   // Only compute the partitions, creating no playlists
+}
+
+// SplitPartition is one playlist a split creates.
+type SplitPartition struct {
+	Name     string           // Playlist name, e.g. "Road Trip - 1990s (2/3)"
+	Key      string           // Artist or decade the tracks share; empty for "Other" and size-only parts
+	Tracks   []models.Track   // In source playlist order
+	Playlist *models.Playlist // Created playlist; nil on a dry run or when it was not created
+}
+
+// SplitResult is the result of [PlaylistEngine.Split].
+type SplitResult struct {
+	Source     *models.PlaylistExport
+	Partitions []SplitPartition
+}
+
+// Split partitions the playlist playlistID of srv by opts and, unless DryRun is set, creates a playlist on srv for
+// each partition. The source playlist is left as it is.
+//
+// Creating stops at the first playlist that fails; the partial result has the playlists created so far.
+func (e *PlaylistEngine) Split(ctx context.Context, srv services.Service, playlistID string, opts SplitOpts, progress chan<- ProgressUpdate) (result *SplitResult, err error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	if srv == nil {
+		return nil, fmt.Errorf("%w: service not initialized", shared.ErrServiceUnavailable)
+	}
+	if _, err := ParseSplitRule(opts.By); err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "split", tracing.String("ytx.source_id", playlistID), tracing.String("ytx.split_by", opts.By))
+	defer func() { span.RecordError(err); span.End() }()
+
+	e.sendProgress(progress, fetchingSourceUpdate(1, 1, srv.Name()))
+	source, err := srv.ExportPlaylist(ctx, playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export playlist: %w", err)
+	}
+
+	result = &SplitResult{Source: source, Partitions: SplitPlaylist(source, opts)}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for i := range result.Partitions {
+		partition := &result.Partitions[i]
+		e.sendProgress(progress, createDestinationUpdate(i+1, len(result.Partitions), srv.Name()))
+		created, err := srv.ImportPlaylist(ctx, &models.PlaylistExport{
+			Playlist: models.Playlist{
+				Name:        partition.Name,
+				Description: fmt.Sprintf("Split from %s", source.Playlist.Name),
+				Public:      source.Playlist.Public,
+			},
+			Tracks: partition.Tracks,
+		})
+		// A playlist created before adding its tracks failed is kept in the result, so it can be found and completed
+		partition.Playlist = created
+		if err != nil {
+			return result, fmt.Errorf("%w: failed to create playlist %q: %w", shared.ErrAPIRequest, partition.Name, err)
+		}
+		e.cachePlaylist(ctx, e.serviceKey(srv), *created, partition.Tracks)
+		e.sendProgress(progress, createPlaylistUpdate(i+1, len(result.Partitions), created))
+	}
+	return result, nil
+}
+
+// SplitPlaylist partitions the tracks of export by opts, without creating anything.
+//
+// Artist partitions are ordered by name and decade partitions by decade, followed by tracks whose artist or release
+// date is unknown, then the "Other" partition of those below MinTracks.
+func SplitPlaylist(export *models.PlaylistExport, opts SplitOpts) []SplitPartition {
+	type group struct {
+		key    string
+		label  string
+		order  int // Sorts decades chronologically; unknown keys last
+		tracks []models.Track
+	}
+
+	var groups []*group
+	byKey := map[string]*group{}
+	for _, track := range export.Tracks {
+		key, label, order := "", "", 0
+		switch opts.By {
+		case SplitByArtist:
+			key, label = strings.ToLower(strings.TrimSpace(track.Artist())), strings.TrimSpace(track.Artist())
+			if key == "" {
+				label, order = "Unknown artist", 1
+			}
+		case SplitByDecade:
+			if year := track.ReleaseYear(); year > 0 {
+				key, label, order = fmt.Sprint(year/10*10), fmt.Sprintf("%ds", year/10*10), year/10*10
+			} else {
+				label, order = "Unknown decade", 1<<30
+			}
+		}
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{key: key, label: label, order: order}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.tracks = append(g.tracks, track)
+	}
+
+	slices.SortStableFunc(groups, func(a, b *group) int {
+		return cmp.Or(cmp.Compare(a.order, b.order), cmp.Compare(strings.ToLower(a.label), strings.ToLower(b.label)))
+	})
+
+	// Partitions too small to keep are gathered, in playlist order, into one "Other" partition
+	if opts.By != "" && opts.MinTracks > 0 {
+		other := &group{label: "Other"}
+		kept := groups[:0]
+		for _, g := range groups {
+			if len(g.tracks) < opts.MinTracks {
+				other.tracks = append(other.tracks, g.tracks...)
+				continue
+			}
+			kept = append(kept, g)
+		}
+		groups = kept
+		if len(other.tracks) > 0 {
+			order := map[string]int{}
+			for i, track := range export.Tracks {
+				order[track.ID+"\x00"+track.Title] = i
+			}
+			slices.SortStableFunc(other.tracks, func(a, b models.Track) int {
+				return cmp.Compare(order[a.ID+"\x00"+a.Title], order[b.ID+"\x00"+b.Title])
+			})
+			groups = append(groups, other)
+		}
+	}
+
+	var partitions []SplitPartition
+	for _, g := range groups {
+		name := export.Playlist.Name
+		if g.label != "" {
+			name += " - " + g.label
+		}
+		chunks := [][]models.Track{g.tracks}
+		if opts.MaxTracks > 0 && len(g.tracks) > opts.MaxTracks {
+			chunks = slices.Collect(slices.Chunk(g.tracks, opts.MaxTracks))
+		}
+		for i, chunk := range chunks {
+			partition := SplitPartition{Name: name, Key: g.key, Tracks: chunk}
+			if len(chunks) > 1 {
+				partition.Name = fmt.Sprintf("%s (%d/%d)", name, i+1, len(chunks))
+			}
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions
+}
@@ -0,0 +1,183 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// splittingService creates a playlist for every import, failing from the failAt-th one when it is set
+type splittingService struct {
+	*mockService
+	created []*models.PlaylistExport
+	failAt  int
+}
+
+func (s *splittingService) ImportPlaylist(ctx context.Context, export *models.PlaylistExport) (*models.Playlist, error) {
+	if s.failAt > 0 && len(s.created)+1 >= s.failAt {
+		return nil, errors.New("quota exceeded")
+	}
+	s.created = append(s.created, export)
+	return &models.Playlist{ID: fmt.Sprintf("new-%d", len(s.created)), Name: export.Playlist.Name}, nil
+}
+
+func splitFixture() *models.PlaylistExport {
+	return &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "big", Name: "Mix", Public: true},
+		Tracks: []models.Track{
+			{ID: "1", Title: "A", Artists: []string{"Zed"}, ReleaseDate: "1994-05-01"},
+			{ID: "2", Title: "B", Artists: []string{"abba"}, ReleaseDate: "1976"},
+			{ID: "3", Title: "C", Artists: []string{"Zed"}, ReleaseDate: "2001-11"},
+			{ID: "4", Title: "D", Artists: []string{"ABBA"}, ReleaseDate: "1979-01-01"},
+			{ID: "5", Title: "E", ReleaseDate: ""},
+			{ID: "6", Title: "F", Artists: []string{"Moby"}, ReleaseDate: "1999"},
+		},
+	}
+}
+
+func partitionNames(partitions []SplitPartition) []string {
+	names := make([]string, len(partitions))
+	for i, partition := range partitions {
+		names[i] = partition.Name
+	}
+	return names
+}
+
+func TestSplitPlaylist(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   SplitOpts
+		want   []string
+		tracks [][]string
+	}{
+		{
+			name:   "by artist ignores case and sorts by name",
+			opts:   SplitOpts{By: SplitByArtist},
+			want:   []string{"Mix - abba", "Mix - Moby", "Mix - Zed", "Mix - Unknown artist"},
+			tracks: [][]string{{"B", "D"}, {"F"}, {"A", "C"}, {"E"}},
+		},
+		{
+			name:   "by decade in chronological order",
+			opts:   SplitOpts{By: SplitByDecade},
+			want:   []string{"Mix - 1970s", "Mix - 1990s", "Mix - 2000s", "Mix - Unknown decade"},
+			tracks: [][]string{{"B", "D"}, {"A", "F"}, {"C"}, {"E"}},
+		},
+		{
+			name:   "small groups gathered into other",
+			opts:   SplitOpts{By: SplitByArtist, MinTracks: 2},
+			want:   []string{"Mix - abba", "Mix - Zed", "Mix - Other"},
+			tracks: [][]string{{"B", "D"}, {"A", "C"}, {"E", "F"}},
+		},
+		{
+			name:   "by size alone",
+			opts:   SplitOpts{MaxTracks: 4},
+			want:   []string{"Mix (1/2)", "Mix (2/2)"},
+			tracks: [][]string{{"A", "B", "C", "D"}, {"E", "F"}},
+		},
+		{
+			name:   "large groups split into parts",
+			opts:   SplitOpts{By: SplitByDecade, MaxTracks: 1, MinTracks: 2},
+			want:   []string{"Mix - 1970s (1/2)", "Mix - 1970s (2/2)", "Mix - 1990s (1/2)", "Mix - 1990s (2/2)", "Mix - Other (1/2)", "Mix - Other (2/2)"},
+			tracks: [][]string{{"B"}, {"D"}, {"A"}, {"F"}, {"C"}, {"E"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partitions := SplitPlaylist(splitFixture(), tt.opts)
+			if got := partitionNames(partitions); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("partitions = %q, want %q", got, tt.want)
+			}
+			for i, partition := range partitions {
+				if !sameTitles(partition.Tracks, tt.tracks[i]...) {
+					t.Errorf("%s tracks = %v, want %v", partition.Name, titles(partition.Tracks), tt.tracks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSplitRule(t *testing.T) {
+	for input, want := range map[string]string{"": "", "Artist": SplitByArtist, " decade ": SplitByDecade} {
+		if got, err := ParseSplitRule(input); err != nil || got != want {
+			t.Errorf("ParseSplitRule(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSplitRule("genre"); !errors.Is(err, shared.ErrInvalidArgument) {
+		t.Errorf("ParseSplitRule(genre) error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestPlaylistEngine_Split(t *testing.T) {
+	newService := func() *splittingService {
+		return &splittingService{mockService: &mockService{
+			name:            "Spotify",
+			playlistExports: map[string]*models.PlaylistExport{"big": splitFixture()},
+		}}
+	}
+
+	t.Run("creates a playlist per partition", func(t *testing.T) {
+		srv := newService()
+		cacher := &mockPlaylistCacher{}
+		engine := NewPlaylistEngine(nil, nil, nil)
+		engine.SetPlaylistCacher(cacher)
+
+		result, err := engine.Split(context.Background(), srv, "big", SplitOpts{By: SplitByDecade}, nil)
+		if err != nil {
+			t.Fatalf("Split() error = %v", err)
+		}
+		if len(srv.created) != 4 || len(result.Partitions) != 4 {
+			t.Fatalf("created %d playlists for %d partitions, want 4", len(srv.created), len(result.Partitions))
+		}
+		first := srv.created[0]
+		if first.Playlist.Name != "Mix - 1970s" || first.Playlist.Description != "Split from Mix" || !first.Playlist.Public {
+			t.Errorf("first playlist = %+v", first.Playlist)
+		}
+		if !sameTitles(first.Tracks, "B", "D") {
+			t.Errorf("first playlist tracks = %v, want [B D]", titles(first.Tracks))
+		}
+		if result.Partitions[3].Playlist == nil || result.Partitions[3].Playlist.ID != "new-4" {
+			t.Errorf("last partition playlist = %+v, want new-4", result.Partitions[3].Playlist)
+		}
+		if len(cacher.cached) != 4 {
+			t.Errorf("cached %d playlists, want 4", len(cacher.cached))
+		}
+	})
+
+	t.Run("dry run creates nothing", func(t *testing.T) {
+		srv := newService()
+		result, err := NewPlaylistEngine(nil, nil, nil).Split(context.Background(), srv, "big", SplitOpts{MaxTracks: 5, DryRun: true}, nil)
+		if err != nil {
+			t.Fatalf("Split() error = %v", err)
+		}
+		if len(srv.created) != 0 {
+			t.Errorf("dry run created %d playlists", len(srv.created))
+		}
+		if got := partitionNames(result.Partitions); fmt.Sprint(got) != "[Mix (1/2) Mix (2/2)]" {
+			t.Errorf("partitions = %q", got)
+		}
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		srv := newService()
+		srv.failAt = 2
+		result, err := NewPlaylistEngine(nil, nil, nil).Split(context.Background(), srv, "big", SplitOpts{By: SplitByArtist}, nil)
+		if !errors.Is(err, shared.ErrAPIRequest) {
+			t.Fatalf("Split() error = %v, want ErrAPIRequest", err)
+		}
+		if len(srv.created) != 1 || result.Partitions[0].Playlist == nil || result.Partitions[1].Playlist != nil {
+			t.Errorf("created %d playlists, partitions = %+v", len(srv.created), result.Partitions)
+		}
+	})
+
+	t.Run("missing playlist", func(t *testing.T) {
+		_, err := NewPlaylistEngine(nil, nil, nil).Split(context.Background(), newService(), "nope", SplitOpts{By: SplitByArtist}, nil)
+		if !errors.Is(err, shared.ErrPlaylistNotFound) {
+			t.Errorf("Split() error = %v, want ErrPlaylistNotFound", err)
+		}
+	})
+}
@@ -5,12 +5,24 @@
 package tasks
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
-
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/desertthunder/ytx/internal/formatter"
 	"github.com/desertthunder/ytx/internal/models"
 	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tracing"
 )
 
 // APIClient defines the interface for making API requests to the proxy.
@@ -20,20 +32,40 @@ type APIClient interface {
 
 // TrackMatchResult represents the result of attempting to match a single track.
 type TrackMatchResult struct {
-	Original models.Track  // Original track from source
-	Matched  *models.Track // Matched track (nil if not found)
-	Error    error         // Error if match failed
+	Original   models.Track   // Original track from source
+	Matched    *models.Track  // Matched track (nil if not found)
+	Error      error          // Error if match failed
+	Skipped    string         // Why the track was not searched because it can never match (see [models.Track.Unmatchable])
+	Method     string         // How the match was found ([MatchSearch], [MatchCache], [MatchISRC], [MatchOverride], or [MatchCopy]); empty when unmatched
+	Confidence float64        // Similarity between Original and Matched from 0 to 1; zero when unmatched
+	Rejected   []models.Track // Cached and searched candidates refused for a duration too far from Original's, or as region-unavailable
+	SearchStep string         // Step of the search fallback chain that found a [MatchSearch] match, such as [SearchTitle]
 }
 
 // TransferRunResult contains all data from a full transfer operation.
 type TransferRunResult struct {
 	SourcePlaylist  *models.PlaylistExport // Source playlist with tracks
-	DestPlaylist    *models.Playlist       // Created destination playlist
+	DestPlaylist    *models.Playlist       // Created destination playlist, or the first part of a split transfer
+	DestParts       []*models.Playlist     // Every created destination playlist, in order, when the transfer was split
 	TrackMatches    []TrackMatchResult     // Individual track match results
 	SuccessCount    int                    // Number of successfully matched tracks
 	FailedCount     int                    // Number of failed matches
+	SkippedCount    int                    // Unmatchable tracks (podcast episodes, local files) that were not searched
 	TotalTracks     int                    // Total tracks processed
-	MatchPercentage float64                // Success rate as percentage
+	MatchPercentage float64                // Success rate as percentage of the matchable tracks
+	CachedSearches  int                    // Matches served from the search cache or by ISRC from the track cache
+	Overridden      int                    // Tracks matched through [TransferOpts.Overrides]
+	Ignored         []models.Track         // Source tracks skipped because they are on the ignore list
+	SourceService   string                 // Service key of the source playlist ("spotify" or "youtube")
+	DestService     string                 // Service key of the destination playlist
+	MigrationID     string                 // Migration history ID (empty when history is disabled)
+	CoverCopied     bool                   // Whether the source cover image was copied to the destination
+	CoverError      error                  // Why the cover could not be copied, when [TransferOpts.CopyCover] is set
+	Cancelled       bool                   // Whether the context ended first; counts cover the tracks searched until then
+	Warnings        []string               // Caveats about the destination, e.g. collaborators that were not carried over
+	Duplicates      int                    // Repeats of a source track left out under [TransferOpts.Dedupe]
+
+	Timing // When the transfer ran and how long it spent fetching, searching, and creating the playlist
 }
 
 // ComparisonResult contains track comparison details between two playlists.
@@ -41,17 +73,52 @@ type ComparisonResult struct {
 	SourcePlaylist *models.PlaylistExport // Source playlist
 	DestPlaylist   *models.PlaylistExport // Destination playlist
 	MatchedCount   int                    // Tracks found in both
+	Matched        []models.Track         // Source tracks found in dest
 	MissingInDest  []models.Track         // Tracks in source but not in dest
 	ExtraInDest    []models.Track         // Tracks in dest but not in source
+	Ignored        []models.Track         // Tracks from either playlist left out because they are on the ignore list
 }
 
 // TransferDiffResult contains the results of comparing two playlists.
 type TransferDiffResult struct {
-	Comparison ComparisonResult
+	Comparison   ComparisonResult
+	SourceCached bool // Source playlist was loaded from the local cache (offline diffs only)
+	DestCached   bool // Destination playlist was loaded from the local cache (offline diffs only)
+
+	Timing // When the diff ran and how long it spent loading each playlist and comparing them
+}
+
+// PushResult contains the outcome of adding missing tracks to an existing playlist.
+type PushResult struct {
+	TrackMatches []TrackMatchResult // Individual track match results
+	AddedCount   int                // Number of tracks added to the playlist
+	FailedCount  int                // Number of tracks with no match on the destination service
+	SkippedCount int                // Unmatchable tracks (podcast episodes, local files) that were not searched
+}
+
+// DiffTarget identifies a playlist for [PlaylistEngine.DiffTargets] and offline diffs.
+type DiffTarget struct {
+	ServiceKey string                 // Cache service name ("spotify", "youtube")
+	PlaylistID string                 // Service-specific playlist ID
+	Service    services.Service       // Optional: used for a live fetch when the playlist is not cached
+	Export     *models.PlaylistExport // Optional: a snapshot, e.g. read from an export file, compared instead of fetching
+}
+
+// name describes where the target's playlist comes from, for progress messages
+func (t DiffTarget) name() string {
+	switch {
+	case t.Export != nil:
+		return "export file"
+	case t.Service != nil:
+		return t.Service.Name()
+	default:
+		return t.ServiceKey
+	}
 }
 
 // EndpointResult represents the result of fetching data from a single API endpoint.
 type EndpointResult struct {
+	Name     string // Section name, as used for the [DumpData] JSON key
 	Endpoint string
 	Data     any
 	Error    error
@@ -69,6 +136,10 @@ type DumpResult struct {
 	UploadedSongs  any              // Uploaded songs
 	UploadedAlbums any              // Uploaded albums
 	Errors         []EndpointResult // Failed endpoint fetches
+
+	// When the dump ran and how long each endpoint took. Endpoints are fetched concurrently, so their phases
+	// overlap and can add up to more than the dump's duration.
+	Timing
 }
 
 // PlaylistExportJob represents a single playlist to be exported in a bulk operation.
@@ -83,6 +154,7 @@ type PlaylistExportResult struct {
 	PlaylistName string   // Playlist name for display
 	Success      bool     // Whether export succeeded
 	Files        []string // Paths to created files
+	CoverImage   string   // Path to the saved cover image, also in Files; empty when none was saved
 	Error        error    // Error if export failed
 }
 
@@ -94,19 +166,21 @@ type BulkExportResult struct {
 	Results           []PlaylistExportResult // Individual export results
 	OutputDirectory   string                 // Base output directory
 	ManifestPath      string                 // Path to export manifest JSON
+	ArchivePath       string                 // Path to the zip or tar.gz of the output directory, when archived
 }
 
 type DumpData struct {
-	Health         any   `json:"health"`
-	Playlists      any   `json:"playlists,omitempty"`
-	Songs          any   `json:"songs,omitempty"`
-	Albums         any   `json:"albums,omitempty"`
-	Artists        any   `json:"artists,omitempty"`
-	LikedSongs     any   `json:"liked_songs,omitempty"`
-	History        any   `json:"history,omitempty"`
-	UploadedSongs  any   `json:"uploaded_songs,omitempty"`
-	UploadedAlbums any   `json:"uploaded_albums,omitempty"`
-	Errors         []any `json:"errors,omitempty"`
+	Health         any     `json:"health"`
+	Playlists      any     `json:"playlists,omitempty"`
+	Songs          any     `json:"songs,omitempty"`
+	Albums         any     `json:"albums,omitempty"`
+	Artists        any     `json:"artists,omitempty"`
+	LikedSongs     any     `json:"liked_songs,omitempty"`
+	History        any     `json:"history,omitempty"`
+	UploadedSongs  any     `json:"uploaded_songs,omitempty"`
+	UploadedAlbums any     `json:"uploaded_albums,omitempty"`
+	Errors         []any   `json:"errors,omitempty"`
+	Timing         *Timing `json:"timing,omitempty"` // When the dump ran and how long each endpoint took
 }
 
 type endpointOperation struct {
@@ -115,8 +189,16 @@ type endpointOperation struct {
 	target  *any
 	phase   Phase
 	message string
+	timeout time.Duration
+	paged   bool // Library endpoints that take limit and continuation parameters
 }
 
+// Per-request limits for [PlaylistEngine.Dump], unless overridden with [PlaylistEngine.SetDumpTimeout]
+const (
+	DefaultDumpTimeout        = time.Minute
+	DefaultDumpHistoryTimeout = 10 * time.Minute // Listening history is often far larger than the rest
+)
+
 // SyncEngine defines operations for syncing playlists between services.
 type SyncEngine interface {
 	// Run performs a full Spotify → YouTube Music sync by fetching source playlist, searches for tracks, creates destination playlist.
@@ -131,16 +213,104 @@ type SyncEngine interface {
 
 // TrackCacher defines the interface for caching tracks to automatically cache tracks during transfer operations.
 type TrackCacher interface {
-	CacheTrack(service, serviceID string, track models.Track) error
+	CacheTrack(ctx context.Context, service, serviceID string, track models.Track) error
 }
 
+// BulkTrackCacher is an optional extension of [TrackCacher] that caches many tracks in one transaction.
+//
+// When the configured cacher implements it, the engine caches whole playlists with a single call.
+type BulkTrackCacher interface {
+	BulkCacheTracks(ctx context.Context, service string, tracks []models.Track) error
+}
+
+// CounterpartFinder is an optional extension of [TrackCacher] that translates a track to another service from
+// the cache by its ISRC, which identifies a recording across services.
+//
+// When the configured cacher implements it, the engine matches tracks with a cached counterpart without searching.
+// FindCounterpart returns an error when service has no cached track with the ISRC.
+type CounterpartFinder interface {
+	FindCounterpart(ctx context.Context, service, isrc string) (*models.Track, error)
+}
+
+// PlaylistCacher defines the interface for persisting a playlist with its ordered tracks after a transfer.
+type PlaylistCacher interface {
+	CachePlaylist(ctx context.Context, service string, playlist models.Playlist, tracks []models.Track) error
+}
+
+// PlaylistStore defines the interface for loading cached playlists with their ordered tracks.
+type PlaylistStore interface {
+	LoadPlaylist(ctx context.Context, service, playlistID string) (*models.PlaylistExport, error)
+}
+
+// SearchCache defines the interface for reusing YouTube Music search results across transfers.
+//
+// Lookup returns an error on a miss or an expired entry, in which case the engine searches live.
+type SearchCache interface {
+	Lookup(ctx context.Context, query models.Track) (*models.Track, error)
+	Store(ctx context.Context, query, match models.Track) error
+}
+
+// MigrationRecorder defines the interface for recording transfers in the migration history.
+//
+// StartTransfer is called once the source playlist is fetched and returns the ID of the running migration.
+// RecordProgress is called at most once per [ProgressPersistInterval] while tracks are matched, and
+// FinishTransfer records the outcome.
+type MigrationRecorder interface {
+	StartTransfer(ctx context.Context, record models.TransferRecord) (string, error)
+	RecordProgress(ctx context.Context, migrationID string, current, total int) error
+	FinishTransfer(ctx context.Context, migrationID string, record models.TransferRecord) error
+}
+
+// IgnoreList defines the interface for skipping tracks, such as podcast episodes or local files, during transfers and diffs.
+//
+// Tracks whose check fails are treated as not ignored.
+type IgnoreList interface {
+	Ignores(ctx context.Context, track models.Track) (bool, error)
+}
+
+// Notifier defines the interface for announcing finished transfers, such as through a [WebhookNotifier].
+//
+// NotifyTransfer is called once per transfer, successful or not, after the migration history is updated.
+type Notifier interface {
+	NotifyTransfer(ctx context.Context, summary TransferSummary) error
+}
+
+// MetricsRecorder defines the interface for instrumenting the engine, such as with a Prometheus registry.
+//
+// Searches are observed for live lookups only, not for search cache hits or overrides.
+type MetricsRecorder interface {
+	ObserveSearch(service string, duration time.Duration, matched bool)
+	ObserveTransfer(status string, duration time.Duration) // status is [TransferCompleted] or [TransferFailed]
+	ObserveQueueDepth(depth int)
+}
+
+// ProgressPersistInterval throttles how often a running transfer's progress is written to the migration history.
+//
+// The first and last tracks are always written.
+const ProgressPersistInterval = time.Second
+
 // PlaylistEngine implements SyncEngine for playlist operations.
-// Contains dependencies on music services, API client, and optional track caching.
+// Contains dependencies on music services, API client, and optional track and playlist caching.
 type PlaylistEngine struct {
-	spotify     services.Service
-	youtube     services.Service
-	api         APIClient
-	trackCacher TrackCacher // Optional: tracks are cached automatically if provided
+	spotify             services.Service
+	youtube             services.Service
+	api                 APIClient
+	trackCacher         TrackCacher        // Optional: tracks are cached automatically if provided
+	playlistCacher      PlaylistCacher     // Optional: transferred playlists are cached if provided
+	searchCache         SearchCache        // Optional: search results are reused if provided
+	recorder            MigrationRecorder  // Optional: transfers are recorded in the migration history if provided
+	ignoreList          IgnoreList         // Optional: listed tracks are skipped if provided
+	notifier            Notifier           // Optional: finished transfers are announced if provided
+	metrics             MetricsRecorder    // Optional: searches and transfers are measured if provided
+	maxDurationDelta    time.Duration      // Matches further from the source's duration are refused; disabled while zero
+	explicitPreference  ExplicitPreference // Version picked when a search finds explicit and clean ones
+	flagUnavailable     bool               // Region-unavailable matches are refused and reported as such
+	dumpTimeout         time.Duration      // Per-request Dump limit; the defaults apply while zero
+	dumpFull            bool               // Dump follows library continuations until exhausted
+	nameTemplate        string             // Destination name template when [TransferOpts.Name] is blank
+	descriptionTemplate string             // Destination description template when [TransferOpts.Description] is blank
+	logger              *log.Logger        // Discards everything unless set
+	downloadImage       func(url string) ([]byte, error)
 }
 
 func (r TransferRunResult) GetInfo() string {
@@ -151,160 +321,928 @@ func (r TransferRunResult) GetInfo() string {
 		r.DestPlaylist.Name,
 		r.SuccessCount,
 		r.SuccessCount,
-		r.TotalTracks,
+		r.MatchableTracks(),
 		r.MatchPercentage,
 	)
 }
 
+// MatchableTracks returns the number of tracks that were searched or will be, excluding unmatchable tracks.
+func (r TransferRunResult) MatchableTracks() int {
+	return r.TotalTracks - r.SkippedCount
+}
+
 // NewPlaylistEngine creates a new PlaylistEngine with the provided services.
 func NewPlaylistEngine(spotify, youtube services.Service, api APIClient) *PlaylistEngine {
 	return &PlaylistEngine{
-		spotify: spotify,
-		youtube: youtube,
-		api:     api,
+		spotify:       spotify,
+		youtube:       youtube,
+		api:           api,
+		logger:        log.New(io.Discard),
+		downloadImage: formatter.DownloadImage,
 	}
 }
 
+// SetLogger sets the logger for track matches and finished transfers. A nil logger discards them.
+func (e *PlaylistEngine) SetLogger(logger *log.Logger) {
+	if logger == nil {
+		logger = log.New(io.Discard)
+	}
+	e.logger = logger
+}
+
+// SetDumpTimeout limits how long each request made by [PlaylistEngine.Dump] may take, overriding
+// [DefaultDumpTimeout] and [DefaultDumpHistoryTimeout]. Zero restores the defaults.
+func (e *PlaylistEngine) SetDumpTimeout(timeout time.Duration) {
+	e.dumpTimeout = timeout
+}
+
+// SetDumpFull makes [PlaylistEngine.Dump] request library endpoints a page at a time and follow their
+// continuations until exhausted, rather than take the single, possibly truncated, response the proxy gives.
+func (e *PlaylistEngine) SetDumpFull(full bool) {
+	e.dumpFull = full
+}
+
 // SetTrackCacher enables automatic track caching for this engine.
 // Tracks fetched from Spotify and YouTube will be cached transparently.
 func (e *PlaylistEngine) SetTrackCacher(cacher TrackCacher) {
 	e.trackCacher = cacher
 }
 
-// sendProgress sends a progress update through the channel without blocking.
-// Uses select with default to ensure progress reporting never blocks execution.
+// SetPlaylistCacher enables playlist persistence for this engine.
+// Source and destination playlists are cached with their ordered tracks after a successful transfer.
+func (e *PlaylistEngine) SetPlaylistCacher(cacher PlaylistCacher) {
+	e.playlistCacher = cacher
+}
+
+// SetSearchCache enables search result caching for this engine.
+// Cached matches are used instead of calling SearchTrack, and live matches are stored for later runs.
+func (e *PlaylistEngine) SetSearchCache(cache SearchCache) {
+	e.searchCache = cache
+}
+
+// SetMaxDurationDelta refuses search matches whose duration differs from the source track's by more than delta,
+// unless [TransferOpts.MaxDurationDelta] is set. Zero, the default, accepts any duration.
+func (e *PlaylistEngine) SetMaxDurationDelta(delta time.Duration) {
+	e.maxDurationDelta = delta
+}
+
+// SetExplicitPreference picks the explicit or clean version of a track when a search finds both, unless
+// [TransferOpts.Explicit] is set. [NoExplicitPreference], the default, takes the best-ranked result.
+func (e *PlaylistEngine) SetExplicitPreference(preference ExplicitPreference) {
+	e.explicitPreference = preference
+}
+
+// SetFlagUnavailable refuses matches the destination reports as unavailable in the account's region, searching on
+// for a playable one and failing with [shared.ErrTrackUnavailable] when there is none. Off by default, when such
+// matches are added like any other; [TransferOpts.FlagUnavailable] turns it on for one transfer.
+func (e *PlaylistEngine) SetFlagUnavailable(flag bool) {
+	e.flagUnavailable = flag
+}
+
+// SetMigrationRecorder enables migration history for this engine.
+// Every transfer that fetches its source playlist is recorded with live progress, whether or not it succeeds.
+func (e *PlaylistEngine) SetMigrationRecorder(recorder MigrationRecorder) {
+	e.recorder = recorder
+}
+
+// SetIgnoreList enables the ignore list for this engine.
+// Listed source tracks are not searched during transfers, and listed tracks are left out of diffs.
+func (e *PlaylistEngine) SetIgnoreList(list IgnoreList) {
+	e.ignoreList = list
+}
+
+// SetNotifier enables transfer notifications for this engine.
+// Every transfer started with [PlaylistEngine.RunWithOpts] is announced when it finishes, including failed ones.
+func (e *PlaylistEngine) SetNotifier(notifier Notifier) {
+	e.notifier = notifier
+}
+
+// SetMetricsRecorder enables instrumentation for this engine.
+func (e *PlaylistEngine) SetMetricsRecorder(metrics MetricsRecorder) {
+	e.metrics = metrics
+}
+
+// filterIgnored splits tracks into those to keep and those on the ignore list, preserving order.
+func (e *PlaylistEngine) filterIgnored(ctx context.Context, tracks []models.Track) ([]models.Track, []models.Track) {
+	if e.ignoreList == nil {
+		return tracks, nil
+	}
+
+	kept := make([]models.Track, 0, len(tracks))
+	var ignored []models.Track
+	for _, track := range tracks {
+		if skip, err := e.ignoreList.Ignores(ctx, track); err == nil && skip {
+			ignored = append(ignored, track)
+			continue
+		}
+		kept = append(kept, track)
+	}
+	return kept, ignored
+}
+
+// sendProgress timestamps a progress update and sends it through the channel without blocking.
+// Operations send on a [progressDispatcher], which queues updates for a slow consumer, so one is only dropped
+// when the dispatcher itself has fallen behind.
 func (e *PlaylistEngine) sendProgress(progress chan<- ProgressUpdate, update ProgressUpdate) {
 	if progress == nil {
 		return
 	}
+	if update.Time.IsZero() {
+		update.Time = time.Now()
+	}
 	select {
 	case progress <- update:
 		// Sent successfully
 	default:
-		// Channel full or closed, skip this update
+		// Dispatcher behind, skip this update
 	}
 }
 
 // cacheTrack attempts to cache a track. Failures are silent to avoid disrupting operations.
-func (e *PlaylistEngine) cacheTrack(service, serviceID string, track models.Track) {
+func (e *PlaylistEngine) cacheTrack(ctx context.Context, service, serviceID string, track models.Track) {
 	if e.trackCacher == nil {
 		return
 	}
 	// Cache failures are silent - they should not disrupt playlist operations
-	_ = e.trackCacher.CacheTrack(service, serviceID, track)
+	_ = e.trackCacher.CacheTrack(ctx, service, serviceID, track)
 }
 
-// cacheTracks attempts to cache multiple tracks. Failures are silent.
-func (e *PlaylistEngine) cacheTracks(service string, tracks []models.Track) {
+// cacheTracks attempts to cache multiple tracks, batching when the cacher supports it. Failures are silent.
+func (e *PlaylistEngine) cacheTracks(ctx context.Context, service string, tracks []models.Track) {
 	if e.trackCacher == nil {
 		return
 	}
+	if bulk, ok := e.trackCacher.(BulkTrackCacher); ok {
+		_ = bulk.BulkCacheTracks(ctx, service, tracks)
+		return
+	}
 	for _, track := range tracks {
-		e.cacheTrack(service, track.ID, track)
+		e.cacheTrack(ctx, service, track.ID, track)
 	}
 }
 
-// Run performs a full Spotify → YouTube Music playlist sync.
-func (e *PlaylistEngine) Run(ctx context.Context, srcID string, progress chan<- ProgressUpdate) (*TransferRunResult, error) {
-	if e.spotify == nil {
-		return nil, fmt.Errorf("%w: Spotify service not initialized", shared.ErrServiceUnavailable)
+// cachePlaylist attempts to cache a playlist with its ordered tracks. Failures are silent.
+func (e *PlaylistEngine) cachePlaylist(ctx context.Context, service string, playlist models.Playlist, tracks []models.Track) {
+	if e.playlistCacher == nil {
+		return
+	}
+	_ = e.playlistCacher.CachePlaylist(ctx, service, playlist, tracks)
+}
+
+// transferRoute is the direction of a playlist transfer.
+type transferRoute struct {
+	source    services.Service
+	dest      services.Service
+	sourceKey string // Service key used for caching and the migration history
+	destKey   string
+}
+
+// sameService reports whether the route reads from and writes to the same provider, e.g. two Spotify accounts
+func (r transferRoute) sameService() bool {
+	return r.sourceKey == r.destKey
+}
+
+// route resolves the source and destination services for a transfer. Accounts given in opts replace the engine's
+// service on their side of the route.
+func (e *PlaylistEngine) route(opts TransferOpts) transferRoute {
+	route := transferRoute{source: e.spotify, dest: e.youtube, sourceKey: "spotify", destKey: "youtube"}
+	if opts.Reverse {
+		route = transferRoute{source: e.youtube, dest: e.spotify, sourceKey: "youtube", destKey: "spotify"}
+	}
+	if opts.Source != nil {
+		route.source, route.sourceKey = opts.Source.Service, opts.Source.ServiceKey
 	}
-	if e.youtube == nil {
-		return nil, fmt.Errorf("%w: YouTube Music service not initialized", shared.ErrServiceUnavailable)
+	if opts.Dest != nil {
+		route.dest, route.destKey = opts.Dest.Service, opts.Dest.ServiceKey
 	}
+	return route
+}
 
-	result := &TransferRunResult{}
+// searchTrack finds the destination match for a track, consulting the caches first: a cached track of the
+// destination service with the same ISRC when the track cacher is a [CounterpartFinder], then the search cache.
+//
+// The search cache only holds YouTube Music matches, so it is skipped when transferring to Spotify. Neither cache
+// records whether a track is explicit, so both are skipped, and nothing is cached, under an explicit preference.
+//
+// Live searches walk the fallback chain of [searchSteps] until one finds an acceptable candidate. When
+// criteria.maxDelta is positive, matches whose duration differs from the track's by more are refused, as are
+// region-unavailable ones when criteria.flagUnavailable is set; refused candidates are returned as rejected. A
+// destination that is a [services.TrackSearcher] has its further candidates tried in rank order, and the first
+// of them in the preferred version is taken when there is one. Cache write failures are silent.
+func (e *PlaylistEngine) searchTrack(ctx context.Context, route transferRoute, track models.Track, criteria matchCriteria) (searchOutcome, error) {
+	preferring := criteria.explicit != NoExplicitPreference
+	if finder, ok := e.trackCacher.(CounterpartFinder); ok && track.ISRC != "" && !preferring {
+		if match, err := finder.FindCounterpart(ctx, route.destKey, track.ISRC); err == nil && match != nil {
+			return searchOutcome{match: match, method: MatchISRC}, nil
+		}
+	}
 
-	e.sendProgress(progress, fetchingSourceUpdate(1, 1))
+	searchCache := e.searchCache
+	if route.destKey != "youtube" || preferring {
+		searchCache = nil
+	}
 
-	srcPlaylist, err := e.spotify.ExportPlaylist(ctx, srcID)
-	if err != nil {
-		playlists, playlistsErr := e.spotify.GetPlaylists(ctx)
-		if playlistsErr != nil {
-			return nil, fmt.Errorf("%w: failed to get playlists: %v", shared.ErrAPIRequest, playlistsErr)
+	outcome := searchOutcome{method: MatchSearch}
+	if searchCache != nil {
+		if match, err := searchCache.Lookup(ctx, track); err == nil && match != nil {
+			if criteria.accepts(track, *match) {
+				return searchOutcome{match: match, method: MatchCache}, nil
+			}
+			outcome.rejected = append(outcome.rejected, *match)
 		}
+	}
+
+	ctx, span := tracing.Start(ctx, "transfer.search_track", tracing.String("ytx.title", track.Title), tracing.String("ytx.artist", track.Artist()))
+	defer span.End()
 
-		var matchedID string
-		for _, pl := range playlists {
-			if pl.Name == srcID {
-				matchedID = pl.ID
-				break
+	var err error
+	for _, step := range searchSteps(route.dest, track) {
+		var candidates []models.Track
+		candidates, err = e.searchCandidates(ctx, route, step, criteria.ranked(track))
+		if err != nil && !errors.Is(err, shared.ErrTrackNotFound) {
+			break
+		}
+
+		var accepted []models.Track
+		for _, candidate := range candidates {
+			if criteria.accepts(track, candidate) && step.accepts(track, candidate) {
+				accepted = append(accepted, candidate)
+			} else {
+				outcome.rejected = append(outcome.rejected, candidate)
 			}
 		}
+		if len(accepted) == 0 {
+			continue
+		}
 
-		if matchedID == "" {
-			return nil, fmt.Errorf("%w: no playlist found with name '%s'", shared.ErrPlaylistNotFound, srcID)
+		match := accepted[0]
+		if i := slices.IndexFunc(accepted, criteria.explicit.prefers); i >= 0 {
+			match = accepted[i]
 		}
+		// Only what a plain title and artist search finds is what a later search would find
+		if searchCache != nil && step.name == SearchTitleArtist {
+			_ = searchCache.Store(ctx, track, match)
+		}
+		outcome.match, outcome.step = &match, step.name
+		return outcome, nil
+	}
 
-		srcPlaylist, err = e.spotify.ExportPlaylist(ctx, matchedID)
-		if err != nil {
-			return nil, fmt.Errorf("%w: failed to export playlist: %v", shared.ErrAPIRequest, err)
+	switch {
+	case err != nil && !errors.Is(err, shared.ErrTrackNotFound):
+	case criteria.flagUnavailable && slices.ContainsFunc(outcome.rejected, func(t models.Track) bool { return t.Unavailable }):
+		err = fmt.Errorf("%w: %d result(s) found, none accepted: %s",
+			shared.ErrTrackUnavailable, len(outcome.rejected), criteria.rejections(track, outcome.rejected))
+	case len(outcome.rejected) > 0:
+		err = fmt.Errorf("%w: %d result(s) found, none accepted: %s",
+			shared.ErrTrackNotFound, len(outcome.rejected), criteria.rejections(track, outcome.rejected))
+	default:
+		err = fmt.Errorf("%w: %s - %s", shared.ErrTrackNotFound, track.Title, track.Artist())
+	}
+	span.RecordError(err)
+	return outcome, err
+}
+
+// searchCandidates runs one step of the fallback chain, returning its candidates best first. Ranked searches on a
+// [services.TrackSearcher] return several; other searches return the destination's best match.
+func (e *PlaylistEngine) searchCandidates(ctx context.Context, route transferRoute, step searchStep, ranked bool) ([]models.Track, error) {
+	searchStart := time.Now()
+	var (
+		candidates []models.Track
+		err        error
+	)
+	if searcher, ok := route.dest.(services.ISRCSearcher); ok && step.name == SearchISRC {
+		candidates, err = searcher.SearchISRC(ctx, step.isrc)
+	} else if searcher, ok := route.dest.(services.TrackSearcher); ok && ranked {
+		candidates, err = searcher.SearchTracks(ctx, step.title, step.artist, searchCandidates)
+	} else {
+		var match *models.Track
+		if match, err = route.dest.SearchTrack(ctx, step.title, step.artist); err == nil {
+			candidates = []models.Track{*match}
 		}
 	}
+	if err == nil && len(candidates) == 0 {
+		err = fmt.Errorf("%w: %s - %s", shared.ErrTrackNotFound, step.title, step.artist)
+	}
+	e.observeSearch(route.destKey, searchStart, err)
+	return candidates, err
+}
+
+// searchCandidates is how many ranked results are tried against the duration guard and explicit preference
+const searchCandidates = 5
+
+// Steps of the search fallback chain reported in [TrackMatchResult.SearchStep], in the order they are tried
+const (
+	SearchISRC           = "isrc"           // The source track's ISRC, on an [services.ISRCSearcher] destination
+	SearchTitleArtist    = "title_artist"   // Title and primary artist
+	SearchTitle          = "title"          // Title alone, accepting only results with the same normalized title
+	SearchTransliterated = "transliterated" // Title and artist spelled in Latin letters by [shared.Transliterate]
+)
+
+// searchStep is one query of the search fallback chain
+type searchStep struct {
+	name          string
+	title, artist string
+	isrc          string
+}
+
+// searchSteps returns the fallback chain for track, leaving out steps that would repeat an earlier query
+func searchSteps(dest services.Service, track models.Track) []searchStep {
+	var steps []searchStep
+	if _, ok := dest.(services.ISRCSearcher); ok && track.ISRC != "" {
+		steps = append(steps, searchStep{name: SearchISRC, isrc: track.ISRC})
+	}
+	steps = append(steps, searchStep{name: SearchTitleArtist, title: track.Title, artist: track.Artist()})
+	if track.Artist() != "" {
+		steps = append(steps, searchStep{name: SearchTitle, title: track.Title})
+	}
+	if title, artist := shared.Transliterate(track.Title), shared.Transliterate(track.Artist()); title != track.Title || artist != track.Artist() {
+		steps = append(steps, searchStep{name: SearchTransliterated, title: title, artist: artist})
+	}
+	return steps
+}
+
+// accepts reports whether a candidate found by the step can match track: a title-only search, which finds
+// anyone's recordings, has to find the same title
+func (s searchStep) accepts(track, candidate models.Track) bool {
+	return s.name != SearchTitle || shared.NormalizeTitle(candidate.Title) == shared.NormalizeTitle(track.Title)
+}
+
+// searchOutcome is what [PlaylistEngine.searchTrack] found for a track
+type searchOutcome struct {
+	match    *models.Track
+	method   string         // [MatchISRC], [MatchCache], or [MatchSearch]
+	step     string         // Fallback step that found a [MatchSearch] match
+	rejected []models.Track // Candidates refused by the [matchCriteria]
+}
+
+// matchCriteria are the requirements and preferences a search match is held to
+type matchCriteria struct {
+	maxDelta        time.Duration      // Matches further from the source's duration are refused; unchecked while zero
+	explicit        ExplicitPreference // Version taken when candidates include both
+	flagUnavailable bool               // Region-unavailable matches are refused
+}
+
+// accepts reports whether match meets the criteria for track
+func (c matchCriteria) accepts(track, match models.Track) bool {
+	return durationMatches(track, match, c.maxDelta) && !(c.flagUnavailable && match.Unavailable)
+}
+
+// rejections describes why the rejected candidates were refused for track, counting them by reason, e.g.
+// "2 outside 5s of 3:45, 1 unavailable in this region"
+func (c matchCriteria) rejections(track models.Track, rejected []models.Track) string {
+	var outside, unavailable, retitled int
+	for _, candidate := range rejected {
+		switch {
+		case !durationMatches(track, candidate, c.maxDelta):
+			outside++
+		case c.flagUnavailable && candidate.Unavailable:
+			unavailable++
+		default:
+			// The title-only search step refuses results with another title
+			retitled++
+		}
+	}
+
+	var reasons []string
+	if outside > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d outside %s of %s", outside, c.maxDelta, shared.FormatDuration(track.Duration)))
+	}
+	if unavailable > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d unavailable in this region", unavailable))
+	}
+	if retitled > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d with another title", retitled))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// ranked reports whether searches for track should fetch several candidates to choose from
+func (c matchCriteria) ranked(track models.Track) bool {
+	return c.explicit != NoExplicitPreference || c.flagUnavailable || (c.maxDelta > 0 && track.Duration > 0)
+}
+
+// durationMatches reports whether match's duration is within maxDelta of track's. Tracks without a duration,
+// and every track when maxDelta is not positive, match.
+func durationMatches(track, match models.Track, maxDelta time.Duration) bool {
+	if maxDelta <= 0 || track.Duration <= 0 || match.Duration <= 0 {
+		return true
+	}
+	delta := time.Duration(track.Duration-match.Duration) * time.Second
+	return delta.Abs() <= maxDelta
+}
+
+// ExplicitPreference picks between the explicit and clean versions of a track when a search finds both.
+// It is a preference rather than a requirement: a track only found in the other version still matches.
+type ExplicitPreference string
+
+const (
+	NoExplicitPreference ExplicitPreference = ""         // Take the best-ranked result
+	PreferExplicit       ExplicitPreference = "explicit" // Take the explicit version
+	PreferClean          ExplicitPreference = "clean"    // Take the clean (radio) version
+)
+
+// ParseExplicitPreference parses "explicit", "clean", or "none" (also blank), ignoring case.
+func ParseExplicitPreference(s string) (ExplicitPreference, error) {
+	switch preference := ExplicitPreference(strings.ToLower(strings.TrimSpace(s))); preference {
+	case PreferExplicit, PreferClean:
+		return preference, nil
+	case NoExplicitPreference, "none":
+		return NoExplicitPreference, nil
+	}
+	return "", fmt.Errorf("%w: explicit preference %q (want explicit, clean, or none)", shared.ErrInvalidArgument, s)
+}
+
+// prefers reports whether the preference favors track; nothing is favored without a preference
+func (p ExplicitPreference) prefers(track models.Track) bool {
+	switch p {
+	case PreferExplicit:
+		return track.Explicit
+	case PreferClean:
+		return !track.Explicit
+	}
+	return false
+}
+
+// overrideTrack matches a track through an override: a pinned destination ID is used as is, and a custom query is
+// searched as the title. Override matches skip the search cache.
+func (e *PlaylistEngine) overrideTrack(ctx context.Context, route transferRoute, override TrackOverride, track models.Track) (*models.Track, error) {
+	if override.DestID != "" {
+		return &models.Track{
+			ID:       override.DestID,
+			Title:    track.Title,
+			Artists:  track.Artists,
+			Album:    track.Album,
+			Duration: track.Duration,
+			ISRC:     track.ISRC,
+		}, nil
+	}
+
+	match, err := route.dest.SearchTrack(ctx, override.Query, "")
+	if err != nil {
+		return nil, fmt.Errorf("override query '%s': %w", override.Query, err)
+	}
+	return match, nil
+}
+
+// transferRecord summarizes a transfer's current state for the migration history
+func transferRecord(result *TransferRunResult, startedAt time.Time, runErr error) models.TransferRecord {
+	record := models.TransferRecord{
+		SourceService:  result.SourceService,
+		SourcePlaylist: result.SourcePlaylist.Playlist,
+		TargetService:  result.DestService,
+		TargetPlaylist: result.DestPlaylist,
+		TracksTotal:    result.TotalTracks,
+		TracksMigrated: result.SuccessCount,
+		StartedAt:      startedAt,
+	}
+	if runErr != nil {
+		record.Error = runErr.Error()
+	}
+	for _, match := range result.TrackMatches {
+		if match.Error != nil {
+			record.Failures = append(record.Failures, match.Original)
+		}
+	}
+	return record
+}
+
+// startTransfer attempts to record a running transfer in the migration history, setting result.MigrationID.
+// Failures are silent and leave the transfer unrecorded.
+func (e *PlaylistEngine) startTransfer(ctx context.Context, result *TransferRunResult, startedAt time.Time) {
+	if e.recorder == nil {
+		return
+	}
+	if id, err := e.recorder.StartTransfer(ctx, transferRecord(result, startedAt, nil)); err == nil {
+		result.MigrationID = id
+	}
+}
+
+// recordProgress attempts to persist a running transfer's progress. Failures are silent.
+func (e *PlaylistEngine) recordProgress(ctx context.Context, result *TransferRunResult, current int) {
+	if e.recorder == nil || result.MigrationID == "" {
+		return
+	}
+	_ = e.recorder.RecordProgress(ctx, result.MigrationID, current, result.TotalTracks)
+}
+
+// finishTransfer attempts to record a transfer's outcome in the migration history. Failures are silent.
+func (e *PlaylistEngine) finishTransfer(ctx context.Context, result *TransferRunResult, startedAt time.Time, runErr error) {
+	if e.recorder == nil || result == nil || result.MigrationID == "" {
+		return
+	}
+
+	record := transferRecord(result, startedAt, runErr)
+	record.CompletedAt = time.Now()
+	_ = e.recorder.FinishTransfer(ctx, result.MigrationID, record)
+}
+
+// TransferOpts customizes the destination playlist created by [PlaylistEngine.RunWithOpts].
+type TransferOpts struct {
+	Name        string // Destination playlist name template (default: source playlist name); see [PlaylistTemplateData]
+	Description string // Destination playlist description template (default: "Migrated from {source service}: {name}")
+	Public      bool   // Create a public playlist instead of a private one
+	Reverse     bool   // Transfer from YouTube Music to Spotify
+	CopyCover   bool   // Copy the source playlist's cover image to the destination playlist
+	Split       bool   // Split matches beyond the destination's playlist size limit into "Name (1/N)", ... playlists
+
+	// MaxDurationDelta refuses matches whose duration differs from the source track's by more, so an extended
+	// mix is not matched to a radio edit; zero uses the engine's ([PlaylistEngine.SetMaxDurationDelta])
+	MaxDurationDelta time.Duration
+
+	// Explicit picks the explicit or clean version of a track when a search finds both; empty uses the engine's
+	// ([PlaylistEngine.SetExplicitPreference])
+	Explicit ExplicitPreference
+
+	// FlagUnavailable refuses region-unavailable matches, reporting tracks only found that way as unavailable
+	// instead of adding them ([PlaylistEngine.SetFlagUnavailable])
+	FlagUnavailable bool
+
+	// Dedupe transfers each track once, leaving out later repeats of it in the source playlist
+	Dedupe bool
+
+	Overrides *TrackOverrides // Optional: pinned matches consulted before searching
+
+	// Source and Dest replace the engine's services on their side of the transfer, so playlists can be copied
+	// between two accounts of the same provider, e.g. Spotify → Spotify; nil uses the engine's
+	Source *TransferAccount
+	Dest   *TransferAccount
+}
+
+// TransferAccount is a service signed in to a particular account, such as a second profile's Spotify.
+type TransferAccount struct {
+	ServiceKey string // "spotify" or "youtube"; used for caching and the migration history
+	Service    services.Service
+}
+
+// Run performs a full Spotify → YouTube Music playlist sync into a private playlist named after the source.
+func (e *PlaylistEngine) Run(ctx context.Context, srcID string, progress chan<- ProgressUpdate) (*TransferRunResult, error) {
+	return e.RunWithOpts(ctx, srcID, TransferOpts{}, progress)
+}
+
+// RunWithOpts performs a full playlist sync, recording it in the migration history when enabled.
+//
+// Transfers run Spotify → YouTube Music unless opts.Reverse is set, or opts.Source and opts.Dest name other
+// accounts. Between two accounts of the same provider, tracks are copied by ID instead of searched for.
+func (e *PlaylistEngine) RunWithOpts(ctx context.Context, srcID string, opts TransferOpts, progress chan<- ProgressUpdate) (*TransferRunResult, error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	timer := startTiming()
+	startedAt := timer.timing.StartedAt
+	ctx, span := tracing.Start(ctx, "transfer", tracing.String("ytx.playlist_id", srcID))
+	defer span.End()
+
+	result, err := e.run(ctx, srcID, opts, progress, timer)
+	if result != nil {
+		result.Timing = timer.finish()
+	}
+	span.RecordError(err)
+	e.finishTransfer(context.WithoutCancel(ctx), result, startedAt, err)
+	e.notifyTransfer(context.WithoutCancel(ctx), srcID, result, startedAt, err)
+	e.observeTransfer(startedAt, err)
+	if err != nil {
+		e.logger.Warn("transfer failed", "playlist", srcID, "duration", time.Since(startedAt), "err", err)
+	} else {
+		e.logger.Info("transfer finished", "playlist", srcID, "matched", result.SuccessCount, "failed", result.FailedCount, "duration", time.Since(startedAt))
+	}
+	return result, err
+}
+
+// run performs the transfer for [PlaylistEngine.RunWithOpts].
+func (e *PlaylistEngine) run(ctx context.Context, srcID string, opts TransferOpts, progress chan<- ProgressUpdate, timer *phaseTimer) (*TransferRunResult, error) {
+	route := e.route(opts)
+	if route.source == nil {
+		return nil, fmt.Errorf("%w: source %s service not initialized", shared.ErrServiceUnavailable, route.sourceKey)
+	}
+	if route.dest == nil {
+		return nil, fmt.Errorf("%w: destination %s service not initialized", shared.ErrServiceUnavailable, route.destKey)
+	}
+	result := &TransferRunResult{SourceService: route.sourceKey, DestService: route.destKey}
+
+	// Each phase gets a child span of the transfer; the deferred End covers early returns
+	transferCtx := ctx
+	ctx, phase := tracing.Start(transferCtx, "transfer.fetch_source", tracing.String("ytx.service", route.sourceKey))
+	defer func() { phase.End() }()
+	timer.enter(FetchSource)
+
+	e.sendProgress(progress, fetchingSourceUpdate(1, 1, route.source.Name()))
+
+	srcPlaylist, err := exportPlaylist(ctx, route.source, srcID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rendered before any searching so a broken template fails the transfer at once
+	destination, err := e.Destination(srcPlaylist, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	total := len(srcPlaylist.Tracks)
+	tracks, ignored := e.filterIgnored(ctx, srcPlaylist.Tracks)
+	if opts.Dedupe {
+		var repeats []models.Track
+		tracks, repeats = dedupeTracks(tracks)
+		result.Duplicates = len(repeats)
+	}
+	total := len(tracks)
 	result.SourcePlaylist = srcPlaylist
+	result.Ignored = ignored
+	if warning := collaborativeWarning(srcPlaylist.Playlist, destination, route); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+		e.logger.Warn("transferring a collaborative playlist", "playlist", srcPlaylist.Playlist.ID, "dest", route.destKey)
+	}
+	for _, track := range tracks {
+		if opts.skipReason(srcPlaylist.Playlist.ID, track) != "" {
+			result.SkippedCount++
+		}
+	}
 	result.TotalTracks = total
 
-	e.cacheTracks("spotify", srcPlaylist.Tracks)
+	// Checked before searching, against the tracks that could match, so an oversized transfer fails at once
+	limit := maxPlaylistSize(route.dest)
+	if matchable := result.MatchableTracks(); limit > 0 && matchable > limit && !opts.Split {
+		return nil, fmt.Errorf("%w: %d tracks to transfer but %s playlists hold at most %d; split them into several playlists (--split)",
+			shared.ErrInvalidInput, matchable, route.dest.Name(), limit)
+	}
+	e.startTransfer(ctx, result, timer.timing.StartedAt)
+
+	e.cacheTracks(ctx, route.sourceKey, srcPlaylist.Tracks)
 	e.sendProgress(progress, foundPlaylistUpdate(1, 1, srcPlaylist))
-	e.sendProgress(progress, searchTracksUpdate(0, total, nil))
+
+	phase.End()
+	ctx, phase = tracing.Start(transferCtx, "transfer.match_tracks", tracing.String("ytx.service", route.destKey), tracing.Int("ytx.tracks", total))
+	timer.enter(SearchTracks)
+	e.sendProgress(progress, searchTracksUpdate(0, total, nil, route.dest.Name()))
 
 	matches := make([]TrackMatchResult, total)
 	successCount := 0
+	var lastPersisted time.Time
+	pause := &rateLimitPause{}
+
+	for i, track := range tracks {
+		// Checked before every track so a cancelled transfer makes no further requests
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return cancelledTransfer(result, matches[:i], successCount, ctxErr)
+		}
+		e.sendProgress(progress, searchTracksUpdate(i+1, total, &track, route.dest.Name()))
 
-	for i, track := range srcPlaylist.Tracks {
-		e.sendProgress(progress, searchTracksUpdate(i+1, total, &track))
+		if reason := opts.skipReason(srcPlaylist.Playlist.ID, track); reason != "" {
+			matches[i] = TrackMatchResult{Original: track, Skipped: reason}
+			if i+1 == total {
+				e.recordProgress(ctx, result, i+1)
+			}
+			continue
+		}
 
-		ytTrack, err := e.youtube.SearchTrack(ctx, track.Title, track.Artist)
+		var destTrack *models.Track
+		var outcome searchOutcome
+		override, overridden := opts.Overrides.Lookup(srcPlaylist.Playlist.ID, track)
+		err = e.withRateLimit(ctx, pause, progress, route.dest.Name(), func() (err error) {
+			if overridden {
+				destTrack, err = e.overrideTrack(ctx, route, override, track)
+			} else if route.sameService() {
+				copied := track
+				destTrack, outcome.method = &copied, MatchCopy
+			} else {
+				outcome, err = e.searchTrack(ctx, route, track, matchCriteria{
+					maxDelta:        cmp.Or(opts.MaxDurationDelta, e.maxDurationDelta),
+					explicit:        cmp.Or(opts.Explicit, e.explicitPreference),
+					flagUnavailable: opts.FlagUnavailable || e.flagUnavailable,
+				})
+				destTrack = outcome.match
+			}
+			return err
+		})
+		method := outcome.method
+		if overridden {
+			method = MatchOverride
+			result.Overridden++
+		}
+		// A search cut short by cancellation is not counted as a failed match
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return cancelledTransfer(result, matches[:i], successCount, ctxErr)
+		}
+		if method == MatchCache || method == MatchISRC {
+			result.CachedSearches++
+		}
 		matches[i] = TrackMatchResult{
-			Original: track,
-			Matched:  ytTrack,
-			Error:    err,
+			Original:   track,
+			Matched:    destTrack,
+			Error:      err,
+			Rejected:   outcome.rejected,
+			SearchStep: outcome.step,
 		}
 
 		if err == nil {
+			matches[i].Method = method
+			matches[i].Confidence = matchConfidence(track, *destTrack)
 			successCount++
-			e.cacheTrack("youtube", ytTrack.ID, *ytTrack)
+			e.cacheTrack(ctx, route.destKey, destTrack.ID, *destTrack)
+			e.logger.Debug("matched track", "title", track.Title, "artist", track.Artist(), "match", destTrack.ID, "method", method)
+		} else {
+			e.logger.Debug("no match for track", "title", track.Title, "artist", track.Artist(), "err", err)
 		}
-	}
 
-	result.TrackMatches = matches
-	result.SuccessCount = successCount
-	result.FailedCount = total - successCount
-	if result.TotalTracks > 0 {
-		result.MatchPercentage = float64(successCount) / float64(result.TotalTracks) * 100
+		if now := time.Now(); i+1 == total || now.Sub(lastPersisted) >= ProgressPersistInterval {
+			e.recordProgress(ctx, result, i+1)
+			lastPersisted = now
+		}
 	}
 
+	summarizeMatches(result, matches, successCount)
+
 	if successCount == 0 {
 		return result, fmt.Errorf("no tracks were matched - cannot create empty playlist")
 	}
 
-	e.sendProgress(progress, createDestinationUpdate(1, 1))
+	// Checked last before creating the destination so a cancelled transfer leaves nothing behind
+	if err := ctx.Err(); err != nil {
+		result.Cancelled = true
+		return result, fmt.Errorf("transfer cancelled before creating playlist: %w", err)
+	}
+
+	phase.End()
+	ctx, phase = tracing.Start(transferCtx, "transfer.create_playlist", tracing.String("ytx.service", route.destKey))
+	timer.enter(CreatePlaylist)
+	e.sendProgress(progress, createDestinationUpdate(1, 1, route.dest.Name()))
 
+	// Matches are added in source order and keep the source's added_at, so the destination mirrors the source
 	matchedTracks := make([]models.Track, 0, successCount)
 	for _, match := range matches {
 		if match.Matched != nil {
-			matchedTracks = append(matchedTracks, *match.Matched)
+			matched := *match.Matched
+			matched.AddedAt = match.Original.AddedAt
+			matchedTracks = append(matchedTracks, matched)
+		}
+	}
+	parts := splitTracks(matchedTracks, limit)
+	for i, tracks := range parts {
+		playlist := destination
+		if len(parts) > 1 {
+			playlist.Name = fmt.Sprintf("%s (%d/%d)", destination.Name, i+1, len(parts))
+		}
+
+		importedPl, err := route.dest.ImportPlaylist(ctx, &models.PlaylistExport{Playlist: playlist, Tracks: tracks})
+		// A playlist created before adding its tracks failed is kept in the result, so it can be found and completed
+		if importedPl != nil {
+			if result.DestPlaylist == nil {
+				result.DestPlaylist = importedPl
+			}
+			if len(parts) > 1 {
+				result.DestParts = append(result.DestParts, importedPl)
+			}
+		}
+		if err != nil {
+			return result, fmt.Errorf("%w: failed to create playlist %q: %w", shared.ErrAPIRequest, playlist.Name, err)
 		}
+		if opts.CopyCover {
+			e.sendProgress(progress, copyCoverUpdate(i+1, len(parts), route.dest.Name()))
+			if result.CoverError = e.copyCover(ctx, route, srcPlaylist.Playlist.ID, importedPl.ID); result.CoverError == nil {
+				result.CoverCopied = true
+			}
+		}
+		e.cachePlaylist(ctx, route.destKey, *importedPl, tracks)
+		e.sendProgress(progress, createPlaylistUpdate(i+1, len(parts), importedPl))
+	}
+	e.cachePlaylist(ctx, route.sourceKey, srcPlaylist.Playlist, srcPlaylist.Tracks)
+	return result, nil
+}
+
+// maxPlaylistSize returns the most tracks a playlist on svc can hold, or zero when it has no known limit
+func maxPlaylistSize(svc services.Service) int {
+	if limiter, ok := svc.(services.PlaylistSizeLimiter); ok {
+		return limiter.MaxPlaylistSize()
 	}
-	destExport := &models.PlaylistExport{
-		Playlist: models.Playlist{
-			Name:        srcPlaylist.Playlist.Name,
-			Description: fmt.Sprintf("Migrated from Spotify: %s", srcPlaylist.Playlist.Name),
-			Public:      false,
-		},
-		Tracks: matchedTracks,
+	return 0
+}
+
+// splitTracks splits tracks into consecutive parts of at most limit tracks; a limit of zero keeps them whole
+func splitTracks(tracks []models.Track, limit int) [][]models.Track {
+	if limit <= 0 || len(tracks) <= limit {
+		return [][]models.Track{tracks}
+	}
+	parts := make([][]models.Track, 0, (len(tracks)+limit-1)/limit)
+	for start := 0; start < len(tracks); start += limit {
+		parts = append(parts, tracks[start:min(start+limit, len(tracks))])
 	}
+	return parts
+}
 
-	importedPl, err := e.youtube.ImportPlaylist(ctx, destExport)
+// copyCover downloads the source playlist's cover image and uploads it to the destination playlist.
+//
+// Fails when either service does not support playlist covers, so the transfer itself never does.
+func (e *PlaylistEngine) copyCover(ctx context.Context, route transferRoute, srcID, destID string) error {
+	source, ok := route.source.(services.PlaylistCoverSource)
+	if !ok {
+		return fmt.Errorf("%w: %s does not expose playlist covers", shared.ErrNotImplemented, route.source.Name())
+	}
+	dest, ok := route.dest.(services.PlaylistCoverSetter)
+	if !ok {
+		return fmt.Errorf("%w: %s does not support uploading playlist covers", shared.ErrNotImplemented, route.dest.Name())
+	}
+
+	imageURL, err := source.CoverImageURL(ctx, srcID)
+	if err != nil {
+		return fmt.Errorf("failed to get cover image: %w", err)
+	}
+	image, err := e.downloadImage(imageURL)
 	if err != nil {
-		return result, fmt.Errorf("%w: failed to create playlist: %v", shared.ErrAPIRequest, err)
+		return err
 	}
+	if err := dest.SetPlaylistCover(ctx, destID, image, http.DetectContentType(image)); err != nil {
+		return fmt.Errorf("%w: %v", shared.ErrAPIRequest, err)
+	}
+	return nil
+}
 
-	result.DestPlaylist = importedPl
-	e.sendProgress(progress, createPlaylistUpdate(1, 1, importedPl))
-	return result, nil
+// skipReason returns why a source track is skipped without searching, or an empty string when it is searched.
+//
+// Unmatchable tracks such as podcast episodes and local files are skipped unless an override pins their match.
+func (o TransferOpts) skipReason(playlistID string, track models.Track) string {
+	reason := track.Unmatchable()
+	if reason == "" {
+		return ""
+	}
+	if _, ok := o.Overrides.Lookup(playlistID, track); ok {
+		return ""
+	}
+	return reason
+}
+
+// exportPlaylist exports the playlist of service with the given ID or, failing that, the first one named idOrName
+func exportPlaylist(ctx context.Context, service services.Service, idOrName string) (*models.PlaylistExport, error) {
+	export, err := service.ExportPlaylist(ctx, idOrName)
+	if err == nil {
+		return export, nil
+	}
+
+	playlist, err := playlistNamed(ctx, service, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil {
+		return nil, fmt.Errorf("%w: no playlist found with name '%s'", shared.ErrPlaylistNotFound, idOrName)
+	}
+
+	export, err = service.ExportPlaylist(ctx, playlist.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to export playlist: %w", shared.ErrAPIRequest, err)
+	}
+	return export, nil
+}
+
+// playlistNamed returns the first of service's playlists named name, or nil when it has none
+func playlistNamed(ctx context.Context, service services.Service, name string) (*models.Playlist, error) {
+	playlists, err := service.GetPlaylists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get playlists: %w", shared.ErrAPIRequest, err)
+	}
+	for _, playlist := range playlists {
+		if playlist.Name == name {
+			return &playlist, nil
+		}
+	}
+	return nil, nil
+}
+
+// collaborativeWarning explains what a transfer of a collaborative playlist leaves behind: collaborators are never
+// invited to the destination, which only Spotify can make collaborative. It is empty for other playlists.
+func collaborativeWarning(source, dest models.Playlist, route transferRoute) string {
+	switch {
+	case !source.Collaborative:
+		return ""
+	case route.destKey != "spotify":
+		return fmt.Sprintf("%q is collaborative, but %s playlists are created without collaborators", source.Name, route.dest.Name())
+	case !dest.Collaborative:
+		return fmt.Sprintf("%q is collaborative, but public playlists can't be, so the copy is not", source.Name)
+	default:
+		return fmt.Sprintf("%q is collaborative; its collaborators need to be invited to the copy again", source.Name)
+	}
+}
+
+// cancelledTransfer marks result as cancelled, summarizing the matches of the tracks searched before ctx ended.
+func cancelledTransfer(result *TransferRunResult, matches []TrackMatchResult, successCount int, err error) (*TransferRunResult, error) {
+	summarizeMatches(result, matches, successCount)
+	result.Cancelled = true
+	return result, fmt.Errorf("transfer cancelled after %d of %d tracks: %w", len(matches), result.TotalTracks, err)
+}
+
+// summarizeMatches sets the match results and counts of result from the tracks searched so far.
+//
+// FailedCount only covers searched tracks, so it stays accurate for a cancelled transfer. Skipped
+// tracks count as neither matched nor failed.
+func summarizeMatches(result *TransferRunResult, matches []TrackMatchResult, successCount int) {
+	result.TrackMatches = matches
+	result.SuccessCount = successCount
+	result.FailedCount = 0
+	for _, match := range matches {
+		if match.Error != nil {
+			result.FailedCount++
+		}
+	}
+	if matchable := result.MatchableTracks(); matchable > 0 {
+		result.MatchPercentage = float64(successCount) / float64(matchable) * 100
+	}
 }
 
 // Diff compares two playlists and identifies differences.
@@ -312,146 +1250,348 @@ func (e *PlaylistEngine) Diff(ctx context.Context, sourceSvc, destSvc services.S
 	if sourceSvc == nil || destSvc == nil {
 		return nil, fmt.Errorf("%w: service not initialized", shared.ErrServiceUnavailable)
 	}
+	return e.DiffTargets(ctx, DiffTarget{PlaylistID: sourceID, Service: sourceSvc}, DiffTarget{PlaylistID: destID, Service: destSvc}, progress)
+}
+
+// DiffTargets compares two playlists, each taken from the target's Export when set or fetched live through its
+// Service otherwise, so an exported snapshot can be compared against a live playlist.
+func (e *PlaylistEngine) DiffTargets(ctx context.Context, source, dest DiffTarget, progress chan<- ProgressUpdate) (result *TransferDiffResult, err error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	if (source.Export == nil && source.Service == nil) || (dest.Export == nil && dest.Service == nil) {
+		return nil, fmt.Errorf("%w: service not initialized", shared.ErrServiceUnavailable)
+	}
+
+	ctx, span := tracing.Start(ctx, "diff", tracing.String("ytx.source_id", source.PlaylistID), tracing.String("ytx.dest_id", dest.PlaylistID))
+	defer func() { span.RecordError(err); span.End() }()
+
+	result = &TransferDiffResult{}
+	timer := startTiming()
+
+	timer.enter(FetchSource)
+	e.sendProgress(progress, fetchSourceUpdate(1, 2, source.name()))
+	sourceExport := source.Export
+	if sourceExport == nil {
+		if sourceExport, err = source.Service.ExportPlaylist(ctx, source.PlaylistID); err != nil {
+			return nil, fmt.Errorf("failed to export source playlist: %w", err)
+		}
+	}
+
+	timer.enter(FetchDest)
+	e.sendProgress(progress, fetchDestUpdate(2, 2, dest.name()))
+	destExport := dest.Export
+	if destExport == nil {
+		if destExport, err = dest.Service.ExportPlaylist(ctx, dest.PlaylistID); err != nil {
+			return nil, fmt.Errorf("failed to export destination playlist: %w", err)
+		}
+	}
+
+	timer.enter(Compare)
+	e.sendProgress(progress, buildDestMapUpdate(1, 2))
+	e.sendProgress(progress, missingTrackUpdate(2, 2))
+	result.Comparison = e.compare(ctx, sourceExport, destExport)
+	result.Timing = timer.finish()
+
+	return result, nil
+}
+
+// DiffOffline compares two playlists using cached data from store, falling back to a live fetch
+// through the target's service only when a playlist is not cached.
+func (e *PlaylistEngine) DiffOffline(ctx context.Context, store PlaylistStore, source, dest DiffTarget, progress chan<- ProgressUpdate) (*TransferDiffResult, error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	if store == nil {
+		return nil, fmt.Errorf("%w: playlist store not initialized", shared.ErrServiceUnavailable)
+	}
 
 	result := &TransferDiffResult{}
+	timer := startTiming()
 
-	e.sendProgress(progress, fetchSourceUpdate(1, 2, sourceSvc.Name()))
-	sourceExport, err := sourceSvc.ExportPlaylist(ctx, sourceID)
+	timer.enter(FetchSource)
+	e.sendProgress(progress, fetchSourceUpdate(1, 2, source.name()))
+	sourceExport, cached, err := e.loadDiffTarget(ctx, store, source)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to export source playlist: %v", shared.ErrPlaylistNotFound, err)
+		return nil, fmt.Errorf("failed to load source playlist: %w", err)
 	}
+	result.SourceCached = cached
 
-	e.sendProgress(progress, fetchDestUpdate(2, 2, destSvc.Name()))
-	destExport, err := destSvc.ExportPlaylist(ctx, destID)
+	timer.enter(FetchDest)
+	e.sendProgress(progress, fetchDestUpdate(2, 2, dest.name()))
+	destExport, cached, err := e.loadDiffTarget(ctx, store, dest)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to export destination playlist: %v", shared.ErrPlaylistNotFound, err)
+		return nil, fmt.Errorf("failed to load destination playlist: %w", err)
 	}
+	result.DestCached = cached
 
-	result.Comparison.SourcePlaylist = sourceExport
-	result.Comparison.DestPlaylist = destExport
-
+	timer.enter(Compare)
 	e.sendProgress(progress, buildDestMapUpdate(1, 2))
-	destTrackMap := make(map[string]models.Track)
-	destISRCMap := make(map[string]models.Track)
+	e.sendProgress(progress, missingTrackUpdate(2, 2))
+	result.Comparison = e.compare(ctx, sourceExport, destExport)
+	result.Timing = timer.finish()
 
-	for _, track := range destExport.Tracks {
-		normalizedKey := shared.NormalizeTrackKey(track.Title, track.Artist)
-		destTrackMap[normalizedKey] = track
-		if track.ISRC != "" {
-			destISRCMap[track.ISRC] = track
-		}
+	return result, nil
+}
+
+// loadDiffTarget loads a playlist from the target's export, the cache, or from the live service when it is not
+// cached. Reports whether the playlist came from the cache.
+func (e *PlaylistEngine) loadDiffTarget(ctx context.Context, store PlaylistStore, target DiffTarget) (*models.PlaylistExport, bool, error) {
+	if target.Export != nil {
+		return target.Export, false, nil
 	}
 
-	e.sendProgress(progress, missingTrackUpdate(2, 2))
-	var missingInDest []models.Track
-	matchedCount := 0
+	export, err := store.LoadPlaylist(ctx, target.ServiceKey, target.PlaylistID)
+	if err == nil {
+		return export, true, nil
+	}
 
-	for _, srcTrack := range sourceExport.Tracks {
-		matched := false
+	if target.Service == nil {
+		return nil, false, fmt.Errorf("%w: playlist %s not cached and %s service unavailable", shared.ErrPlaylistNotFound, target.PlaylistID, target.ServiceKey)
+	}
 
-		if srcTrack.ISRC != "" {
-			if _, found := destISRCMap[srcTrack.ISRC]; found {
-				matched = true
-			}
-		}
+	export, err = target.Service.ExportPlaylist(ctx, target.PlaylistID)
+	if err != nil {
+		return nil, false, err
+	}
 
-		if !matched {
-			normalizedKey := shared.NormalizeTrackKey(srcTrack.Title, srcTrack.Artist)
-			if _, found := destTrackMap[normalizedKey]; found {
-				matched = true
-			}
-		}
+	e.cachePlaylist(ctx, target.ServiceKey, export.Playlist, export.Tracks)
+	return export, false, nil
+}
 
-		if matched {
-			matchedCount++
-		} else {
-			missingInDest = append(missingInDest, srcTrack)
-		}
+// compare compares two playlists, leaving out tracks on the ignore list.
+// The comparison keeps the full exports as its source and destination playlists.
+func (e *PlaylistEngine) compare(ctx context.Context, sourceExport, destExport *models.PlaylistExport) ComparisonResult {
+	sourceTracks, sourceIgnored := e.filterIgnored(ctx, sourceExport.Tracks)
+	destTracks, destIgnored := e.filterIgnored(ctx, destExport.Tracks)
+	if len(sourceIgnored) == 0 && len(destIgnored) == 0 {
+		return comparePlaylists(sourceExport, destExport)
 	}
 
-	sourceTrackMap := make(map[string]models.Track)
-	sourceISRCMap := make(map[string]models.Track)
+	comparison := comparePlaylists(
+		&models.PlaylistExport{Playlist: sourceExport.Playlist, Tracks: sourceTracks},
+		&models.PlaylistExport{Playlist: destExport.Playlist, Tracks: destTracks},
+	)
+	comparison.SourcePlaylist = sourceExport
+	comparison.DestPlaylist = destExport
+	comparison.Ignored = append(sourceIgnored, destIgnored...)
+	return comparison
+}
 
-	for _, track := range sourceExport.Tracks {
-		normalizedKey := shared.NormalizeTrackKey(track.Title, track.Artist)
-		sourceTrackMap[normalizedKey] = track
-		if track.ISRC != "" {
-			sourceISRCMap[track.ISRC] = track
-		}
+// comparePlaylists matches tracks between two playlists by ID or ISRC (preferred), or normalized title with
+// matching artists (see [trackIndex]).
+func comparePlaylists(sourceExport, destExport *models.PlaylistExport) ComparisonResult {
+	comparison := ComparisonResult{
+		SourcePlaylist: sourceExport,
+		DestPlaylist:   destExport,
 	}
 
-	var extraInDest []models.Track
-	for _, destTrack := range destExport.Tracks {
-		matched := false
+	missingInDest, matchedInDest := partition(sourceExport.Tracks, newTrackIndex(destExport.Tracks))
+	extraInDest := missingFrom(destExport.Tracks, newTrackIndex(sourceExport.Tracks))
 
-		if destTrack.ISRC != "" {
-			if _, found := sourceISRCMap[destTrack.ISRC]; found {
-				matched = true
-			}
+	comparison.MatchedCount = len(matchedInDest)
+	comparison.Matched = matchedInDest
+	comparison.MissingInDest = missingInDest
+	comparison.ExtraInDest = extraInDest
+
+	return comparison
+}
+
+// PushMissing searches destSvc for each track and appends the matches to the existing playlist destID.
+//
+// Typically used with [ComparisonResult.MissingInDest] to bring a destination playlist in line with its source.
+func (e *PlaylistEngine) PushMissing(ctx context.Context, destSvc services.Service, destID string, tracks []models.Track, progress chan<- ProgressUpdate) (result *PushResult, err error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
+	if destSvc == nil {
+		return nil, fmt.Errorf("%w: service not initialized", shared.ErrServiceUnavailable)
+	}
+
+	ctx, span := tracing.Start(ctx, "push_missing", tracing.String("ytx.dest_id", destID), tracing.Int("ytx.tracks", len(tracks)))
+	defer func() { span.RecordError(err); span.End() }()
+
+	total := len(tracks)
+	result = &PushResult{TrackMatches: make([]TrackMatchResult, 0, total)}
+	matched := make([]models.Track, 0, total)
+	pause := &rateLimitPause{}
+
+	e.sendProgress(progress, searchTracksUpdate(0, total, nil, destSvc.Name()))
+	for i, track := range tracks {
+		e.sendProgress(progress, searchTracksUpdate(i+1, total, &track, destSvc.Name()))
+
+		if reason := track.Unmatchable(); reason != "" {
+			result.TrackMatches = append(result.TrackMatches, TrackMatchResult{Original: track, Skipped: reason})
+			result.SkippedCount++
+			continue
 		}
 
-		if !matched {
-			normalizedKey := shared.NormalizeTrackKey(destTrack.Title, destTrack.Artist)
-			if _, found := sourceTrackMap[normalizedKey]; found {
-				matched = true
-			}
+		var destTrack *models.Track
+		err := e.withRateLimit(ctx, pause, progress, destSvc.Name(), func() (err error) {
+			searchStart := time.Now()
+			destTrack, err = destSvc.SearchTrack(ctx, track.Title, track.Artist())
+			e.observeSearch(e.serviceKey(destSvc), searchStart, err)
+			return err
+		})
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, fmt.Errorf("push cancelled after %d of %d tracks: %w", i, total, ctxErr)
 		}
 
-		if !matched {
-			extraInDest = append(extraInDest, destTrack)
+		match := TrackMatchResult{Original: track, Matched: destTrack, Error: err}
+		if err != nil {
+			result.TrackMatches = append(result.TrackMatches, match)
+			result.FailedCount++
+			continue
 		}
+		match.Method = MatchSearch
+		match.Confidence = matchConfidence(track, *destTrack)
+		result.TrackMatches = append(result.TrackMatches, match)
+		matched = append(matched, *destTrack)
 	}
 
-	result.Comparison.MatchedCount = matchedCount
-	result.Comparison.MissingInDest = missingInDest
-	result.Comparison.ExtraInDest = extraInDest
+	if len(matched) == 0 {
+		return result, fmt.Errorf("no tracks were matched - nothing to add")
+	}
+
+	e.sendProgress(progress, addTracksUpdate(1, 1, len(matched), destSvc.Name()))
+	if err := destSvc.AddTracks(ctx, destID, matched); err != nil {
+		return result, fmt.Errorf("%w: failed to add tracks: %w", shared.ErrAPIRequest, err)
+	}
 
+	result.AddedCount = len(matched)
 	return result, nil
 }
 
 // Dump fetches all data from the API proxy.
-func (e *PlaylistEngine) Dump(ctx context.Context, progress chan<- ProgressUpdate) (*DumpResult, error) {
+//
+// Endpoints are fetched concurrently, each request under its own timeout. As each one finishes, an update carrying its
+// [EndpointResult] as Data is sent; unlike other updates these are never dropped, so the caller can write sections
+// while slower ones are still downloading, and must keep reading progress until Dump returns.
+func (e *PlaylistEngine) Dump(ctx context.Context, progress chan<- ProgressUpdate) (result *DumpResult, err error) {
+	progress, flushProgress := e.dispatchProgress(progress)
+	defer flushProgress()
+
 	if e.api == nil {
 		return nil, fmt.Errorf("%w: API client not initialized", shared.ErrServiceUnavailable)
 	}
 
-	result := &DumpResult{
+	ctx, span := tracing.Start(ctx, "dump")
+	defer func() { span.RecordError(err); span.End() }()
+
+	result = &DumpResult{
 		Errors: []EndpointResult{},
 	}
+	timer := startTiming()
 
 	endpoints := []endpointOperation{
 		{name: "health", path: "/health", target: &result.Health, phase: FetchHealth, message: "Fetching health status..."},
-		{name: "playlists", path: "/api/library/playlists", target: &result.Playlists, phase: FetchPlaylists, message: "Fetching playlists..."},
-		{name: "songs", path: "/api/library/songs", target: &result.Songs, phase: FetchSongs, message: "Fetching songs..."},
-		{name: "albums", path: "/api/library/albums", target: &result.Albums, phase: FetchAlbums, message: "Fetching albums..."},
-		{name: "artists", path: "/api/library/artists", target: &result.Artists, phase: FetchArtists, message: "Fetching artists..."},
-		{name: "liked_songs", path: "/api/library/liked-songs", target: &result.LikedSongs, phase: FetchLiked, message: "Fetching liked songs..."},
-		{name: "history", path: "/api/library/history", target: &result.History, phase: FetchHistory, message: "Fetching history..."},
-		{name: "uploaded_songs", path: "/api/uploads/songs", target: &result.UploadedSongs, phase: FetchUploads, message: "Fetching uploaded songs..."},
-		{name: "uploaded_albums", path: "/api/uploads/albums", target: &result.UploadedAlbums, phase: FetchUploads, message: "Fetching uploaded albums..."},
+		{name: "playlists", path: "/api/library/playlists", target: &result.Playlists, phase: FetchPlaylists, message: "Fetching playlists...", paged: true},
+		{name: "songs", path: "/api/library/songs", target: &result.Songs, phase: FetchSongs, message: "Fetching songs...", paged: true},
+		{name: "albums", path: "/api/library/albums", target: &result.Albums, phase: FetchAlbums, message: "Fetching albums...", paged: true},
+		{name: "artists", path: "/api/library/artists", target: &result.Artists, phase: FetchArtists, message: "Fetching artists...", paged: true},
+		{name: "liked_songs", path: "/api/library/liked-songs", target: &result.LikedSongs, phase: FetchLiked, message: "Fetching liked songs...", paged: true},
+		{name: "history", path: "/api/library/history", target: &result.History, phase: FetchHistory, message: "Fetching history...", timeout: DefaultDumpHistoryTimeout, paged: true},
+		{name: "uploaded_songs", path: "/api/uploads/songs", target: &result.UploadedSongs, phase: FetchUploads, message: "Fetching uploaded songs...", paged: true},
+		{name: "uploaded_albums", path: "/api/uploads/albums", target: &result.UploadedAlbums, phase: FetchUploads, message: "Fetching uploaded albums...", paged: true},
 	}
 
 	totalSteps := len(endpoints)
+	fetched := make([]EndpointResult, totalSteps)
+	var completed atomic.Int32
+	var wg sync.WaitGroup
 
 	for i, endpoint := range endpoints {
 		e.sendProgress(progress, operationUpdate(endpoint, i+1, totalSteps))
 
-		resp, err := e.api.Get(ctx, endpoint.path)
-		if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			errMsg := ""
-			if err != nil {
-				errMsg = err.Error()
-			} else {
-				errMsg = fmt.Sprintf("status %d", resp.StatusCode)
-			}
-			result.Errors = append(result.Errors, EndpointResult{
-				Endpoint: endpoint.path,
-				Error:    fmt.Errorf("%s", errMsg),
-			})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			fetched[i] = e.fetchEndpoint(ctx, endpoint, progress)
+			timer.add(endpoint.phase, time.Since(start))
+			e.sendSection(ctx, progress, endpointUpdate(endpoint, fetched[i], int(completed.Add(1)), totalSteps))
+		}()
+	}
+	wg.Wait()
+	result.Timing = timer.finish()
+
+	for i, endpoint := range endpoints {
+		if fetched[i].Error != nil {
+			result.Errors = append(result.Errors, fetched[i])
 		} else {
-			*endpoint.target = resp.JSONData
+			*endpoint.target = fetched[i].Data
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("dump cancelled: %w", err)
+	}
 	return result, nil
 }
+
+// fetchEndpoint fetches a single Dump endpoint, page by page when it is paged and a full dump was requested
+func (e *PlaylistEngine) fetchEndpoint(ctx context.Context, endpoint endpointOperation, progress chan<- ProgressUpdate) EndpointResult {
+	result := EndpointResult{Name: endpoint.name, Endpoint: endpoint.path}
+	if !e.dumpFull || !endpoint.paged {
+		result.Data, result.Error = e.getEndpoint(ctx, endpoint, endpoint.path)
+		return result
+	}
+
+	items := []any{}
+	continuation := ""
+	for page := 1; ; page++ {
+		data, err := e.getEndpoint(ctx, endpoint, services.LibraryPath(endpoint.path, services.DefaultLibraryPageSize, continuation))
+		if err != nil {
+			result.Error = fmt.Errorf("page %d: %w", page, err)
+			return result
+		}
+		libraryPage, ok := services.ParseLibraryPage(data)
+		if !ok {
+			result.Error = fmt.Errorf("page %d: unexpected response", page)
+			return result
+		}
+
+		items = append(items, libraryPage.Items...)
+		e.sendProgress(progress, libraryPageUpdate(endpoint, page, len(items)))
+
+		// A repeated token would loop forever, so it ends the listing like a missing one
+		if libraryPage.Continuation == "" || libraryPage.Continuation == continuation {
+			break
+		}
+		continuation = libraryPage.Continuation
+	}
+
+	result.Data = items
+	return result
+}
+
+// getEndpoint makes a single Dump request under the endpoint's timeout, returning its JSON data
+func (e *PlaylistEngine) getEndpoint(ctx context.Context, endpoint endpointOperation, path string) (any, error) {
+	timeout := e.dumpTimeout
+	if timeout <= 0 {
+		timeout = cmp.Or(endpoint.timeout, DefaultDumpTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := e.api.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return resp.JSONData, nil
+}
+
+// sendSection sends an update that must not be dropped, waiting for room in the channel unless ctx is done
+func (e *PlaylistEngine) sendSection(ctx context.Context, progress chan<- ProgressUpdate, update ProgressUpdate) {
+	if progress == nil {
+		return
+	}
+	update.Time = time.Now()
+	select {
+	case progress <- update:
+	case <-ctx.Done():
+	}
+}
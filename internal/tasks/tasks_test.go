@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/desertthunder/ytx/internal/formatter"
 	"github.com/desertthunder/ytx/internal/models"
 	"github.com/desertthunder/ytx/internal/services"
 	"github.com/desertthunder/ytx/internal/shared"
@@ -25,7 +29,10 @@ type mockService struct {
 	exportCallCount int
 	exportErrOnce   bool // If true, only fail first export call
 	importErr       error
+	importCalled    bool
+	imported        *models.PlaylistExport
 	searchErr       error
+	onSearch        func(title string) // Called before each search, e.g. to cancel the context mid-transfer
 }
 
 func (m *mockService) Name() string {
@@ -65,10 +72,12 @@ func (m *mockService) ExportPlaylist(ctx context.Context, playlistID string) (*m
 	if export, ok := m.playlistExports[playlistID]; ok {
 		return export, nil
 	}
-	return nil, fmt.Errorf("playlist not found")
+	return nil, fmt.Errorf("%w: %s", shared.ErrPlaylistNotFound, playlistID)
 }
 
 func (m *mockService) ImportPlaylist(ctx context.Context, playlist *models.PlaylistExport) (*models.Playlist, error) {
+	m.importCalled = true
+	m.imported = playlist
 	if m.importErr != nil {
 		return nil, m.importErr
 	}
@@ -76,6 +85,9 @@ func (m *mockService) ImportPlaylist(ctx context.Context, playlist *models.Playl
 }
 
 func (m *mockService) SearchTrack(ctx context.Context, title, artist string) (*models.Track, error) {
+	if m.onSearch != nil {
+		m.onSearch(title)
+	}
 	if m.searchErr != nil {
 		return nil, m.searchErr
 	}
@@ -83,7 +95,19 @@ func (m *mockService) SearchTrack(ctx context.Context, title, artist string) (*m
 	if track, ok := m.searchResults[key]; ok {
 		return track, nil
 	}
-	return nil, fmt.Errorf("track not found")
+	return nil, fmt.Errorf("%w: %s", shared.ErrTrackNotFound, key)
+}
+
+func (m *mockService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	return nil
+}
+
+func (m *mockService) RemoveTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	return nil
+}
+
+func (m *mockService) ReorderTracks(ctx context.Context, playlistID string, rangeStart, rangeLength, insertBefore int) error {
+	return nil
 }
 
 // Mock API client for testing
@@ -127,8 +151,8 @@ func TestPlaylistEngine_Run(t *testing.T) {
 							Name: "My Spotify Playlist",
 						},
 						Tracks: []models.Track{
-							{ID: "track1", Title: "Song 1", Artist: "Artist 1"},
-							{ID: "track2", Title: "Song 2", Artist: "Artist 2"},
+							{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+							{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
 						},
 					},
 				},
@@ -136,8 +160,8 @@ func TestPlaylistEngine_Run(t *testing.T) {
 			youtubeService: &mockService{
 				name: "YouTube Music",
 				searchResults: map[string]*models.Track{
-					"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artist: "Artist 1"},
-					"Song 2|Artist 2": {ID: "yt2", Title: "Song 2", Artist: "Artist 2"},
+					"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					"Song 2|Artist 2": {ID: "yt2", Title: "Song 2", Artists: []string{"Artist 2"}},
 				},
 				importResult: &models.Playlist{
 					ID:         "yt_playlist",
@@ -164,7 +188,7 @@ func TestPlaylistEngine_Run(t *testing.T) {
 							Name: "My Spotify Playlist",
 						},
 						Tracks: []models.Track{
-							{ID: "track1", Title: "Song 1", Artist: "Artist 1"},
+							{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
 						},
 					},
 				},
@@ -174,7 +198,7 @@ func TestPlaylistEngine_Run(t *testing.T) {
 			youtubeService: &mockService{
 				name: "YouTube Music",
 				searchResults: map[string]*models.Track{
-					"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artist: "Artist 1"},
+					"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
 				},
 				importResult: &models.Playlist{
 					ID:         "yt_playlist",
@@ -198,9 +222,9 @@ func TestPlaylistEngine_Run(t *testing.T) {
 							Name: "My Spotify Playlist",
 						},
 						Tracks: []models.Track{
-							{ID: "track1", Title: "Song 1", Artist: "Artist 1"},
-							{ID: "track2", Title: "Song 2", Artist: "Artist 2"},
-							{ID: "track3", Title: "Song 3", Artist: "Artist 3"},
+							{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+							{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+							{ID: "track3", Title: "Song 3", Artists: []string{"Artist 3"}},
 						},
 					},
 				},
@@ -208,9 +232,9 @@ func TestPlaylistEngine_Run(t *testing.T) {
 			youtubeService: &mockService{
 				name: "YouTube Music",
 				searchResults: map[string]*models.Track{
-					"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artist: "Artist 1"},
+					"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
 					// Song 2 not found
-					"Song 3|Artist 3": {ID: "yt3", Title: "Song 3", Artist: "Artist 3"},
+					"Song 3|Artist 3": {ID: "yt3", Title: "Song 3", Artists: []string{"Artist 3"}},
 				},
 				importResult: &models.Playlist{
 					ID:         "yt_playlist",
@@ -234,7 +258,7 @@ func TestPlaylistEngine_Run(t *testing.T) {
 							Name: "My Spotify Playlist",
 						},
 						Tracks: []models.Track{
-							{ID: "track1", Title: "Song 1", Artist: "Artist 1"},
+							{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
 						},
 					},
 				},
@@ -254,15 +278,24 @@ func TestPlaylistEngine_Run(t *testing.T) {
 			engine := NewPlaylistEngine(tt.spotifyService, tt.youtubeService, nil)
 
 			progressCh := make(chan ProgressUpdate, 100)
+			unstamped := make(chan int, 1)
 			go func() {
-				for range progressCh {
-					// Drain progress channel
+				count := 0
+				for update := range progressCh {
+					if update.Time.IsZero() {
+						count++
+					}
 				}
+				unstamped <- count
 			}()
 
 			result, err := engine.Run(context.Background(), tt.sourceID, progressCh)
 			close(progressCh)
 
+			if count := <-unstamped; count > 0 {
+				t.Errorf("expected every progress update to be timestamped, %d were not", count)
+			}
+
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -280,171 +313,1953 @@ func TestPlaylistEngine_Run(t *testing.T) {
 	}
 }
 
-func TestPlaylistEngine_Run_ServiceErrors(t *testing.T) {
-	t.Run("spotify service not initialized", func(t *testing.T) {
-		engine := NewPlaylistEngine(nil, &mockService{}, nil)
-		progressCh := make(chan ProgressUpdate, 10)
+// Mock track cachers for testing
+type mockTrackCacher struct {
+	cached map[string][]string
+}
 
-		_, err := engine.Run(context.Background(), "playlist123", progressCh)
-		close(progressCh)
+func (m *mockTrackCacher) CacheTrack(ctx context.Context, service, serviceID string, track models.Track) error {
+	if m.cached == nil {
+		m.cached = map[string][]string{}
+	}
+	m.cached[service] = append(m.cached[service], serviceID)
+	return nil
+}
 
-		if err == nil {
-			t.Error("Run() expected error for nil spotify service")
+type mockBulkTrackCacher struct {
+	mockTrackCacher
+	batches map[string]int
+}
+
+func (m *mockBulkTrackCacher) BulkCacheTracks(ctx context.Context, service string, tracks []models.Track) error {
+	if m.batches == nil {
+		m.batches = map[string]int{}
+	}
+	m.batches[service] += len(tracks)
+	return nil
+}
+
+// mockRankedService returns several candidates per search, best first
+type mockRankedService struct {
+	*mockService
+	candidates map[string][]models.Track
+}
+
+func (m *mockRankedService) SearchTracks(ctx context.Context, title, artist string, limit int) ([]models.Track, error) {
+	return m.candidates[title+"|"+artist], nil
+}
+
+func TestPlaylistEngine_Run_MaxDurationDelta(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Strobe", Artists: []string{"deadmau5"}, Duration: 214},
+					{ID: "track2", Title: "Levels", Artists: []string{"Avicii"}, Duration: 200},
+					{ID: "track3", Title: "Unknown Length", Artists: []string{"Artist"}},
+				},
+			},
+		},
+	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Strobe|deadmau5":       {ID: "yt1", Title: "Strobe (Extended Mix)", Artists: []string{"deadmau5"}, Duration: 637},
+			"Levels|Avicii":         {ID: "yt2", Title: "Levels", Artists: []string{"Avicii"}, Duration: 203},
+			"Unknown Length|Artist": {ID: "yt3", Title: "Unknown Length", Artists: []string{"Artist"}, Duration: 180},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
+	}
+
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetMaxDurationDelta(time.Minute)
+	result, err := engine.RunWithOpts(context.Background(), "playlist123", TransferOpts{MaxDurationDelta: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatalf("RunWithOpts() error = %v", err)
+	}
+
+	strobe := result.TrackMatches[0]
+	if strobe.Matched != nil || !errors.Is(strobe.Error, shared.ErrTrackNotFound) {
+		t.Errorf("expected the extended mix refused, got %+v", strobe)
+	}
+	if len(strobe.Rejected) != 1 || strobe.Rejected[0].ID != "yt1" {
+		t.Errorf("expected the refused candidate reported, got %+v", strobe.Rejected)
+	}
+	if !strings.Contains(strobe.Error.Error(), "1 outside 5s of") {
+		t.Errorf("expected the duration window named, got %v", strobe.Error)
+	}
+	if result.TrackMatches[1].Matched == nil || result.TrackMatches[2].Matched == nil {
+		t.Errorf("expected close and unknown durations accepted, got %+v", result.TrackMatches[1:])
+	}
+
+	t.Run("ranked candidates", func(t *testing.T) {
+		ranked := &mockRankedService{mockService: youtube, candidates: map[string][]models.Track{
+			"Strobe|deadmau5": {
+				{ID: "yt1", Title: "Strobe (Extended Mix)", Artists: []string{"deadmau5"}, Duration: 637},
+				{ID: "yt4", Title: "Strobe (Radio Edit)", Artists: []string{"deadmau5"}, Duration: 212},
+			},
+			"Levels|Avicii":         {{ID: "yt2", Title: "Levels", Artists: []string{"Avicii"}, Duration: 203}},
+			"Unknown Length|Artist": {{ID: "yt3", Title: "Unknown Length", Artists: []string{"Artist"}}},
+		}}
+		engine := NewPlaylistEngine(spotify, ranked, nil)
+		engine.SetMaxDurationDelta(5 * time.Second)
+
+		result, err := engine.Run(context.Background(), "playlist123", nil)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
 		}
-		if err != nil && !errors.Is(err, shared.ErrServiceUnavailable) {
-			if !strings.Contains(err.Error(), "not initialized") {
-				t.Errorf("Run() error should mention service not initialized, got: %v", err)
-			}
+		strobe := result.TrackMatches[0]
+		if strobe.Matched == nil || strobe.Matched.ID != "yt4" || len(strobe.Rejected) != 1 {
+			t.Errorf("expected the radio edit after refusing the extended mix, got %+v", strobe)
 		}
 	})
+}
 
-	t.Run("youtube service not initialized", func(t *testing.T) {
-		engine := NewPlaylistEngine(&mockService{}, nil, nil)
-		progressCh := make(chan ProgressUpdate, 10)
+// mockISRCService also finds tracks by ISRC
+type mockISRCService struct {
+	*mockService
+	isrcResults map[string][]models.Track
+}
 
-		_, err := engine.Run(context.Background(), "playlist123", progressCh)
-		close(progressCh)
+func (m *mockISRCService) SearchISRC(ctx context.Context, isrc string) ([]models.Track, error) {
+	return m.isrcResults[isrc], nil
+}
 
-		if err == nil {
-			t.Error("Run() expected error for nil youtube service")
+func TestPlaylistEngine_Run_SearchFallback(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Strobe", Artists: []string{"deadmau5"}, ISRC: "CA1"},
+					{ID: "track2", Title: "Levels", Artists: []string{"Avicii"}},
+					{ID: "track3", Title: "Группа крови", Artists: []string{"Кино"}},
+					{ID: "track4", Title: "Other", Artists: []string{"Artist"}},
+					{ID: "track5", Title: "Blocked", Artists: []string{"Artist"}},
+				},
+			},
+		},
+	}
+	dest := &mockISRCService{
+		mockService: &mockService{
+			name: "YouTube Music",
+			searchResults: map[string]*models.Track{
+				"Levels|":           {ID: "yt2", Title: "Levels (Radio Edit)", Artists: []string{"Avicii - Topic"}},
+				"Gruppa krovi|Kino": {ID: "yt3", Title: "Gruppa Krovi", Artists: []string{"Kino"}},
+				"Other|":            {ID: "yt4", Title: "Another Song", Artists: []string{"Someone"}},
+				"Blocked|Artist":    {ID: "yt5", Title: "Blocked", Artists: []string{"Artist"}, Unavailable: true},
+				"Strobe|deadmau5":   {ID: "yt_search", Title: "Strobe", Artists: []string{"deadmau5"}},
+			},
+			importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
+		},
+		isrcResults: map[string][]models.Track{"CA1": {{ID: "yt1", Title: "Strobe", Artists: []string{"deadmau5"}, ISRC: "CA1"}}},
+	}
+
+	engine := NewPlaylistEngine(spotify, dest, nil)
+	result, err := engine.Run(context.Background(), "playlist123", nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for i, want := range []struct{ id, step string }{{"yt1", SearchISRC}, {"yt2", SearchTitle}, {"yt3", SearchTransliterated}, {"", ""}, {"yt5", SearchTitleArtist}} {
+		got, id := result.TrackMatches[i], ""
+		if got.Matched != nil {
+			id = got.Matched.ID
+		}
+		if id != want.id || got.SearchStep != want.step {
+			t.Errorf("track %d matched %q by %q (%v), want %q by %q", i, id, got.SearchStep, got.Error, want.id, want.step)
+		}
+	}
+	if other := result.TrackMatches[3]; !errors.Is(other.Error, shared.ErrTrackNotFound) || len(other.Rejected) != 1 {
+		t.Errorf("expected a title-only result with another title refused, got %+v", other)
+	} else if msg := other.Error.Error(); !strings.Contains(msg, "1 with another title") || strings.Contains(msg, "outside") {
+		t.Errorf("expected the refusal put down to the title alone, got %q", msg)
+	}
+	if report := result.Report(time.Now()); report.Tracks[1].Method != "search (title)" {
+		t.Errorf("expected the fallback step in the report, got %q", report.Tracks[1].Method)
+	}
+
+	t.Run("flag unavailable", func(t *testing.T) {
+		result, err := engine.RunWithOpts(context.Background(), "playlist123", TransferOpts{FlagUnavailable: true}, nil)
+		if err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		blocked := result.TrackMatches[4]
+		if blocked.Matched != nil || !errors.Is(blocked.Error, shared.ErrTrackUnavailable) {
+			t.Errorf("expected the unavailable match refused, got %+v", blocked)
+		} else if msg := blocked.Error.Error(); !strings.Contains(msg, "1 unavailable in this region") || strings.Contains(msg, "outside") {
+			t.Errorf("expected the refusal put down to availability alone, got %q", msg)
+		}
+		if report := result.Report(time.Now()); report.Tracks[4].Status != formatter.ReportUnavailable {
+			t.Errorf("expected the track reported as unavailable, got %q", report.Tracks[4].Status)
 		}
 	})
 }
 
-func TestPlaylistEngine_Diff(t *testing.T) {
-	sourceExport := &models.PlaylistExport{
-		Playlist: models.Playlist{ID: "src", Name: "Source"},
-		Tracks: []models.Track{
-			{ID: "1", Title: "Track 1", Artist: "Artist A", ISRC: "ISRC1"},
-			{ID: "2", Title: "Track 2", Artist: "Artist B", ISRC: "ISRC2"},
-			{ID: "3", Title: "Track 3", Artist: "Artist C", ISRC: "ISRC3"},
+func TestPlaylistEngine_Run_ExplicitPreference(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Lose Yourself", Artists: []string{"Eminem"}, Explicit: true, Duration: 326},
+					{ID: "track2", Title: "Clean Only", Artists: []string{"Artist"}, Duration: 200},
+				},
+			},
 		},
 	}
-
-	destExport := &models.PlaylistExport{
-		Playlist: models.Playlist{ID: "dest", Name: "Destination"},
-		Tracks: []models.Track{
-			{ID: "10", Title: "Track 1", Artist: "Artist A", ISRC: "ISRC1"}, // Match by ISRC
-			{ID: "20", Title: "Track 2", Artist: "Artist B"},                // Match by title+artist
-			{ID: "40", Title: "Track 4", Artist: "Artist D", ISRC: "ISRC4"}, // Extra track
+	youtube := &mockRankedService{
+		mockService: &mockService{name: "YouTube Music", importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"}},
+		candidates: map[string][]models.Track{
+			"Lose Yourself|Eminem": {
+				{ID: "yt1", Title: "Lose Yourself", Artists: []string{"Eminem"}, Explicit: true, Duration: 326},
+				{ID: "yt2", Title: "Lose Yourself (Live)", Artists: []string{"Eminem"}, Duration: 600},
+				{ID: "yt3", Title: "Lose Yourself", Artists: []string{"Eminem"}, Duration: 325},
+			},
+			"Clean Only|Artist": {{ID: "yt4", Title: "Clean Only", Artists: []string{"Artist"}, Duration: 200}},
 		},
 	}
 
-	sourceSvc := &mockService{
+	tests := []struct {
+		name   string
+		engine ExplicitPreference
+		opts   TransferOpts
+		want   []string
+	}{
+		{"no preference", NoExplicitPreference, TransferOpts{MaxDurationDelta: time.Minute}, []string{"yt1", "yt4"}},
+		{"clean", PreferClean, TransferOpts{MaxDurationDelta: 5 * time.Second}, []string{"yt3", "yt4"}},
+		{"explicit from options", PreferClean, TransferOpts{Explicit: PreferExplicit}, []string{"yt1", "yt4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewPlaylistEngine(spotify, youtube, nil)
+			engine.SetExplicitPreference(tt.engine)
+			result, err := engine.RunWithOpts(context.Background(), "playlist123", tt.opts, nil)
+			if err != nil {
+				t.Fatalf("RunWithOpts() error = %v", err)
+			}
+			for i, id := range tt.want {
+				if match := result.TrackMatches[i].Matched; match == nil || match.ID != id {
+					t.Errorf("track %d matched %+v, want %s", i, match, id)
+				}
+			}
+		})
+	}
+
+	for _, input := range []string{"", "none", " Clean ", "EXPLICIT"} {
+		if _, err := ParseExplicitPreference(input); err != nil {
+			t.Errorf("ParseExplicitPreference(%q) error = %v", input, err)
+		}
+	}
+	if _, err := ParseExplicitPreference("radio"); !errors.Is(err, shared.ErrInvalidArgument) {
+		t.Errorf("expected an unknown preference refused, got %v", err)
+	}
+}
+
+type mockCounterpartCacher struct {
+	mockTrackCacher
+	counterparts map[string]*models.Track // Keyed by service|ISRC
+}
+
+func (m *mockCounterpartCacher) FindCounterpart(ctx context.Context, service, isrc string) (*models.Track, error) {
+	if track, ok := m.counterparts[service+"|"+isrc]; ok {
+		return track, nil
+	}
+	return nil, shared.ErrRecordNotFound
+}
+
+func TestPlaylistEngine_Run_CounterpartFinder(t *testing.T) {
+	spotify := &mockService{
 		name: "Spotify",
 		playlistExports: map[string]*models.PlaylistExport{
-			"src": sourceExport,
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}, ISRC: "USAAA0000001"},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}, ISRC: "USAAA0000002"},
+				},
+			},
 		},
 	}
-
-	destSvc := &mockService{
+	youtube := &mockService{
 		name: "YouTube Music",
-		playlistExports: map[string]*models.PlaylistExport{
-			"dest": destExport,
+		searchResults: map[string]*models.Track{
+			"Song 2|Artist 2": {ID: "yt2", Title: "Song 2", Artists: []string{"Artist 2"}},
 		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
 	}
 
-	engine := NewPlaylistEngine(nil, nil, nil)
-
-	progressCh := make(chan ProgressUpdate, 100)
-	go func() {
-		for range progressCh {
-			// Drain progress channel
-		}
-	}()
-
-	result, err := engine.Diff(context.Background(), sourceSvc, destSvc, "src", "dest", progressCh)
-	close(progressCh)
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetTrackCacher(&mockCounterpartCacher{counterparts: map[string]*models.Track{
+		"youtube|USAAA0000001": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}, ISRC: "USAAA0000001"},
+		"spotify|USAAA0000002": {ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}, ISRC: "USAAA0000002"},
+	}})
 
+	result, err := engine.Run(context.Background(), "playlist123", nil)
 	if err != nil {
-		t.Fatalf("Diff() error = %v", err)
+		t.Fatalf("Run() error = %v", err)
 	}
+	if result.TrackMatches[0].Matched.ID != "yt1" || result.TrackMatches[0].Method != MatchISRC {
+		t.Errorf("expected yt1 translated by ISRC, got %+v", result.TrackMatches[0])
+	}
+	if result.TrackMatches[1].Matched.ID != "yt2" || result.TrackMatches[1].Method != MatchSearch {
+		t.Errorf("expected yt2 searched without a YouTube counterpart, got %+v", result.TrackMatches[1])
+	}
+	if result.CachedSearches != 1 {
+		t.Errorf("expected 1 cached match, got %d", result.CachedSearches)
+	}
+}
 
-	if result.Comparison.MatchedCount != 2 {
-		t.Errorf("Diff() matchedCount = %v, want 2", result.Comparison.MatchedCount)
+func TestPlaylistEngine_Run_BulkTrackCacher(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
+			},
+		},
+	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
 	}
 
-	if len(result.Comparison.MissingInDest) != 1 {
-		t.Errorf("Diff() missingInDest count = %v, want 1", len(result.Comparison.MissingInDest))
-	} else if result.Comparison.MissingInDest[0].ID != "3" {
-		t.Errorf("Diff() missing track ID = %v, want '3'", result.Comparison.MissingInDest[0].ID)
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	cacher := &mockBulkTrackCacher{}
+	engine.SetTrackCacher(cacher)
+
+	if _, err := engine.Run(context.Background(), "playlist123", nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
 	}
 
-	if len(result.Comparison.ExtraInDest) != 1 {
-		t.Errorf("Diff() extraInDest count = %v, want 1", len(result.Comparison.ExtraInDest))
-	} else if result.Comparison.ExtraInDest[0].ID != "40" {
-		t.Errorf("Diff() extra track ID = %v, want '40'", result.Comparison.ExtraInDest[0].ID)
+	if cacher.batches["spotify"] != 2 {
+		t.Errorf("expected source tracks cached in one batch, got %d", cacher.batches["spotify"])
+	}
+	if len(cacher.cached["spotify"]) != 0 {
+		t.Errorf("expected no per-track spotify caching, got %d", len(cacher.cached["spotify"]))
+	}
+	if len(cacher.cached["youtube"]) != 1 {
+		t.Errorf("expected matched track cached individually, got %d", len(cacher.cached["youtube"]))
 	}
 }
 
-func TestPlaylistEngine_Dump(t *testing.T) {
-	apiClient := &mockAPIClient{
-		responses: map[string]*services.APIResponse{
-			"/health": {
-				StatusCode: 200,
-				IsJSON:     true,
-				JSONData:   map[string]string{"status": "ok"},
-			},
-			"/api/library/playlists": {
-				StatusCode: 200,
-				IsJSON:     true,
-				JSONData:   []string{"playlist1", "playlist2"},
-			},
-			"/api/library/songs": {
-				StatusCode: 500,
-				Body:       []byte("internal error"),
+type mockPlaylistCacher struct {
+	cached map[string]int
+}
+
+func (m *mockPlaylistCacher) CachePlaylist(ctx context.Context, service string, playlist models.Playlist, tracks []models.Track) error {
+	if m.cached == nil {
+		m.cached = map[string]int{}
+	}
+	m.cached[service+":"+playlist.ID] = len(tracks)
+	return nil
+}
+
+func TestPlaylistEngine_Run_PlaylistCacher(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
 			},
 		},
 	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
+	}
 
-	engine := NewPlaylistEngine(nil, nil, apiClient)
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	cacher := &mockPlaylistCacher{}
+	engine.SetPlaylistCacher(cacher)
 
-	progressCh := make(chan ProgressUpdate, 100)
-	progressUpdates := []ProgressUpdate{}
-	done := make(chan bool)
+	if _, err := engine.Run(context.Background(), "playlist123", nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
 
-	go func() {
-		for update := range progressCh {
-			progressUpdates = append(progressUpdates, update)
-		}
-		done <- true
-	}()
+	if got := cacher.cached["spotify:playlist123"]; got != 2 {
+		t.Errorf("expected source playlist cached with 2 tracks, got %d", got)
+	}
+	if got := cacher.cached["youtube:yt_playlist"]; got != 1 {
+		t.Errorf("expected destination playlist cached with 1 track, got %d", got)
+	}
+}
 
-	result, err := engine.Dump(context.Background(), progressCh)
-	close(progressCh)
-	<-done
+// Mock search cache for testing
+type mockSearchCache struct {
+	entries map[string]*models.Track
+	stored  int
+}
 
-	if err != nil {
-		t.Fatalf("Dump() error = %v", err)
+func (m *mockSearchCache) Lookup(ctx context.Context, query models.Track) (*models.Track, error) {
+	if match, ok := m.entries[query.Title+"|"+query.Artist()]; ok {
+		return match, nil
 	}
+	return nil, shared.ErrTrackNotFound
+}
 
-	if result.Health == nil {
-		t.Error("Dump() health data should not be nil")
+func (m *mockSearchCache) Store(ctx context.Context, query, match models.Track) error {
+	if m.entries == nil {
+		m.entries = make(map[string]*models.Track)
 	}
+	m.entries[query.Title+"|"+query.Artist()] = &match
+	m.stored++
+	return nil
+}
 
-	if result.Playlists == nil {
-		t.Error("Dump() playlists data should not be nil")
+func TestPlaylistEngine_Run_SearchCache(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
+			},
+		},
+	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 2|Artist 2": {ID: "yt2", Title: "Song 2", Artists: []string{"Artist 2"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
 	}
 
-	if len(result.Errors) == 0 {
-		t.Error("Dump() should have errors for failed endpoints")
+	cache := &mockSearchCache{entries: map[string]*models.Track{
+		"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+	}}
+
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetSearchCache(cache)
+
+	result, err := engine.Run(context.Background(), "playlist123", nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
 	}
 
-	if len(progressUpdates) == 0 {
-		t.Error("Dump() should send progress updates")
+	if result.SuccessCount != 2 {
+		t.Errorf("expected 2 matches, got %d", result.SuccessCount)
+	}
+	if result.CachedSearches != 1 {
+		t.Errorf("expected 1 cached search, got %d", result.CachedSearches)
+	}
+	if result.TrackMatches[0].Matched.ID != "yt1" {
+		t.Errorf("expected cached match yt1, got %s", result.TrackMatches[0].Matched.ID)
+	}
+	if result.TrackMatches[0].Method != MatchCache || result.TrackMatches[1].Method != MatchSearch {
+		t.Errorf("expected cache then search methods, got %q and %q", result.TrackMatches[0].Method, result.TrackMatches[1].Method)
+	}
+	if cache.stored != 1 {
+		t.Errorf("expected live search result to be stored once, got %d", cache.stored)
 	}
 }
 
-func TestPlaylistEngine_Dump_APIClientError(t *testing.T) {
-	engine := NewPlaylistEngine(nil, nil, nil)
-	progressCh := make(chan ProgressUpdate, 10)
+// Mock ignore list for testing, keyed by ISRC or artist
+type mockIgnoreList struct {
+	ignored map[string]bool
+}
 
-	_, err := engine.Dump(context.Background(), progressCh)
-	close(progressCh)
+func (m *mockIgnoreList) Ignores(ctx context.Context, track models.Track) (bool, error) {
+	return m.ignored[track.ISRC] || m.ignored[track.Artist()], nil
+}
 
-	if err == nil {
-		t.Error("Dump() expected error for nil API client")
+func TestPlaylistEngine_Run_IgnoreList(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "episode", Title: "Episode 12", Artists: []string{"Some Podcast"}},
+					{ID: "local", Title: "Demo", Artists: []string{"Artist 2"}, ISRC: "LOCAL1"},
+				},
+			},
+		},
 	}
+	var searched []string
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
+		onSearch:     func(title string) { searched = append(searched, title) },
+	}
+
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetIgnoreList(&mockIgnoreList{ignored: map[string]bool{"Some Podcast": true, "LOCAL1": true}})
+
+	result, err := engine.Run(context.Background(), "playlist123", nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if result.TotalTracks != 1 || result.SuccessCount != 1 || result.FailedCount != 0 {
+		t.Errorf("TotalTracks = %d, SuccessCount = %d, FailedCount = %d, want 1, 1, 0", result.TotalTracks, result.SuccessCount, result.FailedCount)
+	}
+	if len(result.Ignored) != 2 || result.Ignored[0].ID != "episode" || result.Ignored[1].ID != "local" {
+		t.Errorf("Ignored = %+v, want episode and local", result.Ignored)
+	}
+	if len(searched) != 1 || searched[0] != "Song 1" {
+		t.Errorf("expected ignored tracks to skip search, searched %v", searched)
+	}
+}
+
+func TestPlaylistEngine_Run_SkipsUnmatchable(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}, URI: "spotify:track:track1"},
+					{ID: "episode1", Title: "Episode 12", URI: "spotify:episode:episode1"},
+					{Title: "Demo", Artists: []string{"Artist 2"}, URI: "spotify:local:Artist+2::Demo:180"},
+					{ID: "episode2", Title: "Episode 13", URI: "spotify:episode:episode2"},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
+			},
+		},
+	}
+	var searched []string
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1":    {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+			"Episode 13 (Clip)|": {ID: "yt_clip"},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
+		onSearch:     func(title string) { searched = append(searched, title) },
+	}
+
+	opts := TransferOpts{Overrides: &TrackOverrides{Tracks: []TrackOverride{{SourceID: "episode2", Query: "Episode 13 (Clip)"}}}}
+	result, err := NewPlaylistEngine(spotify, youtube, nil).RunWithOpts(context.Background(), "playlist123", opts, nil)
+	if err != nil {
+		t.Fatalf("RunWithOpts() error = %v", err)
+	}
+
+	if result.SkippedCount != 2 || result.FailedCount != 1 || result.SuccessCount != 2 {
+		t.Errorf("SkippedCount = %d, FailedCount = %d, SuccessCount = %d, want 2, 1, 2", result.SkippedCount, result.FailedCount, result.SuccessCount)
+	}
+	if result.MatchableTracks() != 3 || result.MatchPercentage < 66 || result.MatchPercentage > 67 {
+		t.Errorf("expected the match rate over 3 matchable tracks, got %d tracks at %.1f%%", result.MatchableTracks(), result.MatchPercentage)
+	}
+	if got := result.TrackMatches[1]; got.Skipped != models.UnmatchableEpisode || got.Error != nil {
+		t.Errorf("expected the episode to be skipped, got %+v", got)
+	}
+	if got := result.TrackMatches[2]; got.Skipped != models.UnmatchableLocalFile {
+		t.Errorf("expected the local file to be skipped, got %+v", got)
+	}
+	// The unmatched track is searched by title and artist, then by title alone
+	if !slices.Equal(searched, []string{"Song 1", "Episode 13 (Clip)", "Song 2", "Song 2"}) {
+		t.Errorf("expected only matchable and overridden tracks to be searched, searched %v", searched)
+	}
+}
+
+// Mock migration recorder for testing
+type mockMigrationRecorder struct {
+	started  []models.TransferRecord
+	progress []int
+	records  []models.TransferRecord
+}
+
+func (m *mockMigrationRecorder) StartTransfer(ctx context.Context, record models.TransferRecord) (string, error) {
+	m.started = append(m.started, record)
+	return fmt.Sprintf("migration%d", len(m.started)), nil
+}
+
+func (m *mockMigrationRecorder) RecordProgress(ctx context.Context, migrationID string, current, total int) error {
+	m.progress = append(m.progress, current)
+	return nil
+}
+
+func (m *mockMigrationRecorder) FinishTransfer(ctx context.Context, migrationID string, record models.TransferRecord) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func TestPlaylistEngine_Run_MigrationRecorder(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
+			},
+		},
+	}
+
+	t.Run("records completed transfer with unmatched tracks", func(t *testing.T) {
+		youtube := &mockService{
+			name: "YouTube Music",
+			searchResults: map[string]*models.Track{
+				"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+			},
+			importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
+		}
+
+		recorder := &mockMigrationRecorder{}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+		engine.SetMigrationRecorder(recorder)
+
+		result, err := engine.Run(context.Background(), "playlist123", nil)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if result.MigrationID != "migration1" {
+			t.Errorf("expected result to carry the migration ID, got %q", result.MigrationID)
+		}
+		if len(recorder.started) != 1 || recorder.started[0].TracksTotal != 2 {
+			t.Errorf("expected transfer to be started with 2 tracks, got %+v", recorder.started)
+		}
+		if len(recorder.progress) != 2 || recorder.progress[1] != 2 {
+			t.Errorf("expected progress for the first and last track, got %v", recorder.progress)
+		}
+		if len(recorder.records) != 1 {
+			t.Fatalf("expected 1 recorded transfer, got %d", len(recorder.records))
+		}
+		record := recorder.records[0]
+		if record.SourcePlaylist.ID != "playlist123" || record.TargetPlaylist == nil || record.TargetPlaylist.ID != "yt_playlist" {
+			t.Errorf("unexpected playlists recorded: %+v -> %+v", record.SourcePlaylist, record.TargetPlaylist)
+		}
+		if record.TracksTotal != 2 || record.TracksMigrated != 1 {
+			t.Errorf("expected 1/2 tracks migrated, got %d/%d", record.TracksMigrated, record.TracksTotal)
+		}
+		if len(record.Failures) != 1 || record.Failures[0].Title != "Song 2" {
+			t.Errorf("expected Song 2 to be recorded as unmatched, got %v", record.Failures)
+		}
+		if record.Error != "" || record.CompletedAt.Before(record.StartedAt) {
+			t.Errorf("unexpected error or timing: %q %v-%v", record.Error, record.StartedAt, record.CompletedAt)
+		}
+	})
+
+	t.Run("records failed transfer", func(t *testing.T) {
+		recorder := &mockMigrationRecorder{}
+		engine := NewPlaylistEngine(spotify, &mockService{name: "YouTube Music"}, nil)
+		engine.SetMigrationRecorder(recorder)
+
+		if _, err := engine.Run(context.Background(), "playlist123", nil); err == nil {
+			t.Fatal("Run() expected error when no tracks match")
+		}
+
+		if len(recorder.records) != 1 || recorder.records[0].Error == "" {
+			t.Fatalf("expected a failed transfer to be recorded, got %v", recorder.records)
+		}
+		if recorder.records[0].TargetPlaylist != nil || len(recorder.records[0].Failures) != 2 {
+			t.Errorf("expected no destination and 2 unmatched tracks, got %+v", recorder.records[0])
+		}
+	})
+
+	t.Run("skips transfers without a source playlist", func(t *testing.T) {
+		recorder := &mockMigrationRecorder{}
+		engine := NewPlaylistEngine(&mockService{name: "Spotify", getPlaylistsErr: fmt.Errorf("offline")}, &mockService{}, nil)
+		engine.SetMigrationRecorder(recorder)
+
+		if _, err := engine.Run(context.Background(), "missing", nil); err == nil {
+			t.Fatal("Run() expected error for missing playlist")
+		}
+		if len(recorder.started) != 0 || len(recorder.records) != 0 {
+			t.Errorf("expected nothing recorded, got %d started and %d finished", len(recorder.started), len(recorder.records))
+		}
+	})
+}
+
+func TestPlaylistEngine_RunWithOpts(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Road Trip"},
+				Tracks:   []models.Track{{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts TransferOpts
+		want models.Playlist
+	}{
+		{
+			name: "defaults to private playlist named after source",
+			opts: TransferOpts{},
+			want: models.Playlist{Name: "Road Trip", Description: "Migrated from Spotify: Road Trip"},
+		},
+		{
+			name: "uses custom name, description, and visibility",
+			opts: TransferOpts{Name: " Summer ", Description: "Windows down", Public: true},
+			want: models.Playlist{Name: "Summer", Description: "Windows down", Public: true},
+		},
+		{
+			name: "blank name falls back to source name",
+			opts: TransferOpts{Name: "  ", Public: true},
+			want: models.Playlist{Name: "Road Trip", Description: "Migrated from Spotify: Road Trip", Public: true},
+		},
+		{
+			name: "renders name and description templates",
+			opts: TransferOpts{Name: "{{.SourceName}} ({{.TrackCount}})", Description: "To {{.DestService}}"},
+			want: models.Playlist{Name: "Road Trip (1)", Description: "To YouTube Music"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			youtube := &mockService{
+				name: "YouTube Music",
+				searchResults: map[string]*models.Track{
+					"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+				},
+				importResult: &models.Playlist{ID: "yt_playlist"},
+			}
+
+			engine := NewPlaylistEngine(spotify, youtube, nil)
+			if _, err := engine.RunWithOpts(context.Background(), "playlist123", tt.opts, nil); err != nil {
+				t.Fatalf("RunWithOpts() error = %v", err)
+			}
+
+			if youtube.imported == nil {
+				t.Fatal("expected destination playlist to be imported")
+			}
+			if got := youtube.imported.Playlist; got != tt.want {
+				t.Errorf("destination = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaylistEngine_Run_PreservesOrderAndAddedAt(t *testing.T) {
+	first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Road Trip"},
+				Tracks: []models.Track{
+					{ID: "track3", Title: "Song 3", Artists: []string{"Artist"}, AddedAt: first.Add(2 * time.Hour)},
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist"}, AddedAt: first},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist"}, AddedAt: first.Add(time.Hour)},
+				},
+			},
+		},
+	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist": {ID: "yt1"},
+			"Song 2|Artist": {ID: "yt2"},
+			"Song 3|Artist": {ID: "yt3"},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist"},
+	}
+
+	if _, err := NewPlaylistEngine(spotify, youtube, nil).Run(context.Background(), "playlist123", nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []struct {
+		id      string
+		addedAt time.Time
+	}{{"yt3", first.Add(2 * time.Hour)}, {"yt1", first}, {"yt2", first.Add(time.Hour)}}
+	if len(youtube.imported.Tracks) != len(want) {
+		t.Fatalf("imported %d tracks, want %d", len(youtube.imported.Tracks), len(want))
+	}
+	for i, track := range youtube.imported.Tracks {
+		if track.ID != want[i].id || !track.AddedAt.Equal(want[i].addedAt) {
+			t.Errorf("track %d = %s added %v, want %s added %v", i, track.ID, track.AddedAt, want[i].id, want[i].addedAt)
+		}
+	}
+	if !youtube.searchResults["Song 1|Artist"].AddedAt.IsZero() {
+		t.Error("expected search results to be left unchanged")
+	}
+}
+
+func TestPlaylistEngine_RunWithOpts_Reverse(t *testing.T) {
+	youtube := &mockService{
+		name: "YouTube Music",
+		playlistExports: map[string]*models.PlaylistExport{
+			"PL123": {
+				Playlist: models.Playlist{ID: "PL123", Name: "Liked Mix"},
+				Tracks: []models.Track{
+					{ID: "vid1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "vid2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
+			},
+		},
+	}
+	spotify := &mockService{
+		name: "Spotify",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "sp1", Title: "Song 1", Artists: []string{"Artist 1"}},
+			"Song 2|Artist 2": {ID: "sp2", Title: "Song 2", Artists: []string{"Artist 2"}},
+		},
+		importResult: &models.Playlist{ID: "sp_playlist", Name: "Liked Mix"},
+	}
+
+	searchCache := &mockSearchCache{entries: map[string]*models.Track{
+		"Song 1|Artist 1": {ID: "yt_cached", Title: "Song 1", Artists: []string{"Artist 1"}},
+	}}
+	trackCacher := &mockTrackCacher{}
+	recorder := &mockMigrationRecorder{}
+
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetSearchCache(searchCache)
+	engine.SetTrackCacher(trackCacher)
+	engine.SetMigrationRecorder(recorder)
+
+	result, err := engine.RunWithOpts(context.Background(), "PL123", TransferOpts{Reverse: true}, nil)
+	if err != nil {
+		t.Fatalf("RunWithOpts() error = %v", err)
+	}
+
+	if result.SourceService != "youtube" || result.DestService != "spotify" {
+		t.Errorf("expected youtube -> spotify, got %s -> %s", result.SourceService, result.DestService)
+	}
+	if result.SuccessCount != 2 || result.CachedSearches != 0 {
+		t.Errorf("expected 2 uncached matches, got %d (%d cached)", result.SuccessCount, result.CachedSearches)
+	}
+	if result.TrackMatches[0].Matched.ID != "sp1" {
+		t.Errorf("expected Spotify match, got %s", result.TrackMatches[0].Matched.ID)
+	}
+	if searchCache.stored != 0 {
+		t.Errorf("expected YouTube Music search cache to be skipped, got %d stores", searchCache.stored)
+	}
+
+	if spotify.imported == nil || spotify.imported.Playlist.Description != "Migrated from YouTube Music: Liked Mix" {
+		t.Errorf("expected playlist to be imported into Spotify, got %+v", spotify.imported)
+	}
+	if youtube.importCalled {
+		t.Error("expected nothing to be imported into YouTube Music")
+	}
+
+	if len(trackCacher.cached["youtube"]) != 2 || len(trackCacher.cached["spotify"]) != 2 {
+		t.Errorf("expected source and matched tracks cached per service, got %v", trackCacher.cached)
+	}
+	if len(recorder.records) != 1 || recorder.records[0].SourceService != "youtube" || recorder.records[0].TargetService != "spotify" {
+		t.Errorf("expected reverse transfer to be recorded, got %+v", recorder.records)
+	}
+}
+
+func TestPlaylistEngine_RunWithOpts_SameService(t *testing.T) {
+	personal := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"sp123": {
+				Playlist: models.Playlist{ID: "sp123", Name: "Focus"},
+				Tracks: []models.Track{
+					{ID: "sp1", Title: "Song 1", Artists: []string{"Artist 1"}, ISRC: "USAAA0000001"},
+					{ID: "sp2", Title: "Song 2", Artists: []string{"Artist 2"}},
+					{ID: "local1", Title: "Voice Memo", Artists: []string{"Me"}, URI: "spotify:local:Me:::Voice+Memo:60"},
+				},
+			},
+		},
+	}
+	work := &mockService{name: "Spotify", importResult: &models.Playlist{ID: "sp_work", Name: "Focus"}}
+	youtube := &mockService{name: "YouTube Music"}
+	recorder := &mockMigrationRecorder{}
+
+	engine := NewPlaylistEngine(personal, youtube, nil)
+	engine.SetMigrationRecorder(recorder)
+
+	result, err := engine.RunWithOpts(context.Background(), "sp123", TransferOpts{
+		Dest: &TransferAccount{ServiceKey: "spotify", Service: work},
+	}, nil)
+	if err != nil {
+		t.Fatalf("RunWithOpts() error = %v", err)
+	}
+
+	if result.SourceService != "spotify" || result.DestService != "spotify" {
+		t.Errorf("expected spotify -> spotify, got %s -> %s", result.SourceService, result.DestService)
+	}
+	if result.SuccessCount != 2 || result.SkippedCount != 1 {
+		t.Errorf("expected 2 copied tracks and the local file skipped, got %d copied, %d skipped", result.SuccessCount, result.SkippedCount)
+	}
+	for _, match := range result.TrackMatches[:2] {
+		if match.Method != MatchCopy || match.Matched.ID != match.Original.ID || match.Confidence != 1 {
+			t.Errorf("expected %s copied by ID, got %+v", match.Original.ID, match)
+		}
+	}
+
+	if work.imported == nil || len(work.imported.Tracks) != 2 || work.imported.Tracks[1].ID != "sp2" {
+		t.Errorf("expected the tracks imported into the second account, got %+v", work.imported)
+	}
+	if personal.importCalled || youtube.importCalled {
+		t.Error("expected nothing imported into the engine's services")
+	}
+	if youtube.exportCallCount != 0 {
+		t.Error("expected YouTube Music left untouched")
+	}
+	if len(recorder.records) != 1 || recorder.records[0].TargetService != "spotify" {
+		t.Errorf("expected the copy to be recorded, got %+v", recorder.records)
+	}
+
+	t.Run("missing account", func(t *testing.T) {
+		_, err := engine.RunWithOpts(context.Background(), "sp123", TransferOpts{
+			Dest: &TransferAccount{ServiceKey: "spotify"},
+		}, nil)
+		if !errors.Is(err, shared.ErrServiceUnavailable) {
+			t.Errorf("expected ErrServiceUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestPlaylistEngine_RunWithOpts_Collaborative(t *testing.T) {
+	source := func() *mockService {
+		return &mockService{
+			name: "Spotify",
+			playlistExports: map[string]*models.PlaylistExport{
+				"sp123": {
+					Playlist: models.Playlist{ID: "sp123", Name: "Shared", Owner: "Ada", Collaborative: true},
+					Tracks:   []models.Track{{ID: "sp1", Title: "Song 1", Artists: []string{"Artist 1"}}},
+				},
+			},
+		}
+	}
+	youtube := func() *mockService {
+		return &mockService{
+			name:          "YouTube Music",
+			searchResults: map[string]*models.Track{"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}}},
+			importResult:  &models.Playlist{ID: "created", Name: "Shared"},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		opts       func(dest *mockService) TransferOpts
+		wantCollab bool
+		want       string
+	}{
+		{
+			name: "to YouTube Music",
+			opts: func(*mockService) TransferOpts { return TransferOpts{} },
+			want: `"Shared" is collaborative, but YouTube Music playlists are created without collaborators`,
+		},
+		{
+			name: "to another Spotify account",
+			opts: func(dest *mockService) TransferOpts {
+				return TransferOpts{Dest: &TransferAccount{ServiceKey: "spotify", Service: dest}}
+			},
+			wantCollab: true,
+			want:       `"Shared" is collaborative; its collaborators need to be invited to the copy again`,
+		},
+		{
+			name: "to a public Spotify playlist",
+			opts: func(dest *mockService) TransferOpts {
+				return TransferOpts{Public: true, Dest: &TransferAccount{ServiceKey: "spotify", Service: dest}}
+			},
+			want: `"Shared" is collaborative, but public playlists can't be, so the copy is not`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			youtube := youtube()
+			dest := &mockService{name: "Spotify", importResult: &models.Playlist{ID: "created", Name: "Shared"}}
+			engine := NewPlaylistEngine(source(), youtube, nil)
+
+			result, err := engine.RunWithOpts(context.Background(), "sp123", tt.opts(dest), nil)
+			if err != nil {
+				t.Fatalf("RunWithOpts() error = %v", err)
+			}
+			if len(result.Warnings) != 1 || result.Warnings[0] != tt.want {
+				t.Errorf("expected warning %q, got %q", tt.want, result.Warnings)
+			}
+
+			imported := youtube.imported
+			if dest.importCalled {
+				imported = dest.imported
+			}
+			if imported == nil || imported.Playlist.Collaborative != tt.wantCollab {
+				t.Errorf("expected a collaborative destination: %v, got %+v", tt.wantCollab, imported)
+			}
+		})
+	}
+
+	t.Run("not collaborative", func(t *testing.T) {
+		spotify := source()
+		spotify.playlistExports["sp123"].Playlist.Collaborative = false
+		result, err := NewPlaylistEngine(spotify, youtube(), nil).RunWithOpts(context.Background(), "sp123", TransferOpts{}, nil)
+		if err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("expected no warnings, got %q", result.Warnings)
+		}
+	})
+}
+
+// Mock services that expose and accept playlist covers
+type mockCoverSource struct {
+	mockService
+	coverURL string
+}
+
+func (m *mockCoverSource) CoverImageURL(ctx context.Context, playlistID string) (string, error) {
+	if m.coverURL == "" {
+		return "", fmt.Errorf("no image available")
+	}
+	return m.coverURL + "/" + playlistID, nil
+}
+
+type mockCoverSetter struct {
+	mockService
+	coverFor    string
+	cover       []byte
+	contentType string
+}
+
+func (m *mockCoverSetter) SetPlaylistCover(ctx context.Context, playlistID string, image []byte, contentType string) error {
+	m.coverFor, m.cover, m.contentType = playlistID, image, contentType
+	return nil
+}
+
+func TestPlaylistEngine_RunWithOpts_CopyCover(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n cover")
+	export := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "playlist123", Name: "Road Trip"},
+		Tracks:   []models.Track{{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}}},
+	}
+	newYouTube := func() *mockCoverSetter {
+		return &mockCoverSetter{mockService: mockService{
+			name:          "YouTube Music",
+			searchResults: map[string]*models.Track{"Song 1|Artist 1": {ID: "yt1"}},
+			importResult:  &models.Playlist{ID: "yt_playlist"},
+		}}
+	}
+
+	t.Run("uploads the source cover", func(t *testing.T) {
+		spotify := &mockCoverSource{
+			mockService: mockService{name: "Spotify", playlistExports: map[string]*models.PlaylistExport{"playlist123": export}},
+			coverURL:    "https://covers.example",
+		}
+		youtube := newYouTube()
+
+		var downloaded string
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+		engine.downloadImage = func(url string) ([]byte, error) {
+			downloaded = url
+			return png, nil
+		}
+
+		result, err := engine.RunWithOpts(context.Background(), "playlist123", TransferOpts{CopyCover: true}, nil)
+		if err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		if !result.CoverCopied || result.CoverError != nil {
+			t.Errorf("expected cover to be copied, got error %v", result.CoverError)
+		}
+		if downloaded != "https://covers.example/playlist123" {
+			t.Errorf("downloaded %q, want the source playlist cover", downloaded)
+		}
+		if youtube.coverFor != "yt_playlist" || string(youtube.cover) != string(png) {
+			t.Errorf("cover uploaded to %q with %d bytes", youtube.coverFor, len(youtube.cover))
+		}
+		if youtube.contentType != "image/png" {
+			t.Errorf("content type = %q, want image/png", youtube.contentType)
+		}
+	})
+
+	t.Run("cover failures do not fail the transfer", func(t *testing.T) {
+		spotify := &mockCoverSource{
+			mockService: mockService{name: "Spotify", playlistExports: map[string]*models.PlaylistExport{"playlist123": export}},
+		}
+		youtube := newYouTube()
+
+		result, err := NewPlaylistEngine(spotify, youtube, nil).RunWithOpts(context.Background(), "playlist123", TransferOpts{CopyCover: true}, nil)
+		if err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		if result.CoverCopied || result.CoverError == nil {
+			t.Error("expected the missing cover to be reported")
+		}
+		if result.DestPlaylist == nil || youtube.coverFor != "" {
+			t.Error("expected the playlist to be created without a cover")
+		}
+	})
+
+	t.Run("destination without cover support", func(t *testing.T) {
+		spotify := &mockCoverSource{
+			mockService: mockService{name: "Spotify", playlistExports: map[string]*models.PlaylistExport{"playlist123": export}},
+			coverURL:    "https://covers.example",
+		}
+		youtube := &newYouTube().mockService
+
+		result, err := NewPlaylistEngine(spotify, youtube, nil).RunWithOpts(context.Background(), "playlist123", TransferOpts{CopyCover: true}, nil)
+		if err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		if !errors.Is(result.CoverError, shared.ErrNotImplemented) {
+			t.Errorf("CoverError = %v, want ErrNotImplemented", result.CoverError)
+		}
+	})
+}
+
+func TestPlaylistEngine_Run_Cancelled(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+					{ID: "track3", Title: "Song 3", Artists: []string{"Artist 3"}},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var searched []string
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+			"Song 2|Artist 2": {ID: "yt2", Title: "Song 2", Artists: []string{"Artist 2"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Playlist"},
+		onSearch: func(title string) {
+			searched = append(searched, title)
+			if title == "Song 2" {
+				cancel()
+			}
+		},
+	}
+
+	recorder := &mockMigrationRecorder{}
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	engine.SetMigrationRecorder(recorder)
+
+	result, err := engine.Run(ctx, "playlist123", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+
+	if result == nil || !result.Cancelled {
+		t.Fatalf("expected a partial result marked cancelled, got %+v", result)
+	}
+	if len(searched) != 2 {
+		t.Errorf("expected no searches after cancelling, got %v", searched)
+	}
+	if len(result.TrackMatches) != 1 || result.SuccessCount != 1 || result.FailedCount != 0 || result.TotalTracks != 3 {
+		t.Errorf("expected 1 of 3 tracks searched and matched, got %d matches, %d/%d of %d",
+			len(result.TrackMatches), result.SuccessCount, result.FailedCount, result.TotalTracks)
+	}
+	if youtube.importCalled || result.DestPlaylist != nil {
+		t.Error("expected no destination playlist to be created")
+	}
+	if len(recorder.records) != 1 || !strings.Contains(recorder.records[0].Error, "cancelled") {
+		t.Errorf("expected the cancelled transfer to be recorded, got %+v", recorder.records)
+	}
+}
+
+// limitedService caps playlist sizes and records every playlist imported
+type limitedService struct {
+	*mockService
+	limit    int
+	imported []*models.PlaylistExport
+}
+
+func (l *limitedService) MaxPlaylistSize() int {
+	return l.limit
+}
+
+func (l *limitedService) ImportPlaylist(ctx context.Context, playlist *models.PlaylistExport) (*models.Playlist, error) {
+	l.imported = append(l.imported, playlist)
+	return &models.Playlist{ID: fmt.Sprintf("part%d", len(l.imported)), Name: playlist.Playlist.Name, TrackCount: len(playlist.Tracks)}, nil
+}
+
+func TestPlaylistEngine_Run_Split(t *testing.T) {
+	tracks := make([]models.Track, 5)
+	searchResults := make(map[string]*models.Track)
+	for i := range tracks {
+		title := fmt.Sprintf("Song %d", i+1)
+		tracks[i] = models.Track{ID: fmt.Sprintf("t%d", i+1), Title: title, Artists: []string{"Artist"}}
+		searchResults[title+"|Artist"] = &models.Track{ID: fmt.Sprintf("yt%d", i+1), Title: title, Artists: []string{"Artist"}}
+	}
+	spotify := &mockService{
+		name:            "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{"p1": {Playlist: models.Playlist{ID: "p1", Name: "Big"}, Tracks: tracks}},
+	}
+
+	t.Run("fails before searching without split", func(t *testing.T) {
+		searched := 0
+		youtube := &limitedService{mockService: &mockService{name: "YouTube Music", onSearch: func(string) { searched++ }}, limit: 2}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+
+		_, err := engine.Run(context.Background(), "p1", nil)
+		if !errors.Is(err, shared.ErrInvalidInput) || !strings.Contains(err.Error(), "at most 2") {
+			t.Fatalf("Run() error = %v, want ErrInvalidInput naming the limit", err)
+		}
+		if searched != 0 || len(youtube.imported) != 0 {
+			t.Errorf("expected no searches or playlists, got %d searches and %d playlists", searched, len(youtube.imported))
+		}
+	})
+
+	t.Run("splits into numbered playlists", func(t *testing.T) {
+		youtube := &limitedService{mockService: &mockService{name: "YouTube Music", searchResults: searchResults}, limit: 2}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+
+		result, err := engine.RunWithOpts(context.Background(), "p1", TransferOpts{Split: true}, nil)
+		if err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		if len(youtube.imported) != 3 || len(result.DestParts) != 3 || result.DestPlaylist != result.DestParts[0] {
+			t.Fatalf("expected 3 parts, got %d imported and %+v", len(youtube.imported), result.DestParts)
+		}
+		for i, want := range []struct {
+			name  string
+			first string
+			count int
+		}{{"Big (1/3)", "yt1", 2}, {"Big (2/3)", "yt3", 2}, {"Big (3/3)", "yt5", 1}} {
+			part := youtube.imported[i]
+			if part.Playlist.Name != want.name || part.Tracks[0].ID != want.first || len(part.Tracks) != want.count {
+				t.Errorf("part %d = %s with %d tracks from %s, want %s with %d from %s",
+					i+1, part.Playlist.Name, len(part.Tracks), part.Tracks[0].ID, want.name, want.count, want.first)
+			}
+		}
+	})
+
+	t.Run("keeps one playlist within the limit", func(t *testing.T) {
+		youtube := &limitedService{mockService: &mockService{name: "YouTube Music", searchResults: searchResults}, limit: 5}
+		engine := NewPlaylistEngine(spotify, youtube, nil)
+
+		result, err := engine.RunWithOpts(context.Background(), "p1", TransferOpts{Split: true}, nil)
+		if err != nil {
+			t.Fatalf("RunWithOpts() error = %v", err)
+		}
+		if len(youtube.imported) != 1 || youtube.imported[0].Playlist.Name != "Big" || result.DestParts != nil {
+			t.Errorf("expected a single unnumbered playlist, got %d", len(youtube.imported))
+		}
+	})
+}
+
+func TestPlaylistEngine_Run_RateLimited(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"p1": {
+				Playlist: models.Playlist{ID: "p1", Name: "Playlist"},
+				Tracks: []models.Track{
+					{ID: "t1", Title: "Song 1", Artists: []string{"Artist"}},
+					{ID: "t2", Title: "Song 2", Artists: []string{"Artist"}},
+				},
+			},
+		},
+	}
+	youtube := &throttledService{
+		mockService: &mockService{
+			name: "YouTube Music",
+			searchResults: map[string]*models.Track{
+				"Song 1|Artist": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist"}},
+				"Song 2|Artist": {ID: "yt2", Title: "Song 2", Artists: []string{"Artist"}},
+			},
+			importResult: &models.Playlist{ID: "ytp", Name: "Playlist"},
+		},
+		throttle: 1,
+	}
+
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+	progressCh := make(chan ProgressUpdate, 100)
+	result, err := engine.Run(context.Background(), "p1", progressCh)
+	close(progressCh)
+
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.SuccessCount != 2 || result.FailedCount != 0 || youtube.calls != 3 {
+		t.Errorf("expected the rate-limited search retried, got %d/%d after %d searches", result.SuccessCount, result.FailedCount, youtube.calls)
+	}
+	rateLimited := false
+	for update := range progressCh {
+		rateLimited = rateLimited || update.Phase == RateLimited
+	}
+	if !rateLimited {
+		t.Error("expected a rate limit update")
+	}
+}
+
+func TestPlaylistEngine_Run_ServiceErrors(t *testing.T) {
+	t.Run("spotify service not initialized", func(t *testing.T) {
+		engine := NewPlaylistEngine(nil, &mockService{}, nil)
+		progressCh := make(chan ProgressUpdate, 10)
+
+		_, err := engine.Run(context.Background(), "playlist123", progressCh)
+		close(progressCh)
+
+		if err == nil {
+			t.Error("Run() expected error for nil spotify service")
+		}
+		if err != nil && !errors.Is(err, shared.ErrServiceUnavailable) {
+			if !strings.Contains(err.Error(), "not initialized") {
+				t.Errorf("Run() error should mention service not initialized, got: %v", err)
+			}
+		}
+	})
+
+	t.Run("youtube service not initialized", func(t *testing.T) {
+		engine := NewPlaylistEngine(&mockService{}, nil, nil)
+		progressCh := make(chan ProgressUpdate, 10)
+
+		_, err := engine.Run(context.Background(), "playlist123", progressCh)
+		close(progressCh)
+
+		if err == nil {
+			t.Error("Run() expected error for nil youtube service")
+		}
+	})
+}
+
+func TestPlaylistEngine_Diff(t *testing.T) {
+	sourceExport := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "src", Name: "Source"},
+		Tracks: []models.Track{
+			{ID: "1", Title: "Track 1", Artists: []string{"Artist A"}, ISRC: "ISRC1"},
+			{ID: "2", Title: "Track 2", Artists: []string{"Artist B"}, ISRC: "ISRC2"},
+			{ID: "3", Title: "Track 3", Artists: []string{"Artist C"}, ISRC: "ISRC3"},
+		},
+	}
+
+	destExport := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "dest", Name: "Destination"},
+		Tracks: []models.Track{
+			{ID: "10", Title: "Track 1", Artists: []string{"Artist A"}, ISRC: "ISRC1"}, // Match by ISRC
+			{ID: "20", Title: "Track 2", Artists: []string{"Artist B"}},                // Match by title+artist
+			{ID: "40", Title: "Track 4", Artists: []string{"Artist D"}, ISRC: "ISRC4"}, // Extra track
+		},
+	}
+
+	sourceSvc := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"src": sourceExport,
+		},
+	}
+
+	destSvc := &mockService{
+		name: "YouTube Music",
+		playlistExports: map[string]*models.PlaylistExport{
+			"dest": destExport,
+		},
+	}
+
+	engine := NewPlaylistEngine(nil, nil, nil)
+
+	progressCh := make(chan ProgressUpdate, 100)
+	go func() {
+		for range progressCh {
+			// Drain progress channel
+		}
+	}()
+
+	result, err := engine.Diff(context.Background(), sourceSvc, destSvc, "src", "dest", progressCh)
+	close(progressCh)
+
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	if result.Comparison.MatchedCount != 2 {
+		t.Errorf("Diff() matchedCount = %v, want 2", result.Comparison.MatchedCount)
+	}
+
+	if len(result.Comparison.Matched) != 2 || result.Comparison.Matched[0].ID != "1" || result.Comparison.Matched[1].ID != "2" {
+		t.Errorf("Diff() matched = %+v, want source tracks 1 and 2", result.Comparison.Matched)
+	}
+
+	if len(result.Comparison.MissingInDest) != 1 {
+		t.Errorf("Diff() missingInDest count = %v, want 1", len(result.Comparison.MissingInDest))
+	} else if result.Comparison.MissingInDest[0].ID != "3" {
+		t.Errorf("Diff() missing track ID = %v, want '3'", result.Comparison.MissingInDest[0].ID)
+	}
+
+	if len(result.Comparison.ExtraInDest) != 1 {
+		t.Errorf("Diff() extraInDest count = %v, want 1", len(result.Comparison.ExtraInDest))
+	} else if result.Comparison.ExtraInDest[0].ID != "40" {
+		t.Errorf("Diff() extra track ID = %v, want '40'", result.Comparison.ExtraInDest[0].ID)
+	}
+
+	if len(result.Phases) != 3 || result.Phases[2].Phase != Compare || result.CompletedAt.IsZero() {
+		t.Errorf("Diff() timing = %+v, want fetch source, fetch dest, and compare phases", result.Timing)
+	}
+}
+
+func TestPlaylistEngine_DiffTargets(t *testing.T) {
+	snapshot := &models.PlaylistExport{
+		Playlist: models.Playlist{Name: "Snapshot"},
+		Tracks:   []models.Track{{Title: "Kept", Artists: []string{"A"}}, {Title: "Removed", Artists: []string{"B"}}},
+	}
+	liveSvc := &mockService{
+		name: "YouTube Music",
+		playlistExports: map[string]*models.PlaylistExport{
+			"live": {Playlist: models.Playlist{Name: "Live"}, Tracks: []models.Track{{Title: "Kept", Artists: []string{"A"}}}},
+		},
+	}
+	engine := NewPlaylistEngine(nil, nil, nil)
+
+	t.Run("snapshot against live playlist", func(t *testing.T) {
+		result, err := engine.DiffTargets(context.Background(), DiffTarget{Export: snapshot}, DiffTarget{PlaylistID: "live", Service: liveSvc}, nil)
+		if err != nil {
+			t.Fatalf("DiffTargets() error = %v", err)
+		}
+		if result.Comparison.MatchedCount != 1 || len(result.Comparison.MissingInDest) != 1 || result.Comparison.MissingInDest[0].Title != "Removed" {
+			t.Errorf("unexpected comparison %+v", result.Comparison)
+		}
+		if liveSvc.exportCallCount != 1 {
+			t.Errorf("expected only the live playlist to be fetched, got %d exports", liveSvc.exportCallCount)
+		}
+	})
+
+	t.Run("live playlist against snapshot", func(t *testing.T) {
+		result, err := engine.DiffTargets(context.Background(), DiffTarget{PlaylistID: "live", Service: liveSvc}, DiffTarget{Export: snapshot}, nil)
+		if err != nil {
+			t.Fatalf("DiffTargets() error = %v", err)
+		}
+		if len(result.Comparison.ExtraInDest) != 1 || result.Comparison.ExtraInDest[0].Title != "Removed" {
+			t.Errorf("unexpected comparison %+v", result.Comparison)
+		}
+	})
+
+	t.Run("duet credited differently", func(t *testing.T) {
+		duet := models.Track{Title: "Under Pressure", Artists: []string{"Queen", "David Bowie"}}
+		source := &models.PlaylistExport{Tracks: []models.Track{duet, {Title: "Under Pressure", Artists: []string{"Annie Lennox"}}}}
+		dest := &models.PlaylistExport{Tracks: []models.Track{{Title: "Under Pressure (Remastered 2011)", Artists: []string{"David Bowie - Topic"}}}}
+
+		result, err := engine.DiffTargets(context.Background(), DiffTarget{Export: source}, DiffTarget{Export: dest}, nil)
+		if err != nil {
+			t.Fatalf("DiffTargets() error = %v", err)
+		}
+		if result.Comparison.MatchedCount != 1 || len(result.Comparison.MissingInDest) != 1 || result.Comparison.MissingInDest[0].Artist() != "Annie Lennox" {
+			t.Errorf("expected only the duet matched, got %+v", result.Comparison)
+		}
+	})
+
+	t.Run("same title sharing a featured artist", func(t *testing.T) {
+		source := &models.PlaylistExport{Tracks: []models.Track{{Title: "Only Love", Artists: []string{"Calvin Harris", "Rihanna"}}}}
+		dest := &models.PlaylistExport{Tracks: []models.Track{{Title: "Only Love", Artists: []string{"Kygo", "Rihanna"}}}}
+
+		result, err := engine.DiffTargets(context.Background(), DiffTarget{Export: source}, DiffTarget{Export: dest}, nil)
+		if err != nil {
+			t.Fatalf("DiffTargets() error = %v", err)
+		}
+		if result.Comparison.MatchedCount != 0 || len(result.Comparison.MissingInDest) != 1 || len(result.Comparison.ExtraInDest) != 1 {
+			t.Errorf("expected different songs sharing a featured artist not to match, got %+v", result.Comparison)
+		}
+	})
+
+	t.Run("neither export nor service", func(t *testing.T) {
+		_, err := engine.DiffTargets(context.Background(), DiffTarget{Export: snapshot}, DiffTarget{PlaylistID: "live"}, nil)
+		if !errors.Is(err, shared.ErrServiceUnavailable) {
+			t.Errorf("expected ErrServiceUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestPlaylistEngine_Diff_IgnoreList(t *testing.T) {
+	sourceSvc := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"src": {
+				Playlist: models.Playlist{ID: "src", Name: "Source"},
+				Tracks: []models.Track{
+					{ID: "1", Title: "Track 1", Artists: []string{"Artist A"}, ISRC: "ISRC1"},
+					{ID: "2", Title: "Episode 1", Artists: []string{"Some Podcast"}},
+				},
+			},
+		},
+	}
+	destSvc := &mockService{
+		name: "YouTube Music",
+		playlistExports: map[string]*models.PlaylistExport{
+			"dest": {
+				Playlist: models.Playlist{ID: "dest", Name: "Destination"},
+				Tracks: []models.Track{
+					{ID: "10", Title: "Track 1", Artists: []string{"Artist A"}, ISRC: "ISRC1"},
+					{ID: "30", Title: "Placeholder", Artists: []string{"Artist C"}, ISRC: "LOCAL1"},
+				},
+			},
+		},
+	}
+
+	engine := NewPlaylistEngine(nil, nil, nil)
+	engine.SetIgnoreList(&mockIgnoreList{ignored: map[string]bool{"Some Podcast": true, "LOCAL1": true}})
+
+	result, err := engine.Diff(context.Background(), sourceSvc, destSvc, "src", "dest", nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	comparison := result.Comparison
+	if comparison.MatchedCount != 1 || len(comparison.MissingInDest) != 0 || len(comparison.ExtraInDest) != 0 {
+		t.Errorf("expected ignored tracks to be neither missing nor extra, got %+v", comparison)
+	}
+	if len(comparison.Ignored) != 2 || comparison.Ignored[0].ID != "2" || comparison.Ignored[1].ID != "30" {
+		t.Errorf("Ignored = %+v, want tracks 2 and 30", comparison.Ignored)
+	}
+	if len(comparison.SourcePlaylist.Tracks) != 2 || len(comparison.DestPlaylist.Tracks) != 2 {
+		t.Error("expected the comparison to keep the full playlists")
+	}
+}
+
+// Mock service that records tracks added to existing playlists
+type mockAppenderService struct {
+	mockService
+	addedTo string
+	added   []models.Track
+	addErr  error
+}
+
+func (m *mockAppenderService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.addedTo = playlistID
+	m.added = tracks
+	return nil
+}
+
+func TestPlaylistEngine_PushMissing(t *testing.T) {
+	missing := []models.Track{
+		{ID: "3", Title: "Track 3", Artists: []string{"Artist C"}},
+		{ID: "4", Title: "Track 4", Artists: []string{"Artist D"}},
+	}
+
+	t.Run("adds matched tracks to the destination playlist", func(t *testing.T) {
+		destSvc := &mockAppenderService{mockService: mockService{
+			name: "YouTube Music",
+			searchResults: map[string]*models.Track{
+				"Track 3|Artist C": {ID: "yt3", Title: "Track 3", Artists: []string{"Artist C"}},
+			},
+		}}
+		engine := NewPlaylistEngine(nil, nil, nil)
+
+		result, err := engine.PushMissing(context.Background(), destSvc, "dest", missing, nil)
+		if err != nil {
+			t.Fatalf("PushMissing() error = %v", err)
+		}
+
+		if result.AddedCount != 1 || result.FailedCount != 1 {
+			t.Errorf("PushMissing() added = %d, failed = %d, want 1 and 1", result.AddedCount, result.FailedCount)
+		}
+		if len(result.TrackMatches) != 2 {
+			t.Errorf("PushMissing() track matches = %d, want 2", len(result.TrackMatches))
+		}
+		if destSvc.addedTo != "dest" || len(destSvc.added) != 1 || destSvc.added[0].ID != "yt3" {
+			t.Errorf("AddTracks() called with %q %+v, want dest [yt3]", destSvc.addedTo, destSvc.added)
+		}
+	})
+
+	t.Run("errors when no tracks match", func(t *testing.T) {
+		destSvc := &mockAppenderService{mockService: mockService{name: "YouTube Music"}}
+		engine := NewPlaylistEngine(nil, nil, nil)
+
+		result, err := engine.PushMissing(context.Background(), destSvc, "dest", missing, nil)
+		if err == nil {
+			t.Fatal("PushMissing() expected error when no tracks match")
+		}
+		if result.FailedCount != 2 || destSvc.added != nil {
+			t.Errorf("PushMissing() failed = %d, added = %+v, want 2 failures and no tracks added", result.FailedCount, destSvc.added)
+		}
+	})
+
+	t.Run("wraps add errors", func(t *testing.T) {
+		destSvc := &mockAppenderService{
+			mockService: mockService{
+				name:          "YouTube Music",
+				searchResults: map[string]*models.Track{"Track 3|Artist C": {ID: "yt3"}},
+			},
+			addErr: errors.New("quota exceeded"),
+		}
+		engine := NewPlaylistEngine(nil, nil, nil)
+
+		_, err := engine.PushMissing(context.Background(), destSvc, "dest", missing, nil)
+		if !errors.Is(err, shared.ErrAPIRequest) {
+			t.Errorf("PushMissing() error = %v, want ErrAPIRequest", err)
+		}
+	})
+}
+
+// Mock playlist store for testing
+type mockPlaylistStore struct {
+	playlists map[string]*models.PlaylistExport
+}
+
+func (m *mockPlaylistStore) LoadPlaylist(ctx context.Context, service, playlistID string) (*models.PlaylistExport, error) {
+	if export, ok := m.playlists[service+":"+playlistID]; ok {
+		return export, nil
+	}
+	return nil, fmt.Errorf("playlist not cached")
+}
+
+func TestPlaylistEngine_DiffOffline(t *testing.T) {
+	sourceExport := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "src", Name: "Source"},
+		Tracks: []models.Track{
+			{ID: "1", Title: "Track 1", Artists: []string{"Artist A"}, ISRC: "ISRC1"},
+			{ID: "2", Title: "Track 2", Artists: []string{"Artist B"}},
+		},
+	}
+	destExport := &models.PlaylistExport{
+		Playlist: models.Playlist{ID: "dest", Name: "Destination"},
+		Tracks: []models.Track{
+			{ID: "10", Title: "Track 1", Artists: []string{"Artist A"}, ISRC: "ISRC1"},
+		},
+	}
+
+	store := &mockPlaylistStore{playlists: map[string]*models.PlaylistExport{"spotify:src": sourceExport}}
+
+	t.Run("falls back to live fetch for uncached playlists", func(t *testing.T) {
+		destSvc := &mockService{
+			name:            "YouTube Music",
+			playlistExports: map[string]*models.PlaylistExport{"dest": destExport},
+		}
+		engine := NewPlaylistEngine(nil, nil, nil)
+		cacher := &mockPlaylistCacher{}
+		engine.SetPlaylistCacher(cacher)
+
+		result, err := engine.DiffOffline(context.Background(), store,
+			DiffTarget{ServiceKey: "spotify", PlaylistID: "src"},
+			DiffTarget{ServiceKey: "youtube", PlaylistID: "dest", Service: destSvc},
+			nil,
+		)
+		if err != nil {
+			t.Fatalf("DiffOffline() error = %v", err)
+		}
+
+		if !result.SourceCached || result.DestCached {
+			t.Errorf("expected cached source and live destination, got source=%v dest=%v", result.SourceCached, result.DestCached)
+		}
+		if result.Comparison.MatchedCount != 1 || len(result.Comparison.MissingInDest) != 1 {
+			t.Errorf("unexpected comparison: matched=%d missing=%d", result.Comparison.MatchedCount, len(result.Comparison.MissingInDest))
+		}
+		if _, ok := cacher.cached["youtube:dest"]; !ok {
+			t.Error("expected live-fetched playlist to be cached")
+		}
+	})
+
+	t.Run("errors when uncached and no service", func(t *testing.T) {
+		engine := NewPlaylistEngine(nil, nil, nil)
+
+		_, err := engine.DiffOffline(context.Background(), store,
+			DiffTarget{ServiceKey: "spotify", PlaylistID: "src"},
+			DiffTarget{ServiceKey: "youtube", PlaylistID: "dest"},
+			nil,
+		)
+		if !errors.Is(err, shared.ErrPlaylistNotFound) {
+			t.Errorf("expected ErrPlaylistNotFound, got %v", err)
+		}
+	})
+}
+
+func TestPlaylistEngine_Dump(t *testing.T) {
+	apiClient := &mockAPIClient{
+		responses: map[string]*services.APIResponse{
+			"/health": {
+				StatusCode: 200,
+				IsJSON:     true,
+				JSONData:   map[string]string{"status": "ok"},
+			},
+			"/api/library/playlists": {
+				StatusCode: 200,
+				IsJSON:     true,
+				JSONData:   []string{"playlist1", "playlist2"},
+			},
+			"/api/library/songs": {
+				StatusCode: 500,
+				Body:       []byte("internal error"),
+			},
+		},
+	}
+
+	engine := NewPlaylistEngine(nil, nil, apiClient)
+
+	progressCh := make(chan ProgressUpdate, 100)
+	progressUpdates := []ProgressUpdate{}
+	done := make(chan bool)
+
+	go func() {
+		for update := range progressCh {
+			progressUpdates = append(progressUpdates, update)
+		}
+		done <- true
+	}()
+
+	result, err := engine.Dump(context.Background(), progressCh)
+	close(progressCh)
+	<-done
+
+	if err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	if result.Health == nil {
+		t.Error("Dump() health data should not be nil")
+	}
+
+	if result.Playlists == nil {
+		t.Error("Dump() playlists data should not be nil")
+	}
+
+	if len(result.Errors) == 0 {
+		t.Error("Dump() should have errors for failed endpoints")
+	}
+
+	if len(progressUpdates) == 0 {
+		t.Error("Dump() should send progress updates")
+	}
+
+	// Both upload endpoints count toward one phase
+	if len(result.Phases) != 8 || result.CompletedAt.IsZero() {
+		t.Errorf("Dump() timing = %+v, want one entry per phase", result.Timing)
+	}
+}
+
+// blockingAPIClient answers every path immediately except block, which waits for the request to be cancelled
+type blockingAPIClient struct {
+	block string
+}
+
+func (c *blockingAPIClient) Get(ctx context.Context, path string) (*services.APIResponse, error) {
+	if path == c.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &services.APIResponse{StatusCode: 200, IsJSON: true, JSONData: path}, nil
+}
+
+func TestPlaylistEngine_Dump_Sections(t *testing.T) {
+	engine := NewPlaylistEngine(nil, nil, &blockingAPIClient{block: "/api/library/history"})
+	engine.SetDumpTimeout(50 * time.Millisecond)
+
+	// Unbuffered, so every section update must wait for the reader rather than be dropped
+	progressCh := make(chan ProgressUpdate)
+	var sections []EndpointResult
+	done := make(chan bool)
+	go func() {
+		for update := range progressCh {
+			if section, ok := update.Data.(EndpointResult); ok {
+				sections = append(sections, section)
+			}
+		}
+		done <- true
+	}()
+
+	result, err := engine.Dump(context.Background(), progressCh)
+	close(progressCh)
+	<-done
+
+	if err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+	if len(sections) != 9 {
+		t.Fatalf("expected 9 section updates, got %d", len(sections))
+	}
+	if last := sections[len(sections)-1]; last.Name != "history" || !errors.Is(last.Error, context.DeadlineExceeded) {
+		t.Errorf("expected the timed-out history section last, got %+v", last)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Endpoint != "/api/library/history" {
+		t.Errorf("expected only history to fail, got %+v", result.Errors)
+	}
+	if result.History != nil || result.UploadedAlbums != "/api/uploads/albums" {
+		t.Errorf("unexpected dump data: history=%v uploaded_albums=%v", result.History, result.UploadedAlbums)
+	}
+}
+
+// pagedAPIClient serves songs in two pages joined by a continuation, and every other endpoint as a bare array
+type pagedAPIClient struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (c *pagedAPIClient) Get(ctx context.Context, path string) (*services.APIResponse, error) {
+	c.mu.Lock()
+	c.paths = append(c.paths, path)
+	c.mu.Unlock()
+
+	var data any = []any{path}
+	switch path {
+	case "/api/library/songs?limit=100":
+		data = map[string]any{"items": []any{"a", "b"}, "continuation": "c1"}
+	case "/api/library/songs?continuation=c1&limit=100":
+		data = map[string]any{"items": []any{"c"}}
+	}
+	return &services.APIResponse{StatusCode: 200, IsJSON: true, JSONData: data}, nil
+}
+
+func TestPlaylistEngine_Dump_Full(t *testing.T) {
+	client := &pagedAPIClient{}
+	engine := NewPlaylistEngine(nil, nil, client)
+	engine.SetDumpFull(true)
+
+	progressCh := make(chan ProgressUpdate, 100)
+	result, err := engine.Dump(context.Background(), progressCh)
+	close(progressCh)
+	if err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	songs, ok := result.Songs.([]any)
+	if !ok || len(songs) != 3 || songs[0] != "a" || songs[2] != "c" {
+		t.Errorf("expected songs from both pages, got %v", result.Songs)
+	}
+	if result.Health == nil || len(result.Errors) != 0 {
+		t.Errorf("unexpected dump: health=%v errors=%+v", result.Health, result.Errors)
+	}
+	if !slices.Contains(client.paths, "/health") || !slices.Contains(client.paths, "/api/library/history?limit=100") {
+		t.Errorf("expected health unpaged and history paged, got %v", client.paths)
+	}
+
+	// Page updates read only after Dump returns are coalesced, but the last one always arrives
+	lastPage := 0
+	for update := range progressCh {
+		if update.Phase == FetchSongs && strings.HasPrefix(update.Message, "Fetched page") {
+			lastPage = update.Step
+		}
+	}
+	if lastPage != 2 {
+		t.Errorf("expected the last page update for songs to be page 2, got %d", lastPage)
+	}
+}
+
+func TestPlaylistEngine_Dump_APIClientError(t *testing.T) {
+	engine := NewPlaylistEngine(nil, nil, nil)
+	progressCh := make(chan ProgressUpdate, 10)
+
+	_, err := engine.Dump(context.Background(), progressCh)
+	close(progressCh)
+
+	if err == nil {
+		t.Error("Dump() expected error for nil API client")
+	}
+}
+
+// Mock library persister for testing
+type mockLibraryPersister struct {
+	playlists []models.Playlist
+	tracks    []models.Track
+	albums    []models.Album
+	artists   []models.Artist
+	trackErr  error
+}
+
+func (m *mockLibraryPersister) UpsertPlaylist(ctx context.Context, service string, playlist models.Playlist) error {
+	m.playlists = append(m.playlists, playlist)
+	return nil
+}
+
+func (m *mockLibraryPersister) UpsertTrack(ctx context.Context, service string, track models.Track) error {
+	if m.trackErr != nil {
+		return m.trackErr
+	}
+	m.tracks = append(m.tracks, track)
+	return nil
+}
+
+func (m *mockLibraryPersister) UpsertAlbum(ctx context.Context, service string, album models.Album) error {
+	m.albums = append(m.albums, album)
+	return nil
+}
+
+func (m *mockLibraryPersister) UpsertArtist(ctx context.Context, service string, artist models.Artist) error {
+	m.artists = append(m.artists, artist)
+	return nil
+}
+
+func TestPersistDump(t *testing.T) {
+	dump := &DumpResult{
+		Playlists: []any{
+			map[string]any{"playlistId": "PL1", "title": "Road Trip", "count": 12, "privacy": "PUBLIC"},
+			map[string]any{"title": "No ID"},
+		},
+		Songs: []any{
+			map[string]any{
+				"videoId":          "vid1",
+				"title":            "Song One",
+				"artists":          []any{map[string]any{"name": "Artist A", "id": "UC1"}},
+				"album":            map[string]any{"name": "Album A", "id": "MPRE1"},
+				"duration_seconds": 200,
+			},
+		},
+		Albums: []any{
+			map[string]any{"browseId": "MPRE1", "title": "Album A", "year": "2020", "artists": []any{map[string]any{"name": "Artist A"}}},
+		},
+		Artists: []any{
+			map[string]any{"browseId": "UC1", "artist": "Artist A"},
+		},
+	}
+
+	t.Run("upserts all sections", func(t *testing.T) {
+		persister := &mockLibraryPersister{}
+
+		summary, err := PersistDump(t.Context(), dump, persister)
+		if err != nil {
+			t.Fatalf("PersistDump() error = %v", err)
+		}
+
+		if summary.Playlists != 1 || summary.Songs != 1 || summary.Albums != 1 || summary.Artists != 1 {
+			t.Errorf("unexpected counts: %+v", summary)
+		}
+		if summary.Skipped != 1 {
+			t.Errorf("expected 1 skipped entry, got %d", summary.Skipped)
+		}
+		if !persister.playlists[0].Public {
+			t.Error("expected public playlist")
+		}
+		if persister.tracks[0].Artist() != "Artist A" || persister.tracks[0].Album != "Album A" {
+			t.Errorf("unexpected track mapping: %+v", persister.tracks[0])
+		}
+		if persister.albums[0].Artist != "Artist A" {
+			t.Errorf("expected album artist 'Artist A', got %q", persister.albums[0].Artist)
+		}
+		if persister.artists[0].Name != "Artist A" {
+			t.Errorf("expected artist name 'Artist A', got %q", persister.artists[0].Name)
+		}
+	})
+
+	t.Run("collects upsert errors", func(t *testing.T) {
+		persister := &mockLibraryPersister{trackErr: errors.New("db locked")}
+
+		summary, err := PersistDump(t.Context(), dump, persister)
+		if err != nil {
+			t.Fatalf("PersistDump() error = %v", err)
+		}
+		if summary.Songs != 0 || len(summary.Errors) != 1 {
+			t.Errorf("expected one collected error, got %+v", summary)
+		}
+	})
+
+	t.Run("nil persister", func(t *testing.T) {
+		if _, err := PersistDump(t.Context(), dump, nil); err == nil {
+			t.Error("expected error for nil persister")
+		}
+	})
 }
 
 func TestProgressUpdate_NonBlocking(t *testing.T) {
@@ -454,14 +2269,14 @@ func TestProgressUpdate_NonBlocking(t *testing.T) {
 			playlistExports: map[string]*models.PlaylistExport{
 				"p1": {
 					Playlist: models.Playlist{ID: "p1", Name: "Test"},
-					Tracks:   []models.Track{{ID: "t1", Title: "Song", Artist: "Artist"}},
+					Tracks:   []models.Track{{ID: "t1", Title: "Song", Artists: []string{"Artist"}}},
 				},
 			},
 		},
 		&mockService{
 			name: "YouTube Music",
 			searchResults: map[string]*models.Track{
-				"Song|Artist": {ID: "yt1", Title: "Song", Artist: "Artist"},
+				"Song|Artist": {ID: "yt1", Title: "Song", Artists: []string{"Artist"}},
 			},
 			importResult: &models.Playlist{ID: "ytp1", Name: "Test", TrackCount: 1},
 		},
@@ -0,0 +1,85 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// Default destination playlist templates, used when neither [TransferOpts] nor [PlaylistEngine.SetPlaylistTemplates]
+// give one.
+const (
+	DefaultNameTemplate        = "{{.SourceName}}"
+	DefaultDescriptionTemplate = "Migrated from {{.SourceService}}: {{.SourceName}}"
+)
+
+// PlaylistTemplateData holds the variables of destination playlist name and description templates.
+type PlaylistTemplateData struct {
+	SourceName        string // Source playlist name
+	SourceDescription string // Source playlist description
+	SourceService     string // Source service display name, e.g. "Spotify"
+	DestService       string // Destination service display name
+	TrackCount        int    // Tracks in the source playlist
+	Date              string // Transfer date as YYYY-MM-DD
+}
+
+// RenderPlaylistTemplate executes a destination name or description template (Go text/template syntax) with data.
+// Text without actions is returned as is. Invalid templates fail with [shared.ErrInvalidInput].
+func RenderPlaylistTemplate(text string, data PlaylistTemplateData) (string, error) {
+	tmpl, err := template.New("playlist").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid playlist template %q: %v", shared.ErrInvalidInput, text, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: invalid playlist template %q: %v", shared.ErrInvalidInput, text, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// SetPlaylistTemplates sets the name and description templates of destination playlists for transfers whose
+// [TransferOpts] leave them blank, as configured in config.toml. Blank templates restore the defaults.
+func (e *PlaylistEngine) SetPlaylistTemplates(name, description string) {
+	e.nameTemplate = strings.TrimSpace(name)
+	e.descriptionTemplate = strings.TrimSpace(description)
+}
+
+// Destination returns the playlist a transfer of src with opts creates, rendering the name and description
+// templates from opts, then those set with [PlaylistEngine.SetPlaylistTemplates], then the defaults.
+//
+// A collaborative source makes a collaborative Spotify destination unless opts.Public is set, as public playlists
+// can't be collaborative; YouTube Music playlists never are.
+func (e *PlaylistEngine) Destination(src *models.PlaylistExport, opts TransferOpts) (models.Playlist, error) {
+	route := e.route(opts)
+	data := PlaylistTemplateData{
+		SourceName:        src.Playlist.Name,
+		SourceDescription: src.Playlist.Description,
+		TrackCount:        len(src.Tracks),
+		Date:              time.Now().Format(time.DateOnly),
+	}
+	if route.source != nil {
+		data.SourceService = route.source.Name()
+	}
+	if route.dest != nil {
+		data.DestService = route.dest.Name()
+	}
+
+	name, err := RenderPlaylistTemplate(cmp.Or(strings.TrimSpace(opts.Name), e.nameTemplate, DefaultNameTemplate), data)
+	if err != nil {
+		return models.Playlist{}, err
+	}
+	// A template rendering blank, e.g. from an empty variable, still leaves the playlist a name
+	name = cmp.Or(name, src.Playlist.Name)
+
+	description, err := RenderPlaylistTemplate(cmp.Or(strings.TrimSpace(opts.Description), e.descriptionTemplate, DefaultDescriptionTemplate), data)
+	if err != nil {
+		return models.Playlist{}, err
+	}
+	collaborative := src.Playlist.Collaborative && !opts.Public && route.destKey == "spotify"
+	return models.Playlist{Name: name, Description: description, Public: opts.Public, Collaborative: collaborative}, nil
+}
@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestPlaylistEngine_Destination(t *testing.T) {
+	src := &models.PlaylistExport{
+		Playlist: models.Playlist{Name: "Road Trip", Description: "Long drives"},
+		Tracks:   []models.Track{{Title: "Song 1"}, {Title: "Song 2"}},
+	}
+	engine := NewPlaylistEngine(&mockService{name: "Spotify"}, &mockService{name: "YouTube Music"}, nil)
+	engine.SetPlaylistTemplates("{{.SourceName}} {{.Date}}", "{{.SourceDescription}} - {{.TrackCount}} tracks from {{.SourceService}}")
+
+	got, err := engine.Destination(src, TransferOpts{})
+	if err != nil {
+		t.Fatalf("Destination() error = %v", err)
+	}
+	want := models.Playlist{Name: "Road Trip " + time.Now().Format(time.DateOnly), Description: "Long drives - 2 tracks from Spotify"}
+	if got != want {
+		t.Errorf("Destination() = %+v, want %+v", got, want)
+	}
+
+	t.Run("options override configured templates", func(t *testing.T) {
+		got, err := engine.Destination(src, TransferOpts{Name: "Plain", Reverse: true})
+		if err != nil {
+			t.Fatalf("Destination() error = %v", err)
+		}
+		if got.Name != "Plain" || got.Description != "Long drives - 2 tracks from YouTube Music" {
+			t.Errorf("unexpected destination %+v", got)
+		}
+	})
+
+	t.Run("blank name falls back to the source name", func(t *testing.T) {
+		got, err := engine.Destination(&models.PlaylistExport{Playlist: models.Playlist{Name: "Mix"}}, TransferOpts{Name: "{{.SourceDescription}}"})
+		if err != nil || got.Name != "Mix" {
+			t.Errorf("expected the source name, got %+v (error %v)", got, err)
+		}
+	})
+
+	t.Run("invalid templates fail before searching", func(t *testing.T) {
+		for _, text := range []string{"{{.SourceName", "{{.Missing}}"} {
+			if _, err := engine.Destination(src, TransferOpts{Description: text}); !errors.Is(err, shared.ErrInvalidInput) {
+				t.Errorf("Destination(%q) error = %v, want ErrInvalidInput", text, err)
+			}
+		}
+
+		youtube := &mockService{name: "YouTube Music"}
+		engine := NewPlaylistEngine(&mockService{name: "Spotify", playlistExports: map[string]*models.PlaylistExport{"p1": src}}, youtube, nil)
+		engine.SetPlaylistTemplates("{{.Nope}}", "")
+		if _, err := engine.Run(context.Background(), "p1", nil); !errors.Is(err, shared.ErrInvalidInput) {
+			t.Fatalf("Run() error = %v, want ErrInvalidInput", err)
+		}
+		if youtube.importCalled {
+			t.Error("expected no playlist to be created")
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package tasks
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/formatter"
+)
+
+// PhaseDuration is how long an operation spent in one [Phase].
+type PhaseDuration struct {
+	Phase    Phase
+	Duration time.Duration
+}
+
+// MarshalJSON encodes the duration in seconds, e.g. {"phase": "search_tracks", "seconds": 12.5}.
+func (d PhaseDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Phase   Phase   `json:"phase"`
+		Seconds float64 `json:"seconds"`
+	}{d.Phase, d.Duration.Seconds()})
+}
+
+// Timing records when an operation ran and how long it spent in each of its phases.
+//
+// Results embed it, so a transfer's start is result.StartedAt and the time spent searching is
+// result.PhaseDuration([SearchTracks]).
+type Timing struct {
+	StartedAt   time.Time       `json:"started_at"`
+	CompletedAt time.Time       `json:"completed_at"`
+	Phases      []PhaseDuration `json:"phases"` // In the order the phases started; time spent in a phase twice is summed
+}
+
+// Duration returns how long the operation took, or zero before it completed.
+func (t Timing) Duration() time.Duration {
+	if t.CompletedAt.IsZero() {
+		return 0
+	}
+	return t.CompletedAt.Sub(t.StartedAt)
+}
+
+// PhaseDuration returns the time spent in phase, or zero when the operation never entered it.
+func (t Timing) PhaseDuration(phase Phase) time.Duration {
+	for _, d := range t.Phases {
+		if d.Phase == phase {
+			return d.Duration
+		}
+	}
+	return 0
+}
+
+// Summary describes the timing for display, e.g. "1m2.5s (Search tracks 58.1s, Create playlist 2.2s)".
+//
+// It is not String, which results embedding a Timing would take on as their own.
+func (t Timing) Summary() string {
+	return formatter.FormatTiming(t.StartedAt, t.CompletedAt, t.reportPhases())
+}
+
+// reportPhases names the phases for display
+func (t Timing) reportPhases() []formatter.ReportPhase {
+	phases := make([]formatter.ReportPhase, len(t.Phases))
+	for i, d := range t.Phases {
+		phases[i] = formatter.ReportPhase{Name: d.Phase.Description(), Duration: d.Duration}
+	}
+	return phases
+}
+
+// phaseTimer measures the [Timing] of an operation, either one phase after another with enter, or with add for
+// phases that run concurrently. It is safe for concurrent use.
+type phaseTimer struct {
+	mu      sync.Mutex
+	timing  Timing
+	current Phase
+	since   time.Time // When the current phase was entered; zero when none is running
+}
+
+// startTiming returns a timer for an operation starting now
+func startTiming() *phaseTimer {
+	return &phaseTimer{timing: Timing{StartedAt: time.Now()}}
+}
+
+// enter ends the running phase and starts phase
+func (t *phaseTimer) enter(phase Phase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.stop(now)
+	t.record(phase, 0) // Listed from when it starts, ahead of phases added while it runs
+	t.current, t.since = phase, now
+}
+
+// add counts d toward phase without affecting the running phase
+func (t *phaseTimer) add(phase Phase, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.record(phase, d)
+}
+
+// finish ends the running phase and returns the operation's timing, completed now
+func (t *phaseTimer) finish() Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.stop(now)
+	t.timing.CompletedAt = now
+	return t.timing
+}
+
+func (t *phaseTimer) stop(now time.Time) {
+	if !t.since.IsZero() {
+		t.record(t.current, now.Sub(t.since))
+		t.since = time.Time{}
+	}
+}
+
+func (t *phaseTimer) record(phase Phase, d time.Duration) {
+	for i := range t.timing.Phases {
+		if t.timing.Phases[i].Phase == phase {
+			t.timing.Phases[i].Duration += d
+			return
+		}
+	}
+	t.timing.Phases = append(t.timing.Phases, PhaseDuration{Phase: phase, Duration: d})
+}
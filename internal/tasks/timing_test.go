@@ -0,0 +1,101 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+)
+
+func TestPhaseTimer(t *testing.T) {
+	t.Run("sums phases entered more than once in first-entered order", func(t *testing.T) {
+		timer := startTiming()
+		timer.enter(FetchSource)
+		timer.enter(SearchTracks)
+		timer.add(FetchUploads, 2*time.Second)
+		timer.enter(FetchSource)
+		timer.add(FetchUploads, 3*time.Second)
+		timing := timer.finish()
+
+		var phases []Phase
+		for _, d := range timing.Phases {
+			phases = append(phases, d.Phase)
+		}
+		if len(phases) != 3 || phases[0] != FetchSource || phases[1] != SearchTracks || phases[2] != FetchUploads {
+			t.Errorf("unexpected phases %v", phases)
+		}
+		if got := timing.PhaseDuration(FetchUploads); got != 5*time.Second {
+			t.Errorf("expected added durations summed, got %v", got)
+		}
+		if timing.CompletedAt.Before(timing.StartedAt) || timing.Duration() < timing.PhaseDuration(FetchSource) {
+			t.Errorf("unexpected timing %+v", timing)
+		}
+	})
+
+	t.Run("JSON durations are in seconds", func(t *testing.T) {
+		data, err := json.Marshal(PhaseDuration{Phase: SearchTracks, Duration: 1500 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		if string(data) != `{"phase":"search_tracks","seconds":1.5}` {
+			t.Errorf("unexpected JSON %s", data)
+		}
+	})
+
+	t.Run("summary names each phase", func(t *testing.T) {
+		start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		timing := Timing{
+			StartedAt:   start,
+			CompletedAt: start.Add(61 * time.Second),
+			Phases:      []PhaseDuration{{SearchTracks, 58 * time.Second}, {CreatePlaylist, 2 * time.Second}},
+		}
+		if got := timing.Summary(); got != "1m1s (Search tracks 58s, Create playlist 2s)" {
+			t.Errorf("unexpected summary %q", got)
+		}
+		if got := (Timing{}).Summary(); got != "" {
+			t.Errorf("expected no summary before completing, got %q", got)
+		}
+	})
+}
+
+func TestPlaylistEngine_Run_Timing(t *testing.T) {
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"src": {Playlist: models.Playlist{ID: "src", Name: "Source"}, Tracks: []models.Track{{ID: "1", Title: "Song", Artists: []string{"Artist"}}}},
+		},
+	}
+	youtube := &mockService{
+		name:          "YouTube Music",
+		searchResults: map[string]*models.Track{"Song|Artist": {ID: "yt1", Title: "Song", Artists: []string{"Artist"}}},
+		importResult:  &models.Playlist{ID: "dest", Name: "Source"},
+	}
+	engine := NewPlaylistEngine(spotify, youtube, nil)
+
+	before := time.Now()
+	result, err := engine.Run(context.Background(), "src", nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if result.StartedAt.Before(before) || result.CompletedAt.Before(result.StartedAt) {
+		t.Errorf("unexpected start %v and completion %v", result.StartedAt, result.CompletedAt)
+	}
+	want := []Phase{FetchSource, SearchTracks, CreatePlaylist}
+	if len(result.Phases) != len(want) {
+		t.Fatalf("expected phases %v, got %+v", want, result.Phases)
+	}
+	for i, phase := range want {
+		if result.Phases[i].Phase != phase {
+			t.Errorf("phase %d: expected %v, got %v", i, phase, result.Phases[i].Phase)
+		}
+	}
+
+	report := result.Report(time.Now())
+	if duration := report.Duration(); !strings.Contains(duration, "Search tracks") {
+		t.Errorf("expected the report to time each phase, got %q", duration)
+	}
+}
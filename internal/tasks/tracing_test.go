@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/tracing"
+)
+
+// Mock span exporter for testing
+type mockExporter struct {
+	spans []tracing.SpanData
+}
+
+func (m *mockExporter) Export(ctx context.Context, spans []tracing.SpanData) error {
+	m.spans = append(m.spans, spans...)
+	return nil
+}
+
+func TestPlaylistEngine_RunWithOpts_Tracing(t *testing.T) {
+	exporter := &mockExporter{}
+	tracer := tracing.NewTracer(exporter)
+	tracing.SetTracer(tracer)
+	t.Cleanup(func() { tracing.SetTracer(nil) })
+
+	spotify := &mockService{
+		name: "Spotify",
+		playlistExports: map[string]*models.PlaylistExport{
+			"playlist123": {
+				Playlist: models.Playlist{ID: "playlist123", Name: "Road Trip"},
+				Tracks: []models.Track{
+					{ID: "track1", Title: "Song 1", Artists: []string{"Artist 1"}},
+					{ID: "track2", Title: "Song 2", Artists: []string{"Artist 2"}},
+				},
+			},
+		},
+	}
+	youtube := &mockService{
+		name: "YouTube Music",
+		searchResults: map[string]*models.Track{
+			"Song 1|Artist 1": {ID: "yt1", Title: "Song 1", Artists: []string{"Artist 1"}},
+		},
+		importResult: &models.Playlist{ID: "yt_playlist", Name: "Road Trip"},
+	}
+
+	if _, err := NewPlaylistEngine(spotify, youtube, nil).Run(context.Background(), "playlist123", nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	byName := map[string][]tracing.SpanData{}
+	for _, span := range exporter.spans {
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+	if len(byName["transfer"]) != 1 {
+		t.Fatalf("expected one transfer span, got %v", byName)
+	}
+	root := byName["transfer"][0]
+	for _, phase := range []string{"transfer.fetch_source", "transfer.match_tracks", "transfer.create_playlist"} {
+		if len(byName[phase]) != 1 || byName[phase][0].ParentID != root.SpanID {
+			t.Errorf("expected one %s span under the transfer span, got %v", phase, byName[phase])
+		}
+	}
+
+	searches := byName["transfer.search_track"]
+	if len(searches) != 2 {
+		t.Fatalf("expected 2 search spans, got %d", len(searches))
+	}
+	match := byName["transfer.match_tracks"][0]
+	for _, search := range searches {
+		if search.ParentID != match.SpanID {
+			t.Error("expected search spans under the match phase")
+		}
+	}
+	if searches[0].Err != "" || searches[1].Err == "" {
+		t.Errorf("expected only the second search to fail, got %q and %q", searches[0].Err, searches[1].Err)
+	}
+}
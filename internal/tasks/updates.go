@@ -1,23 +1,31 @@
 package tasks
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
 )
 
 // ProgressUpdate represents a progress event during a long-running operation.
 //
 // Used to send real-time updates to the CLI or UI layer for display.
 type ProgressUpdate struct {
-	Phase   Phase  // Operation phase
-	Step    int    // Current step number within phase
-	Total   int    // Total steps in this phase
-	Message string // Human-readable message for display
-	Data    any    // Optional phase-specific data for advanced UIs
+	Phase   Phase     // Operation phase
+	Step    int       // Current step number within phase
+	Total   int       // Total steps in this phase
+	Message string    // Human-readable message for display
+	Data    any       // Optional phase-specific data for advanced UIs
+	Time    time.Time // When the update was sent, for computing rates and ETAs
 }
 
-// Operation phase enumeration
+// Phase is the stage of an operation a [ProgressUpdate] reports on.
+//
+// Phases are identified outside ytx by stable string codes, e.g. "search_tracks": [Phase.String] returns the
+// code, and JSON encodes a Phase as its code. [Phases] lists every phase with its description.
 type Phase int
 
 const (
@@ -35,49 +43,99 @@ const (
 	CreatePlaylist
 	SearchTracks
 	ExportPlaylist
+	RateLimited
 )
 
+// phases holds the code and description of every [Phase]. Codes are part of the JSON progress output and the web
+// API, so a released code never changes; new phases are added at the end.
+var phases = [...]struct{ code, description string }{
+	FetchSource:    {"fetch_source", "Fetch source"},
+	FetchDest:      {"fetch_dest", "Fetch destination"},
+	Compare:        {"compare", "Compare tracks"},
+	FetchHealth:    {"fetch_health", "Check proxy health"},
+	FetchPlaylists: {"fetch_playlists", "Fetch playlists"},
+	FetchSongs:     {"fetch_songs", "Fetch songs"},
+	FetchAlbums:    {"fetch_albums", "Fetch albums"},
+	FetchArtists:   {"fetch_artists", "Fetch artists"},
+	FetchLiked:     {"fetch_liked", "Fetch liked songs"},
+	FetchHistory:   {"fetch_history", "Fetch history"},
+	FetchUploads:   {"fetch_uploads", "Fetch uploads"},
+	CreatePlaylist: {"create_playlist", "Create playlist"},
+	SearchTracks:   {"search_tracks", "Search tracks"},
+	ExportPlaylist: {"export_playlist", "Export playlist"},
+	RateLimited:    {"rate_limited", "Paused for rate limit"},
+}
+
+// PhaseInfo describes a [Phase] for clients of the JSON progress output and the web API.
+type PhaseInfo struct {
+	Phase       Phase  `json:"code"`
+	Description string `json:"description"`
+}
+
+// Phases returns every [Phase] with its description, in order.
+func Phases() []PhaseInfo {
+	infos := make([]PhaseInfo, len(phases))
+	for i, phase := range phases {
+		infos[i] = PhaseInfo{Phase: Phase(i), Description: phase.description}
+	}
+	return infos
+}
+
+// ParsePhase returns the [Phase] with the given code, e.g. "search_tracks".
+func ParsePhase(code string) (Phase, error) {
+	for i, phase := range phases {
+		if phase.code == code {
+			return Phase(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown progress phase '%s'", shared.ErrInvalidArgument, code)
+}
+
+// String returns the stable code of the phase, or an empty string for an unknown phase.
 func (p Phase) String() string {
-	switch p {
-	case FetchSource:
-		return "fetch_source"
-	case FetchDest:
-		return "fetch_dest"
-	case Compare:
-		return "compare"
-	case FetchHealth:
-		return "fetch_health"
-	case FetchPlaylists:
-		return "fetch_playlists"
-	case FetchSongs:
-		return "fetch_songs"
-	case FetchAlbums:
-		return "fetch_albums"
-	case FetchArtists:
-		return "fetch_artists"
-	case FetchLiked:
-		return "fetch_liked"
-	case FetchHistory:
-		return "fetch_history"
-	case FetchUploads:
-		return "fetch_uploads"
-	case CreatePlaylist:
-		return "create_playlist"
-	case SearchTracks:
-		return "search_tracks"
-	case ExportPlaylist:
-		return "export_playlist"
-	default:
+	if p < 0 || int(p) >= len(phases) {
 		return ""
 	}
+	return phases[p].code
+}
+
+// Description names the phase for display, e.g. "Search tracks".
+func (p Phase) Description() string {
+	if p < 0 || int(p) >= len(phases) {
+		return fmt.Sprintf("Phase %d", int(p))
+	}
+	return phases[p].description
+}
+
+// MarshalJSON encodes the phase as its code.
+func (p Phase) MarshalJSON() ([]byte, error) {
+	code := p.String()
+	if code == "" {
+		return nil, fmt.Errorf("unknown progress phase %d", int(p))
+	}
+	return json.Marshal(code)
+}
+
+// UnmarshalJSON decodes a phase from its code.
+func (p *Phase) UnmarshalJSON(data []byte) error {
+	var code string
+	if err := json.Unmarshal(data, &code); err != nil {
+		return fmt.Errorf("%w: progress phase must be a string code: %v", shared.ErrInvalidArgument, err)
+	}
+	phase, err := ParsePhase(code)
+	if err != nil {
+		return err
+	}
+	*p = phase
+	return nil
 }
 
-func fetchingSourceUpdate(step, total int) ProgressUpdate {
+func fetchingSourceUpdate(step, total int, service string) ProgressUpdate {
 	return ProgressUpdate{
 		Phase:   FetchSource,
 		Step:    step,
 		Total:   total,
-		Message: "Fetching source playlist from Spotify...",
+		Message: fmt.Sprintf("Fetching source playlist from %s...", service),
 	}
 }
 
@@ -126,6 +184,41 @@ func operationUpdate(endpoint endpointOperation, step int, total int) ProgressUp
 	}
 }
 
+// endpointUpdate reports a finished Dump endpoint, carrying its [EndpointResult] as Data
+func endpointUpdate(endpoint endpointOperation, result EndpointResult, step, total int) ProgressUpdate {
+	message := fmt.Sprintf("Fetched %s", strings.ReplaceAll(endpoint.name, "_", " "))
+	if result.Error != nil {
+		message = fmt.Sprintf("Failed to fetch %s: %v", strings.ReplaceAll(endpoint.name, "_", " "), result.Error)
+	}
+	return ProgressUpdate{
+		Phase:   endpoint.phase,
+		Step:    step,
+		Total:   total,
+		Message: message,
+		Data:    result,
+	}
+}
+
+// libraryPageUpdate reports a page fetched from a paged Dump endpoint; the total is unknown until the last page
+func libraryPageUpdate(endpoint endpointOperation, page, items int) ProgressUpdate {
+	return ProgressUpdate{
+		Phase:   endpoint.phase,
+		Step:    page,
+		Message: fmt.Sprintf("Fetched page %d of %s (%d items)", page, strings.ReplaceAll(endpoint.name, "_", " "), items),
+	}
+}
+
+// rateLimitedUpdate announces a pause of every worker while service's rate limit passes
+func rateLimitedUpdate(service string, wait time.Duration) ProgressUpdate {
+	return ProgressUpdate{
+		Phase:   RateLimited,
+		Step:    1,
+		Total:   1,
+		Message: fmt.Sprintf("%s is rate limiting requests, pausing for %s...", service, wait.Round(time.Second)),
+		Data:    wait,
+	}
+}
+
 func createPlaylistUpdate(step, total int, pl *models.Playlist) ProgressUpdate {
 	return ProgressUpdate{
 		Phase:   CreatePlaylist,
@@ -136,29 +229,47 @@ func createPlaylistUpdate(step, total int, pl *models.Playlist) ProgressUpdate {
 	}
 }
 
-func createDestinationUpdate(step, total int) ProgressUpdate {
+func createDestinationUpdate(step, total int, service string) ProgressUpdate {
+	return ProgressUpdate{
+		Phase:   CreatePlaylist,
+		Step:    step,
+		Total:   total,
+		Message: fmt.Sprintf("Creating playlist on %s...", service),
+	}
+}
+
+func copyCoverUpdate(step, total int, service string) ProgressUpdate {
 	return ProgressUpdate{
 		Phase:   CreatePlaylist,
 		Step:    step,
 		Total:   total,
-		Message: "Creating playlist on YouTube Music...",
+		Message: fmt.Sprintf("Copying cover image to %s...", service),
 	}
 }
 
-func searchTracksUpdate(step, total int, tr *models.Track) ProgressUpdate {
+func addTracksUpdate(step, total, count int, service string) ProgressUpdate {
+	return ProgressUpdate{
+		Phase:   CreatePlaylist,
+		Step:    step,
+		Total:   total,
+		Message: fmt.Sprintf("Adding %d tracks on %s...", count, service),
+	}
+}
+
+func searchTracksUpdate(step, total int, tr *models.Track, service string) ProgressUpdate {
 	if tr == nil {
 		return ProgressUpdate{
 			Phase:   SearchTracks,
 			Step:    step,
 			Total:   total,
-			Message: "Searching for tracks on YouTube Music...",
+			Message: fmt.Sprintf("Searching for tracks on %s...", service),
 		}
 	}
 	return ProgressUpdate{
 		Phase:   SearchTracks,
 		Step:    step,
 		Total:   total,
-		Message: fmt.Sprintf("[%d/%d] %s - %s", step, total, tr.Artist, tr.Title),
+		Message: fmt.Sprintf("[%d/%d] %s - %s", step, total, tr.Artist(), tr.Title),
 	}
 }
 
@@ -181,20 +292,40 @@ func exportingPlaylistUpdate(step, total int, name string) ProgressUpdate {
 	}
 }
 
-func exportCompletedUpdate(step, total int, name string, filesCount int) ProgressUpdate {
+func exportCompletedUpdate(step, total int, res PlaylistExportResult) ProgressUpdate {
 	return ProgressUpdate{
 		Phase:   ExportPlaylist,
 		Step:    step,
 		Total:   total,
-		Message: fmt.Sprintf("[%d/%d] ✓ %s (%d files)", step, total, name, filesCount),
+		Message: fmt.Sprintf("[%d/%d] ✓ %s (%d files)", step, total, res.PlaylistName, len(res.Files)),
+		Data:    res,
 	}
 }
 
-func exportFailedUpdate(step, total int, name string, err error) ProgressUpdate {
+func exportFailedUpdate(step, total int, res PlaylistExportResult) ProgressUpdate {
 	return ProgressUpdate{
 		Phase:   ExportPlaylist,
 		Step:    step,
 		Total:   total,
-		Message: fmt.Sprintf("[%d/%d] ✗ %s: %v", step, total, name, err),
+		Message: fmt.Sprintf("[%d/%d] ✗ %s: %v", step, total, res.PlaylistName, res.Error),
+		Data:    res,
+	}
+}
+
+func fetchLibraryUpdate(phase Phase, what, service string) ProgressUpdate {
+	return ProgressUpdate{
+		Phase:   phase,
+		Step:    1,
+		Total:   1,
+		Message: fmt.Sprintf("Fetching %s from %s...", what, service),
+	}
+}
+
+func scanPlaylistUpdate(step, total int, name string) ProgressUpdate {
+	return ProgressUpdate{
+		Phase:   FetchPlaylists,
+		Step:    step,
+		Total:   total,
+		Message: fmt.Sprintf("[%d/%d] Checking playlist: %s...", step, total, name),
 	}
 }
@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestPhase(t *testing.T) {
+	t.Run("every phase has a unique code and a description", func(t *testing.T) {
+		codes := map[string]bool{}
+		for i, info := range Phases() {
+			code := info.Phase.String()
+			if info.Phase != Phase(i) || code == "" || info.Description == "" || codes[code] {
+				t.Errorf("phase %d: unexpected code %q and description %q", i, code, info.Description)
+			}
+			codes[code] = true
+		}
+		if len(codes) != int(RateLimited)+1 {
+			t.Errorf("expected every phase registered, got %d", len(codes))
+		}
+	})
+
+	t.Run("JSON uses the code", func(t *testing.T) {
+		data, err := json.Marshal(ProgressUpdate{Phase: SearchTracks})
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		var decoded struct{ Phase string }
+		json.Unmarshal(data, &decoded)
+		if decoded.Phase != "search_tracks" {
+			t.Errorf("expected the phase code, got %s", data)
+		}
+
+		var update ProgressUpdate
+		if err := json.Unmarshal(data, &update); err != nil || update.Phase != SearchTracks {
+			t.Errorf("expected the phase decoded from its code, got %v (%v)", update.Phase, err)
+		}
+	})
+
+	t.Run("JSON output of the registry", func(t *testing.T) {
+		data, err := json.Marshal(Phases()[:1])
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		if string(data) != `[{"code":"fetch_source","description":"Fetch source"}]` {
+			t.Errorf("unexpected registry JSON %s", data)
+		}
+	})
+
+	t.Run("unknown phases", func(t *testing.T) {
+		if _, err := ParsePhase("teleport"); !errors.Is(err, shared.ErrInvalidArgument) {
+			t.Errorf("expected ErrInvalidArgument, got %v", err)
+		}
+		if _, err := json.Marshal(Phase(99)); err == nil {
+			t.Error("expected an unknown phase to fail to marshal")
+		}
+		if got := Phase(99).String(); got != "" {
+			t.Errorf("expected no code for an unknown phase, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+// FakeService is an in-memory [services.Service] for end-to-end tests.
+//
+// Playlists are kept in memory in the order they were added, searches are answered from a catalog of tracks, and
+// imported playlists are created with IDs fake-1, fake-2, and so on. It is safe for concurrent use.
+type FakeService struct {
+	mu        sync.Mutex
+	name      string
+	playlists []*models.PlaylistExport
+	catalog   []models.Track
+	created   int
+	exportErr error
+}
+
+// NewFakeService returns an empty fake service named name, e.g. "Spotify"
+func NewFakeService(name string) *FakeService {
+	return &FakeService{name: name}
+}
+
+// AddPlaylist stores a copy of playlist, replacing any playlist with the same ID
+func (f *FakeService) AddPlaylist(playlist models.PlaylistExport) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored := clonePlaylist(&playlist)
+	if i := f.index(playlist.Playlist.ID); i >= 0 {
+		f.playlists[i] = stored
+		return
+	}
+	f.playlists = append(f.playlists, stored)
+}
+
+// AddCatalog makes tracks findable by [FakeService.SearchTrack]
+func (f *FakeService) AddCatalog(tracks ...models.Track) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.catalog = append(f.catalog, tracks...)
+}
+
+// Playlist returns a copy of the stored playlist with the given ID, e.g. to check what a transfer created
+func (f *FakeService) Playlist(playlistID string) (*models.PlaylistExport, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := f.index(playlistID)
+	if i < 0 {
+		return nil, false
+	}
+	return clonePlaylist(f.playlists[i]), true
+}
+
+// FailExports makes [FakeService.ExportPlaylist] fail with err, e.g. to check how commands report an expired token;
+// nil restores it
+func (f *FakeService) FailExports(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exportErr = err
+}
+
+func (f *FakeService) Authenticate(ctx context.Context, credentials map[string]string) error {
+	return nil
+}
+
+func (f *FakeService) GetPlaylists(ctx context.Context) ([]models.Playlist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	playlists := make([]models.Playlist, len(f.playlists))
+	for i, p := range f.playlists {
+		playlists[i] = p.Playlist
+	}
+	return playlists, nil
+}
+
+func (f *FakeService) GetPlaylist(ctx context.Context, playlistID string) (*models.Playlist, error) {
+	export, err := f.ExportPlaylist(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	return &export.Playlist, nil
+}
+
+func (f *FakeService) ExportPlaylist(ctx context.Context, playlistID string) (*models.PlaylistExport, error) {
+	f.mu.Lock()
+	err := f.exportErr
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if export, ok := f.Playlist(playlistID); ok {
+		return export, nil
+	}
+	return nil, fmt.Errorf("%w: %s has no playlist %s", shared.ErrPlaylistNotFound, f.name, playlistID)
+}
+
+// ImportPlaylist creates a playlist with the next fake ID holding playlist's tracks
+func (f *FakeService) ImportPlaylist(ctx context.Context, playlist *models.PlaylistExport) (*models.Playlist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.created++
+	created := clonePlaylist(playlist)
+	created.Playlist.ID = fmt.Sprintf("fake-%d", f.created)
+	created.Playlist.TrackCount = len(created.Tracks)
+	f.playlists = append(f.playlists, created)
+	return &created.Playlist, nil
+}
+
+// SearchTrack returns the first catalog track with the same title and artist, ignoring case, accents, and version
+// notes, or failing that the first with the same title
+func (f *FakeService) SearchTrack(ctx context.Context, title, artist string) (*models.Track, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := shared.NormalizeTrackKey(title, artist)
+	for _, track := range f.catalog {
+		if shared.NormalizeTrackKey(track.Title, track.Artist()) == key {
+			return &track, nil
+		}
+	}
+	for _, track := range f.catalog {
+		if shared.NormalizeTitle(track.Title) == shared.NormalizeTitle(title) {
+			return &track, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no results found for '%s' by '%s'", shared.ErrTrackNotFound, title, artist)
+}
+
+func (f *FakeService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	return f.edit(playlistID, func(p *models.PlaylistExport) {
+		p.Tracks = append(p.Tracks, tracks...)
+	})
+}
+
+func (f *FakeService) RemoveTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	return f.edit(playlistID, func(p *models.PlaylistExport) {
+		p.Tracks = slices.DeleteFunc(p.Tracks, func(track models.Track) bool {
+			return slices.ContainsFunc(tracks, func(removed models.Track) bool { return removed.ID == track.ID })
+		})
+	})
+}
+
+func (f *FakeService) ReorderTracks(ctx context.Context, playlistID string, rangeStart, rangeLength, insertBefore int) error {
+	var err error
+	editErr := f.edit(playlistID, func(p *models.PlaylistExport) {
+		if rangeStart < 0 || rangeLength < 0 || rangeStart+rangeLength > len(p.Tracks) || insertBefore < 0 || insertBefore > len(p.Tracks) {
+			err = fmt.Errorf("%w: cannot move %d tracks from %d to %d in a playlist of %d", shared.ErrInvalidArgument,
+				rangeLength, rangeStart, insertBefore, len(p.Tracks))
+			return
+		}
+		moved := slices.Clone(p.Tracks[rangeStart : rangeStart+rangeLength])
+		rest := slices.Delete(slices.Clone(p.Tracks), rangeStart, rangeStart+rangeLength)
+		if insertBefore > rangeStart {
+			insertBefore -= min(rangeLength, insertBefore-rangeStart)
+		}
+		p.Tracks = slices.Insert(rest, insertBefore, moved...)
+	})
+	if editErr != nil {
+		return editErr
+	}
+	return err
+}
+
+func (f *FakeService) Name() string { return f.name }
+
+// edit applies change to the stored playlist with the given ID and updates its track count
+func (f *FakeService) edit(playlistID string, change func(*models.PlaylistExport)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := f.index(playlistID)
+	if i < 0 {
+		return fmt.Errorf("%w: %s has no playlist %s", shared.ErrPlaylistNotFound, f.name, playlistID)
+	}
+	change(f.playlists[i])
+	f.playlists[i].Playlist.TrackCount = len(f.playlists[i].Tracks)
+	return nil
+}
+
+func (f *FakeService) index(playlistID string) int {
+	return slices.IndexFunc(f.playlists, func(p *models.PlaylistExport) bool { return p.Playlist.ID == playlistID })
+}
+
+func clonePlaylist(playlist *models.PlaylistExport) *models.PlaylistExport {
+	clone := *playlist
+	clone.Tracks = slices.Clone(playlist.Tracks)
+	return &clone
+}
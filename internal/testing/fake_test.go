@@ -0,0 +1,64 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+)
+
+func TestFakeService(t *testing.T) {
+	ctx := context.Background()
+	ids := func(t *testing.T, f *FakeService, playlistID string) []string {
+		t.Helper()
+		export, ok := f.Playlist(playlistID)
+		if !ok {
+			t.Fatalf("expected playlist %s", playlistID)
+		}
+		var ids []string
+		for _, track := range export.Tracks {
+			ids = append(ids, track.ID)
+		}
+		return ids
+	}
+
+	f := NewFakeService("Fake")
+	f.AddPlaylist(models.PlaylistExport{
+		Playlist: models.Playlist{ID: "p1"},
+		Tracks:   []models.Track{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}},
+	})
+
+	if err := f.ReorderTracks(ctx, "p1", 0, 2, 4); err != nil {
+		t.Fatalf("ReorderTracks() error = %v", err)
+	}
+	if got := ids(t, f, "p1"); !slices.Equal(got, []string{"c", "d", "a", "b"}) {
+		t.Errorf("expected the first two tracks moved to the end, got %v", got)
+	}
+	if err := f.ReorderTracks(ctx, "p1", 3, 1, 0); err != nil {
+		t.Fatalf("ReorderTracks() error = %v", err)
+	}
+	if got := ids(t, f, "p1"); !slices.Equal(got, []string{"b", "c", "d", "a"}) {
+		t.Errorf("expected the last track moved to the start, got %v", got)
+	}
+	if err := f.ReorderTracks(ctx, "p1", 3, 2, 0); !errors.Is(err, shared.ErrInvalidArgument) {
+		t.Errorf("expected a range past the end to be refused, got %v", err)
+	}
+
+	if err := f.RemoveTracks(ctx, "p1", []models.Track{{ID: "c"}, {ID: "a"}}); err != nil {
+		t.Fatalf("RemoveTracks() error = %v", err)
+	}
+	if export, _ := f.Playlist("p1"); export.Playlist.TrackCount != 2 {
+		t.Errorf("expected the track count updated, got %d", export.Playlist.TrackCount)
+	}
+
+	created, err := f.ImportPlaylist(ctx, &models.PlaylistExport{Playlist: models.Playlist{Name: "New"}, Tracks: []models.Track{{ID: "x"}}})
+	if err != nil || created.ID != "fake-1" || created.TrackCount != 1 {
+		t.Errorf("expected fake-1 with one track, got %+v, %v", created, err)
+	}
+	if _, err := f.ExportPlaylist(ctx, "missing"); !errors.Is(err, shared.ErrPlaylistNotFound) {
+		t.Errorf("expected ErrPlaylistNotFound, got %v", err)
+	}
+}
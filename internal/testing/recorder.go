@@ -0,0 +1,293 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RecordEnv names the environment variable that makes a [Recorder] send requests to the live service and record
+// them, rather than replaying its fixture, e.g. YTX_RECORD=1 go test ./internal/services -run Fixtures
+const RecordEnv = "YTX_RECORD"
+
+// Redacted replaces sensitive values in recorded fixtures
+const Redacted = "REDACTED"
+
+// sensitiveKeys lists query parameters and JSON object keys whose values are never written to a fixture
+var sensitiveKeys = []string{
+	"access_token", "refresh_token", "id_token", "client_secret", "code_verifier", "email", "birthdate", "cookie",
+}
+
+// recordedHeaders lists the response headers kept in fixtures; the rest, such as Set-Cookie, are dropped.
+// Request headers are never recorded, so neither Authorization nor X-Auth-File end up in a fixture.
+var recordedHeaders = []string{"Content-Type", "Retry-After", "ETag", "Cache-Control", "Last-Modified", "Location"}
+
+// Interaction is a request and the response it received, as stored in a fixture.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request by method, and by the path and query of its URL, so fixtures replay against
+// any host: a proxy recorded on localhost:8080 replays against an [httptest.Server] or the default base URL alike.
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`            // Path and sanitized query, e.g. /v1/me/playlists?limit=50&offset=0
+	Body   json.RawMessage `json:"body,omitempty"` // JSON request bodies, for reading; not used to match requests
+}
+
+// RecordedResponse is a recorded response. JSON bodies are stored as JSON so fixtures stay readable and editable;
+// anything else is stored as text.
+type RecordedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+	Text   string            `json:"text,omitempty"`
+}
+
+// Recorder is an [http.RoundTripper] that replays the responses recorded in a fixture file, or, when [RecordEnv]
+// is set, sends requests through to the live service and records them to the fixture when the test ends.
+//
+// Recording sanitizes everything it writes: request headers are left out, response headers are limited to
+// [recordedHeaders], the values of [sensitiveKeys] are replaced with [Redacted] in queries and JSON bodies, and
+// strings registered with [Recorder.Redact] are replaced wherever they appear.
+//
+// Replayed requests are matched by method and URL, in any order, so concurrent requests such as pages fetched in
+// parallel replay reliably. Each recording is used once; when a request is repeated more often than it was
+// recorded, its last recording is replayed again. A request without a recording fails with an error naming it.
+type Recorder struct {
+	mu           sync.Mutex
+	t            testing.TB
+	path         string
+	recording    bool
+	next         http.RoundTripper
+	interactions []Interaction
+	used         []bool
+	redactions   map[string]string
+}
+
+// NewRecorder returns a recorder for the fixture at path, typically under the test package's testdata directory.
+//
+// Replaying fails the test at once when the fixture can't be read. Recording sends requests through
+// [http.DefaultTransport] and writes the fixture, creating its directory, when t and its subtests complete.
+func NewRecorder(t testing.TB, path string) *Recorder {
+	t.Helper()
+	r := &Recorder{t: t, path: path, recording: os.Getenv(RecordEnv) != "", next: http.DefaultTransport, redactions: map[string]string{}}
+	if r.recording {
+		t.Cleanup(r.save)
+		return r
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s (record it with %s=1): %v", path, RecordEnv, err)
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		t.Fatalf("Failed to parse fixture %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r
+}
+
+// Recording reports whether requests are sent to the live service, e.g. so a test can use real credentials
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Redact replaces value with replacement wherever it appears in the recorded fixture, e.g. a user ID or the name
+// of a private playlist. Empty values are ignored.
+func (r *Recorder) Redact(value, replacement string) {
+	if value == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactions[value] = replacement
+}
+
+// Client returns an [http.Client] whose requests go through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip replays the recorded response to req, or sends and records it
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.recording {
+		return r.record(req)
+	}
+
+	key := requestURL(req.URL)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.URL != key {
+			continue
+		}
+		if match = i; !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no recording of %s %s in %s", req.Method, key, r.path)
+	}
+	r.used[match] = true
+	return r.interactions[match].Response.response(req), nil
+}
+
+// record sends req to the live service and records the sanitized exchange, returning the response unchanged
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request:  RecordedRequest{Method: req.Method, URL: requestURL(req.URL)},
+		Response: RecordedResponse{Status: resp.StatusCode, Header: map[string]string{}},
+	}
+	if json.Valid(reqBody) {
+		interaction.Request.Body = redactJSON(reqBody)
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			interaction.Response.Header[name] = value
+		}
+	}
+	if json.Valid(body) {
+		interaction.Response.Body = redactJSON(body)
+	} else {
+		interaction.Response.Text = string(body)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, interaction)
+	return resp, nil
+}
+
+// save writes the recorded interactions to the fixture, with the registered redactions applied
+func (r *Recorder) save() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		r.t.Errorf("Failed to encode fixture %s: %v", r.path, err)
+		return
+	}
+	text := string(data)
+	for value, replacement := range r.redactions {
+		text = strings.ReplaceAll(text, value, replacement)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		r.t.Errorf("Failed to create fixture directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(r.path, []byte(text+"\n"), 0o644); err != nil {
+		r.t.Errorf("Failed to write fixture %s: %v", r.path, err)
+	}
+}
+
+// response builds the recorded response to req
+func (rr RecordedResponse) response(req *http.Request) *http.Response {
+	body := []byte(rr.Text)
+	if len(rr.Body) > 0 {
+		body = rr.Body
+	}
+	header := http.Header{}
+	for name, value := range rr.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.Status, http.StatusText(rr.Status)),
+		StatusCode:    rr.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// requestURL returns the path and query of u that requests are matched by, with sensitive query values redacted
+// and parameters sorted so their order doesn't matter
+func requestURL(u *url.URL) string {
+	query := u.Query()
+	for name := range query {
+		if isSensitive(name) {
+			query.Set(name, Redacted)
+		}
+	}
+	if len(query) == 0 {
+		return u.EscapedPath()
+	}
+	return u.EscapedPath() + "?" + query.Encode()
+}
+
+// redactJSON returns data with the values of [sensitiveKeys] replaced with [Redacted] at any depth
+func redactJSON(data []byte) json.RawMessage {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSensitive(key) && item != nil {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSensitive(key string) bool {
+	return slices.Contains(sensitiveKeys, strings.ToLower(key))
+}
@@ -0,0 +1,83 @@
+package testing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+			return
+		}
+		w.Write([]byte(`{"id": "user-42", "email": "me@example.com", "token": {"access_token": "abc"}, "page": ` + r.URL.Query().Get("page") + `}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "recorded.json")
+	get := func(t *testing.T, client *http.Client, url string) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("records sanitized fixtures", func(t *testing.T) {
+		t.Setenv(RecordEnv, "1")
+		recorder := NewRecorder(t, path)
+		recorder.Redact("user-42", "user")
+
+		if status, body := get(t, recorder.Client(), server.URL+"/me?page=1&access_token=secret"); status != http.StatusOK || !strings.Contains(body, "me@example.com") {
+			t.Errorf("expected the live response unchanged, got %d %s", status, body)
+		}
+		get(t, recorder.Client(), server.URL+"/me?page=2&access_token=secret")
+		get(t, recorder.Client(), server.URL+"/missing")
+	})
+
+	fixture := MustReadFile(t, path)
+	for _, secret := range []string{"secret", "user-42", "me@example.com", "abc", "Set-Cookie", "Authorization"} {
+		if strings.Contains(fixture, secret) {
+			t.Errorf("expected %q to be left out of the fixture:\n%s", secret, fixture)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 live requests, got %d", requests)
+	}
+
+	t.Run("replays fixtures without the live service", func(t *testing.T) {
+		client := NewRecorder(t, path).Client()
+
+		// Parameters are matched in any order, with sensitive values redacted
+		if status, body := get(t, client, "http://elsewhere/me?access_token=other&page=2"); status != http.StatusOK || !strings.Contains(body, `"page": 2`) {
+			t.Errorf("expected the second page, got %d %s", status, body)
+		}
+		if _, body := get(t, client, "http://elsewhere/me?page=1&access_token=other"); !strings.Contains(body, `"id": "user"`) {
+			t.Errorf("expected the redacted first page, got %s", body)
+		}
+		if status, body := get(t, client, "http://elsewhere/missing"); status != http.StatusNotFound || body != "not found" {
+			t.Errorf("expected the recorded 404, got %d %s", status, body)
+		}
+		if _, err := client.Get("http://elsewhere/unrecorded"); err == nil || !strings.Contains(err.Error(), "GET /unrecorded") {
+			t.Errorf("expected an error naming the unrecorded request, got %v", err)
+		}
+		if requests != 3 {
+			t.Errorf("expected no live requests while replaying, got %d", requests-3)
+		}
+	})
+}
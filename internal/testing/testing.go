@@ -34,6 +34,15 @@ func (m *MockService) ImportPlaylist(ctx context.Context, playlist *models.Playl
 func (m *MockService) SearchTrack(ctx context.Context, title, artist string) (*models.Track, error) {
 	return nil, nil
 }
+func (m *MockService) AddTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	return nil
+}
+func (m *MockService) RemoveTracks(ctx context.Context, playlistID string, tracks []models.Track) error {
+	return nil
+}
+func (m *MockService) ReorderTracks(ctx context.Context, playlistID string, rangeStart, rangeLength, insertBefore int) error {
+	return nil
+}
 func (m *MockService) Name() string { return "mock" }
 
 // FWriter always returns an error on Write
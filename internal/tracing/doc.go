@@ -0,0 +1,19 @@
+// Package tracing records OpenTelemetry-compatible spans and exports them to an OTLP/HTTP collector.
+//
+// # Spans
+//
+// [Start] begins a span as a child of the span carried by the context, or as the root of a new trace, and returns
+// a context carrying it. Spans are no-ops until a [Tracer] is installed with [SetTracer], so instrumented code
+// pays almost nothing when tracing is off.
+//
+// # Propagation
+//
+// [Transport] wraps an [http.RoundTripper] with a client span per request and injects the W3C traceparent header,
+// so the YouTube Music proxy can join the trace. [WrapDriver] wraps a [database/sql/driver.Driver] with a span per
+// query and statement.
+//
+// # Export
+//
+// Ended spans are buffered by the [Tracer] and sent in batches through an [Exporter]. [OTLPExporter] posts them
+// as OTLP/JSON to a collector's /v1/traces endpoint. [Tracer.Shutdown] flushes what is left when the CLI exits.
+package tracing
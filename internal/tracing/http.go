@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Transport wraps next (or [http.DefaultTransport] when nil) with a client span per request, named after the
+// method and host, and injects the W3C traceparent header of that span.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx, span := StartKind(req.Context(), req.Method+" "+req.URL.Host, KindClient,
+			String("http.request.method", req.Method),
+			String("url.full", req.URL.Redacted()),
+		)
+		if span == nil {
+			return next.RoundTrip(req)
+		}
+		defer span.End()
+
+		req = req.Clone(ctx)
+		req.Header.Set("traceparent", span.TraceParent())
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			span.RecordError(err)
+			return resp, err
+		}
+		span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.RecordError(httpStatusError(resp.StatusCode))
+		}
+		return resp, nil
+	})
+}
+
+// httpStatusError reports an error status code as a span error
+type httpStatusError int
+
+func (e httpStatusError) Error() string {
+	return "HTTP " + strconv.Itoa(int(e))
+}
+
+// RoundTripperFunc adapts a function to [http.RoundTripper], for transports wrapping another like [Transport]
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
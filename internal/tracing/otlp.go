@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// OTLPExporter posts spans as OTLP/JSON to a collector over HTTP.
+type OTLPExporter struct {
+	url     string
+	service string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewOTLPExporter creates an exporter for the collector at endpoint (e.g. http://localhost:4318), posting to its
+// /v1/traces path unless endpoint already ends with it.
+//
+// headers are sent with every request, e.g. for collector authentication. client defaults to [http.DefaultClient].
+func NewOTLPExporter(endpoint, service string, headers map[string]string, client *http.Client) *OTLPExporter {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OTLPExporter{url: url, service: service, headers: headers, client: client}
+}
+
+// URL returns the endpoint spans are posted to.
+func (e *OTLPExporter) URL() string {
+	return e.url
+}
+
+// Export posts spans in a single OTLP request.
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(otlpRequest(e.service, spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to export spans: collector returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// otlpRequest builds an ExportTraceServiceRequest in the OTLP/JSON encoding
+func otlpRequest(service string, spans []SpanData) map[string]any {
+	encoded := make([]map[string]any, len(spans))
+	for i, span := range spans {
+		s := map[string]any{
+			"traceId":           hex.EncodeToString(span.TraceID[:]),
+			"spanId":            hex.EncodeToString(span.SpanID[:]),
+			"name":              span.Name,
+			"kind":              span.Kind,
+			"startTimeUnixNano": strconv.FormatInt(span.Start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(span.End.UnixNano(), 10),
+			"attributes":        otlpAttributes(span.Attributes),
+		}
+		if span.ParentID != ([8]byte{}) {
+			s["parentSpanId"] = hex.EncodeToString(span.ParentID[:])
+		}
+		if span.Err != "" {
+			s["status"] = map[string]any{"code": 2, "message": span.Err}
+		}
+		encoded[i] = s
+	}
+
+	return map[string]any{
+		"resourceSpans": []map[string]any{{
+			"resource": map[string]any{
+				"attributes": otlpAttributes([]Attribute{String("service.name", service)}),
+			},
+			"scopeSpans": []map[string]any{{
+				"scope": map[string]any{"name": "github.com/desertthunder/ytx"},
+				"spans": encoded,
+			}},
+		}},
+	}
+}
+
+// otlpAttributes encodes attributes as OTLP KeyValues, formatting unsupported value types as strings
+func otlpAttributes(attrs []Attribute) []map[string]any {
+	encoded := make([]map[string]any, 0, len(attrs))
+	for _, attr := range attrs {
+		var value map[string]any
+		switch v := attr.Value.(type) {
+		case string:
+			value = map[string]any{"stringValue": v}
+		case bool:
+			value = map[string]any{"boolValue": v}
+		case int:
+			value = map[string]any{"intValue": strconv.Itoa(v)}
+		case int64:
+			value = map[string]any{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]any{"doubleValue": v}
+		default:
+			value = map[string]any{"stringValue": fmt.Sprint(v)}
+		}
+		encoded = append(encoded, map[string]any{"key": attr.Key, "value": value})
+	}
+	return encoded
+}
@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+)
+
+// WrapDriver wraps a database driver so that every query and exec made with a context gets a client span
+// carrying the statement.
+//
+// The wrapped driver's connections must implement [driver.ExecerContext], [driver.QueryerContext],
+// [driver.ConnPrepareContext], and [driver.ConnBeginTx], as go-sqlite3's do.
+func WrapDriver(d driver.Driver) driver.Driver {
+	return tracedDriver{d}
+}
+
+type tracedDriver struct {
+	driver.Driver
+}
+
+func (d tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{conn}, nil
+}
+
+// tracedConn forwards to the wrapped connection, adding spans around ExecContext and QueryContext. The optional
+// interfaces database/sql checks for, such as [driver.NamedValueChecker], are passed through too.
+type tracedConn struct {
+	driver.Conn
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, "db.exec", query)
+	defer span.End()
+
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		span.RecordError(err)
+	}
+	return result, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, "db.query", query)
+	defer span.End()
+
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		span.RecordError(err)
+	}
+	return rows, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // Fallback for drivers without BeginTx
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// startQuery begins a span named after the statement's first keyword, e.g. "db.query SELECT"
+func startQuery(ctx context.Context, name, query string) (context.Context, *Span) {
+	statement := strings.TrimSpace(query)
+	if keyword, _, ok := strings.Cut(statement, " "); ok {
+		name += " " + strings.ToUpper(keyword)
+	}
+	return StartKind(ctx, name, KindClient, String("db.system", "sqlite"), String("db.statement", statement))
+}
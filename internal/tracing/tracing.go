@@ -0,0 +1,207 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MaxBatchSize is how many ended spans are buffered before they are exported in the background.
+const MaxBatchSize = 512
+
+// Span kinds, as numbered by OTLP
+const (
+	KindInternal = 1
+	KindClient   = 3
+)
+
+// Attribute is a key-value pair attached to a span. Values are strings, bools, ints, int64s, or float64s.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// SpanData is the exported record of an ended span.
+type SpanData struct {
+	TraceID    [16]byte
+	SpanID     [8]byte
+	ParentID   [8]byte // Zero for root spans
+	Name       string
+	Kind       int
+	Start      time.Time
+	End        time.Time
+	Attributes []Attribute
+	Err        string // Empty unless the span recorded an error
+}
+
+// Span is an operation in progress. A nil span, returned while tracing is off, ignores every call.
+type Span struct {
+	tracer *Tracer
+	data   SpanData
+	ended  atomic.Bool
+	mu     sync.Mutex
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// RecordError marks the span as failed with err. A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Err = err.Error()
+}
+
+// End finishes the span and hands it to the tracer for export. Only the first call has an effect.
+func (s *Span) End() {
+	if s == nil || s.ended.Swap(true) {
+		return
+	}
+	s.mu.Lock()
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+	s.tracer.record(data)
+}
+
+// TraceParent returns the span's W3C traceparent header value.
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(s.data.TraceID[:]), hex.EncodeToString(s.data.SpanID[:]))
+}
+
+// Exporter sends ended spans to a tracing backend, such as an [OTLPExporter].
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Tracer buffers ended spans and exports them in batches of [MaxBatchSize].
+type Tracer struct {
+	exporter Exporter
+	buffer   []SpanData
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	onError  func(error)
+}
+
+// NewTracer creates a tracer exporting spans through exporter.
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// OnError sets a callback for failed background exports, which are otherwise dropped silently.
+func (t *Tracer) OnError(fn func(error)) {
+	t.onError = fn
+}
+
+// Flush exports every buffered span.
+func (t *Tracer) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	batch := t.buffer
+	t.buffer = nil
+	t.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return t.exporter.Export(ctx, batch)
+}
+
+// Shutdown waits for background exports and flushes the remaining spans.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.wg.Wait()
+	return t.Flush(ctx)
+}
+
+// record buffers an ended span, exporting the buffer in the background once it is full
+func (t *Tracer) record(data SpanData) {
+	t.mu.Lock()
+	t.buffer = append(t.buffer, data)
+	full := len(t.buffer) >= MaxBatchSize
+	t.mu.Unlock()
+
+	if !full {
+		return
+	}
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		if err := t.Flush(context.Background()); err != nil && t.onError != nil {
+			t.onError(err)
+		}
+	}()
+}
+
+// globalTracer is the tracer used by [Start], nil while tracing is off
+var globalTracer atomic.Pointer[Tracer]
+
+// SetTracer installs the tracer used by [Start]. A nil tracer turns tracing off.
+func SetTracer(t *Tracer) {
+	globalTracer.Store(t)
+}
+
+// spanKey is the context key of the current span
+type spanKey struct{}
+
+// SpanFromContext returns the span carried by ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Start begins an internal span named name. See [StartKind].
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return StartKind(ctx, name, KindInternal, attrs...)
+}
+
+// StartKind begins a span of the given kind as a child of the span carried by ctx, or as the root of a new trace.
+//
+// While no tracer is installed it returns ctx unchanged and a nil span.
+func StartKind(ctx context.Context, name string, kind int, attrs ...Attribute) (context.Context, *Span) {
+	tracer := globalTracer.Load()
+	if tracer == nil {
+		return ctx, nil
+	}
+
+	span := &Span{tracer: tracer, data: SpanData{
+		Name:       name,
+		Kind:       kind,
+		Start:      time.Now(),
+		Attributes: attrs,
+	}}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentID = parent.data.SpanID
+	} else {
+		_, _ = rand.Read(span.data.TraceID[:])
+	}
+	_, _ = rand.Read(span.data.SpanID[:])
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
@@ -0,0 +1,204 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Mock exporter for testing
+type mockExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (m *mockExporter) Export(ctx context.Context, spans []SpanData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans = append(m.spans, spans...)
+	return nil
+}
+
+// find returns the span named name, or whose name is name followed by a space and more
+func (m *mockExporter) find(t *testing.T, name string) SpanData {
+	t.Helper()
+	for _, span := range m.spans {
+		if span.Name == name || strings.HasPrefix(span.Name, name+" ") {
+			return span
+		}
+	}
+	t.Fatalf("no span named %s in %d exported spans", name, len(m.spans))
+	return SpanData{}
+}
+
+// installTracer installs a tracer for the duration of a test
+func installTracer(t *testing.T) (*Tracer, *mockExporter) {
+	t.Helper()
+	exporter := &mockExporter{}
+	tracer := NewTracer(exporter)
+	SetTracer(tracer)
+	t.Cleanup(func() { SetTracer(nil) })
+	return tracer, exporter
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx := context.Background()
+	got, span := Start(ctx, "noop")
+	if span != nil || got != ctx {
+		t.Fatal("expected no span and an unchanged context without a tracer")
+	}
+	span.SetAttributes(String("key", "value"))
+	span.RecordError(errors.New("ignored"))
+	span.End()
+}
+
+func TestStart_ParentAndExport(t *testing.T) {
+	tracer, exporter := installTracer(t)
+
+	ctx, parent := Start(context.Background(), "transfer")
+	_, child := Start(ctx, "transfer.search_track", String("ytx.title", "Song"))
+	child.RecordError(errors.New("no match"))
+	child.End()
+	child.End()
+	parent.End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if len(exporter.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(exporter.spans))
+	}
+
+	root, search := exporter.find(t, "transfer"), exporter.find(t, "transfer.search_track")
+	if search.TraceID != root.TraceID || search.ParentID != root.SpanID {
+		t.Error("expected the search span to be a child of the transfer span")
+	}
+	if root.ParentID != ([8]byte{}) {
+		t.Error("expected the transfer span to be a root span")
+	}
+	if search.Err != "no match" || len(search.Attributes) != 1 {
+		t.Errorf("unexpected search span %+v", search)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body map[string]any
+	var path, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+	}))
+	defer srv.Close()
+
+	exporter := NewOTLPExporter(srv.URL+"/", "ytx-test", map[string]string{"Authorization": "Bearer token"}, nil)
+	span := SpanData{Name: "transfer", Kind: KindInternal, Err: "boom", Attributes: []Attribute{Int("ytx.tracks", 3)}}
+	span.TraceID[0], span.SpanID[0] = 1, 2
+	if err := exporter.Export(context.Background(), []SpanData{span}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if path != "/v1/traces" || auth != "Bearer token" {
+		t.Errorf("posted to %s with auth %q", path, auth)
+	}
+	resourceSpans := body["resourceSpans"].([]any)[0].(map[string]any)
+	spans := resourceSpans["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)
+	encoded := spans[0].(map[string]any)
+	if encoded["traceId"] != "01000000000000000000000000000000" || encoded["spanId"] != "0200000000000000" {
+		t.Errorf("unexpected ids %v / %v", encoded["traceId"], encoded["spanId"])
+	}
+	if _, ok := encoded["parentSpanId"]; ok {
+		t.Error("expected no parentSpanId for a root span")
+	}
+	if status := encoded["status"].(map[string]any); status["code"] != float64(2) || status["message"] != "boom" {
+		t.Errorf("unexpected status %v", status)
+	}
+	attr := encoded["attributes"].([]any)[0].(map[string]any)
+	if attr["value"].(map[string]any)["intValue"] != "3" {
+		t.Errorf("unexpected attribute %v", attr)
+	}
+}
+
+func TestOTLPExporter_CollectorError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad spans", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := NewOTLPExporter(srv.URL+"/v1/traces", "ytx", nil, nil).Export(context.Background(), []SpanData{{Name: "x"}})
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected a collector error, got %v", err)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	tracer, exporter := installTracer(t)
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	ctx, parent := Start(context.Background(), "transfer")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/search", nil)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+	_ = tracer.Shutdown(context.Background())
+
+	client := exporter.find(t, "GET")
+	if client.Kind != KindClient || client.ParentID != parent.data.SpanID || client.Err != "HTTP 404" {
+		t.Errorf("unexpected client span %+v", client)
+	}
+	if !strings.HasPrefix(traceparent, "00-") || !strings.Contains(traceparent, hex.EncodeToString(client.TraceID[:])) {
+		t.Errorf("unexpected traceparent %q", traceparent)
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Error("expected the original request to be left unmodified")
+	}
+}
+
+func TestWrapDriver(t *testing.T) {
+	tracer, exporter := installTracer(t)
+	sql.Register("sqlite3-tracing-test", WrapDriver(&sqlite3.SQLiteDriver{}))
+
+	db, err := sql.Open("sqlite3-tracing-test", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "CREATE TABLE tracks (id TEXT)"); err != nil {
+		t.Fatalf("exec error = %v", err)
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tracks").Scan(&count); err != nil {
+		t.Fatalf("query error = %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO missing VALUES (1)"); err == nil {
+		t.Fatal("expected an error for a missing table")
+	}
+	_ = tracer.Shutdown(ctx)
+
+	exporter.find(t, "db.exec CREATE")
+	exporter.find(t, "db.query SELECT")
+	if failed := exporter.find(t, "db.exec INSERT"); !strings.Contains(failed.Err, "no such table") {
+		t.Errorf("expected the failed insert to be recorded, got %+v", failed)
+	}
+}
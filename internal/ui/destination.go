@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desertthunder/ytx/internal/tasks"
+)
+
+// destinationForm edits the name, description, and visibility of the destination playlist in [ConfirmView].
+//
+// Queued transfers keep each source playlist's name, so only visibility applies to them.
+type destinationForm struct {
+	name        textinput.Model
+	description textinput.Model
+	public      bool
+	editing     bool
+	focus       int // 0 for name, 1 for description
+}
+
+func newDestinationForm() destinationForm {
+	name := textinput.New()
+	name.Prompt = "Name: "
+	name.CharLimit = 150
+
+	description := textinput.New()
+	description.Prompt = "Description: "
+	description.CharLimit = 5000
+
+	return destinationForm{name: name, description: description}
+}
+
+// reset fills the form with the default destination name and description
+func (f *destinationForm) reset(name, description string) {
+	f.name.SetValue(name)
+	f.description.SetValue(description)
+	f.public = false
+	f.editing = false
+	f.blur()
+}
+
+// opts returns the transfer options for the form. Names are only set for single playlist transfers.
+func (f destinationForm) opts(queued bool) tasks.TransferOpts {
+	if queued {
+		return tasks.TransferOpts{Public: f.public}
+	}
+	return tasks.TransferOpts{Name: f.name.Value(), Description: f.description.Value(), Public: f.public}
+}
+
+func (f destinationForm) visibility() string {
+	if f.public {
+		return "Public"
+	}
+	return "Private"
+}
+
+func (f *destinationForm) blur() {
+	f.name.Blur()
+	f.description.Blur()
+}
+
+// startEditing focuses the name input
+func (f *destinationForm) startEditing() tea.Cmd {
+	f.editing = true
+	f.focus = 0
+	f.description.Blur()
+	return f.name.Focus()
+}
+
+// nextField moves focus between the name and description inputs
+func (f *destinationForm) nextField() tea.Cmd {
+	f.focus = (f.focus + 1) % 2
+	if f.focus == 0 {
+		f.description.Blur()
+		return f.name.Focus()
+	}
+	f.name.Blur()
+	return f.description.Focus()
+}
+
+// handleDestinationKeys edits the destination fields; enter or esc returns to the confirm prompt
+func (m *Model) handleDestinationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter", "esc":
+		m.destination.editing = false
+		m.destination.blur()
+		if strings.TrimSpace(m.destination.name.Value()) == "" {
+			m.destination.name.SetValue(m.selectedPlaylist.Playlist.Name)
+		}
+		return m, nil
+	case "tab", "shift+tab", "up", "down":
+		return m, m.destination.nextField()
+	}
+
+	var cmd tea.Cmd
+	if m.destination.focus == 0 {
+		m.destination.name, cmd = m.destination.name.Update(msg)
+	} else {
+		m.destination.description, cmd = m.destination.description.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) renderDestination(queued bool) string {
+	var b strings.Builder
+	b.WriteString("\nDestination:\n")
+	if !queued {
+		b.WriteString("  " + m.destination.name.View() + "\n")
+		b.WriteString("  " + m.destination.description.View() + "\n")
+	}
+	b.WriteString(fmt.Sprintf("  Visibility: %s\n", m.destination.visibility()))
+
+	if m.destination.editing {
+		b.WriteString("\n" + m.help.ShortHelpView([]key.Binding{m.keys.next, m.keys.done}))
+	}
+	return b.String()
+}
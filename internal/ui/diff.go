@@ -0,0 +1,285 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/tasks"
+)
+
+// diffViewer compares a source playlist with a playlist on the destination service in [DiffView].
+//
+// The viewer first lists destination playlists to compare against, then shows the matched, missing,
+// and extra tracks in a scrollable viewport. Missing tracks can be pushed to the destination playlist.
+type diffViewer struct {
+	source    models.Playlist
+	dest      models.Playlist
+	targets   list.Model // Destination playlists to compare against
+	picking   bool       // Choosing a destination playlist
+	comparing bool       // Diff running in the background
+	pushing   bool       // Missing tracks being pushed in the background
+	result    *tasks.TransferDiffResult
+	push      *tasks.PushResult
+	pushErr   error
+	err       error
+	viewport  viewport.Model
+}
+
+func newDiffViewer() diffViewer {
+	return diffViewer{
+		targets:  list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		viewport: viewport.New(0, 0),
+	}
+}
+
+// missing returns the source tracks not found in the destination playlist
+func (d diffViewer) missing() []models.Track {
+	if d.result == nil {
+		return nil
+	}
+	return d.result.Comparison.MissingInDest
+}
+
+// setSize fits the destination list and track viewport to the terminal
+func (d *diffViewer) setSize(width, height int) {
+	d.targets.SetSize(width-4, height-8)
+	d.viewport.Width = width - 4
+	d.viewport.Height = max(height-12, 3)
+}
+
+// startDiff fetches the destination service's playlists so one can be compared with the highlighted playlist
+func (m *Model) startDiff() tea.Cmd {
+	item, ok := m.playlistList.SelectedItem().(playlistItem)
+	if !ok || m.source.target == nil {
+		return nil
+	}
+
+	m.diff = newDiffViewer()
+	m.diff.source = item.playlist
+	m.diff.picking = true
+	m.diff.targets.Title = fmt.Sprintf("Compare '%s' with a %s playlist", item.playlist.Name, m.source.dest)
+	if m.width > 0 && m.height > 0 {
+		m.diff.setSize(m.width, m.height)
+	}
+
+	m.view = LoadingView
+	m.loadingMsg = fmt.Sprintf("Loading %s playlists...", m.source.dest)
+	return tea.Batch(m.fetchDiffTargets(), m.spinner.Tick)
+}
+
+func (m *Model) fetchDiffTargets() tea.Cmd {
+	return func() tea.Msg {
+		playlists, err := m.source.target.GetPlaylists(m.ctx)
+		return diffTargetsFetchedMsg(playlists, err)
+	}
+}
+
+// runDiff compares the source playlist with the chosen destination playlist
+func (m *Model) runDiff() tea.Cmd {
+	m.diff.comparing = true
+	source, dest := m.diff.source.ID, m.diff.dest.ID
+	return func() tea.Msg {
+		result, err := m.engine.Diff(m.ctx, m.source.service, m.source.target, source, dest, nil)
+		return diffCompleteMsg(result, err)
+	}
+}
+
+// pushMissing adds the missing tracks to the destination playlist
+func (m *Model) pushMissing() tea.Cmd {
+	m.diff.pushing = true
+	m.diff.push = nil
+	m.diff.pushErr = nil
+	dest, missing := m.diff.dest.ID, m.diff.missing()
+	return func() tea.Msg {
+		result, err := m.engine.PushMissing(m.ctx, m.source.target, dest, missing, nil)
+		return pushCompleteMsg(result, err)
+	}
+}
+
+func (m *Model) handleDiffTargetsFetched(msg Msg) (tea.Model, tea.Cmd) {
+	data := msg.data.(struct {
+		playlists []models.Playlist
+		err       error
+	})
+
+	m.view = DiffView
+	if data.err != nil {
+		m.diff.err = data.err
+		return m, nil
+	}
+
+	items := make([]list.Item, len(data.playlists))
+	for i, pl := range data.playlists {
+		items[i] = playlistItem{playlist: pl}
+	}
+	return m, m.diff.targets.SetItems(items)
+}
+
+func (m *Model) handleDiffComplete(msg Msg) (tea.Model, tea.Cmd) {
+	data := msg.data.(struct {
+		result *tasks.TransferDiffResult
+		err    error
+	})
+
+	m.diff.comparing = false
+	m.diff.err = data.err
+	if data.err == nil {
+		m.diff.result = data.result
+		m.diff.viewport.SetContent(m.renderDiffTracks())
+	}
+	return m, nil
+}
+
+// handlePushComplete records the outcome of a push and compares the playlists again to show what is still missing
+func (m *Model) handlePushComplete(msg Msg) (tea.Model, tea.Cmd) {
+	data := msg.data.(struct {
+		result *tasks.PushResult
+		err    error
+	})
+
+	m.diff.pushing = false
+	m.diff.push = data.result
+	m.diff.pushErr = data.err
+	if data.result == nil || data.result.AddedCount == 0 {
+		return m, nil
+	}
+	return m, m.runDiff()
+}
+
+// handleDiffKeys picks the destination playlist, then scrolls the comparison and pushes missing tracks
+func (m *Model) handleDiffKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if m.diff.picking && m.diff.targets.SettingFilter() && msg.String() != "ctrl+c" {
+		m.diff.targets, cmd = m.diff.targets.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		if m.diff.picking || m.diff.err != nil {
+			m.view = PlaylistListView
+			return m, nil
+		}
+		m.diff.picking = true
+		m.diff.result = nil
+		m.diff.push = nil
+		m.diff.pushErr = nil
+		return m, nil
+	}
+
+	if m.diff.picking {
+		if msg.String() == "enter" {
+			if item, ok := m.diff.targets.SelectedItem().(playlistItem); ok {
+				m.diff.dest = item.playlist
+				m.diff.picking = false
+				return m, m.runDiff()
+			}
+		}
+		m.diff.targets, cmd = m.diff.targets.Update(msg)
+		return m, cmd
+	}
+
+	if msg.String() == "p" && !m.diff.pushing && !m.diff.comparing && len(m.diff.missing()) > 0 {
+		return m, m.pushMissing()
+	}
+
+	m.diff.viewport, cmd = m.diff.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) renderDiff() string {
+	if m.diff.err != nil {
+		title := styles.err.Render("Comparison failed")
+		helpView := m.help.ShortHelpView([]key.Binding{m.keys.back, m.keys.quit})
+		return fmt.Sprintf("%s\n\n%v\n\n%s", title, m.diff.err, helpView)
+	}
+
+	if m.diff.picking {
+		helpView := m.help.ShortHelpView([]key.Binding{m.keys.enter, m.keys.filter, m.keys.back, m.keys.quit})
+		return fmt.Sprintf("%s\n\n%s", m.diff.targets.View(), helpView)
+	}
+
+	title := styles.title.Render(fmt.Sprintf("'%s' (%s) vs '%s' (%s)",
+		m.diff.source.Name, m.source.service.Name(), m.diff.dest.Name, m.source.dest))
+
+	if m.diff.result == nil {
+		return fmt.Sprintf("%s\n\nComparing playlists...", title)
+	}
+
+	comparison := m.diff.result.Comparison
+	summary := fmt.Sprintf("%s • %s • %s",
+		styles.ok.Render(fmt.Sprintf("%d matched", comparison.MatchedCount)),
+		styles.err.Render(fmt.Sprintf("%d missing", len(comparison.MissingInDest))),
+		styles.warn.Render(fmt.Sprintf("%d extra", len(comparison.ExtraInDest))),
+	)
+
+	var status string
+	switch {
+	case m.diff.pushing:
+		status = fmt.Sprintf("Pushing %d missing tracks to %s...", len(comparison.MissingInDest), m.source.dest)
+	case m.diff.comparing:
+		status = "Refreshing comparison..."
+	case m.diff.pushErr != nil:
+		status = styles.err.Render(fmt.Sprintf("Push failed: %v", m.diff.pushErr))
+	case m.diff.push != nil:
+		status = styles.ok.Render(fmt.Sprintf("✓ Added %d tracks", m.diff.push.AddedCount))
+		if m.diff.push.FailedCount > 0 {
+			status += styles.warn.Render(fmt.Sprintf(" (%d not found on %s)", m.diff.push.FailedCount, m.source.dest))
+		}
+		if m.diff.push.SkippedCount > 0 {
+			status += styles.warn.Render(fmt.Sprintf(" (%d skipped: unmatchable)", m.diff.push.SkippedCount))
+		}
+	}
+
+	helpKeys := []key.Binding{m.keys.up, m.keys.down}
+	if len(comparison.MissingInDest) > 0 && !m.diff.pushing && !m.diff.comparing {
+		helpKeys = append(helpKeys, m.keys.push)
+	}
+	helpKeys = append(helpKeys, m.keys.back, m.keys.quit)
+	helpView := m.help.ShortHelpView(helpKeys)
+
+	return fmt.Sprintf("%s\n%s\n\n%s\n\n%s\n%s", title, summary, m.diff.viewport.View(), status, helpView)
+}
+
+// renderDiffTracks lists the missing, extra, and matched tracks for the viewport
+func (m *Model) renderDiffTracks() string {
+	comparison := m.diff.result.Comparison
+
+	var b strings.Builder
+	sections := []struct {
+		heading string
+		marker  string
+		tracks  []models.Track
+		style   func(...string) string
+	}{
+		{fmt.Sprintf("Missing from %s", m.source.dest), "−", comparison.MissingInDest, styles.err.Render},
+		{fmt.Sprintf("Only on %s", m.source.dest), "+", comparison.ExtraInDest, styles.warn.Render},
+		{"Matched", "✓", comparison.Matched, styles.ok.Render},
+	}
+
+	for _, section := range sections {
+		if len(section.tracks) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(section.style(fmt.Sprintf("%s (%d)", section.heading, len(section.tracks))) + "\n")
+		for _, track := range section.tracks {
+			b.WriteString(section.style(fmt.Sprintf("  %s %s - %s", section.marker, track.Artist(), track.Title)) + "\n")
+		}
+	}
+
+	if b.Len() == 0 {
+		return styles.help.Render("Both playlists are empty.")
+	}
+	return b.String()
+}
@@ -1,11 +1,15 @@
 // Package ui implements an interactive terminal interface using bubbletea's Elm architecture.
 //
-// The TUI provides a multi-view workflow for playlist migration:
-//  1. [PlaylistListView] : Browse and select Spotify playlists
+// The TUI provides a multi-view workflow for playlist migration in either direction:
+//  0. [SourceView] : Choose Spotify or YouTube Music as the source (s switches back from the playlist list)
+//  1. [PlaylistListView] : Browse, filter (/), and select playlists; space queues several for transfer (t)
 //  2. [TrackListView] : Preview tracks before transfer
-//  3. [ConfirmView] : Confirm transfer operation
-//  4. [TransferView] : Monitor real-time progress updates
-//  5. [ResultView] : Display success metrics and failed matches
+//  3. [ConfirmView] : Confirm transfer operation after editing the destination name/description (e) and visibility (p)
+//  4. [TransferView] : Monitor a progress bar with phase timings, throughput, and ETA, or cancel the transfer (c/esc)
+//  5. [ResultView] : Display success metrics and failed matches, or how far a cancelled transfer got
+//  6. [QueueView] : Transfer queued playlists one at a time, showing each playlist's status
+//  7. [DiffView] : Compare a playlist (d) with one on the destination service and push missing tracks (p)
+//  8. [ExportView] : Export the selected playlists (x) as json/csv/markdown/txt into a chosen directory
 //
 // The (view) [Model] implements bubbletea/Elm's standard Init/Update/View pattern, receiving messages via the Msg union type.
 // Progress updates flow through a channel from the PlaylistEngine, providing non-blocking status reporting during transfers.
@@ -0,0 +1,323 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/tasks"
+)
+
+// exportFormats are the formats offered in [ExportView], in the order they are cycled.
+var exportFormats = []string{"json", "csv", "markdown", "txt"}
+
+// bulkExport configures and runs a [tasks.PlaylistEngine.BulkExport] of the selected playlists in [ExportView].
+//
+// The form picks the format and output directory; once started, finished playlists are counted from the
+// results carried by the engine's progress updates.
+type bulkExport struct {
+	playlists    []models.Playlist
+	format       int // Index into exportFormats
+	outputDir    textinput.Model
+	editing      bool // Output directory input focused
+	running      bool
+	cancel       context.CancelFunc // Cancels the running export; nil when idle
+	cancelling   bool
+	progressChan chan tasks.ProgressUpdate
+	latest       tasks.ProgressUpdate
+	succeeded    int
+	failed       int
+	bar          progress.Model
+	result       *tasks.BulkExportResult
+	err          error
+}
+
+func newBulkExport() bulkExport {
+	outputDir := textinput.New()
+	outputDir.Prompt = "Output directory: "
+	outputDir.CharLimit = 255
+	return bulkExport{outputDir: outputDir}
+}
+
+func (x bulkExport) formatName() string {
+	return exportFormats[x.format]
+}
+
+// finished reports whether the export has run and returned
+func (x bulkExport) finished() bool {
+	return !x.running && (x.result != nil || x.err != nil)
+}
+
+func (x bulkExport) percent() float64 {
+	if len(x.playlists) == 0 {
+		return 0
+	}
+	return float64(x.succeeded+x.failed) / float64(len(x.playlists))
+}
+
+// exportPlaylists returns the selected playlists in list order, or the highlighted playlist when none are selected
+func (m *Model) exportPlaylists() []models.Playlist {
+	if len(m.selected) == 0 {
+		if item, ok := m.playlistList.SelectedItem().(playlistItem); ok {
+			return []models.Playlist{item.playlist}
+		}
+		return nil
+	}
+
+	playlists := make([]models.Playlist, 0, len(m.selected))
+	for _, pl := range m.playlists {
+		if m.selected[pl.ID] {
+			playlists = append(playlists, pl)
+		}
+	}
+	return playlists
+}
+
+// openExport shows the export form for the selected playlists
+func (m *Model) openExport() {
+	playlists := m.exportPlaylists()
+	if len(playlists) == 0 {
+		return
+	}
+
+	m.export = newBulkExport()
+	m.export.playlists = playlists
+	m.export.outputDir.SetValue(fmt.Sprintf("%s_export_%d", m.source.key(), time.Now().Unix()))
+	m.view = ExportView
+}
+
+// startExport runs the bulk export in the background, streaming its progress updates
+func (m *Model) startExport() tea.Cmd {
+	ids := make([]string, len(m.export.playlists))
+	for i, pl := range m.export.playlists {
+		ids[i] = pl.ID
+	}
+
+	opts := tasks.BulkExportOpts{
+		Format:    m.export.formatName(),
+		OutputDir: strings.TrimSpace(m.export.outputDir.Value()),
+	}
+	if spotifySvc, ok := m.source.service.(*services.SpotifyService); ok && opts.Format == "markdown" {
+		opts.GetCoverImage = spotifySvc.CoverImageURL
+	}
+
+	progressCh := make(chan tasks.ProgressUpdate, 100)
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.export.progressChan = progressCh
+	m.export.cancel = cancel
+	m.export.running = true
+	m.export.bar = progress.New(progress.WithDefaultGradient())
+	m.export.bar.Width = barWidth(m.width)
+
+	go func() {
+		result, err := m.engine.BulkExport(ctx, progressCh, m.source.service, ids, opts)
+		m.export.result = result
+		m.export.err = err
+		close(progressCh)
+	}()
+
+	return m.waitForExportProgress()
+}
+
+func (m *Model) waitForExportProgress() tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-m.export.progressChan
+		if !ok {
+			return exportCompleteMsg(m.export.result, m.export.err)
+		}
+		return exportProgressMsg(update)
+	}
+}
+
+// handleExportProgress counts playlists as the engine reports them finished
+func (m *Model) handleExportProgress(msg Msg) (tea.Model, tea.Cmd) {
+	update := msg.data.(tasks.ProgressUpdate)
+	m.export.latest = update
+	if res, ok := update.Data.(tasks.PlaylistExportResult); ok {
+		if res.Success {
+			m.export.succeeded++
+		} else {
+			m.export.failed++
+		}
+	}
+	return m, m.waitForExportProgress()
+}
+
+func (m *Model) handleExportComplete(msg Msg) (tea.Model, tea.Cmd) {
+	data := msg.data.(struct {
+		result *tasks.BulkExportResult
+		err    error
+	})
+
+	if m.export.cancel != nil {
+		m.export.cancel()
+		m.export.cancel = nil
+	}
+	m.export.running = false
+	m.export.progressChan = nil
+	m.export.result = data.result
+	m.export.err = data.err
+	if data.result != nil {
+		m.export.succeeded = data.result.SuccessfulExports
+		m.export.failed = data.result.FailedExports
+	}
+	return m, nil
+}
+
+// handleExportKeys edits the export form, cancels a running export, or restarts once it has finished
+func (m *Model) handleExportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.export.running:
+		switch msg.String() {
+		case "c", "esc", "ctrl+c":
+			if m.export.cancel != nil && !m.export.cancelling {
+				m.export.cancelling = true
+				m.export.cancel()
+			}
+		}
+		return m, nil
+	case m.export.finished():
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "r":
+			m.clearSelection()
+			m.view = PlaylistListView
+		}
+		return m, nil
+	case m.export.editing:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "enter", "esc", "tab":
+			m.export.editing = false
+			m.export.outputDir.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.export.outputDir, cmd = m.export.outputDir.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.view = PlaylistListView
+	case "left", "h":
+		m.export.format = (m.export.format + len(exportFormats) - 1) % len(exportFormats)
+	case "right", "l":
+		m.export.format = (m.export.format + 1) % len(exportFormats)
+	case "tab":
+		m.export.editing = true
+		return m, m.export.outputDir.Focus()
+	case "enter":
+		if strings.TrimSpace(m.export.outputDir.Value()) != "" {
+			return m, m.startExport()
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) renderExport() string {
+	switch {
+	case m.export.running:
+		return m.renderExportProgress()
+	case m.export.finished():
+		return m.renderExportResult()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.title.Render(fmt.Sprintf("Export %d %s playlists", len(m.export.playlists), m.source.service.Name())))
+	b.WriteString("\n\n")
+	for _, pl := range m.export.playlists {
+		b.WriteString(fmt.Sprintf("  • %s (%d tracks)\n", pl.Name, pl.TrackCount))
+	}
+
+	b.WriteString("\nFormat: ")
+	for i, format := range exportFormats {
+		if i == m.export.format {
+			b.WriteString(styles.ok.Render("[" + format + "]"))
+		} else {
+			b.WriteString(styles.help.Render(" " + format + " "))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString("\n" + m.export.outputDir.View() + "\n\n")
+
+	if m.export.editing {
+		b.WriteString(m.help.ShortHelpView([]key.Binding{m.keys.done}))
+	} else {
+		b.WriteString(m.help.ShortHelpView([]key.Binding{m.keys.format, m.keys.next, m.keys.start, m.keys.back, m.keys.quit}))
+	}
+	return b.String()
+}
+
+func (m *Model) renderExportProgress() string {
+	title := styles.title.Render(fmt.Sprintf("Exporting %d playlists as %s", len(m.export.playlists), m.export.formatName()))
+	counts := fmt.Sprintf("%d/%d finished • %s • %s",
+		m.export.succeeded+m.export.failed, len(m.export.playlists),
+		styles.ok.Render(fmt.Sprintf("%d exported", m.export.succeeded)),
+		styles.err.Render(fmt.Sprintf("%d failed", m.export.failed)),
+	)
+
+	if m.export.cancelling {
+		return fmt.Sprintf("%s\n\n%s\n%s\n\n%s", title, m.export.bar.ViewAs(m.export.percent()), counts,
+			styles.warn.Render("Cancelling after the playlists in progress..."))
+	}
+
+	helpView := m.help.ShortHelpView([]key.Binding{m.keys.cancel})
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s\n\n%s", title, m.export.bar.ViewAs(m.export.percent()), counts, m.export.latest.Message, helpView)
+}
+
+func (m *Model) renderExportResult() string {
+	helpView := m.help.ShortHelpView([]key.Binding{m.keys.restart, m.keys.quit})
+	result := m.export.result
+
+	if result == nil {
+		return fmt.Sprintf("%s\n\n%s", styles.err.Render(fmt.Sprintf("Export failed: %v", m.export.err)), helpView)
+	}
+
+	var title string
+	switch {
+	case m.export.cancelling || errors.Is(m.export.err, context.Canceled):
+		title = styles.warn.Render("✗ Export Cancelled")
+	case m.export.err != nil:
+		title = styles.warn.Render(fmt.Sprintf("⚠ %v", m.export.err))
+	case result.FailedExports > 0:
+		title = styles.warn.Render("⚠ Export finished with failures")
+	default:
+		title = styles.ok.Render("✓ Export Complete!")
+	}
+
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	b.WriteString(fmt.Sprintf("Total playlists: %d\n", result.TotalPlaylists))
+	b.WriteString(fmt.Sprintf("Successful: %d\n", result.SuccessfulExports))
+	b.WriteString(fmt.Sprintf("Failed: %d\n", result.FailedExports))
+	b.WriteString(fmt.Sprintf("Output directory: %s\n", result.OutputDirectory))
+	if result.ManifestPath != "" {
+		b.WriteString(fmt.Sprintf("Manifest: %s\n", result.ManifestPath))
+	}
+
+	if result.FailedExports > 0 {
+		b.WriteString("\n" + styles.warn.Render("Failed exports:"))
+		for _, res := range result.Results {
+			if !res.Success {
+				b.WriteString(fmt.Sprintf("\n  ✗ %s: %v", res.PlaylistName, res.Error))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + helpView)
+	return b.String()
+}
@@ -4,26 +4,54 @@ import "github.com/charmbracelet/bubbles/key"
 
 // keyMap defines the [key.Binding] mapping for the TUI.
 type keyMap struct {
-	up      key.Binding
-	down    key.Binding
-	enter   key.Binding
-	back    key.Binding
-	yes     key.Binding
-	no      key.Binding
-	restart key.Binding
-	quit    key.Binding
+	up       key.Binding
+	down     key.Binding
+	enter    key.Binding
+	toggle   key.Binding
+	filter   key.Binding
+	transfer key.Binding
+	source   key.Binding
+	diff     key.Binding
+	push     key.Binding
+	export   key.Binding
+	format   key.Binding
+	start    key.Binding
+	edit     key.Binding
+	public   key.Binding
+	next     key.Binding
+	done     key.Binding
+	back     key.Binding
+	yes      key.Binding
+	no       key.Binding
+	restart  key.Binding
+	cancel   key.Binding
+	quit     key.Binding
 }
 
 func newKeyMap() keyMap {
 	return keyMap{
-		up:      key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
-		down:    key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
-		enter:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
-		back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
-		yes:     key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes")),
-		no:      key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "no")),
-		restart: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "restart")),
-		quit:    key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		enter:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+		toggle:   key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+		filter:   key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		transfer: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "transfer")),
+		source:   key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "switch source")),
+		diff:     key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "compare")),
+		push:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "push missing")),
+		export:   key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "export")),
+		format:   key.NewBinding(key.WithKeys("left", "right", "h", "l"), key.WithHelp("←/→", "format")),
+		start:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "start")),
+		edit:     key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit details")),
+		public:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "public/private")),
+		next:     key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next field")),
+		done:     key.NewBinding(key.WithKeys("enter", "esc"), key.WithHelp("enter", "done")),
+		back:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+		yes:      key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes")),
+		no:       key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "no")),
+		restart:  key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "restart")),
+		cancel:   key.NewBinding(key.WithKeys("c", "esc", "ctrl+c"), key.WithHelp("c/esc", "cancel")),
+		quit:     key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
 	}
 }
 
@@ -34,7 +62,10 @@ func (k keyMap) ShortHelp() []key.Binding {
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.up, k.down, k.enter},
+		{k.toggle, k.filter, k.transfer, k.source},
+		{k.diff, k.push, k.export, k.format, k.start},
 		{k.back, k.yes, k.no},
-		{k.restart, k.quit},
+		{k.edit, k.public, k.next, k.done},
+		{k.restart, k.cancel, k.quit},
 	}
 }
@@ -5,20 +5,50 @@ import (
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/services"
 )
 
 var (
+	_ list.Item = sourceItem{}
 	_ list.Item = playlistItem{}
 	_ list.Item = trackItem{}
 )
 
+// sourceItem is a service to browse and transfer playlists from, implementing [list.Item].
+type sourceItem struct {
+	service services.Service
+	target  services.Service // Service playlists are transferred to; nil when unavailable
+	dest    string           // Name of the service playlists are transferred to
+	reverse bool             // Transfers run YouTube Music → Spotify
+}
+
+// key returns the service key used for caching and default export directories
+func (i sourceItem) key() string {
+	if i.reverse {
+		return "youtube"
+	}
+	return "spotify"
+}
+
+func (i sourceItem) FilterValue() string { return i.service.Name() }
+func (i sourceItem) Title() string       { return i.service.Name() }
+func (i sourceItem) Description() string {
+	return fmt.Sprintf("Browse %s playlists and transfer them to %s", i.service.Name(), i.dest)
+}
+
 // playlistItem wraps [models.Playlist] to implement [list.Item].
 type playlistItem struct {
 	playlist models.Playlist
+	selected bool // Queued for a multi-playlist transfer
 }
 
 func (i playlistItem) FilterValue() string { return i.playlist.Name }
-func (i playlistItem) Title() string       { return i.playlist.Name }
+func (i playlistItem) Title() string {
+	if i.selected {
+		return "● " + i.playlist.Name
+	}
+	return i.playlist.Name
+}
 func (i playlistItem) Description() string {
 	desc := fmt.Sprintf("%d tracks", i.playlist.TrackCount)
 	if i.playlist.Description != "" {
@@ -35,7 +65,7 @@ type trackItem struct {
 func (i trackItem) FilterValue() string { return i.track.Title }
 func (i trackItem) Title() string       { return i.track.Title }
 func (i trackItem) Description() string {
-	desc := i.track.Artist
+	desc := i.track.Artist()
 	if i.track.Album != "" {
 		desc = fmt.Sprintf("%s • %s", desc, i.track.Album)
 	}
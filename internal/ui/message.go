@@ -24,6 +24,11 @@ const (
 	MsgTracksFetched
 	MsgProgressUpdate
 	MsgTransferComplete
+	MsgDiffTargetsFetched
+	MsgDiffComplete
+	MsgPushComplete
+	MsgExportProgress
+	MsgExportComplete
 )
 
 // playlistsFetchedMsg is the constructor for [MsgPlaylistsFetched]
@@ -53,6 +58,55 @@ func progressUpdateMsg(update tasks.ProgressUpdate) Msg {
 	return Msg{kind: MsgProgressUpdate, data: update}
 }
 
+// diffTargetsFetchedMsg is the constructor for [MsgDiffTargetsFetched]
+func diffTargetsFetchedMsg(playlists []models.Playlist, err error) Msg {
+	return Msg{
+		kind: MsgDiffTargetsFetched,
+		data: struct {
+			playlists []models.Playlist
+			err       error
+		}{playlists, err},
+	}
+}
+
+// diffCompleteMsg is the constructor for [MsgDiffComplete]
+func diffCompleteMsg(result *tasks.TransferDiffResult, err error) Msg {
+	return Msg{
+		kind: MsgDiffComplete,
+		data: struct {
+			result *tasks.TransferDiffResult
+			err    error
+		}{result, err},
+	}
+}
+
+// pushCompleteMsg is the constructor for [MsgPushComplete]
+func pushCompleteMsg(result *tasks.PushResult, err error) Msg {
+	return Msg{
+		kind: MsgPushComplete,
+		data: struct {
+			result *tasks.PushResult
+			err    error
+		}{result, err},
+	}
+}
+
+// exportProgressMsg is the constructor for [MsgExportProgress]
+func exportProgressMsg(update tasks.ProgressUpdate) Msg {
+	return Msg{kind: MsgExportProgress, data: update}
+}
+
+// exportCompleteMsg is the constructor for [MsgExportComplete]
+func exportCompleteMsg(result *tasks.BulkExportResult, err error) Msg {
+	return Msg{
+		kind: MsgExportComplete,
+		data: struct {
+			result *tasks.BulkExportResult
+			err    error
+		}{result, err},
+	}
+}
+
 // transferCompleteMsg is the constructor for [MsgTransferComplete]
 func transferCompleteMsg(result *tasks.TransferRunResult, err error) Msg {
 	return Msg{
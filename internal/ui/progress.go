@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/desertthunder/ytx/internal/tasks"
+)
+
+// maxBarWidth caps the progress bar on wide terminals.
+const maxBarWidth = 60
+
+// phaseTiming records how long a finished transfer phase took.
+type phaseTiming struct {
+	phase    tasks.Phase
+	duration time.Duration
+}
+
+// transferStats derives per-phase timing, search throughput, and an ETA from timestamped [tasks.ProgressUpdate]s.
+//
+// Only update timestamps are used, so the figures describe the transfer as of its latest update.
+type transferStats struct {
+	bar        progress.Model
+	phase      tasks.Phase
+	phaseStart time.Time
+	timings    []phaseTiming
+	latest     tasks.ProgressUpdate
+}
+
+func newTransferStats(width int) transferStats {
+	bar := progress.New(progress.WithDefaultGradient())
+	bar.Width = barWidth(width)
+	return transferStats{bar: bar}
+}
+
+// barWidth fits the progress bar to the terminal width
+func barWidth(width int) int {
+	if width <= 4 {
+		return maxBarWidth
+	}
+	return min(width-4, maxBarWidth)
+}
+
+// observe records an update, closing the timing of the previous phase when the phase changes
+func (s *transferStats) observe(update tasks.ProgressUpdate) {
+	// A rate limit pause interrupts the current phase rather than starting a new one
+	if update.Phase == tasks.RateLimited {
+		return
+	}
+	if update.Time.IsZero() {
+		update.Time = time.Now()
+	}
+
+	switch {
+	case s.phaseStart.IsZero():
+		s.phase = update.Phase
+		s.phaseStart = update.Time
+	case update.Phase != s.phase:
+		s.timings = append(s.timings, phaseTiming{phase: s.phase, duration: update.Time.Sub(s.phaseStart)})
+		s.phase = update.Phase
+		s.phaseStart = update.Time
+	}
+
+	s.latest = update
+}
+
+// completed returns the number of finished steps in the current phase.
+//
+// Track searches report the track about to be searched, so one fewer has finished.
+func (s transferStats) completed() int {
+	if s.phase == tasks.SearchTracks {
+		return max(s.latest.Step-1, 0)
+	}
+	return s.latest.Step
+}
+
+func (s transferStats) percent() float64 {
+	if s.latest.Total == 0 {
+		return 0
+	}
+	return float64(s.completed()) / float64(s.latest.Total)
+}
+
+// rate returns tracks searched per second, or zero outside the search phase and before any track finishes
+func (s transferStats) rate() float64 {
+	elapsed := s.latest.Time.Sub(s.phaseStart).Seconds()
+	if s.phase != tasks.SearchTracks || elapsed <= 0 || s.completed() == 0 {
+		return 0
+	}
+	return float64(s.completed()) / elapsed
+}
+
+// eta estimates the time left to search the remaining tracks at the current rate
+func (s transferStats) eta() (time.Duration, bool) {
+	rate := s.rate()
+	if rate == 0 {
+		return 0, false
+	}
+	remaining := float64(s.latest.Total - s.completed())
+	return time.Duration(remaining / rate * float64(time.Second)).Round(time.Second), true
+}
+
+// summary reports search progress, throughput, and ETA on a single line
+func (s transferStats) summary() string {
+	if s.phase != tasks.SearchTracks {
+		return ""
+	}
+
+	line := fmt.Sprintf("%d/%d tracks", s.completed(), s.latest.Total)
+	if rate := s.rate(); rate > 0 {
+		line += fmt.Sprintf(" • %.1f tracks/s", rate)
+	}
+	if eta, ok := s.eta(); ok {
+		line += fmt.Sprintf(" • ETA %s", eta)
+	}
+	return line
+}
+
+// View renders the progress bar, throughput, and the duration of each finished phase
+func (s transferStats) View() string {
+	var b strings.Builder
+	b.WriteString(s.bar.ViewAs(s.percent()))
+
+	if summary := s.summary(); summary != "" {
+		b.WriteString("\n" + summary)
+	}
+
+	for _, timing := range s.timings {
+		b.WriteString("\n" + styles.help.Render(fmt.Sprintf("✓ %s (%s)", timing.phase.Description(), timing.duration.Round(time.Millisecond))))
+	}
+	return b.String()
+}
@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/tasks"
+)
+
+// QueueStatus is the state of a playlist queued for transfer in [QueueView].
+type QueueStatus int
+
+const (
+	QueuePending QueueStatus = iota
+	QueueRunning
+	QueueCompleted
+	QueueFailed
+	QueueCancelled
+)
+
+func (s QueueStatus) String() string {
+	switch s {
+	case QueuePending:
+		return "pending"
+	case QueueRunning:
+		return "running"
+	case QueueCompleted:
+		return "completed"
+	case QueueFailed:
+		return "failed"
+	case QueueCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// queueItem is a playlist queued for transfer along with its outcome.
+type queueItem struct {
+	playlist models.Playlist
+	status   QueueStatus
+	result   *tasks.TransferRunResult
+	err      error
+}
+
+// toggleSelection adds or removes the highlighted playlist from the multi-selection
+func (m *Model) toggleSelection() tea.Cmd {
+	item, ok := m.playlistList.SelectedItem().(playlistItem)
+	if !ok {
+		return nil
+	}
+
+	item.selected = !item.selected
+	if item.selected {
+		m.selected[item.playlist.ID] = true
+	} else {
+		delete(m.selected, item.playlist.ID)
+	}
+
+	m.updatePlaylistTitle()
+	return m.playlistList.SetItem(m.playlistList.GlobalIndex(), item)
+}
+
+// clearSelection deselects every playlist
+func (m *Model) clearSelection() {
+	m.selected = map[string]bool{}
+	items := m.playlistList.Items()
+	for i, it := range items {
+		if item, ok := it.(playlistItem); ok {
+			item.selected = false
+			items[i] = item
+		}
+	}
+	m.playlistList.SetItems(items)
+	m.updatePlaylistTitle()
+}
+
+// updatePlaylistTitle shows the number of selected playlists in the list title
+func (m *Model) updatePlaylistTitle() {
+	title := fmt.Sprintf("%s Playlists", m.source.service.Name())
+	if len(m.selected) > 0 {
+		title = fmt.Sprintf("%s (%d selected)", title, len(m.selected))
+	}
+	m.playlistList.Title = title
+}
+
+// buildQueue queues the selected playlists in list order
+func (m *Model) buildQueue() {
+	m.queue = make([]queueItem, 0, len(m.selected))
+	for _, pl := range m.playlists {
+		if m.selected[pl.ID] {
+			m.queue = append(m.queue, queueItem{playlist: pl})
+		}
+	}
+	m.queueIndex = 0
+}
+
+// startQueue begins transferring the first queued playlist
+func (m *Model) startQueue() tea.Cmd {
+	m.view = QueueView
+	m.queue[0].status = QueueRunning
+	m.engine.ObserveQueueDepth(len(m.queue))
+	return m.startTransfer(m.queue[0].playlist.ID, m.destination.opts(true))
+}
+
+// handleQueueComplete records the outcome of the running queue item and starts the next one.
+//
+// A cancelled transfer cancels every playlist still pending.
+func (m *Model) handleQueueComplete(result *tasks.TransferRunResult, err error) (tea.Model, tea.Cmd) {
+	current := &m.queue[m.queueIndex]
+	current.result = result
+	current.err = err
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		current.status = QueueCancelled
+		for i := m.queueIndex + 1; i < len(m.queue); i++ {
+			m.queue[i].status = QueueCancelled
+		}
+		m.queueIndex = len(m.queue)
+		m.engine.ObserveQueueDepth(0)
+		return m, nil
+	case err != nil:
+		current.status = QueueFailed
+	default:
+		current.status = QueueCompleted
+	}
+
+	m.queueIndex++
+	m.engine.ObserveQueueDepth(len(m.queue) - m.queueIndex)
+	if m.queueIndex >= len(m.queue) {
+		return m, nil
+	}
+
+	m.queue[m.queueIndex].status = QueueRunning
+	return m, m.startTransfer(m.queue[m.queueIndex].playlist.ID, m.destination.opts(true))
+}
+
+// queueRunning reports whether a queued transfer is still in progress
+func (m *Model) queueRunning() bool {
+	return m.queueIndex < len(m.queue)
+}
+
+// handleQueueKeys cancels the running queue, or restarts/quits once every playlist has finished
+func (m *Model) handleQueueKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.queueRunning() {
+		return m.handleTransferKeys(msg)
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.queue = nil
+		m.queueIndex = 0
+		m.cancelling = false
+		m.clearSelection()
+		m.view = PlaylistListView
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m *Model) renderQueue() string {
+	var (
+		b         strings.Builder
+		completed int
+	)
+
+	if m.queueRunning() {
+		b.WriteString(styles.title.Render(fmt.Sprintf("Transferring Playlists (%d/%d)", m.queueIndex+1, len(m.queue))))
+	} else {
+		b.WriteString(styles.title.Render("Transfer Queue Finished"))
+	}
+	b.WriteString("\n\n")
+
+	for _, item := range m.queue {
+		line := fmt.Sprintf("%-10s %s", item.status, item.playlist.Name)
+		switch item.status {
+		case QueueRunning:
+			if summary := m.stats.summary(); summary != "" {
+				line += fmt.Sprintf(" (%s)", summary)
+			}
+			b.WriteString(styles.title.Render(line))
+		case QueueCompleted:
+			completed++
+			b.WriteString(styles.ok.Render(line))
+			if item.result != nil {
+				b.WriteString(fmt.Sprintf(" — %d/%d matched", item.result.SuccessCount, item.result.MatchableTracks()))
+				if item.result.SkippedCount > 0 {
+					b.WriteString(fmt.Sprintf(", %d skipped", item.result.SkippedCount))
+				}
+			}
+		case QueueFailed:
+			b.WriteString(styles.err.Render(line))
+			b.WriteString(fmt.Sprintf(" — %v", item.err))
+		case QueueCancelled:
+			b.WriteString(styles.warn.Render(line))
+		default:
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	var helpKeys []key.Binding
+	switch {
+	case m.cancelling && m.queueRunning():
+		b.WriteString("\n" + styles.warn.Render("Cancelling after the current track..."))
+	case m.queueRunning():
+		helpKeys = []key.Binding{m.keys.cancel}
+	default:
+		b.WriteString(fmt.Sprintf("\n%d of %d playlists transferred", completed, len(m.queue)))
+		helpKeys = []key.Binding{m.keys.restart, m.keys.quit}
+	}
+
+	if len(helpKeys) > 0 {
+		b.WriteString("\n\n" + m.help.ShortHelpView(helpKeys))
+	}
+	return b.String()
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -26,13 +27,18 @@ const (
 	TransferView
 	ResultView
 	AuthErrorView
+	QueueView
+	SourceView
+	DiffView
+	ExportView
 )
 
 // Model represents the TUI application state.
 type Model struct {
 	ctx              context.Context
 	view             ViewState
-	spotify          services.Service
+	source           sourceItem // Service being browsed and the direction of its transfers
+	sourceList       list.Model
 	engine           *tasks.PlaylistEngine
 	width            int
 	height           int
@@ -40,10 +46,19 @@ type Model struct {
 	loadingMsg       string
 	playlistList     list.Model
 	playlists        []models.Playlist
+	selected         map[string]bool // Playlist IDs selected for a queued transfer
+	queue            []queueItem
+	queueIndex       int // Index of the running queue item; len(queue) once finished
 	trackList        list.Model
 	selectedPlaylist *models.PlaylistExport
+	destination      destinationForm
+	diff             diffViewer
+	export           bulkExport
 	progressChan     chan tasks.ProgressUpdate
 	progress         tasks.ProgressUpdate
+	stats            transferStats
+	cancelTransfer   context.CancelFunc // Cancels the running transfer; nil outside TransferView
+	cancelling       bool
 	result           *tasks.TransferRunResult
 	err              error
 	authErrorMsg     string
@@ -53,9 +68,25 @@ type Model struct {
 }
 
 // NewModel creates a new TUI [Model] with the provided dependencies.
-func NewModel(ctx context.Context, spotify services.Service, engine *tasks.PlaylistEngine) *Model {
+//
+// When youtube is nil the source selection is skipped and Spotify playlists are loaded immediately.
+func NewModel(ctx context.Context, spotify, youtube services.Service, engine *tasks.PlaylistEngine) *Model {
+	source := sourceItem{service: spotify, dest: "YouTube Music"}
+	view := LoadingView
+	sources := []list.Item{}
+	if youtube != nil {
+		source.target = youtube
+		source.dest = youtube.Name()
+		view = SourceView
+		sources = append(sources, source, sourceItem{service: youtube, target: spotify, dest: spotify.Name(), reverse: true})
+	}
+
+	sourceList := list.New(sources, list.NewDefaultDelegate(), 0, 0)
+	sourceList.Title = "Transfer playlists from"
+	sourceList.SetFilteringEnabled(false)
+
 	playlistList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-	playlistList.Title = "Spotify Playlists"
+	playlistList.Title = fmt.Sprintf("%s Playlists", source.service.Name())
 
 	trackList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 
@@ -65,20 +96,28 @@ func NewModel(ctx context.Context, spotify services.Service, engine *tasks.Playl
 
 	return &Model{
 		ctx:          ctx,
-		view:         LoadingView,
-		spotify:      spotify,
+		view:         view,
+		source:       source,
+		sourceList:   sourceList,
 		engine:       engine,
 		spinner:      s,
 		loadingMsg:   "Loading playlists...",
 		playlistList: playlistList,
+		selected:     map[string]bool{},
 		trackList:    trackList,
+		destination:  newDestinationForm(),
+		diff:         newDiffViewer(),
+		export:       newBulkExport(),
 		help:         help.New(),
 		keys:         newKeyMap(),
 	}
 }
 
-// Init initializes the TUI by fetching playlists from Spotify.
+// Init initializes the TUI, fetching Spotify playlists right away when there is no source to choose.
 func (m *Model) Init() tea.Cmd {
+	if m.view == SourceView {
+		return m.spinner.Tick
+	}
 	return tea.Batch(m.fetchPlaylists(), m.spinner.Tick)
 }
 
@@ -101,6 +140,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleProgressUpdate(appMsg)
 		case MsgTransferComplete:
 			return m.handleTransferComplete(appMsg)
+		case MsgDiffTargetsFetched:
+			return m.handleDiffTargetsFetched(appMsg)
+		case MsgDiffComplete:
+			return m.handleDiffComplete(appMsg)
+		case MsgPushComplete:
+			return m.handlePushComplete(appMsg)
+		case MsgExportProgress:
+			return m.handleExportProgress(appMsg)
+		case MsgExportComplete:
+			return m.handleExportComplete(appMsg)
 		}
 	}
 
@@ -116,6 +165,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	m.width = msg.Width
 	m.height = msg.Height
+	m.stats.bar.Width = barWidth(msg.Width)
+	m.export.bar.Width = barWidth(msg.Width)
+	m.sourceList.SetSize(msg.Width-4, msg.Height-8)
+	m.diff.setSize(msg.Width, msg.Height)
 	if m.playlistList.Width() == 0 {
 		m.playlistList.SetSize(msg.Width-4, msg.Height-8)
 	}
@@ -131,16 +184,26 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if msg.String() == "q" || msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
+	case SourceView:
+		return m.handleSourceKeys(msg)
 	case PlaylistListView:
 		return m.handlePlaylistListKeys(msg)
 	case TrackListView:
 		return m.handleTrackListKeys(msg)
 	case ConfirmView:
 		return m.handleConfirmKeys(msg)
+	case TransferView:
+		return m.handleTransferKeys(msg)
 	case ResultView:
 		return m.handleResultKeys(msg)
 	case AuthErrorView:
 		return m.handleAuthErrorKeys(msg)
+	case QueueView:
+		return m.handleQueueKeys(msg)
+	case DiffView:
+		return m.handleDiffKeys(msg)
+	case ExportView:
+		return m.handleExportKeys(msg)
 	}
 	return m, nil
 }
@@ -165,7 +228,7 @@ func (m *Model) handlePlaylistsFetched(msg Msg) (tea.Model, tea.Cmd) {
 	m.playlists = data.playlists
 	items := make([]list.Item, len(data.playlists))
 	for i, pl := range data.playlists {
-		items[i] = playlistItem{playlist: pl}
+		items[i] = playlistItem{playlist: pl, selected: m.selected[pl.ID]}
 	}
 	m.playlistList.SetItems(items)
 	if m.width > 0 && m.height > 0 {
@@ -210,6 +273,7 @@ func (m *Model) handleTracksFetched(msg Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) handleProgressUpdate(msg Msg) (tea.Model, tea.Cmd) {
 	m.progress = msg.data.(tasks.ProgressUpdate)
+	m.stats.observe(m.progress)
 	return m, m.waitForProgress()
 }
 
@@ -219,6 +283,19 @@ func (m *Model) handleTransferComplete(msg Msg) (tea.Model, tea.Cmd) {
 		err    error
 	})
 
+	if m.cancelTransfer != nil {
+		m.cancelTransfer()
+		m.cancelTransfer = nil
+	}
+
+	if m.view == QueueView {
+		// Outcomes are kept per queue item so a failed playlist doesn't stop the queue
+		m.result = nil
+		m.err = nil
+		m.progressChan = nil
+		return m.handleQueueComplete(data.result, data.err)
+	}
+
 	m.result = data.result
 	m.err = data.err
 	m.view = ResultView
@@ -236,6 +313,8 @@ func (m *Model) View() string {
 	switch m.view {
 	case LoadingView:
 		return m.renderLoading()
+	case SourceView:
+		return m.renderSourceList()
 	case PlaylistListView:
 		return m.renderPlaylistList()
 	case TrackListView:
@@ -248,15 +327,76 @@ func (m *Model) View() string {
 		return m.renderResult()
 	case AuthErrorView:
 		return m.renderAuthError()
+	case QueueView:
+		return m.renderQueue()
+	case DiffView:
+		return m.renderDiff()
+	case ExportView:
+		return m.renderExport()
 	default:
 		return ""
 	}
 }
 
+// handleSourceKeys loads the playlists of the chosen source service, discarding any previous selection
+func (m *Model) handleSourceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		source, ok := m.sourceList.SelectedItem().(sourceItem)
+		if !ok {
+			return m, nil
+		}
+
+		m.source = source
+		m.playlists = nil
+		m.queue = nil
+		m.playlistList.ResetFilter()
+		m.clearSelection()
+		m.view = LoadingView
+		m.loadingMsg = fmt.Sprintf("Loading %s playlists...", source.service.Name())
+		return m, tea.Batch(m.fetchPlaylists(), m.spinner.Tick)
+	}
+
+	var cmd tea.Cmd
+	m.sourceList, cmd = m.sourceList.Update(msg)
+	return m, cmd
+}
+
 func (m *Model) handlePlaylistListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	// While the filter is being typed every key except ctrl+c belongs to the filter input
+	if m.playlistList.SettingFilter() && msg.String() != "ctrl+c" {
+		m.playlistList, cmd = m.playlistList.Update(msg)
+		return m, cmd
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return m, tea.Quit
+	case " ":
+		return m, m.toggleSelection()
+	case "s":
+		if len(m.sourceList.Items()) > 0 {
+			m.view = SourceView
+			return m, nil
+		}
+	case "d":
+		if m.source.target != nil {
+			return m, m.startDiff()
+		}
+	case "x":
+		m.openExport()
+		return m, nil
+	case "t":
+		if len(m.selected) > 0 {
+			m.buildQueue()
+			m.destination.reset("", "")
+			m.view = ConfirmView
+			return m, nil
+		}
 	case "enter":
 		selected := m.playlistList.SelectedItem()
 		if selected != nil {
@@ -268,7 +408,6 @@ func (m *Model) handlePlaylistListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	var cmd tea.Cmd
 	m.playlistList, cmd = m.playlistList.Update(msg)
 	return m, cmd
 }
@@ -281,6 +420,9 @@ func (m *Model) handleTrackListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.view = PlaylistListView
 		return m, nil
 	case "t":
+		// Rendered from the configured templates; a broken template is reported when the transfer starts
+		destination, _ := m.engine.Destination(m.selectedPlaylist, tasks.TransferOpts{Reverse: m.source.reverse})
+		m.destination.reset(destination.Name, destination.Description)
 		m.view = ConfirmView
 		return m, nil
 	}
@@ -291,13 +433,45 @@ func (m *Model) handleTrackListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.destination.editing {
+		return m.handleDestinationKeys(msg)
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c", "n":
+		if len(m.queue) > 0 {
+			m.queue = nil
+			m.view = PlaylistListView
+			return m, nil
+		}
 		m.view = TrackListView
 		return m, nil
+	case "e":
+		if len(m.queue) == 0 {
+			return m, m.destination.startEditing()
+		}
+	case "p":
+		m.destination.public = !m.destination.public
+		return m, nil
 	case "y":
+		if len(m.queue) > 0 {
+			return m, m.startQueue()
+		}
 		m.view = TransferView
-		return m, m.startTransfer()
+		return m, m.startTransfer(m.selectedPlaylist.Playlist.ID, m.destination.opts(false))
+	}
+	return m, nil
+}
+
+// handleTransferKeys cancels the running transfer. The engine stops after the current track search and
+// the result view is shown once it returns.
+func (m *Model) handleTransferKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "c", "esc", "ctrl+c":
+		if m.cancelTransfer != nil && !m.cancelling {
+			m.cancelling = true
+			m.cancelTransfer()
+		}
 	}
 	return m, nil
 }
@@ -311,6 +485,7 @@ func (m *Model) handleResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.selectedPlaylist = nil
 		m.result = nil
 		m.err = nil
+		m.cancelling = false
 		return m, nil
 	}
 	return m, nil
@@ -355,36 +530,50 @@ func (m *Model) isAuthError(err error) bool {
 func (m *Model) updateLists(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch m.view {
+	case SourceView:
+		m.sourceList, cmd = m.sourceList.Update(msg)
 	case PlaylistListView:
 		m.playlistList, cmd = m.playlistList.Update(msg)
 	case TrackListView:
 		m.trackList, cmd = m.trackList.Update(msg)
+	case DiffView:
+		if m.diff.picking {
+			m.diff.targets, cmd = m.diff.targets.Update(msg)
+		}
 	}
 	return m, cmd
 }
 
 func (m *Model) fetchPlaylists() tea.Cmd {
 	return func() tea.Msg {
-		playlists, err := m.spotify.GetPlaylists(m.ctx)
+		playlists, err := m.source.service.GetPlaylists(m.ctx)
 		return playlistsFetchedMsg(playlists, err)
 	}
 }
 
 func (m *Model) fetchTracks(playlistID string) tea.Cmd {
 	return func() tea.Msg {
-		playlist, err := m.spotify.ExportPlaylist(m.ctx, playlistID)
+		playlist, err := m.source.service.ExportPlaylist(m.ctx, playlistID)
 		return tracksFetchedMsg(playlist, err)
 	}
 }
 
-func (m *Model) startTransfer() tea.Cmd {
-	m.progressChan = make(chan tasks.ProgressUpdate, 50)
+func (m *Model) startTransfer(playlistID string, opts tasks.TransferOpts) tea.Cmd {
+	progress := make(chan tasks.ProgressUpdate, 50)
+	m.progressChan = progress
+	m.progress = tasks.ProgressUpdate{}
+	m.stats = newTransferStats(m.width)
+	m.cancelling = false
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelTransfer = cancel
+	opts.Reverse = m.source.reverse
 
 	go func() {
-		result, err := m.engine.Run(m.ctx, m.selectedPlaylist.Playlist.ID, m.progressChan)
+		result, err := m.engine.RunWithOpts(ctx, playlistID, opts, progress)
 		m.result = result
 		m.err = err
-		close(m.progressChan)
+		close(progress)
 	}()
 
 	return m.waitForProgress()
@@ -409,26 +598,64 @@ func (m *Model) renderLoading() string {
 	return fmt.Sprintf("\n\n  %s %s\n\n%s", m.spinner.View(), m.loadingMsg, helpView)
 }
 
+func (m *Model) renderSourceList() string {
+	helpView := m.help.ShortHelpView([]key.Binding{m.keys.enter, m.keys.quit})
+	return fmt.Sprintf("%s\n\n%s", m.sourceList.View(), helpView)
+}
+
 func (m *Model) renderPlaylistList() string {
-	helpKeys := []key.Binding{m.keys.enter, m.keys.quit}
+	helpKeys := []key.Binding{m.keys.enter, m.keys.toggle}
+	if len(m.selected) > 0 {
+		helpKeys = append(helpKeys, m.keys.transfer)
+	}
+	helpKeys = append(helpKeys, m.keys.filter)
+	helpKeys = append(helpKeys, m.keys.export)
+	if m.source.target != nil {
+		helpKeys = append(helpKeys, m.keys.diff)
+	}
+	if len(m.sourceList.Items()) > 0 {
+		helpKeys = append(helpKeys, m.keys.source)
+	}
+	helpKeys = append(helpKeys, m.keys.quit)
 	helpView := m.help.ShortHelpView(helpKeys)
 	return fmt.Sprintf("%s\n\n%s", m.playlistList.View(), helpView)
 }
 
 func (m *Model) renderTrackList() string {
-	transferKey := key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "transfer"))
-	helpKeys := []key.Binding{transferKey, m.keys.back, m.keys.quit}
+	helpKeys := []key.Binding{m.keys.transfer, m.keys.back, m.keys.quit}
 	helpView := m.help.ShortHelpView(helpKeys)
 	return fmt.Sprintf("%s\n\n%s", m.trackList.View(), helpView)
 }
 
 func (m *Model) renderConfirm() string {
-	title := styles.title.Render(fmt.Sprintf("Transfer '%s' to YouTube Music?", m.selectedPlaylist.Playlist.Name))
+	if len(m.queue) > 0 {
+		return m.renderQueueConfirm()
+	}
+
+	title := styles.title.Render(fmt.Sprintf("Transfer '%s' to %s?", m.selectedPlaylist.Playlist.Name, m.source.dest))
 	info := fmt.Sprintf("\nPlaylist: %s\nTracks: %d\n", m.selectedPlaylist.Playlist.Name, len(m.selectedPlaylist.Tracks))
+	destination := m.renderDestination(false)
+	if m.destination.editing {
+		return fmt.Sprintf("%s\n%s%s", title, info, destination)
+	}
+
+	helpKeys := []key.Binding{m.keys.yes, m.keys.no, m.keys.edit, m.keys.public, m.keys.quit}
+	helpView := m.help.ShortHelpView(helpKeys)
+	return fmt.Sprintf("%s\n%s%s\n%s", title, info, destination, helpView)
+}
+
+func (m *Model) renderQueueConfirm() string {
+	title := styles.title.Render(fmt.Sprintf("Transfer %d playlists to %s?", len(m.queue), m.source.dest))
 
-	helpKeys := []key.Binding{m.keys.yes, m.keys.no, m.keys.quit}
+	var info strings.Builder
+	info.WriteString("\nPlaylists are transferred one at a time:\n")
+	for _, item := range m.queue {
+		info.WriteString(fmt.Sprintf("  • %s (%d tracks)\n", item.playlist.Name, item.playlist.TrackCount))
+	}
+
+	helpKeys := []key.Binding{m.keys.yes, m.keys.no, m.keys.public, m.keys.quit}
 	helpView := m.help.ShortHelpView(helpKeys)
-	return fmt.Sprintf("%s\n%s\n%s", title, info, helpView)
+	return fmt.Sprintf("%s\n%s%s\n%s", title, info.String(), m.renderDestination(true), helpView)
 }
 
 func (m *Model) renderTransfer() string {
@@ -439,17 +666,28 @@ func (m *Model) renderTransfer() string {
 	case tasks.FetchSource:
 		phase = "Fetching source playlist..."
 	case tasks.SearchTracks:
-		phase = fmt.Sprintf("Searching tracks (%d/%d)", m.progress.Step, m.progress.Total)
+		phase = fmt.Sprintf("Searching tracks on %s...", m.source.dest)
 	case tasks.CreatePlaylist:
-		phase = "Creating playlist on YouTube Music..."
+		phase = fmt.Sprintf("Creating playlist on %s...", m.source.dest)
+	case tasks.RateLimited:
+		phase = styles.warn.Render("Paused while rate limited...")
 	default:
-		phase = "Processing..."
+		phase = m.progress.Phase.Description() + "..."
+	}
+
+	if m.cancelling {
+		return fmt.Sprintf("%s\n\n%s\n%s\n\n%s", title, phase, m.stats.View(), styles.warn.Render("Cancelling after the current track..."))
 	}
 
-	return fmt.Sprintf("%s\n\n%s\n%s", title, phase, m.progress.Message)
+	helpView := m.help.ShortHelpView([]key.Binding{m.keys.cancel})
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s\n\n%s", title, phase, m.stats.View(), m.progress.Message, helpView)
 }
 
 func (m *Model) renderResult() string {
+	if errors.Is(m.err, context.Canceled) {
+		return m.renderCancelled()
+	}
+
 	if m.err != nil {
 		return styles.err.Render(fmt.Sprintf("Transfer failed: %v\n\nPress r to retry, q to quit", m.err))
 	}
@@ -466,7 +704,16 @@ func (m *Model) renderResult() string {
 		failed = fmt.Sprintf("\n\n%s", styles.warn.Render(fmt.Sprintf("Failed to match %d tracks:", m.result.FailedCount)))
 		for _, match := range m.result.TrackMatches {
 			if match.Error != nil {
-				failed += fmt.Sprintf("\n  • %s - %s", match.Original.Artist, match.Original.Title)
+				failed += fmt.Sprintf("\n  • %s - %s", match.Original.Artist(), match.Original.Title)
+			}
+		}
+	}
+
+	if m.result.SkippedCount > 0 {
+		failed += fmt.Sprintf("\n\n%s", styles.warn.Render(fmt.Sprintf("Skipped %d unmatchable tracks:", m.result.SkippedCount)))
+		for _, match := range m.result.TrackMatches {
+			if match.Skipped != "" {
+				failed += fmt.Sprintf("\n  • %s - %s (%s)", match.Original.Artist(), match.Original.Title, match.Skipped)
 			}
 		}
 	}
@@ -476,6 +723,27 @@ func (m *Model) renderResult() string {
 	return fmt.Sprintf("%s\n%s%s\n\n%s", title, info, failed, helpView)
 }
 
+// renderCancelled shows how far a cancelled transfer got. The engine stops before creating the
+// destination playlist, so nothing needs to be cleaned up on the destination service.
+func (m *Model) renderCancelled() string {
+	title := styles.warn.Render("✗ Transfer Cancelled")
+
+	info := "\nCancelled before any tracks were searched."
+	if m.result != nil && m.result.TotalTracks > 0 {
+		info = fmt.Sprintf("\nSearched %d of %d tracks (%d matched) before cancelling.",
+			len(m.result.TrackMatches), m.result.TotalTracks, m.result.SuccessCount)
+	}
+	if m.result != nil && m.result.DestPlaylist != nil {
+		info += fmt.Sprintf("\nPlaylist '%s' was already created on %s.", m.result.DestPlaylist.Name, m.source.dest)
+	} else {
+		info += fmt.Sprintf("\nNo playlist was created on %s.", m.source.dest)
+	}
+
+	helpKeys := []key.Binding{m.keys.restart, m.keys.quit}
+	helpView := m.help.ShortHelpView(helpKeys)
+	return fmt.Sprintf("%s\n%s\n\n%s", title, info, helpView)
+}
+
 func (m *Model) renderAuthError() string {
 	title := styles.err.Render("⚠ Authentication Error")
 
@@ -483,21 +751,26 @@ func (m *Model) renderAuthError() string {
 	if m.authErrorMsg != "" {
 		message = fmt.Sprintf("\n%s\n", m.authErrorMsg)
 	} else {
-		message = "\nYour Spotify authentication has expired.\n"
+		message = fmt.Sprintf("\nYour %s authentication has expired.\n", m.source.service.Name())
+	}
+
+	command, flow := "ytx spotify auth", "browser authentication flow"
+	if m.source.reverse {
+		command, flow = "ytx setup youtube --curl-file <file>", "DevTools steps to copy your browser headers"
 	}
 
-	instructions := `
+	instructions := fmt.Sprintf(`
 To fix this issue:
 1. Exit the TUI (press 'q')
-2. Run: ytx spotify auth
-3. Follow the browser authentication flow
+2. Run: %s
+3. Follow the %s
 4. Re-launch the TUI
 
 Alternatively:
 - Press 'r' to retry (if token was auto-refreshed)
 - Press 'esc' to go back
 - Press 'q' to quit
-`
+`, command, flow)
 
 	retryKey := key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry"))
 	backKey := key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back"))
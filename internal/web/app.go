@@ -0,0 +1,497 @@
+package web
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/repositories"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// MinSessionSecret is the minimum length in bytes of [AppOpts.SessionSecret]
+	MinSessionSecret = 32
+	// minPasswordLength is the shortest password accepted at registration
+	minPasswordLength = 8
+	// maxPageSize caps the limit query parameter of list endpoints
+	maxPageSize = 200
+)
+
+// AppOpts configures an [App].
+type AppOpts struct {
+	DB            *sql.DB               // Migrated database holding users, credentials, playlists, and migrations
+	SessionSecret []byte                // Key signing session cookies; at least [MinSessionSecret] bytes
+	Spotify       services.OAuthService // Spotify OAuth client for connecting accounts; nil disables /auth/spotify
+	BaseURL       string                // Public URL of the server, used to build the OAuth redirect URI
+	SecureCookies bool                  // Mark cookies Secure, for servers behind HTTPS
+	Jobs          JobQueue              // Job queue behind the /jobs routes; nil answers 503
+	Origins       []string              // Origins besides the server's own allowed to open job sockets
+}
+
+// App is the multi-user web server: accounts log in with a password and keep their own service credentials, and
+// every playlist and migration query is scoped to the logged in user. It implements [server.Handler].
+type App struct {
+	users       *repositories.UserRepository
+	credentials *repositories.CredentialRepository
+	playlists   *repositories.PlaylistRepository
+	migrations  *repositories.MigrationRepository
+	cookies     cookieJar
+	spotify     services.OAuthService
+	baseURL     string
+	jobs        JobQueue
+	origins     []string
+	mux         *http.ServeMux
+}
+
+// NewApp creates an [App] and registers its routes.
+func NewApp(opts AppOpts) (*App, error) {
+	if opts.DB == nil {
+		return nil, fmt.Errorf("%w: database not initialized", shared.ErrServiceUnavailable)
+	}
+	if len(opts.SessionSecret) < MinSessionSecret {
+		return nil, fmt.Errorf("%w: session secret must be at least %d bytes", shared.ErrInvalidArgument, MinSessionSecret)
+	}
+
+	credentials, err := repositories.NewCredentialRepository(opts.DB, opts.SessionSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &App{
+		users:       repositories.NewUserRepository(opts.DB),
+		credentials: credentials,
+		playlists:   repositories.NewPlaylistRepository(opts.DB),
+		migrations:  repositories.NewMigrationRepository(opts.DB),
+		cookies:     cookieJar{secret: opts.SessionSecret, secure: opts.SecureCookies},
+		spotify:     opts.Spotify,
+		baseURL:     strings.TrimRight(opts.BaseURL, "/"),
+		jobs:        opts.Jobs,
+		origins:     opts.Origins,
+		mux:         http.NewServeMux(),
+	}
+
+	a.mux.HandleFunc("POST /register", a.register)
+	a.mux.HandleFunc("POST /login", a.login)
+	a.mux.HandleFunc("POST /logout", a.logout)
+	a.mux.HandleFunc("GET /me", a.authed(a.me))
+	a.mux.HandleFunc("GET /playlists", a.authed(a.listPlaylists))
+	a.mux.HandleFunc("GET /migrations", a.authed(a.listMigrations))
+	a.mux.HandleFunc("PUT /credentials/youtube", a.authed(a.saveYouTubeAuth))
+	a.mux.HandleFunc("DELETE /credentials/{service}", a.authed(a.deleteCredentials))
+	a.mux.HandleFunc("POST /jobs", a.authed(a.submitJob))
+	a.mux.HandleFunc("GET /jobs", a.authed(a.listJobs))
+	a.mux.HandleFunc("GET /jobs/{id}", a.authed(a.getJob))
+	a.mux.HandleFunc("DELETE /jobs/{id}", a.authed(a.cancelJob))
+	a.mux.HandleFunc("GET /jobs/{id}/events", a.authed(a.jobEvents))
+	a.mux.HandleFunc("GET /jobs/{id}/ws", a.authed(a.jobSocket))
+	a.mux.HandleFunc("GET /phases", a.listPhases)
+	a.mux.HandleFunc("GET /auth/spotify", a.authed(a.spotifyLogin))
+	a.mux.HandleFunc("GET /auth/spotify/callback", a.authed(a.spotifyCallback))
+	return a, nil
+}
+
+// Routes returns the path patterns the app serves.
+func (a *App) Routes() []string {
+	return []string{"/register", "/login", "/logout", "/me", "/playlists", "/migrations", "/credentials/", "/jobs", "/jobs/", "/phases", "/auth/spotify", "/auth/spotify/callback"}
+}
+
+// ServeHTTP dispatches the request to the app's handlers by method and path.
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// HasSession reports whether r carries a valid session cookie, which the server only issues at login. The serve
+// command passes it to [server.RequireAPIKeyOr], so browsers reach the app's routes with the cookie alone.
+func (a *App) HasSession(r *http.Request) bool {
+	_, ok := a.cookies.get(r, sessionCookie)
+	return ok
+}
+
+// userHandler handles a request made by a logged in user
+type userHandler func(w http.ResponseWriter, r *http.Request, user *models.User)
+
+// authed resolves the session's user for h, answering 401 without a valid session
+func (a *App) authed(h userHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := a.cookies.get(r, sessionCookie)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "not logged in")
+			return
+		}
+		user, err := a.users.Get(r.Context(), userID)
+		if err != nil {
+			a.cookies.clear(w, sessionCookie)
+			writeError(w, http.StatusUnauthorized, "not logged in")
+			return
+		}
+		h(w, r, user)
+	}
+}
+
+// dummyPasswordHash is checked in place of the hash of an account that does not exist or has no password, so
+// failing such a login takes as long as failing on a wrong password
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := shared.HashPassword("ytx dummy password")
+	return hash
+})
+
+type credentialsRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type userResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Services  []string  `json:"services"`
+}
+
+func (a *App) register(w http.ResponseWriter, r *http.Request) {
+	var req credentialsRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	email := strings.TrimSpace(req.Email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		writeError(w, http.StatusBadRequest, "a valid email is required")
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("password must be at least %d characters", minPasswordLength))
+		return
+	}
+
+	if _, err := a.users.GetByEmail(r.Context(), email); err == nil {
+		writeError(w, http.StatusConflict, "email already registered")
+		return
+	} else if !errors.Is(err, shared.ErrRecordNotFound) {
+		writeErr(w, err)
+		return
+	}
+
+	hash, err := shared.HashPassword(req.Password)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	user := models.NewUser(0, email, strings.TrimSpace(req.Name))
+	if err := a.users.CreateWithPassword(r.Context(), user, hash); err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	a.cookies.set(w, sessionCookie, user.ID(), sessionTTL)
+	writeJSON(w, http.StatusCreated, userResponse{ID: user.ID(), Email: user.Email(), Name: user.Name(), CreatedAt: user.CreatedAt(), Services: []string{}})
+}
+
+func (a *App) login(w http.ResponseWriter, r *http.Request) {
+	var req credentialsRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	// Unknown emails and wrong passwords get the same answer after the same work, a password check against the
+	// dummy hash standing in for a missing one, so logins cannot probe for accounts by what or when they answer
+	user, err := a.users.GetByEmail(r.Context(), strings.TrimSpace(req.Email))
+	if err != nil && !errors.Is(err, shared.ErrRecordNotFound) {
+		writeErr(w, err)
+		return
+	}
+	var hash string
+	if user != nil {
+		if hash, err = a.users.PasswordHash(r.Context(), user.ID()); err != nil {
+			writeErr(w, err)
+			return
+		}
+	}
+	if hash == "" {
+		shared.VerifyPassword(dummyPasswordHash(), req.Password)
+		writeError(w, http.StatusUnauthorized, "invalid email or password")
+		return
+	}
+	if !shared.VerifyPassword(hash, req.Password) {
+		writeError(w, http.StatusUnauthorized, "invalid email or password")
+		return
+	}
+
+	a.cookies.set(w, sessionCookie, user.ID(), sessionTTL)
+	a.me(w, r, user)
+}
+
+func (a *App) logout(w http.ResponseWriter, r *http.Request) {
+	a.cookies.clear(w, sessionCookie)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *App) me(w http.ResponseWriter, r *http.Request, user *models.User) {
+	connected, err := a.credentials.Services(r.Context(), user.ID())
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	if connected == nil {
+		connected = []string{}
+	}
+	writeJSON(w, http.StatusOK, userResponse{ID: user.ID(), Email: user.Email(), Name: user.Name(), CreatedAt: user.CreatedAt(), Services: connected})
+}
+
+type playlistResponse struct {
+	ID          string    `json:"id"`
+	Service     string    `json:"service"`
+	ServiceID   string    `json:"service_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	TrackCount  int       `json:"track_count"`
+	Public      bool      `json:"public"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (a *App) listPlaylists(w http.ResponseWriter, r *http.Request, user *models.User) {
+	opts, ok := listOpts(w, r)
+	if !ok {
+		return
+	}
+	criteria := map[string]any{"user_id": user.ID(), "service": r.URL.Query().Get("service")}
+
+	playlists, err := a.playlists.List(r.Context(), criteria, opts)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	total, err := a.playlists.Count(r.Context(), criteria)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	items := make([]playlistResponse, len(playlists))
+	for i, p := range playlists {
+		items[i] = playlistResponse{
+			ID: p.ID(), Service: p.Service(), ServiceID: p.ServiceID(), Name: p.Name(), Description: p.Description(),
+			TrackCount: p.TrackCount(), Public: p.Public(), UpdatedAt: p.UpdatedAt(),
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"playlists": items, "total": total})
+}
+
+type migrationResponse struct {
+	ID               string     `json:"id"`
+	SourceService    string     `json:"source_service"`
+	SourcePlaylistID string     `json:"source_playlist_id"`
+	TargetService    string     `json:"target_service"`
+	TargetPlaylistID string     `json:"target_playlist_id"`
+	Status           string     `json:"status"`
+	TracksTotal      int        `json:"tracks_total"`
+	TracksMigrated   int        `json:"tracks_migrated"`
+	TracksFailed     int        `json:"tracks_failed"`
+	ErrorMessage     string     `json:"error_message,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+}
+
+func (a *App) listMigrations(w http.ResponseWriter, r *http.Request, user *models.User) {
+	opts, ok := listOpts(w, r)
+	if !ok {
+		return
+	}
+	criteria := map[string]any{"user_id": user.ID(), "status": r.URL.Query().Get("status")}
+
+	jobs, err := a.migrations.List(r.Context(), criteria, opts)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	total, err := a.migrations.Count(r.Context(), criteria)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	items := make([]migrationResponse, len(jobs))
+	for i, m := range jobs {
+		items[i] = migrationResponse{
+			ID: m.ID(), SourceService: m.SourceService(), SourcePlaylistID: m.SourcePlaylistID(),
+			TargetService: m.TargetService(), TargetPlaylistID: m.TargetPlaylistID(), Status: m.Status(),
+			TracksTotal: m.TracksTotal(), TracksMigrated: m.TracksMigrated(), TracksFailed: m.TracksFailed(),
+			ErrorMessage: m.ErrorMessage(), CreatedAt: m.CreatedAt(), CompletedAt: m.CompletedAt(),
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"migrations": items, "total": total})
+}
+
+// saveYouTubeAuth stores the user's YouTube Music auth file, uploaded or pasted as the request body: the browser
+// headers of browser.json or the tokens of oauth.json. The contents are kept, never a path on the server.
+func (a *App) saveYouTubeAuth(w http.ResponseWriter, r *http.Request, user *models.User) {
+	var auth map[string]any
+	if !readJSON(w, r, &auth) {
+		return
+	}
+	if !isYouTubeAuth(auth) {
+		writeError(w, http.StatusBadRequest, "body must be the contents of browser.json (with a cookie header) or oauth.json (with a refresh_token)")
+		return
+	}
+	headers, err := json.Marshal(auth)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	if err := a.credentials.SaveAuthHeaders(r.Context(), user.ID(), "youtube", headers); err != nil {
+		writeErr(w, err)
+		return
+	}
+	a.me(w, r, user)
+}
+
+// isYouTubeAuth reports whether auth looks like a YouTube Music auth file: browser headers carrying the session
+// cookie, or OAuth tokens
+func isYouTubeAuth(auth map[string]any) bool {
+	for key, value := range auth {
+		if s, ok := value.(string); ok && s != "" && (strings.EqualFold(key, "cookie") || key == "refresh_token") {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) deleteCredentials(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if err := a.credentials.Delete(r.Context(), user.ID(), r.PathValue("service")); err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// spotifyLogin starts connecting the user's Spotify account, keeping the OAuth state and PKCE verifier in a
+// short-lived signed cookie until the callback
+func (a *App) spotifyLogin(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.spotify == nil {
+		writeError(w, http.StatusServiceUnavailable, "spotify is not configured")
+		return
+	}
+	state, err := shared.GenerateState()
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	opts := []oauth2.AuthCodeOption{oauth2.SetAuthURLParam("redirect_uri", a.spotifyRedirectURI())}
+	var verifier string
+	if a.spotify.UsesPKCE() {
+		pkce := services.NewPKCE()
+		verifier = pkce.Verifier
+		opts = append(opts, pkce.AuthURLOption())
+	}
+
+	a.cookies.set(w, oauthCookie, state+":"+verifier, oauthTTL)
+	http.Redirect(w, r, a.spotify.GetAuthURL(state, opts...), http.StatusFound)
+}
+
+// spotifyCallback completes the OAuth flow started by spotifyLogin and stores the token for the user
+func (a *App) spotifyCallback(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.spotify == nil {
+		writeError(w, http.StatusServiceUnavailable, "spotify is not configured")
+		return
+	}
+	value, ok := a.cookies.get(r, oauthCookie)
+	a.cookies.clear(w, oauthCookie)
+	state, verifier, _ := strings.Cut(value, ":")
+	if !ok || state == "" || r.URL.Query().Get("state") != state {
+		writeError(w, http.StatusBadRequest, "invalid or expired state parameter")
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("authorization failed: %s", r.URL.Query().Get("error")))
+		return
+	}
+
+	opts := []oauth2.AuthCodeOption{oauth2.SetAuthURLParam("redirect_uri", a.spotifyRedirectURI())}
+	if verifier != "" {
+		opts = append(opts, services.PKCE{Verifier: verifier}.ExchangeOption())
+	}
+	token, err := a.spotify.GetOAuthConfig().Exchange(r.Context(), code, opts...)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("token exchange failed: %v", err))
+		return
+	}
+	if err := a.credentials.SaveToken(r.Context(), user.ID(), "spotify", token); err != nil {
+		writeErr(w, err)
+		return
+	}
+	a.me(w, r, user)
+}
+
+func (a *App) spotifyRedirectURI() string {
+	return a.baseURL + "/auth/spotify/callback"
+}
+
+// listOpts reads the limit and offset query parameters, answering 400 when they are invalid
+func listOpts(w http.ResponseWriter, r *http.Request) (models.ListOpts, bool) {
+	opts := models.ListOpts{Limit: 50}
+	for name, dst := range map[string]*int{"limit": &opts.Limit, "offset": &opts.Offset} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%s must be a non-negative integer", name))
+			return opts, false
+		}
+		*dst = n
+	}
+	if opts.Limit == 0 || opts.Limit > maxPageSize {
+		opts.Limit = maxPageSize
+	}
+	return opts, true
+}
+
+// readJSON decodes a JSON request body into v, answering 400 when it is malformed
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeErr answers with the status matching a repository or service error
+func writeErr(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, shared.ErrRecordNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, shared.ErrInvalidArgument), errors.Is(err, shared.ErrInvalidInput):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, shared.ErrServiceUnavailable):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
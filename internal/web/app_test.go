@@ -0,0 +1,647 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/server"
+	"github.com/desertthunder/ytx/internal/services"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+)
+
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	db, err := shared.NewDatabase(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := shared.RunMigrations(db); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	app, err := NewApp(AppOpts{DB: db, SessionSecret: bytes.Repeat([]byte("s"), MinSessionSecret), BaseURL: "http://ytx.test"})
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+	return app
+}
+
+// do sends a request to app with the given cookies and returns the response
+func do(app *App, method, path, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	return rec
+}
+
+// session returns the session cookie set by a response
+func session(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == sessionCookie {
+			return cookie
+		}
+	}
+	t.Fatalf("expected a session cookie, got status %d: %s", rec.Code, rec.Body)
+	return nil
+}
+
+func TestApp(t *testing.T) {
+	app := newTestApp(t)
+
+	alice := session(t, do(app, "POST", "/register", `{"email":"alice@example.com","name":"Alice","password":"wonderland"}`))
+	bob := session(t, do(app, "POST", "/register", `{"email":"bob@example.com","password":"builder99"}`))
+
+	t.Run("registration rules", func(t *testing.T) {
+		for body, want := range map[string]int{
+			`{"email":"alice@example.com","password":"wonderland"}`: http.StatusConflict,
+			`{"email":"not-an-email","password":"wonderland"}`:      http.StatusBadRequest,
+			`{"email":"carol@example.com","password":"short"}`:      http.StatusBadRequest,
+			`not json`: http.StatusBadRequest,
+		} {
+			if rec := do(app, "POST", "/register", body); rec.Code != want {
+				t.Errorf("register %s: status %d, want %d", body, rec.Code, want)
+			}
+		}
+	})
+
+	t.Run("login", func(t *testing.T) {
+		if rec := do(app, "POST", "/login", `{"email":"alice@example.com","password":"wrong-password"}`); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected a wrong password to be refused, got %d", rec.Code)
+		}
+		if rec := do(app, "POST", "/login", `{"email":"nobody@example.com","password":"wonderland"}`); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected an unknown email to be refused, got %d", rec.Code)
+		}
+		if rec := do(app, "POST", "/login", `{"email":"nobody@example.com","password":"ytx dummy password"}`); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected the dummy hash's password to be refused, got %d", rec.Code)
+		}
+
+		rec := do(app, "GET", "/me", "", session(t, do(app, "POST", "/login", `{"email":"alice@example.com","password":"wonderland"}`)))
+		var me userResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &me); err != nil || me.Email != "alice@example.com" || me.Name != "Alice" {
+			t.Errorf("GET /me = %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("unknown emails take as long as wrong passwords", func(t *testing.T) {
+		timeLogin := func(body string) time.Duration {
+			start := time.Now()
+			do(app, "POST", "/login", body)
+			return time.Since(start)
+		}
+		// The first unknown email also derives the dummy hash
+		timeLogin(`{"email":"nobody@example.com","password":"warm-up"}`)
+
+		// Both hash the password once, so an unknown email is not several times faster
+		wrong := timeLogin(`{"email":"alice@example.com","password":"wrong-password"}`)
+		unknown := timeLogin(`{"email":"nobody@example.com","password":"wrong-password"}`)
+		if unknown < wrong/4 {
+			t.Errorf("unknown email answered in %s, a wrong password in %s", unknown, wrong)
+		}
+	})
+
+	t.Run("requires a valid session", func(t *testing.T) {
+		if rec := do(app, "GET", "/playlists", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 without a session, got %d", rec.Code)
+		}
+		forged := &http.Cookie{Name: sessionCookie, Value: strings.Replace(alice.Value, "|", "x|", 1)}
+		if rec := do(app, "GET", "/me", "", forged); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 for a tampered session, got %d", rec.Code)
+		}
+
+		// A value signed for the OAuth state cookie must not pass as a session
+		rec := httptest.NewRecorder()
+		var me userResponse
+		json.Unmarshal(do(app, "GET", "/me", "", alice).Body.Bytes(), &me)
+		app.cookies.set(rec, oauthCookie, me.ID, oauthTTL)
+		renamed := &http.Cookie{Name: sessionCookie, Value: rec.Result().Cookies()[0].Value}
+		if rec := do(app, "GET", "/me", "", renamed); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 for a cookie signed under another name, got %d", rec.Code)
+		}
+	})
+
+	t.Run("scopes playlists to the user", func(t *testing.T) {
+		var me userResponse
+		json.Unmarshal(do(app, "GET", "/me", "", alice).Body.Bytes(), &me)
+
+		for _, playlist := range []*models.PersistedPlaylist{
+			models.NewPersistedPlaylist(0, "spotify", "sp1", me.ID, models.Playlist{Name: "Alice's Mix"}),
+			models.NewPersistedPlaylist(0, "spotify", "sp2", "someone-else", models.Playlist{Name: "Other Mix"}),
+		} {
+			if err := app.playlists.Create(t.Context(), playlist); err != nil {
+				t.Fatalf("failed to create playlist: %v", err)
+			}
+		}
+
+		var got struct {
+			Playlists []playlistResponse `json:"playlists"`
+			Total     int                `json:"total"`
+		}
+		json.Unmarshal(do(app, "GET", "/playlists", "", alice).Body.Bytes(), &got)
+		if got.Total != 1 || len(got.Playlists) != 1 || got.Playlists[0].Name != "Alice's Mix" {
+			t.Errorf("expected only Alice's playlist, got %+v", got)
+		}
+		json.Unmarshal(do(app, "GET", "/playlists", "", bob).Body.Bytes(), &got)
+		if got.Total != 0 {
+			t.Errorf("expected Bob to see no playlists, got %+v", got)
+		}
+		if rec := do(app, "GET", "/migrations?limit=-1", "", bob); rec.Code != http.StatusBadRequest {
+			t.Errorf("expected an invalid limit to be refused, got %d", rec.Code)
+		}
+	})
+
+	t.Run("keeps credentials per user", func(t *testing.T) {
+		for _, body := range []string{`{}`, `{"accept":"*/*"}`, `not json`} {
+			if rec := do(app, "PUT", "/credentials/youtube", body, bob); rec.Code != http.StatusBadRequest {
+				t.Errorf("expected %s to be refused as an auth file, got %d", body, rec.Code)
+			}
+		}
+		if rec := do(app, "PUT", "/credentials/youtube", `{"Cookie":"SID=bob","accept":"*/*"}`, bob); rec.Code != http.StatusOK {
+			t.Fatalf("PUT /credentials/youtube = %d %s", rec.Code, rec.Body)
+		}
+
+		var me userResponse
+		json.Unmarshal(do(app, "GET", "/me", "", bob).Body.Bytes(), &me)
+		if len(me.Services) != 1 || me.Services[0] != "youtube" {
+			t.Errorf("expected Bob to have YouTube connected, got %v", me.Services)
+		}
+		json.Unmarshal(do(app, "GET", "/me", "", alice).Body.Bytes(), &me)
+		if len(me.Services) != 0 {
+			t.Errorf("expected Alice to have nothing connected, got %v", me.Services)
+		}
+	})
+
+	t.Run("never reads another user's auth file", func(t *testing.T) {
+		// Bob's auth file on the server, which Alice tries to claim by its path
+		authFile := t.TempDir() + "/headers.json"
+		if err := os.WriteFile(authFile, []byte(`{"cookie":"SID=bob"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if rec := do(app, "PUT", "/credentials/youtube", `{"auth_file":"`+authFile+`"}`, alice); rec.Code != http.StatusBadRequest {
+			t.Errorf("expected a server path to be refused, got %d %s", rec.Code, rec.Body)
+		}
+
+		var me userResponse
+		json.Unmarshal(do(app, "GET", "/me", "", alice).Body.Bytes(), &me)
+		if _, err := app.credentials.AuthHeaders(t.Context(), me.ID, "youtube"); !errors.Is(err, shared.ErrRecordNotFound) {
+			t.Errorf("expected Alice to have no YouTube credentials, got %v", err)
+		}
+		json.Unmarshal(do(app, "GET", "/me", "", bob).Body.Bytes(), &me)
+		headers, err := app.credentials.AuthHeaders(t.Context(), me.ID, "youtube")
+		if err != nil || !strings.Contains(string(headers), "SID=bob") {
+			t.Errorf("expected Bob's uploaded headers, got %s, %v", headers, err)
+		}
+	})
+
+	t.Run("lists progress phases", func(t *testing.T) {
+		rec := do(app, "GET", "/phases", "")
+		var phases []struct{ Code, Description string }
+		if err := json.Unmarshal(rec.Body.Bytes(), &phases); rec.Code != http.StatusOK || err != nil {
+			t.Fatalf("expected 200 with phases, got %d: %s", rec.Code, rec.Body)
+		}
+		if len(phases) != len(tasks.Phases()) || phases[0].Code != "fetch_source" || phases[0].Description == "" {
+			t.Errorf("unexpected phases %+v", phases)
+		}
+	})
+
+	t.Run("spotify needs configuring", func(t *testing.T) {
+		if rec := do(app, "GET", "/auth/spotify", "", alice); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected 503 without a Spotify client, got %d", rec.Code)
+		}
+		callback := "/auth/spotify/callback?" + url.Values{"state": {"x"}, "code": {"y"}}.Encode()
+		if rec := do(app, "GET", callback, "", alice); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected 503 without a Spotify client, got %d", rec.Code)
+		}
+	})
+}
+
+func TestAPIKey(t *testing.T) {
+	router := server.NewBasicRouter()
+	router.Use(server.RequireAPIKey("s3cret", server.DefaultAPIKeyExemptions...))
+	router.Handler(newTestApp(t))
+
+	// Requests past the key check reach the app, which refuses them for the missing session instead
+	for _, tc := range []struct {
+		name     string
+		path     string
+		header   http.Header
+		rejected bool
+		want     int
+	}{
+		{"no key", "/me", nil, true, http.StatusUnauthorized},
+		{"wrong key", "/me", http.Header{server.APIKeyHeader: {"guess"}}, true, http.StatusUnauthorized},
+		{"key header", "/me", http.Header{server.APIKeyHeader: {"s3cret"}}, false, http.StatusUnauthorized},
+		{"bearer token", "/playlists", http.Header{"Authorization": {"Bearer s3cret"}}, false, http.StatusUnauthorized},
+		{"probes are exempt", "/readyz", nil, false, http.StatusNotFound},
+		{"callback is exempt", "/auth/spotify/callback", nil, false, http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			for name, values := range tc.header {
+				req.Header.Set(name, values[0])
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Errorf("status %d, want %d", rec.Code, tc.want)
+			}
+			if rejected := rec.Header().Get("WWW-Authenticate") != ""; rejected != tc.rejected {
+				t.Errorf("rejected for the API key = %v, want %v", rejected, tc.rejected)
+			}
+		})
+	}
+}
+
+func TestAPIKey_Session(t *testing.T) {
+	release := make(chan struct{})
+	q := NewQueue(blockingTransfer(release), QueueOpts{Workers: 1})
+	defer q.Stop()
+	defer close(release)
+
+	app := newTestApp(t)
+	spotify, err := services.NewSpotifyService(map[string]string{"client_id": "client"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.spotify, app.jobs = spotify, q
+
+	router := server.NewBasicRouter()
+	router.Use(server.RequireAPIKeyOr("s3cret", app.HasSession, server.DefaultAPIKeyExemptions...))
+	router.Handler(app)
+	serve := func(method, path, body string, header http.Header, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for name, values := range header {
+			req.Header.Set(name, values[0])
+		}
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Logging in takes the key, and the session cookie it issues stands in for the key afterwards
+	if rec := serve("POST", "/register", `{"email":"alice@example.com","password":"wonderland"}`, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected registering without the key to be refused, got %d", rec.Code)
+	}
+	alice := session(t, serve("POST", "/register", `{"email":"alice@example.com","password":"wonderland"}`, http.Header{server.APIKeyHeader: {"s3cret"}}))
+
+	rec := serve("GET", "/auth/spotify", "", nil, alice)
+	if rec.Code != http.StatusFound || !strings.HasPrefix(rec.Header().Get("Location"), "https://accounts.spotify.com/") {
+		t.Errorf("expected the Spotify login to redirect with only a session cookie, got %d %v", rec.Code, rec.Header())
+	}
+
+	rec = serve("POST", "/jobs", `{"playlist_id":"p1"}`, nil, alice)
+	var job JobInfo
+	if rec.Code != http.StatusAccepted || json.Unmarshal(rec.Body.Bytes(), &job) != nil {
+		t.Fatalf("POST /jobs = %d %s", rec.Code, rec.Body)
+	}
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	events := func(cookies ...*http.Cookie) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/jobs/"+job.ID+"/events", nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := events(alice); resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("expected the event stream with only a session cookie, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	forged := &http.Cookie{Name: sessionCookie, Value: "user|9999999999|forged"}
+	for _, resp := range []*http.Response{events(), events(forged)} {
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("expected the API key required without a valid session, got %d", resp.StatusCode)
+		}
+	}
+}
+
+func TestBrowserHeaders(t *testing.T) {
+	router := server.NewBasicRouter()
+	router.Use(
+		server.SecurityHeaders(server.DefaultContentSecurityPolicy),
+		server.CORS([]string{"https://app.example.com"}),
+		server.RequireAPIKey("s3cret", server.DefaultAPIKeyExemptions...),
+	)
+	router.Handler(newTestApp(t))
+
+	serve := func(method, origin string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/me", nil)
+		req.Header.Set("Origin", origin)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("preflight skips the API key", func(t *testing.T) {
+		rec := serve(http.MethodOptions, "https://app.example.com", "Access-Control-Request-Method", "GET")
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("status %d, want 204", rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("Access-Control-Allow-Origin = %q", got)
+		}
+		if !strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), server.APIKeyHeader) {
+			t.Errorf("expected the API key header to be allowed, got %q", rec.Header().Get("Access-Control-Allow-Headers"))
+		}
+	})
+
+	t.Run("allowed origin gets credentials", func(t *testing.T) {
+		rec := serve(http.MethodGet, "https://app.example.com", server.APIKeyHeader, "s3cret")
+		if rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("expected credentials allowed, got headers %v", rec.Header())
+		}
+	})
+
+	t.Run("other origins are not allowed", func(t *testing.T) {
+		rec := serve(http.MethodOptions, "https://evil.example.com", "Access-Control-Request-Method", "GET")
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("expected no CORS headers, got %q", got)
+		}
+	})
+
+	t.Run("security headers on every response", func(t *testing.T) {
+		rec := serve(http.MethodGet, "https://evil.example.com")
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status %d, want 401", rec.Code)
+		}
+		h := rec.Header()
+		if h.Get("X-Content-Type-Options") != "nosniff" || h.Get("Referrer-Policy") == "" || !strings.Contains(h.Get("Content-Security-Policy"), "frame-ancestors 'none'") {
+			t.Errorf("missing security headers: %v", h)
+		}
+	})
+}
+
+func TestRequestLogging(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs)
+
+	router := server.NewBasicRouter()
+	router.Use(server.AccessLog(logger), server.Recover(logger))
+	router.Handle(http.MethodGet, "/boom", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	router.Handle(http.MethodGet, "/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+	router.Handler(newTestApp(t))
+
+	serve := func(path, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if requestID != "" {
+			req.Header.Set(server.RequestIDHeader, requestID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("logs requests with their ID", func(t *testing.T) {
+		logs.Reset()
+		rec := serve("/ping?token=secret", "trace-123")
+		if got := rec.Header().Get(server.RequestIDHeader); got != "trace-123" {
+			t.Errorf("expected the incoming request ID echoed, got %q", got)
+		}
+		line := logs.String()
+		for _, want := range []string{"method=GET", "path=/ping", "status=200", "request_id=trace-123"} {
+			if !strings.Contains(line, want) {
+				t.Errorf("expected %q in access log %q", want, line)
+			}
+		}
+		if strings.Contains(line, "secret") {
+			t.Errorf("expected the query string left out of %q", line)
+		}
+
+		if got := serve("/ping", "bad id\n").Header().Get(server.RequestIDHeader); got == "" || strings.ContainsAny(got, " \n") {
+			t.Errorf("expected an invalid request ID replaced, got %q", got)
+		}
+	})
+
+	t.Run("recovers panics as JSON errors", func(t *testing.T) {
+		logs.Reset()
+		rec := serve("/boom", "")
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("status %d, want 500", rec.Code)
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["request_id"] != rec.Header().Get(server.RequestIDHeader) {
+			t.Errorf("unexpected error body %s (%v)", rec.Body, err)
+		}
+		if !strings.Contains(logs.String(), "handler panicked") || !strings.Contains(logs.String(), "status=500") {
+			t.Errorf("expected the panic and a 500 access log, got %q", logs.String())
+		}
+	})
+}
+
+func TestProbes(t *testing.T) {
+	router := server.NewBasicRouter()
+	router.Use(server.RequireAPIKey("s3cret"))
+	router.Handler(newTestApp(t))
+
+	srv := server.NewServer(router)
+	srv.AddReadinessCheck("database", func(ctx context.Context) error { return nil })
+	proxyDown := true
+	srv.AddReadinessCheck("proxy", func(ctx context.Context) error {
+		if proxyDown {
+			return errors.New("unreachable")
+		}
+		return nil
+	})
+
+	probe := func(path string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]any
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec.Code, body
+	}
+
+	if code, body := probe("/healthz"); code != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("GET /healthz = %d %v, want 200 without an API key", code, body)
+	}
+
+	code, body := probe("/readyz")
+	checks, _ := body["checks"].(map[string]any)
+	if code != http.StatusServiceUnavailable || checks["database"] != "ok" || checks["proxy"] != "unreachable" {
+		t.Errorf("GET /readyz = %d %v, want 503 naming the failed check", code, body)
+	}
+	proxyDown = false
+	if code, body := probe("/readyz"); code != http.StatusOK || body["status"] != "ready" {
+		t.Errorf("GET /readyz = %d %v, want 200 once every check passes", code, body)
+	}
+
+	// Everything else still goes through the router's middleware
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me", nil))
+	if rec.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("expected /me to require the API key, got %d", rec.Code)
+	}
+}
+
+func TestRouterPatterns(t *testing.T) {
+	router := server.NewBasicRouter()
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		router.HandleFunc(method, "/api/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Method + " " + server.PathParam(r, "id")))
+		})
+	}
+	router.HandleFunc("", "/api/any", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method))
+	})
+
+	for _, tc := range []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/api/jobs/42", http.StatusOK, "GET 42"},
+		{http.MethodDelete, "/api/jobs/42", http.StatusOK, "DELETE 42"},
+		{http.MethodGet, "/api/jobs/a%2Fb", http.StatusOK, "GET a/b"},
+		{http.MethodHead, "/api/jobs/42", http.StatusOK, ""},
+		{http.MethodPost, "/api/jobs/42", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/api/jobs/42/tracks", http.StatusNotFound, ""},
+		{http.MethodPatch, "/api/any", http.StatusOK, "PATCH"},
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		if rec.Code != tc.code || (tc.body != "" && rec.Body.String() != tc.body) {
+			t.Errorf("%s %s = %d %q, want %d %q", tc.method, tc.path, rec.Code, rec.Body, tc.code, tc.body)
+		}
+		if tc.code == http.StatusMethodNotAllowed && !strings.Contains(rec.Header().Get("Allow"), "DELETE") {
+			t.Errorf("expected the allowed methods in the Allow header, got %q", rec.Header().Get("Allow"))
+		}
+	}
+}
+
+// fakeJob is a queue holding a single job, "job-1"; only watching it is supported
+type fakeJob struct {
+	JobQueue
+	updates   chan tasks.ProgressUpdate
+	cancelled chan bool
+	rate      float64
+}
+
+func (j *fakeJob) Watch(ctx context.Context, userID, jobID string) (<-chan tasks.ProgressUpdate, JobControl, error) {
+	if jobID != "job-1" {
+		return nil, nil, fmt.Errorf("%w: job %s", shared.ErrRecordNotFound, jobID)
+	}
+	return j.updates, j, nil
+}
+
+func (j *fakeJob) Cancel() { j.cancelled <- true }
+
+func (j *fakeJob) SetRateLimit(rps float64) error {
+	j.rate = rps
+	return nil
+}
+
+func TestJobSocket(t *testing.T) {
+	job := &fakeJob{updates: make(chan tasks.ProgressUpdate, 1), cancelled: make(chan bool, 1)}
+	app := newTestApp(t)
+	app.jobs = job
+	user := session(t, do(app, "POST", "/register", `{"email":"dj@example.com","password":"turntable"}`))
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	if rec := do(app, "GET", "/jobs/job-2/ws", "", user); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another job, got %d", rec.Code)
+	}
+	app.jobs = nil
+	if rec := do(app, "GET", "/jobs/job-1/ws", "", user); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a job queue, got %d", rec.Code)
+	}
+	app.jobs = job
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/jobs/job-1/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.AddCookie(user)
+	req.Write(conn)
+	br := bufio.NewReader(conn)
+	if resp, err := http.ReadResponse(br, req); err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake failed: %v %v", resp, err)
+	}
+
+	// Server frames are unmasked text frames well under 126 bytes here
+	readMessage := func() socketMessage {
+		t.Helper()
+		header := make([]byte, 2)
+		io.ReadFull(br, header)
+		payload := make([]byte, header[1]&0x7f)
+		io.ReadFull(br, payload)
+		var msg socketMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			t.Fatalf("invalid message %q: %v", payload, err)
+		}
+		return msg
+	}
+	sendControl := func(body string) {
+		frame := []byte{0x81, 0x80 | byte(len(body)), 0, 0, 0, 0} // A zero mask leaves the payload as is
+		conn.Write(append(frame, body...))
+	}
+
+	job.updates <- tasks.ProgressUpdate{Phase: tasks.SearchTracks, Step: 3, Total: 10, Message: "Searching..."}
+	if msg := readMessage(); msg.Type != "progress" || msg.Phase != "search_tracks" || msg.PhaseDescription != "Search tracks" || msg.Step != 3 || msg.Total != 10 {
+		t.Errorf("unexpected progress message %+v", msg)
+	}
+
+	sendControl(`{"type":"rate_limit","requests_per_second":2.5}`)
+	if msg := readMessage(); msg.Type != "ack" || job.rate != 2.5 {
+		t.Errorf("expected the rate limit applied, got %+v (rate %v)", msg, job.rate)
+	}
+	sendControl(`{"type":"pause"}`)
+	if msg := readMessage(); msg.Type != "error" {
+		t.Errorf("expected an unknown control to be refused, got %+v", msg)
+	}
+	sendControl(`{"type":"cancel"}`)
+	if msg := readMessage(); msg.Type != "ack" || !<-job.cancelled {
+		t.Errorf("expected the job cancelled, got %+v", msg)
+	}
+
+	close(job.updates)
+	if msg := readMessage(); msg.Type != "done" {
+		t.Errorf("expected done once the job finishes, got %+v", msg)
+	}
+}
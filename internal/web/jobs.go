@@ -0,0 +1,230 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/server"
+	"github.com/desertthunder/ytx/internal/tasks"
+)
+
+// socketPingInterval keeps idle job sockets open through proxies
+const socketPingInterval = 30 * time.Second
+
+// JobControl steers a running job from the browser.
+type JobControl interface {
+	Cancel()                                      // Cancel stops the job, which then reports its final state
+	SetRateLimit(requestsPerSecond float64) error // SetRateLimit changes how fast the job calls the services
+}
+
+// JobWatcher is what the job queue provides to the web server: the progress updates and controls of a user's job.
+type JobWatcher interface {
+	// Watch returns the progress updates of jobID, closed when the job finishes, and its controls. It fails with
+	// [shared.ErrRecordNotFound] when userID has no such job.
+	Watch(ctx context.Context, userID, jobID string) (<-chan tasks.ProgressUpdate, JobControl, error)
+}
+
+// socketMessage is sent to the browser on a job socket
+type socketMessage struct {
+	Type             string    `json:"type"`            // "progress", "ack", "error", or "done"
+	Phase            string    `json:"phase,omitempty"` // Stable code, listed with descriptions by GET /phases
+	PhaseDescription string    `json:"phase_description,omitempty"`
+	Step             int       `json:"step,omitempty"`
+	Total            int       `json:"total,omitempty"`
+	Message          string    `json:"message,omitempty"`
+	Time             time.Time `json:"time,omitzero"`
+}
+
+// socketControl is received from the browser on a job socket
+type socketControl struct {
+	Type              string  `json:"type"` // "cancel" or "rate_limit"
+	RequestsPerSecond float64 `json:"requests_per_second"`
+}
+
+// submitJob queues a transfer for the user, answering 202 with the queued job
+func (a *App) submitJob(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.jobs == nil {
+		writeError(w, http.StatusServiceUnavailable, "no job queue is running")
+		return
+	}
+	var req TransferRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	req.PlaylistID = strings.TrimSpace(req.PlaylistID)
+
+	job, err := a.jobs.Submit(user.ID(), req)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (a *App) listJobs(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.jobs == nil {
+		writeError(w, http.StatusServiceUnavailable, "no job queue is running")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"jobs": a.jobs.Jobs(user.ID())})
+}
+
+func (a *App) getJob(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.jobs == nil {
+		writeError(w, http.StatusServiceUnavailable, "no job queue is running")
+		return
+	}
+	job, err := a.jobs.Job(user.ID(), server.PathParam(r, "id"))
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// cancelJob cancels a queued or running job; cancelling a finished job does nothing
+func (a *App) cancelJob(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.jobs == nil {
+		writeError(w, http.StatusServiceUnavailable, "no job queue is running")
+		return
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	_, control, err := a.jobs.Watch(ctx, user.ID(), server.PathParam(r, "id"))
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	control.Cancel()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// jobEvents streams a job's progress as server-sent "progress" events, ending with a "done" event carrying the
+// job's final state
+func (a *App) jobEvents(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.jobs == nil {
+		writeError(w, http.StatusServiceUnavailable, "no job queue is running")
+		return
+	}
+	jobID := server.PathParam(r, "id")
+	updates, _, err := a.jobs.Watch(r.Context(), user.ID(), jobID)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	sse, err := server.NewSSEWriter(w, r)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	stop := sse.KeepAlive(server.DefaultKeepAlive)
+	defer stop()
+
+	err = server.Stream(sse, updates, func(update tasks.ProgressUpdate) server.Event {
+		return server.Event{Name: "progress", Data: progressMessage(update)}
+	})
+	if err != nil {
+		return
+	}
+	if job, err := a.jobs.Job(user.ID(), jobID); err == nil {
+		sse.Send("done", job)
+	}
+}
+
+// listPhases lists the codes of progress phases with their descriptions, for clients showing job progress
+func (a *App) listPhases(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, tasks.Phases())
+}
+
+// progressMessage converts a progress update for the browser
+func progressMessage(update tasks.ProgressUpdate) socketMessage {
+	return socketMessage{
+		Type: "progress", Phase: update.Phase.String(), PhaseDescription: update.Phase.Description(),
+		Step: update.Step, Total: update.Total, Message: update.Message, Time: update.Time,
+	}
+}
+
+// jobSocket streams a job's progress over a WebSocket and applies the control messages the browser sends back,
+// for clients that need more than the one-way SSE stream
+func (a *App) jobSocket(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if a.jobs == nil {
+		writeError(w, http.StatusServiceUnavailable, "no job queue is running")
+		return
+	}
+
+	// The job outlives this connection; only watching stops when the socket closes
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	defer cancel()
+	updates, control, err := a.jobs.Watch(ctx, user.ID(), server.PathParam(r, "id"))
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	ws, err := server.UpgradeWebSocket(w, r, a.origins)
+	if err != nil {
+		return
+	}
+	defer ws.Close(server.CloseNormal, "")
+
+	go a.readControls(ws, control, cancel)
+
+	ping := time.NewTicker(socketPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				ws.WriteJSON(socketMessage{Type: "done"})
+				return
+			}
+			err = ws.WriteJSON(progressMessage(update))
+		case <-ping.C:
+			err = ws.Ping()
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readControls applies control messages until the socket closes, then cancels ctx to stop the writer
+func (a *App) readControls(ws *server.WebSocket, control JobControl, cancel context.CancelFunc) {
+	defer cancel()
+	for {
+		var msg socketControl
+		if err := ws.ReadJSON(&msg); err != nil {
+			if errors.Is(err, server.ErrWebSocketClosed) {
+				return
+			}
+			ws.WriteJSON(socketMessage{Type: "error", Message: "invalid control message"})
+			continue
+		}
+
+		switch msg.Type {
+		case "cancel":
+			control.Cancel()
+		case "rate_limit":
+			if msg.RequestsPerSecond <= 0 {
+				ws.WriteJSON(socketMessage{Type: "error", Message: "requests_per_second must be positive"})
+				continue
+			}
+			if err := control.SetRateLimit(msg.RequestsPerSecond); err != nil {
+				ws.WriteJSON(socketMessage{Type: "error", Message: err.Error()})
+				continue
+			}
+		default:
+			ws.WriteJSON(socketMessage{Type: "error", Message: "unknown control message " + msg.Type})
+			continue
+		}
+		ws.WriteJSON(socketMessage{Type: "ack", Message: msg.Type})
+	}
+}
@@ -0,0 +1,418 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultQueueWorkers is the number of transfers a [Queue] runs at once when [QueueOpts.Workers] is unset
+	DefaultQueueWorkers = 2
+	// DefaultQueueCapacity is the number of transfers a [Queue] holds waiting when [QueueOpts.Capacity] is unset
+	DefaultQueueCapacity = 64
+	// DefaultRequestsPerSecond is the rate limit of every job when [QueueOpts.RequestsPerSecond] is unset
+	DefaultRequestsPerSecond = 5
+	// keptFinishedJobs is the number of finished jobs a queue remembers for listing before forgetting the oldest
+	keptFinishedJobs = 200
+	// watcherBufferSize is the number of updates queued for one watcher before further updates are dropped
+	watcherBufferSize = 64
+)
+
+// JobStatus is the state of a queued transfer.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// finished reports whether the job will not change again
+func (s JobStatus) finished() bool {
+	return s == JobCompleted || s == JobFailed || s == JobCancelled
+}
+
+// TransferRequest is a transfer submitted to the job queue.
+type TransferRequest struct {
+	PlaylistID string `json:"playlist_id"`
+	Reverse    bool   `json:"reverse"` // Transfer from YouTube Music to Spotify
+	Public     bool   `json:"public"`
+	Name       string `json:"name,omitempty"` // Destination playlist name template
+
+	Explicit tasks.ExplicitPreference `json:"explicit,omitempty"` // Version preferred when both are found; the server's default when empty
+}
+
+// JobInfo is a snapshot of a queued transfer.
+type JobInfo struct {
+	ID             string          `json:"id"`
+	Status         JobStatus       `json:"status"`
+	Request        TransferRequest `json:"request"`
+	Phase          string          `json:"phase,omitempty"` // Phase of the latest progress update
+	Step           int             `json:"step,omitempty"`
+	Total          int             `json:"total,omitempty"`
+	Message        string          `json:"message,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	MigrationID    string          `json:"migration_id,omitempty"`
+	DestPlaylistID string          `json:"dest_playlist_id,omitempty"`
+	TracksMatched  int             `json:"tracks_matched"`
+	TracksFailed   int             `json:"tracks_failed"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      time.Time       `json:"started_at,omitzero"`
+	FinishedAt     time.Time       `json:"finished_at,omitzero"`
+}
+
+// JobQueue is what the web server needs from a job queue: submitting and listing a user's transfers, and
+// watching and steering them.
+type JobQueue interface {
+	JobWatcher
+	// Submit queues a transfer for userID, failing with [shared.ErrServiceUnavailable] when the queue is full.
+	Submit(userID string, req TransferRequest) (JobInfo, error)
+	// Jobs returns userID's jobs, newest first.
+	Jobs(userID string) []JobInfo
+	// Job returns one of userID's jobs, failing with [shared.ErrRecordNotFound] when there is no such job.
+	Job(userID, jobID string) (JobInfo, error)
+}
+
+// TransferFunc runs a queued transfer for userID, sending progress on progress. Every request it makes to the
+// services should wait for limiter, so the job's rate limit can be changed while it runs.
+type TransferFunc func(ctx context.Context, userID string, req TransferRequest, limiter *rate.Limiter, progress chan<- tasks.ProgressUpdate) (*tasks.TransferRunResult, error)
+
+// QueueOpts configures a [Queue].
+type QueueOpts struct {
+	Workers           int     // Transfers run at once (default: [DefaultQueueWorkers])
+	Capacity          int     // Transfers waiting for a worker (default: [DefaultQueueCapacity])
+	RequestsPerSecond float64 // Rate limit every job starts at, and the most a job may raise it to (default: [DefaultRequestsPerSecond])
+}
+
+// Queue runs submitted transfers on a fixed pool of workers, in memory: jobs are lost when the server stops,
+// though completed transfers remain in the migration history.
+type Queue struct {
+	run     TransferFunc
+	rps     float64
+	pending chan *job
+	ctx     context.Context
+	stop    context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*job
+	seq  []*job // In submission order, for listing and forgetting finished jobs
+}
+
+// job is a queued transfer with its watchers
+type job struct {
+	userID  string
+	limiter *rate.Limiter
+	maxRate float64            // Fastest rate limit the job may be set to, the queue's
+	cancel  context.CancelFunc // Set when the job starts
+
+	mu       sync.Mutex
+	info     JobInfo
+	last     tasks.ProgressUpdate // Latest progress update, replayed to watchers of a finished job
+	watchers map[chan tasks.ProgressUpdate]struct{}
+	stopped  bool // Cancelled before it started
+}
+
+// NewQueue starts a queue running transfers with run. Call [Queue.Stop] to cancel running jobs on shutdown.
+func NewQueue(run TransferFunc, opts QueueOpts) *Queue {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultQueueWorkers
+	}
+	if opts.Capacity <= 0 {
+		opts.Capacity = DefaultQueueCapacity
+	}
+	if opts.RequestsPerSecond <= 0 {
+		opts.RequestsPerSecond = DefaultRequestsPerSecond
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	q := &Queue{
+		run:     run,
+		rps:     opts.RequestsPerSecond,
+		pending: make(chan *job, opts.Capacity),
+		ctx:     ctx,
+		stop:    stop,
+		jobs:    make(map[string]*job),
+	}
+	for range opts.Workers {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Stop cancels the running and waiting jobs and waits for the workers to exit.
+func (q *Queue) Stop() {
+	q.stop()
+	q.wg.Wait()
+
+	for {
+		select {
+		case j := <-q.pending:
+			j.finish(JobCancelled, nil, context.Canceled)
+		default:
+			return
+		}
+	}
+}
+
+// Submit implements [JobQueue].
+func (q *Queue) Submit(userID string, req TransferRequest) (JobInfo, error) {
+	if req.PlaylistID == "" {
+		return JobInfo{}, fmt.Errorf("%w: playlist_id is required", shared.ErrInvalidArgument)
+	}
+	explicit, err := tasks.ParseExplicitPreference(string(req.Explicit))
+	if err != nil {
+		return JobInfo{}, err
+	}
+	req.Explicit = explicit
+	if q.ctx.Err() != nil {
+		return JobInfo{}, fmt.Errorf("%w: job queue stopped", shared.ErrServiceUnavailable)
+	}
+
+	j := &job{
+		userID:   userID,
+		limiter:  rate.NewLimiter(rate.Limit(q.rps), 1),
+		maxRate:  q.rps,
+		info:     JobInfo{ID: shared.GenerateID(), Status: JobQueued, Request: req, CreatedAt: time.Now()},
+		watchers: make(map[chan tasks.ProgressUpdate]struct{}),
+	}
+
+	// Registered before a worker can take it, so the job can be looked up and cancelled as soon as it runs
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs[j.info.ID] = j
+	select {
+	case q.pending <- j:
+	default:
+		delete(q.jobs, j.info.ID)
+		return JobInfo{}, fmt.Errorf("%w: job queue is full, try again later", shared.ErrServiceUnavailable)
+	}
+	q.seq = append(q.seq, j)
+	q.forgetFinished()
+	return j.snapshot(), nil
+}
+
+// Jobs implements [JobQueue].
+func (q *Queue) Jobs(userID string) []JobInfo {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := []JobInfo{}
+	for i := len(q.seq) - 1; i >= 0; i-- {
+		if q.seq[i].userID == userID {
+			jobs = append(jobs, q.seq[i].snapshot())
+		}
+	}
+	return jobs
+}
+
+// Job implements [JobQueue].
+func (q *Queue) Job(userID, jobID string) (JobInfo, error) {
+	j, err := q.find(userID, jobID)
+	if err != nil {
+		return JobInfo{}, err
+	}
+	return j.snapshot(), nil
+}
+
+// Watch implements [JobWatcher]. Watchers that fall behind miss plain progress updates rather than slow the job;
+// the channel of a finished job yields its last update, if any, and is closed.
+func (q *Queue) Watch(ctx context.Context, userID, jobID string) (<-chan tasks.ProgressUpdate, JobControl, error) {
+	j, err := q.find(userID, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan tasks.ProgressUpdate, watcherBufferSize)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.info.Status.finished() {
+		if !j.last.Time.IsZero() {
+			ch <- j.last
+		}
+		close(ch)
+		return ch, j, nil
+	}
+	j.watchers[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if _, ok := j.watchers[ch]; ok {
+			delete(j.watchers, ch)
+			close(ch)
+		}
+	}()
+	return ch, j, nil
+}
+
+// find returns userID's job jobID; jobs of other users are not found, so their IDs cannot be probed
+func (q *Queue) find(userID, jobID string) (*job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if j, ok := q.jobs[jobID]; ok && j.userID == userID {
+		return j, nil
+	}
+	return nil, fmt.Errorf("%w: job %s", shared.ErrRecordNotFound, jobID)
+}
+
+// forgetFinished drops the oldest finished jobs beyond [keptFinishedJobs]; q.mu must be held
+func (q *Queue) forgetFinished() {
+	finished := 0
+	for _, j := range q.seq {
+		if j.snapshot().Status.finished() {
+			finished++
+		}
+	}
+
+	kept := q.seq[:0]
+	for _, j := range q.seq {
+		if finished > keptFinishedJobs && j.snapshot().Status.finished() {
+			delete(q.jobs, j.info.ID)
+			finished--
+			continue
+		}
+		kept = append(kept, j)
+	}
+	q.seq = kept
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for {
+		select {
+		case j := <-q.pending:
+			q.runJob(j)
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *Queue) runJob(j *job) {
+	ctx, cancel := context.WithCancel(q.ctx)
+	defer cancel()
+
+	j.mu.Lock()
+	if j.stopped {
+		j.mu.Unlock()
+		return
+	}
+	j.cancel = cancel
+	j.info.Status = JobRunning
+	j.info.StartedAt = time.Now()
+	j.mu.Unlock()
+
+	progress := make(chan tasks.ProgressUpdate)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for update := range progress {
+			j.publish(update)
+		}
+	}()
+
+	result, err := q.run(ctx, j.userID, j.info.Request, j.limiter, progress)
+	close(progress)
+	<-forwarded
+
+	switch {
+	case err == nil:
+		j.finish(JobCompleted, result, nil)
+	case ctx.Err() != nil && errors.Is(err, context.Canceled):
+		j.finish(JobCancelled, result, err)
+	default:
+		j.finish(JobFailed, result, err)
+	}
+}
+
+// Cancel implements [JobControl]. A job still waiting for a worker is cancelled right away.
+func (j *job) Cancel() {
+	j.mu.Lock()
+	if j.cancel != nil {
+		cancel := j.cancel
+		j.mu.Unlock()
+		cancel()
+		return
+	}
+	if j.info.Status.finished() {
+		j.mu.Unlock()
+		return
+	}
+	j.stopped = true
+	j.mu.Unlock()
+	j.finish(JobCancelled, nil, context.Canceled)
+}
+
+// SetRateLimit implements [JobControl], refusing rates above the queue's so no job calls the services faster than
+// the server allows.
+func (j *job) SetRateLimit(requestsPerSecond float64) error {
+	if requestsPerSecond <= 0 {
+		return fmt.Errorf("%w: requests per second must be positive", shared.ErrInvalidArgument)
+	}
+	if requestsPerSecond > j.maxRate {
+		return fmt.Errorf("%w: requests per second must be at most %g", shared.ErrInvalidArgument, j.maxRate)
+	}
+	j.limiter.SetLimit(rate.Limit(requestsPerSecond))
+	return nil
+}
+
+func (j *job) snapshot() JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.info
+}
+
+// publish records update and fans it out to the watchers, dropping it for those whose buffer is full
+func (j *job) publish(update tasks.ProgressUpdate) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if update.Time.IsZero() {
+		update.Time = time.Now()
+	}
+	j.last = update
+	j.info.Phase, j.info.Step, j.info.Total, j.info.Message = update.Phase.String(), update.Step, update.Total, update.Message
+	for ch := range j.watchers {
+		select {
+		case ch <- update:
+		default:
+		}
+	}
+}
+
+// finish records the job's outcome and closes its watchers' channels
+func (j *job) finish(status JobStatus, result *tasks.TransferRunResult, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.info.Status.finished() {
+		return
+	}
+
+	j.info.Status = status
+	j.info.FinishedAt = time.Now()
+	if err != nil && status != JobCancelled {
+		j.info.Error = err.Error()
+	}
+	if result != nil {
+		j.info.MigrationID = result.MigrationID
+		j.info.TracksMatched, j.info.TracksFailed = result.SuccessCount, result.FailedCount
+		if result.DestPlaylist != nil {
+			j.info.DestPlaylistID = result.DestPlaylist.ID
+		}
+	}
+	for ch := range j.watchers {
+		delete(j.watchers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,224 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertthunder/ytx/internal/models"
+	"github.com/desertthunder/ytx/internal/shared"
+	"github.com/desertthunder/ytx/internal/tasks"
+	"golang.org/x/time/rate"
+)
+
+// blockingTransfer sends one progress update, then waits for release or cancellation
+func blockingTransfer(release <-chan struct{}) TransferFunc {
+	return func(ctx context.Context, userID string, req TransferRequest, limiter *rate.Limiter, progress chan<- tasks.ProgressUpdate) (*tasks.TransferRunResult, error) {
+		progress <- tasks.ProgressUpdate{Phase: tasks.SearchTracks, Step: 1, Total: 2, Message: "Searching " + req.PlaylistID}
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if req.PlaylistID == "broken" {
+			return nil, errors.New("source playlist not found")
+		}
+		return &tasks.TransferRunResult{MigrationID: "m-" + req.PlaylistID, DestPlaylist: &models.Playlist{ID: "dest"}, SuccessCount: 2}, nil
+	}
+}
+
+// waitForStatus polls jobID until it reaches status
+func waitForStatus(t *testing.T, q *Queue, userID, jobID string, status JobStatus) JobInfo {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, err := q.Job(userID, jobID); err == nil && job.Status == status {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	job, _ := q.Job(userID, jobID)
+	t.Fatalf("job %s did not reach %s, got %+v", jobID, status, job)
+	return job
+}
+
+func TestQueue(t *testing.T) {
+	release := make(chan struct{})
+	q := NewQueue(blockingTransfer(release), QueueOpts{Workers: 1, Capacity: 1})
+	defer q.Stop()
+
+	first, err := q.Submit("alice", TransferRequest{PlaylistID: "p1"})
+	if err != nil || first.Status != JobQueued {
+		t.Fatalf("Submit() = %+v, %v", first, err)
+	}
+	waitForStatus(t, q, "alice", first.ID, JobRunning)
+
+	second, err := q.Submit("alice", TransferRequest{PlaylistID: "broken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Submit("alice", TransferRequest{PlaylistID: "p3"}); !errors.Is(err, shared.ErrServiceUnavailable) {
+		t.Errorf("expected a full queue to refuse jobs, got %v", err)
+	}
+	if _, err := q.Submit("alice", TransferRequest{}); !errors.Is(err, shared.ErrInvalidArgument) {
+		t.Errorf("expected a playlist ID to be required, got %v", err)
+	}
+	if _, err := q.Submit("alice", TransferRequest{PlaylistID: "p3", Explicit: "radio"}); !errors.Is(err, shared.ErrInvalidArgument) {
+		t.Errorf("expected an unknown explicit preference refused, got %v", err)
+	}
+
+	if _, err := q.Job("bob", first.ID); !errors.Is(err, shared.ErrRecordNotFound) {
+		t.Errorf("expected another user's job to be hidden, got %v", err)
+	}
+	if jobs := q.Jobs("alice"); len(jobs) != 2 || jobs[0].ID != second.ID {
+		t.Errorf("expected alice's two jobs newest first, got %+v", jobs)
+	}
+
+	updates, control, err := q.Watch(t.Context(), "alice", first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := control.SetRateLimit(0); !errors.Is(err, shared.ErrInvalidArgument) {
+		t.Errorf("expected a non-positive rate to be refused, got %v", err)
+	}
+	if err := control.SetRateLimit(DefaultRequestsPerSecond + 1); !errors.Is(err, shared.ErrInvalidArgument) {
+		t.Errorf("expected a rate above the queue's to be refused, got %v", err)
+	}
+	if err := control.SetRateLimit(1); err != nil {
+		t.Errorf("expected a slower rate to be accepted, got %v", err)
+	}
+	release <- struct{}{}
+	for range updates {
+	}
+	done := waitForStatus(t, q, "alice", first.ID, JobCompleted)
+	if done.MigrationID != "m-p1" || done.DestPlaylistID != "dest" || done.TracksMatched != 2 || done.Phase != "search_tracks" {
+		t.Errorf("unexpected completed job %+v", done)
+	}
+
+	updates, _, _ = q.Watch(t.Context(), "alice", first.ID)
+	if update, ok := <-updates; !ok || update.Message != "Searching p1" {
+		t.Errorf("expected a finished job to replay its last update, got %+v", update)
+	}
+
+	release <- struct{}{}
+	if failed := waitForStatus(t, q, "alice", second.ID, JobFailed); failed.Error != "source playlist not found" {
+		t.Errorf("expected the transfer error recorded, got %+v", failed)
+	}
+
+	t.Run("cancel", func(t *testing.T) {
+		running, _ := q.Submit("alice", TransferRequest{PlaylistID: "p4"})
+		waitForStatus(t, q, "alice", running.ID, JobRunning)
+		queued, _ := q.Submit("alice", TransferRequest{PlaylistID: "p5"})
+
+		_, control, _ := q.Watch(t.Context(), "alice", queued.ID)
+		control.Cancel()
+		waitForStatus(t, q, "alice", queued.ID, JobCancelled)
+
+		_, control, _ = q.Watch(t.Context(), "alice", running.ID)
+		control.Cancel()
+		if job := waitForStatus(t, q, "alice", running.ID, JobCancelled); job.Error != "" {
+			t.Errorf("expected no error for a cancelled job, got %q", job.Error)
+		}
+	})
+}
+
+func TestQueue_RegistersBeforeRunning(t *testing.T) {
+	var q *Queue
+	seen := make(chan int, 1)
+	q = NewQueue(func(ctx context.Context, userID string, req TransferRequest, limiter *rate.Limiter, progress chan<- tasks.ProgressUpdate) (*tasks.TransferRunResult, error) {
+		seen <- len(q.Jobs(userID))
+		return &tasks.TransferRunResult{}, nil
+	}, QueueOpts{Workers: 1})
+	defer q.Stop()
+
+	for range 20 {
+		if _, err := q.Submit("alice", TransferRequest{PlaylistID: "p1"}); err != nil {
+			t.Fatal(err)
+		}
+		if n := <-seen; n == 0 {
+			t.Fatal("expected a running job to be listed")
+		}
+	}
+}
+
+func TestJobRoutes(t *testing.T) {
+	release := make(chan struct{})
+	q := NewQueue(blockingTransfer(release), QueueOpts{Workers: 1})
+	defer q.Stop()
+	app := newTestApp(t)
+	app.jobs = q
+	alice := session(t, do(app, "POST", "/register", `{"email":"alice@example.com","password":"wonderland"}`))
+	bob := session(t, do(app, "POST", "/register", `{"email":"bob@example.com","password":"builder99"}`))
+
+	rec := do(app, "POST", "/jobs", `{"playlist_id":" p1 ","public":true}`, alice)
+	var job JobInfo
+	if rec.Code != http.StatusAccepted || json.Unmarshal(rec.Body.Bytes(), &job) != nil || job.Request.PlaylistID != "p1" {
+		t.Fatalf("POST /jobs = %d %s", rec.Code, rec.Body)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/jobs/"+job.ID {
+		t.Errorf("expected the job's location, got %q", loc)
+	}
+	if rec := do(app, "POST", "/jobs", `{}`, alice); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a playlist ID, got %d", rec.Code)
+	}
+	if rec := do(app, "GET", "/jobs/"+job.ID, "", bob); rec.Code != http.StatusNotFound {
+		t.Errorf("expected another user's job to be hidden, got %d", rec.Code)
+	}
+	if rec := do(app, "GET", "/jobs", "", bob); !strings.Contains(rec.Body.String(), `"jobs":[]`) {
+		t.Errorf("expected bob to have no jobs, got %s", rec.Body)
+	}
+	waitForStatus(t, q, app.mustUserID(t, "alice@example.com"), job.ID, JobRunning)
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/jobs/"+job.ID+"/events", nil)
+	req.AddCookie(alice)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	close(release)
+
+	var events []string
+	var final JobInfo
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, name)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok && events[len(events)-1] == "done" {
+			json.Unmarshal([]byte(data), &final)
+		}
+	}
+	if len(events) == 0 || events[len(events)-1] != "done" || final.Status != JobCompleted {
+		t.Errorf("expected progress events ending with the completed job, got %v %+v", events, final)
+	}
+
+	if rec := do(app, "DELETE", "/jobs/"+job.ID, "", alice); rec.Code != http.StatusNoContent {
+		t.Errorf("expected cancelling a finished job to succeed, got %d", rec.Code)
+	}
+	if rec := do(app, "GET", "/jobs/"+job.ID, "", alice); !strings.Contains(rec.Body.String(), `"status":"completed"`) {
+		t.Errorf("expected the finished job unchanged, got %s", rec.Body)
+	}
+}
+
+// mustUserID returns the ID of the account registered with email
+func (a *App) mustUserID(t *testing.T, email string) string {
+	t.Helper()
+	user, err := a.users.GetByEmail(t.Context(), email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.ID()
+}
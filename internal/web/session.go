@@ -0,0 +1,81 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sessionCookie = "ytx_session"
+	oauthCookie   = "ytx_oauth"
+	sessionTTL    = 7 * 24 * time.Hour
+	oauthTTL      = 10 * time.Minute
+)
+
+// cookieJar issues and reads HMAC-signed cookies, so a session or OAuth state cannot be forged without the secret.
+//
+// Values are "<value>|<expiry unix>|<signature>"; values must not contain '|'. The signature also covers the cookie's
+// name, so a value signed for one cookie, such as the OAuth state, is refused as another, such as the session.
+type cookieJar struct {
+	secret []byte
+	secure bool
+}
+
+// set writes a signed cookie holding value that expires after ttl
+func (j cookieJar) set(w http.ResponseWriter, name, value string, ttl time.Duration) {
+	expires := time.Now().Add(ttl)
+	payload := value + "|" + strconv.FormatInt(expires.Unix(), 10)
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    payload + "|" + j.sign(name, payload),
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   j.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// get returns the value of a signed cookie, or false when it is missing, tampered with, or expired
+func (j cookieJar) get(r *http.Request, name string) (string, bool) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+
+	i := strings.LastIndex(cookie.Value, "|")
+	if i < 0 {
+		return "", false
+	}
+	payload, signature := cookie.Value[:i], cookie.Value[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(j.sign(name, payload))) {
+		return "", false
+	}
+
+	value, expiry, ok := strings.Cut(payload, "|")
+	if !ok {
+		return "", false
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return "", false
+	}
+	return value, true
+}
+
+// clear expires a cookie in the browser
+func (j cookieJar) clear(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{Name: name, Value: "", Path: "/", MaxAge: -1, HttpOnly: true, Secure: j.secure})
+}
+
+func (j cookieJar) sign(name, payload string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(name + "="))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,5 +1,34 @@
 // Package web implements an HTMX-based web application mirroring the TUI functionality.
 //
+// # Accounts
+//
+// [App] is the multi-user JSON API the views build on. Accounts register and log in with an email and password
+// (hashed with [shared.HashPassword]) and are tracked by an HMAC-signed session cookie. Each account keeps its own
+// service credentials, stored per user by [repositories.CredentialRepository]: a Spotify token from the OAuth flow at
+// /auth/spotify, and the contents of a YouTube Music auth file uploaded with PUT /credentials/youtube, both encrypted
+// with a key derived from the session secret. Playlist and migration listings are always filtered by the session's
+// user ID.
+//
+//	POST   /register               → Create an account and log in
+//	POST   /login, /logout         → Start or end a session
+//	GET    /me                     → Current account and connected services
+//	GET    /playlists, /migrations → The account's cached playlists and transfers (limit, offset)
+//	PUT    /credentials/youtube    → Upload the YouTube Music auth file's contents
+//	DELETE /credentials/{service}  → Disconnect a service
+//	POST   /jobs                   → Queue a transfer of {"playlist_id", "reverse", "public", "name"}
+//	GET    /jobs, /jobs/{id}       → The account's queued, running, and recent transfers
+//	DELETE /jobs/{id}              → Cancel a transfer
+//	GET    /jobs/{id}/events       → SSE stream of a job's progress, ending with a "done" event
+//	GET    /jobs/{id}/ws           → WebSocket streaming a job's progress and accepting cancel and rate_limit controls
+//
+// # Jobs
+//
+// Transfers run on a [Queue]: a fixed pool of workers taking jobs in submission order, held in memory. Each job
+// calls the [TransferFunc] it was created with, which the ytx serve command implements with the submitting user's
+// own credentials, and has its own rate limiter that the WebSocket's rate_limit control adjusts while it runs, never
+// above [QueueOpts.RequestsPerSecond].
+// Without a queue, the /jobs routes answer 503.
+//
 // # HTMX Web Application Implementation Plan
 //
 // # Architecture